@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Creates the table with the schema of the custom attributes of the users
+func CreateAttributeDefinitionsTable(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS attribute_definitions(
+			name varchar(64) NOT NULL,
+			type varchar(16) NOT NULL CHECK (type IN ('string', 'number', 'boolean')),
+			required boolean NOT NULL DEFAULT false,
+			user_editable boolean NOT NULL DEFAULT false,
+			in_token boolean NOT NULL DEFAULT false,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			updated_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (name)
+		);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewCreateAttributeDefinitionsTableMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "create-attribute-definitions-table",
+		Up:   CreateAttributeDefinitionsTable,
+	}
+}
@@ -0,0 +1,98 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAttributeDefinitionsTable_FailExec(t *testing.T) {
+	migration := NewCreateAttributeDefinitionsTableMigration()
+	assert.Equal(t, migration.Name, "create-attribute-definitions-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS attribute_definitions(
+			name varchar(64) NOT NULL,
+			type varchar(16) NOT NULL CHECK (type IN ('string', 'number', 'boolean')),
+			required boolean NOT NULL DEFAULT false,
+			user_editable boolean NOT NULL DEFAULT false,
+			in_token boolean NOT NULL DEFAULT false,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			updated_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (name)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestCreateAttributeDefinitionsTable_TimeoutReached(t *testing.T) {
+	migration := NewCreateAttributeDefinitionsTableMigration()
+	assert.Equal(t, migration.Name, "create-attribute-definitions-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS attribute_definitions(
+			name varchar(64) NOT NULL,
+			type varchar(16) NOT NULL CHECK (type IN ('string', 'number', 'boolean')),
+			required boolean NOT NULL DEFAULT false,
+			user_editable boolean NOT NULL DEFAULT false,
+			in_token boolean NOT NULL DEFAULT false,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			updated_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (name)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestCreateAttributeDefinitionsTable_Success(t *testing.T) {
+	migration := NewCreateAttributeDefinitionsTableMigration()
+	assert.Equal(t, migration.Name, "create-attribute-definitions-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS attribute_definitions(
+			name varchar(64) NOT NULL,
+			type varchar(16) NOT NULL CHECK (type IN ('string', 'number', 'boolean')),
+			required boolean NOT NULL DEFAULT false,
+			user_editable boolean NOT NULL DEFAULT false,
+			in_token boolean NOT NULL DEFAULT false,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			updated_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (name)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
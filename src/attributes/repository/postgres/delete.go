@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Deletes an attribute definition and removes the attribute from every user,
+// so it doesn't come back with stale values if it is defined again.
+func (r PostgresRepository) Delete(ctx context.Context, name string) error {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM attribute_definitions WHERE name = $1`, name)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE users SET attributes = attributes - $1::text WHERE attributes ? $1`, name)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	deleteQuery          = `DELETE FROM attribute_definitions WHERE name = $1`
+	removeFromUsersQuery = `UPDATE users SET attributes = attributes - $1::text WHERE attributes ? $1`
+)
+
+func TestDelete_ErrorBeginningTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin().WillReturnError(errors.New("boom"))
+
+	err = New(db).Delete(context.TODO(), "locale")
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestDelete_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(deleteQuery)).
+		WithArgs("locale").
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = New(db).Delete(ctx, "locale")
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(deleteQuery)).
+		WithArgs("locale").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = New(db).Delete(context.TODO(), "locale")
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete_ErrorRemovingFromUsersRollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(deleteQuery)).
+		WithArgs("locale").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(removeFromUsersQuery)).
+		WithArgs("locale").
+		WillReturnError(errors.New("boom"))
+	mock.ExpectRollback()
+
+	err = New(db).Delete(context.TODO(), "locale")
+	assert.Equal(t, err.Error(), "boom")
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(deleteQuery)).
+		WithArgs("locale").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(removeFromUsersQuery)).
+		WithArgs("locale").
+		WillReturnResult(sqlmock.NewResult(0, 12))
+	mock.ExpectCommit()
+
+	err = New(db).Delete(context.TODO(), "locale")
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
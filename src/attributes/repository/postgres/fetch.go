@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets all the attribute definitions, by name.
+func (r PostgresRepository) Fetch(ctx context.Context) ([]domain.AttributeDefinition, error) {
+	result := make([]domain.AttributeDefinition, 0)
+
+	query := `
+		SELECT name, type, required, user_editable, in_token, created_at, updated_at
+		FROM attribute_definitions
+		ORDER BY name
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return result, err
+	}
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		definition, err := r.scanDefinitionRow(rows)
+		if err != nil {
+			return make([]domain.AttributeDefinition, 0), err
+		}
+		result = append(result, definition)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const fetchQuery = `
+		SELECT name, type, required, user_editable, in_token, created_at, updated_at
+		FROM attribute_definitions
+		ORDER BY name
+	`
+
+func TestFetch_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(fetchQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).Fetch(context.TODO())
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestFetch_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(fetchQuery)).
+		ExpectQuery().
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).Fetch(ctx)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestFetch_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	department := domain.AttributeDefinition{Name: "department", Type: domain.AttributeTypeString, Required: true, InToken: true, CreatedAt: now, UpdatedAt: now}
+	timezone := domain.AttributeDefinition{Name: "timezone", Type: domain.AttributeTypeString, UserEditable: true, CreatedAt: now, UpdatedAt: now}
+	mock.ExpectPrepare(regexp.QuoteMeta(fetchQuery)).
+		ExpectQuery().
+		WillReturnRows(sqlmock.NewRows([]string{"name", "type", "required", "user_editable", "in_token", "created_at", "updated_at"}).
+			AddRow(department.Name, department.Type, department.Required, department.UserEditable, department.InToken, now, now).
+			AddRow(timezone.Name, timezone.Type, timezone.Required, timezone.UserEditable, timezone.InToken, now, now))
+
+	res, err := New(db).Fetch(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.AttributeDefinition{department, timezone}, res)
+}
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type PostgresRepository struct {
+	Db *sql.DB
+}
+
+func New(db *sql.DB) domain.AttributeRepository {
+	return PostgresRepository{db}
+}
+
+// Row of a single or multi row query
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans an attribute definition row
+func (r PostgresRepository) scanDefinitionRow(row rowScanner) (domain.AttributeDefinition, error) {
+	result := domain.AttributeDefinition{}
+
+	err := row.Scan(
+		&result.Name,
+		&result.Type,
+		&result.Required,
+		&result.UserEditable,
+		&result.InToken,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	)
+	if err != nil {
+		return domain.AttributeDefinition{}, err
+	}
+
+	return result, nil
+}
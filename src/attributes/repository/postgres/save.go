@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Creates an attribute definition, or updates it when one with the same name exists.
+func (r PostgresRepository) Save(ctx context.Context, definition domain.AttributeDefinition) (domain.AttributeDefinition, error) {
+	query := `
+		INSERT INTO attribute_definitions (name, type, required, user_editable, in_token, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (name) DO UPDATE
+		SET type = EXCLUDED.type, required = EXCLUDED.required, user_editable = EXCLUDED.user_editable,
+			in_token = EXCLUDED.in_token, updated_at = EXCLUDED.updated_at
+		RETURNING name, type, required, user_editable, in_token, created_at, updated_at
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.AttributeDefinition{}, err
+	}
+
+	row := stmt.QueryRowContext(ctx,
+		definition.Name,
+		definition.Type,
+		definition.Required,
+		definition.UserEditable,
+		definition.InToken,
+		time.Now(),
+	)
+	return r.scanDefinitionRow(row)
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const saveQuery = `
+		INSERT INTO attribute_definitions (name, type, required, user_editable, in_token, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (name) DO UPDATE
+		SET type = EXCLUDED.type, required = EXCLUDED.required, user_editable = EXCLUDED.user_editable,
+			in_token = EXCLUDED.in_token, updated_at = EXCLUDED.updated_at
+		RETURNING name, type, required, user_editable, in_token, created_at, updated_at
+	`
+
+func TestSave_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(saveQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).Save(context.TODO(), domain.AttributeDefinition{Name: "locale", Type: domain.AttributeTypeString})
+	assert.Equal(t, err.Error(), "boom")
+	assert.Equal(t, domain.AttributeDefinition{}, res)
+}
+
+func TestSave_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(saveQuery)).
+		ExpectQuery().
+		WithArgs("locale", domain.AttributeTypeString, false, true, false, anyTime{}).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).Save(ctx, domain.AttributeDefinition{Name: "locale", Type: domain.AttributeTypeString, UserEditable: true})
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Equal(t, domain.AttributeDefinition{}, res)
+}
+
+func TestSave_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	createdAt, updatedAt := time.Now().Add(-time.Hour), time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(saveQuery)).
+		ExpectQuery().
+		WithArgs("locale", domain.AttributeTypeString, false, true, false, anyTime{}).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "type", "required", "user_editable", "in_token", "created_at", "updated_at"}).
+			AddRow("locale", "string", false, true, false, createdAt, updatedAt))
+
+	res, err := New(db).Save(context.TODO(), domain.AttributeDefinition{Name: "locale", Type: domain.AttributeTypeString, UserEditable: true})
+	assert.Nil(t, err)
+	assert.Equal(t, domain.AttributeDefinition{
+		Name:         "locale",
+		Type:         domain.AttributeTypeString,
+		UserEditable: true,
+		CreatedAt:    createdAt,
+		UpdatedAt:    updatedAt,
+	}, res)
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type DefaultAttributeService struct {
+	Logger         *log.Logger
+	AttributeRepo  domain.AttributeRepository
+	UserRepo       domain.UserRepository
+	ContextTimeout time.Duration
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	attributeRepo domain.AttributeRepository,
+	userRepo domain.UserRepository,
+	contextTimeout time.Duration,
+) domain.AttributeService {
+	return DefaultAttributeService{
+		logger,
+		attributeRepo,
+		userRepo,
+		contextTimeout,
+	}
+}
+
+// Instantiation for tests
+func newService(attributeRepo domain.AttributeRepository, userRepo domain.UserRepository) DefaultAttributeService {
+	return DefaultAttributeService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		attributeRepo,
+		userRepo,
+		time.Duration(5 * time.Second),
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets the schema of the custom attributes.
+func (s DefaultAttributeService) GetDefinitions(ctx context.Context) ([]domain.AttributeDefinition, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	return s.AttributeRepo.Fetch(ctx)
+}
+
+// Creates or updates an attribute definition.
+// The type of an existing attribute can't change, since users may already have values of the old type.
+func (s DefaultAttributeService) SaveDefinition(ctx context.Context, definition domain.AttributeDefinition) (domain.AttributeDefinition, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	if !definition.IsValid() {
+		return domain.AttributeDefinition{}, domain.ErrBadParamInput
+	}
+
+	definitions, err := s.AttributeRepo.Fetch(ctx)
+	if err != nil {
+		return domain.AttributeDefinition{}, err
+	}
+	for _, existing := range definitions {
+		if existing.Name == definition.Name && existing.Type != definition.Type {
+			return domain.AttributeDefinition{}, domain.ErrNotAllowed
+		}
+	}
+
+	return s.AttributeRepo.Save(ctx, definition)
+}
+
+// Deletes an attribute definition, along with the values of every user.
+func (s DefaultAttributeService) DeleteDefinition(ctx context.Context, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	if len(name) == 0 {
+		return domain.ErrBadParamInput
+	}
+
+	return s.AttributeRepo.Delete(ctx, name)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetDefinitions(t *testing.T) {
+	definitions := []domain.AttributeDefinition{{Name: "locale", Type: domain.AttributeTypeString}}
+	attributeRepo := new(mocks.AttributeRepository)
+	attributeRepo.On("Fetch", mock.Anything).Once().Return(definitions, nil)
+
+	res, err := newService(attributeRepo, nil).GetDefinitions(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, definitions, res)
+	attributeRepo.AssertExpectations(t)
+}
+
+func TestSaveDefinition_InvalidDefinition(t *testing.T) {
+	service := newService(nil, nil)
+
+	for _, definition := range []domain.AttributeDefinition{
+		{Name: "", Type: domain.AttributeTypeString},
+		{Name: "1st", Type: domain.AttributeTypeString},
+		{Name: "has space", Type: domain.AttributeTypeString},
+		{Name: "locale", Type: "date"},
+	} {
+		res, err := service.SaveDefinition(context.TODO(), definition)
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		assert.Equal(t, domain.AttributeDefinition{}, res)
+	}
+}
+
+func TestSaveDefinition_ErrorFetching(t *testing.T) {
+	attributeRepo := new(mocks.AttributeRepository)
+	attributeRepo.On("Fetch", mock.Anything).Once().Return(nil, errors.New("boom"))
+
+	_, err := newService(attributeRepo, nil).SaveDefinition(context.TODO(), domain.AttributeDefinition{Name: "locale", Type: domain.AttributeTypeString})
+	assert.Equal(t, "boom", err.Error())
+	attributeRepo.AssertExpectations(t)
+}
+
+func TestSaveDefinition_TypeCantChange(t *testing.T) {
+	attributeRepo := new(mocks.AttributeRepository)
+	attributeRepo.On("Fetch", mock.Anything).Once().
+		Return([]domain.AttributeDefinition{{Name: "age", Type: domain.AttributeTypeString}}, nil)
+
+	_, err := newService(attributeRepo, nil).SaveDefinition(context.TODO(), domain.AttributeDefinition{Name: "age", Type: domain.AttributeTypeNumber})
+	assert.Equal(t, domain.ErrNotAllowed, err)
+	attributeRepo.AssertExpectations(t)
+}
+
+func TestSaveDefinition_Success(t *testing.T) {
+	existing := domain.AttributeDefinition{Name: "locale", Type: domain.AttributeTypeString}
+	updated := domain.AttributeDefinition{Name: "locale", Type: domain.AttributeTypeString, UserEditable: true, InToken: true}
+
+	attributeRepo := new(mocks.AttributeRepository)
+	attributeRepo.On("Fetch", mock.Anything).Once().Return([]domain.AttributeDefinition{existing}, nil)
+	attributeRepo.On("Save", mock.Anything, updated).Once().Return(updated, nil)
+
+	res, err := newService(attributeRepo, nil).SaveDefinition(context.TODO(), updated)
+	assert.Nil(t, err)
+	assert.Equal(t, updated, res)
+	attributeRepo.AssertExpectations(t)
+}
+
+func TestDeleteDefinition_InvalidName(t *testing.T) {
+	err := newService(nil, nil).DeleteDefinition(context.TODO(), "")
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestDeleteDefinition_Success(t *testing.T) {
+	attributeRepo := new(mocks.AttributeRepository)
+	attributeRepo.On("Delete", mock.Anything, "locale").Once().Return(nil)
+
+	err := newService(attributeRepo, nil).DeleteDefinition(context.TODO(), "locale")
+	assert.Nil(t, err)
+	attributeRepo.AssertExpectations(t)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Merges a patch into the attributes of a user at a version, where null values remove attributes.
+// Only the patched attributes are checked: they must be defined and have a value of their type,
+// and the required ones can't be removed. Users created without a required attribute can still
+// be patched without it, as nothing else checks them.
+// Validation errors wrap domain.ErrBadParamInput and describe the offending attribute.
+func (s DefaultAttributeService) Patch(ctx context.Context, userID uuid.UUID, version int, patch domain.Attributes, asAdmin bool) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	if userID == uuid.Nil || version <= 0 || len(patch) == 0 {
+		return nil, domain.ErrBadParamInput
+	}
+
+	definitions, err := s.AttributeRepo.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	definitionsByName := make(map[string]domain.AttributeDefinition, len(definitions))
+	for _, definition := range definitions {
+		definitionsByName[definition.Name] = definition
+	}
+
+	user, err := s.UserRepo.GetByUUID(ctx, userID)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Version != version {
+		return nil, domain.ErrVersionConflict
+	}
+
+	merged := make(domain.Attributes, len(user.Attributes)+len(patch))
+	for name, value := range user.Attributes {
+		merged[name] = value
+	}
+
+	// Sorted so the same patch always fails on the same attribute.
+	names := make([]string, 0, len(patch))
+	for name := range patch {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		definition, ok := definitionsByName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: attribute %q is not defined", domain.ErrBadParamInput, name)
+		}
+		if !asAdmin && !definition.UserEditable {
+			return nil, domain.ErrNotAllowed
+		}
+
+		value := patch[name]
+		if value == nil {
+			if definition.Required {
+				return nil, fmt.Errorf("%w: attribute %q is required", domain.ErrBadParamInput, name)
+			}
+			delete(merged, name)
+			continue
+		}
+		if !definition.Type.Accepts(value) {
+			return nil, fmt.Errorf("%w: attribute %q must be a %s", domain.ErrBadParamInput, name, definition.Type)
+		}
+		merged[name] = value
+	}
+
+	if err = s.UserRepo.UpdateAttributes(ctx, userID, version, merged); err != nil {
+		return nil, err
+	}
+
+	result := *user
+	result.Attributes = merged
+	result.Version = version + 1
+	return &result, nil
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var patchDefinitions = []domain.AttributeDefinition{
+	{Name: "department", Type: domain.AttributeTypeString, Required: true},
+	{Name: "newsletter", Type: domain.AttributeTypeBoolean, UserEditable: true},
+	{Name: "timezone", Type: domain.AttributeTypeString, Required: true, UserEditable: true},
+	{Name: "seniority", Type: domain.AttributeTypeNumber},
+}
+
+func newPatchService(user *domain.User) (DefaultAttributeService, *mocks.AttributeRepository, *mocks.UserRepository) {
+	attributeRepo := new(mocks.AttributeRepository)
+	attributeRepo.On("Fetch", mock.Anything).Maybe().Return(patchDefinitions, nil)
+
+	userRepo := new(mocks.UserRepository)
+	if user != nil {
+		userRepo.On("GetByUUID", mock.Anything, user.ID).Maybe().Return(user, nil)
+	}
+	return newService(attributeRepo, userRepo), attributeRepo, userRepo
+}
+
+func TestPatch_InvalidInput(t *testing.T) {
+	service, _, _ := newPatchService(nil)
+
+	res, err := service.Patch(context.TODO(), uuid.Nil, 2, domain.Attributes{"timezone": "UTC"}, false)
+	assert.Nil(t, res)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+
+	res, err = service.Patch(context.TODO(), uuid.New(), 2, domain.Attributes{}, false)
+	assert.Nil(t, res)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+
+	res, err = service.Patch(context.TODO(), uuid.New(), 0, domain.Attributes{"timezone": "UTC"}, false)
+	assert.Nil(t, res)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestPatch_UserNotFound(t *testing.T) {
+	userID := uuid.New()
+	service, _, userRepo := newPatchService(nil)
+	userRepo.On("GetByUUID", mock.Anything, userID).Once().Return(nil, sql.ErrNoRows)
+
+	res, err := service.Patch(context.TODO(), userID, 2, domain.Attributes{"timezone": "UTC"}, false)
+	assert.Nil(t, res)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestPatch_VersionConflict(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Version: 3}
+	service, _, userRepo := newPatchService(user)
+
+	res, err := service.Patch(context.TODO(), user.ID, 2, domain.Attributes{"timezone": "UTC"}, false)
+	assert.Nil(t, res)
+	assert.Equal(t, domain.ErrVersionConflict, err)
+	userRepo.AssertNotCalled(t, "UpdateAttributes", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatch_UnknownAttribute(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Version: 2}
+	service, _, _ := newPatchService(user)
+
+	res, err := service.Patch(context.TODO(), user.ID, 2, domain.Attributes{"shoe_size": 42.0}, true)
+	assert.Nil(t, res)
+	assert.True(t, errors.Is(err, domain.ErrBadParamInput))
+	assert.Contains(t, err.Error(), `attribute "shoe_size" is not defined`)
+}
+
+func TestPatch_WrongType(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Version: 2}
+	service, _, _ := newPatchService(user)
+
+	res, err := service.Patch(context.TODO(), user.ID, 2, domain.Attributes{"seniority": "senior", "department": "sales"}, true)
+	assert.Nil(t, res)
+	assert.True(t, errors.Is(err, domain.ErrBadParamInput))
+	assert.Contains(t, err.Error(), `attribute "seniority" must be a number`)
+}
+
+func TestPatch_UserCantEditAdminAttributes(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Version: 2}
+	service, _, _ := newPatchService(user)
+
+	res, err := service.Patch(context.TODO(), user.ID, 2, domain.Attributes{"department": "sales"}, false)
+	assert.Nil(t, res)
+	assert.Equal(t, domain.ErrNotAllowed, err)
+}
+
+func TestPatch_RequiredAttributes(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Version: 2, Attributes: domain.Attributes{"timezone": "UTC", "department": "sales"}}
+
+	t.Run("can't be removed", func(t *testing.T) {
+		service, _, _ := newPatchService(user)
+		res, err := service.Patch(context.TODO(), user.ID, 2, domain.Attributes{"timezone": nil}, false)
+		assert.Nil(t, res)
+		assert.True(t, errors.Is(err, domain.ErrBadParamInput))
+		assert.Contains(t, err.Error(), `attribute "timezone" is required`)
+	})
+
+	t.Run("not even by administrators", func(t *testing.T) {
+		service, _, _ := newPatchService(user)
+		res, err := service.Patch(context.TODO(), user.ID, 2, domain.Attributes{"seniority": 3.0, "department": nil}, true)
+		assert.Nil(t, res)
+		assert.Contains(t, err.Error(), `attribute "department" is required`)
+	})
+
+	t.Run("only the patched ones are checked", func(t *testing.T) {
+		// Users created without the required attributes can still be patched.
+		user := &domain.User{ID: uuid.New(), Version: 2}
+		service, _, userRepo := newPatchService(user)
+		expected := domain.Attributes{"seniority": 3.0}
+		userRepo.On("UpdateAttributes", mock.Anything, user.ID, 2, expected).Once().Return(nil)
+
+		res, err := service.Patch(context.TODO(), user.ID, 2, domain.Attributes{"seniority": 3.0}, true)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, res.Attributes)
+		userRepo.AssertExpectations(t)
+	})
+}
+
+func TestPatch_ErrorUpdating(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Version: 2, Attributes: domain.Attributes{"timezone": "UTC"}}
+	service, _, userRepo := newPatchService(user)
+	userRepo.On("UpdateAttributes", mock.Anything, user.ID, 2, mock.Anything).Once().Return(errors.New("boom"))
+
+	res, err := service.Patch(context.TODO(), user.ID, 2, domain.Attributes{"newsletter": false}, false)
+	assert.Nil(t, res)
+	assert.Equal(t, "boom", err.Error())
+}
+
+func TestPatch_Success(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Version: 2, Attributes: domain.Attributes{"timezone": "UTC", "department": "sales", "newsletter": true}}
+	service, attributeRepo, userRepo := newPatchService(user)
+	expected := domain.Attributes{"timezone": "Europe/Lisbon", "department": "marketing", "seniority": 2.0}
+	userRepo.On("UpdateAttributes", mock.Anything, user.ID, 2, expected).Once().Return(nil)
+
+	res, err := service.Patch(context.TODO(), user.ID, 2, domain.Attributes{
+		"timezone":   "Europe/Lisbon",
+		"department": "marketing",
+		"seniority":  2.0,
+		"newsletter": nil,
+	}, true)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, res.Attributes)
+	assert.Equal(t, 3, res.Version)
+	// The stored attributes of the user aren't changed in place.
+	assert.Equal(t, "UTC", user.Attributes["timezone"])
+	attributeRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Creates the table recording the exports of the data of the users
+func CreateDataExportsTable(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS data_exports(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			requested_by uuid NOT NULL,
+			format_version integer NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+		CREATE INDEX IF NOT EXISTS data_exports_user_id_idx ON data_exports (user_id);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewCreateDataExportsTableMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "create-data-exports-table",
+		Up:   CreateDataExportsTable,
+	}
+}
@@ -0,0 +1,95 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateDataExportsTable_FailExec(t *testing.T) {
+	migration := NewCreateDataExportsTableMigration()
+	assert.Equal(t, migration.Name, "create-data-exports-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS data_exports(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			requested_by uuid NOT NULL,
+			format_version integer NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+		CREATE INDEX IF NOT EXISTS data_exports_user_id_idx ON data_exports (user_id);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestCreateDataExportsTable_TimeoutReached(t *testing.T) {
+	migration := NewCreateDataExportsTableMigration()
+	assert.Equal(t, migration.Name, "create-data-exports-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS data_exports(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			requested_by uuid NOT NULL,
+			format_version integer NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+		CREATE INDEX IF NOT EXISTS data_exports_user_id_idx ON data_exports (user_id);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestCreateDataExportsTable_Success(t *testing.T) {
+	migration := NewCreateDataExportsTableMigration()
+	assert.Equal(t, migration.Name, "create-data-exports-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS data_exports(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			requested_by uuid NOT NULL,
+			format_version integer NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+		CREATE INDEX IF NOT EXISTS data_exports_user_id_idx ON data_exports (user_id);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets the exports of the data of a user, oldest first.
+func (r PostgresRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]domain.DataExportRecord, error) {
+	result := make([]domain.DataExportRecord, 0)
+
+	query := `
+		SELECT id, user_id, requested_by, format_version, created_at
+		FROM data_exports
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return result, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, userID)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		record, err := r.scanRecordRow(rows)
+		if err != nil {
+			return make([]domain.DataExportRecord, 0), err
+		}
+		result = append(result, record)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const getByUserQuery = `
+		SELECT id, user_id, requested_by, format_version, created_at
+		FROM data_exports
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+func TestGetByUser_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getByUserQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).GetByUser(context.TODO(), uuid.New())
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestGetByUser_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(getByUserQuery)).
+		ExpectQuery().
+		WithArgs(userID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).GetByUser(ctx, userID)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestGetByUser_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	first := domain.DataExportRecord{ID: uuid.New(), UserID: userID, RequestedBy: userID, FormatVersion: 1, CreatedAt: time.Now().Add(-time.Hour)}
+	second := domain.DataExportRecord{ID: uuid.New(), UserID: userID, RequestedBy: uuid.New(), FormatVersion: 1, CreatedAt: time.Now()}
+	mock.ExpectPrepare(regexp.QuoteMeta(getByUserQuery)).
+		ExpectQuery().
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "requested_by", "format_version", "created_at"}).
+			AddRow(first.ID, first.UserID, first.RequestedBy, first.FormatVersion, first.CreatedAt).
+			AddRow(second.ID, second.UserID, second.RequestedBy, second.FormatVersion, second.CreatedAt))
+
+	res, err := New(db).GetByUser(context.TODO(), userID)
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.DataExportRecord{first, second}, res)
+}
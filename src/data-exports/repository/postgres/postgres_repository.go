@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type PostgresRepository struct {
+	Db *sql.DB
+}
+
+func New(db *sql.DB) domain.DataExportRepository {
+	return PostgresRepository{db}
+}
+
+// Row of a single or multi row query
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans a data export record row
+func (r PostgresRepository) scanRecordRow(row rowScanner) (domain.DataExportRecord, error) {
+	result := domain.DataExportRecord{}
+
+	err := row.Scan(
+		&result.ID,
+		&result.UserID,
+		&result.RequestedBy,
+		&result.FormatVersion,
+		&result.CreatedAt,
+	)
+	if err != nil {
+		return domain.DataExportRecord{}, err
+	}
+
+	return result, nil
+}
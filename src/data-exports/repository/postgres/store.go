@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Records an export of the data of a user.
+func (r PostgresRepository) Store(ctx context.Context, record domain.DataExportRecord) (domain.DataExportRecord, error) {
+	if record.ID == uuid.Nil {
+		record.ID = uuid.New()
+	}
+
+	query := `
+		INSERT INTO data_exports (id, user_id, requested_by, format_version, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, user_id, requested_by, format_version, created_at
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.DataExportRecord{}, err
+	}
+
+	row := stmt.QueryRowContext(ctx, record.ID, record.UserID, record.RequestedBy, record.FormatVersion, time.Now())
+	return r.scanRecordRow(row)
+}
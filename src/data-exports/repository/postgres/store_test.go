@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const storeQuery = `
+		INSERT INTO data_exports (id, user_id, requested_by, format_version, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, user_id, requested_by, format_version, created_at
+	`
+
+func TestStore_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(storeQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).Store(context.TODO(), domain.DataExportRecord{UserID: uuid.New()})
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestStore_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	record := domain.DataExportRecord{ID: uuid.New(), UserID: uuid.New(), RequestedBy: uuid.New(), FormatVersion: 1}
+	mock.ExpectPrepare(regexp.QuoteMeta(storeQuery)).
+		ExpectQuery().
+		WithArgs(record.ID, record.UserID, record.RequestedBy, 1, anyTime{}).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).Store(ctx, record)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestStore_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	record := domain.DataExportRecord{ID: uuid.New(), UserID: uuid.New(), RequestedBy: uuid.New(), FormatVersion: 1}
+	createdAt := time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(storeQuery)).
+		ExpectQuery().
+		WithArgs(record.ID, record.UserID, record.RequestedBy, 1, anyTime{}).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "requested_by", "format_version", "created_at"}).
+			AddRow(record.ID, record.UserID, record.RequestedBy, 1, createdAt))
+
+	res, err := New(db).Store(context.TODO(), record)
+	assert.Nil(t, err)
+	record.CreatedAt = createdAt
+	assert.Equal(t, record, res)
+}
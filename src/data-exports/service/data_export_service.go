@@ -0,0 +1,84 @@
+package service
+
+import (
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type DefaultDataExportService struct {
+	Logger               *log.Logger
+	DataExportRepo       domain.DataExportRepository
+	UserService          domain.UserService
+	RefreshTokenRepo     domain.RefreshTokenRepository
+	MFARepo              domain.MFARepository
+	PasskeyRepo          domain.PasskeyRepository
+	OneTimeTokenRepo     domain.OneTimeTokenRepository
+	GroupRepo            domain.GroupRepository
+	LoginEventRepo       domain.LoginEventRepository
+	UsernameHistoryRepo  domain.UsernameHistoryRepository
+	IdentityProviderRepo domain.IdentityProviderRepository
+	ContextTimeout       time.Duration
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	dataExportRepo domain.DataExportRepository,
+	userService domain.UserService,
+	refreshTokenRepo domain.RefreshTokenRepository,
+	mfaRepo domain.MFARepository,
+	passkeyRepo domain.PasskeyRepository,
+	oneTimeTokenRepo domain.OneTimeTokenRepository,
+	groupRepo domain.GroupRepository,
+	loginEventRepo domain.LoginEventRepository,
+	usernameHistoryRepo domain.UsernameHistoryRepository,
+	identityProviderRepo domain.IdentityProviderRepository,
+	contextTimeout time.Duration,
+) domain.DataExportService {
+	return DefaultDataExportService{
+		logger,
+		dataExportRepo,
+		userService,
+		refreshTokenRepo,
+		mfaRepo,
+		passkeyRepo,
+		oneTimeTokenRepo,
+		groupRepo,
+		loginEventRepo,
+		usernameHistoryRepo,
+		identityProviderRepo,
+		contextTimeout,
+	}
+}
+
+// Instantiation for tests
+func newService(
+	dataExportRepo domain.DataExportRepository,
+	userService domain.UserService,
+	refreshTokenRepo domain.RefreshTokenRepository,
+	mfaRepo domain.MFARepository,
+	passkeyRepo domain.PasskeyRepository,
+	oneTimeTokenRepo domain.OneTimeTokenRepository,
+	groupRepo domain.GroupRepository,
+	loginEventRepo domain.LoginEventRepository,
+	usernameHistoryRepo domain.UsernameHistoryRepository,
+	identityProviderRepo domain.IdentityProviderRepository,
+) DefaultDataExportService {
+	return DefaultDataExportService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		dataExportRepo,
+		userService,
+		refreshTokenRepo,
+		mfaRepo,
+		passkeyRepo,
+		oneTimeTokenRepo,
+		groupRepo,
+		loginEventRepo,
+		usernameHistoryRepo,
+		identityProviderRepo,
+		time.Duration(5 * time.Second),
+	}
+}
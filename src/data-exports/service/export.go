@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// How many login events are fetched at a time.
+const loginEventsPageSize = 500
+
+// Gets everything stored about a user as a JSON document, recording the export.
+// The export being made is listed in the document as well.
+func (s DefaultDataExportService) Export(ctx context.Context, userID uuid.UUID, requestedBy uuid.UUID) ([]byte, error) {
+	if userID == uuid.Nil || requestedBy == uuid.Nil {
+		return nil, domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	user, err := s.UserService.GetUserByUUID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	export := domain.DataExport{
+		FormatVersion: domain.DataExportFormatVersion,
+		GeneratedAt:   time.Now().UTC(),
+		Profile:       *user,
+	}
+
+	if export.UsernameHistory, err = s.UsernameHistoryRepo.GetByUser(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	if export.Groups, err = s.GroupRepo.GetByMember(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	for i := range export.Groups {
+		if export.Groups[i].Roles, err = s.GroupRepo.GetRoles(ctx, export.Groups[i].ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if user.RefreshTokenId.Valid {
+		token, err := s.RefreshTokenRepo.GetByUUID(ctx, user.RefreshTokenId.UUID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		if err == nil {
+			export.Session = &domain.DataExportSession{ID: token.Id, ValidUntil: token.ValidUntil}
+		}
+	}
+
+	totp, err := s.MFARepo.GetTOTPSecret(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err == nil {
+		export.TOTP = &totp
+	}
+
+	if export.Passkeys, err = s.PasskeyRepo.GetCredentialsByUser(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	if export.Identities, err = s.IdentityProviderRepo.GetIdentitiesByUser(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	if export.LoginEvents, err = s.getLoginEvents(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	if export.PendingTokens, err = s.OneTimeTokenRepo.GetByUser(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	if export.Exports, err = s.DataExportRepo.GetByUser(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	record, err := s.DataExportRepo.Store(ctx, domain.DataExportRecord{
+		UserID:        user.ID,
+		RequestedBy:   requestedBy,
+		FormatVersion: domain.DataExportFormatVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	export.Exports = append(export.Exports, record)
+
+	s.Logger.Printf("data of user %v exported by %v\n", user.ID, requestedBy)
+	return json.MarshalIndent(export, "", "  ")
+}
+
+// Gets all the login events of a user, a page at a time.
+func (s DefaultDataExportService) getLoginEvents(ctx context.Context, userID uuid.UUID) ([]domain.LoginEvent, error) {
+	result := make([]domain.LoginEvent, 0)
+	for {
+		page, err := s.LoginEventRepo.GetByUser(ctx, userID, domain.LoginEventFilter{
+			Limit:  loginEventsPageSize,
+			Offset: len(result),
+		})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, page...)
+		if len(page) < loginEventsPageSize {
+			return result, nil
+		}
+	}
+}
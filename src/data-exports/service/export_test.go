@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type exportMocks struct {
+	dataExportRepo       *mocks.DataExportRepository
+	userService          *mocks.UserService
+	refreshTokenRepo     *mocks.RefreshTokenRepository
+	mfaRepo              *mocks.MFARepository
+	passkeyRepo          *mocks.PasskeyRepository
+	oneTimeTokenRepo     *mocks.OneTimeTokenRepository
+	groupRepo            *mocks.GroupRepository
+	loginEventRepo       *mocks.LoginEventRepository
+	usernameHistoryRepo  *mocks.UsernameHistoryRepository
+	identityProviderRepo *mocks.IdentityProviderRepository
+}
+
+func newExportService() (DefaultDataExportService, exportMocks) {
+	m := exportMocks{
+		new(mocks.DataExportRepository),
+		new(mocks.UserService),
+		new(mocks.RefreshTokenRepository),
+		new(mocks.MFARepository),
+		new(mocks.PasskeyRepository),
+		new(mocks.OneTimeTokenRepository),
+		new(mocks.GroupRepository),
+		new(mocks.LoginEventRepository),
+		new(mocks.UsernameHistoryRepository),
+		new(mocks.IdentityProviderRepository),
+	}
+	return newService(
+		m.dataExportRepo, m.userService, m.refreshTokenRepo, m.mfaRepo,
+		m.passkeyRepo, m.oneTimeTokenRepo, m.groupRepo, m.loginEventRepo, m.usernameHistoryRepo,
+		m.identityProviderRepo,
+	), m
+}
+
+func TestExport_InvalidInput(t *testing.T) {
+	service, _ := newExportService()
+	res, err := service.Export(context.TODO(), uuid.Nil, uuid.New())
+	assert.Nil(t, res)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestExport_UserNotFound(t *testing.T) {
+	userID := uuid.New()
+	service, m := newExportService()
+	m.userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(nil, sql.ErrNoRows)
+
+	res, err := service.Export(context.TODO(), userID, userID)
+	assert.Nil(t, res)
+	assert.Equal(t, domain.ErrNotFound, err)
+	m.userService.AssertExpectations(t)
+}
+
+func TestExport_ErrorGettingUsernameHistory(t *testing.T) {
+	userID := uuid.New()
+	service, m := newExportService()
+	m.userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(&domain.User{ID: userID}, nil)
+	m.usernameHistoryRepo.On("GetByUser", mock.Anything, userID).Once().Return(nil, errors.New("boom"))
+
+	res, err := service.Export(context.TODO(), userID, userID)
+	assert.Nil(t, res)
+	assert.Equal(t, "boom", err.Error())
+	m.usernameHistoryRepo.AssertExpectations(t)
+}
+
+func TestExport_ErrorGettingGroups(t *testing.T) {
+	userID := uuid.New()
+	service, m := newExportService()
+	m.userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(&domain.User{ID: userID}, nil)
+	m.usernameHistoryRepo.On("GetByUser", mock.Anything, userID).Once().Return([]domain.UsernameChange{}, nil)
+	m.groupRepo.On("GetByMember", mock.Anything, userID).Once().Return(nil, errors.New("boom"))
+
+	res, err := service.Export(context.TODO(), userID, userID)
+	assert.Nil(t, res)
+	assert.Equal(t, "boom", err.Error())
+	m.groupRepo.AssertExpectations(t)
+}
+
+func TestExport_ErrorGettingIdentities(t *testing.T) {
+	userID := uuid.New()
+	service, m := newExportService()
+	m.userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(&domain.User{ID: userID}, nil)
+	m.usernameHistoryRepo.On("GetByUser", mock.Anything, userID).Once().Return([]domain.UsernameChange{}, nil)
+	m.groupRepo.On("GetByMember", mock.Anything, userID).Once().Return([]domain.Group{}, nil)
+	m.mfaRepo.On("GetTOTPSecret", mock.Anything, userID).Once().Return(domain.TOTPSecret{}, sql.ErrNoRows)
+	m.passkeyRepo.On("GetCredentialsByUser", mock.Anything, userID).Once().Return([]domain.PasskeyCredential{}, nil)
+	m.identityProviderRepo.On("GetIdentitiesByUser", mock.Anything, userID).Once().Return(nil, errors.New("boom"))
+
+	res, err := service.Export(context.TODO(), userID, userID)
+	assert.Nil(t, res)
+	assert.Equal(t, "boom", err.Error())
+	m.identityProviderRepo.AssertExpectations(t)
+}
+
+func TestExport_ErrorGettingLoginEvents(t *testing.T) {
+	userID := uuid.New()
+	service, m := newExportService()
+	m.userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(&domain.User{ID: userID}, nil)
+	m.usernameHistoryRepo.On("GetByUser", mock.Anything, userID).Once().Return([]domain.UsernameChange{}, nil)
+	m.groupRepo.On("GetByMember", mock.Anything, userID).Once().Return([]domain.Group{}, nil)
+	m.mfaRepo.On("GetTOTPSecret", mock.Anything, userID).Once().Return(domain.TOTPSecret{}, sql.ErrNoRows)
+	m.passkeyRepo.On("GetCredentialsByUser", mock.Anything, userID).Once().Return([]domain.PasskeyCredential{}, nil)
+	m.identityProviderRepo.On("GetIdentitiesByUser", mock.Anything, userID).Once().Return([]domain.ExternalIdentity{}, nil)
+	m.loginEventRepo.On("GetByUser", mock.Anything, userID, mock.Anything).Once().Return(nil, errors.New("boom"))
+
+	res, err := service.Export(context.TODO(), userID, userID)
+	assert.Nil(t, res)
+	assert.Equal(t, "boom", err.Error())
+	m.loginEventRepo.AssertExpectations(t)
+}
+
+func TestExport_ErrorRecordingExport(t *testing.T) {
+	userID := uuid.New()
+	service, m := newExportService()
+	m.userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(&domain.User{ID: userID}, nil)
+	m.usernameHistoryRepo.On("GetByUser", mock.Anything, userID).Once().Return([]domain.UsernameChange{}, nil)
+	m.groupRepo.On("GetByMember", mock.Anything, userID).Once().Return([]domain.Group{}, nil)
+	m.mfaRepo.On("GetTOTPSecret", mock.Anything, userID).Once().Return(domain.TOTPSecret{}, sql.ErrNoRows)
+	m.passkeyRepo.On("GetCredentialsByUser", mock.Anything, userID).Once().Return([]domain.PasskeyCredential{}, nil)
+	m.identityProviderRepo.On("GetIdentitiesByUser", mock.Anything, userID).Once().Return([]domain.ExternalIdentity{}, nil)
+	m.loginEventRepo.On("GetByUser", mock.Anything, userID, mock.Anything).Once().Return([]domain.LoginEvent{}, nil)
+	m.oneTimeTokenRepo.On("GetByUser", mock.Anything, userID).Once().Return([]domain.OneTimeToken{}, nil)
+	m.dataExportRepo.On("GetByUser", mock.Anything, userID).Once().Return([]domain.DataExportRecord{}, nil)
+	m.dataExportRepo.On("Store", mock.Anything, mock.AnythingOfType("domain.DataExportRecord")).
+		Once().Return(domain.DataExportRecord{}, errors.New("boom"))
+
+	res, err := service.Export(context.TODO(), userID, userID)
+	assert.Nil(t, res)
+	assert.Equal(t, "boom", err.Error())
+	m.dataExportRepo.AssertExpectations(t)
+}
+
+func TestExport_Success(t *testing.T) {
+	userID := uuid.New()
+	adminID := uuid.New()
+	roleID := uuid.New()
+	refreshTokenID := uuid.New()
+	refreshToken := uuid.New()
+
+	user := &domain.User{
+		ID:             userID,
+		Username:       "alice",
+		Email:          "alice@example.com",
+		Password:       "$2a$14$the-password-hash",
+		RoleId:         roleID,
+		Role:           &domain.Role{ID: roleID, RoleSlug: "user", RoleLabel: "User"},
+		RefreshTokenId: uuid.NullUUID{UUID: refreshTokenID, Valid: true},
+		Status:         domain.UserStatusActive,
+	}
+	previous := domain.DataExportRecord{ID: uuid.New(), UserID: userID, RequestedBy: userID, FormatVersion: 1}
+	recorded := domain.DataExportRecord{ID: uuid.New(), UserID: userID, RequestedBy: adminID, FormatVersion: 1}
+
+	group := domain.Group{ID: uuid.New(), Name: "Support"}
+
+	service, m := newExportService()
+	m.userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(user, nil)
+	m.usernameHistoryRepo.On("GetByUser", mock.Anything, userID).Once().
+		Return([]domain.UsernameChange{{ID: uuid.New(), UserID: userID, OldUsername: "alice-old", NewUsername: "alice"}}, nil)
+	m.groupRepo.On("GetByMember", mock.Anything, userID).Once().Return([]domain.Group{group}, nil)
+	m.groupRepo.On("GetRoles", mock.Anything, group.ID).Once().
+		Return([]domain.Role{{ID: uuid.New(), RoleSlug: "editor", RoleLabel: "Editor"}}, nil)
+	m.refreshTokenRepo.On("GetByUUID", mock.Anything, refreshTokenID).Once().
+		Return(domain.RefreshToken{Id: refreshTokenID, Token: refreshToken, ValidUntil: time.Now().Add(time.Hour)}, nil)
+	m.mfaRepo.On("GetTOTPSecret", mock.Anything, userID).Once().
+		Return(domain.TOTPSecret{UserID: userID, EncryptedSecret: "encrypted-secret", ConfirmedAt: time.Now()}, nil)
+	m.passkeyRepo.On("GetCredentialsByUser", mock.Anything, userID).Once().
+		Return([]domain.PasskeyCredential{{ID: uuid.New(), UserID: userID, PublicKey: []byte("public-key")}}, nil)
+	m.identityProviderRepo.On("GetIdentitiesByUser", mock.Anything, userID).Once().
+		Return([]domain.ExternalIdentity{{ID: uuid.New(), ProviderID: uuid.New(), UserID: userID, Subject: "upstream-subject"}}, nil)
+	// A full page is followed by the request of the next one.
+	firstPage := make([]domain.LoginEvent, loginEventsPageSize)
+	for i := range firstPage {
+		firstPage[i] = domain.LoginEvent{ID: uuid.New(), UserID: userID, Type: domain.LoginEventLogin, Success: true}
+	}
+	m.loginEventRepo.On("GetByUser", mock.Anything, userID, domain.LoginEventFilter{Limit: loginEventsPageSize}).Once().
+		Return(firstPage, nil)
+	m.loginEventRepo.On("GetByUser", mock.Anything, userID, domain.LoginEventFilter{Limit: loginEventsPageSize, Offset: loginEventsPageSize}).Once().
+		Return([]domain.LoginEvent{{ID: uuid.New(), UserID: userID, Type: domain.LoginEventLogin, IP: "203.0.113.7", UserAgent: "curl/8.0"}}, nil)
+	m.oneTimeTokenRepo.On("GetByUser", mock.Anything, userID).Once().
+		Return([]domain.OneTimeToken{{ID: uuid.New(), UserID: userID, Purpose: domain.TokenPurposePasswordReset, TokenHash: "token-hash"}}, nil)
+	m.dataExportRepo.On("GetByUser", mock.Anything, userID).Once().Return([]domain.DataExportRecord{previous}, nil)
+	m.dataExportRepo.On("Store", mock.Anything, domain.DataExportRecord{
+		UserID:        userID,
+		RequestedBy:   adminID,
+		FormatVersion: domain.DataExportFormatVersion,
+	}).Once().Return(recorded, nil)
+
+	res, err := service.Export(context.TODO(), userID, adminID)
+	assert.Nil(t, err)
+
+	// No secrets are ever exported.
+	for _, secret := range []string{user.Password, refreshToken.String(), "encrypted-secret", "token-hash"} {
+		assert.NotContains(t, string(res), secret)
+	}
+
+	export := domain.DataExport{}
+	assert.Nil(t, json.Unmarshal(res, &export))
+	assert.Equal(t, domain.DataExportFormatVersion, export.FormatVersion)
+	assert.Equal(t, "alice", export.Profile.Username)
+	assert.Equal(t, "user", export.Profile.Role.RoleSlug)
+	assert.Len(t, export.UsernameHistory, 1)
+	assert.Equal(t, "alice-old", export.UsernameHistory[0].OldUsername)
+	assert.Len(t, export.Groups, 1)
+	assert.Equal(t, "Support", export.Groups[0].Name)
+	assert.Equal(t, "editor", export.Groups[0].Roles[0].RoleSlug)
+	assert.Equal(t, refreshTokenID, export.Session.ID)
+	assert.True(t, export.TOTP.IsConfirmed())
+	assert.Len(t, export.Passkeys, 1)
+	assert.Len(t, export.Identities, 1)
+	assert.Equal(t, "upstream-subject", export.Identities[0].Subject)
+	assert.Len(t, export.LoginEvents, loginEventsPageSize+1)
+	assert.Equal(t, "203.0.113.7", export.LoginEvents[loginEventsPageSize].IP)
+	assert.Equal(t, "curl/8.0", export.LoginEvents[loginEventsPageSize].UserAgent)
+	assert.Len(t, export.PendingTokens, 1)
+	assert.Equal(t, []uuid.UUID{previous.ID, recorded.ID}, []uuid.UUID{export.Exports[0].ID, export.Exports[1].ID})
+	m.usernameHistoryRepo.AssertExpectations(t)
+	m.groupRepo.AssertExpectations(t)
+	m.refreshTokenRepo.AssertExpectations(t)
+	m.mfaRepo.AssertExpectations(t)
+	m.passkeyRepo.AssertExpectations(t)
+	m.identityProviderRepo.AssertExpectations(t)
+	m.loginEventRepo.AssertExpectations(t)
+	m.oneTimeTokenRepo.AssertExpectations(t)
+	m.dataExportRepo.AssertExpectations(t)
+}
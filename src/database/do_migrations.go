@@ -6,9 +6,20 @@ import (
 	"log"
 	"time"
 
+	_attributesMigrations "github.com/plagioriginal/user-microservice/attributes/migrations"
+	_dataExportsMigrations "github.com/plagioriginal/user-microservice/data-exports/migrations"
 	"github.com/plagioriginal/user-microservice/database/migrations"
+	_groupsMigrations "github.com/plagioriginal/user-microservice/groups/migrations"
+	_identityProvidersMigrations "github.com/plagioriginal/user-microservice/identity-providers/migrations"
+	_loginAttemptsMigrations "github.com/plagioriginal/user-microservice/login-attempts/migrations"
+	_loginEventsMigrations "github.com/plagioriginal/user-microservice/login-events/migrations"
+	_mfaMigrations "github.com/plagioriginal/user-microservice/mfa/migrations"
+	_oneTimeTokensMigrations "github.com/plagioriginal/user-microservice/one-time-tokens/migrations"
+	_organizationsMigrations "github.com/plagioriginal/user-microservice/organizations/migrations"
+	_passkeysMigrations "github.com/plagioriginal/user-microservice/passkeys/migrations"
 	_refreshTokensMigrations "github.com/plagioriginal/user-microservice/refresh-tokens/migrations"
 	_rolesMigrations "github.com/plagioriginal/user-microservice/roles/migrations"
+	_usernameHistoryMigrations "github.com/plagioriginal/user-microservice/username-history/migrations"
 	_usersMigrations "github.com/plagioriginal/user-microservice/users/migrations"
 )
 
@@ -31,10 +42,43 @@ func DoMigrations(l *log.Logger, db *sql.DB, settings MigrationSettings) {
 			_usersMigrations.NewCreateUsersMigration(),
 			_refreshTokensMigrations.NewCreateRefreshTokensMigration(),
 			_usersMigrations.NewAddRefreshTokenReferenceMigration(),
+			_usersMigrations.NewAddNormalizedUsernameMigration(),
+			_loginAttemptsMigrations.NewCreateLoginAttemptsMigration(),
+			_usersMigrations.NewAddEmailMigration(),
+			_oneTimeTokensMigrations.NewCreateOneTimeTokensMigration(),
+			_rolesMigrations.NewAddRequiresMFAMigration(),
+			_mfaMigrations.NewCreateTOTPSecretsTableMigration(),
+			_mfaMigrations.NewCreateRecoveryCodesTableMigration(),
+			_passkeysMigrations.NewCreatePasskeyCredentialsTableMigration(),
+			_passkeysMigrations.NewCreatePasskeySessionsTableMigration(),
+			_usersMigrations.NewAddStatusMigration(),
+			_dataExportsMigrations.NewCreateDataExportsTableMigration(),
+			_usersMigrations.NewAddAttributesMigration(),
+			_attributesMigrations.NewCreateAttributeDefinitionsTableMigration(),
+			_groupsMigrations.NewCreateGroupsTableMigration(),
+			_groupsMigrations.NewCreateGroupMembersTableMigration(),
+			_groupsMigrations.NewCreateGroupRolesTableMigration(),
+			_organizationsMigrations.NewCreateOrganizationsTableMigration(),
+			_organizationsMigrations.NewAddOrganizationScopeMigration(),
+			_usersMigrations.NewAddLastLoginMigration(),
+			_loginEventsMigrations.NewCreateLoginEventsTableMigration(),
+			_usernameHistoryMigrations.NewCreateUsernameHistoryTableMigration(),
+			_usersMigrations.NewAddVersionMigration(),
+			_rolesMigrations.NewAddVersionMigration(),
+			_usersMigrations.NewAddPasswordChangeMigration(),
+			_rolesMigrations.NewAddPasswordMaxAgeMigration(),
+			_identityProvidersMigrations.NewCreateIdentityProvidersTableMigration(),
+			_identityProvidersMigrations.NewCreateOIDCSessionsTableMigration(),
+			_identityProvidersMigrations.NewCreateUserIdentitiesTableMigration(),
+			_rolesMigrations.NewScopeUniqueIndexesMigration(),
+			_oneTimeTokensMigrations.NewAddOrganizationScopeMigration(),
+			_passkeysMigrations.NewAddSessionOrganizationMigration(),
+			_usersMigrations.NewAddBackendMigration(),
 
 			// Seeds
 			_rolesMigrations.NewAddRolesMigration(),
 			_usersMigrations.NewAddDefaultUserMigration(settings.BcryptCost),
+			_usersMigrations.NewFlagDefaultUserPasswordChangeMigration(),
 		},
 	}
 
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Type of the values of a custom attribute.
+type AttributeType string
+
+const (
+	AttributeTypeString  AttributeType = "string"
+	AttributeTypeNumber  AttributeType = "number"
+	AttributeTypeBoolean AttributeType = "boolean"
+)
+
+// Names of the attributes, as they appear in the JSON documents and tokens.
+var attributeNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]{0,63}$`)
+
+// Returns if the type is one of the known types.
+func (t AttributeType) IsValid() bool {
+	switch t {
+	case AttributeTypeString, AttributeTypeNumber, AttributeTypeBoolean:
+		return true
+	}
+	return false
+}
+
+// Returns if a JSON decoded value is of this type.
+func (t AttributeType) Accepts(value interface{}) bool {
+	switch value.(type) {
+	case string:
+		return t == AttributeTypeString
+	case float64:
+		return t == AttributeTypeNumber
+	case bool:
+		return t == AttributeTypeBoolean
+	}
+	return false
+}
+
+// Custom attributes of a user, by name.
+type Attributes map[string]interface{}
+
+// Schema of a custom attribute, managed by the administrators.
+type AttributeDefinition struct {
+	Name string        `json:"name"`
+	Type AttributeType `json:"type"`
+	// Required attributes can't be removed by patching the attributes of a user. Users are
+	// created without them, and patches only check the attributes they change.
+	Required bool `json:"required"`
+	// Users can change the attribute themselves, otherwise only administrators can.
+	UserEditable bool `json:"userEditable"`
+	// The attribute is copied into the access tokens of the users.
+	InToken   bool      `json:"inToken"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Returns if the definition can be stored.
+func (d AttributeDefinition) IsValid() bool {
+	return attributeNamePattern.MatchString(d.Name) && d.Type.IsValid()
+}
+
+// Filters of the listing of users.
+type UserFilter struct {
+	// Users must have all these attributes, with the same values.
+	Attributes Attributes
+	Limit      int
+	Offset     int
+}
+
+type AttributeRepository interface {
+	Fetch(ctx context.Context) ([]AttributeDefinition, error)
+	// Creates or updates a definition.
+	Save(ctx context.Context, definition AttributeDefinition) (AttributeDefinition, error)
+	// Deletes a definition, removing the attribute from every user.
+	Delete(ctx context.Context, name string) error
+}
+
+type AttributeService interface {
+	GetDefinitions(ctx context.Context) ([]AttributeDefinition, error)
+	SaveDefinition(ctx context.Context, definition AttributeDefinition) (AttributeDefinition, error)
+	DeleteDefinition(ctx context.Context, name string) error
+	// Merges a patch into the attributes of a user at the given version, where null values remove attributes.
+	// Only administrators can change attributes that aren't user editable.
+	// Returns the user with the merged attributes.
+	Patch(ctx context.Context, userID uuid.UUID, version int, patch Attributes, asAdmin bool) (*User, error)
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Version of the format of the data exports, raised on every breaking change.
+const DataExportFormatVersion = 1
+
+// Record of an export of the data of a user.
+type DataExportRecord struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"userId"`
+	// User who asked for it, the user themselves or an administrator.
+	RequestedBy   uuid.UUID `json:"requestedBy"`
+	FormatVersion int       `json:"formatVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// Session of a user, without the refresh token itself.
+type DataExportSession struct {
+	ID         uuid.UUID `json:"id"`
+	ValidUntil time.Time `json:"validUntil"`
+}
+
+// Everything stored about a user, handed over on data-subject access requests.
+// Secrets (password hash, tokens, keys) are never part of it.
+type DataExport struct {
+	FormatVersion int       `json:"formatVersion"`
+	GeneratedAt   time.Time `json:"generatedAt"`
+	Profile       User      `json:"profile"`
+	// Previous usernames of the user, newest first.
+	UsernameHistory []UsernameChange `json:"usernameHistory"`
+	// Groups the user is a member of, with the roles it inherits from them.
+	Groups   []Group             `json:"groups"`
+	Session  *DataExportSession  `json:"session"`
+	TOTP     *TOTPSecret         `json:"totp"`
+	Passkeys []PasskeyCredential `json:"passkeys"`
+	// Accounts of OpenID Connect providers linked to the user.
+	Identities []ExternalIdentity `json:"identities"`
+	// Every login, refresh and logout of the user, newest first, with the IPs and user agents.
+	LoginEvents []LoginEvent `json:"loginEvents"`
+	// Email verification and password reset links not used yet.
+	PendingTokens []OneTimeToken     `json:"pendingTokens"`
+	Exports       []DataExportRecord `json:"exports"`
+}
+
+type DataExportRepository interface {
+	Store(ctx context.Context, record DataExportRecord) (DataExportRecord, error)
+	GetByUser(ctx context.Context, userID uuid.UUID) ([]DataExportRecord, error)
+}
+
+type DataExportService interface {
+	// Gets the JSON document with the data of a user, recording the export.
+	Export(ctx context.Context, userID uuid.UUID, requestedBy uuid.UUID) ([]byte, error)
+}
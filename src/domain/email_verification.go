@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Settings for the email verification flow.
+type EmailVerificationSettings struct {
+	// Link sent to the users, the token is added as the "token" query parameter.
+	VerificationURL string
+	// How long the verification links are valid.
+	TokenTTL time.Duration
+}
+
+type EmailVerificationService interface {
+	SendVerificationEmail(ctx context.Context, user *User) error
+	VerifyEmail(ctx context.Context, token string) (*User, error)
+}
@@ -7,4 +7,12 @@ var (
 	ErrBadParamInput = errors.New("invalid parameter")
 	ErrNotFound      = errors.New("resource not found")
 	ErrInvalidToken  = errors.New("invalid token")
+	ErrAlreadyExists = errors.New("resource already exists")
+	ErrUserNotActive = errors.New("user is not active")
+	// The user has to change its password before getting new tokens.
+	ErrPasswordChangeRequired = errors.New("password change required")
+	// The user doesn't log in with a password of the service, so it can't set one.
+	ErrNoLocalPassword = errors.New("user has no local password")
+	// The resource changed since the version the caller had.
+	ErrVersionConflict = errors.New("version conflict")
 )
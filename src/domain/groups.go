@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Group of users. Its members inherit the roles assigned to it, on top of their own role.
+type Group struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Roles       []Role    `json:"roles"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type GroupRepository interface {
+	Fetch(ctx context.Context) ([]Group, error)
+	GetByUUID(ctx context.Context, id uuid.UUID) (Group, error)
+	// Creates or updates a group. Names are unique, ignoring the case.
+	Save(ctx context.Context, group Group) (Group, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// Adds a user to a group, doing nothing if it already is a member.
+	AddMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error
+	RemoveMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error
+	GetMembers(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error)
+	// Gets the groups a user is a member of, without their roles.
+	GetByMember(ctx context.Context, userID uuid.UUID) ([]Group, error)
+	// Assigns a role to a group, doing nothing if it already is assigned.
+	AssignRole(ctx context.Context, groupID uuid.UUID, roleID uuid.UUID) error
+	UnassignRole(ctx context.Context, groupID uuid.UUID, roleID uuid.UUID) error
+	GetRoles(ctx context.Context, groupID uuid.UUID) ([]Role, error)
+	// Gets the roles a user inherits from all of its groups.
+	GetRolesByMember(ctx context.Context, userID uuid.UUID) ([]Role, error)
+}
+
+type GroupService interface {
+	GetAll(ctx context.Context) ([]Group, error)
+	Get(ctx context.Context, id uuid.UUID) (Group, error)
+	// Creates a group when it has no ID, updates it otherwise.
+	Save(ctx context.Context, group Group) (Group, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	AddMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error
+	RemoveMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error
+	GetMembers(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error)
+	AssignRole(ctx context.Context, groupID uuid.UUID, roleSlug string) (Group, error)
+	UnassignRole(ctx context.Context, groupID uuid.UUID, roleSlug string) (Group, error)
+}
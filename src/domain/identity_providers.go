@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Slugs of the identity providers, as they appear in the login requests.
+var identityProviderSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// Upstream OpenID Connect provider the users of an organization can log in with,
+// instead of a local password.
+type IdentityProvider struct {
+	ID           uuid.UUID `json:"id"`
+	Slug         string    `json:"slug"`
+	Name         string    `json:"name"`
+	Issuer       string    `json:"issuer"`
+	ClientID     string    `json:"clientId"`
+	ClientSecret string    `json:"-"`
+	// Where the provider sends the users back to, registered on the provider.
+	RedirectURL string `json:"redirectUrl"`
+	// Scopes asked for on top of openid.
+	Scopes []string `json:"scopes"`
+	// Claim of the ID tokens with the upstream roles or groups of the user, a string or a list of strings.
+	RoleClaim string `json:"roleClaim"`
+	// Local role slug by upstream value of the RoleClaim. The first value with a mapping wins.
+	RoleMapping map[string]string `json:"roleMapping"`
+	// Role of the provisioned users none of whose upstream roles are mapped.
+	DefaultRole string `json:"defaultRole"`
+	// Local users with the same email, verified by the provider, are linked to their upstream account.
+	LinkByEmail bool `json:"linkByEmail"`
+	// Upstream users without a local user get one on their first login.
+	AutoProvision bool      `json:"autoProvision"`
+	Enabled       bool      `json:"enabled"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// Returns if the provider can be stored.
+func (p IdentityProvider) IsValid() bool {
+	if !identityProviderSlugPattern.MatchString(p.Slug) || len(p.ClientID) == 0 {
+		return false
+	}
+	if p.AutoProvision && len(p.DefaultRole) == 0 && len(p.RoleMapping) == 0 {
+		return false
+	}
+	return isAbsoluteURL(p.Issuer) && isAbsoluteURL(p.RedirectURL)
+}
+
+// Returns if a string is an absolute http(s) URL.
+func isAbsoluteURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "https" || parsed.Scheme == "http") && len(parsed.Host) > 0
+}
+
+// Upstream account of a local user.
+type ExternalIdentity struct {
+	ID         uuid.UUID `json:"id"`
+	ProviderID uuid.UUID `json:"providerId"`
+	UserID     uuid.UUID `json:"userId"`
+	// Subject of the ID tokens, unique within the provider.
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// State kept between sending a user to the provider and it coming back.
+type OIDCSession struct {
+	// Sent as the state of the authorization request.
+	ID         uuid.UUID `json:"id"`
+	ProviderID uuid.UUID `json:"providerId"`
+	// Expected in the ID token, so it can't be replayed.
+	Nonce string `json:"-"`
+	// PKCE verifier of the code challenge sent to the provider.
+	CodeVerifier string    `json:"-"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+// Returns if the session can't be used anymore.
+func (s OIDCSession) IsExpired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
+
+// Where to send a user to log in with a provider.
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorizationUrl"`
+	State            string `json:"state"`
+}
+
+// Settings of the OpenID Connect relying party.
+type OIDCSettings struct {
+	// How long a user has to log in upstream and come back.
+	SessionTTL time.Duration
+	// How long the discovery documents and keys of the providers are cached.
+	DiscoveryTTL time.Duration
+	// Timeout of the requests to the providers.
+	HTTPTimeout time.Duration
+	// Allows issuers over http or on private and loopback addresses, for local development and tests.
+	AllowPrivateIssuers bool
+}
+
+type IdentityProviderRepository interface {
+	Fetch(ctx context.Context) ([]IdentityProvider, error)
+	GetBySlug(ctx context.Context, slug string) (IdentityProvider, error)
+	GetByUUID(ctx context.Context, id uuid.UUID) (IdentityProvider, error)
+	// Creates a provider, or updates it when one with the same slug exists.
+	Save(ctx context.Context, provider IdentityProvider) (IdentityProvider, error)
+	// Deletes a provider, along with the links of the users to it.
+	Delete(ctx context.Context, slug string) error
+	StoreSession(ctx context.Context, session OIDCSession) error
+	ConsumeSession(ctx context.Context, id uuid.UUID) (OIDCSession, error)
+	GetIdentity(ctx context.Context, providerID uuid.UUID, subject string) (ExternalIdentity, error)
+	StoreIdentity(ctx context.Context, identity ExternalIdentity) (ExternalIdentity, error)
+	// Gets the upstream accounts linked to a user.
+	GetIdentitiesByUser(ctx context.Context, userID uuid.UUID) ([]ExternalIdentity, error)
+}
+
+type OIDCService interface {
+	GetProviders(ctx context.Context) ([]IdentityProvider, error)
+	// Creates or updates a provider. An empty client secret keeps the stored one.
+	SaveProvider(ctx context.Context, provider IdentityProvider) (IdentityProvider, error)
+	DeleteProvider(ctx context.Context, slug string) error
+	// Starts a login with a provider, returning where to send the user.
+	BeginLogin(ctx context.Context, providerSlug string) (OIDCAuthorization, error)
+	// Finishes a login with the authorization code the provider sent back with the state,
+	// returning the local user of the upstream account.
+	FinishLogin(ctx context.Context, state string, code string) (*User, error)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Settings for the invitations sent by administrators.
+type InvitationSettings struct {
+	// Link sent to the invited users, the token is added as the "token" query parameter.
+	AcceptURL string
+	// How long the invitation links are valid.
+	TokenTTL time.Duration
+	// Shortest password the invited users can choose, like the imported ones.
+	MinPasswordLength int
+}
+
+// Pending user invited by an administrator, that chooses its own password when accepting.
+type Invitation struct {
+	User      *User     `json:"user"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type InviteUserRequest struct {
+	Username string
+	Email    string
+	RoleSlug string
+}
+
+type AcceptInvitationRequest struct {
+	Token    string
+	Password string
+	Profile  UserProfile
+}
+
+type InvitationService interface {
+	// Creates a pending user and sends it an invitation link by email.
+	Invite(ctx context.Context, request InviteUserRequest) (Invitation, error)
+	// Activates the invited user with its password and profile.
+	Accept(ctx context.Context, request AcceptInvitationRequest) (*User, error)
+	GetPending(ctx context.Context) ([]Invitation, error)
+	// Sends a new invitation link to a pending user, the previous one stops working.
+	Resend(ctx context.Context, userID uuid.UUID) (Invitation, error)
+	// Revokes the invitation of a pending user, deleting it.
+	Revoke(ctx context.Context, userID uuid.UUID) error
+}
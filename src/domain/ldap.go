@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"crypto/x509"
+	"time"
+)
+
+// Settings of the logins against an LDAP directory, like Active Directory.
+type LDAPSettings struct {
+	// ldap:// or ldaps:// URL of the directory.
+	URL string
+	// Upgrades ldap:// connections to TLS before sending any credentials.
+	StartTLS bool
+	// CAs the certificate of the directory is verified with, the system ones when nil.
+	RootCAs *x509.CertPool
+	// Skips the verification of the certificate of the directory. Only for development.
+	InsecureSkipVerify bool
+	// DN the users bind as, with %s replaced by the username (e.g. uid=%s,ou=people,dc=example,dc=com).
+	// When empty, the users are searched for first, and then bound as the DN found.
+	BindDNTemplate string
+	// Account the users are searched for with, anonymously when empty.
+	BindDN       string
+	BindPassword string
+	// Where the users are searched for, and the filter with %s replaced by the username.
+	BaseDN     string
+	UserFilter string
+	// Attributes of the users copied into their local user.
+	EmailAttribute     string
+	FirstNameAttribute string
+	LastNameAttribute  string
+	// Attribute of the users with the DNs of their groups, like memberOf.
+	GroupAttribute string
+	// When set, the groups are searched for under this DN instead, with the filter
+	// with %s replaced by the DN of the user (e.g. (member=%s)).
+	GroupBaseDN string
+	GroupFilter string
+	// Local role slug by group DN. The first group of the user with a mapping wins.
+	RoleMapping map[string]string
+	// Role of the users none of whose groups are mapped.
+	DefaultRole string
+	// Directory users without a local user get one on their first login.
+	AutoProvision bool
+	// Timeout of the connections and requests to the directory.
+	Timeout time.Duration
+}
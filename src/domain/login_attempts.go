@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Failed login attempts for a given key (a username or a client IP).
+type LoginAttempt struct {
+	Key            string    `json:"key"`
+	FailedAttempts int       `json:"failedAttempts"`
+	LastFailedAt   time.Time `json:"lastFailedAt"`
+	LockedUntil    time.Time `json:"lockedUntil"`
+}
+
+// Settings for the brute-force protection of the logins.
+type LoginThrottleSettings struct {
+	// Failed attempts allowed for a username before it gets locked.
+	MaxFailedAttemptsPerUser int
+	// Failed attempts allowed for a client IP before it gets locked.
+	MaxFailedAttemptsPerIP int
+	// Lockout applied when the threshold is reached, doubled on every further failure.
+	LockoutDuration time.Duration
+	// Upper bound of the lockout.
+	MaxLockoutDuration time.Duration
+	// Failures older than this window are forgotten.
+	FailureWindow time.Duration
+}
+
+type LoginAttemptRepository interface {
+	GetByKey(ctx context.Context, key string) (LoginAttempt, error)
+	IncrementFailures(ctx context.Context, key string, windowStart time.Time) (LoginAttempt, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Delete(ctx context.Context, key string) error
+}
+
+type LoginAttemptService interface {
+	GetRetryAfter(ctx context.Context, username string, ip string) (time.Duration, error)
+	RegisterFailure(ctx context.Context, username string, ip string) (time.Duration, error)
+	RegisterSuccess(ctx context.Context, username string) error
+	ClearLockout(ctx context.Context, username string, ip string) error
+}
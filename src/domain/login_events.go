@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// What a login event records.
+type LoginEventType string
+
+const (
+	LoginEventLogin   LoginEventType = "login"
+	LoginEventRefresh LoginEventType = "refresh"
+	LoginEventLogout  LoginEventType = "logout"
+)
+
+// Attempt to log in, refresh the tokens or log out, successful or not.
+type LoginEvent struct {
+	ID uuid.UUID `json:"id"`
+	// Nil when the user is unknown, e.g. logins with a username that doesn't exist.
+	UserID   uuid.UUID      `json:"userId"`
+	Username string         `json:"username"`
+	Type     LoginEventType `json:"type"`
+	Success  bool           `json:"success"`
+	// Why the attempt failed.
+	Reason    string    `json:"reason,omitempty"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"userAgent"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Page of the login history of a user, optionally between two dates.
+type LoginEventFilter struct {
+	// Zero to not limit the oldest events.
+	From time.Time
+	// Zero to not limit the newest events.
+	To     time.Time
+	Limit  int
+	Offset int
+}
+
+type LoginEventRepository interface {
+	Store(ctx context.Context, event LoginEvent) (LoginEvent, error)
+	// Gets the events of a user, newest first.
+	GetByUser(ctx context.Context, userID uuid.UUID, filter LoginEventFilter) ([]LoginEvent, error)
+}
+
+type LoginEventService interface {
+	// Records an event, keeping track of the last login of the user on successful logins.
+	Record(ctx context.Context, event LoginEvent) error
+	GetHistory(ctx context.Context, userID uuid.UUID, filter LoginEventFilter) ([]LoginEvent, error)
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Settings for the passwordless login with links.
+type MagicLinkSettings struct {
+	// Link sent to the users, the token is added as the "token" query parameter.
+	LoginURL string
+	// How long the links are valid.
+	TokenTTL time.Duration
+	// Links that can be requested for an account within the request window.
+	MaxRequestsPerWindow int
+	RequestWindow        time.Duration
+}
+
+type MagicLinkService interface {
+	// Sends a login link to a user. Links requested with a nonce can only be
+	// redeemed with the same nonce, binding them to the device that asked for them.
+	Request(ctx context.Context, username string, nonce string) error
+	// Gets the user of a link, which can't be used again afterwards.
+	Redeem(ctx context.Context, token string, nonce string) (*User, error)
+}
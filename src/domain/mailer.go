@@ -0,0 +1,14 @@
+package domain
+
+import "context"
+
+// Plain text email sent to a user.
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, email Email) error
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TOTP (RFC 6238) shared secret of a user, stored encrypted.
+// The enrollment is pending until the user confirms it with a first code.
+type TOTPSecret struct {
+	UserID          uuid.UUID `json:"userId"`
+	EncryptedSecret string    `json:"-"`
+	ConfirmedAt     time.Time `json:"confirmedAt"`
+	// Last time step a code was accepted for, so codes can't be replayed.
+	LastUsedStep int64     `json:"-"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// Returns if the enrollment was confirmed.
+func (s TOTPSecret) IsConfirmed() bool {
+	return !s.ConfirmedAt.IsZero()
+}
+
+// Data needed by an authenticator app to start generating codes.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// Settings of the multi-factor authentication.
+type MFASettings struct {
+	// Issuer shown in the authenticator apps.
+	Issuer string
+	// AES-256 key used to encrypt the TOTP secrets.
+	EncryptionKey []byte
+	// Recovery codes generated when the enrollment is confirmed.
+	RecoveryCodesCount int
+}
+
+type MFARepository interface {
+	GetTOTPSecret(ctx context.Context, userID uuid.UUID) (TOTPSecret, error)
+	SaveTOTPSecret(ctx context.Context, secret TOTPSecret) error
+	ConfirmTOTPSecret(ctx context.Context, userID uuid.UUID, step int64) error
+	UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) error
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error
+}
+
+type MFAService interface {
+	BeginTOTPEnrollment(ctx context.Context, user *User) (TOTPEnrollment, error)
+	ConfirmTOTPEnrollment(ctx context.Context, user *User, code string) ([]string, error)
+	IsEnrolled(ctx context.Context, userID uuid.UUID) (bool, error)
+	VerifyCode(ctx context.Context, userID uuid.UUID, code string) error
+	VerifyRecoveryCode(ctx context.Context, userID uuid.UUID, code string) error
+}
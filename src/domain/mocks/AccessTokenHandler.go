@@ -6,11 +6,9 @@ import (
 	context "context"
 
 	jwt "github.com/golang-jwt/jwt"
+	uuid "github.com/google/uuid"
 	domain "github.com/plagioriginal/user-microservice/domain"
-
 	mock "github.com/stretchr/testify/mock"
-
-	uuid "github.com/google/uuid"
 )
 
 // AccessTokenHandler is an autogenerated mock type for the AccessTokenHandler type
@@ -19,17 +17,68 @@ type AccessTokenHandler struct {
 }
 
 // DeleteRefreshToken provides a mock function with given fields: ctx, refreshToken
-func (_m *AccessTokenHandler) DeleteRefreshToken(ctx context.Context, refreshToken string) bool {
+func (_m *AccessTokenHandler) DeleteRefreshToken(ctx context.Context, refreshToken string) (*domain.User, bool) {
 	ret := _m.Called(ctx, refreshToken)
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+	var r0 *domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.User); ok {
 		r0 = rf(ctx, refreshToken)
 	} else {
-		r0 = ret.Get(0).(bool)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
 	}
 
-	return r0
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(context.Context, string) bool); ok {
+		r1 = rf(ctx, refreshToken)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// GenerateMFAChallenge provides a mock function with given fields: user
+func (_m *AccessTokenHandler) GenerateMFAChallenge(user *domain.User) (string, error) {
+	ret := _m.Called(user)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*domain.User) string); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*domain.User) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GeneratePasswordChangeToken provides a mock function with given fields: user
+func (_m *AccessTokenHandler) GeneratePasswordChangeToken(user *domain.User) (string, error) {
+	ret := _m.Called(user)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*domain.User) string); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*domain.User) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // GenerateTokens provides a mock function with given fields: ctx, user
@@ -53,6 +102,93 @@ func (_m *AccessTokenHandler) GenerateTokens(ctx context.Context, user *domain.U
 	return r0, r1
 }
 
+// GetOrganizationIDFromToken provides a mock function with given fields: token
+func (_m *AccessTokenHandler) GetOrganizationIDFromToken(token *jwt.Token) (uuid.UUID, error) {
+	ret := _m.Called(token)
+
+	var r0 uuid.UUID
+	if rf, ok := ret.Get(0).(func(*jwt.Token) uuid.UUID); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*jwt.Token) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserIDFromMFAChallenge provides a mock function with given fields: tokenString
+func (_m *AccessTokenHandler) GetUserIDFromMFAChallenge(tokenString string) (uuid.UUID, uuid.UUID, error) {
+	ret := _m.Called(tokenString)
+
+	var r0 uuid.UUID
+	if rf, ok := ret.Get(0).(func(string) uuid.UUID); ok {
+		r0 = rf(tokenString)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	var r1 uuid.UUID
+	if rf, ok := ret.Get(1).(func(string) uuid.UUID); ok {
+		r1 = rf(tokenString)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(uuid.UUID)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(tokenString)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetUserIDFromPasswordChangeToken provides a mock function with given fields: tokenString
+func (_m *AccessTokenHandler) GetUserIDFromPasswordChangeToken(tokenString string) (uuid.UUID, uuid.UUID, error) {
+	ret := _m.Called(tokenString)
+
+	var r0 uuid.UUID
+	if rf, ok := ret.Get(0).(func(string) uuid.UUID); ok {
+		r0 = rf(tokenString)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	var r1 uuid.UUID
+	if rf, ok := ret.Get(1).(func(string) uuid.UUID); ok {
+		r1 = rf(tokenString)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(uuid.UUID)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(tokenString)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetUserIDFromToken provides a mock function with given fields: token
 func (_m *AccessTokenHandler) GetUserIDFromToken(token *jwt.Token) (uuid.UUID, error) {
 	ret := _m.Called(token)
@@ -76,15 +212,17 @@ func (_m *AccessTokenHandler) GetUserIDFromToken(token *jwt.Token) (uuid.UUID, e
 	return r0, r1
 }
 
-// GetUserRoleFromToken provides a mock function with given fields: token
-func (_m *AccessTokenHandler) GetUserRoleFromToken(token *jwt.Token) (string, error) {
+// GetUserRolesFromToken provides a mock function with given fields: token
+func (_m *AccessTokenHandler) GetUserRolesFromToken(token *jwt.Token) ([]string, error) {
 	ret := _m.Called(token)
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func(*jwt.Token) string); ok {
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(*jwt.Token) []string); ok {
 		r0 = rf(token)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
 	}
 
 	var r1 error
@@ -97,13 +235,13 @@ func (_m *AccessTokenHandler) GetUserRoleFromToken(token *jwt.Token) (string, er
 	return r0, r1
 }
 
-// IsJWTokenValid provides a mock function with given fields: token
-func (_m *AccessTokenHandler) IsJWTokenValid(token *jwt.Token) bool {
-	ret := _m.Called(token)
+// IsJWTokenValid provides a mock function with given fields: ctx, token
+func (_m *AccessTokenHandler) IsJWTokenValid(ctx context.Context, token *jwt.Token) bool {
+	ret := _m.Called(ctx, token)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(*jwt.Token) bool); ok {
-		r0 = rf(token)
+	if rf, ok := ret.Get(0).(func(context.Context, *jwt.Token) bool); ok {
+		r0 = rf(ctx, token)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
@@ -4,11 +4,11 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
+	uuid "github.com/google/uuid"
 	domain "github.com/plagioriginal/user-microservice/domain"
 	mock "github.com/stretchr/testify/mock"
-
-	uuid "github.com/google/uuid"
 )
 
 // RoleRepository is an autogenerated mock type for the RoleRepository type
@@ -16,6 +16,20 @@ type RoleRepository struct {
 	mock.Mock
 }
 
+// Delete provides a mock function with given fields: ctx, id, version, deletedAt
+func (_m *RoleRepository) Delete(ctx context.Context, id uuid.UUID, version int, deletedAt time.Time) error {
+	ret := _m.Called(ctx, id, version, deletedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, time.Time) error); ok {
+		r0 = rf(ctx, id, version, deletedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Fetch provides a mock function with given fields: ctx
 func (_m *RoleRepository) Fetch(ctx context.Context) ([]domain.Role, error) {
 	ret := _m.Called(ctx)
@@ -101,3 +115,24 @@ func (_m *RoleRepository) Store(ctx context.Context, role domain.Role) (domain.R
 
 	return r0, r1
 }
+
+// Update provides a mock function with given fields: ctx, role
+func (_m *RoleRepository) Update(ctx context.Context, role domain.Role) (domain.Role, error) {
+	ret := _m.Called(ctx, role)
+
+	var r0 domain.Role
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Role) domain.Role); ok {
+		r0 = rf(ctx, role)
+	} else {
+		r0 = ret.Get(0).(domain.Role)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Role) error); ok {
+		r1 = rf(ctx, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -5,6 +5,7 @@ package mocks
 import (
 	context "context"
 
+	uuid "github.com/google/uuid"
 	domain "github.com/plagioriginal/user-microservice/domain"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -14,6 +15,43 @@ type RoleService struct {
 	mock.Mock
 }
 
+// Delete provides a mock function with given fields: ctx, id, version
+func (_m *RoleService) Delete(ctx context.Context, id uuid.UUID, version int) error {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) error); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx
+func (_m *RoleService) GetAll(ctx context.Context) ([]domain.Role, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []domain.Role
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Role); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Role)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBySlug provides a mock function with given fields: ctx, slug
 func (_m *RoleService) GetBySlug(ctx context.Context, slug string) (domain.Role, error) {
 	ret := _m.Called(ctx, slug)
@@ -55,3 +93,24 @@ func (_m *RoleService) Store(ctx context.Context, role domain.Role) (domain.Role
 
 	return r0, r1
 }
+
+// Update provides a mock function with given fields: ctx, role
+func (_m *RoleService) Update(ctx context.Context, role domain.Role) (domain.Role, error) {
+	ret := _m.Called(ctx, role)
+
+	var r0 domain.Role
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Role) domain.Role); ok {
+		r0 = rf(ctx, role)
+	} else {
+		r0 = ret.Get(0).(domain.Role)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Role) error); ok {
+		r1 = rf(ctx, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -4,11 +4,11 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
+	uuid "github.com/google/uuid"
 	domain "github.com/plagioriginal/user-microservice/domain"
 	mock "github.com/stretchr/testify/mock"
-
-	uuid "github.com/google/uuid"
 )
 
 // UserRepository is an autogenerated mock type for the UserRepository type
@@ -16,6 +16,122 @@ type UserRepository struct {
 	mock.Mock
 }
 
+// Activate provides a mock function with given fields: ctx, id, version, password, profile, invitationID
+func (_m *UserRepository) Activate(ctx context.Context, id uuid.UUID, version int, password string, profile domain.UserProfile, invitationID uuid.UUID) error {
+	ret := _m.Called(ctx, id, version, password, profile, invitationID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, string, domain.UserProfile, uuid.UUID) error); ok {
+		r0 = rf(ctx, id, version, password, profile, invitationID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ChangePassword provides a mock function with given fields: ctx, id, password
+func (_m *UserRepository) ChangePassword(ctx context.Context, id uuid.UUID, password string) error {
+	ret := _m.Called(ctx, id, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, id, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountPasswordSchemes provides a mock function with given fields: ctx, schemes
+func (_m *UserRepository) CountPasswordSchemes(ctx context.Context, schemes []string) (map[string]int, error) {
+	ret := _m.Called(ctx, schemes)
+
+	var r0 map[string]int
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]int); ok {
+		r0 = rf(ctx, schemes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, schemes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id, version, deletedAt
+func (_m *UserRepository) Delete(ctx context.Context, id uuid.UUID, version int, deletedAt time.Time) error {
+	ret := _m.Called(ctx, id, version, deletedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, time.Time) error); ok {
+		r0 = rf(ctx, id, version, deletedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindConflicts provides a mock function with given fields: ctx, username, email
+func (_m *UserRepository) FindConflicts(ctx context.Context, username string, email string) (bool, bool, error) {
+	ret := _m.Called(ctx, username, email)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(ctx, username, email)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) bool); ok {
+		r1 = rf(ctx, username, email)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, string) error); ok {
+		r2 = rf(ctx, username, email)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetByEmail provides a mock function with given fields: ctx, email
+func (_m *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	ret := _m.Called(ctx, email)
+
+	var r0 *domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByRefreshToken provides a mock function with given fields: ctx, id
 func (_m *UserRepository) GetByRefreshToken(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	ret := _m.Called(ctx, id)
@@ -85,6 +201,101 @@ func (_m *UserRepository) GetByUsername(ctx context.Context, username string) (*
 	return r0, r1
 }
 
+// List provides a mock function with given fields: ctx, filter
+func (_m *UserRepository) List(ctx context.Context, filter domain.UserFilter) ([]domain.User, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserFilter) []domain.User); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.UserFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDeleted provides a mock function with given fields: ctx, deletedAfter, limit, offset
+func (_m *UserRepository) ListDeleted(ctx context.Context, deletedAfter time.Time, limit int, offset int) ([]domain.User, error) {
+	ret := _m.Called(ctx, deletedAfter, limit, offset)
+
+	var r0 []domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int, int) []domain.User); ok {
+		r0 = rf(ctx, deletedAfter, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int, int) error); ok {
+		r1 = rf(ctx, deletedAfter, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkEmailVerified provides a mock function with given fields: ctx, id, email
+func (_m *UserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) error {
+	ret := _m.Called(ctx, id, email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, id, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PurgeDeleted provides a mock function with given fields: ctx, deletedBefore, limit
+func (_m *UserRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) (int64, error) {
+	ret := _m.Called(ctx, deletedBefore, limit)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) int64); ok {
+		r0 = rf(ctx, deletedBefore, limit)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, deletedBefore, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id, deletedAfter
+func (_m *UserRepository) Restore(ctx context.Context, id uuid.UUID, deletedAfter time.Time) error {
+	ret := _m.Called(ctx, id, deletedAfter)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r0 = rf(ctx, id, deletedAfter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SaveRefreshToken provides a mock function with given fields: ctx, user, token
 func (_m *UserRepository) SaveRefreshToken(ctx context.Context, user *domain.User, token domain.RefreshToken) error {
 	ret := _m.Called(ctx, user, token)
@@ -121,3 +332,110 @@ func (_m *UserRepository) Store(ctx context.Context, user domain.User) (*domain.
 
 	return r0, r1
 }
+
+// StoreMany provides a mock function with given fields: ctx, users, batchSize
+func (_m *UserRepository) StoreMany(ctx context.Context, users []domain.User, batchSize int) ([]uuid.UUID, error) {
+	ret := _m.Called(ctx, users, batchSize)
+
+	var r0 []uuid.UUID
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.User, int) []uuid.UUID); ok {
+		r0 = rf(ctx, users, batchSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uuid.UUID)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.User, int) error); ok {
+		r1 = rf(ctx, users, batchSize)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SyncProfile provides a mock function with given fields: ctx, id, version, email, profile, roleID
+func (_m *UserRepository) SyncProfile(ctx context.Context, id uuid.UUID, version int, email string, profile domain.UserProfile, roleID uuid.UUID) error {
+	ret := _m.Called(ctx, id, version, email, profile, roleID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, string, domain.UserProfile, uuid.UUID) error); ok {
+		r0 = rf(ctx, id, version, email, profile, roleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAttributes provides a mock function with given fields: ctx, id, version, attributes
+func (_m *UserRepository) UpdateAttributes(ctx context.Context, id uuid.UUID, version int, attributes domain.Attributes) error {
+	ret := _m.Called(ctx, id, version, attributes)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, domain.Attributes) error); ok {
+		r0 = rf(ctx, id, version, attributes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateLastLogin provides a mock function with given fields: ctx, id, at
+func (_m *UserRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error {
+	ret := _m.Called(ctx, id, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: ctx, id, password
+func (_m *UserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, password string) error {
+	ret := _m.Called(ctx, id, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, id, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateStatus provides a mock function with given fields: ctx, id, version, from, to, reason
+func (_m *UserRepository) UpdateStatus(ctx context.Context, id uuid.UUID, version int, from domain.UserStatus, to domain.UserStatus, reason string) error {
+	ret := _m.Called(ctx, id, version, from, to, reason)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, domain.UserStatus, domain.UserStatus, string) error); ok {
+		r0 = rf(ctx, id, version, from, to, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUsername provides a mock function with given fields: ctx, id, version, change
+func (_m *UserRepository) UpdateUsername(ctx context.Context, id uuid.UUID, version int, change domain.UsernameChange) error {
+	ret := _m.Called(ctx, id, version, change)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, domain.UsernameChange) error); ok {
+		r0 = rf(ctx, id, version, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -5,10 +5,9 @@ package mocks
 import (
 	context "context"
 
+	uuid "github.com/google/uuid"
 	domain "github.com/plagioriginal/user-microservice/domain"
 	mock "github.com/stretchr/testify/mock"
-
-	uuid "github.com/google/uuid"
 )
 
 // UserService is an autogenerated mock type for the UserService type
@@ -16,6 +15,96 @@ type UserService struct {
 	mock.Mock
 }
 
+// ChangePassword provides a mock function with given fields: ctx, id, password
+func (_m *UserService) ChangePassword(ctx context.Context, id uuid.UUID, password string) (*domain.User, error) {
+	ret := _m.Called(ctx, id, password)
+
+	var r0 *domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) *domain.User); ok {
+		r0 = rf(ctx, id, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, id, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ChangeStatus provides a mock function with given fields: ctx, id, version, status, reason
+func (_m *UserService) ChangeStatus(ctx context.Context, id uuid.UUID, version int, status domain.UserStatus, reason string) (*domain.User, error) {
+	ret := _m.Called(ctx, id, version, status, reason)
+
+	var r0 *domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, domain.UserStatus, string) *domain.User); ok {
+		r0 = rf(ctx, id, version, status, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int, domain.UserStatus, string) error); ok {
+		r1 = rf(ctx, id, version, status, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ChangeUsername provides a mock function with given fields: ctx, id, version, username
+func (_m *UserService) ChangeUsername(ctx context.Context, id uuid.UUID, version int, username string) (*domain.User, error) {
+	ret := _m.Called(ctx, id, version, username)
+
+	var r0 *domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, string) *domain.User); ok {
+		r0 = rf(ctx, id, version, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int, string) error); ok {
+		r1 = rf(ctx, id, version, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLegacyPasswordReport provides a mock function with given fields: ctx
+func (_m *UserService) GetLegacyPasswordReport(ctx context.Context) (domain.LegacyPasswordReport, error) {
+	ret := _m.Called(ctx)
+
+	var r0 domain.LegacyPasswordReport
+	if rf, ok := ret.Get(0).(func(context.Context) domain.LegacyPasswordReport); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(domain.LegacyPasswordReport)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserByLogin provides a mock function with given fields: ctx, request
 func (_m *UserService) GetUserByLogin(ctx context.Context, request domain.GetUserRequest) (*domain.User, error) {
 	ret := _m.Called(ctx, request)
@@ -62,6 +151,29 @@ func (_m *UserService) GetUserByUUID(ctx context.Context, _a1 uuid.UUID) (*domai
 	return r0, r1
 }
 
+// List provides a mock function with given fields: ctx, filter
+func (_m *UserService) List(ctx context.Context, filter domain.UserFilter) ([]domain.User, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserFilter) []domain.User); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.UserFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Store provides a mock function with given fields: ctx, request
 func (_m *UserService) Store(ctx context.Context, request domain.StoreUserRequest) (*domain.User, error) {
 	ret := _m.Called(ctx, request)
@@ -0,0 +1,14 @@
+package domain
+
+import "context"
+
+// Message sent to a user through a notifier.
+type Notification struct {
+	Subject string
+	Body    string
+}
+
+// Delivers notifications to the users (by email, SMS...).
+type Notifier interface {
+	Notify(ctx context.Context, user *User, notification Notification) error
+}
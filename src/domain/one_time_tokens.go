@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Purposes of the one-time tokens
+const (
+	TokenPurposeEmailVerification string = "email-verification"
+	TokenPurposePasswordReset     string = "password-reset"
+	TokenPurposeInvitation        string = "invitation"
+	TokenPurposeMagicLink         string = "magic-link"
+)
+
+// Single use token sent to a user (email verification, password reset...).
+// Only the hash of the token is stored.
+type OneTimeToken struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"userId"`
+	Purpose   string    `json:"purpose"`
+	TokenHash string    `json:"-"`
+	Payload   string    `json:"-"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Organization of the user the token was issued to, where the user is looked up when it's used.
+	OrganizationID uuid.UUID `json:"-"`
+}
+
+type OneTimeTokenRepository interface {
+	Store(ctx context.Context, token OneTimeToken) (OneTimeToken, error)
+	Consume(ctx context.Context, purpose string, tokenHash string) (OneTimeToken, error)
+	GetByHash(ctx context.Context, purpose string, tokenHash string) (OneTimeToken, error)
+	DeleteByUser(ctx context.Context, userID uuid.UUID, purpose string) error
+	CountByUserSince(ctx context.Context, userID uuid.UUID, purpose string, since time.Time) (int, error)
+	GetByUser(ctx context.Context, userID uuid.UUID) ([]OneTimeToken, error)
+	// Gets the tokens for a purpose of the users of the organization.
+	GetByPurpose(ctx context.Context, purpose string) ([]OneTimeToken, error)
+}
+
+type OneTimeTokenService interface {
+	Issue(ctx context.Context, userID uuid.UUID, purpose string, payload string, ttl time.Duration) (string, error)
+	Consume(ctx context.Context, purpose string, token string) (OneTimeToken, error)
+	// Gets a token without using it up, for the callers deleting it along with what it's used for.
+	Peek(ctx context.Context, purpose string, token string) (OneTimeToken, error)
+	Revoke(ctx context.Context, userID uuid.UUID, purpose string) error
+	CountIssuedSince(ctx context.Context, userID uuid.UUID, purpose string, since time.Time) (int, error)
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Organization every user, role and refresh token belonged to before there were organizations.
+// Requests that don't name an organization act on it.
+var DefaultOrganizationID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// Slugs of the organizations, used to refer to them in URLs.
+var organizationSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}$`)
+
+// Tenant of the service: users, roles, groups and refresh tokens belong to a single organization.
+type Organization struct {
+	ID        uuid.UUID `json:"id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Returns if the organization can be stored.
+func (o Organization) IsValid() bool {
+	return organizationSlugPattern.MatchString(o.Slug) && len(o.Name) > 0 && len(o.Name) <= 255
+}
+
+type organizationContextKey struct{}
+
+// Returns a context scoped to an organization, the repositories only see its data.
+func WithOrganization(ctx context.Context, organizationID uuid.UUID) context.Context {
+	return context.WithValue(ctx, organizationContextKey{}, organizationID)
+}
+
+// Gets the organization a context is scoped to, the default one when it isn't.
+func OrganizationFromContext(ctx context.Context) uuid.UUID {
+	if organizationID, ok := ctx.Value(organizationContextKey{}).(uuid.UUID); ok && organizationID != uuid.Nil {
+		return organizationID
+	}
+	return DefaultOrganizationID
+}
+
+type OrganizationRepository interface {
+	// Stores a new organization. Slugs are unique.
+	Store(ctx context.Context, organization Organization) (Organization, error)
+	GetByUUID(ctx context.Context, id uuid.UUID) (Organization, error)
+	// Deletes an organization along with all of its data.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type OrganizationService interface {
+	// Creates an organization with the default roles and its first administrator.
+	Create(ctx context.Context, organization Organization, admin StoreUserRequest) (Organization, *User, error)
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Ceremonies a passkey session can belong to.
+const (
+	PasskeyCeremonyRegistration = "registration"
+	PasskeyCeremonyLogin        = "login"
+)
+
+// WebAuthn credential (passkey) registered by a user.
+type PasskeyCredential struct {
+	ID              uuid.UUID `json:"id"`
+	UserID          uuid.UUID `json:"userId"`
+	CredentialID    []byte    `json:"credentialId"`
+	PublicKey       []byte    `json:"-"`
+	AttestationType string    `json:"attestationType"`
+	AAGUID          []byte    `json:"aaguid"`
+	// Signature counter reported by the authenticator on its last use.
+	SignCount  uint32    `json:"-"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+}
+
+// State kept between the two steps of a WebAuthn ceremony.
+// Login sessions don't belong to any user, since the passkey identifies it.
+type PasskeySession struct {
+	ID       uuid.UUID     `json:"id"`
+	UserID   uuid.NullUUID `json:"userId"`
+	Ceremony string        `json:"ceremony"`
+	// JSON encoded session data of the WebAuthn library.
+	Data      string    `json:"-"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// Organization the ceremony began in, where the user is looked up at its end.
+	OrganizationID uuid.UUID `json:"-"`
+}
+
+// Returns if the session can't be used anymore.
+func (s PasskeySession) IsExpired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
+
+// Challenge issued when a ceremony begins.
+type PasskeyChallenge struct {
+	SessionID uuid.UUID `json:"sessionId"`
+	// JSON encoded options to pass to navigator.credentials.create/get.
+	Options []byte `json:"options"`
+}
+
+// Settings of the WebAuthn relying party.
+type PasskeySettings struct {
+	// Domain the passkeys are scoped to.
+	RPID          string
+	RPDisplayName string
+	// Origins the ceremonies are allowed to come from.
+	RPOrigins []string
+	// How long a ceremony can take between its two steps.
+	SessionTTL time.Duration
+}
+
+type PasskeyRepository interface {
+	StoreCredential(ctx context.Context, credential PasskeyCredential) (PasskeyCredential, error)
+	GetCredentialsByUser(ctx context.Context, userID uuid.UUID) ([]PasskeyCredential, error)
+	UpdateCredentialUsage(ctx context.Context, id uuid.UUID, signCount uint32) error
+	StoreSession(ctx context.Context, session PasskeySession) error
+	ConsumeSession(ctx context.Context, id uuid.UUID, ceremony string) (PasskeySession, error)
+}
+
+type PasskeyService interface {
+	BeginRegistration(ctx context.Context, user *User) (PasskeyChallenge, error)
+	FinishRegistration(ctx context.Context, user *User, sessionID uuid.UUID, response []byte) (PasskeyCredential, error)
+	BeginLogin(ctx context.Context) (PasskeyChallenge, error)
+	FinishLogin(ctx context.Context, sessionID uuid.UUID, response []byte) (*User, error)
+}
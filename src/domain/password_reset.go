@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Settings for the self-service password reset.
+type PasswordResetSettings struct {
+	// Link sent to the users, the token is added as the "token" query parameter.
+	ResetURL string
+	// How long the reset links are valid.
+	TokenTTL time.Duration
+	// Resets that can be requested for an account within the request window.
+	MaxRequestsPerWindow int
+	RequestWindow        time.Duration
+}
+
+type PasswordResetService interface {
+	RequestPasswordReset(ctx context.Context, usernameOrEmail string) error
+	ResetPassword(ctx context.Context, token string, newPassword string) error
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrRegistrationDisabled  = errors.New("registration is disabled")
+	ErrEmailDomainNotAllowed = errors.New("email domain not allowed")
+)
+
+// Settings for the public self-registration.
+type RegistrationSettings struct {
+	Enabled bool
+	// Role given to the registered users.
+	DefaultRoleSlug string
+	// When not empty, only emails of these domains can register (and an email is required).
+	AllowedEmailDomains []string
+}
+
+type RegisterUserRequest struct {
+	Username string
+	Email    string
+	Password string
+}
+
+type RegistrationService interface {
+	Register(ctx context.Context, request RegisterUserRequest) (*User, error)
+}
@@ -2,30 +2,64 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"regexp"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Slugs of the roles, as they appear in the access tokens.
+var roleSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// Returned when deleting a role some users or groups still hold.
+var ErrRoleInUse = errors.New("role in use")
+
 type Role struct {
 	ID        uuid.UUID `json:"id"`
 	RoleSlug  string    `json:"roleSlug"`
 	RoleLabel string    `json:"roleLabel"`
+	// Users with this role must use multi-factor authentication to log in.
+	RequiresMFA bool `json:"requiresMfa"`
+	// Days the passwords of the users with this role are valid for, 0 when they never expire.
+	PasswordMaxAgeDays int `json:"passwordMaxAgeDays"`
+	// Incremented on every change, so concurrent changes can be detected.
+	Version   int       `json:"version"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 	DeletedAt time.Time `json:"-"`
 }
 
+// Returns if the slug of the role is valid.
+func (r Role) HasValidSlug() bool {
+	return roleSlugPattern.MatchString(r.RoleSlug)
+}
+
+// Returns if the role is one of the default ones, which every organization relies on.
+func (r Role) IsBuiltIn() bool {
+	return r.RoleSlug == DEFAULT_ROLE_ADMIN.RoleSlug || r.RoleSlug == DEFAULT_ROLE_USER.RoleSlug
+}
+
 type RoleRepository interface {
 	Fetch(ctx context.Context) ([]Role, error)
+	// Stores a new role. Slugs and labels are unique among the roles that aren't deleted.
 	Store(ctx context.Context, role Role) (Role, error)
 	GetBySlug(ctx context.Context, slug string) (Role, error)
 	GetByUUID(ctx context.Context, uuid uuid.UUID) (Role, error)
+	// Updates the label and password policies of a role at its version.
+	Update(ctx context.Context, role Role) (Role, error)
+	// Soft deletes a role at a version, failing with ErrRoleInUse while any user
+	// (deleted ones included) or group holds it.
+	Delete(ctx context.Context, id uuid.UUID, version int, deletedAt time.Time) error
 }
 
 type RoleService interface {
+	GetAll(ctx context.Context) ([]Role, error)
 	Store(ctx context.Context, role Role) (Role, error)
 	GetBySlug(ctx context.Context, slug string) (Role, error)
+	Update(ctx context.Context, role Role) (Role, error)
+	// Deletes a role at a version. The default roles can't be deleted.
+	Delete(ctx context.Context, id uuid.UUID, version int) error
 }
 
 var (
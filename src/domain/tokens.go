@@ -13,6 +13,8 @@ type RefreshToken struct {
 	Id         uuid.UUID `json:"-"`
 	Token      uuid.UUID `json:"token"`
 	ValidUntil time.Time `json:"-"`
+	// Organization of the user the token was issued to.
+	OrganizationID uuid.UUID `json:"-"`
 }
 
 type TokenResponse struct {
@@ -23,12 +25,23 @@ type TokenResponse struct {
 
 type AccessTokenHandler interface {
 	ParseJWT(tokenString string) (*jwt.Token, error)
-	IsJWTokenValid(token *jwt.Token) bool
-	GetUserRoleFromToken(token *jwt.Token) (string, error)
+	// Checks the signature and expiration of a token, and that its username is still the one of its user.
+	IsJWTokenValid(ctx context.Context, token *jwt.Token) bool
+	// Gets the own role of the user followed by the ones it inherits from its groups.
+	GetUserRolesFromToken(token *jwt.Token) ([]string, error)
+	GetOrganizationIDFromToken(token *jwt.Token) (uuid.UUID, error)
 	GenerateTokens(ctx context.Context, user *User) (TokenResponse, error)
 	RefreshAllTokens(ctx context.Context, askedRefreshToken uuid.UUID) (TokenResponse, error)
 	GetUserIDFromToken(token *jwt.Token) (uuid.UUID, error)
-	DeleteRefreshToken(ctx context.Context, refreshToken string) bool
+	// Deletes a refresh token, returning the user it was issued to.
+	DeleteRefreshToken(ctx context.Context, refreshToken string) (*User, bool)
+	GenerateMFAChallenge(user *User) (string, error)
+	// Gets the user of a MFA challenge and the organization it belongs to.
+	GetUserIDFromMFAChallenge(tokenString string) (userID uuid.UUID, organizationID uuid.UUID, err error)
+	// Restricted tokens given on login to users that have to change their password,
+	// which can only be used for that.
+	GeneratePasswordChangeToken(user *User) (string, error)
+	GetUserIDFromPasswordChangeToken(tokenString string) (userID uuid.UUID, organizationID uuid.UUID, err error)
 }
 
 type RefreshTokenRepository interface {
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Version of the archives of the user backups, increased on incompatible changes.
+// Archives of older versions can still be restored.
+const UserBackupFormatVersion = 2
+
+// Kind of a record of a user backup archive, one JSON record per line.
+// The archive starts with the header, followed by the roles, the groups, the users,
+// their role assignments and their group memberships, and ends with the footer.
+type UserBackupRecordType string
+
+const (
+	UserBackupRecordHeader          UserBackupRecordType = "header"
+	UserBackupRecordRole            UserBackupRecordType = "role"
+	UserBackupRecordGroup           UserBackupRecordType = "group"
+	UserBackupRecordUser            UserBackupRecordType = "user"
+	UserBackupRecordRoleAssignment  UserBackupRecordType = "roleAssignment"
+	UserBackupRecordGroupMembership UserBackupRecordType = "groupMembership"
+	UserBackupRecordFooter          UserBackupRecordType = "footer"
+)
+
+// Line of a user backup archive, with the field of its type set.
+type UserBackupRecord struct {
+	Type            UserBackupRecordType       `json:"type"`
+	Header          *UserBackupHeader          `json:"header,omitempty"`
+	Role            *Role                      `json:"role,omitempty"`
+	Group           *UserBackupGroup           `json:"group,omitempty"`
+	User            *UserBackupUser            `json:"user,omitempty"`
+	RoleAssignment  *UserBackupRoleAssignment  `json:"roleAssignment,omitempty"`
+	GroupMembership *UserBackupGroupMembership `json:"groupMembership,omitempty"`
+	Footer          *UserBackupFooter          `json:"footer,omitempty"`
+}
+
+type UserBackupHeader struct {
+	FormatVersion int       `json:"formatVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+	// The password hashes of the users are in the archive.
+	WithPasswords bool `json:"withPasswords"`
+}
+
+// User as stored in a backup, without the role, which is on its assignment.
+type UserBackupUser struct {
+	ID            uuid.UUID  `json:"id"`
+	FirstName     string     `json:"firstName"`
+	LastName      string     `json:"lastName"`
+	Username      string     `json:"username"`
+	Email         string     `json:"email"`
+	EmailVerified bool       `json:"emailVerified"`
+	PasswordHash  string     `json:"passwordHash,omitempty"`
+	Status        UserStatus `json:"status"`
+	StatusReason  string     `json:"statusReason,omitempty"`
+	Attributes    Attributes `json:"attributes"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+	// Set on the deleted users that could still be restored when the archive was made.
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+// Group as stored in a backup, with the IDs of the roles of the archive it grants.
+type UserBackupGroup struct {
+	ID          uuid.UUID   `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	RoleIDs     []uuid.UUID `json:"roleIds"`
+}
+
+type UserBackupGroupMembership struct {
+	GroupID uuid.UUID `json:"groupId"`
+	UserID  uuid.UUID `json:"userId"`
+}
+
+// Role of a user. The slug allows restoring into environments where the roles have other IDs.
+type UserBackupRoleAssignment struct {
+	UserID   uuid.UUID `json:"userId"`
+	RoleID   uuid.UUID `json:"roleId"`
+	RoleSlug string    `json:"roleSlug"`
+}
+
+type UserBackupFooter struct {
+	// Records of the archive, without the footer.
+	Records int `json:"records"`
+	// "sha256:" followed by the hex SHA-256 of every line before the footer.
+	Checksum string `json:"checksum"`
+}
+
+// Outcome of a restore. Restores are idempotent, restoring an archive again skips everything.
+type UserRestoreReport struct {
+	RolesCreated int `json:"rolesCreated"`
+	// Roles that already existed, with the same slug.
+	RolesMatched  int `json:"rolesMatched"`
+	GroupsCreated int `json:"groupsCreated"`
+	// Groups that already existed, with the same name.
+	GroupsMatched int `json:"groupsMatched"`
+	UsersRestored int `json:"usersRestored"`
+	// Users that already existed, or conflict with existing ones.
+	UsersSkipped        int `json:"usersSkipped"`
+	MembershipsRestored int `json:"membershipsRestored"`
+}
+
+type UserBackupService interface {
+	// Writes the archive with every role, group and user, including the deleted users that can
+	// still be restored. Password hashes are left out unless asked for.
+	Export(ctx context.Context, w io.Writer, withPasswords bool) error
+	// Restores the roles, groups and users of an archive that aren't stored yet, with their memberships.
+	// Roles existing with another ID fail the restore, unless remapping the roles by slug.
+	Restore(ctx context.Context, r io.Reader, remapRoles bool) (UserRestoreReport, error)
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Settings for deleting users and purging them for good.
+type UserDeletionSettings struct {
+	// How long a deleted user can be restored, before being purged.
+	RetentionPeriod time.Duration
+	// How often the purger looks for users to purge.
+	PurgeInterval time.Duration
+	// Users purged at most in each transaction.
+	PurgeBatchSize int
+}
+
+type UserDeletionService interface {
+	// Deletes a user at the given version, failing with ErrVersionConflict when it changed since.
+	Delete(ctx context.Context, id uuid.UUID, version int) error
+	Restore(ctx context.Context, id uuid.UUID) (*User, error)
+	PurgeDeleted(ctx context.Context) (int64, error)
+	// Purges the deleted users on every interval, until the context is done.
+	RunPurger(ctx context.Context)
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+)
+
+// Formats the users can be imported from.
+type UserImportFormat string
+
+const (
+	// Comma separated values, with a header naming the columns.
+	UserImportFormatCSV UserImportFormat = "csv"
+	// One JSON object per line.
+	UserImportFormatJSONLines UserImportFormat = "jsonl"
+)
+
+// Outcome of a row of an import.
+type UserImportRowStatus string
+
+const (
+	UserImportRowCreated UserImportRowStatus = "created"
+	// The username is taken, by an existing user or an earlier row.
+	UserImportRowSkipped UserImportRowStatus = "skipped"
+	UserImportRowFailed  UserImportRowStatus = "failed"
+)
+
+// User read from a row of an import.
+type UserImportRecord struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	// Hash of the password in the system the user comes from, in place of the password.
+	// Legacy hashes are replaced with bcrypt ones on the first login of the user.
+	PasswordHash string `json:"passwordHash"`
+	// Slug of the role, the default user role when empty.
+	Role string `json:"role"`
+}
+
+// Report of a row of an import.
+type UserImportRow struct {
+	// Line of the row in the imported file.
+	Line     int                 `json:"line"`
+	Username string              `json:"username"`
+	Status   UserImportRowStatus `json:"status"`
+	Reason   string              `json:"reason,omitempty"`
+	UserID   uuid.UUID           `json:"userId"`
+}
+
+// Report of an import, with the outcome of every row.
+// On dry runs nothing is stored, and the created rows are the ones that would be.
+type UserImportReport struct {
+	DryRun  bool            `json:"dryRun"`
+	Created int             `json:"created"`
+	Skipped int             `json:"skipped"`
+	Failed  int             `json:"failed"`
+	Rows    []UserImportRow `json:"rows"`
+}
+
+type UserImportSettings struct {
+	// Users inserted by each statement.
+	BatchSize         int
+	MinPasswordLength int
+	// Accepts the weak salted SHA-1 hashes on the imported password hashes.
+	AllowSHA1Hashes bool
+}
+
+type UserImportService interface {
+	Import(ctx context.Context, r io.Reader, format UserImportFormat, dryRun bool) (UserImportReport, error)
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Settings for changing the usernames of the users.
+type UsernameSettings struct {
+	// How long an old username is kept from other users.
+	ReservationPeriod time.Duration
+}
+
+// Change of the username of a user.
+// The old username stays reserved for the user for a while, so nobody else can take it.
+type UsernameChange struct {
+	ID            uuid.UUID `json:"id"`
+	UserID        uuid.UUID `json:"userId"`
+	OldUsername   string    `json:"oldUsername"`
+	NewUsername   string    `json:"newUsername"`
+	ChangedAt     time.Time `json:"changedAt"`
+	ReservedUntil time.Time `json:"reservedUntil"`
+}
+
+type UsernameHistoryRepository interface {
+	Store(ctx context.Context, change UsernameChange) (UsernameChange, error)
+	// Gets the changes of the username of a user, newest first.
+	GetByUser(ctx context.Context, userID uuid.UUID) ([]UsernameChange, error)
+	// Returns if a username, regardless of its casing, was given up by a user other than
+	// the given one and is still reserved at a given time.
+	IsReserved(ctx context.Context, username string, exceptUserID uuid.UUID, at time.Time) (bool, error)
+	// Returns if a user changed from a username after a given time.
+	WasRenamedSince(ctx context.Context, userID uuid.UUID, username string, since time.Time) (bool, error)
+}
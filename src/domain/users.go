@@ -7,24 +7,115 @@ import (
 	"github.com/google/uuid"
 )
 
+// Lifecycle status of a user account.
+type UserStatus string
+
+const (
+	// Created, but not allowed to log in yet.
+	UserStatusPending UserStatus = "pending"
+	UserStatusActive  UserStatus = "active"
+	// Blocked by an administrator, with a reason.
+	UserStatusSuspended   UserStatus = "suspended"
+	UserStatusDeactivated UserStatus = "deactivated"
+)
+
+// Statuses each status can move to.
+var userStatusTransitions = map[UserStatus][]UserStatus{
+	UserStatusPending:     {UserStatusActive, UserStatusDeactivated},
+	UserStatusActive:      {UserStatusSuspended, UserStatusDeactivated},
+	UserStatusSuspended:   {UserStatusActive, UserStatusDeactivated},
+	UserStatusDeactivated: {UserStatusActive},
+}
+
+// Returns if the status is one of the known statuses.
+func (s UserStatus) IsValid() bool {
+	_, ok := userStatusTransitions[s]
+	return ok
+}
+
+// Returns if a user can move from this status to the next one.
+func (s UserStatus) CanTransitionTo(next UserStatus) bool {
+	for _, allowed := range userStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 type User struct {
 	ID             uuid.UUID     `json:"id"`
 	FirstName      string        `json:"firstName"`
 	LastName       string        `json:"lastName"`
 	Username       string        `json:"username"`
+	Email          string        `json:"email"`
+	EmailVerified  bool          `json:"emailVerified"`
 	Password       string        `json:"-"`
 	RoleId         uuid.UUID     `json:"-"`
+	OrganizationID uuid.UUID     `json:"organizationId"`
 	Role           *Role         `json:"role,omitempty"`
 	RefreshTokenId uuid.NullUUID `json:"-"`
-	CreatedAt      time.Time     `json:"createdAt"`
-	UpdatedAt      time.Time     `json:"updatedAt"`
-	DeletedAt      time.Time     `json:"-"`
+	Status         UserStatus    `json:"status"`
+	StatusReason   string        `json:"statusReason,omitempty"`
+	Attributes     Attributes    `json:"attributes"`
+	LastLoginAt    time.Time     `json:"lastLoginAt"`
+	// Users that must change their password can't do anything else until they do.
+	MustChangePassword bool      `json:"mustChangePassword"`
+	PasswordChangedAt  time.Time `json:"passwordChangedAt"`
+	// Authentication backend that provisioned the user and keeps it in sync, like ldap.
+	// Empty for the users of the service itself.
+	Backend string `json:"backend,omitempty"`
+	// Incremented on every change, so concurrent changes can be detected.
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	DeletedAt time.Time `json:"-"`
+}
+
+// Returns if the user is allowed to log in.
+func (u User) IsActive() bool {
+	return u.Status == UserStatusActive
+}
+
+// Returns if the user logs in with a password kept by the service. Users of a directory
+// log in with the password of the directory, and the ones without a password with an
+// identity provider, a passkey or a magic link.
+func (u User) HasLocalPassword() bool {
+	return len(u.Backend) == 0 && len(u.Password) > 0
+}
+
+// Returns if the user has to change its password before logging in, either because
+// it was asked to or because the password is older than what its role allows.
+// Users without a local password have nothing to change.
+func (u User) PasswordChangeRequired(now time.Time) bool {
+	if !u.HasLocalPassword() {
+		return false
+	}
+	if u.MustChangePassword {
+		return true
+	}
+	if u.Role == nil || u.Role.PasswordMaxAgeDays <= 0 {
+		return false
+	}
+	return now.After(u.PasswordChangedAt.AddDate(0, 0, u.Role.PasswordMaxAgeDays))
 }
 
 type StoreUserRequest struct {
 	Username string
+	Email    string
+	// Users stored without a password can't log in with one.
 	Password string
 	RoleSlug string
+	// Active when not set.
+	Status UserStatus
+	// The user has to change the password on its first login.
+	MustChangePassword bool
+}
+
+// Details a user gives about itself.
+type UserProfile struct {
+	FirstName string
+	LastName  string
 }
 
 type GetUserRequest struct {
@@ -32,16 +123,72 @@ type GetUserRequest struct {
 	Password string
 }
 
+// Users whose passwords are still hashed with the scheme of the system they were
+// imported from, which are rehashed on their next login.
+type LegacyPasswordReport struct {
+	Total int `json:"total"`
+	// Users by scheme, with every known scheme.
+	Schemes map[string]int `json:"schemes"`
+}
+
 type UserRepository interface {
 	Store(ctx context.Context, user User) (*User, error)
+	// Stores the users in batches inside a transaction, skipping the ones that conflict
+	// with existing users. Users with a DeletedAt are stored deleted.
+	// Returns the IDs of the stored users.
+	StoreMany(ctx context.Context, users []User, batchSize int) ([]uuid.UUID, error)
 	GetByUUID(ctx context.Context, uuid uuid.UUID) (*User, error)
 	GetByUsername(ctx context.Context, username string) (*User, error)
+	GetByEmail(ctx context.Context, email string) (*User, error)
+	// Returns if a username and an email are taken by any user, deleted ones included.
+	FindConflicts(ctx context.Context, username string, email string) (usernameTaken bool, emailTaken bool, err error)
 	SaveRefreshToken(ctx context.Context, user *User, token RefreshToken) error
 	GetByRefreshToken(ctx context.Context, id uuid.UUID) (*User, error)
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) error
+	UpdatePassword(ctx context.Context, id uuid.UUID, password string) error
+	// Sets a password chosen by the user, which no longer has to change it.
+	ChangePassword(ctx context.Context, id uuid.UUID, password string) error
+	// Updates that take a version only apply to the user at that version, failing with ErrVersionConflict otherwise.
+	UpdateStatus(ctx context.Context, id uuid.UUID, version int, from UserStatus, to UserStatus, reason string) error
+	Delete(ctx context.Context, id uuid.UUID, version int, deletedAt time.Time) error
+	Restore(ctx context.Context, id uuid.UUID, deletedAfter time.Time) error
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) (int64, error)
+	UpdateAttributes(ctx context.Context, id uuid.UUID, version int, attributes Attributes) error
+	UpdateLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error
+	// Renames a user to the new username of a change, storing the change in the username history along with it.
+	UpdateUsername(ctx context.Context, id uuid.UUID, version int, change UsernameChange) error
+	// Overwrites the email, profile and role of a user with the ones of the directory it logs in with.
+	// The email is taken as verified.
+	SyncProfile(ctx context.Context, id uuid.UUID, version int, email string, profile UserProfile, roleID uuid.UUID) error
+	// Activates a pending user with its password and profile, marking its email as verified.
+	// Deletes the one-time token it was invited with along with it, failing with ErrInvalidToken when it's gone.
+	Activate(ctx context.Context, id uuid.UUID, version int, password string, profile UserProfile, invitationID uuid.UUID) error
+	List(ctx context.Context, filter UserFilter) ([]User, error)
+	// Lists the users deleted after a time in a paginated manner, with their DeletedAt set.
+	ListDeleted(ctx context.Context, deletedAfter time.Time, limit int, offset int) ([]User, error)
+	// Counts the users whose password hashes are in each of the schemes.
+	CountPasswordSchemes(ctx context.Context, schemes []string) (map[string]int, error)
+}
+
+// Verifies the credentials of the logins against where the users keep their passwords.
+type Authenticator interface {
+	// Gets the local user with its role the credentials belong to, whatever its status.
+	// Fails with ErrNotFound when the user isn't known here, so the next authenticator is tried.
+	Authenticate(ctx context.Context, username string, password string) (*User, error)
 }
 
 type UserService interface {
 	Store(ctx context.Context, request StoreUserRequest) (*User, error)
+	// Gets an active user by its credentials, checked by the authenticators in order.
 	GetUserByLogin(ctx context.Context, request GetUserRequest) (*User, error)
 	GetUserByUUID(ctx context.Context, uuid uuid.UUID) (*User, error)
+	// Changes of a user take the version the caller has of it, failing with ErrVersionConflict
+	// when the user changed since.
+	ChangeStatus(ctx context.Context, id uuid.UUID, version int, status UserStatus, reason string) (*User, error)
+	// Renames a user, keeping the old username reserved for it.
+	ChangeUsername(ctx context.Context, id uuid.UUID, version int, username string) (*User, error)
+	// Sets a new password for a user, which can't be the current one.
+	ChangePassword(ctx context.Context, id uuid.UUID, password string) (*User, error)
+	List(ctx context.Context, filter UserFilter) ([]User, error)
+	GetLegacyPasswordReport(ctx context.Context) (LegacyPasswordReport, error)
 }
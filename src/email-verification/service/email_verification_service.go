@@ -0,0 +1,75 @@
+package service
+
+import (
+	"io/ioutil"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type DefaultEmailVerificationService struct {
+	Logger              *log.Logger
+	UserRepo            domain.UserRepository
+	UserService         domain.UserService
+	OneTimeTokenService domain.OneTimeTokenService
+	Mailer              domain.Mailer
+	ContextTimeout      time.Duration
+	Settings            domain.EmailVerificationSettings
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	userRepo domain.UserRepository,
+	userService domain.UserService,
+	oneTimeTokenService domain.OneTimeTokenService,
+	mailer domain.Mailer,
+	contextTimeout time.Duration,
+	settings domain.EmailVerificationSettings,
+) domain.EmailVerificationService {
+	return DefaultEmailVerificationService{
+		logger,
+		userRepo,
+		userService,
+		oneTimeTokenService,
+		mailer,
+		contextTimeout,
+		settings,
+	}
+}
+
+// Instantiation for tests
+func newService(
+	userRepo domain.UserRepository,
+	userService domain.UserService,
+	oneTimeTokenService domain.OneTimeTokenService,
+	mailer domain.Mailer,
+) DefaultEmailVerificationService {
+	return DefaultEmailVerificationService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		userRepo,
+		userService,
+		oneTimeTokenService,
+		mailer,
+		time.Duration(5 * time.Second),
+		domain.EmailVerificationSettings{
+			VerificationURL: "https://example.com/verify-email?source=email",
+			TokenTTL:        24 * time.Hour,
+		},
+	}
+}
+
+// Builds the verification link with the token.
+func (s DefaultEmailVerificationService) verificationLink(token string) (string, error) {
+	link, err := url.Parse(s.Settings.VerificationURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String(), nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Sends an email with a verification link to a user.
+// Previous links of the user stop working.
+func (s DefaultEmailVerificationService) SendVerificationEmail(ctx context.Context, user *domain.User) error {
+	if user == nil || len(user.Email) == 0 {
+		return domain.ErrBadParamInput
+	}
+	if user.EmailVerified {
+		return domain.ErrNotAllowed
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	if err := s.OneTimeTokenService.Revoke(ctx, user.ID, domain.TokenPurposeEmailVerification); err != nil {
+		return err
+	}
+
+	// The email is kept with the token, so changing it invalidates the link.
+	token, err := s.OneTimeTokenService.Issue(ctx, user.ID, domain.TokenPurposeEmailVerification, user.Email, s.Settings.TokenTTL)
+	if err != nil {
+		return err
+	}
+
+	link, err := s.verificationLink(token)
+	if err != nil {
+		return err
+	}
+
+	hours := int(math.Ceil(s.Settings.TokenTTL.Hours()))
+	err = s.Mailer.Send(ctx, domain.Email{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nPlease confirm your email address by opening the following link:\n%s\n\nThe link expires in %d hours. If you didn't ask for it, you can ignore this email.\n",
+			user.Username, link, hours,
+		),
+	})
+	if err != nil {
+		s.Logger.Printf("error sending verification email to user {%s}: %v\n", user.ID.String(), err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSendVerificationEmail_InvalidInput(t *testing.T) {
+	s := newService(nil, nil, nil, nil)
+
+	err := s.SendVerificationEmail(context.TODO(), nil)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+
+	err = s.SendVerificationEmail(context.TODO(), &domain.User{ID: uuid.New()})
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestSendVerificationEmail_AlreadyVerified(t *testing.T) {
+	err := newService(nil, nil, nil, nil).SendVerificationEmail(context.TODO(), &domain.User{
+		ID:            uuid.New(),
+		Email:         "alice@example.com",
+		EmailVerified: true,
+	})
+	assert.Equal(t, domain.ErrNotAllowed, err)
+}
+
+func TestSendVerificationEmail_ErrorRevokingTokens(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "alice@example.com"}
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Revoke", mock.Anything, user.ID, domain.TokenPurposeEmailVerification).
+		Once().Return(errors.New("boom"))
+
+	err := newService(nil, nil, tokenService, nil).SendVerificationEmail(context.TODO(), user)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	tokenService.AssertExpectations(t)
+}
+
+func TestSendVerificationEmail_ErrorIssuingToken(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "alice@example.com"}
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Revoke", mock.Anything, user.ID, domain.TokenPurposeEmailVerification).
+		Once().Return(nil)
+	tokenService.On("Issue", mock.Anything, user.ID, domain.TokenPurposeEmailVerification, "alice@example.com", 24*time.Hour).
+		Once().Return("", errors.New("boom"))
+
+	err := newService(nil, nil, tokenService, nil).SendVerificationEmail(context.TODO(), user)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	tokenService.AssertExpectations(t)
+}
+
+func TestSendVerificationEmail_ErrorSendingEmail(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "alice@example.com"}
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Revoke", mock.Anything, user.ID, domain.TokenPurposeEmailVerification).
+		Once().Return(nil)
+	tokenService.On("Issue", mock.Anything, user.ID, domain.TokenPurposeEmailVerification, "alice@example.com", 24*time.Hour).
+		Once().Return("the-token", nil)
+
+	mailer := new(mocks.Mailer)
+	mailer.On("Send", mock.Anything, mock.AnythingOfType("domain.Email")).
+		Once().Return(errors.New("boom"))
+
+	err := newService(nil, nil, tokenService, mailer).SendVerificationEmail(context.TODO(), user)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	tokenService.AssertExpectations(t)
+	mailer.AssertExpectations(t)
+}
+
+func TestSendVerificationEmail_Success(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com"}
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Revoke", mock.Anything, user.ID, domain.TokenPurposeEmailVerification).
+		Once().Return(nil)
+	tokenService.On("Issue", mock.Anything, user.ID, domain.TokenPurposeEmailVerification, "alice@example.com", 24*time.Hour).
+		Once().Return("the-token", nil)
+
+	mailer := new(mocks.Mailer)
+	mailer.On("Send", mock.Anything, mock.MatchedBy(func(email domain.Email) bool {
+		return email.To == "alice@example.com" &&
+			strings.Contains(email.Body, "Hello alice") &&
+			strings.Contains(email.Body, "https://example.com/verify-email?source=email&token=the-token") &&
+			strings.Contains(email.Body, "expires in 24 hours")
+	})).Once().Return(nil)
+
+	err := newService(nil, nil, tokenService, mailer).SendVerificationEmail(context.TODO(), user)
+	assert.Nil(t, err)
+	tokenService.AssertExpectations(t)
+	mailer.AssertExpectations(t)
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Verifies the email of the user a token was sent to.
+// Fails if the user changed its email since.
+func (s DefaultEmailVerificationService) VerifyEmail(ctx context.Context, token string) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	verification, err := s.OneTimeTokenService.Consume(ctx, domain.TokenPurposeEmailVerification, token)
+	if err != nil {
+		return nil, err
+	}
+	// The user is looked up in the organization the token was issued in.
+	ctx = domain.WithOrganization(ctx, verification.OrganizationID)
+
+	user, err := s.UserService.GetUserByUUID(ctx, verification.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(user.Email, verification.Payload) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	err = s.UserRepo.MarkEmailVerified(ctx, user.ID, verification.Payload)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user.EmailVerified = true
+	return user, nil
+}
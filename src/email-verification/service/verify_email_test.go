@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestVerifyEmail_InvalidToken(t *testing.T) {
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposeEmailVerification, "the-token").
+		Once().Return(domain.OneTimeToken{}, domain.ErrInvalidToken)
+
+	user, err := newService(nil, nil, tokenService, nil).VerifyEmail(context.TODO(), "the-token")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	tokenService.AssertExpectations(t)
+}
+
+func TestVerifyEmail_ErrorGettingUser(t *testing.T) {
+	userID := uuid.New()
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposeEmailVerification, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID, Payload: "alice@example.com"}, nil)
+
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.Anything, userID).
+		Once().Return(nil, errors.New("boom"))
+
+	user, err := newService(nil, userService, tokenService, nil).VerifyEmail(context.TODO(), "the-token")
+	assert.Nil(t, user)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	tokenService.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
+
+func TestVerifyEmail_ScopedToOrganizationOfToken(t *testing.T) {
+	userID, organizationID := uuid.New(), uuid.New()
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposeEmailVerification, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID, Payload: "alice@example.com", OrganizationID: organizationID}, nil)
+
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.MatchedBy(func(ctx context.Context) bool {
+		return domain.OrganizationFromContext(ctx) == organizationID
+	}), userID).Once().Return(nil, sql.ErrNoRows)
+
+	user, err := newService(nil, userService, tokenService, nil).VerifyEmail(context.TODO(), "the-token")
+	assert.Nil(t, user)
+	assert.Equal(t, sql.ErrNoRows, err)
+	userService.AssertExpectations(t)
+}
+
+func TestVerifyEmail_EmailChanged(t *testing.T) {
+	userID := uuid.New()
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposeEmailVerification, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID, Payload: "alice@example.com"}, nil)
+
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.Anything, userID).
+		Once().Return(&domain.User{ID: userID, Email: "alice@other.com"}, nil)
+
+	user, err := newService(nil, userService, tokenService, nil).VerifyEmail(context.TODO(), "the-token")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	tokenService.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
+
+func TestVerifyEmail_EmailChangedConcurrently(t *testing.T) {
+	userID := uuid.New()
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposeEmailVerification, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID, Payload: "alice@example.com"}, nil)
+
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.Anything, userID).
+		Once().Return(&domain.User{ID: userID, Email: "alice@example.com"}, nil)
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("MarkEmailVerified", mock.Anything, userID, "alice@example.com").
+		Once().Return(domain.ErrNotFound)
+
+	user, err := newService(userRepo, userService, tokenService, nil).VerifyEmail(context.TODO(), "the-token")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	userRepo.AssertExpectations(t)
+}
+
+func TestVerifyEmail_ErrorMarkingAsVerified(t *testing.T) {
+	userID := uuid.New()
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposeEmailVerification, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID, Payload: "alice@example.com"}, nil)
+
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.Anything, userID).
+		Once().Return(&domain.User{ID: userID, Email: "alice@example.com"}, nil)
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("MarkEmailVerified", mock.Anything, userID, "alice@example.com").
+		Once().Return(errors.New("boom"))
+
+	user, err := newService(userRepo, userService, tokenService, nil).VerifyEmail(context.TODO(), "the-token")
+	assert.Nil(t, user)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	userRepo.AssertExpectations(t)
+}
+
+func TestVerifyEmail_Success(t *testing.T) {
+	userID := uuid.New()
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposeEmailVerification, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID, Payload: "alice@example.com"}, nil)
+
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.Anything, userID).
+		Once().Return(&domain.User{ID: userID, Email: "Alice@Example.com"}, nil)
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("MarkEmailVerified", mock.Anything, userID, "alice@example.com").
+		Once().Return(nil)
+
+	user, err := newService(userRepo, userService, tokenService, nil).VerifyEmail(context.TODO(), "the-token")
+	assert.Nil(t, err)
+	assert.Equal(t, userID, user.ID)
+	assert.True(t, user.EmailVerified)
+	tokenService.AssertExpectations(t)
+	userService.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
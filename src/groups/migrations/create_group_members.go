@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Creates the table of the users that are members of the groups
+func CreateGroupMembersTable(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS group_members(
+			group_id uuid NOT NULL REFERENCES groups(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (group_id, user_id)
+		);
+		CREATE INDEX IF NOT EXISTS group_members_user_id_idx ON group_members (user_id);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewCreateGroupMembersTableMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "create-group-members-table",
+		Up:   CreateGroupMembersTable,
+	}
+}
@@ -0,0 +1,89 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateGroupMembersTable_FailExec(t *testing.T) {
+	migration := NewCreateGroupMembersTableMigration()
+	assert.Equal(t, migration.Name, "create-group-members-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS group_members(
+			group_id uuid NOT NULL REFERENCES groups(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (group_id, user_id)
+		);
+		CREATE INDEX IF NOT EXISTS group_members_user_id_idx ON group_members (user_id);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestCreateGroupMembersTable_TimeoutReached(t *testing.T) {
+	migration := NewCreateGroupMembersTableMigration()
+	assert.Equal(t, migration.Name, "create-group-members-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS group_members(
+			group_id uuid NOT NULL REFERENCES groups(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (group_id, user_id)
+		);
+		CREATE INDEX IF NOT EXISTS group_members_user_id_idx ON group_members (user_id);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestCreateGroupMembersTable_Success(t *testing.T) {
+	migration := NewCreateGroupMembersTableMigration()
+	assert.Equal(t, migration.Name, "create-group-members-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS group_members(
+			group_id uuid NOT NULL REFERENCES groups(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (group_id, user_id)
+		);
+		CREATE INDEX IF NOT EXISTS group_members_user_id_idx ON group_members (user_id);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
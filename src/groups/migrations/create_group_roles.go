@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Creates the table of the roles assigned to the groups, which their members inherit
+func CreateGroupRolesTable(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS group_roles(
+			group_id uuid NOT NULL REFERENCES groups(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			role_id uuid NOT NULL REFERENCES roles(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (group_id, role_id)
+		);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewCreateGroupRolesTableMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "create-group-roles-table",
+		Up:   CreateGroupRolesTable,
+	}
+}
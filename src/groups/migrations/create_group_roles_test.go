@@ -0,0 +1,86 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateGroupRolesTable_FailExec(t *testing.T) {
+	migration := NewCreateGroupRolesTableMigration()
+	assert.Equal(t, migration.Name, "create-group-roles-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS group_roles(
+			group_id uuid NOT NULL REFERENCES groups(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			role_id uuid NOT NULL REFERENCES roles(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (group_id, role_id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestCreateGroupRolesTable_TimeoutReached(t *testing.T) {
+	migration := NewCreateGroupRolesTableMigration()
+	assert.Equal(t, migration.Name, "create-group-roles-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS group_roles(
+			group_id uuid NOT NULL REFERENCES groups(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			role_id uuid NOT NULL REFERENCES roles(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (group_id, role_id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestCreateGroupRolesTable_Success(t *testing.T) {
+	migration := NewCreateGroupRolesTableMigration()
+	assert.Equal(t, migration.Name, "create-group-roles-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS group_roles(
+			group_id uuid NOT NULL REFERENCES groups(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			role_id uuid NOT NULL REFERENCES roles(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (group_id, role_id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
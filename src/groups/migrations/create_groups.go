@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Creates the table of the groups of users
+func CreateGroupsTable(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS groups(
+			id uuid NOT NULL,
+			name varchar(255) NOT NULL,
+			normalized_name varchar(255) NOT NULL UNIQUE,
+			description text NOT NULL DEFAULT '',
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			updated_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewCreateGroupsTableMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "create-groups-table",
+		Up:   CreateGroupsTable,
+	}
+}
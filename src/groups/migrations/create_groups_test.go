@@ -0,0 +1,95 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateGroupsTable_FailExec(t *testing.T) {
+	migration := NewCreateGroupsTableMigration()
+	assert.Equal(t, migration.Name, "create-groups-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS groups(
+			id uuid NOT NULL,
+			name varchar(255) NOT NULL,
+			normalized_name varchar(255) NOT NULL UNIQUE,
+			description text NOT NULL DEFAULT '',
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			updated_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestCreateGroupsTable_TimeoutReached(t *testing.T) {
+	migration := NewCreateGroupsTableMigration()
+	assert.Equal(t, migration.Name, "create-groups-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS groups(
+			id uuid NOT NULL,
+			name varchar(255) NOT NULL,
+			normalized_name varchar(255) NOT NULL UNIQUE,
+			description text NOT NULL DEFAULT '',
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			updated_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestCreateGroupsTable_Success(t *testing.T) {
+	migration := NewCreateGroupsTableMigration()
+	assert.Equal(t, migration.Name, "create-groups-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS groups(
+			id uuid NOT NULL,
+			name varchar(255) NOT NULL,
+			normalized_name varchar(255) NOT NULL UNIQUE,
+			description text NOT NULL DEFAULT '',
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			updated_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Adds a user to a group, doing nothing if it already is a member.
+// domain.ErrNotFound is returned when the group or the user don't exist.
+func (r PostgresRepository) AddMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error {
+	query := `
+		INSERT INTO group_members (group_id, user_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, groupID, userID, time.Now())
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolationCode {
+		return domain.ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const addMemberQuery = `
+		INSERT INTO group_members (group_id, user_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`
+
+func TestAddMember_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(addMemberQuery)).WillReturnError(errors.New("boom"))
+
+	err = New(db).AddMember(context.TODO(), uuid.New(), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestAddMember_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID, userID := uuid.New(), uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(addMemberQuery)).
+		ExpectExec().
+		WithArgs(groupID, userID, anyTime{}).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = New(db).AddMember(ctx, groupID, userID)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestAddMember_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID, userID := uuid.New(), uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(addMemberQuery)).
+		ExpectExec().
+		WithArgs(groupID, userID, anyTime{}).
+		WillReturnError(&pq.Error{Code: "23503"})
+
+	err = New(db).AddMember(context.TODO(), groupID, userID)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestAddMember_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID, userID := uuid.New(), uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(addMemberQuery)).
+		ExpectExec().
+		WithArgs(groupID, userID, anyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = New(db).AddMember(context.TODO(), groupID, userID)
+	assert.Nil(t, err)
+}
+
+func TestAddMember_AlreadyMember(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID, userID := uuid.New(), uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(addMemberQuery)).
+		ExpectExec().
+		WithArgs(groupID, userID, anyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = New(db).AddMember(context.TODO(), groupID, userID)
+	assert.Nil(t, err)
+}
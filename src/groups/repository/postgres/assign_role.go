@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Assigns a role to a group, doing nothing if it already is assigned.
+// domain.ErrNotFound is returned when the group or the role don't exist.
+func (r PostgresRepository) AssignRole(ctx context.Context, groupID uuid.UUID, roleID uuid.UUID) error {
+	query := `
+		INSERT INTO group_roles (group_id, role_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, groupID, roleID, time.Now())
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolationCode {
+		return domain.ErrNotFound
+	}
+	return err
+}
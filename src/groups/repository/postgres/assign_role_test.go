@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const assignRoleQuery = `
+		INSERT INTO group_roles (group_id, role_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`
+
+func TestAssignRole_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(assignRoleQuery)).WillReturnError(errors.New("boom"))
+
+	err = New(db).AssignRole(context.TODO(), uuid.New(), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestAssignRole_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID, roleID := uuid.New(), uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(assignRoleQuery)).
+		ExpectExec().
+		WithArgs(groupID, roleID, anyTime{}).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = New(db).AssignRole(ctx, groupID, roleID)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestAssignRole_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID, roleID := uuid.New(), uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(assignRoleQuery)).
+		ExpectExec().
+		WithArgs(groupID, roleID, anyTime{}).
+		WillReturnError(&pq.Error{Code: "23503"})
+
+	err = New(db).AssignRole(context.TODO(), groupID, roleID)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestAssignRole_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID, roleID := uuid.New(), uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(assignRoleQuery)).
+		ExpectExec().
+		WithArgs(groupID, roleID, anyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = New(db).AssignRole(context.TODO(), groupID, roleID)
+	assert.Nil(t, err)
+}
+
+func TestAssignRole_AlreadyAssigned(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID, roleID := uuid.New(), uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(assignRoleQuery)).
+		ExpectExec().
+		WithArgs(groupID, roleID, anyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = New(db).AssignRole(context.TODO(), groupID, roleID)
+	assert.Nil(t, err)
+}
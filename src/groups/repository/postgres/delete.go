@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Deletes a group, along with its memberships and role assignments.
+func (r PostgresRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	stmt, err := r.Db.PrepareContext(ctx, `DELETE FROM groups WHERE id = $1 AND organization_id = $2`)
+	if err != nil {
+		return err
+	}
+
+	result, err := stmt.ExecContext(ctx, id, domain.OrganizationFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	return requireAffectedRows(result)
+}
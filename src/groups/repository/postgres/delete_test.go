@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const deleteQuery = `DELETE FROM groups WHERE id = $1 AND organization_id = $2`
+
+func TestDelete_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(deleteQuery)).WillReturnError(errors.New("boom"))
+
+	err = New(db).Delete(context.TODO(), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestDelete_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(deleteQuery)).
+		ExpectExec().
+		WithArgs(id, domain.DefaultOrganizationID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = New(db).Delete(ctx, id)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(deleteQuery)).
+		ExpectExec().
+		WithArgs(id, domain.DefaultOrganizationID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = New(db).Delete(context.TODO(), id)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestDelete_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(deleteQuery)).
+		ExpectExec().
+		WithArgs(id, domain.DefaultOrganizationID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = New(db).Delete(context.TODO(), id)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets all the groups, by name, without their roles.
+func (r PostgresRepository) Fetch(ctx context.Context) ([]domain.Group, error) {
+	result := make([]domain.Group, 0)
+
+	query := `
+		SELECT id, name, description, created_at, updated_at
+		FROM groups
+		WHERE organization_id = $1
+		ORDER BY normalized_name
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return result, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, domain.OrganizationFromContext(ctx))
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		group, err := r.scanGroupRow(rows)
+		if err != nil {
+			return make([]domain.Group, 0), err
+		}
+		result = append(result, group)
+	}
+	return result, rows.Err()
+}
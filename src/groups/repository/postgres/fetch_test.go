@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const fetchQuery = `
+		SELECT id, name, description, created_at, updated_at
+		FROM groups
+		WHERE organization_id = $1
+		ORDER BY normalized_name
+	`
+
+func TestFetch_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(fetchQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).Fetch(context.TODO())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestFetch_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(fetchQuery)).
+		ExpectQuery().
+		WithArgs(domain.DefaultOrganizationID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).Fetch(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestFetch_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	firstID, secondID := uuid.New(), uuid.New()
+	createdAt := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "name", "description", "created_at", "updated_at"}).
+		AddRow(firstID, "Editors", "", createdAt, createdAt).
+		AddRow(secondID, "Support", "First line support", createdAt, createdAt)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(fetchQuery)).
+		ExpectQuery().
+		WithArgs(domain.DefaultOrganizationID).
+		WillReturnRows(rows)
+
+	res, err := New(db).Fetch(context.TODO())
+	assert.Nil(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, firstID, res[0].ID)
+	assert.Equal(t, "Support", res[1].Name)
+	assert.Equal(t, "First line support", res[1].Description)
+	assert.Equal(t, createdAt, res[1].CreatedAt)
+}
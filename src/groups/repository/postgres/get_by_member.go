@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets the groups a user is a member of, by name, without their roles.
+func (r PostgresRepository) GetByMember(ctx context.Context, userID uuid.UUID) ([]domain.Group, error) {
+	result := make([]domain.Group, 0)
+
+	query := `
+		SELECT g.id, g.name, g.description, g.created_at, g.updated_at
+		FROM groups g
+		JOIN group_members gm ON gm.group_id = g.id
+		WHERE gm.user_id = $1 AND g.organization_id = $2
+		ORDER BY g.normalized_name
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return result, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, userID, domain.OrganizationFromContext(ctx))
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		group, err := r.scanGroupRow(rows)
+		if err != nil {
+			return make([]domain.Group, 0), err
+		}
+		result = append(result, group)
+	}
+	return result, rows.Err()
+}
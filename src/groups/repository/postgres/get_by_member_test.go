@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const getByMemberQuery = `
+		SELECT g.id, g.name, g.description, g.created_at, g.updated_at
+		FROM groups g
+		JOIN group_members gm ON gm.group_id = g.id
+		WHERE gm.user_id = $1 AND g.organization_id = $2
+		ORDER BY g.normalized_name
+	`
+
+func TestGetByMember_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getByMemberQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).GetByMember(context.TODO(), uuid.New())
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestGetByMember_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID, groupID := uuid.New(), uuid.New()
+	createdAt := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "name", "description", "created_at", "updated_at"}).
+		AddRow(groupID, "Support", "First line support", createdAt, createdAt)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getByMemberQuery)).
+		ExpectQuery().
+		WithArgs(userID, domain.DefaultOrganizationID).
+		WillReturnRows(rows)
+
+	res, err := New(db).GetByMember(context.TODO(), userID)
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.Group{{ID: groupID, Name: "Support", Description: "First line support", CreatedAt: createdAt, UpdatedAt: createdAt}}, res)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
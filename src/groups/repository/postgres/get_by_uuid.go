@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets a group, without its roles.
+func (r PostgresRepository) GetByUUID(ctx context.Context, id uuid.UUID) (domain.Group, error) {
+	query := `
+		SELECT id, name, description, created_at, updated_at
+		FROM groups
+		WHERE id = $1 AND organization_id = $2
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.Group{}, err
+	}
+
+	result, err := r.scanGroupRow(stmt.QueryRowContext(ctx, id, domain.OrganizationFromContext(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Group{}, domain.ErrNotFound
+	}
+	return result, err
+}
@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Gets the IDs of the members of a group, by the time they joined.
+func (r PostgresRepository) GetMembers(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error) {
+	result := make([]uuid.UUID, 0)
+
+	query := `
+		SELECT user_id
+		FROM group_members
+		WHERE group_id = $1
+		ORDER BY created_at, user_id
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return result, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, groupID)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return make([]uuid.UUID, 0), err
+		}
+		result = append(result, userID)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const getMembersQuery = `
+		SELECT user_id
+		FROM group_members
+		WHERE group_id = $1
+		ORDER BY created_at, user_id
+	`
+
+func TestGetMembers_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getMembersQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).GetMembers(context.TODO(), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestGetMembers_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(getMembersQuery)).
+		ExpectQuery().
+		WithArgs(groupID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).GetMembers(ctx, groupID)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestGetMembers_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID, firstID, secondID := uuid.New(), uuid.New(), uuid.New()
+	rows := sqlmock.NewRows([]string{"user_id"}).
+		AddRow(firstID).
+		AddRow(secondID)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getMembersQuery)).
+		ExpectQuery().
+		WithArgs(groupID).
+		WillReturnRows(rows)
+
+	res, err := New(db).GetMembers(context.TODO(), groupID)
+	assert.Nil(t, err)
+	assert.Equal(t, []uuid.UUID{firstID, secondID}, res)
+}
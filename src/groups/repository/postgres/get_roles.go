@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets the roles assigned to a group, by slug.
+func (r PostgresRepository) GetRoles(ctx context.Context, groupID uuid.UUID) ([]domain.Role, error) {
+	query := `
+		SELECT r.id, r.role_slug, r.role_label, r.requires_mfa, r.version, r.created_at, r.updated_at
+		FROM group_roles gr
+		JOIN roles r ON r.id = gr.role_id
+		WHERE gr.group_id = $1 AND r.deleted_at IS NULL
+		ORDER BY r.role_slug
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return make([]domain.Role, 0), err
+	}
+
+	rows, err := stmt.QueryContext(ctx, groupID)
+	if err != nil {
+		return make([]domain.Role, 0), err
+	}
+	defer rows.Close()
+
+	return r.scanRoleRows(rows)
+}
@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets the roles a user inherits from all of its groups, by slug.
+// Roles assigned to several of its groups are only returned once.
+func (r PostgresRepository) GetRolesByMember(ctx context.Context, userID uuid.UUID) ([]domain.Role, error) {
+	query := `
+		SELECT DISTINCT r.id, r.role_slug, r.role_label, r.requires_mfa, r.version, r.created_at, r.updated_at
+		FROM group_members gm
+		JOIN group_roles gr ON gr.group_id = gm.group_id
+		JOIN roles r ON r.id = gr.role_id
+		WHERE gm.user_id = $1 AND r.deleted_at IS NULL
+		ORDER BY r.role_slug
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return make([]domain.Role, 0), err
+	}
+
+	rows, err := stmt.QueryContext(ctx, userID)
+	if err != nil {
+		return make([]domain.Role, 0), err
+	}
+	defer rows.Close()
+
+	return r.scanRoleRows(rows)
+}
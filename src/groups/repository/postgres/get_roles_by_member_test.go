@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const getRolesByMemberQuery = `
+		SELECT DISTINCT r.id, r.role_slug, r.role_label, r.requires_mfa, r.version, r.created_at, r.updated_at
+		FROM group_members gm
+		JOIN group_roles gr ON gr.group_id = gm.group_id
+		JOIN roles r ON r.id = gr.role_id
+		WHERE gm.user_id = $1 AND r.deleted_at IS NULL
+		ORDER BY r.role_slug
+	`
+
+func TestGetRolesByMember_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getRolesByMemberQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).GetRolesByMember(context.TODO(), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestGetRolesByMember_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(getRolesByMemberQuery)).
+		ExpectQuery().
+		WithArgs(userID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).GetRolesByMember(ctx, userID)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestGetRolesByMember_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID, roleID := uuid.New(), uuid.New()
+	createdAt := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "role_slug", "role_label", "requires_mfa", "version", "created_at", "updated_at"}).
+		AddRow(roleID, "editor", "Editor", true, 1, createdAt, createdAt)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getRolesByMemberQuery)).
+		ExpectQuery().
+		WithArgs(userID).
+		WillReturnRows(rows)
+
+	res, err := New(db).GetRolesByMember(context.TODO(), userID)
+	assert.Nil(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, roleID, res[0].ID)
+	assert.Equal(t, "editor", res[0].RoleSlug)
+	assert.True(t, res[0].RequiresMFA)
+}
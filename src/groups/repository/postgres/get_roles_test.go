@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const getRolesQuery = `
+		SELECT r.id, r.role_slug, r.role_label, r.requires_mfa, r.version, r.created_at, r.updated_at
+		FROM group_roles gr
+		JOIN roles r ON r.id = gr.role_id
+		WHERE gr.group_id = $1 AND r.deleted_at IS NULL
+		ORDER BY r.role_slug
+	`
+
+func TestGetRoles_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getRolesQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).GetRoles(context.TODO(), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestGetRoles_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(getRolesQuery)).
+		ExpectQuery().
+		WithArgs(groupID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).GetRoles(ctx, groupID)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestGetRoles_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID, roleID := uuid.New(), uuid.New()
+	createdAt := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "role_slug", "role_label", "requires_mfa", "version", "created_at", "updated_at"}).
+		AddRow(roleID, "editor", "Editor", true, 1, createdAt, createdAt)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getRolesQuery)).
+		ExpectQuery().
+		WithArgs(groupID).
+		WillReturnRows(rows)
+
+	res, err := New(db).GetRoles(context.TODO(), groupID)
+	assert.Nil(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, roleID, res[0].ID)
+	assert.Equal(t, "editor", res[0].RoleSlug)
+	assert.True(t, res[0].RequiresMFA)
+}
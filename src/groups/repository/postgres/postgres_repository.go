@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Postgres error codes for unique and foreign key constraint violations
+const (
+	uniqueViolationCode     = "23505"
+	foreignKeyViolationCode = "23503"
+)
+
+type PostgresRepository struct {
+	Db *sql.DB
+}
+
+func New(db *sql.DB) domain.GroupRepository {
+	return PostgresRepository{db}
+}
+
+// Row of a single or multi row query
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans a group row, without its roles
+func (r PostgresRepository) scanGroupRow(row rowScanner) (domain.Group, error) {
+	result := domain.Group{}
+	err := row.Scan(
+		&result.ID,
+		&result.Name,
+		&result.Description,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	return result, nil
+}
+
+// Scans the rows of a roles query
+func (r PostgresRepository) scanRoleRows(rows *sql.Rows) ([]domain.Role, error) {
+	result := make([]domain.Role, 0)
+	for rows.Next() {
+		role := domain.Role{}
+		err := rows.Scan(
+			&role.ID,
+			&role.RoleSlug,
+			&role.RoleLabel,
+			&role.RequiresMFA,
+			&role.Version,
+			&role.CreatedAt,
+			&role.UpdatedAt,
+		)
+		if err != nil {
+			return make([]domain.Role, 0), err
+		}
+		result = append(result, role)
+	}
+	return result, rows.Err()
+}
+
+// Name of a group as kept unique, so names only differing in case can't coexist.
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Returns domain.ErrNotFound when a statement didn't affect any row.
+func requireAffectedRows(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
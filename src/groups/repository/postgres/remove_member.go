@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Removes a user from a group.
+func (r PostgresRepository) RemoveMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error {
+	stmt, err := r.Db.PrepareContext(ctx, `DELETE FROM group_members WHERE group_id = $1 AND user_id = $2`)
+	if err != nil {
+		return err
+	}
+
+	result, err := stmt.ExecContext(ctx, groupID, userID)
+	if err != nil {
+		return err
+	}
+	return requireAffectedRows(result)
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const removeMemberQuery = `DELETE FROM group_members WHERE group_id = $1 AND user_id = $2`
+
+func TestRemoveMember_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(removeMemberQuery)).WillReturnError(errors.New("boom"))
+
+	err = New(db).RemoveMember(context.TODO(), uuid.New(), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestRemoveMember_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID := uuid.New()
+	userID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(removeMemberQuery)).
+		ExpectExec().
+		WithArgs(groupID, userID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = New(db).RemoveMember(ctx, groupID, userID)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestRemoveMember_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID := uuid.New()
+	userID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(removeMemberQuery)).
+		ExpectExec().
+		WithArgs(groupID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = New(db).RemoveMember(context.TODO(), groupID, userID)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestRemoveMember_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID := uuid.New()
+	userID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(removeMemberQuery)).
+		ExpectExec().
+		WithArgs(groupID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = New(db).RemoveMember(context.TODO(), groupID, userID)
+	assert.Nil(t, err)
+}
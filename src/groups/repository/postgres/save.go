@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Creates or updates a group, without touching its members and roles.
+// domain.ErrAlreadyExists is returned when another group of the organization has the same name.
+func (r PostgresRepository) Save(ctx context.Context, group domain.Group) (domain.Group, error) {
+	if group.ID == uuid.Nil {
+		group.ID = uuid.New()
+	}
+
+	query := `
+		INSERT INTO groups (id, name, normalized_name, description, created_at, updated_at, organization_id)
+		VALUES ($1, $2, $3, $4, $5, $5, $6)
+		ON CONFLICT (id) DO UPDATE
+		SET name = EXCLUDED.name, normalized_name = EXCLUDED.normalized_name, description = EXCLUDED.description, updated_at = EXCLUDED.updated_at
+		WHERE groups.organization_id = EXCLUDED.organization_id
+		RETURNING id, name, description, created_at, updated_at
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.Group{}, err
+	}
+
+	row := stmt.QueryRowContext(ctx, group.ID, group.Name, normalizeName(group.Name), group.Description, time.Now(), domain.OrganizationFromContext(ctx))
+
+	result, err := r.scanGroupRow(row)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+		return domain.Group{}, domain.ErrAlreadyExists
+	}
+	// The ID is taken by a group of another organization, which is left untouched.
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Group{}, domain.ErrNotFound
+	}
+	return result, err
+}
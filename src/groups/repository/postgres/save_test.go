@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const saveQuery = `
+		INSERT INTO groups (id, name, normalized_name, description, created_at, updated_at, organization_id)
+		VALUES ($1, $2, $3, $4, $5, $5, $6)
+		ON CONFLICT (id) DO UPDATE
+		SET name = EXCLUDED.name, normalized_name = EXCLUDED.normalized_name, description = EXCLUDED.description, updated_at = EXCLUDED.updated_at
+		WHERE groups.organization_id = EXCLUDED.organization_id
+		RETURNING id, name, description, created_at, updated_at
+	`
+
+func TestSave_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(saveQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).Save(context.TODO(), domain.Group{Name: "Editors"})
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestSave_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	group := domain.Group{ID: uuid.New(), Name: "Editors"}
+	mock.ExpectPrepare(regexp.QuoteMeta(saveQuery)).
+		ExpectQuery().
+		WithArgs(group.ID, "Editors", "editors", "", anyTime{}, domain.DefaultOrganizationID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).Save(ctx, group)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestSave_AlreadyExists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	group := domain.Group{ID: uuid.New(), Name: " EDITORS "}
+	mock.ExpectPrepare(regexp.QuoteMeta(saveQuery)).
+		ExpectQuery().
+		WithArgs(group.ID, " EDITORS ", "editors", "", anyTime{}, domain.DefaultOrganizationID).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	res, err := New(db).Save(context.TODO(), group)
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+	assert.Empty(t, res)
+}
+
+func TestSave_GroupOfAnotherOrganization(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	group := domain.Group{ID: uuid.New(), Name: "Editors"}
+	mock.ExpectPrepare(regexp.QuoteMeta(saveQuery)).
+		ExpectQuery().
+		WithArgs(group.ID, "Editors", "editors", "", anyTime{}, domain.DefaultOrganizationID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "created_at", "updated_at"}))
+
+	res, err := New(db).Save(context.TODO(), group)
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.Empty(t, res)
+}
+
+func TestSave_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	createdAt := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "name", "description", "created_at", "updated_at"}).
+		AddRow(id, "Editors", "Can edit", createdAt, createdAt)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(saveQuery)).
+		ExpectQuery().
+		WithArgs(sqlmock.AnyArg(), "Editors", "editors", "Can edit", anyTime{}, domain.DefaultOrganizationID).
+		WillReturnRows(rows)
+
+	res, err := New(db).Save(context.TODO(), domain.Group{Name: "Editors", Description: "Can edit"})
+	assert.Nil(t, err)
+	assert.Equal(t, id, res.ID)
+	assert.Equal(t, "Editors", res.Name)
+	assert.Equal(t, createdAt, res.UpdatedAt)
+}
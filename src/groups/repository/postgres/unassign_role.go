@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Removes a role from a group.
+func (r PostgresRepository) UnassignRole(ctx context.Context, groupID uuid.UUID, roleID uuid.UUID) error {
+	stmt, err := r.Db.PrepareContext(ctx, `DELETE FROM group_roles WHERE group_id = $1 AND role_id = $2`)
+	if err != nil {
+		return err
+	}
+
+	result, err := stmt.ExecContext(ctx, groupID, roleID)
+	if err != nil {
+		return err
+	}
+	return requireAffectedRows(result)
+}
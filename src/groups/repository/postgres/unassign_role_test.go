@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const unassignRoleQuery = `DELETE FROM group_roles WHERE group_id = $1 AND role_id = $2`
+
+func TestUnassignRole_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(unassignRoleQuery)).WillReturnError(errors.New("boom"))
+
+	err = New(db).UnassignRole(context.TODO(), uuid.New(), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestUnassignRole_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID := uuid.New()
+	roleID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(unassignRoleQuery)).
+		ExpectExec().
+		WithArgs(groupID, roleID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = New(db).UnassignRole(ctx, groupID, roleID)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestUnassignRole_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID := uuid.New()
+	roleID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(unassignRoleQuery)).
+		ExpectExec().
+		WithArgs(groupID, roleID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = New(db).UnassignRole(context.TODO(), groupID, roleID)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestUnassignRole_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	groupID := uuid.New()
+	roleID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(unassignRoleQuery)).
+		ExpectExec().
+		WithArgs(groupID, roleID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = New(db).UnassignRole(context.TODO(), groupID, roleID)
+	assert.Nil(t, err)
+}
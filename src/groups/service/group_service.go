@@ -0,0 +1,45 @@
+package service
+
+import (
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type DefaultGroupService struct {
+	Logger         *log.Logger
+	GroupRepo      domain.GroupRepository
+	UserRepo       domain.UserRepository
+	RoleRepo       domain.RoleRepository
+	ContextTimeout time.Duration
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	groupRepo domain.GroupRepository,
+	userRepo domain.UserRepository,
+	roleRepo domain.RoleRepository,
+	contextTimeout time.Duration,
+) domain.GroupService {
+	return DefaultGroupService{
+		logger,
+		groupRepo,
+		userRepo,
+		roleRepo,
+		contextTimeout,
+	}
+}
+
+// Instantiation for tests
+func newService(groupRepo domain.GroupRepository, userRepo domain.UserRepository, roleRepo domain.RoleRepository) DefaultGroupService {
+	return DefaultGroupService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		groupRepo,
+		userRepo,
+		roleRepo,
+		time.Duration(5 * time.Second),
+	}
+}
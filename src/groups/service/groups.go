@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Longest name of a group, as stored in the DB.
+const maxGroupNameLength = 255
+
+// Gets all the groups, with their roles.
+func (s DefaultGroupService) GetAll(ctx context.Context) ([]domain.Group, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	groups, err := s.GroupRepo.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range groups {
+		groups[i].Roles, err = s.GroupRepo.GetRoles(ctx, groups[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return groups, nil
+}
+
+// Gets a group, with its roles.
+func (s DefaultGroupService) Get(ctx context.Context, id uuid.UUID) (domain.Group, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	return s.getWithRoles(ctx, id)
+}
+
+// Creates a group when it has no ID, updates its name and description otherwise.
+func (s DefaultGroupService) Save(ctx context.Context, group domain.Group) (domain.Group, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	group.Name = strings.TrimSpace(group.Name)
+	if len(group.Name) == 0 || utf8.RuneCountInString(group.Name) > maxGroupNameLength {
+		return domain.Group{}, domain.ErrBadParamInput
+	}
+
+	// Saving doesn't create groups with IDs picked by the callers.
+	if group.ID != uuid.Nil {
+		if _, err := s.GroupRepo.GetByUUID(ctx, group.ID); err != nil {
+			return domain.Group{}, err
+		}
+	}
+
+	saved, err := s.GroupRepo.Save(ctx, group)
+	if err != nil {
+		return domain.Group{}, err
+	}
+
+	saved.Roles, err = s.GroupRepo.GetRoles(ctx, saved.ID)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	return saved, nil
+}
+
+// Deletes a group. Its members lose the roles they inherited from it on their next refresh.
+func (s DefaultGroupService) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	if id == uuid.Nil {
+		return domain.ErrBadParamInput
+	}
+
+	return s.GroupRepo.Delete(ctx, id)
+}
+
+// Gets a group along with its roles.
+func (s DefaultGroupService) getWithRoles(ctx context.Context, id uuid.UUID) (domain.Group, error) {
+	if id == uuid.Nil {
+		return domain.Group{}, domain.ErrBadParamInput
+	}
+
+	group, err := s.GroupRepo.GetByUUID(ctx, id)
+	if err != nil {
+		return domain.Group{}, err
+	}
+
+	group.Roles, err = s.GroupRepo.GetRoles(ctx, id)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	return group, nil
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetAll_ErrorFetching(t *testing.T) {
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("Fetch", mock.Anything).Once().Return(nil, errors.New("boom"))
+
+	res, err := newService(groupRepo, nil, nil).GetAll(context.TODO())
+	assert.Equal(t, "boom", err.Error())
+	assert.Nil(t, res)
+	groupRepo.AssertExpectations(t)
+}
+
+func TestGetAll_Success(t *testing.T) {
+	first := domain.Group{ID: uuid.New(), Name: "Editors"}
+	second := domain.Group{ID: uuid.New(), Name: "Support"}
+	roles := []domain.Role{{ID: uuid.New(), RoleSlug: "editor"}}
+
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("Fetch", mock.Anything).Once().Return([]domain.Group{first, second}, nil)
+	groupRepo.On("GetRoles", mock.Anything, first.ID).Once().Return(roles, nil)
+	groupRepo.On("GetRoles", mock.Anything, second.ID).Once().Return([]domain.Role{}, nil)
+
+	res, err := newService(groupRepo, nil, nil).GetAll(context.TODO())
+	assert.Nil(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, roles, res[0].Roles)
+	assert.Empty(t, res[1].Roles)
+	groupRepo.AssertExpectations(t)
+}
+
+func TestGet_InvalidID(t *testing.T) {
+	_, err := newService(nil, nil, nil).Get(context.TODO(), uuid.Nil)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestGet_NotFound(t *testing.T) {
+	id := uuid.New()
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("GetByUUID", mock.Anything, id).Once().Return(domain.Group{}, domain.ErrNotFound)
+
+	_, err := newService(groupRepo, nil, nil).Get(context.TODO(), id)
+	assert.Equal(t, domain.ErrNotFound, err)
+	groupRepo.AssertExpectations(t)
+}
+
+func TestGet_Success(t *testing.T) {
+	group := domain.Group{ID: uuid.New(), Name: "Editors"}
+	roles := []domain.Role{{ID: uuid.New(), RoleSlug: "editor"}}
+
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("GetByUUID", mock.Anything, group.ID).Once().Return(group, nil)
+	groupRepo.On("GetRoles", mock.Anything, group.ID).Once().Return(roles, nil)
+
+	res, err := newService(groupRepo, nil, nil).Get(context.TODO(), group.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "Editors", res.Name)
+	assert.Equal(t, roles, res.Roles)
+	groupRepo.AssertExpectations(t)
+}
+
+func TestSave_InvalidName(t *testing.T) {
+	service := newService(nil, nil, nil)
+
+	for _, name := range []string{"", "   ", strings.Repeat("a", 256)} {
+		res, err := service.Save(context.TODO(), domain.Group{Name: name})
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		assert.Equal(t, domain.Group{}, res)
+	}
+}
+
+func TestSave_UpdatingUnknownGroup(t *testing.T) {
+	id := uuid.New()
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("GetByUUID", mock.Anything, id).Once().Return(domain.Group{}, domain.ErrNotFound)
+
+	_, err := newService(groupRepo, nil, nil).Save(context.TODO(), domain.Group{ID: id, Name: "Editors"})
+	assert.Equal(t, domain.ErrNotFound, err)
+	groupRepo.AssertExpectations(t)
+}
+
+func TestSave_NameTaken(t *testing.T) {
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("Save", mock.Anything, domain.Group{Name: "Editors"}).Once().Return(domain.Group{}, domain.ErrAlreadyExists)
+
+	_, err := newService(groupRepo, nil, nil).Save(context.TODO(), domain.Group{Name: " Editors "})
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+	groupRepo.AssertExpectations(t)
+}
+
+func TestSave_Create(t *testing.T) {
+	saved := domain.Group{ID: uuid.New(), Name: "Editors", Description: "Can edit"}
+
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("Save", mock.Anything, domain.Group{Name: "Editors", Description: "Can edit"}).Once().Return(saved, nil)
+	groupRepo.On("GetRoles", mock.Anything, saved.ID).Once().Return([]domain.Role{}, nil)
+
+	res, err := newService(groupRepo, nil, nil).Save(context.TODO(), domain.Group{Name: "Editors", Description: "Can edit"})
+	assert.Nil(t, err)
+	assert.Equal(t, saved.ID, res.ID)
+	assert.Empty(t, res.Roles)
+	groupRepo.AssertExpectations(t)
+}
+
+func TestSave_Update(t *testing.T) {
+	group := domain.Group{ID: uuid.New(), Name: "Writers"}
+	roles := []domain.Role{{ID: uuid.New(), RoleSlug: "editor"}}
+
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("GetByUUID", mock.Anything, group.ID).Once().Return(domain.Group{ID: group.ID, Name: "Editors"}, nil)
+	groupRepo.On("Save", mock.Anything, group).Once().Return(group, nil)
+	groupRepo.On("GetRoles", mock.Anything, group.ID).Once().Return(roles, nil)
+
+	res, err := newService(groupRepo, nil, nil).Save(context.TODO(), group)
+	assert.Nil(t, err)
+	assert.Equal(t, "Writers", res.Name)
+	assert.Equal(t, roles, res.Roles)
+	groupRepo.AssertExpectations(t)
+}
+
+func TestDelete_InvalidID(t *testing.T) {
+	err := newService(nil, nil, nil).Delete(context.TODO(), uuid.Nil)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestDelete_Success(t *testing.T) {
+	id := uuid.New()
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("Delete", mock.Anything, id).Once().Return(nil)
+
+	err := newService(groupRepo, nil, nil).Delete(context.TODO(), id)
+	assert.Nil(t, err)
+	groupRepo.AssertExpectations(t)
+}
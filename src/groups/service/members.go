@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Adds a user to a group. The user gets the roles of the group on its next refresh.
+func (s DefaultGroupService) AddMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	if groupID == uuid.Nil || userID == uuid.Nil {
+		return domain.ErrBadParamInput
+	}
+
+	// Groups and users of other organizations are left alone.
+	if _, err := s.GroupRepo.GetByUUID(ctx, groupID); err != nil {
+		return err
+	}
+
+	// Deleted users are still in the DB, so the foreign key doesn't catch them.
+	_, err := s.UserRepo.GetByUUID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.GroupRepo.AddMember(ctx, groupID, userID)
+}
+
+// Removes a user from a group. The user loses the roles of the group on its next refresh.
+func (s DefaultGroupService) RemoveMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	if groupID == uuid.Nil || userID == uuid.Nil {
+		return domain.ErrBadParamInput
+	}
+
+	if _, err := s.GroupRepo.GetByUUID(ctx, groupID); err != nil {
+		return err
+	}
+
+	return s.GroupRepo.RemoveMember(ctx, groupID, userID)
+}
+
+// Gets the IDs of the members of a group.
+func (s DefaultGroupService) GetMembers(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	if groupID == uuid.Nil {
+		return nil, domain.ErrBadParamInput
+	}
+
+	if _, err := s.GroupRepo.GetByUUID(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	return s.GroupRepo.GetMembers(ctx, groupID)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddMember_InvalidIDs(t *testing.T) {
+	service := newService(nil, nil, nil)
+
+	assert.Equal(t, domain.ErrBadParamInput, service.AddMember(context.TODO(), uuid.Nil, uuid.New()))
+	assert.Equal(t, domain.ErrBadParamInput, service.AddMember(context.TODO(), uuid.New(), uuid.Nil))
+}
+
+func TestAddMember_UnknownGroup(t *testing.T) {
+	groupID := uuid.New()
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("GetByUUID", mock.Anything, groupID).Once().Return(domain.Group{}, domain.ErrNotFound)
+
+	err := newService(groupRepo, nil, nil).AddMember(context.TODO(), groupID, uuid.New())
+	assert.Equal(t, domain.ErrNotFound, err)
+	groupRepo.AssertExpectations(t)
+}
+
+func TestAddMember_UnknownUser(t *testing.T) {
+	groupID, userID := uuid.New(), uuid.New()
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("GetByUUID", mock.Anything, groupID).Once().Return(domain.Group{ID: groupID}, nil)
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userID).Once().Return(nil, sql.ErrNoRows)
+
+	err := newService(groupRepo, userRepo, nil).AddMember(context.TODO(), groupID, userID)
+	assert.Equal(t, domain.ErrNotFound, err)
+	userRepo.AssertExpectations(t)
+}
+
+func TestAddMember_Success(t *testing.T) {
+	groupID, userID := uuid.New(), uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userID).Once().Return(&domain.User{ID: userID}, nil)
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("GetByUUID", mock.Anything, groupID).Once().Return(domain.Group{ID: groupID}, nil)
+	groupRepo.On("AddMember", mock.Anything, groupID, userID).Once().Return(nil)
+
+	err := newService(groupRepo, userRepo, nil).AddMember(context.TODO(), groupID, userID)
+	assert.Nil(t, err)
+	userRepo.AssertExpectations(t)
+	groupRepo.AssertExpectations(t)
+}
+
+func TestRemoveMember_InvalidIDs(t *testing.T) {
+	err := newService(nil, nil, nil).RemoveMember(context.TODO(), uuid.New(), uuid.Nil)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestRemoveMember_Success(t *testing.T) {
+	groupID, userID := uuid.New(), uuid.New()
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("GetByUUID", mock.Anything, groupID).Once().Return(domain.Group{ID: groupID}, nil)
+	groupRepo.On("RemoveMember", mock.Anything, groupID, userID).Once().Return(domain.ErrNotFound)
+
+	err := newService(groupRepo, nil, nil).RemoveMember(context.TODO(), groupID, userID)
+	assert.Equal(t, domain.ErrNotFound, err)
+	groupRepo.AssertExpectations(t)
+}
+
+func TestGetMembers_UnknownGroup(t *testing.T) {
+	groupID := uuid.New()
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("GetByUUID", mock.Anything, groupID).Once().Return(domain.Group{}, domain.ErrNotFound)
+
+	res, err := newService(groupRepo, nil, nil).GetMembers(context.TODO(), groupID)
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.Nil(t, res)
+	groupRepo.AssertExpectations(t)
+}
+
+func TestGetMembers_Success(t *testing.T) {
+	groupID := uuid.New()
+	members := []uuid.UUID{uuid.New(), uuid.New()}
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("GetByUUID", mock.Anything, groupID).Once().Return(domain.Group{ID: groupID}, nil)
+	groupRepo.On("GetMembers", mock.Anything, groupID).Once().Return(members, nil)
+
+	res, err := newService(groupRepo, nil, nil).GetMembers(context.TODO(), groupID)
+	assert.Nil(t, err)
+	assert.Equal(t, members, res)
+	groupRepo.AssertExpectations(t)
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Assigns a role to a group, returning the group with its roles.
+func (s DefaultGroupService) AssignRole(ctx context.Context, groupID uuid.UUID, roleSlug string) (domain.Group, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	role, err := s.getRole(ctx, groupID, roleSlug)
+	if err != nil {
+		return domain.Group{}, err
+	}
+
+	if err := s.GroupRepo.AssignRole(ctx, groupID, role.ID); err != nil {
+		return domain.Group{}, err
+	}
+	return s.getWithRoles(ctx, groupID)
+}
+
+// Removes a role from a group, returning the group with its remaining roles.
+func (s DefaultGroupService) UnassignRole(ctx context.Context, groupID uuid.UUID, roleSlug string) (domain.Group, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	role, err := s.getRole(ctx, groupID, roleSlug)
+	if err != nil {
+		return domain.Group{}, err
+	}
+
+	if err := s.GroupRepo.UnassignRole(ctx, groupID, role.ID); err != nil {
+		return domain.Group{}, err
+	}
+	return s.getWithRoles(ctx, groupID)
+}
+
+// Gets the role with a slug, for an assignment to a group.
+// Both the group and the role have to be of the organization of the context.
+func (s DefaultGroupService) getRole(ctx context.Context, groupID uuid.UUID, roleSlug string) (domain.Role, error) {
+	if groupID == uuid.Nil || len(roleSlug) == 0 {
+		return domain.Role{}, domain.ErrBadParamInput
+	}
+
+	if _, err := s.GroupRepo.GetByUUID(ctx, groupID); err != nil {
+		return domain.Role{}, err
+	}
+
+	role, err := s.RoleRepo.GetBySlug(ctx, roleSlug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Role{}, domain.ErrNotFound
+	}
+	return role, err
+}
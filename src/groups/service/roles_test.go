@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAssignRole_InvalidParams(t *testing.T) {
+	service := newService(nil, nil, nil)
+
+	_, err := service.AssignRole(context.TODO(), uuid.Nil, "editor")
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	_, err = service.AssignRole(context.TODO(), uuid.New(), "")
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestAssignRole_UnknownRole(t *testing.T) {
+	groupID := uuid.New()
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("GetByUUID", mock.Anything, groupID).Once().Return(domain.Group{ID: groupID}, nil)
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "editor").Once().Return(domain.Role{}, sql.ErrNoRows)
+
+	_, err := newService(groupRepo, nil, roleRepo).AssignRole(context.TODO(), groupID, "editor")
+	assert.Equal(t, domain.ErrNotFound, err)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestAssignRole_UnknownGroup(t *testing.T) {
+	groupID := uuid.New()
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("GetByUUID", mock.Anything, groupID).Once().Return(domain.Group{}, domain.ErrNotFound)
+
+	_, err := newService(groupRepo, nil, nil).AssignRole(context.TODO(), groupID, "editor")
+	assert.Equal(t, domain.ErrNotFound, err)
+	groupRepo.AssertExpectations(t)
+}
+
+func TestAssignRole_Success(t *testing.T) {
+	group := domain.Group{ID: uuid.New(), Name: "Editors"}
+	role := domain.Role{ID: uuid.New(), RoleSlug: "editor"}
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "editor").Once().Return(role, nil)
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("AssignRole", mock.Anything, group.ID, role.ID).Once().Return(nil)
+	groupRepo.On("GetByUUID", mock.Anything, group.ID).Twice().Return(group, nil)
+	groupRepo.On("GetRoles", mock.Anything, group.ID).Once().Return([]domain.Role{role}, nil)
+
+	res, err := newService(groupRepo, nil, roleRepo).AssignRole(context.TODO(), group.ID, "editor")
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.Role{role}, res.Roles)
+	groupRepo.AssertExpectations(t)
+}
+
+func TestUnassignRole_Success(t *testing.T) {
+	group := domain.Group{ID: uuid.New(), Name: "Editors"}
+	role := domain.Role{ID: uuid.New(), RoleSlug: "editor"}
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "editor").Once().Return(role, nil)
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("UnassignRole", mock.Anything, group.ID, role.ID).Once().Return(nil)
+	groupRepo.On("GetByUUID", mock.Anything, group.ID).Twice().Return(group, nil)
+	groupRepo.On("GetRoles", mock.Anything, group.ID).Once().Return([]domain.Role{}, nil)
+
+	res, err := newService(groupRepo, nil, roleRepo).UnassignRole(context.TODO(), group.ID, "editor")
+	assert.Nil(t, err)
+	assert.Empty(t, res.Roles)
+	groupRepo.AssertExpectations(t)
+}
@@ -0,0 +1,17 @@
+package helpers
+
+import "strconv"
+
+// Converts a string to a boolean ("true", "1", "false", "0"...). If string
+// in question cannot be converted, it will be converted to a default value
+func ConvertToBool(value string, defaultValue bool) bool {
+	if len(value) == 0 {
+		return defaultValue
+	}
+
+	valueBool, err := strconv.ParseBool(value)
+	if err != nil {
+		return defaultValue
+	}
+	return valueBool
+}
@@ -0,0 +1,27 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToBool(t *testing.T) {
+	tests := []struct {
+		input        string
+		defaultValue bool
+		expected     bool
+	}{
+		{"true", false, true},
+		{"1", false, true},
+		{"false", true, false},
+		{"0", true, false},
+		{"", true, true},
+		{"", false, false},
+		{"yes please", false, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, ConvertToBool(test.input, test.defaultValue), test.input)
+	}
+}
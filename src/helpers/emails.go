@@ -0,0 +1,17 @@
+package helpers
+
+import (
+	"net/mail"
+	"strings"
+)
+
+// Checks if a string is a bare email address ("alice@example.com"),
+// without display name or angle brackets.
+func IsValidEmail(email string) bool {
+	if len(email) == 0 || len(email) > 255 || strings.ContainsAny(email, "\r\n") {
+		return false
+	}
+
+	address, err := mail.ParseAddress(email)
+	return err == nil && address.Address == email
+}
@@ -0,0 +1,30 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidEmail(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"alice@example.com", true},
+		{"Alice.Smith+tag@sub.example.com", true},
+		{"", false},
+		{"alice", false},
+		{"alice@", false},
+		{"Alice <alice@example.com>", false},
+		{"<alice@example.com>", false},
+		{" alice@example.com", false},
+		{"alice@example.com\r\nBcc: eve@example.com", false},
+		{strings.Repeat("a", 250) + "@example.com", false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, IsValidEmail(test.input), test.input)
+	}
+}
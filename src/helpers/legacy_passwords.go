@@ -0,0 +1,249 @@
+package helpers
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Schemes of the password hashes imported from other systems, in the encoding
+// Django uses for them, where the scheme is the first "$" separated field:
+//
+//	pbkdf2_sha256$<iterations>$<salt>$<base64 hash>
+//	scrypt$<n>$<salt>$<r>$<p>$<base64 hash>
+//	argon2$<argon2i|argon2id>$v=19$m=<memory>,t=<time>,p=<threads>$<base64 salt>$<base64 hash>
+//	sha1$<salt>$<hex hash of the salt followed by the password>
+const (
+	LegacyPasswordSchemePBKDF2SHA256 = "pbkdf2_sha256"
+	LegacyPasswordSchemeScrypt       = "scrypt"
+	LegacyPasswordSchemeArgon2       = "argon2"
+	LegacyPasswordSchemeSaltedSHA1   = "sha1"
+)
+
+// Every legacy scheme.
+var LegacyPasswordSchemes = []string{
+	LegacyPasswordSchemePBKDF2SHA256,
+	LegacyPasswordSchemeScrypt,
+	LegacyPasswordSchemeArgon2,
+	LegacyPasswordSchemeSaltedSHA1,
+}
+
+// Limits of the cost parameters, so a crafted hash can't exhaust the server.
+const (
+	maxPBKDF2Iterations = 10_000_000
+	maxScryptN          = 1 << 20
+	maxScryptRP         = 1 << 10
+	maxArgon2Memory     = 1 << 20
+	maxArgon2Time       = 32
+)
+
+var (
+	ErrInvalidLegacyPasswordHash   = errors.New("invalid legacy password hash")
+	ErrMismatchedLegacyPassword    = errors.New("password doesn't match the legacy hash")
+	errUnknownLegacyPasswordHash   = fmt.Errorf("%w: unknown scheme", ErrInvalidLegacyPasswordHash)
+	errLegacyPasswordHashTooCostly = fmt.Errorf("%w: cost parameters too high", ErrInvalidLegacyPasswordHash)
+)
+
+// Gets the legacy scheme of a password hash, empty when it isn't a legacy hash.
+func LegacyPasswordScheme(hash string) string {
+	scheme, _, _ := strings.Cut(hash, "$")
+	for _, known := range LegacyPasswordSchemes {
+		if scheme == known {
+			return scheme
+		}
+	}
+	return ""
+}
+
+// Checks if a legacy password hash is well formed, in a known scheme.
+func ValidateLegacyPasswordHash(hash string) error {
+	_, err := parseLegacyPasswordHash(hash)
+	return err
+}
+
+// Compares a password with a legacy hash, returning nil when they match.
+func CompareLegacyPasswordHash(hash string, password string) error {
+	parsed, err := parseLegacyPasswordHash(hash)
+	if err != nil {
+		return err
+	}
+	derived, err := parsed.derive(password)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(parsed.stored, derived) != 1 {
+		return ErrMismatchedLegacyPassword
+	}
+	return nil
+}
+
+// Hash stored in a legacy encoding, with the function that hashes a password
+// with the same parameters.
+type legacyPasswordHash struct {
+	stored []byte
+	derive func(password string) ([]byte, error)
+}
+
+// Parses a legacy hash, checking its parameters.
+func parseLegacyPasswordHash(hash string) (legacyPasswordHash, error) {
+	fields := strings.Split(hash, "$")
+	switch LegacyPasswordScheme(hash) {
+	case LegacyPasswordSchemePBKDF2SHA256:
+		return parsePBKDF2Hash(fields)
+	case LegacyPasswordSchemeScrypt:
+		return parseScryptHash(fields)
+	case LegacyPasswordSchemeArgon2:
+		return parseArgon2Hash(fields)
+	case LegacyPasswordSchemeSaltedSHA1:
+		return parseSaltedSHA1Hash(fields)
+	}
+	return legacyPasswordHash{}, errUnknownLegacyPasswordHash
+}
+
+// pbkdf2_sha256$<iterations>$<salt>$<base64 hash>
+func parsePBKDF2Hash(fields []string) (legacyPasswordHash, error) {
+	if len(fields) != 4 || len(fields[2]) == 0 {
+		return legacyPasswordHash{}, ErrInvalidLegacyPasswordHash
+	}
+	iterations, err := positiveInt(fields[1])
+	if err != nil {
+		return legacyPasswordHash{}, err
+	}
+	if iterations > maxPBKDF2Iterations {
+		return legacyPasswordHash{}, errLegacyPasswordHashTooCostly
+	}
+	stored, err := decodeLegacyHash(base64.StdEncoding, fields[3])
+	if err != nil {
+		return legacyPasswordHash{}, err
+	}
+
+	return legacyPasswordHash{stored, func(password string) ([]byte, error) {
+		return pbkdf2.Key([]byte(password), []byte(fields[2]), iterations, len(stored), sha256.New), nil
+	}}, nil
+}
+
+// scrypt$<n>$<salt>$<r>$<p>$<base64 hash>
+func parseScryptHash(fields []string) (legacyPasswordHash, error) {
+	if len(fields) != 6 || len(fields[2]) == 0 {
+		return legacyPasswordHash{}, ErrInvalidLegacyPasswordHash
+	}
+	params := [3]int{}
+	for i, field := range []string{fields[1], fields[3], fields[4]} {
+		value, err := positiveInt(field)
+		if err != nil {
+			return legacyPasswordHash{}, err
+		}
+		params[i] = value
+	}
+	n, r, p := params[0], params[1], params[2]
+	if n < 2 || n&(n-1) != 0 {
+		return legacyPasswordHash{}, ErrInvalidLegacyPasswordHash
+	}
+	if n > maxScryptN || r*p > maxScryptRP {
+		return legacyPasswordHash{}, errLegacyPasswordHashTooCostly
+	}
+	stored, err := decodeLegacyHash(base64.StdEncoding, fields[5])
+	if err != nil {
+		return legacyPasswordHash{}, err
+	}
+
+	return legacyPasswordHash{stored, func(password string) ([]byte, error) {
+		return scrypt.Key([]byte(password), []byte(fields[2]), n, r, p, len(stored))
+	}}, nil
+}
+
+// argon2$<argon2i|argon2id>$v=19$m=<memory>,t=<time>,p=<threads>$<base64 salt>$<base64 hash>
+func parseArgon2Hash(fields []string) (legacyPasswordHash, error) {
+	if len(fields) != 6 || fields[2] != fmt.Sprintf("v=%d", argon2.Version) {
+		return legacyPasswordHash{}, ErrInvalidLegacyPasswordHash
+	}
+	variant := fields[1]
+	if variant != "argon2i" && variant != "argon2id" {
+		return legacyPasswordHash{}, ErrInvalidLegacyPasswordHash
+	}
+
+	var memory, time, threads int
+	for _, param := range strings.Split(fields[3], ",") {
+		name, value, _ := strings.Cut(param, "=")
+		parsed, err := positiveInt(value)
+		if err != nil {
+			return legacyPasswordHash{}, err
+		}
+		switch name {
+		case "m":
+			memory = parsed
+		case "t":
+			time = parsed
+		case "p":
+			threads = parsed
+		default:
+			return legacyPasswordHash{}, ErrInvalidLegacyPasswordHash
+		}
+	}
+	if memory == 0 || time == 0 || threads == 0 || threads > 255 {
+		return legacyPasswordHash{}, ErrInvalidLegacyPasswordHash
+	}
+	if memory > maxArgon2Memory || time > maxArgon2Time {
+		return legacyPasswordHash{}, errLegacyPasswordHashTooCostly
+	}
+	salt, err := decodeLegacyHash(base64.RawStdEncoding, fields[4])
+	if err != nil {
+		return legacyPasswordHash{}, err
+	}
+	stored, err := decodeLegacyHash(base64.RawStdEncoding, fields[5])
+	if err != nil {
+		return legacyPasswordHash{}, err
+	}
+
+	key := argon2.IDKey
+	if variant == "argon2i" {
+		key = argon2.Key
+	}
+	return legacyPasswordHash{stored, func(password string) ([]byte, error) {
+		return key([]byte(password), salt, uint32(time), uint32(memory), uint8(threads), uint32(len(stored))), nil
+	}}, nil
+}
+
+// sha1$<salt>$<hex hash of the salt followed by the password>
+func parseSaltedSHA1Hash(fields []string) (legacyPasswordHash, error) {
+	if len(fields) != 3 || len(fields[1]) == 0 {
+		return legacyPasswordHash{}, ErrInvalidLegacyPasswordHash
+	}
+	stored, err := hex.DecodeString(fields[2])
+	if err != nil || len(stored) != sha1.Size {
+		return legacyPasswordHash{}, ErrInvalidLegacyPasswordHash
+	}
+
+	return legacyPasswordHash{stored, func(password string) ([]byte, error) {
+		derived := sha1.Sum([]byte(fields[1] + password))
+		return derived[:], nil
+	}}, nil
+}
+
+// Parses a cost parameter of a hash.
+func positiveInt(value string) (int, error) {
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		return 0, ErrInvalidLegacyPasswordHash
+	}
+	return parsed, nil
+}
+
+// Decodes the salt or hash of a hash, which can't be empty.
+func decodeLegacyHash(encoding *base64.Encoding, value string) ([]byte, error) {
+	decoded, err := encoding.DecodeString(value)
+	if err != nil || len(decoded) == 0 {
+		return nil, ErrInvalidLegacyPasswordHash
+	}
+	return decoded, nil
+}
@@ -0,0 +1,76 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/argon2"
+)
+
+func TestLegacyPasswordScheme(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"pbkdf2_sha256$1000$salt$hash", LegacyPasswordSchemePBKDF2SHA256},
+		{"scrypt$16$salt$8$1$hash", LegacyPasswordSchemeScrypt},
+		{"argon2$argon2id$v=19$m=16,t=2,p=1$salt$hash", LegacyPasswordSchemeArgon2},
+		{"sha1$salt$hash", LegacyPasswordSchemeSaltedSHA1},
+		{"$2a$14$ajq8Q7fbtFRQvXpdCq7Jcuy.Rx1h/L4J60Otx.gyNLbAYctGMJ9tK", ""},
+		{"md5$salt$hash", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, LegacyPasswordScheme(test.input), test.input)
+	}
+}
+
+func TestCompareLegacyPasswordHash(t *testing.T) {
+	argon2id := "argon2$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ$" +
+		base64.RawStdEncoding.EncodeToString(argon2.IDKey([]byte("correct horse"), []byte("somesalt"), 1, 64, 1, 32))
+
+	hashes := map[string]string{
+		"pbkdf2":   "pbkdf2_sha256$1000$saltsalt$qQDPSZa3Ormyy9oK1Pu0ZLLwP2Svzmx3yu8OvDdq/J0=",
+		"scrypt":   "scrypt$16$saltsalt$8$1$5eJHz+r0WlGIVtbszqsCUiq6CMxJnB1YJDpacs0jSZSXFr4VxXSu1b6GIgfhikRZeIb0/HStb7GW8fWVwx/Z0g==",
+		"argon2id": argon2id,
+		"sha1":     "sha1$saltsalt$599c8acd3f623920c9f30c903535e864275a2e35",
+	}
+
+	for name, hash := range hashes {
+		assert.Nil(t, ValidateLegacyPasswordHash(hash), name)
+		assert.Nil(t, CompareLegacyPasswordHash(hash, "correct horse"), name)
+		assert.ErrorIs(t, CompareLegacyPasswordHash(hash, "wrong horse"), ErrMismatchedLegacyPassword, name)
+	}
+
+	// Reference vector of the argon2 implementation.
+	argon2i := "argon2$argon2i$v=19$m=65536,t=2,p=4$c29tZXNhbHQ$RdescudvJCsgt3ub+b+dWRWJTmaaJObG"
+	assert.Nil(t, CompareLegacyPasswordHash(argon2i, "password"))
+}
+
+func TestValidateLegacyPasswordHash_FailIfInvalid(t *testing.T) {
+	hashes := []string{
+		"",
+		"$2a$14$ajq8Q7fbtFRQvXpdCq7Jcuy.Rx1h/L4J60Otx.gyNLbAYctGMJ9tK",
+		"pbkdf2_sha256$1000$saltsalt",
+		"pbkdf2_sha256$-1$saltsalt$qQDPSZa3Ormyy9oK1Pu0ZLLwP2Svzmx3yu8OvDdq/J0=",
+		"pbkdf2_sha256$1000$$qQDPSZa3Ormyy9oK1Pu0ZLLwP2Svzmx3yu8OvDdq/J0=",
+		"pbkdf2_sha256$1000$saltsalt$not base64",
+		"pbkdf2_sha256$100000000$saltsalt$qQDPSZa3Ormyy9oK1Pu0ZLLwP2Svzmx3yu8OvDdq/J0=",
+		"scrypt$15$saltsalt$8$1$5eJHz+r0WlGIVtbszqsCUg==",
+		"scrypt$4194304$saltsalt$8$1$5eJHz+r0WlGIVtbszqsCUg==",
+		"scrypt$16$saltsalt$1024$2$5eJHz+r0WlGIVtbszqsCUg==",
+		"argon2$argon2d$v=19$m=64,t=1,p=1$c29tZXNhbHQ$RdescudvJCsgt3ub+b+dWRWJTmaaJObG",
+		"argon2$argon2id$v=16$m=64,t=1,p=1$c29tZXNhbHQ$RdescudvJCsgt3ub+b+dWRWJTmaaJObG",
+		"argon2$argon2id$v=19$m=64,t=1$c29tZXNhbHQ$RdescudvJCsgt3ub+b+dWRWJTmaaJObG",
+		"argon2$argon2id$v=19$m=64,t=1,p=1,x=2$c29tZXNhbHQ$RdescudvJCsgt3ub+b+dWRWJTmaaJObG",
+		"argon2$argon2id$v=19$m=4194304,t=1,p=1$c29tZXNhbHQ$RdescudvJCsgt3ub+b+dWRWJTmaaJObG",
+		"sha1$saltsalt$599c8acd",
+		"sha1$$599c8acd3f623920c9f30c903535e864275a2e35",
+	}
+
+	for _, hash := range hashes {
+		assert.ErrorIs(t, ValidateLegacyPasswordHash(hash), ErrInvalidLegacyPasswordHash, hash)
+	}
+}
@@ -1,6 +1,9 @@
 package helpers
 
-import "context"
+import (
+	"context"
+	"strings"
+)
 
 func StringFromContext(ctx context.Context, key string) string {
 	val := ctx.Value(key)
@@ -13,3 +16,14 @@ func StringFromContext(ctx context.Context, key string) string {
 	}
 	return res
 }
+
+// Splits a comma separated list, ignoring the empty values.
+func SplitList(value string) []string {
+	result := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -23,3 +23,9 @@ func TestFromContext_DifferentStructure(t *testing.T) {
 	res := StringFromContext(ctx, "cenas")
 	assert.Equal(t, "", res)
 }
+
+func TestSplitList(t *testing.T) {
+	assert.Equal(t, []string{}, SplitList(""))
+	assert.Equal(t, []string{"example.com"}, SplitList("example.com"))
+	assert.Equal(t, []string{"example.com", "example.org"}, SplitList(" example.com, ,example.org ,"))
+}
@@ -0,0 +1,19 @@
+package helpers
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalizes a username so that visually equivalent usernames
+// ("Alice", " alice", "ＡＬＩＣＥ") are the same one.
+// Applies NFKC, case folding and trims the surrounding spaces.
+func NormalizeUsername(username string) string {
+	normalized := norm.NFKC.String(strings.TrimSpace(username))
+	normalized = cases.Fold().String(normalized)
+
+	// Folding can produce non-normalized sequences, so normalize again.
+	return strings.TrimSpace(norm.NFKC.String(normalized))
+}
@@ -0,0 +1,28 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeUsername(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"alice", "alice"},
+		{"Alice", "alice"},
+		{"  ALICE \t", "alice"},
+		{"ＡＬＩＣＥ", "alice"},
+		{"Straße", "strasse"},
+		{"ﬁona", "fiona"},
+		{"José", "josé"},
+		{"José", "josé"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, NormalizeUsername(test.input), test.input)
+	}
+}
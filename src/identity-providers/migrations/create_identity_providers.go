@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Creates the table of the upstream OpenID Connect providers of each organization
+func CreateIdentityProvidersTable(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS identity_providers(
+			id uuid NOT NULL,
+			organization_id uuid NOT NULL REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			slug varchar(64) NOT NULL,
+			name varchar(255) NOT NULL DEFAULT '',
+			issuer text NOT NULL,
+			client_id text NOT NULL,
+			client_secret text NOT NULL DEFAULT '',
+			redirect_url text NOT NULL,
+			scopes text NOT NULL DEFAULT '',
+			role_claim varchar(255) NOT NULL DEFAULT '',
+			role_mapping jsonb NOT NULL DEFAULT '{}',
+			default_role varchar(255) NOT NULL DEFAULT '',
+			link_by_email boolean NOT NULL DEFAULT false,
+			auto_provision boolean NOT NULL DEFAULT false,
+			enabled boolean NOT NULL DEFAULT true,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			updated_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id),
+			UNIQUE (organization_id, slug)
+		);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewCreateIdentityProvidersTableMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "create-identity-providers-table",
+		Up:   CreateIdentityProvidersTable,
+	}
+}
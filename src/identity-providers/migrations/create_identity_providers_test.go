@@ -0,0 +1,131 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateIdentityProvidersTable_FailExec(t *testing.T) {
+	migration := NewCreateIdentityProvidersTableMigration()
+	assert.Equal(t, migration.Name, "create-identity-providers-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS identity_providers(
+			id uuid NOT NULL,
+			organization_id uuid NOT NULL REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			slug varchar(64) NOT NULL,
+			name varchar(255) NOT NULL DEFAULT '',
+			issuer text NOT NULL,
+			client_id text NOT NULL,
+			client_secret text NOT NULL DEFAULT '',
+			redirect_url text NOT NULL,
+			scopes text NOT NULL DEFAULT '',
+			role_claim varchar(255) NOT NULL DEFAULT '',
+			role_mapping jsonb NOT NULL DEFAULT '{}',
+			default_role varchar(255) NOT NULL DEFAULT '',
+			link_by_email boolean NOT NULL DEFAULT false,
+			auto_provision boolean NOT NULL DEFAULT false,
+			enabled boolean NOT NULL DEFAULT true,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			updated_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id),
+			UNIQUE (organization_id, slug)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestCreateIdentityProvidersTable_TimeoutReached(t *testing.T) {
+	migration := NewCreateIdentityProvidersTableMigration()
+	assert.Equal(t, migration.Name, "create-identity-providers-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS identity_providers(
+			id uuid NOT NULL,
+			organization_id uuid NOT NULL REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			slug varchar(64) NOT NULL,
+			name varchar(255) NOT NULL DEFAULT '',
+			issuer text NOT NULL,
+			client_id text NOT NULL,
+			client_secret text NOT NULL DEFAULT '',
+			redirect_url text NOT NULL,
+			scopes text NOT NULL DEFAULT '',
+			role_claim varchar(255) NOT NULL DEFAULT '',
+			role_mapping jsonb NOT NULL DEFAULT '{}',
+			default_role varchar(255) NOT NULL DEFAULT '',
+			link_by_email boolean NOT NULL DEFAULT false,
+			auto_provision boolean NOT NULL DEFAULT false,
+			enabled boolean NOT NULL DEFAULT true,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			updated_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id),
+			UNIQUE (organization_id, slug)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestCreateIdentityProvidersTable_Success(t *testing.T) {
+	migration := NewCreateIdentityProvidersTableMigration()
+	assert.Equal(t, migration.Name, "create-identity-providers-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS identity_providers(
+			id uuid NOT NULL,
+			organization_id uuid NOT NULL REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			slug varchar(64) NOT NULL,
+			name varchar(255) NOT NULL DEFAULT '',
+			issuer text NOT NULL,
+			client_id text NOT NULL,
+			client_secret text NOT NULL DEFAULT '',
+			redirect_url text NOT NULL,
+			scopes text NOT NULL DEFAULT '',
+			role_claim varchar(255) NOT NULL DEFAULT '',
+			role_mapping jsonb NOT NULL DEFAULT '{}',
+			default_role varchar(255) NOT NULL DEFAULT '',
+			link_by_email boolean NOT NULL DEFAULT false,
+			auto_provision boolean NOT NULL DEFAULT false,
+			enabled boolean NOT NULL DEFAULT true,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			updated_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id),
+			UNIQUE (organization_id, slug)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
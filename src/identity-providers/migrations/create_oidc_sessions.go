@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Creates the table keeping the logins in progress with the identity providers
+func CreateOIDCSessionsTable(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS oidc_sessions(
+			id uuid NOT NULL,
+			provider_id uuid NOT NULL REFERENCES identity_providers(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			nonce varchar(255) NOT NULL,
+			code_verifier varchar(255) NOT NULL,
+			expires_at timestamptz NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewCreateOIDCSessionsTableMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "create-oidc-sessions-table",
+		Up:   CreateOIDCSessionsTable,
+	}
+}
@@ -0,0 +1,95 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateOIDCSessionsTable_FailExec(t *testing.T) {
+	migration := NewCreateOIDCSessionsTableMigration()
+	assert.Equal(t, migration.Name, "create-oidc-sessions-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS oidc_sessions(
+			id uuid NOT NULL,
+			provider_id uuid NOT NULL REFERENCES identity_providers(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			nonce varchar(255) NOT NULL,
+			code_verifier varchar(255) NOT NULL,
+			expires_at timestamptz NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestCreateOIDCSessionsTable_TimeoutReached(t *testing.T) {
+	migration := NewCreateOIDCSessionsTableMigration()
+	assert.Equal(t, migration.Name, "create-oidc-sessions-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS oidc_sessions(
+			id uuid NOT NULL,
+			provider_id uuid NOT NULL REFERENCES identity_providers(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			nonce varchar(255) NOT NULL,
+			code_verifier varchar(255) NOT NULL,
+			expires_at timestamptz NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestCreateOIDCSessionsTable_Success(t *testing.T) {
+	migration := NewCreateOIDCSessionsTableMigration()
+	assert.Equal(t, migration.Name, "create-oidc-sessions-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS oidc_sessions(
+			id uuid NOT NULL,
+			provider_id uuid NOT NULL REFERENCES identity_providers(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			nonce varchar(255) NOT NULL,
+			code_verifier varchar(255) NOT NULL,
+			expires_at timestamptz NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
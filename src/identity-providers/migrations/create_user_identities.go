@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Creates the table linking the users to their accounts on the identity providers
+func CreateUserIdentitiesTable(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS user_identities(
+			id uuid NOT NULL,
+			provider_id uuid NOT NULL REFERENCES identity_providers(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			subject varchar(255) NOT NULL,
+			email varchar(255) NOT NULL DEFAULT '',
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id),
+			UNIQUE (provider_id, subject)
+		);
+		CREATE INDEX IF NOT EXISTS user_identities_user_id_idx ON user_identities (user_id);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewCreateUserIdentitiesTableMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "create-user-identities-table",
+		Up:   CreateUserIdentitiesTable,
+	}
+}
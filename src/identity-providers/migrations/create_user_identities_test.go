@@ -0,0 +1,101 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateUserIdentitiesTable_FailExec(t *testing.T) {
+	migration := NewCreateUserIdentitiesTableMigration()
+	assert.Equal(t, migration.Name, "create-user-identities-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS user_identities(
+			id uuid NOT NULL,
+			provider_id uuid NOT NULL REFERENCES identity_providers(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			subject varchar(255) NOT NULL,
+			email varchar(255) NOT NULL DEFAULT '',
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id),
+			UNIQUE (provider_id, subject)
+		);
+		CREATE INDEX IF NOT EXISTS user_identities_user_id_idx ON user_identities (user_id);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestCreateUserIdentitiesTable_TimeoutReached(t *testing.T) {
+	migration := NewCreateUserIdentitiesTableMigration()
+	assert.Equal(t, migration.Name, "create-user-identities-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS user_identities(
+			id uuid NOT NULL,
+			provider_id uuid NOT NULL REFERENCES identity_providers(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			subject varchar(255) NOT NULL,
+			email varchar(255) NOT NULL DEFAULT '',
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id),
+			UNIQUE (provider_id, subject)
+		);
+		CREATE INDEX IF NOT EXISTS user_identities_user_id_idx ON user_identities (user_id);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestCreateUserIdentitiesTable_Success(t *testing.T) {
+	migration := NewCreateUserIdentitiesTableMigration()
+	assert.Equal(t, migration.Name, "create-user-identities-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS user_identities(
+			id uuid NOT NULL,
+			provider_id uuid NOT NULL REFERENCES identity_providers(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			subject varchar(255) NOT NULL,
+			email varchar(255) NOT NULL DEFAULT '',
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id),
+			UNIQUE (provider_id, subject)
+		);
+		CREATE INDEX IF NOT EXISTS user_identities_user_id_idx ON user_identities (user_id);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,236 @@
+// Package mockidp implements an OpenID Connect provider in memory, so the federated
+// logins can be driven in tests without any real identity provider.
+//
+// It serves the discovery document, the keys and the token endpoint of the authorization
+// code flow over an httptest server. The login page is replaced by Authorize, which answers
+// an authorization URL with the code the provider would redirect the user back with.
+package mockidp
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+)
+
+// Login of a user waiting for its code to be exchanged.
+type authorization struct {
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	claims        map[string]interface{}
+}
+
+// Provider with a single client, signing its ID tokens with RS256.
+type Provider struct {
+	Server       *httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	mu             sync.Mutex
+	key            *rsa.PrivateKey
+	keyID          string
+	authorizations map[string]authorization
+	requests       map[string]int
+}
+
+// Starts a provider for a client. It must be closed once done.
+func New(clientID string, clientSecret string) (*Provider, error) {
+	p := &Provider{
+		ClientID:       clientID,
+		ClientSecret:   clientSecret,
+		authorizations: map[string]authorization{},
+		requests:       map[string]int{},
+	}
+	if err := p.RotateKey(); err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.count(p.discovery))
+	mux.HandleFunc("/jwks", p.count(p.jwks))
+	mux.HandleFunc("/token", p.count(p.token))
+	p.Server = httptest.NewServer(mux)
+	return p, nil
+}
+
+func (p *Provider) Close() {
+	p.Server.Close()
+}
+
+// Issuer of the ID tokens, which is also where the discovery document is.
+func (p *Provider) Issuer() string {
+	return p.Server.URL
+}
+
+// Replaces the signing key, as providers do from time to time.
+func (p *Provider) RotateKey() error {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.key = key
+	p.keyID = uuid.NewString()
+	return nil
+}
+
+// Times a path of the provider was requested.
+func (p *Provider) Requests(path string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.requests[path]
+}
+
+// Logs a user in on the authorization URL of a relying party, returning the code
+// it is redirected back with. The claims are added to the ID token, replacing the
+// standard ones, so tests can also send tokens a relying party must reject.
+func (p *Provider) Authorize(authorizationURL string, claims map[string]interface{}) (string, error) {
+	parsed, err := url.Parse(authorizationURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := parsed.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != p.ClientID {
+		return "", errors.New("invalid authorization request")
+	}
+	if query.Get("code_challenge_method") != "S256" || len(query.Get("code_challenge")) == 0 {
+		return "", errors.New("PKCE is required")
+	}
+
+	code := uuid.NewString()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.authorizations[code] = authorization{
+		redirectURI:   query.Get("redirect_uri"),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		claims:        claims,
+	}
+	return code, nil
+}
+
+// Signs an ID token with the current key of the provider.
+func (p *Provider) SignIDToken(claims jwt.MapClaims) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = p.keyID
+	return token.SignedString(p.key)
+}
+
+// Wraps a handler, counting its requests.
+func (p *Provider) count(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		p.requests[r.URL.Path]++
+		p.mu.Unlock()
+		handler(w, r)
+	}
+}
+
+func (p *Provider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.Issuer(),
+		"authorization_endpoint":                p.Issuer() + "/authorize",
+		"token_endpoint":                        p.Issuer() + "/token",
+		"jwks_uri":                              p.Issuer() + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (p *Provider) jwks(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": p.keyID,
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}},
+	})
+}
+
+// Exchanges a code for the tokens, authenticating the client with HTTP basic authentication.
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	}
+	if !ok || clientID != p.ClientID || clientSecret != p.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	p.mu.Lock()
+	pending, found := p.authorizations[r.PostForm.Get("code")]
+	delete(p.authorizations, r.PostForm.Get("code"))
+	p.mu.Unlock()
+
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if r.PostForm.Get("grant_type") != "authorization_code" || !found ||
+		pending.redirectURI != r.PostForm.Get("redirect_uri") ||
+		pending.codeChallenge != base64.RawURLEncoding.EncodeToString(verifier[:]) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   p.Issuer(),
+		"sub":   uuid.NewString(),
+		"aud":   p.ClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": pending.nonce,
+	}
+	for name, value := range pending.claims {
+		claims[name] = value
+	}
+
+	idToken, err := p.SignIDToken(claims)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": uuid.NewString(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets and deletes a login session, so its state can only be used once.
+// Returns sql.ErrNoRows when there's no such session.
+func (r PostgresRepository) ConsumeSession(ctx context.Context, id uuid.UUID) (domain.OIDCSession, error) {
+	query := `
+		DELETE FROM oidc_sessions
+		WHERE id = $1
+		RETURNING id, provider_id, nonce, code_verifier, expires_at
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.OIDCSession{}, err
+	}
+
+	result := domain.OIDCSession{}
+	err = stmt.QueryRowContext(ctx, id).Scan(
+		&result.ID,
+		&result.ProviderID,
+		&result.Nonce,
+		&result.CodeVerifier,
+		&result.ExpiresAt,
+	)
+	if err != nil {
+		return domain.OIDCSession{}, err
+	}
+	return result, nil
+}
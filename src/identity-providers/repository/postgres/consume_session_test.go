@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const consumeSessionQuery = `
+		DELETE FROM oidc_sessions
+		WHERE id = $1
+		RETURNING id, provider_id, nonce, code_verifier, expires_at
+	`
+
+func TestConsumeSession_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(consumeSessionQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).ConsumeSession(context.TODO(), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestConsumeSession_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(consumeSessionQuery)).
+		ExpectQuery().
+		WithArgs(id).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).ConsumeSession(ctx, id)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestConsumeSession_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(consumeSessionQuery)).
+		ExpectQuery().
+		WithArgs(id).
+		WillReturnError(sql.ErrNoRows)
+
+	res, err := New(db).ConsumeSession(context.TODO(), id)
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Empty(t, res)
+}
+
+func TestConsumeSession_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	providerID := uuid.New()
+	expiresAt := time.Now().Add(time.Minute)
+	rows := sqlmock.NewRows([]string{"id", "provider_id", "nonce", "code_verifier", "expires_at"}).
+		AddRow(id, providerID, "nonce", "verifier", expiresAt)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(consumeSessionQuery)).
+		ExpectQuery().
+		WithArgs(id).
+		WillReturnRows(rows)
+
+	res, err := New(db).ConsumeSession(context.TODO(), id)
+	assert.Nil(t, err)
+	assert.Equal(t, id, res.ID)
+	assert.Equal(t, providerID, res.ProviderID)
+	assert.Equal(t, "nonce", res.Nonce)
+	assert.Equal(t, "verifier", res.CodeVerifier)
+	assert.Equal(t, expiresAt, res.ExpiresAt)
+	assert.False(t, res.IsExpired())
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Deletes an identity provider, along with its sessions and the links of the users to it.
+func (r PostgresRepository) Delete(ctx context.Context, slug string) error {
+	stmt, err := r.Db.PrepareContext(ctx, `DELETE FROM identity_providers WHERE slug = $1 AND organization_id = $2`)
+	if err != nil {
+		return err
+	}
+
+	result, err := stmt.ExecContext(ctx, slug, domain.OrganizationFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	return requireAffectedRows(result)
+}
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets all the identity providers of the organization, by slug.
+func (r PostgresRepository) Fetch(ctx context.Context) ([]domain.IdentityProvider, error) {
+	result := make([]domain.IdentityProvider, 0)
+
+	query := `
+		SELECT id, slug, name, issuer, client_id, client_secret, redirect_url, scopes, role_claim,
+			role_mapping, default_role, link_by_email, auto_provision, enabled, created_at, updated_at
+		FROM identity_providers
+		WHERE organization_id = $1
+		ORDER BY slug
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return result, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, domain.OrganizationFromContext(ctx))
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		provider, err := r.scanProviderRow(rows)
+		if err != nil {
+			return make([]domain.IdentityProvider, 0), err
+		}
+		result = append(result, provider)
+	}
+	return result, rows.Err()
+}
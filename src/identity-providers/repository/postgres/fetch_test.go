@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const fetchQuery = `
+		SELECT id, slug, name, issuer, client_id, client_secret, redirect_url, scopes, role_claim,
+			role_mapping, default_role, link_by_email, auto_provision, enabled, created_at, updated_at
+		FROM identity_providers
+		WHERE organization_id = $1
+		ORDER BY slug
+	`
+
+func TestFetch_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(fetchQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).Fetch(context.TODO())
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestFetch_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(fetchQuery)).
+		ExpectQuery().
+		WithArgs(domain.DefaultOrganizationID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).Fetch(ctx)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestFetch_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id, createdAt := uuid.New(), time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(fetchQuery)).
+		ExpectQuery().
+		WithArgs(domain.DefaultOrganizationID).
+		WillReturnRows(addProviderRow(sqlmock.NewRows(providerRowColumns), id, createdAt))
+
+	res, err := New(db).Fetch(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.IdentityProvider{expectedProvider(id, createdAt)}, res)
+}
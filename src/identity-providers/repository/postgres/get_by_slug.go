@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets an identity provider of the organization by its slug.
+// Returns sql.ErrNoRows when there's no such provider.
+func (r PostgresRepository) GetBySlug(ctx context.Context, slug string) (domain.IdentityProvider, error) {
+	query := `
+		SELECT id, slug, name, issuer, client_id, client_secret, redirect_url, scopes, role_claim,
+			role_mapping, default_role, link_by_email, auto_provision, enabled, created_at, updated_at
+		FROM identity_providers
+		WHERE slug = $1 AND organization_id = $2
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.IdentityProvider{}, err
+	}
+
+	return r.scanProviderRow(stmt.QueryRowContext(ctx, slug, domain.OrganizationFromContext(ctx)))
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const getBySlugQuery = `
+		SELECT id, slug, name, issuer, client_id, client_secret, redirect_url, scopes, role_claim,
+			role_mapping, default_role, link_by_email, auto_provision, enabled, created_at, updated_at
+		FROM identity_providers
+		WHERE slug = $1 AND organization_id = $2
+	`
+
+func TestGetBySlug_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getBySlugQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).GetBySlug(context.TODO(), "corporate")
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestGetBySlug_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getBySlugQuery)).
+		ExpectQuery().
+		WithArgs("corporate", domain.DefaultOrganizationID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).GetBySlug(ctx, "corporate")
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestGetBySlug_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getBySlugQuery)).
+		ExpectQuery().
+		WithArgs("corporate", domain.DefaultOrganizationID).
+		WillReturnError(sql.ErrNoRows)
+
+	res, err := New(db).GetBySlug(context.TODO(), "corporate")
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Empty(t, res)
+}
+
+func TestGetBySlug_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id, createdAt := uuid.New(), time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(getBySlugQuery)).
+		ExpectQuery().
+		WithArgs("corporate", domain.DefaultOrganizationID).
+		WillReturnRows(addProviderRow(sqlmock.NewRows(providerRowColumns), id, createdAt))
+
+	res, err := New(db).GetBySlug(context.TODO(), "corporate")
+	assert.Nil(t, err)
+	assert.Equal(t, expectedProvider(id, createdAt), res)
+}
@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets an identity provider of the organization by its ID.
+// Returns sql.ErrNoRows when there's no such provider.
+func (r PostgresRepository) GetByUUID(ctx context.Context, id uuid.UUID) (domain.IdentityProvider, error) {
+	query := `
+		SELECT id, slug, name, issuer, client_id, client_secret, redirect_url, scopes, role_claim,
+			role_mapping, default_role, link_by_email, auto_provision, enabled, created_at, updated_at
+		FROM identity_providers
+		WHERE id = $1 AND organization_id = $2
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.IdentityProvider{}, err
+	}
+
+	return r.scanProviderRow(stmt.QueryRowContext(ctx, id, domain.OrganizationFromContext(ctx)))
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets the upstream accounts linked to a user, oldest first.
+func (r PostgresRepository) GetIdentitiesByUser(ctx context.Context, userID uuid.UUID) ([]domain.ExternalIdentity, error) {
+	result := make([]domain.ExternalIdentity, 0)
+
+	query := `
+		SELECT id, provider_id, user_id, subject, email, created_at
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at, id
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return result, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, userID)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		identity := domain.ExternalIdentity{}
+		err = rows.Scan(
+			&identity.ID,
+			&identity.ProviderID,
+			&identity.UserID,
+			&identity.Subject,
+			&identity.Email,
+			&identity.CreatedAt,
+		)
+		if err != nil {
+			return make([]domain.ExternalIdentity, 0), err
+		}
+		result = append(result, identity)
+	}
+	return result, rows.Err()
+}
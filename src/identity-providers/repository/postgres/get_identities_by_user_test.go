@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const getIdentitiesByUserQuery = `
+		SELECT id, provider_id, user_id, subject, email, created_at
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at, id
+	`
+
+func TestGetIdentitiesByUser_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getIdentitiesByUserQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).GetIdentitiesByUser(context.TODO(), uuid.New())
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestGetIdentitiesByUser_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(getIdentitiesByUserQuery)).
+		ExpectQuery().
+		WithArgs(userID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).GetIdentitiesByUser(ctx, userID)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestGetIdentitiesByUser_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id, providerID, userID, createdAt := uuid.New(), uuid.New(), uuid.New(), time.Now()
+	rows := sqlmock.NewRows([]string{"id", "provider_id", "user_id", "subject", "email", "created_at"}).
+		AddRow(id, providerID, userID, "subject", "staff@example.com", createdAt)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getIdentitiesByUserQuery)).
+		ExpectQuery().
+		WithArgs(userID).
+		WillReturnRows(rows)
+
+	res, err := New(db).GetIdentitiesByUser(context.TODO(), userID)
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.ExternalIdentity{{
+		ID:         id,
+		ProviderID: providerID,
+		UserID:     userID,
+		Subject:    "subject",
+		Email:      "staff@example.com",
+		CreatedAt:  createdAt,
+	}}, res)
+}
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets the link of an upstream account to its local user.
+// Returns sql.ErrNoRows when the account isn't linked to any user.
+func (r PostgresRepository) GetIdentity(ctx context.Context, providerID uuid.UUID, subject string) (domain.ExternalIdentity, error) {
+	query := `
+		SELECT id, provider_id, user_id, subject, email, created_at
+		FROM user_identities
+		WHERE provider_id = $1 AND subject = $2
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.ExternalIdentity{}, err
+	}
+
+	result := domain.ExternalIdentity{}
+	err = stmt.QueryRowContext(ctx, providerID, subject).Scan(
+		&result.ID,
+		&result.ProviderID,
+		&result.UserID,
+		&result.Subject,
+		&result.Email,
+		&result.CreatedAt,
+	)
+	if err != nil {
+		return domain.ExternalIdentity{}, err
+	}
+	return result, nil
+}
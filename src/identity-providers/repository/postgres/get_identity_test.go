@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const getIdentityQuery = `
+		SELECT id, provider_id, user_id, subject, email, created_at
+		FROM user_identities
+		WHERE provider_id = $1 AND subject = $2
+	`
+
+func TestGetIdentity_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getIdentityQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).GetIdentity(context.TODO(), uuid.New(), "subject")
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestGetIdentity_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	providerID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(getIdentityQuery)).
+		ExpectQuery().
+		WithArgs(providerID, "subject").
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).GetIdentity(ctx, providerID, "subject")
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestGetIdentity_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	providerID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(getIdentityQuery)).
+		ExpectQuery().
+		WithArgs(providerID, "subject").
+		WillReturnError(sql.ErrNoRows)
+
+	res, err := New(db).GetIdentity(context.TODO(), providerID, "subject")
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Empty(t, res)
+}
+
+func TestGetIdentity_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id, providerID, userID, createdAt := uuid.New(), uuid.New(), uuid.New(), time.Now()
+	rows := sqlmock.NewRows([]string{"id", "provider_id", "user_id", "subject", "email", "created_at"}).
+		AddRow(id, providerID, userID, "subject", "staff@example.com", createdAt)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getIdentityQuery)).
+		ExpectQuery().
+		WithArgs(providerID, "subject").
+		WillReturnRows(rows)
+
+	res, err := New(db).GetIdentity(context.TODO(), providerID, "subject")
+	assert.Nil(t, err)
+	assert.Equal(t, domain.ExternalIdentity{
+		ID:         id,
+		ProviderID: providerID,
+		UserID:     userID,
+		Subject:    "subject",
+		Email:      "staff@example.com",
+		CreatedAt:  createdAt,
+	}, res)
+}
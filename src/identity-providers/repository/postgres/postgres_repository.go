@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Postgres error code for unique constraint violations
+const uniqueViolationCode = "23505"
+
+type PostgresRepository struct {
+	Db *sql.DB
+}
+
+func New(db *sql.DB) domain.IdentityProviderRepository {
+	return PostgresRepository{db}
+}
+
+// Row of a single or multi row query
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans an identity provider row
+func (r PostgresRepository) scanProviderRow(row rowScanner) (domain.IdentityProvider, error) {
+	result := domain.IdentityProvider{}
+	var scopes string
+	var roleMapping []byte
+
+	err := row.Scan(
+		&result.ID,
+		&result.Slug,
+		&result.Name,
+		&result.Issuer,
+		&result.ClientID,
+		&result.ClientSecret,
+		&result.RedirectURL,
+		&scopes,
+		&result.RoleClaim,
+		&roleMapping,
+		&result.DefaultRole,
+		&result.LinkByEmail,
+		&result.AutoProvision,
+		&result.Enabled,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	)
+	if err != nil {
+		return domain.IdentityProvider{}, err
+	}
+
+	result.Scopes = strings.Fields(scopes)
+	if err = json.Unmarshal(roleMapping, &result.RoleMapping); err != nil {
+		return domain.IdentityProvider{}, err
+	}
+	return result, nil
+}
+
+// Returns domain.ErrNotFound when a statement didn't affect any row.
+func requireAffectedRows(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type anyTime struct{}
+
+// Match satisfies sqlmock.Argument interface
+func (a anyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+// Columns of the identity provider rows
+var providerRowColumns = []string{
+	"id", "slug", "name", "issuer", "client_id", "client_secret", "redirect_url", "scopes", "role_claim",
+	"role_mapping", "default_role", "link_by_email", "auto_provision", "enabled", "created_at", "updated_at",
+}
+
+// Row of the provider used by the tests
+func addProviderRow(rows *sqlmock.Rows, id uuid.UUID, createdAt time.Time) *sqlmock.Rows {
+	return rows.AddRow(id, "corporate", "Corporate", "https://idp.example.com", "users-service", "secret",
+		"https://app.example.com/callback", "email profile", "groups", []byte(`{"staff":"user"}`), "user",
+		true, true, true, createdAt, createdAt)
+}
+
+// Provider of the row used by the tests
+func expectedProvider(id uuid.UUID, createdAt time.Time) domain.IdentityProvider {
+	return domain.IdentityProvider{
+		ID:            id,
+		Slug:          "corporate",
+		Name:          "Corporate",
+		Issuer:        "https://idp.example.com",
+		ClientID:      "users-service",
+		ClientSecret:  "secret",
+		RedirectURL:   "https://app.example.com/callback",
+		Scopes:        []string{"email", "profile"},
+		RoleClaim:     "groups",
+		RoleMapping:   map[string]string{"staff": "user"},
+		DefaultRole:   "user",
+		LinkByEmail:   true,
+		AutoProvision: true,
+		Enabled:       true,
+		CreatedAt:     createdAt,
+		UpdatedAt:     createdAt,
+	}
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Creates an identity provider, or updates it when the organization has one with the same slug.
+func (r PostgresRepository) Save(ctx context.Context, provider domain.IdentityProvider) (domain.IdentityProvider, error) {
+	roleMapping := provider.RoleMapping
+	if roleMapping == nil {
+		roleMapping = map[string]string{}
+	}
+	encodedRoleMapping, err := json.Marshal(roleMapping)
+	if err != nil {
+		return domain.IdentityProvider{}, err
+	}
+
+	query := `
+		INSERT INTO identity_providers (id, organization_id, slug, name, issuer, client_id, client_secret, redirect_url,
+			scopes, role_claim, role_mapping, default_role, link_by_email, auto_provision, enabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $16)
+		ON CONFLICT (organization_id, slug) DO UPDATE
+		SET name = EXCLUDED.name, issuer = EXCLUDED.issuer, client_id = EXCLUDED.client_id, client_secret = EXCLUDED.client_secret,
+			redirect_url = EXCLUDED.redirect_url, scopes = EXCLUDED.scopes, role_claim = EXCLUDED.role_claim,
+			role_mapping = EXCLUDED.role_mapping, default_role = EXCLUDED.default_role, link_by_email = EXCLUDED.link_by_email,
+			auto_provision = EXCLUDED.auto_provision, enabled = EXCLUDED.enabled, updated_at = EXCLUDED.updated_at
+		RETURNING id, slug, name, issuer, client_id, client_secret, redirect_url, scopes, role_claim,
+			role_mapping, default_role, link_by_email, auto_provision, enabled, created_at, updated_at
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.IdentityProvider{}, err
+	}
+
+	row := stmt.QueryRowContext(ctx,
+		uuid.New(),
+		domain.OrganizationFromContext(ctx),
+		provider.Slug,
+		provider.Name,
+		provider.Issuer,
+		provider.ClientID,
+		provider.ClientSecret,
+		provider.RedirectURL,
+		strings.Join(provider.Scopes, " "),
+		provider.RoleClaim,
+		encodedRoleMapping,
+		provider.DefaultRole,
+		provider.LinkByEmail,
+		provider.AutoProvision,
+		provider.Enabled,
+		time.Now(),
+	)
+	return r.scanProviderRow(row)
+}
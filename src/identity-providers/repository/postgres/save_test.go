@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const saveQuery = `
+		INSERT INTO identity_providers (id, organization_id, slug, name, issuer, client_id, client_secret, redirect_url,
+			scopes, role_claim, role_mapping, default_role, link_by_email, auto_provision, enabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $16)
+		ON CONFLICT (organization_id, slug) DO UPDATE
+		SET name = EXCLUDED.name, issuer = EXCLUDED.issuer, client_id = EXCLUDED.client_id, client_secret = EXCLUDED.client_secret,
+			redirect_url = EXCLUDED.redirect_url, scopes = EXCLUDED.scopes, role_claim = EXCLUDED.role_claim,
+			role_mapping = EXCLUDED.role_mapping, default_role = EXCLUDED.default_role, link_by_email = EXCLUDED.link_by_email,
+			auto_provision = EXCLUDED.auto_provision, enabled = EXCLUDED.enabled, updated_at = EXCLUDED.updated_at
+		RETURNING id, slug, name, issuer, client_id, client_secret, redirect_url, scopes, role_claim,
+			role_mapping, default_role, link_by_email, auto_provision, enabled, created_at, updated_at
+	`
+
+// Arguments of saving the provider used by the tests
+func saveArgs() []driver.Value {
+	return []driver.Value{
+		sqlmock.AnyArg(), domain.DefaultOrganizationID, "corporate", "Corporate", "https://idp.example.com", "users-service", "secret",
+		"https://app.example.com/callback", "email profile", "groups", []byte(`{"staff":"user"}`), "user", true, true, true, anyTime{},
+	}
+}
+
+func TestSave_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(saveQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).Save(context.TODO(), expectedProvider(uuid.Nil, time.Time{}))
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestSave_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(saveQuery)).
+		ExpectQuery().
+		WithArgs(saveArgs()...).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).Save(ctx, expectedProvider(uuid.Nil, time.Time{}))
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestSave_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id, createdAt := uuid.New(), time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(saveQuery)).
+		ExpectQuery().
+		WithArgs(saveArgs()...).
+		WillReturnRows(addProviderRow(sqlmock.NewRows(providerRowColumns), id, createdAt))
+
+	res, err := New(db).Save(context.TODO(), expectedProvider(uuid.Nil, time.Time{}))
+	assert.Nil(t, err)
+	assert.Equal(t, expectedProvider(id, createdAt), res)
+}
+
+func TestSave_WithoutRoleMapping(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	provider := expectedProvider(uuid.Nil, time.Time{})
+	provider.RoleMapping = nil
+	args := saveArgs()
+	args[10] = []byte(`{}`)
+
+	id, createdAt := uuid.New(), time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(saveQuery)).
+		ExpectQuery().
+		WithArgs(args...).
+		WillReturnRows(addProviderRow(sqlmock.NewRows(providerRowColumns), id, createdAt))
+
+	_, err = New(db).Save(context.TODO(), provider)
+	assert.Nil(t, err)
+}
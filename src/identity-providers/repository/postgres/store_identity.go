@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Links an upstream account to a local user.
+// domain.ErrAlreadyExists is returned when the account is already linked.
+func (r PostgresRepository) StoreIdentity(ctx context.Context, identity domain.ExternalIdentity) (domain.ExternalIdentity, error) {
+	query := `
+		INSERT INTO user_identities (id, provider_id, user_id, subject, email, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.ExternalIdentity{}, err
+	}
+
+	identity.ID = uuid.New()
+	identity.CreatedAt = time.Now()
+	_, err = stmt.ExecContext(ctx,
+		identity.ID,
+		identity.ProviderID,
+		identity.UserID,
+		identity.Subject,
+		identity.Email,
+		identity.CreatedAt,
+	)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+		return domain.ExternalIdentity{}, domain.ErrAlreadyExists
+	}
+	if err != nil {
+		return domain.ExternalIdentity{}, err
+	}
+	return identity, nil
+}
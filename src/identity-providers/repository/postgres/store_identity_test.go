@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const storeIdentityQuery = `
+		INSERT INTO user_identities (id, provider_id, user_id, subject, email, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+func TestStoreIdentity_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(storeIdentityQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).StoreIdentity(context.TODO(), domain.ExternalIdentity{ProviderID: uuid.New(), UserID: uuid.New(), Subject: "subject"})
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestStoreIdentity_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	identity := domain.ExternalIdentity{ProviderID: uuid.New(), UserID: uuid.New(), Subject: "subject"}
+	mock.ExpectPrepare(regexp.QuoteMeta(storeIdentityQuery)).
+		ExpectExec().
+		WithArgs(sqlmock.AnyArg(), identity.ProviderID, identity.UserID, "subject", "", anyTime{}).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).StoreIdentity(ctx, identity)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestStoreIdentity_AlreadyLinked(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	identity := domain.ExternalIdentity{ProviderID: uuid.New(), UserID: uuid.New(), Subject: "subject"}
+	mock.ExpectPrepare(regexp.QuoteMeta(storeIdentityQuery)).
+		ExpectExec().
+		WithArgs(sqlmock.AnyArg(), identity.ProviderID, identity.UserID, "subject", "", anyTime{}).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	res, err := New(db).StoreIdentity(context.TODO(), identity)
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+	assert.Empty(t, res)
+}
+
+func TestStoreIdentity_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	identity := domain.ExternalIdentity{ProviderID: uuid.New(), UserID: uuid.New(), Subject: "subject", Email: "staff@example.com"}
+	mock.ExpectPrepare(regexp.QuoteMeta(storeIdentityQuery)).
+		ExpectExec().
+		WithArgs(sqlmock.AnyArg(), identity.ProviderID, identity.UserID, "subject", "staff@example.com", anyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res, err := New(db).StoreIdentity(context.TODO(), identity)
+	assert.Nil(t, err)
+	assert.NotEqual(t, uuid.Nil, res.ID)
+	assert.Equal(t, identity.UserID, res.UserID)
+	assert.False(t, res.CreatedAt.IsZero())
+}
@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Stores the state of a login until the user comes back from the provider.
+func (r PostgresRepository) StoreSession(ctx context.Context, session domain.OIDCSession) error {
+	query := `
+		INSERT INTO oidc_sessions (id, provider_id, nonce, code_verifier, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx,
+		session.ID,
+		session.ProviderID,
+		session.Nonce,
+		session.CodeVerifier,
+		session.ExpiresAt,
+		time.Now(),
+	)
+	return err
+}
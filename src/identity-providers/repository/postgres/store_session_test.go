@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const storeSessionQuery = `
+		INSERT INTO oidc_sessions (id, provider_id, nonce, code_verifier, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+func TestStoreSession_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(storeSessionQuery)).WillReturnError(errors.New("boom"))
+
+	err = New(db).StoreSession(context.TODO(), domain.OIDCSession{ID: uuid.New()})
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestStoreSession_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	session := domain.OIDCSession{
+		ID:           uuid.New(),
+		ProviderID:   uuid.New(),
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		ExpiresAt:    time.Now().Add(time.Minute),
+	}
+	mock.ExpectPrepare(regexp.QuoteMeta(storeSessionQuery)).
+		ExpectExec().
+		WithArgs(session.ID, session.ProviderID, "nonce", "verifier", session.ExpiresAt, anyTime{}).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = New(db).StoreSession(ctx, session)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestStoreSession_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	session := domain.OIDCSession{
+		ID:           uuid.New(),
+		ProviderID:   uuid.New(),
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		ExpiresAt:    time.Now().Add(time.Minute),
+	}
+	mock.ExpectPrepare(regexp.QuoteMeta(storeSessionQuery)).
+		ExpectExec().
+		WithArgs(session.ID, session.ProviderID, "nonce", "verifier", session.ExpiresAt, anyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = New(db).StoreSession(context.TODO(), session)
+	assert.Nil(t, err)
+}
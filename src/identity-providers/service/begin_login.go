@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Starts a login with an identity provider of the organization.
+// The user has to be sent to the authorization URL, and comes back to the redirect URL
+// of the provider with the state and a code to finish the login with.
+// Unknown and disabled providers fail with domain.ErrNotFound.
+func (s DefaultOIDCService) BeginLogin(ctx context.Context, providerSlug string) (domain.OIDCAuthorization, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	provider, err := s.ProviderRepo.GetBySlug(ctx, providerSlug)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !provider.Enabled) {
+		return domain.OIDCAuthorization{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.OIDCAuthorization{}, err
+	}
+
+	metadata, err := s.Discovery.metadata(ctx, provider.Issuer)
+	if err != nil {
+		s.Logger.Printf("error discovering the identity provider %v: %v\n", provider.Slug, err)
+		return domain.OIDCAuthorization{}, err
+	}
+
+	nonce, err := generateRandom()
+	if err != nil {
+		return domain.OIDCAuthorization{}, err
+	}
+	codeVerifier, err := generateRandom()
+	if err != nil {
+		return domain.OIDCAuthorization{}, err
+	}
+
+	session := domain.OIDCSession{
+		ID:           uuid.New(),
+		ProviderID:   provider.ID,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    time.Now().Add(s.Settings.SessionTTL),
+	}
+	if err = s.ProviderRepo.StoreSession(ctx, session); err != nil {
+		return domain.OIDCAuthorization{}, err
+	}
+
+	authorizationURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return domain.OIDCAuthorization{}, err
+	}
+	query := authorizationURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", provider.ClientID)
+	query.Set("redirect_uri", provider.RedirectURL)
+	query.Set("scope", scope(provider))
+	query.Set("state", session.ID.String())
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+	authorizationURL.RawQuery = query.Encode()
+
+	return domain.OIDCAuthorization{AuthorizationURL: authorizationURL.String(), State: session.ID.String()}, nil
+}
+
+// Scope of the authorization requests of a provider, which always includes openid.
+func scope(provider domain.IdentityProvider) string {
+	scopes := []string{"openid"}
+	for _, scope := range provider.Scopes {
+		if scope != "openid" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return strings.Join(scopes, " ")
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBeginLogin_UnknownProvider(t *testing.T) {
+	providerRepo := new(mocks.IdentityProviderRepository)
+	providerRepo.On("GetBySlug", mock.Anything, "unknown").Once().Return(domain.IdentityProvider{}, sql.ErrNoRows)
+
+	_, err := newService(providerRepo, nil, nil, nil).BeginLogin(context.TODO(), "unknown")
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestBeginLogin_DisabledProvider(t *testing.T) {
+	providerRepo := new(mocks.IdentityProviderRepository)
+	providerRepo.On("GetBySlug", mock.Anything, "corporate").Once().Return(domain.IdentityProvider{Slug: "corporate"}, nil)
+
+	_, err := newService(providerRepo, nil, nil, nil).BeginLogin(context.TODO(), "corporate")
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestBeginLogin_ProviderUnavailable(t *testing.T) {
+	idp, provider := newIdentityProvider(t)
+	idp.Close()
+	providerRepo := new(mocks.IdentityProviderRepository)
+	providerRepo.On("GetBySlug", mock.Anything, "corporate").Once().Return(provider, nil)
+
+	_, err := newService(providerRepo, nil, nil, nil).BeginLogin(context.TODO(), "corporate")
+	assert.Error(t, err)
+	providerRepo.AssertNotCalled(t, "StoreSession", mock.Anything, mock.Anything)
+}
+
+func TestBeginLogin_ErrorStoringSession(t *testing.T) {
+	_, provider := newIdentityProvider(t)
+	providerRepo := new(mocks.IdentityProviderRepository)
+	providerRepo.On("GetBySlug", mock.Anything, "corporate").Once().Return(provider, nil)
+	providerRepo.On("StoreSession", mock.Anything, mock.Anything).Once().Return(errors.New("boom"))
+
+	_, err := newService(providerRepo, nil, nil, nil).BeginLogin(context.TODO(), "corporate")
+	assert.Equal(t, "boom", err.Error())
+}
+
+func TestBeginLogin_Success(t *testing.T) {
+	idp, provider := newIdentityProvider(t)
+	var session domain.OIDCSession
+	providerRepo := new(mocks.IdentityProviderRepository)
+	providerRepo.On("GetBySlug", mock.Anything, "corporate").Once().Return(provider, nil)
+	providerRepo.On("StoreSession", mock.Anything, mock.Anything).Once().Return(nil).Run(func(args mock.Arguments) {
+		session = args.Get(1).(domain.OIDCSession)
+	})
+
+	authorization, err := newService(providerRepo, nil, nil, nil).BeginLogin(context.TODO(), "corporate")
+	assert.Nil(t, err)
+	assert.Equal(t, session.ID.String(), authorization.State)
+	assert.Equal(t, provider.ID, session.ProviderID)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), session.ExpiresAt, time.Minute)
+
+	assert.True(t, strings.HasPrefix(authorization.AuthorizationURL, idp.Issuer()+"/authorize?"))
+	parsed, err := url.Parse(authorization.AuthorizationURL)
+	assert.Nil(t, err)
+	query := parsed.Query()
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "users-service", query.Get("client_id"))
+	assert.Equal(t, "https://app.example.com/callback", query.Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", query.Get("scope"))
+	assert.Equal(t, authorization.State, query.Get("state"))
+	assert.Equal(t, session.Nonce, query.Get("nonce"))
+	assert.Equal(t, codeChallenge(session.CodeVerifier), query.Get("code_challenge"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Discovery document of a provider, with the fields the relying party uses.
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Key of a JSON Web Key Set.
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+// Discovery document and signing keys of a provider, as fetched last.
+// Never changed once cached: refreshed keys are cached in a new entry.
+type discoveryEntry struct {
+	metadata  providerMetadata
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+// Fetch in progress, waited for by the callers asking for the same one meanwhile.
+type discoveryFetch struct {
+	done  chan struct{}
+	entry *discoveryEntry
+	err   error
+}
+
+// Limits the size of the discovery documents and key sets read from the providers.
+const maxDiscoveryDocumentSize = 1 << 20
+
+// Caches the discovery documents and keys of the providers by issuer, shared by every
+// copy of the service. Keys are fetched again before the TTL when a token is signed
+// with an unknown key, since providers rotate them. Every issuer is fetched once at a
+// time, without holding the lock, so a slow provider doesn't hold up the others.
+type discoveryCache struct {
+	mu      sync.Mutex
+	client  *http.Client
+	ttl     time.Duration
+	entries map[string]*discoveryEntry
+	fetches map[string]*discoveryFetch
+}
+
+func newDiscoveryCache(client *http.Client, ttl time.Duration) *discoveryCache {
+	return &discoveryCache{
+		client:  client,
+		ttl:     ttl,
+		entries: map[string]*discoveryEntry{},
+		fetches: map[string]*discoveryFetch{},
+	}
+}
+
+// Gets the discovery document of an issuer, fetching it with its keys when not cached.
+func (c *discoveryCache) metadata(ctx context.Context, issuer string) (providerMetadata, error) {
+	entry, err := c.entry(ctx, issuer)
+	if err != nil {
+		return providerMetadata{}, err
+	}
+	return entry.metadata, nil
+}
+
+// Gets the key of an issuer with an ID, fetching the keys again when it's unknown.
+func (c *discoveryCache) key(ctx context.Context, issuer string, keyID string) (interface{}, error) {
+	entry, err := c.entry(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+	if key, ok := entry.keys[keyID]; ok {
+		return key, nil
+	}
+
+	entry, err = c.fetchOnce(ctx, "keys "+issuer, func() (*discoveryEntry, error) {
+		keys, err := c.fetchKeys(ctx, entry.metadata.JWKSURI)
+		if err != nil {
+			return nil, err
+		}
+		return &discoveryEntry{metadata: entry.metadata, keys: keys, fetchedAt: entry.fetchedAt}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if key, ok := entry.keys[keyID]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", keyID)
+}
+
+// Gets the cached entry of an issuer, or fetches it when missing or expired.
+func (c *discoveryCache) entry(ctx context.Context, issuer string) (*discoveryEntry, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[issuer]
+	c.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < c.ttl {
+		return entry, nil
+	}
+
+	return c.fetchOnce(ctx, "discovery "+issuer, func() (*discoveryEntry, error) {
+		return c.fetchEntry(ctx, issuer)
+	})
+}
+
+// Runs a fetch of an issuer and caches its entry. The callers asking for the same fetch
+// while it runs wait for it instead of fetching again.
+func (c *discoveryCache) fetchOnce(ctx context.Context, name string, fetch func() (*discoveryEntry, error)) (*discoveryEntry, error) {
+	c.mu.Lock()
+	f, running := c.fetches[name]
+	if !running {
+		f = &discoveryFetch{done: make(chan struct{})}
+		c.fetches[name] = f
+	}
+	c.mu.Unlock()
+
+	if !running {
+		f.entry, f.err = fetch()
+
+		c.mu.Lock()
+		if f.err == nil {
+			c.entries[f.entry.metadata.Issuer] = f.entry
+		}
+		delete(c.fetches, name)
+		c.mu.Unlock()
+		close(f.done)
+	}
+
+	select {
+	case <-f.done:
+		return f.entry, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Fetches the discovery document of an issuer along with its keys.
+func (c *discoveryCache) fetchEntry(ctx context.Context, issuer string) (*discoveryEntry, error) {
+	metadata := providerMetadata{}
+	if err := c.getJSON(ctx, strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration", &metadata); err != nil {
+		return nil, err
+	}
+	// The document must be the one of the issuer, so another provider can't be impersonated.
+	if metadata.Issuer != issuer || len(metadata.AuthorizationEndpoint) == 0 ||
+		len(metadata.TokenEndpoint) == 0 || len(metadata.JWKSURI) == 0 {
+		return nil, fmt.Errorf("invalid discovery document of %q", issuer)
+	}
+
+	keys, err := c.fetchKeys(ctx, metadata.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	return &discoveryEntry{metadata: metadata, keys: keys, fetchedAt: time.Now()}, nil
+}
+
+// Fetches the signing keys of a provider, by ID. Keys of unknown types are skipped.
+func (c *discoveryCache) fetchKeys(ctx context.Context, jwksURI string) (map[string]interface{}, error) {
+	set := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+	if err := c.getJSON(ctx, jwksURI, &set); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]interface{}{}
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+	return keys, nil
+}
+
+// Gets a JSON document, failing when it's larger than maxDiscoveryDocumentSize.
+func (c *discoveryCache) getJSON(ctx context.Context, url string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d getting %q", res.StatusCode, url)
+	}
+	return json.NewDecoder(io.LimitReader(res.Body, maxDiscoveryDocumentSize)).Decode(result)
+}
+
+// Decodes an RSA or elliptic curve public key.
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > int64(^uint32(0)>>1) {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curves := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}
+		curve, ok := curves[k.Curve]
+		if !ok {
+			return nil, errors.New("unsupported curve")
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, errors.New("unsupported key type")
+}
+
+// Decodes a base64url encoded big endian integer.
+func decodeBigInt(value string) (*big.Int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) == 0 {
+		return nil, errors.New("empty integer")
+	}
+	return new(big.Int).SetBytes(decoded), nil
+}
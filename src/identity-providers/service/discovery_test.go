@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscoveryCache_CachesMetadataAndKeys(t *testing.T) {
+	idp, _ := newIdentityProvider(t)
+	cache := newDiscoveryCache(http.DefaultClient, time.Hour)
+
+	metadata, err := cache.metadata(context.TODO(), idp.Issuer())
+	assert.Nil(t, err)
+	assert.Equal(t, idp.Issuer(), metadata.Issuer)
+	assert.Equal(t, idp.Issuer()+"/token", metadata.TokenEndpoint)
+
+	_, err = cache.metadata(context.TODO(), idp.Issuer())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, idp.Requests("/.well-known/openid-configuration"))
+	assert.Equal(t, 1, idp.Requests("/jwks"))
+}
+
+func TestDiscoveryCache_Expires(t *testing.T) {
+	idp, _ := newIdentityProvider(t)
+	cache := newDiscoveryCache(http.DefaultClient, 0)
+
+	_, err := cache.metadata(context.TODO(), idp.Issuer())
+	assert.Nil(t, err)
+	_, err = cache.metadata(context.TODO(), idp.Issuer())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, idp.Requests("/.well-known/openid-configuration"))
+}
+
+func TestDiscoveryCache_RefetchesKeysOnRotation(t *testing.T) {
+	idp, _ := newIdentityProvider(t)
+	cache := newDiscoveryCache(http.DefaultClient, time.Hour)
+
+	_, err := cache.metadata(context.TODO(), idp.Issuer())
+	assert.Nil(t, err)
+	assert.Nil(t, idp.RotateKey())
+
+	token, err := idp.SignIDToken(nil)
+	assert.Nil(t, err)
+	keyID := keyIDOf(t, token)
+
+	key, err := cache.key(context.TODO(), idp.Issuer(), keyID)
+	assert.Nil(t, err)
+	assert.IsType(t, &rsa.PublicKey{}, key)
+	assert.Equal(t, 1, idp.Requests("/.well-known/openid-configuration"))
+	assert.Equal(t, 2, idp.Requests("/jwks"))
+
+	_, err = cache.key(context.TODO(), idp.Issuer(), "unknown")
+	assert.Error(t, err)
+}
+
+func TestDiscoveryCache_IssuerMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"issuer":"https://other.example.com","authorization_endpoint":"a","token_endpoint":"t","jwks_uri":"j"}`))
+	}))
+	defer server.Close()
+
+	_, err := newDiscoveryCache(http.DefaultClient, time.Hour).metadata(context.TODO(), server.URL)
+	assert.Error(t, err)
+}
+
+func TestDiscoveryCache_Unavailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := newDiscoveryCache(http.DefaultClient, time.Hour).metadata(context.TODO(), server.URL)
+	assert.Error(t, err)
+}
+
+// Serves a provider without keys, whose discovery document waits for release to be closed.
+func newSlowProvider(t *testing.T, release chan struct{}) (*httptest.Server, *int32) {
+	var requests int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/jwks" {
+			w.Write([]byte(`{"keys":[]}`))
+			return
+		}
+		atomic.AddInt32(&requests, 1)
+		<-release
+		fmt.Fprintf(w, `{"issuer":%q,"authorization_endpoint":"a","token_endpoint":"t","jwks_uri":%q}`, server.URL, server.URL+"/jwks")
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestDiscoveryCache_FetchesOnceAtATime(t *testing.T) {
+	release := make(chan struct{})
+	server, requests := newSlowProvider(t, release)
+	cache := newDiscoveryCache(http.DefaultClient, time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			metadata, err := cache.metadata(context.TODO(), server.URL)
+			assert.Nil(t, err)
+			assert.Equal(t, server.URL, metadata.Issuer)
+		}()
+	}
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(requests) == 1 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+func TestDiscoveryCache_SlowIssuerDoesntHoldUpOthers(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	slow, requests := newSlowProvider(t, release)
+	idp, _ := newIdentityProvider(t)
+	cache := newDiscoveryCache(http.DefaultClient, time.Hour)
+
+	go cache.metadata(context.TODO(), slow.URL)
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(requests) == 1 }, time.Second, time.Millisecond)
+
+	_, err := cache.metadata(context.TODO(), idp.Issuer())
+	assert.Nil(t, err)
+
+	// Callers waiting for the slow one still give up with their context.
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+	_, err = cache.metadata(ctx, slow.URL)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestDiscoveryCache_DocumentTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"issuer":"%s"}`, strings.Repeat("a", maxDiscoveryDocumentSize))
+	}))
+	defer server.Close()
+
+	_, err := newDiscoveryCache(http.DefaultClient, time.Hour).metadata(context.TODO(), server.URL)
+	assert.Error(t, err)
+}
+
+func TestJSONWebKey_PublicKey(t *testing.T) {
+	// Example keys of RFC 7517, appendix A.1.
+	ec := jsonWebKey{
+		KeyType: "EC",
+		Curve:   "P-256",
+		X:       "MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4",
+		Y:       "4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM",
+	}
+	key, err := ec.publicKey()
+	assert.Nil(t, err)
+	assert.IsType(t, &ecdsa.PublicKey{}, key)
+
+	rsaKey := jsonWebKey{
+		KeyType: "RSA",
+		N:       "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		E:       "AQAB",
+	}
+	key, err = rsaKey.publicKey()
+	assert.Nil(t, err)
+	assert.Equal(t, 65537, key.(*rsa.PublicKey).E)
+
+	_, err = jsonWebKey{KeyType: "oct"}.publicKey()
+	assert.Error(t, err)
+	_, err = jsonWebKey{KeyType: "EC", Curve: "P-256", X: ec.X, Y: ec.X}.publicKey()
+	assert.Error(t, err)
+}
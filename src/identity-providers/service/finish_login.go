@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Finishes a login with the state and the code the identity provider sent the user back with.
+// The code is exchanged for an ID token, which must be signed by the provider, for this client
+// and for this login. Its subject is the upstream account of the user, which is found by its link,
+// linked to the local user with the same verified email, or provisioned with a mapped role,
+// as allowed by the provider.
+// Unknown or expired states and rejected codes or tokens fail with domain.ErrInvalidToken,
+// accounts that can't be linked nor provisioned with domain.ErrNotAllowed, and users that
+// are not active with domain.ErrUserNotActive.
+func (s DefaultOIDCService) FinishLogin(ctx context.Context, state string, code string) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	sessionID, err := uuid.Parse(state)
+	if err != nil || len(code) == 0 {
+		return nil, domain.ErrInvalidToken
+	}
+
+	session, err := s.ProviderRepo.ConsumeSession(ctx, sessionID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && session.IsExpired()) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The provider may have been disabled, or belong to another organization.
+	provider, err := s.ProviderRepo.GetByUUID(ctx, session.ProviderID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !provider.Enabled) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := s.Discovery.metadata(ctx, provider.Issuer)
+	if err != nil {
+		s.Logger.Printf("error discovering the identity provider %v: %v\n", provider.Slug, err)
+		return nil, err
+	}
+
+	idToken, err := s.exchangeCode(ctx, provider, metadata, code, session.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := s.verifyIDToken(ctx, provider, idToken, session.Nonce)
+	if err != nil {
+		s.Logger.Printf("invalid ID token from the identity provider %v: %v\n", provider.Slug, err)
+		return nil, domain.ErrInvalidToken
+	}
+
+	userID, err := s.resolveUser(ctx, provider, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserService.GetUserByUUID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive() {
+		return nil, domain.ErrUserNotActive
+	}
+	return user, nil
+}
+
+// Gets the ID of the local user of an upstream account, linking or provisioning it when allowed.
+func (s DefaultOIDCService) resolveUser(ctx context.Context, provider domain.IdentityProvider, claims idTokenClaims) (uuid.UUID, error) {
+	subject := claims.String("sub")
+	identity, err := s.ProviderRepo.GetIdentity(ctx, provider.ID, subject)
+	if err == nil {
+		return identity.UserID, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, err
+	}
+
+	email := claims.String("email")
+	if provider.LinkByEmail && len(email) > 0 && claims.EmailVerified() {
+		user, err := s.UserRepo.GetByEmail(ctx, email)
+		if err == nil {
+			return s.link(ctx, provider, subject, email, user.ID)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, err
+		}
+	}
+
+	if !provider.AutoProvision {
+		return uuid.Nil, domain.ErrNotAllowed
+	}
+	return s.provision(ctx, provider, claims)
+}
+
+// Creates the local user of an upstream account, with the role its upstream roles map to.
+// Its username is the one preferred upstream, or else the email or the subject.
+// The user has no password, so it can only log in through the provider.
+func (s DefaultOIDCService) provision(ctx context.Context, provider domain.IdentityProvider, claims idTokenClaims) (uuid.UUID, error) {
+	role := provider.DefaultRole
+	for _, upstreamRole := range claims.Strings(provider.RoleClaim) {
+		if mapped, ok := provider.RoleMapping[upstreamRole]; ok {
+			role = mapped
+			break
+		}
+	}
+	if len(role) == 0 {
+		return uuid.Nil, domain.ErrNotAllowed
+	}
+
+	email := claims.String("email")
+	username := claims.String("preferred_username")
+	if len(username) == 0 {
+		username = email
+	}
+	if len(username) == 0 {
+		username = claims.String("sub")
+	}
+
+	user, err := s.UserService.Store(ctx, domain.StoreUserRequest{
+		Username: username,
+		Email:    email,
+		RoleSlug: role,
+		Status:   domain.UserStatusActive,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if len(email) > 0 && claims.EmailVerified() {
+		if err = s.UserRepo.MarkEmailVerified(ctx, user.ID, email); err != nil {
+			return uuid.Nil, err
+		}
+	}
+	return s.link(ctx, provider, claims.String("sub"), email, user.ID)
+}
+
+// Links an upstream account to a local user.
+func (s DefaultOIDCService) link(ctx context.Context, provider domain.IdentityProvider, subject string, email string, userID uuid.UUID) (uuid.UUID, error) {
+	_, err := s.ProviderRepo.StoreIdentity(ctx, domain.ExternalIdentity{
+		ProviderID: provider.ID,
+		UserID:     userID,
+		Subject:    subject,
+		Email:      email,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return userID, nil
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Repository finishing the login of a session with the provider
+func finishingRepo(session domain.OIDCSession, provider domain.IdentityProvider) *mocks.IdentityProviderRepository {
+	providerRepo := new(mocks.IdentityProviderRepository)
+	providerRepo.On("ConsumeSession", mock.Anything, session.ID).Once().Return(session, nil)
+	providerRepo.On("GetByUUID", mock.Anything, provider.ID).Once().Return(provider, nil)
+	return providerRepo
+}
+
+func TestFinishLogin_InvalidState(t *testing.T) {
+	providerRepo := new(mocks.IdentityProviderRepository)
+
+	user, err := newService(providerRepo, nil, nil, nil).FinishLogin(context.TODO(), "not-a-state", "code")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+
+	user, err = newService(providerRepo, nil, nil, nil).FinishLogin(context.TODO(), uuid.NewString(), "")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	providerRepo.AssertNotCalled(t, "ConsumeSession", mock.Anything, mock.Anything)
+}
+
+func TestFinishLogin_UnknownSession(t *testing.T) {
+	sessionID := uuid.New()
+	providerRepo := new(mocks.IdentityProviderRepository)
+	providerRepo.On("ConsumeSession", mock.Anything, sessionID).Once().Return(domain.OIDCSession{}, sql.ErrNoRows)
+
+	user, err := newService(providerRepo, nil, nil, nil).FinishLogin(context.TODO(), sessionID.String(), "code")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+}
+
+func TestFinishLogin_ExpiredSession(t *testing.T) {
+	session := domain.OIDCSession{ID: uuid.New(), ProviderID: uuid.New(), ExpiresAt: time.Now().Add(-time.Second)}
+	providerRepo := new(mocks.IdentityProviderRepository)
+	providerRepo.On("ConsumeSession", mock.Anything, session.ID).Once().Return(session, nil)
+
+	user, err := newService(providerRepo, nil, nil, nil).FinishLogin(context.TODO(), session.ID.String(), "code")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	providerRepo.AssertNotCalled(t, "GetByUUID", mock.Anything, mock.Anything)
+}
+
+func TestFinishLogin_ErrorConsumingSession(t *testing.T) {
+	sessionID := uuid.New()
+	providerRepo := new(mocks.IdentityProviderRepository)
+	providerRepo.On("ConsumeSession", mock.Anything, sessionID).Once().Return(domain.OIDCSession{}, errors.New("boom"))
+
+	user, err := newService(providerRepo, nil, nil, nil).FinishLogin(context.TODO(), sessionID.String(), "code")
+	assert.Nil(t, user)
+	assert.Equal(t, "boom", err.Error())
+}
+
+func TestFinishLogin_DisabledProvider(t *testing.T) {
+	idp, provider := newIdentityProvider(t)
+	s := newService(nil, nil, nil, nil)
+	session, code := loginUpstream(t, s, idp, provider, nil)
+
+	provider.Enabled = false
+	s.ProviderRepo = finishingRepo(session, provider)
+
+	user, err := s.FinishLogin(context.TODO(), session.ID.String(), code)
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+}
+
+func TestFinishLogin_RejectedCode(t *testing.T) {
+	idp, provider := newIdentityProvider(t)
+	s := newService(nil, nil, nil, nil)
+	session, _ := loginUpstream(t, s, idp, provider, nil)
+	s.ProviderRepo = finishingRepo(session, provider)
+
+	user, err := s.FinishLogin(context.TODO(), session.ID.String(), "forged-code")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+}
+
+func TestFinishLogin_InvalidClientSecret(t *testing.T) {
+	idp, provider := newIdentityProvider(t)
+	s := newService(nil, nil, nil, nil)
+	session, code := loginUpstream(t, s, idp, provider, nil)
+
+	provider.ClientSecret = "wrong"
+	s.ProviderRepo = finishingRepo(session, provider)
+
+	user, err := s.FinishLogin(context.TODO(), session.ID.String(), code)
+	assert.Nil(t, user)
+	assert.Error(t, err)
+	assert.NotEqual(t, domain.ErrInvalidToken, err)
+}
+
+func TestFinishLogin_TokenOfAnotherLogin(t *testing.T) {
+	idp, provider := newIdentityProvider(t)
+	s := newService(nil, nil, nil, nil)
+	session, code := loginUpstream(t, s, idp, provider, map[string]interface{}{"nonce": "nonce-of-another-login"})
+	providerRepo := finishingRepo(session, provider)
+	s.ProviderRepo = providerRepo
+
+	user, err := s.FinishLogin(context.TODO(), session.ID.String(), code)
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	providerRepo.AssertNotCalled(t, "GetIdentity", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFinishLogin_LinkedUser(t *testing.T) {
+	idp, provider := newIdentityProvider(t)
+	userID := uuid.New()
+	s := newService(nil, nil, nil, nil)
+	session, code := loginUpstream(t, s, idp, provider, map[string]interface{}{"sub": "staff-1"})
+
+	providerRepo := finishingRepo(session, provider)
+	providerRepo.On("GetIdentity", mock.Anything, provider.ID, "staff-1").Once().
+		Return(domain.ExternalIdentity{UserID: userID}, nil)
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.Anything, userID).Once().
+		Return(&domain.User{ID: userID, Status: domain.UserStatusActive}, nil)
+	s.ProviderRepo, s.UserService = providerRepo, userService
+
+	user, err := s.FinishLogin(context.TODO(), session.ID.String(), code)
+	assert.Nil(t, err)
+	assert.Equal(t, userID, user.ID)
+	providerRepo.AssertNotCalled(t, "StoreIdentity", mock.Anything, mock.Anything)
+}
+
+func TestFinishLogin_InactiveUser(t *testing.T) {
+	idp, provider := newIdentityProvider(t)
+	userID := uuid.New()
+	s := newService(nil, nil, nil, nil)
+	session, code := loginUpstream(t, s, idp, provider, map[string]interface{}{"sub": "staff-1"})
+
+	providerRepo := finishingRepo(session, provider)
+	providerRepo.On("GetIdentity", mock.Anything, provider.ID, "staff-1").Once().
+		Return(domain.ExternalIdentity{UserID: userID}, nil)
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.Anything, userID).Once().
+		Return(&domain.User{ID: userID, Status: domain.UserStatusSuspended}, nil)
+	s.ProviderRepo, s.UserService = providerRepo, userService
+
+	user, err := s.FinishLogin(context.TODO(), session.ID.String(), code)
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrUserNotActive, err)
+}
+
+func TestFinishLogin_LinksByVerifiedEmail(t *testing.T) {
+	idp, provider := newIdentityProvider(t)
+	userID := uuid.New()
+	s := newService(nil, nil, nil, nil)
+	session, code := loginUpstream(t, s, idp, provider, map[string]interface{}{
+		"sub":            "staff-1",
+		"email":          "staff@example.com",
+		"email_verified": true,
+	})
+
+	providerRepo := finishingRepo(session, provider)
+	providerRepo.On("GetIdentity", mock.Anything, provider.ID, "staff-1").Once().
+		Return(domain.ExternalIdentity{}, sql.ErrNoRows)
+	providerRepo.On("StoreIdentity", mock.Anything, domain.ExternalIdentity{
+		ProviderID: provider.ID,
+		UserID:     userID,
+		Subject:    "staff-1",
+		Email:      "staff@example.com",
+	}).Once().Return(domain.ExternalIdentity{}, nil)
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByEmail", mock.Anything, "staff@example.com").Once().Return(&domain.User{ID: userID}, nil)
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.Anything, userID).Once().
+		Return(&domain.User{ID: userID, Status: domain.UserStatusActive}, nil)
+	s.ProviderRepo, s.UserRepo, s.UserService = providerRepo, userRepo, userService
+
+	user, err := s.FinishLogin(context.TODO(), session.ID.String(), code)
+	assert.Nil(t, err)
+	assert.Equal(t, userID, user.ID)
+	providerRepo.AssertExpectations(t)
+	userService.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+}
+
+func TestFinishLogin_DoesNotLinkUnverifiedEmail(t *testing.T) {
+	idp, provider := newIdentityProvider(t)
+	provider.AutoProvision = false
+	s := newService(nil, nil, nil, nil)
+	session, code := loginUpstream(t, s, idp, provider, map[string]interface{}{
+		"sub":   "staff-1",
+		"email": "staff@example.com",
+	})
+
+	providerRepo := finishingRepo(session, provider)
+	providerRepo.On("GetIdentity", mock.Anything, provider.ID, "staff-1").Once().
+		Return(domain.ExternalIdentity{}, sql.ErrNoRows)
+	userRepo := new(mocks.UserRepository)
+	s.ProviderRepo, s.UserRepo = providerRepo, userRepo
+
+	user, err := s.FinishLogin(context.TODO(), session.ID.String(), code)
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrNotAllowed, err)
+	userRepo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+}
+
+func TestFinishLogin_ProvisionsWithMappedRole(t *testing.T) {
+	idp, provider := newIdentityProvider(t)
+	userID := uuid.New()
+	s := newService(nil, nil, nil, nil)
+	session, code := loginUpstream(t, s, idp, provider, map[string]interface{}{
+		"sub":                "staff-1",
+		"preferred_username": "jdoe",
+		"email":              "jdoe@example.com",
+		"email_verified":     true,
+		"groups":             []string{"staff", "it-admins"},
+	})
+
+	providerRepo := finishingRepo(session, provider)
+	providerRepo.On("GetIdentity", mock.Anything, provider.ID, "staff-1").Once().
+		Return(domain.ExternalIdentity{}, sql.ErrNoRows)
+	providerRepo.On("StoreIdentity", mock.Anything, domain.ExternalIdentity{
+		ProviderID: provider.ID,
+		UserID:     userID,
+		Subject:    "staff-1",
+		Email:      "jdoe@example.com",
+	}).Once().Return(domain.ExternalIdentity{}, nil)
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByEmail", mock.Anything, "jdoe@example.com").Once().Return(nil, sql.ErrNoRows)
+	userRepo.On("MarkEmailVerified", mock.Anything, userID, "jdoe@example.com").Once().Return(nil)
+	userService := new(mocks.UserService)
+	userService.On("Store", mock.Anything, domain.StoreUserRequest{
+		Username: "jdoe",
+		Email:    "jdoe@example.com",
+		RoleSlug: "admin",
+		Status:   domain.UserStatusActive,
+	}).Once().Return(&domain.User{ID: userID}, nil)
+	userService.On("GetUserByUUID", mock.Anything, userID).Once().
+		Return(&domain.User{ID: userID, Status: domain.UserStatusActive, EmailVerified: true}, nil)
+	s.ProviderRepo, s.UserRepo, s.UserService = providerRepo, userRepo, userService
+
+	user, err := s.FinishLogin(context.TODO(), session.ID.String(), code)
+	assert.Nil(t, err)
+	assert.Equal(t, userID, user.ID)
+	providerRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func TestFinishLogin_ProvisionsWithDefaultRole(t *testing.T) {
+	idp, provider := newIdentityProvider(t)
+	userID := uuid.New()
+	s := newService(nil, nil, nil, nil)
+	session, code := loginUpstream(t, s, idp, provider, map[string]interface{}{
+		"sub":    "staff-1",
+		"groups": "staff",
+	})
+
+	providerRepo := finishingRepo(session, provider)
+	providerRepo.On("GetIdentity", mock.Anything, provider.ID, "staff-1").Once().
+		Return(domain.ExternalIdentity{}, sql.ErrNoRows)
+	providerRepo.On("StoreIdentity", mock.Anything, mock.Anything).Once().Return(domain.ExternalIdentity{}, nil)
+	userRepo := new(mocks.UserRepository)
+	userService := new(mocks.UserService)
+	userService.On("Store", mock.Anything, domain.StoreUserRequest{
+		Username: "staff-1",
+		RoleSlug: "user",
+		Status:   domain.UserStatusActive,
+	}).Once().Return(&domain.User{ID: userID}, nil)
+	userService.On("GetUserByUUID", mock.Anything, userID).Once().
+		Return(&domain.User{ID: userID, Status: domain.UserStatusActive}, nil)
+	s.ProviderRepo, s.UserRepo, s.UserService = providerRepo, userRepo, userService
+
+	user, err := s.FinishLogin(context.TODO(), session.ID.String(), code)
+	assert.Nil(t, err)
+	assert.Equal(t, userID, user.ID)
+	userRepo.AssertNotCalled(t, "MarkEmailVerified", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFinishLogin_NoRoleToProvision(t *testing.T) {
+	idp, provider := newIdentityProvider(t)
+	provider.DefaultRole = ""
+	s := newService(nil, nil, nil, nil)
+	session, code := loginUpstream(t, s, idp, provider, map[string]interface{}{
+		"sub":    "staff-1",
+		"groups": []string{"staff"},
+	})
+
+	providerRepo := finishingRepo(session, provider)
+	providerRepo.On("GetIdentity", mock.Anything, provider.ID, "staff-1").Once().
+		Return(domain.ExternalIdentity{}, sql.ErrNoRows)
+	userService := new(mocks.UserService)
+	s.ProviderRepo, s.UserService = providerRepo, userService
+
+	user, err := s.FinishLogin(context.TODO(), session.ID.String(), code)
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrNotAllowed, err)
+	userService.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+}
+
+func TestFinishLogin_UsernameTaken(t *testing.T) {
+	idp, provider := newIdentityProvider(t)
+	s := newService(nil, nil, nil, nil)
+	session, code := loginUpstream(t, s, idp, provider, map[string]interface{}{
+		"sub":                "staff-1",
+		"preferred_username": "admin",
+	})
+
+	providerRepo := finishingRepo(session, provider)
+	providerRepo.On("GetIdentity", mock.Anything, provider.ID, "staff-1").Once().
+		Return(domain.ExternalIdentity{}, sql.ErrNoRows)
+	userService := new(mocks.UserService)
+	userService.On("Store", mock.Anything, mock.Anything).Once().Return(nil, domain.ErrAlreadyExists)
+	s.ProviderRepo, s.UserService = providerRepo, userService
+
+	user, err := s.FinishLogin(context.TODO(), session.ID.String(), code)
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+	providerRepo.AssertNotCalled(t, "StoreIdentity", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Signing algorithms accepted on the ID tokens.
+var idTokenAlgorithms = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+// Claims of a verified ID token.
+type idTokenClaims jwt.MapClaims
+
+// Gets a string claim, empty when missing or of another type.
+func (c idTokenClaims) String(name string) string {
+	value, _ := c[name].(string)
+	return value
+}
+
+// Gets a claim that can be a string or a list of strings, as a list.
+func (c idTokenClaims) Strings(name string) []string {
+	switch value := c[name].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		result := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// Returns if the provider verified the email of the user.
+// Some providers send the claim as a string.
+func (c idTokenClaims) EmailVerified() bool {
+	switch value := c["email_verified"].(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true"
+	}
+	return false
+}
+
+// Exchanges an authorization code for the ID token, authenticating with the client secret.
+// Codes the provider rejects fail with domain.ErrInvalidToken.
+func (s DefaultOIDCService) exchangeCode(
+	ctx context.Context,
+	provider domain.IdentityProvider,
+	metadata providerMetadata,
+	code string,
+	codeVerifier string,
+) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {provider.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	// Public clients have no secret, and identify themselves in the form.
+	if len(provider.ClientSecret) == 0 {
+		form.Set("client_id", provider.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if len(provider.ClientSecret) > 0 {
+		req.SetBasicAuth(url.QueryEscape(provider.ClientID), url.QueryEscape(provider.ClientSecret))
+	}
+
+	res, err := s.Discovery.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	body := struct {
+		IDToken string `json:"id_token"`
+		Error   string `json:"error"`
+	}{}
+	if err = json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if res.StatusCode == http.StatusBadRequest && body.Error == "invalid_grant" {
+		return "", domain.ErrInvalidToken
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d exchanging the code: %s", res.StatusCode, body.Error)
+	}
+	if len(body.IDToken) == 0 {
+		return "", errors.New("no ID token in the token response")
+	}
+	return body.IDToken, nil
+}
+
+// Verifies an ID token of a provider, issued for the client, and for the login with the nonce.
+func (s DefaultOIDCService) verifyIDToken(
+	ctx context.Context,
+	provider domain.IdentityProvider,
+	idToken string,
+	nonce string,
+) (idTokenClaims, error) {
+	parser := jwt.Parser{ValidMethods: idTokenAlgorithms}
+	token, err := parser.ParseWithClaims(idToken, jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+		return s.Discovery.key(ctx, provider.Issuer, keyID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Expiration, issue and not before dates are checked when parsing.
+	claims := token.Claims.(jwt.MapClaims)
+	if !claims.VerifyIssuer(provider.Issuer, true) {
+		return nil, errors.New("invalid issuer")
+	}
+	if !claims.VerifyAudience(provider.ClientID, true) {
+		return nil, errors.New("invalid audience")
+	}
+	// Tokens for several audiences must be authorized for this client.
+	if azp, ok := claims["azp"].(string); ok && azp != provider.ClientID {
+		return nil, errors.New("invalid authorized party")
+	}
+	if _, ok := claims["exp"]; !ok {
+		return nil, errors.New("missing expiration")
+	}
+
+	result := idTokenClaims(claims)
+	tokenNonce := result.String("nonce")
+	if subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+		return nil, errors.New("invalid nonce")
+	}
+	if len(result.String("sub")) == 0 {
+		return nil, errors.New("missing subject")
+	}
+	return result, nil
+}
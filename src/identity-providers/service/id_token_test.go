@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+// Gets the ID of the key a token is signed with.
+func keyIDOf(t *testing.T, token string) string {
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+	assert.Nil(t, err)
+	return parsed.Header["kid"].(string)
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	idp, provider := newIdentityProvider(t)
+	s := newService(nil, nil, nil, nil)
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   idp.Issuer(),
+			"sub":   "staff-1",
+			"aud":   "users-service",
+			"iat":   time.Now().Unix(),
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"nonce": "the-nonce",
+		}
+	}
+
+	token, err := idp.SignIDToken(valid())
+	assert.Nil(t, err)
+	claims, err := s.verifyIDToken(context.TODO(), provider, token, "the-nonce")
+	assert.Nil(t, err)
+	assert.Equal(t, "staff-1", claims.String("sub"))
+
+	cases := map[string]func(claims jwt.MapClaims){
+		"another issuer":   func(claims jwt.MapClaims) { claims["iss"] = "https://other.example.com" },
+		"another audience": func(claims jwt.MapClaims) { claims["aud"] = "other-client" },
+		"another authorized party": func(claims jwt.MapClaims) {
+			claims["aud"] = []string{"users-service", "other"}
+			claims["azp"] = "other"
+		},
+		"expired":                   func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"without expiration":        func(claims jwt.MapClaims) { delete(claims, "exp") },
+		"another nonce":             func(claims jwt.MapClaims) { claims["nonce"] = "other-nonce" },
+		"without nonce":             func(claims jwt.MapClaims) { delete(claims, "nonce") },
+		"without subject":           func(claims jwt.MapClaims) { delete(claims, "sub") },
+		"issued in the future":      func(claims jwt.MapClaims) { claims["iat"] = time.Now().Add(time.Hour).Unix() },
+		"not valid before a minute": func(claims jwt.MapClaims) { claims["nbf"] = time.Now().Add(time.Minute).Unix() },
+	}
+	for name, tamper := range cases {
+		t.Run(name, func(t *testing.T) {
+			claims := valid()
+			tamper(claims)
+			token, err := idp.SignIDToken(claims)
+			assert.Nil(t, err)
+
+			_, err = s.verifyIDToken(context.TODO(), provider, token, "the-nonce")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestVerifyIDToken_ForeignSignatures(t *testing.T) {
+	idp, provider := newIdentityProvider(t)
+	other, _ := newIdentityProvider(t)
+	s := newService(nil, nil, nil, nil)
+
+	claims := jwt.MapClaims{
+		"iss":   idp.Issuer(),
+		"sub":   "staff-1",
+		"aud":   "users-service",
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": "the-nonce",
+	}
+
+	// Signed by another provider, with a key the issuer doesn't have.
+	token, err := other.SignIDToken(claims)
+	assert.Nil(t, err)
+	_, err = s.verifyIDToken(context.TODO(), provider, token, "the-nonce")
+	assert.Error(t, err)
+
+	// Not signed at all.
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	assert.Nil(t, err)
+	_, err = s.verifyIDToken(context.TODO(), provider, unsigned, "the-nonce")
+	assert.Error(t, err)
+
+	// Signed with HMAC, using the client secret.
+	hmac, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(provider.ClientSecret))
+	assert.Nil(t, err)
+	_, err = s.verifyIDToken(context.TODO(), provider, hmac, "the-nonce")
+	assert.Error(t, err)
+}
+
+func TestIDTokenClaims(t *testing.T) {
+	claims := idTokenClaims{
+		"sub":            "staff-1",
+		"groups":         []interface{}{"staff", 1, "it-admins"},
+		"role":           "staff",
+		"email_verified": "true",
+	}
+
+	assert.Equal(t, "staff-1", claims.String("sub"))
+	assert.Equal(t, "", claims.String("groups"))
+	assert.Equal(t, []string{"staff", "it-admins"}, claims.Strings("groups"))
+	assert.Equal(t, []string{"staff"}, claims.Strings("role"))
+	assert.Nil(t, claims.Strings("missing"))
+	assert.True(t, claims.EmailVerified())
+	assert.False(t, idTokenClaims{"email_verified": false}.EmailVerified())
+	assert.False(t, idTokenClaims{}.EmailVerified())
+}
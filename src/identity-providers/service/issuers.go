@@ -0,0 +1,69 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Returns if an issuer can be reached by the service without exposing its network:
+// over https, and not on a private or loopback address.
+func isPublicIssuer(issuer string) bool {
+	parsed, err := url.Parse(issuer)
+	if err != nil || parsed.Scheme != "https" {
+		return false
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return isPublicAddress(ip)
+	}
+	return len(host) > 0
+}
+
+// Returns if an address is reachable from the internet.
+func isPublicAddress(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast()
+}
+
+// Creates the client the providers are requested with. Unless private issuers are allowed,
+// it only connects to public addresses, so the documents of a provider, or its host
+// resolving to another address, can't make the service reach its own network.
+func newProviderClient(settings domain.OIDCSettings) *http.Client {
+	if settings.AllowPrivateIssuers {
+		return &http.Client{Timeout: settings.HTTPTimeout}
+	}
+
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(network string, address string, conn syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicAddress(ip) {
+				return fmt.Errorf("connecting to the non-public address %q", host)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: settings.HTTPTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			TLSHandshakeTimeout: 10 * time.Second,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
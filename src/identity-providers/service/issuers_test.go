@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublicIssuer(t *testing.T) {
+	cases := map[string]bool{
+		"https://idp.example.com":          true,
+		"https://93.184.216.34/tenant":     true,
+		"http://idp.example.com":           false,
+		"https://localhost:8443":           false,
+		"https://idp.localhost":            false,
+		"https://127.0.0.1":                false,
+		"https://[::1]":                    false,
+		"https://192.168.1.10":             false,
+		"https://172.16.0.1":               false,
+		"https://[fd00::1]":                false,
+		"https://169.254.169.254/metadata": false,
+		"https://0.0.0.0":                  false,
+		"https://":                         false,
+	}
+
+	for issuer, public := range cases {
+		assert.Equal(t, public, isPublicIssuer(issuer), issuer)
+	}
+}
+
+func TestNewProviderClient_OnlyConnectsToPublicAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	cache := newDiscoveryCache(newProviderClient(domain.OIDCSettings{HTTPTimeout: time.Second}), time.Hour)
+	_, err := cache.metadata(context.TODO(), server.URL)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "non-public address")
+
+	// Unless private issuers are allowed.
+	res, err := newProviderClient(domain.OIDCSettings{HTTPTimeout: time.Second, AllowPrivateIssuers: true}).Get(server.URL)
+	assert.Nil(t, err)
+	res.Body.Close()
+}
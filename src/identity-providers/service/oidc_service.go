@@ -0,0 +1,87 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Random bytes of the nonces and PKCE verifiers
+const randomBytes = 32
+
+type DefaultOIDCService struct {
+	Logger         *log.Logger
+	ProviderRepo   domain.IdentityProviderRepository
+	RoleRepo       domain.RoleRepository
+	UserRepo       domain.UserRepository
+	UserService    domain.UserService
+	ContextTimeout time.Duration
+	Settings       domain.OIDCSettings
+	Discovery      *discoveryCache
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	providerRepo domain.IdentityProviderRepository,
+	roleRepo domain.RoleRepository,
+	userRepo domain.UserRepository,
+	userService domain.UserService,
+	contextTimeout time.Duration,
+	settings domain.OIDCSettings,
+) domain.OIDCService {
+	return DefaultOIDCService{
+		logger,
+		providerRepo,
+		roleRepo,
+		userRepo,
+		userService,
+		contextTimeout,
+		settings,
+		newDiscoveryCache(newProviderClient(settings), settings.DiscoveryTTL),
+	}
+}
+
+// Instantiation for tests
+func newService(
+	providerRepo domain.IdentityProviderRepository,
+	roleRepo domain.RoleRepository,
+	userRepo domain.UserRepository,
+	userService domain.UserService,
+) DefaultOIDCService {
+	return DefaultOIDCService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		providerRepo,
+		roleRepo,
+		userRepo,
+		userService,
+		time.Duration(5 * time.Second),
+		domain.OIDCSettings{
+			SessionTTL:   time.Duration(10 * time.Minute),
+			DiscoveryTTL: time.Duration(time.Hour),
+			HTTPTimeout:  time.Duration(5 * time.Second),
+		},
+		newDiscoveryCache(&http.Client{Timeout: 5 * time.Second}, time.Hour),
+	}
+}
+
+// Generates a random url-safe value, for the nonces and PKCE verifiers
+func generateRandom() (string, error) {
+	b := make([]byte, randomBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// PKCE challenge of a verifier, with the S256 method
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/plagioriginal/user-microservice/identity-providers/mockidp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Starts a mock identity provider, returning it with its configuration.
+func newIdentityProvider(t *testing.T) (*mockidp.Provider, domain.IdentityProvider) {
+	idp, err := mockidp.New("users-service", "secret")
+	assert.Nil(t, err)
+	t.Cleanup(idp.Close)
+
+	return idp, domain.IdentityProvider{
+		ID:            uuid.New(),
+		Slug:          "corporate",
+		Name:          "Corporate",
+		Issuer:        idp.Issuer(),
+		ClientID:      "users-service",
+		ClientSecret:  "secret",
+		RedirectURL:   "https://app.example.com/callback",
+		Scopes:        []string{"email", "profile"},
+		RoleClaim:     "groups",
+		RoleMapping:   map[string]string{"it-admins": "admin"},
+		DefaultRole:   "user",
+		LinkByEmail:   true,
+		AutoProvision: true,
+		Enabled:       true,
+	}
+}
+
+// Begins a login with the provider and logs the user in upstream with the claims,
+// returning the session of the login and the code the user comes back with.
+func loginUpstream(
+	t *testing.T,
+	s DefaultOIDCService,
+	idp *mockidp.Provider,
+	provider domain.IdentityProvider,
+	claims map[string]interface{},
+) (domain.OIDCSession, string) {
+	var session domain.OIDCSession
+	providerRepo := new(mocks.IdentityProviderRepository)
+	providerRepo.On("GetBySlug", mock.Anything, provider.Slug).Once().Return(provider, nil)
+	providerRepo.On("StoreSession", mock.Anything, mock.Anything).Once().Return(nil).Run(func(args mock.Arguments) {
+		session = args.Get(1).(domain.OIDCSession)
+	})
+	s.ProviderRepo = providerRepo
+
+	authorization, err := s.BeginLogin(context.TODO(), provider.Slug)
+	assert.Nil(t, err)
+
+	code, err := idp.Authorize(authorization.AuthorizationURL, claims)
+	assert.Nil(t, err)
+	return session, code
+}
+
+func TestScope(t *testing.T) {
+	assert.Equal(t, "openid", scope(domain.IdentityProvider{}))
+	assert.Equal(t, "openid email groups", scope(domain.IdentityProvider{Scopes: []string{"openid", "email", "groups"}}))
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// Example of RFC 7636, appendix B.
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", codeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
+
+func TestGenerateRandom(t *testing.T) {
+	first, err := generateRandom()
+	assert.Nil(t, err)
+	second, err := generateRandom()
+	assert.Nil(t, err)
+
+	assert.Len(t, first, 43)
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, url.QueryEscape(first), first)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets the identity providers of the organization.
+func (s DefaultOIDCService) GetProviders(ctx context.Context) ([]domain.IdentityProvider, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	return s.ProviderRepo.Fetch(ctx)
+}
+
+// Creates or updates an identity provider of the organization, by slug.
+// The issuer must be public and the roles it provisions users with must exist, failing with
+// domain.ErrBadParamInput otherwise.
+// An empty client secret keeps the one of the stored provider, so it doesn't have to be sent again.
+func (s DefaultOIDCService) SaveProvider(ctx context.Context, provider domain.IdentityProvider) (domain.IdentityProvider, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	provider.Slug = strings.TrimSpace(provider.Slug)
+	provider.Issuer = strings.TrimSpace(provider.Issuer)
+	provider.RedirectURL = strings.TrimSpace(provider.RedirectURL)
+	if !provider.IsValid() {
+		return domain.IdentityProvider{}, domain.ErrBadParamInput
+	}
+	// Issuers are fetched by the service, so administrators can't make it reach its own network.
+	if !s.Settings.AllowPrivateIssuers && !isPublicIssuer(provider.Issuer) {
+		return domain.IdentityProvider{}, domain.ErrBadParamInput
+	}
+
+	roles := []string{}
+	if len(provider.DefaultRole) > 0 {
+		roles = append(roles, provider.DefaultRole)
+	}
+	for _, role := range provider.RoleMapping {
+		roles = append(roles, role)
+	}
+	for _, role := range roles {
+		_, err := s.RoleRepo.GetBySlug(ctx, role)
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.IdentityProvider{}, domain.ErrBadParamInput
+		}
+		if err != nil {
+			return domain.IdentityProvider{}, err
+		}
+	}
+
+	if len(provider.ClientSecret) == 0 {
+		existing, err := s.ProviderRepo.GetBySlug(ctx, provider.Slug)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return domain.IdentityProvider{}, err
+		}
+		provider.ClientSecret = existing.ClientSecret
+	}
+
+	return s.ProviderRepo.Save(ctx, provider)
+}
+
+// Deletes an identity provider of the organization. Its users stay, but can't log in with it anymore.
+func (s DefaultOIDCService) DeleteProvider(ctx context.Context, slug string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	if len(slug) == 0 {
+		return domain.ErrBadParamInput
+	}
+
+	return s.ProviderRepo.Delete(ctx, slug)
+}
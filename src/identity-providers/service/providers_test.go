@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Valid provider to save
+func providerToSave() domain.IdentityProvider {
+	return domain.IdentityProvider{
+		Slug:          " corporate ",
+		Name:          "Corporate",
+		Issuer:        "https://idp.example.com",
+		ClientID:      "users-service",
+		ClientSecret:  "secret",
+		RedirectURL:   "https://app.example.com/callback",
+		RoleMapping:   map[string]string{"it-admins": "admin"},
+		DefaultRole:   "user",
+		AutoProvision: true,
+		Enabled:       true,
+	}
+}
+
+func TestGetProviders(t *testing.T) {
+	providerRepo := new(mocks.IdentityProviderRepository)
+	providerRepo.On("Fetch", mock.Anything).Once().Return([]domain.IdentityProvider{{Slug: "corporate"}}, nil)
+
+	providers, err := newService(providerRepo, nil, nil, nil).GetProviders(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.IdentityProvider{{Slug: "corporate"}}, providers)
+}
+
+func TestSaveProvider_Invalid(t *testing.T) {
+	cases := map[string]func(provider *domain.IdentityProvider){
+		"invalid slug":               func(provider *domain.IdentityProvider) { provider.Slug = "Corporate IdP" },
+		"without client":             func(provider *domain.IdentityProvider) { provider.ClientID = "" },
+		"relative issuer":            func(provider *domain.IdentityProvider) { provider.Issuer = "idp.example.com" },
+		"http issuer":                func(provider *domain.IdentityProvider) { provider.Issuer = "http://idp.example.com" },
+		"loopback issuer":            func(provider *domain.IdentityProvider) { provider.Issuer = "https://127.0.0.1:8443" },
+		"localhost issuer":           func(provider *domain.IdentityProvider) { provider.Issuer = "https://localhost" },
+		"private issuer":             func(provider *domain.IdentityProvider) { provider.Issuer = "https://10.0.0.5" },
+		"link-local issuer":          func(provider *domain.IdentityProvider) { provider.Issuer = "https://169.254.169.254" },
+		"without redirect URL":       func(provider *domain.IdentityProvider) { provider.RedirectURL = "" },
+		"provisioning without roles": func(provider *domain.IdentityProvider) { provider.DefaultRole = ""; provider.RoleMapping = nil },
+	}
+
+	for name, change := range cases {
+		t.Run(name, func(t *testing.T) {
+			provider := providerToSave()
+			change(&provider)
+			providerRepo := new(mocks.IdentityProviderRepository)
+
+			_, err := newService(providerRepo, nil, nil, nil).SaveProvider(context.TODO(), provider)
+			assert.Equal(t, domain.ErrBadParamInput, err)
+			providerRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSaveProvider_UnknownRole(t *testing.T) {
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "user").Return(domain.Role{RoleSlug: "user"}, nil)
+	roleRepo.On("GetBySlug", mock.Anything, "admin").Return(domain.Role{}, sql.ErrNoRows)
+	providerRepo := new(mocks.IdentityProviderRepository)
+
+	_, err := newService(providerRepo, roleRepo, nil, nil).SaveProvider(context.TODO(), providerToSave())
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	providerRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestSaveProvider_ErrorGettingRole(t *testing.T) {
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, mock.Anything).Return(domain.Role{}, errors.New("boom"))
+
+	_, err := newService(nil, roleRepo, nil, nil).SaveProvider(context.TODO(), providerToSave())
+	assert.Equal(t, "boom", err.Error())
+}
+
+func TestSaveProvider_Success(t *testing.T) {
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, mock.Anything).Return(domain.Role{}, nil)
+	providerRepo := new(mocks.IdentityProviderRepository)
+	providerRepo.On("Save", mock.Anything, mock.MatchedBy(func(provider domain.IdentityProvider) bool {
+		return provider.Slug == "corporate" && provider.ClientSecret == "secret"
+	})).Once().Return(domain.IdentityProvider{Slug: "corporate"}, nil)
+
+	provider, err := newService(providerRepo, roleRepo, nil, nil).SaveProvider(context.TODO(), providerToSave())
+	assert.Nil(t, err)
+	assert.Equal(t, "corporate", provider.Slug)
+	providerRepo.AssertNotCalled(t, "GetBySlug", mock.Anything, mock.Anything)
+}
+
+func TestSaveProvider_KeepsClientSecret(t *testing.T) {
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, mock.Anything).Return(domain.Role{}, nil)
+	providerRepo := new(mocks.IdentityProviderRepository)
+	providerRepo.On("GetBySlug", mock.Anything, "corporate").Once().Return(domain.IdentityProvider{ClientSecret: "stored"}, nil)
+	providerRepo.On("Save", mock.Anything, mock.MatchedBy(func(provider domain.IdentityProvider) bool {
+		return provider.ClientSecret == "stored"
+	})).Once().Return(domain.IdentityProvider{Slug: "corporate"}, nil)
+
+	provider := providerToSave()
+	provider.ClientSecret = ""
+	_, err := newService(providerRepo, roleRepo, nil, nil).SaveProvider(context.TODO(), provider)
+	assert.Nil(t, err)
+	providerRepo.AssertExpectations(t)
+}
+
+func TestSaveProvider_PublicClient(t *testing.T) {
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, mock.Anything).Return(domain.Role{}, nil)
+	providerRepo := new(mocks.IdentityProviderRepository)
+	providerRepo.On("GetBySlug", mock.Anything, "corporate").Once().Return(domain.IdentityProvider{}, sql.ErrNoRows)
+	providerRepo.On("Save", mock.Anything, mock.MatchedBy(func(provider domain.IdentityProvider) bool {
+		return provider.ClientSecret == ""
+	})).Once().Return(domain.IdentityProvider{Slug: "corporate"}, nil)
+
+	provider := providerToSave()
+	provider.ClientSecret = ""
+	_, err := newService(providerRepo, roleRepo, nil, nil).SaveProvider(context.TODO(), provider)
+	assert.Nil(t, err)
+	providerRepo.AssertExpectations(t)
+}
+
+func TestDeleteProvider(t *testing.T) {
+	providerRepo := new(mocks.IdentityProviderRepository)
+	providerRepo.On("Delete", mock.Anything, "corporate").Once().Return(domain.ErrNotFound)
+
+	err := newService(providerRepo, nil, nil, nil).DeleteProvider(context.TODO(), "")
+	assert.Equal(t, domain.ErrBadParamInput, err)
+
+	err = newService(providerRepo, nil, nil, nil).DeleteProvider(context.TODO(), "corporate")
+	assert.Equal(t, domain.ErrNotFound, err)
+}
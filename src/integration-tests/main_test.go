@@ -13,11 +13,44 @@ import (
 	_ "github.com/lib/pq"
 	"github.com/ory/dockertest/v3"
 	"github.com/ory/dockertest/v3/docker"
+	_attributesRepo "github.com/plagioriginal/user-microservice/attributes/repository/postgres"
+	_attributesService "github.com/plagioriginal/user-microservice/attributes/service"
+	_dataExportsRepo "github.com/plagioriginal/user-microservice/data-exports/repository/postgres"
+	_dataExportsService "github.com/plagioriginal/user-microservice/data-exports/service"
 	"github.com/plagioriginal/user-microservice/database"
 	"github.com/plagioriginal/user-microservice/domain"
+	_emailVerificationService "github.com/plagioriginal/user-microservice/email-verification/service"
+	_groupsRepo "github.com/plagioriginal/user-microservice/groups/repository/postgres"
+	_groupsService "github.com/plagioriginal/user-microservice/groups/service"
+	_identityProvidersRepo "github.com/plagioriginal/user-microservice/identity-providers/repository/postgres"
+	_identityProvidersService "github.com/plagioriginal/user-microservice/identity-providers/service"
+	_invitationsService "github.com/plagioriginal/user-microservice/invitations/service"
+	_ldapAuthenticator "github.com/plagioriginal/user-microservice/ldap/authenticator"
+	"github.com/plagioriginal/user-microservice/ldap/mockldap"
+	_loginAttemptsRepo "github.com/plagioriginal/user-microservice/login-attempts/repository/postgres"
+	_loginAttemptsService "github.com/plagioriginal/user-microservice/login-attempts/service"
+	_loginEventsRepo "github.com/plagioriginal/user-microservice/login-events/repository/postgres"
+	_loginEventsService "github.com/plagioriginal/user-microservice/login-events/service"
+	_magicLinksService "github.com/plagioriginal/user-microservice/magic-links/service"
+	"github.com/plagioriginal/user-microservice/mailer"
+	_mfaRepo "github.com/plagioriginal/user-microservice/mfa/repository/postgres"
+	_mfaService "github.com/plagioriginal/user-microservice/mfa/service"
+	_oneTimeTokensRepo "github.com/plagioriginal/user-microservice/one-time-tokens/repository/postgres"
+	_oneTimeTokensService "github.com/plagioriginal/user-microservice/one-time-tokens/service"
+	_organizationsRepo "github.com/plagioriginal/user-microservice/organizations/repository/postgres"
+	_organizationsService "github.com/plagioriginal/user-microservice/organizations/service"
+	_passkeysRepo "github.com/plagioriginal/user-microservice/passkeys/repository/postgres"
+	_passkeysService "github.com/plagioriginal/user-microservice/passkeys/service"
+	_passwordResetService "github.com/plagioriginal/user-microservice/password-reset/service"
 	_refreshTokensRepo "github.com/plagioriginal/user-microservice/refresh-tokens/repository/postgres"
 	_refreshTokensService "github.com/plagioriginal/user-microservice/refresh-tokens/service"
+	_registrationService "github.com/plagioriginal/user-microservice/registration/service"
 	_rolesRepo "github.com/plagioriginal/user-microservice/roles/repository/postgres"
+	_rolesService "github.com/plagioriginal/user-microservice/roles/service"
+	_userBackupService "github.com/plagioriginal/user-microservice/user-backups/service"
+	_userDeletionService "github.com/plagioriginal/user-microservice/user-deletion/service"
+	_userImportService "github.com/plagioriginal/user-microservice/user-import/service"
+	_usernameHistoryRepo "github.com/plagioriginal/user-microservice/username-history/repository/postgres"
 	"github.com/plagioriginal/user-microservice/users/handler"
 	_usersRepo "github.com/plagioriginal/user-microservice/users/repository/postgres"
 	_usersService "github.com/plagioriginal/user-microservice/users/service"
@@ -31,8 +64,36 @@ import (
 var (
 	db               *sql.DB
 	refreshTokenRepo domain.RefreshTokenRepository
-	userClient       users.UsersClient
-	databaseSettings database.MigrationSettings
+	// The purger isn't running, the tests purge the deleted users themselves.
+	userDeletionService domain.UserDeletionService
+	userClient          users.UsersClient
+	databaseSettings    database.MigrationSettings
+	// If the default user was created having to change its password, which the tests clear to log in with it.
+	defaultUserMustChangePassword bool
+	testMailer                    *mailer.MemoryMailer
+	// Directory the users can also log in with, after the local passwords.
+	testDirectory *mockldap.Server
+
+	loginThrottleSettings = domain.LoginThrottleSettings{
+		MaxFailedAttemptsPerUser: 3,
+		MaxFailedAttemptsPerIP:   50,
+		LockoutDuration:          time.Minute,
+		MaxLockoutDuration:       time.Hour,
+		FailureWindow:            time.Hour,
+	}
+
+	passwordResetSettings = domain.PasswordResetSettings{
+		ResetURL:             "http://localhost/reset-password",
+		TokenTTL:             time.Hour,
+		MaxRequestsPerWindow: 2,
+		RequestWindow:        time.Hour,
+	}
+
+	userDeletionSettings = domain.UserDeletionSettings{
+		RetentionPeriod: 24 * time.Hour,
+		PurgeInterval:   time.Hour,
+		PurgeBatchSize:  10,
+	}
 )
 
 type testDatabaseSettings struct {
@@ -65,6 +126,9 @@ func TestMain(m *testing.M) {
 	}
 
 	database.DoMigrations(logger, db, databaseSettings)
+	clearDefaultUserPasswordChange(logger)
+	startTestDirectory(logger)
+	defer testDirectory.Close()
 
 	grpcServerStarter, grpcServerFinisher, listener := setupGrpcServer(db, logger)
 	defer grpcServerFinisher()
@@ -84,6 +148,29 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
+// Lets the tests log in with the default user without changing its password first.
+func clearDefaultUserPasswordChange(logger *log.Logger) {
+	username := databaseSettings.DefaultUserUsername
+	if err := db.QueryRow(`SELECT must_change_password FROM users WHERE username = $1`, username).Scan(&defaultUserMustChangePassword); err != nil {
+		logger.Fatalf("could not get the default user: %s", err)
+	}
+	if _, err := db.Exec(`UPDATE users SET must_change_password = false WHERE username = $1`, username); err != nil {
+		logger.Fatalf("could not clear the password change of the default user: %s", err)
+	}
+}
+
+// Starts the directory with the base entries and the search account.
+func startTestDirectory(logger *log.Logger) {
+	var err error
+	if testDirectory, err = mockldap.New(); err != nil {
+		logger.Fatalf("could not start the directory: %s", err)
+	}
+
+	testDirectory.AddEntry("dc=example,dc=com", map[string][]string{"objectClass": {"domain"}})
+	testDirectory.AddEntry("ou=people,dc=example,dc=com", map[string][]string{"objectClass": {"organizationalUnit"}})
+	testDirectory.AddEntry("cn=service,dc=example,dc=com", map[string][]string{"userPassword": {"service secret"}})
+}
+
 func createPool(logger *log.Logger) *dockertest.Pool {
 	pool, err := dockertest.NewPool("")
 	if err != nil {
@@ -136,19 +223,215 @@ func setupGrpcServer(dbSource *sql.DB, logger *log.Logger) (func(), func(), *buf
 	userRepo := _usersRepo.New(db)
 	roleRepo := _rolesRepo.New(db)
 	refreshTokenRepo = _refreshTokensRepo.New(db)
+	loginAttemptRepo := _loginAttemptsRepo.New(db)
+	oneTimeTokenRepo := _oneTimeTokensRepo.New(db)
+	mfaRepo := _mfaRepo.New(db)
+	passkeyRepo := _passkeysRepo.New(db)
+	attributeRepo := _attributesRepo.New(db)
+	groupRepo := _groupsRepo.New(db)
+	organizationRepo := _organizationsRepo.New(db)
+	loginEventRepo := _loginEventsRepo.New(db)
+	usernameHistoryRepo := _usernameHistoryRepo.New(db)
+	identityProviderRepo := _identityProvidersRepo.New(db)
+	testMailer = mailer.NewMemoryMailer()
 
 	// Creating all the services.
 	refreshTokenService := _refreshTokensService.New(logger, refreshTokenRepo, userRepo, time.Duration(10*time.Second))
-	tokenManager := tokens.NewTokenManager("secret", refreshTokenService, roleRepo)
-	userService := _usersService.New(userRepo, roleRepo, time.Duration(10*time.Second), _usersService.TestingBcryptCost)
+	tokenManager := tokens.NewTokenManager("secret", refreshTokenService, roleRepo, attributeRepo, groupRepo, usernameHistoryRepo, userRepo)
+	ldapAuthenticator := _ldapAuthenticator.New(logger, userRepo, roleRepo, domain.LDAPSettings{
+		URL:                testDirectory.URL(),
+		StartTLS:           true,
+		RootCAs:            testDirectory.RootCAs(),
+		BindDN:             "cn=service,dc=example,dc=com",
+		BindPassword:       "service secret",
+		BaseDN:             "ou=people,dc=example,dc=com",
+		UserFilter:         "(uid=%s)",
+		EmailAttribute:     "mail",
+		FirstNameAttribute: "givenName",
+		LastNameAttribute:  "sn",
+		GroupAttribute:     "memberOf",
+		RoleMapping:        map[string]string{"cn=admins,ou=groups,dc=example,dc=com": "admin"},
+		DefaultRole:        "user",
+		AutoProvision:      true,
+		Timeout:            5 * time.Second,
+	})
+	userService := _usersService.New(
+		userRepo,
+		roleRepo,
+		usernameHistoryRepo,
+		time.Duration(10*time.Second),
+		_usersService.TestingBcryptCost,
+		domain.UsernameSettings{ReservationPeriod: 30 * 24 * time.Hour},
+		[]domain.Authenticator{
+			_usersService.NewLocalAuthenticator(userRepo, roleRepo, _usersService.TestingBcryptCost),
+			ldapAuthenticator,
+		},
+	)
+	loginAttemptService := _loginAttemptsService.New(logger, loginAttemptRepo, time.Duration(10*time.Second), loginThrottleSettings)
+	oneTimeTokenService := _oneTimeTokensService.New(logger, oneTimeTokenRepo, time.Duration(10*time.Second))
+	emailVerificationService := _emailVerificationService.New(
+		logger,
+		userRepo,
+		userService,
+		oneTimeTokenService,
+		testMailer,
+		time.Duration(10*time.Second),
+		domain.EmailVerificationSettings{
+			VerificationURL: "http://localhost/verify-email",
+			TokenTTL:        time.Hour,
+		},
+	)
+	sendQueue := mailer.NewQueue(2, 100)
+	passwordResetService := _passwordResetService.New(
+		logger,
+		userRepo,
+		refreshTokenRepo,
+		oneTimeTokenService,
+		mailer.NewEmailNotifier(testMailer),
+		sendQueue,
+		time.Duration(10*time.Second),
+		_usersService.TestingBcryptCost,
+		passwordResetSettings,
+	)
+
+	invitationService := _invitationsService.New(
+		logger,
+		userRepo,
+		userService,
+		oneTimeTokenRepo,
+		oneTimeTokenService,
+		mailer.NewEmailNotifier(testMailer),
+		time.Duration(10*time.Second),
+		_usersService.TestingBcryptCost,
+		domain.InvitationSettings{
+			AcceptURL:         "http://localhost/accept-invitation",
+			TokenTTL:          time.Hour,
+			MinPasswordLength: 8,
+		},
+	)
+
+	magicLinkService := _magicLinksService.New(
+		logger,
+		userRepo,
+		userService,
+		oneTimeTokenService,
+		mailer.NewEmailNotifier(testMailer),
+		sendQueue,
+		time.Duration(10*time.Second),
+		domain.MagicLinkSettings{
+			LoginURL:             "http://localhost/magic-link",
+			TokenTTL:             10 * time.Minute,
+			MaxRequestsPerWindow: 5,
+			RequestWindow:        time.Hour,
+		},
+	)
+
+	oidcService := _identityProvidersService.New(
+		logger,
+		identityProviderRepo,
+		roleRepo,
+		userRepo,
+		userService,
+		time.Duration(10*time.Second),
+		domain.OIDCSettings{
+			SessionTTL:          10 * time.Minute,
+			DiscoveryTTL:        time.Hour,
+			HTTPTimeout:         5 * time.Second,
+			AllowPrivateIssuers: true,
+		},
+	)
 
-	gs := grpc.NewServer()
-	handler := handler.NewUserGRPCHandler(logger, tokenManager, userService)
+	registrationService := _registrationService.New(
+		logger,
+		userService,
+		time.Duration(10*time.Second),
+		domain.RegistrationSettings{
+			Enabled:         true,
+			DefaultRoleSlug: domain.DEFAULT_ROLE_USER.RoleSlug,
+		},
+	)
+
+	mfaService := _mfaService.New(
+		logger,
+		mfaRepo,
+		time.Duration(10*time.Second),
+		domain.MFASettings{
+			Issuer:             "Users Service",
+			EncryptionKey:      []byte("0123456789abcdef0123456789abcdef"),
+			RecoveryCodesCount: 3,
+		},
+	)
+
+	passkeyService := _passkeysService.New(
+		logger,
+		passkeyRepo,
+		userService,
+		time.Duration(10*time.Second),
+		domain.PasskeySettings{
+			RPID:          "localhost",
+			RPDisplayName: "Users Service",
+			RPOrigins:     []string{passkeyOrigin},
+			SessionTTL:    time.Duration(5 * time.Minute),
+		},
+	)
+
+	userDeletionService = _userDeletionService.New(
+		logger,
+		userRepo,
+		userService,
+		time.Duration(10*time.Second),
+		userDeletionSettings,
+	)
+
+	dataExportService := _dataExportsService.New(
+		logger,
+		_dataExportsRepo.New(db),
+		userService,
+		refreshTokenRepo,
+		mfaRepo,
+		passkeyRepo,
+		oneTimeTokenRepo,
+		groupRepo,
+		loginEventRepo,
+		usernameHistoryRepo,
+		identityProviderRepo,
+		time.Duration(10*time.Second),
+	)
+
+	attributeService := _attributesService.New(logger, attributeRepo, userRepo, time.Duration(10*time.Second))
+	groupService := _groupsService.New(logger, groupRepo, userRepo, roleRepo, time.Duration(10*time.Second))
+	roleService := _rolesService.New(logger, roleRepo, time.Duration(10*time.Second))
+	organizationService := _organizationsService.New(logger, organizationRepo, roleRepo, userService, time.Duration(10*time.Second))
+	loginEventService := _loginEventsService.New(logger, loginEventRepo, userRepo, time.Duration(10*time.Second))
+
+	userImportService := _userImportService.New(
+		logger,
+		userRepo,
+		roleRepo,
+		usernameHistoryRepo,
+		time.Duration(10*time.Second),
+		_usersService.TestingBcryptCost,
+		domain.UserImportSettings{BatchSize: 2, MinPasswordLength: 8},
+	)
+
+	userBackupService := _userBackupService.New(logger, userRepo, roleRepo, groupRepo, time.Duration(10*time.Second), userDeletionSettings.RetentionPeriod)
+
+	// The test clients connect as if they were the API Gateway, which is trusted to forward their IPs.
+	trustedProxies, err := handler.ParseTrustedProxies([]string{"127.0.0.1"})
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	gs := grpc.NewServer(
+		grpc.UnaryInterceptor(handler.OrganizationUnaryInterceptor),
+		grpc.StreamInterceptor(handler.OrganizationStreamInterceptor),
+	)
+	handler := handler.NewUserGRPCHandler(logger, tokenManager, userService, loginAttemptService, emailVerificationService, passwordResetService, registrationService, mfaService, passkeyService, userDeletionService, dataExportService, attributeService, userImportService, userBackupService, groupService, organizationService, loginEventService, invitationService, magicLinkService, oidcService, roleService, trustedProxies)
 	users.RegisterUsersServer(gs, handler)
 
 	listener := bufconn.Listen(1024 * 1024)
 	return func() {
-			if err := gs.Serve(listener); err != nil {
+			if err := gs.Serve(loopbackListener{listener}); err != nil {
 				logger.Fatalf("failed to serve grpc test server: %v", err)
 			}
 		}, func() {
@@ -157,6 +440,27 @@ func setupGrpcServer(dbSource *sql.DB, logger *log.Logger) (func(), func(), *buf
 		}, listener
 }
 
+// Listener of in-memory connections coming from the loopback address, like the ones of a local gateway.
+type loopbackListener struct {
+	*bufconn.Listener
+}
+
+func (l loopbackListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return loopbackConn{conn}, nil
+}
+
+type loopbackConn struct {
+	net.Conn
+}
+
+func (c loopbackConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50000}
+}
+
 func setupGrpcClient(listener *bufconn.Listener, logger *log.Logger) func() {
 	grpcDialer := func(context.Context, string) (net.Conn, error) {
 		return listener.Dial()
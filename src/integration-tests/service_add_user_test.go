@@ -63,6 +63,21 @@ func Test_Grpc_Add_User(t *testing.T) {
 			wantedErrCode:    0,
 			wantedErrMessage: "",
 		},
+		{
+			name: "username already taken with another casing",
+			loginReq: &users.LoginRequest{
+				Username: databaseSettings.DefaultUserUsername,
+				Password: databaseSettings.DefaultUserPassword,
+			},
+			req: &users.NewUserRequest{
+				Username: " New-User",
+				Password: "dummy-password",
+				Role:     "user",
+			},
+			wantedRes:        nil,
+			wantedErrCode:    codes.AlreadyExists,
+			wantedErrMessage: "username or email already taken",
+		},
 		{
 			name: "user trying to add is not admin",
 			loginReq: &users.LoginRequest{
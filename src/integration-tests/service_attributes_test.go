@@ -0,0 +1,102 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Grpc_User_Attributes(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	_, err = userClient.SaveAttributeDefinition(context.Background(), &users.SaveAttributeDefinitionRequest{
+		AccessToken: adminLogin.AccessToken,
+		Name:        "department",
+		Type:        "string",
+		InToken:     true,
+	})
+	assert.Nil(t, err)
+	_, err = userClient.SaveAttributeDefinition(context.Background(), &users.SaveAttributeDefinitionRequest{
+		AccessToken:  adminLogin.AccessToken,
+		Name:         "timezone",
+		Type:         "string",
+		UserEditable: true,
+	})
+	assert.Nil(t, err)
+
+	user, err := userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "attributes-user",
+		Password:    "password",
+		Role:        "user",
+	})
+	assert.Nil(t, err)
+
+	userLogin, err := userClient.Login(context.Background(), &users.LoginRequest{Username: "attributes-user", Password: "password"})
+	assert.Nil(t, err)
+
+	patched, err := userClient.PatchUserAttributes(context.Background(), &users.PatchUserAttributesRequest{
+		AccessToken:    userLogin.AccessToken,
+		Version:        user.Version,
+		AttributesJson: `{"timezone":"Europe/Lisbon"}`,
+	})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"timezone":"Europe/Lisbon"}`, patched.AttributesJson)
+
+	_, err = userClient.PatchUserAttributes(context.Background(), &users.PatchUserAttributesRequest{
+		AccessToken:    userLogin.AccessToken,
+		Version:        patched.Version,
+		AttributesJson: `{"department":"sales"}`,
+	})
+	assert.Equal(t, status.Error(codes.PermissionDenied, "attribute can only be changed by administrators"), err)
+
+	_, err = userClient.PatchUserAttributes(context.Background(), &users.PatchUserAttributesRequest{
+		AccessToken:    adminLogin.AccessToken,
+		UserId:         user.Id,
+		Version:        user.Version,
+		AttributesJson: `{"department":"sales"}`,
+	})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	_, err = userClient.PatchUserAttributes(context.Background(), &users.PatchUserAttributesRequest{
+		AccessToken:    adminLogin.AccessToken,
+		UserId:         user.Id,
+		Version:        patched.Version,
+		AttributesJson: `{"department":"sales"}`,
+	})
+	assert.Nil(t, err)
+
+	attributes, err := userClient.GetUserAttributes(context.Background(), &users.GetUserAttributesRequest{AccessToken: userLogin.AccessToken})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"department":"sales","timezone":"Europe/Lisbon"}`, attributes.AttributesJson)
+
+	listed, err := userClient.ListUsers(context.Background(), &users.ListUsersRequest{
+		AccessToken:    adminLogin.AccessToken,
+		AttributesJson: `{"department":"sales"}`,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, listed.Users, 1)
+	assert.Equal(t, user.Id, listed.Users[0].Id)
+
+	// Deleting the definition removes the attribute from the users.
+	_, err = userClient.DeleteAttributeDefinition(context.Background(), &users.DeleteAttributeDefinitionRequest{
+		AccessToken: adminLogin.AccessToken,
+		Name:        "department",
+	})
+	assert.Nil(t, err)
+
+	attributes, err = userClient.GetUserAttributes(context.Background(), &users.GetUserAttributesRequest{
+		AccessToken: adminLogin.AccessToken,
+		UserId:      user.Id,
+	})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"timezone":"Europe/Lisbon"}`, attributes.AttributesJson)
+}
@@ -0,0 +1,131 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Grpc_DefaultUserMustChangePassword(t *testing.T) {
+	assert.True(t, defaultUserMustChangePassword)
+}
+
+func Test_Grpc_ChangePassword(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	created, err := userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken:        adminLogin.AccessToken,
+		Username:           "forced-change",
+		Password:           "given-password",
+		Role:               "user",
+		MustChangePassword: true,
+	})
+	assert.Nil(t, err)
+	assert.True(t, created.MustChangePassword)
+
+	// The login only gives a token to change the password, which isn't an access token.
+	login, err := userClient.Login(context.Background(), &users.LoginRequest{Username: "forced-change", Password: "given-password"})
+	assert.Nil(t, err)
+	assert.True(t, login.PasswordChangeRequired)
+	assert.Empty(t, login.AccessToken)
+	assert.Empty(t, login.RefreshToken)
+
+	_, err = userClient.GetLoginHistory(context.Background(), &users.GetLoginHistoryRequest{AccessToken: login.PasswordChangeToken})
+	assert.Equal(t, status.Error(codes.Unauthenticated, "invalid token"), err)
+
+	_, err = userClient.ChangePassword(context.Background(), &users.ChangePasswordRequest{
+		PasswordChangeToken: login.PasswordChangeToken,
+		NewPassword:         "given-password",
+	})
+	assert.Equal(t, status.Error(codes.InvalidArgument, "new password must be different"), err)
+
+	changed, err := userClient.ChangePassword(context.Background(), &users.ChangePasswordRequest{
+		PasswordChangeToken: login.PasswordChangeToken,
+		NewPassword:         "chosen-password",
+	})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, changed.AccessToken)
+	assert.False(t, changed.User.MustChangePassword)
+
+	// The token can't change the password once it isn't required anymore.
+	_, err = userClient.ChangePassword(context.Background(), &users.ChangePasswordRequest{
+		PasswordChangeToken: login.PasswordChangeToken,
+		NewPassword:         "another-password",
+	})
+	assert.Equal(t, status.Error(codes.Unauthenticated, "invalid token"), err)
+
+	_, err = userClient.Login(context.Background(), &users.LoginRequest{Username: "forced-change", Password: "given-password"})
+	assert.NotNil(t, err)
+	login, err = userClient.Login(context.Background(), &users.LoginRequest{Username: "forced-change", Password: "chosen-password"})
+	assert.Nil(t, err)
+	assert.False(t, login.PasswordChangeRequired)
+	assert.NotEmpty(t, login.AccessToken)
+
+	// Logged in users have to give their current password.
+	_, err = userClient.ChangePassword(context.Background(), &users.ChangePasswordRequest{
+		AccessToken:     login.AccessToken,
+		CurrentPassword: "wrong-password",
+		NewPassword:     "another-password",
+	})
+	assert.Equal(t, status.Error(codes.InvalidArgument, "invalid current password"), err)
+
+	_, err = userClient.ChangePassword(context.Background(), &users.ChangePasswordRequest{
+		AccessToken:     login.AccessToken,
+		CurrentPassword: "chosen-password",
+		NewPassword:     "another-password",
+	})
+	assert.Nil(t, err)
+	_, err = userClient.Login(context.Background(), &users.LoginRequest{Username: "forced-change", Password: "another-password"})
+	assert.Nil(t, err)
+}
+
+func Test_Grpc_PasswordExpiry(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	_, err = userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "expired-password",
+		Password:    "old-password",
+		Role:        "user",
+	})
+	assert.Nil(t, err)
+
+	_, err = db.Exec(`UPDATE roles SET password_max_age_days = 30 WHERE role_slug = 'user'`)
+	assert.Nil(t, err)
+	defer db.Exec(`UPDATE roles SET password_max_age_days = 0 WHERE role_slug = 'user'`)
+
+	// Passwords younger than the max age are fine.
+	login, err := userClient.Login(context.Background(), &users.LoginRequest{Username: "expired-password", Password: "old-password"})
+	assert.Nil(t, err)
+	assert.False(t, login.PasswordChangeRequired)
+
+	_, err = db.Exec(`UPDATE users SET password_changed_at = NOW() - INTERVAL '31 days' WHERE username = 'expired-password'`)
+	assert.Nil(t, err)
+
+	login, err = userClient.Login(context.Background(), &users.LoginRequest{Username: "expired-password", Password: "old-password"})
+	assert.Nil(t, err)
+	assert.True(t, login.PasswordChangeRequired)
+
+	_, err = userClient.ChangePassword(context.Background(), &users.ChangePasswordRequest{
+		PasswordChangeToken: login.PasswordChangeToken,
+		NewPassword:         "new-password",
+	})
+	assert.Nil(t, err)
+
+	login, err = userClient.Login(context.Background(), &users.LoginRequest{Username: "expired-password", Password: "new-password"})
+	assert.Nil(t, err)
+	assert.False(t, login.PasswordChangeRequired)
+	assert.NotEmpty(t, login.AccessToken)
+}
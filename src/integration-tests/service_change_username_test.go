@@ -0,0 +1,97 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Grpc_ChangeUsername(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	for _, username := range []string{"rename-user", "rename-other"} {
+		_, err = userClient.AddUser(context.Background(), &users.NewUserRequest{
+			AccessToken: adminLogin.AccessToken,
+			Username:    username,
+			Password:    "password",
+			Role:        "user",
+		})
+		assert.Nil(t, err)
+	}
+
+	login, err := userClient.Login(context.Background(), &users.LoginRequest{Username: "rename-user", Password: "password"})
+	assert.Nil(t, err)
+
+	// Usernames of other users are taken.
+	_, err = userClient.ChangeUsername(context.Background(), &users.ChangeUsernameRequest{AccessToken: login.AccessToken, Version: login.User.Version, Username: "Rename-Other"})
+	assert.Equal(t, status.Error(codes.AlreadyExists, "username already taken"), err)
+
+	renamed, err := userClient.ChangeUsername(context.Background(), &users.ChangeUsernameRequest{AccessToken: login.AccessToken, Version: login.User.Version, Username: "renamed-user"})
+	assert.Nil(t, err)
+	assert.Equal(t, "renamed-user", renamed.Username)
+	assert.Equal(t, login.User.Id, renamed.Id)
+
+	// The access token with the old username stops working, refreshing gives one with the new username.
+	_, err = userClient.GetLoginHistory(context.Background(), &users.GetLoginHistoryRequest{AccessToken: login.AccessToken})
+	assert.Equal(t, status.Error(codes.Unauthenticated, "invalid token"), err)
+
+	refreshed, err := userClient.Refresh(context.Background(), &users.RefreshRequest{RefreshToken: login.RefreshToken})
+	assert.Nil(t, err)
+	assert.Equal(t, "renamed-user", refreshed.User.Username)
+	_, err = userClient.GetLoginHistory(context.Background(), &users.GetLoginHistoryRequest{AccessToken: refreshed.AccessToken})
+	assert.Nil(t, err)
+
+	// The previous username is part of the data export of the user.
+	stream, err := userClient.ExportMyData(context.Background(), &users.ExportMyDataRequest{AccessToken: refreshed.AccessToken})
+	assert.Nil(t, err)
+	export, _, err := receiveDataExport(t, stream)
+	assert.Nil(t, err)
+	assert.Len(t, export.UsernameHistory, 1)
+	assert.Equal(t, "rename-user", export.UsernameHistory[0].OldUsername)
+	assert.Equal(t, "renamed-user", export.UsernameHistory[0].NewUsername)
+
+	_, err = userClient.Login(context.Background(), &users.LoginRequest{Username: "rename-user", Password: "password"})
+	assert.NotNil(t, err)
+	_, err = userClient.Login(context.Background(), &users.LoginRequest{Username: "renamed-user", Password: "password"})
+	assert.Nil(t, err)
+
+	// The old username is reserved for its user.
+	_, err = userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "RENAME-USER",
+		Password:    "password",
+		Role:        "user",
+	})
+	assert.Equal(t, status.Error(codes.AlreadyExists, "username or email already taken"), err)
+
+	otherLogin, err := userClient.Login(context.Background(), &users.LoginRequest{Username: "rename-other", Password: "password"})
+	assert.Nil(t, err)
+	_, err = userClient.ChangeUsername(context.Background(), &users.ChangeUsernameRequest{AccessToken: otherLogin.AccessToken, Version: otherLogin.User.Version, Username: "rename-user"})
+	assert.Equal(t, status.Error(codes.AlreadyExists, "username already taken"), err)
+
+	// Only administrators rename others, and users can take back their old usernames.
+	_, err = userClient.ChangeUsername(context.Background(), &users.ChangeUsernameRequest{
+		AccessToken: otherLogin.AccessToken,
+		UserId:      login.User.Id,
+		Version:     renamed.Version,
+		Username:    "hijacked",
+	})
+	assert.Equal(t, status.Error(codes.Unauthenticated, "incorrect permissions"), err)
+
+	restored, err := userClient.ChangeUsername(context.Background(), &users.ChangeUsernameRequest{
+		AccessToken: adminLogin.AccessToken,
+		UserId:      login.User.Id,
+		Version:     renamed.Version,
+		Username:    "rename-user",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "rename-user", restored.Username)
+}
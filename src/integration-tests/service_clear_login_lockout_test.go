@@ -0,0 +1,88 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Grpc_Login_Lockout(t *testing.T) {
+	// A dedicated client IP, so the lockout doesn't leak into the other tests.
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-forwarded-for", "203.0.113.26")
+	req := &users.LoginRequest{
+		Username: "throttled-user",
+		Password: "wrong password",
+	}
+
+	for i := 1; i < loginThrottleSettings.MaxFailedAttemptsPerUser; i++ {
+		_, err := userClient.Login(ctx, req)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	}
+
+	// The attempt reaching the threshold locks the username.
+	_, err := userClient.Login(ctx, req)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	_, err = userClient.Login(ctx, req)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name             string
+		req              *users.ClearLoginLockoutRequest
+		wantedErrCode    codes.Code
+		wantedErrMessage string
+	}{
+		{
+			name:             "invalid token",
+			req:              &users.ClearLoginLockoutRequest{Username: "throttled-user"},
+			wantedErrCode:    codes.Unauthenticated,
+			wantedErrMessage: "invalid token",
+		},
+		{
+			name:             "invalid request",
+			req:              &users.ClearLoginLockoutRequest{AccessToken: adminLogin.AccessToken},
+			wantedErrCode:    codes.InvalidArgument,
+			wantedErrMessage: "invalid request",
+		},
+		{
+			name: "success",
+			req: &users.ClearLoginLockoutRequest{
+				AccessToken: adminLogin.AccessToken,
+				Username:    "throttled-user",
+				Ip:          "203.0.113.26",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := userClient.ClearLoginLockout(context.Background(), test.req)
+			if test.wantedErrCode != 0 {
+				assert.Nil(t, res)
+				s, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, s.Code(), test.wantedErrCode)
+				assert.Equal(t, s.Message(), test.wantedErrMessage)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NotNil(t, res)
+		})
+	}
+
+	// Once cleared, the login is attempted again.
+	_, err = userClient.Login(ctx, req)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
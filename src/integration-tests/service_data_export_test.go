@@ -0,0 +1,117 @@
+package integration_tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Chunk receiving side of both data export streams.
+type dataExportClient interface {
+	Recv() (*users.DataExportChunk, error)
+}
+
+// Reads a whole data export from its stream.
+func receiveDataExport(t *testing.T, stream dataExportClient) (domain.DataExport, []byte, error) {
+	document := []byte{}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return domain.DataExport{}, nil, err
+		}
+		document = append(document, chunk.Data...)
+	}
+
+	export := domain.DataExport{}
+	assert.Nil(t, json.Unmarshal(document, &export))
+	return export, document, nil
+}
+
+func Test_Grpc_Export_Data(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	user, err := userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "exported-user",
+		Password:    "exported-password",
+		Role:        "user",
+		Email:       "exported@example.com",
+	})
+	assert.Nil(t, err)
+
+	group, err := userClient.SaveGroup(context.Background(), &users.SaveGroupRequest{AccessToken: adminLogin.AccessToken, Name: "Exported"})
+	assert.Nil(t, err)
+	_, err = userClient.AddGroupMember(context.Background(), &users.GroupMemberRequest{
+		AccessToken: adminLogin.AccessToken,
+		GroupId:     group.Id,
+		UserId:      user.Id,
+	})
+	assert.Nil(t, err)
+
+	userLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "exported-user",
+		Password: "exported-password",
+	})
+	assert.Nil(t, err)
+
+	stream, err := userClient.ExportMyData(context.Background(), &users.ExportMyDataRequest{})
+	assert.Nil(t, err)
+	_, _, err = receiveDataExport(t, stream)
+	assert.Equal(t, status.Error(codes.Unauthenticated, "invalid token"), err)
+
+	stream, err = userClient.ExportMyData(context.Background(), &users.ExportMyDataRequest{AccessToken: userLogin.AccessToken})
+	assert.Nil(t, err)
+	export, document, err := receiveDataExport(t, stream)
+	assert.Nil(t, err)
+	assert.Equal(t, domain.DataExportFormatVersion, export.FormatVersion)
+	assert.Equal(t, user.Id, export.Profile.ID.String())
+	assert.Equal(t, "exported@example.com", export.Profile.Email)
+	assert.Equal(t, "user", export.Profile.Role.RoleSlug)
+	assert.NotNil(t, export.Session)
+	assert.Len(t, export.Groups, 1)
+	assert.Equal(t, group.Id, export.Groups[0].ID.String())
+	assert.Len(t, export.LoginEvents, 1)
+	assert.Equal(t, domain.LoginEventLogin, export.LoginEvents[0].Type)
+	assert.True(t, export.LoginEvents[0].Success)
+	assert.NotContains(t, string(document), userLogin.RefreshToken)
+	assert.NotContains(t, string(document), "$2a$")
+	assert.Len(t, export.Exports, 1)
+	assert.Equal(t, export.Profile.ID, export.Exports[0].RequestedBy)
+
+	userStream, err := userClient.ExportUserData(context.Background(), &users.ExportUserDataRequest{
+		AccessToken: userLogin.AccessToken,
+		UserId:      user.Id,
+	})
+	assert.Nil(t, err)
+	_, _, err = receiveDataExport(t, userStream)
+	assert.Equal(t, status.Error(codes.Unauthenticated, "incorrect permissions"), err)
+
+	adminStream, err := userClient.ExportUserData(context.Background(), &users.ExportUserDataRequest{
+		AccessToken: adminLogin.AccessToken,
+		UserId:      user.Id,
+	})
+	assert.Nil(t, err)
+	export, _, err = receiveDataExport(t, adminStream)
+	assert.Nil(t, err)
+	assert.Len(t, export.Exports, 2)
+	assert.Equal(t, adminLogin.User.Id, export.Exports[1].RequestedBy.String())
+
+	var recorded int
+	err = db.QueryRow(`SELECT count(*) FROM data_exports WHERE user_id = $1`, user.Id).Scan(&recorded)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, recorded)
+}
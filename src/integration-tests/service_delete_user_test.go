@@ -0,0 +1,102 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Grpc_Delete_Restore_And_Purge_User(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	newUserReq := &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "deleted-user",
+		Password:    "password",
+		Role:        "user",
+	}
+	user, err := userClient.AddUser(context.Background(), newUserReq)
+	assert.Nil(t, err)
+
+	loginReq := &users.LoginRequest{Username: "deleted-user", Password: "password"}
+	userLogin, err := userClient.Login(context.Background(), loginReq)
+	assert.Nil(t, err)
+
+	_, err = userClient.DeleteUser(context.Background(), &users.DeleteUserRequest{
+		AccessToken: userLogin.AccessToken,
+		UserId:      user.Id,
+		Version:     user.Version,
+	})
+	assert.Equal(t, status.Error(codes.Unauthenticated, "incorrect permissions"), err)
+
+	_, err = userClient.DeleteUser(context.Background(), &users.DeleteUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		UserId:      user.Id,
+		Version:     user.Version,
+	})
+	assert.Nil(t, err)
+
+	// The session ended with the deletion, and the user is gone.
+	_, err = userClient.Refresh(context.Background(), &users.RefreshRequest{RefreshToken: userLogin.RefreshToken})
+	assert.Error(t, err)
+	_, err = userClient.Login(context.Background(), loginReq)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// The username stays taken until the user is purged.
+	_, err = userClient.AddUser(context.Background(), newUserReq)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	restored, err := userClient.RestoreUser(context.Background(), &users.RestoreUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		UserId:      user.Id,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, user.Id, restored.Id)
+	_, err = userClient.Login(context.Background(), loginReq)
+	assert.Nil(t, err)
+
+	_, err = userClient.DeleteUser(context.Background(), &users.DeleteUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		UserId:      user.Id,
+		Version:     user.Version,
+	})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	_, err = userClient.DeleteUser(context.Background(), &users.DeleteUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		UserId:      user.Id,
+		Version:     restored.Version,
+	})
+	assert.Nil(t, err)
+
+	// Past the retention period, the user can't be restored and is purged.
+	_, err = db.Exec(`UPDATE users SET deleted_at = deleted_at - $2::interval WHERE id = $1`, user.Id, "25 hours")
+	assert.Nil(t, err)
+
+	_, err = userClient.RestoreUser(context.Background(), &users.RestoreUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		UserId:      user.Id,
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	purged, err := userDeletionService.PurgeDeleted(context.Background())
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, purged, int64(1))
+
+	var remaining int
+	err = db.QueryRow(`SELECT count(*) FROM users WHERE id = $1`, user.Id).Scan(&remaining)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, remaining)
+
+	// The username is free again.
+	_, err = userClient.AddUser(context.Background(), newUserReq)
+	assert.Nil(t, err)
+}
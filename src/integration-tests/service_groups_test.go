@@ -0,0 +1,90 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Grpc_Groups(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	user, err := userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "grouped-user",
+		Password:    "password",
+		Role:        "user",
+	})
+	assert.Nil(t, err)
+
+	userLogin, err := userClient.Login(context.Background(), &users.LoginRequest{Username: "grouped-user", Password: "password"})
+	assert.Nil(t, err)
+
+	_, err = userClient.GetGroups(context.Background(), &users.GetGroupsRequest{AccessToken: userLogin.AccessToken})
+	assert.Equal(t, status.Error(codes.Unauthenticated, "incorrect permissions"), err)
+
+	group, err := userClient.SaveGroup(context.Background(), &users.SaveGroupRequest{
+		AccessToken: adminLogin.AccessToken,
+		Name:        "Operators",
+		Description: "Can manage the users",
+	})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, group.Id)
+
+	_, err = userClient.SaveGroup(context.Background(), &users.SaveGroupRequest{AccessToken: adminLogin.AccessToken, Name: "operators"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	group, err = userClient.AssignGroupRole(context.Background(), &users.GroupRoleRequest{
+		AccessToken: adminLogin.AccessToken,
+		GroupId:     group.Id,
+		Role:        "admin",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"admin"}, group.Roles)
+
+	_, err = userClient.AddGroupMember(context.Background(), &users.GroupMemberRequest{
+		AccessToken: adminLogin.AccessToken,
+		GroupId:     group.Id,
+		UserId:      user.Id,
+	})
+	assert.Nil(t, err)
+
+	members, err := userClient.GetGroupMembers(context.Background(), &users.GetGroupMembersRequest{AccessToken: adminLogin.AccessToken, GroupId: group.Id})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{user.Id}, members.UserIds)
+
+	// The membership applies on the next refresh.
+	refreshed, err := userClient.Refresh(context.Background(), &users.RefreshRequest{RefreshToken: userLogin.RefreshToken})
+	assert.Nil(t, err)
+
+	groups, err := userClient.GetGroups(context.Background(), &users.GetGroupsRequest{AccessToken: refreshed.AccessToken})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, groups.Groups)
+
+	_, err = userClient.RemoveGroupMember(context.Background(), &users.GroupMemberRequest{
+		AccessToken: adminLogin.AccessToken,
+		GroupId:     group.Id,
+		UserId:      user.Id,
+	})
+	assert.Nil(t, err)
+
+	refreshed, err = userClient.Refresh(context.Background(), &users.RefreshRequest{RefreshToken: refreshed.RefreshToken})
+	assert.Nil(t, err)
+
+	_, err = userClient.GetGroups(context.Background(), &users.GetGroupsRequest{AccessToken: refreshed.AccessToken})
+	assert.Equal(t, status.Error(codes.Unauthenticated, "incorrect permissions"), err)
+
+	_, err = userClient.DeleteGroup(context.Background(), &users.DeleteGroupRequest{AccessToken: adminLogin.AccessToken, Id: group.Id})
+	assert.Nil(t, err)
+
+	_, err = userClient.GetGroup(context.Background(), &users.GetGroupRequest{AccessToken: adminLogin.AccessToken, Id: group.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
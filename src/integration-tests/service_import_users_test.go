@@ -0,0 +1,163 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Uploads a file to the import stream, in chunks of a few bytes.
+func importUsers(accessToken string, format string, dryRun bool, data string) (*users.ImportUsersResponse, error) {
+	stream, err := userClient.ImportUsers(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	const chunkSize = 16
+	first := true
+	for len(data) > 0 || first {
+		size := chunkSize
+		if len(data) < size {
+			size = len(data)
+		}
+		request := &users.ImportUsersRequest{Data: []byte(data[:size])}
+		if first {
+			request.AccessToken = accessToken
+			request.Format = format
+			request.DryRun = dryRun
+			first = false
+		}
+		if err := stream.Send(request); err != nil {
+			break
+		}
+		data = data[size:]
+	}
+	return stream.CloseAndRecv()
+}
+
+func Test_Grpc_Import_Users(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	csv := "username,email,password,role\n" +
+		"imported-1,imported-1@example.com,imported-password,user\n" +
+		"imported-2,imported-2@example.com,short,user\n" +
+		"imported-3,,imported-password,\n" +
+		"imported-1,imported-4@example.com,imported-password,user\n" +
+		databaseSettings.DefaultUserUsername + ",,imported-password,admin\n" +
+		"imported-5,,imported-password,unknown\n"
+
+	_, err = importUsers("", "csv", false, csv)
+	assert.Equal(t, status.Error(codes.Unauthenticated, "invalid token"), err)
+
+	_, err = importUsers(adminLogin.AccessToken, "xml", false, csv)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	dryRun, err := importUsers(adminLogin.AccessToken, "csv", true, csv)
+	assert.Nil(t, err)
+	assert.True(t, dryRun.DryRun)
+	assert.Equal(t, int32(2), dryRun.Created)
+	assert.Equal(t, int32(1), dryRun.Skipped)
+	assert.Equal(t, int32(3), dryRun.Failed)
+	assert.Len(t, dryRun.Rows, 6)
+	assert.Empty(t, dryRun.Rows[0].UserId)
+	assert.Equal(t, "username already exists", dryRun.Rows[4].Reason)
+
+	_, err = userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "imported-1",
+		Password: "imported-password",
+	})
+	assert.NotNil(t, err)
+
+	report, err := importUsers(adminLogin.AccessToken, "csv", false, csv)
+	assert.Nil(t, err)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, int32(2), report.Created)
+	assert.Equal(t, int32(1), report.Skipped)
+	assert.Equal(t, int32(3), report.Failed)
+	assert.Equal(t, "created", report.Rows[0].Status)
+	assert.NotEmpty(t, report.Rows[0].UserId)
+	assert.Equal(t, "failed", report.Rows[1].Status)
+	assert.Equal(t, "skipped", report.Rows[3].Status)
+	assert.Equal(t, "failed", report.Rows[4].Status)
+	assert.Equal(t, "failed", report.Rows[5].Status)
+
+	login, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "imported-1",
+		Password: "imported-password",
+	})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, login.AccessToken)
+
+	jsonl := `{"username":"IMPORTED-3","password":"imported-password"}` + "\n" +
+		`{"username":"imported-6","password":"imported-password","email":"imported-6@example.com"}` + "\n" +
+		`{"username":"imported-7","password":"imported-password","email":"Imported-1@example.com"}` + "\n"
+
+	// Collisions with existing users are reported on dry runs too.
+	dryRun, err = importUsers(adminLogin.AccessToken, "jsonl", true, jsonl)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), dryRun.Created)
+	assert.Equal(t, int32(2), dryRun.Failed)
+	assert.Equal(t, "username already exists", dryRun.Rows[0].Reason)
+	assert.Equal(t, "email already exists", dryRun.Rows[2].Reason)
+
+	report, err = importUsers(adminLogin.AccessToken, "jsonl", false, jsonl)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), report.Created)
+	assert.Equal(t, int32(2), report.Failed)
+	assert.Equal(t, "imported-6", report.Rows[1].Username)
+}
+
+func Test_Grpc_Import_Users_Legacy_Password_Hashes(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	jsonl := `{"username":"legacy-user","passwordHash":"pbkdf2_sha256$1000$saltsalt$qQDPSZa3Ormyy9oK1Pu0ZLLwP2Svzmx3yu8OvDdq/J0="}` + "\n" +
+		`{"username":"legacy-sha1","passwordHash":"sha1$saltsalt$599c8acd3f623920c9f30c903535e864275a2e35"}` + "\n"
+	report, err := importUsers(adminLogin.AccessToken, "jsonl", false, jsonl)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), report.Created)
+	assert.Equal(t, int32(1), report.Failed)
+
+	_, err = userClient.GetLegacyPasswordReport(context.Background(), &users.GetLegacyPasswordReportRequest{})
+	assert.Equal(t, status.Error(codes.Unauthenticated, "invalid token"), err)
+
+	legacy, err := userClient.GetLegacyPasswordReport(context.Background(), &users.GetLegacyPasswordReportRequest{AccessToken: adminLogin.AccessToken})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), legacy.Schemes["pbkdf2_sha256"])
+
+	_, err = userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "legacy-user",
+		Password: "wrong horse",
+	})
+	assert.NotNil(t, err)
+
+	login, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "legacy-user",
+		Password: "correct horse",
+	})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, login.AccessToken)
+
+	upgraded, err := userClient.GetLegacyPasswordReport(context.Background(), &users.GetLegacyPasswordReportRequest{AccessToken: adminLogin.AccessToken})
+	assert.Nil(t, err)
+	assert.Equal(t, legacy.Total-1, upgraded.Total)
+	assert.Equal(t, int32(0), upgraded.Schemes["pbkdf2_sha256"])
+
+	// The rehashed password keeps working.
+	_, err = userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "legacy-user",
+		Password: "correct horse",
+	})
+	assert.Nil(t, err)
+}
@@ -0,0 +1,105 @@
+package integration_tests
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var invitationLinkRegexp = regexp.MustCompile(`http://localhost/accept-invitation\?\S+`)
+
+// Gets the token of the last invitation link sent to an address.
+func lastInvitationToken(t *testing.T, to string) string {
+	email, ok := testMailer.LastTo(to)
+	assert.True(t, ok)
+
+	link, err := url.Parse(invitationLinkRegexp.FindString(email.Body))
+	assert.Nil(t, err)
+	return link.Query().Get("token")
+}
+
+func Test_Grpc_Invitations(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	invitation, err := userClient.InviteUser(context.Background(), &users.InviteUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "invited-user",
+		Role:        "user",
+		Email:       "invited@example.com",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "pending", invitation.User.Status)
+	firstToken := lastInvitationToken(t, "invited@example.com")
+
+	// Invited users can't log in until they accept the invitation.
+	_, err = userClient.Login(context.Background(), &users.LoginRequest{Username: "invited-user", Password: ""})
+	assert.NotNil(t, err)
+
+	pending, err := userClient.GetInvitations(context.Background(), &users.GetInvitationsRequest{AccessToken: adminLogin.AccessToken})
+	assert.Nil(t, err)
+	assert.Len(t, pending.Invitations, 1)
+	assert.Equal(t, invitation.User.Id, pending.Invitations[0].User.Id)
+
+	// Resending the invitation makes the previous link stop working.
+	_, err = userClient.ResendInvitation(context.Background(), &users.InvitationRequest{
+		AccessToken: adminLogin.AccessToken,
+		UserId:      invitation.User.Id,
+	})
+	assert.Nil(t, err)
+	_, err = userClient.AcceptInvitation(context.Background(), &users.AcceptInvitationRequest{Token: firstToken, Password: "password"})
+	assert.Equal(t, status.Error(codes.InvalidArgument, "invalid token"), err)
+
+	accepted, err := userClient.AcceptInvitation(context.Background(), &users.AcceptInvitationRequest{
+		Token:     lastInvitationToken(t, "invited@example.com"),
+		Password:  "password",
+		FirstName: "Invited",
+		LastName:  "User",
+	})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, accepted.AccessToken)
+	assert.Equal(t, "active", accepted.User.Status)
+	assert.Equal(t, "Invited", accepted.User.FirstName)
+	assert.True(t, accepted.User.EmailVerified)
+
+	_, err = userClient.Login(context.Background(), &users.LoginRequest{Username: "invited-user", Password: "password"})
+	assert.Nil(t, err)
+
+	// Accepted invitations are gone.
+	pending, err = userClient.GetInvitations(context.Background(), &users.GetInvitationsRequest{AccessToken: adminLogin.AccessToken})
+	assert.Nil(t, err)
+	assert.Len(t, pending.Invitations, 0)
+	_, err = userClient.RevokeInvitation(context.Background(), &users.InvitationRequest{
+		AccessToken: adminLogin.AccessToken,
+		UserId:      invitation.User.Id,
+	})
+	assert.Equal(t, status.Error(codes.FailedPrecondition, "user is not invited"), err)
+
+	// Revoking an invitation deletes the invited user.
+	revoked, err := userClient.InviteUser(context.Background(), &users.InviteUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "revoked-user",
+		Role:        "user",
+		Email:       "revoked@example.com",
+	})
+	assert.Nil(t, err)
+	_, err = userClient.RevokeInvitation(context.Background(), &users.InvitationRequest{
+		AccessToken: adminLogin.AccessToken,
+		UserId:      revoked.User.Id,
+	})
+	assert.Nil(t, err)
+	_, err = userClient.AcceptInvitation(context.Background(), &users.AcceptInvitationRequest{
+		Token:    lastInvitationToken(t, "revoked@example.com"),
+		Password: "password",
+	})
+	assert.Equal(t, status.Error(codes.InvalidArgument, "invalid token"), err)
+}
@@ -0,0 +1,176 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Grpc_LDAPLogin(t *testing.T) {
+	dn := "uid=directory-user,ou=people,dc=example,dc=com"
+	testDirectory.AddEntry(dn, map[string][]string{
+		"uid":          {"directory-user"},
+		"mail":         {"directory-user@example.com"},
+		"givenName":    {"Directory"},
+		"sn":           {"User"},
+		"memberOf":     {"cn=admins,ou=groups,dc=example,dc=com"},
+		"userPassword": {"directory password"},
+	})
+
+	// The first login provisions the user, with the role of its groups.
+	login, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "directory-user",
+		Password: "directory password",
+	})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, login.AccessToken)
+	assert.Equal(t, "directory-user", login.User.Username)
+	assert.Equal(t, "directory-user@example.com", login.User.Email)
+	assert.True(t, login.User.EmailVerified)
+	assert.Equal(t, "Directory", login.User.FirstName)
+	assert.Equal(t, "admin", login.User.Role.RoleSlug)
+
+	_, err = userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "directory-user",
+		Password: "wrong password",
+	})
+	assert.Equal(t, status.Error(codes.NotFound, "resource found"), err)
+
+	// Later logins bring the changes of the directory over.
+	testDirectory.AddEntry(dn, map[string][]string{
+		"uid":          {"directory-user"},
+		"mail":         {"renamed@example.com"},
+		"givenName":    {"Directory"},
+		"sn":           {"User"},
+		"userPassword": {"new directory password"},
+	})
+	login, err = userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "directory-user",
+		Password: "new directory password",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "renamed@example.com", login.User.Email)
+	assert.Equal(t, "user", login.User.Role.RoleSlug)
+
+	// Users that left the directory can't log in anymore.
+	testDirectory.DeleteEntry(dn)
+	_, err = userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "directory-user",
+		Password: "new directory password",
+	})
+	assert.Equal(t, status.Error(codes.NotFound, "resource found"), err)
+}
+
+func Test_Grpc_LDAPLoginDoesntTakeOverLocalUsers(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	_, err = userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "local-and-directory",
+		Password:    "local password",
+		Role:        "user",
+	})
+	assert.Nil(t, err)
+
+	testDirectory.AddEntry("uid=local-and-directory,ou=people,dc=example,dc=com", map[string][]string{
+		"uid":          {"local-and-directory"},
+		"memberOf":     {"cn=admins,ou=groups,dc=example,dc=com"},
+		"userPassword": {"directory password"},
+	})
+
+	// Local passwords are checked first, and rejected ones aren't tried on the directory.
+	_, err = userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "local-and-directory",
+		Password: "directory password",
+	})
+	assert.Equal(t, status.Error(codes.NotFound, "resource found"), err)
+
+	login, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "local-and-directory",
+		Password: "local password",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "user", login.User.Role.RoleSlug)
+}
+
+func Test_Grpc_LDAPLoginDoesntTakeOverInvitedUsers(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	_, err = userClient.InviteUser(context.Background(), &users.InviteUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "invited-and-directory",
+		Role:        "user",
+		Email:       "invited-and-directory@example.com",
+	})
+	assert.Nil(t, err)
+
+	testDirectory.AddEntry("uid=invited-and-directory,ou=people,dc=example,dc=com", map[string][]string{
+		"uid":          {"invited-and-directory"},
+		"memberOf":     {"cn=admins,ou=groups,dc=example,dc=com"},
+		"userPassword": {"directory password"},
+	})
+
+	// Users without a password that the directory didn't provision aren't synced with it.
+	_, err = userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "invited-and-directory",
+		Password: "directory password",
+	})
+	assert.Equal(t, status.Error(codes.NotFound, "resource found"), err)
+
+	accepted, err := userClient.AcceptInvitation(context.Background(), &users.AcceptInvitationRequest{
+		Token:    lastInvitationToken(t, "invited-and-directory@example.com"),
+		Password: "invitee password",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "user", accepted.User.Role.RoleSlug)
+}
+
+func Test_Grpc_LDAPLoginOnlyInDefaultOrganization(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	organization, err := userClient.CreateOrganization(context.Background(), &users.CreateOrganizationRequest{
+		AccessToken:   adminLogin.AccessToken,
+		Slug:          "no-directory",
+		Name:          "No directory",
+		AdminUsername: "no-directory-admin",
+		AdminPassword: "no-directory-password",
+	})
+	assert.Nil(t, err)
+
+	testDirectory.AddEntry("uid=other-tenant,ou=people,dc=example,dc=com", map[string][]string{
+		"uid":          {"other-tenant"},
+		"memberOf":     {"cn=admins,ou=groups,dc=example,dc=com"},
+		"userPassword": {"directory password"},
+	})
+
+	// The directory isn't provisioned into other organizations, whose local users still log in.
+	organizationContext := metadata.AppendToOutgoingContext(context.Background(), "x-organization-id", organization.Id)
+	_, err = userClient.Login(organizationContext, &users.LoginRequest{
+		Username: "other-tenant",
+		Password: "directory password",
+	})
+	assert.Equal(t, status.Error(codes.NotFound, "resource found"), err)
+
+	_, err = userClient.Login(organizationContext, &users.LoginRequest{
+		Username: "no-directory-admin",
+		Password: "no-directory-password",
+	})
+	assert.Nil(t, err)
+}
@@ -0,0 +1,86 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Grpc_LoginHistory(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	user, err := userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "history-user",
+		Password:    "password",
+		Role:        "user",
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, user.LastLoginAt)
+
+	clientContext := metadata.AppendToOutgoingContext(context.Background(), "x-forwarded-for", "203.0.113.7", "x-forwarded-user-agent", "Mozilla/5.0")
+
+	_, err = userClient.Login(clientContext, &users.LoginRequest{Username: "history-user", Password: "wrong password"})
+	assert.NotNil(t, err)
+
+	firstLogin, err := userClient.Login(clientContext, &users.LoginRequest{Username: "history-user", Password: "password"})
+	assert.Nil(t, err)
+
+	refreshed, err := userClient.Refresh(clientContext, &users.RefreshRequest{RefreshToken: firstLogin.RefreshToken})
+	assert.Nil(t, err)
+
+	_, err = userClient.Logout(clientContext, &users.RefreshRequest{RefreshToken: refreshed.RefreshToken})
+	assert.Nil(t, err)
+
+	// The user sent on login is the one from before it.
+	secondLogin, err := userClient.Login(clientContext, &users.LoginRequest{Username: "history-user", Password: "password"})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, secondLogin.User.LastLoginAt)
+
+	history, err := userClient.GetLoginHistory(context.Background(), &users.GetLoginHistoryRequest{AccessToken: secondLogin.AccessToken})
+	assert.Nil(t, err)
+	assert.Equal(t, user.Id, history.UserId)
+
+	types := make([]string, 0)
+	for _, event := range history.Events {
+		types = append(types, event.Type)
+		assert.Equal(t, "203.0.113.7", event.Ip)
+		assert.Equal(t, "Mozilla/5.0", event.UserAgent)
+	}
+	assert.Equal(t, []string{"login", "logout", "refresh", "login", "login"}, types)
+	assert.False(t, history.Events[4].Success)
+	assert.Equal(t, "invalid credentials", history.Events[4].Reason)
+
+	page, err := userClient.GetLoginHistory(context.Background(), &users.GetLoginHistoryRequest{
+		AccessToken: secondLogin.AccessToken,
+		Limit:       2,
+		Offset:      1,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, page.Events, 2)
+	assert.Equal(t, history.Events[1].Id, page.Events[0].Id)
+
+	// Only administrators see the history of others.
+	_, err = userClient.GetLoginHistory(context.Background(), &users.GetLoginHistoryRequest{
+		AccessToken: secondLogin.AccessToken,
+		UserId:      adminLogin.User.Id,
+	})
+	assert.Equal(t, status.Error(codes.Unauthenticated, "incorrect permissions"), err)
+
+	adminView, err := userClient.GetLoginHistory(context.Background(), &users.GetLoginHistoryRequest{
+		AccessToken: adminLogin.AccessToken,
+		UserId:      user.Id,
+		From:        history.Events[0].CreatedAt,
+	})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, adminView.Events)
+}
@@ -2,6 +2,7 @@ package integration_tests
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/golang-jwt/jwt"
@@ -54,6 +55,24 @@ func Test_Grpc_Login(t *testing.T) {
 			wantedErrCode:    codes.NotFound,
 			wantedErrMessage: "resource found",
 		},
+		{
+			name: "success with another casing",
+			req: &users.LoginRequest{
+				Username: strings.ToUpper(databaseSettings.DefaultUserUsername),
+				Password: databaseSettings.DefaultUserPassword,
+			},
+			wantedRes: &users.TokenResponse{
+				User: &users.UserResponse{
+					Username: databaseSettings.DefaultUserUsername,
+					Role: &users.UserResponse_RoleResponse{
+						RoleLabel: "Administrator",
+						RoleSlug:  "admin",
+					},
+				},
+			},
+			wantedErrCode:    0,
+			wantedErrMessage: "",
+		},
 		{
 			name: "success",
 			req: &users.LoginRequest{
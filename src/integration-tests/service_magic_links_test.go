@@ -0,0 +1,87 @@
+package integration_tests
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var magicLinkRegexp = regexp.MustCompile(`http://localhost/magic-link\?\S+`)
+
+// Gets the token of the last login link sent to an address.
+func lastMagicLinkToken(t *testing.T, to string) string {
+	email, ok := testMailer.LastTo(to)
+	assert.True(t, ok)
+
+	link, err := url.Parse(magicLinkRegexp.FindString(email.Body))
+	assert.Nil(t, err)
+	return link.Query().Get("token")
+}
+
+// Requests a magic link and waits for it to be sent in the background.
+func requestMagicLinkToken(t *testing.T, request *users.RequestMagicLinkRequest, to string) string {
+	sent := len(testMailer.Emails())
+	_, err := userClient.RequestMagicLink(context.Background(), request)
+	assert.Nil(t, err)
+
+	assert.Eventually(t, func() bool { return len(testMailer.Emails()) > sent }, 5*time.Second, 10*time.Millisecond)
+	return lastMagicLinkToken(t, to)
+}
+
+func Test_Grpc_MagicLinks(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	_, err = userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "magic-user",
+		Password:    "forgotten password",
+		Role:        "user",
+		Email:       "magic-user@example.com",
+	})
+	assert.Nil(t, err)
+
+	// Unknown users can't be told apart.
+	_, err = userClient.RequestMagicLink(context.Background(), &users.RequestMagicLinkRequest{Username: "nobody"})
+	assert.Nil(t, err)
+
+	// Links bound to a device can't be used from another one, and are used up.
+	token := requestMagicLinkToken(t, &users.RequestMagicLinkRequest{Username: "magic-user", Nonce: "device-nonce"}, "magic-user@example.com")
+	assert.NotEmpty(t, token)
+
+	_, err = userClient.RedeemMagicLink(context.Background(), &users.RedeemMagicLinkRequest{Token: token, Nonce: "other-nonce"})
+	assert.Equal(t, status.Error(codes.InvalidArgument, "invalid token"), err)
+	_, err = userClient.RedeemMagicLink(context.Background(), &users.RedeemMagicLinkRequest{Token: token, Nonce: "device-nonce"})
+	assert.Equal(t, status.Error(codes.InvalidArgument, "invalid token"), err)
+
+	token = requestMagicLinkToken(t, &users.RequestMagicLinkRequest{Username: "magic-user", Nonce: "device-nonce"}, "magic-user@example.com")
+
+	login, err := userClient.RedeemMagicLink(context.Background(), &users.RedeemMagicLinkRequest{Token: token, Nonce: "device-nonce"})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, login.AccessToken)
+	assert.NotEmpty(t, login.RefreshToken)
+	assert.Equal(t, "magic-user", login.User.Username)
+
+	_, err = userClient.GetLoginHistory(context.Background(), &users.GetLoginHistoryRequest{AccessToken: login.AccessToken})
+	assert.Nil(t, err)
+
+	// Links are single use.
+	_, err = userClient.RedeemMagicLink(context.Background(), &users.RedeemMagicLinkRequest{Token: token, Nonce: "device-nonce"})
+	assert.Equal(t, status.Error(codes.InvalidArgument, "invalid token"), err)
+
+	// Links without a nonce work anywhere.
+	token = requestMagicLinkToken(t, &users.RequestMagicLinkRequest{Username: "magic-user"}, "magic-user@example.com")
+	login, err = userClient.RedeemMagicLink(context.Background(), &users.RedeemMagicLinkRequest{Token: token})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, login.AccessToken)
+}
@@ -0,0 +1,149 @@
+package integration_tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Grpc_MFA_Enrollment(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	_, err = userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "mfa-user",
+		Password:    "password",
+		Role:        "user",
+	})
+	assert.Nil(t, err)
+
+	login, err := userClient.Login(context.Background(), &users.LoginRequest{Username: "mfa-user", Password: "password"})
+	assert.Nil(t, err)
+	assert.False(t, login.MfaRequired)
+
+	enrollment, err := userClient.BeginTOTPEnrollment(context.Background(), &users.BeginTOTPEnrollmentRequest{
+		AccessToken: login.AccessToken,
+	})
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(enrollment.OtpauthUri, "otpauth://totp/"))
+	assert.Contains(t, enrollment.OtpauthUri, "secret="+enrollment.Secret)
+
+	// The secret is only stored encrypted.
+	var storedSecret string
+	err = db.QueryRow(`SELECT encrypted_secret FROM totp_secrets JOIN users ON users.id = totp_secrets.user_id WHERE users.username = 'mfa-user'`).Scan(&storedSecret)
+	assert.Nil(t, err)
+	assert.NotContains(t, storedSecret, enrollment.Secret)
+
+	_, err = userClient.ConfirmTOTPEnrollment(context.Background(), &users.ConfirmTOTPEnrollmentRequest{
+		AccessToken: login.AccessToken,
+		Code:        "000000",
+	})
+	assert.Equal(t, status.Error(codes.InvalidArgument, "invalid code"), err)
+
+	code, err := totp.GenerateCode(enrollment.Secret, time.Now())
+	assert.Nil(t, err)
+	confirmed, err := userClient.ConfirmTOTPEnrollment(context.Background(), &users.ConfirmTOTPEnrollmentRequest{
+		AccessToken: login.AccessToken,
+		Code:        code,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, confirmed.RecoveryCodes, 3)
+	assert.Nil(t, confirmed.Tokens)
+
+	_, err = userClient.BeginTOTPEnrollment(context.Background(), &users.BeginTOTPEnrollmentRequest{
+		AccessToken: login.AccessToken,
+	})
+	assert.Equal(t, status.Error(codes.FailedPrecondition, "mfa already enabled"), err)
+
+	// The password alone isn't enough anymore.
+	login, err = userClient.Login(context.Background(), &users.LoginRequest{Username: "mfa-user", Password: "password"})
+	assert.Nil(t, err)
+	assert.True(t, login.MfaRequired)
+	assert.False(t, login.MfaEnrollmentRequired)
+	assert.NotEmpty(t, login.MfaToken)
+	assert.Empty(t, login.AccessToken)
+	assert.Empty(t, login.RefreshToken)
+
+	// The MFA token can't be used as an access token.
+	_, err = userClient.SendVerificationEmail(context.Background(), &users.SendVerificationEmailRequest{
+		AccessToken: login.MfaToken,
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Codes can't be replayed.
+	_, err = userClient.VerifyMFA(context.Background(), &users.VerifyMFARequest{MfaToken: login.MfaToken, Code: code})
+	assert.Equal(t, status.Error(codes.InvalidArgument, "invalid code"), err)
+
+	tokens, err := userClient.VerifyMFA(context.Background(), &users.VerifyMFARequest{
+		MfaToken:     login.MfaToken,
+		RecoveryCode: confirmed.RecoveryCodes[0],
+	})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	assert.Equal(t, "mfa-user", tokens.User.Username)
+
+	// Recovery codes only work once.
+	_, err = userClient.VerifyMFA(context.Background(), &users.VerifyMFARequest{
+		MfaToken:     login.MfaToken,
+		RecoveryCode: confirmed.RecoveryCodes[0],
+	})
+	assert.Equal(t, status.Error(codes.InvalidArgument, "invalid code"), err)
+
+	nextCode, err := totp.GenerateCode(enrollment.Secret, time.Now().Add(30*time.Second))
+	assert.Nil(t, err)
+	tokens, err = userClient.VerifyMFA(context.Background(), &users.VerifyMFARequest{MfaToken: login.MfaToken, Code: nextCode})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+}
+
+func Test_Grpc_MFA_RequiredByRole(t *testing.T) {
+	_, err := db.Exec(`UPDATE roles SET requires_mfa = true WHERE role_slug = 'user'`)
+	assert.Nil(t, err)
+	defer db.Exec(`UPDATE roles SET requires_mfa = false WHERE role_slug = 'user'`)
+
+	registered, err := userClient.Register(context.Background(), &users.RegisterRequest{
+		Username: "mfa-required-user",
+		Password: "password",
+	})
+	assert.Nil(t, err)
+	assert.True(t, registered.MfaRequired)
+	assert.True(t, registered.MfaEnrollmentRequired)
+	assert.Empty(t, registered.AccessToken)
+
+	login, err := userClient.Login(context.Background(), &users.LoginRequest{Username: "mfa-required-user", Password: "password"})
+	assert.Nil(t, err)
+	assert.True(t, login.MfaRequired)
+	assert.True(t, login.MfaEnrollmentRequired)
+
+	_, err = userClient.VerifyMFA(context.Background(), &users.VerifyMFARequest{MfaToken: login.MfaToken, Code: "123456"})
+	assert.Equal(t, status.Error(codes.FailedPrecondition, "mfa enrollment required"), err)
+
+	enrollment, err := userClient.BeginTOTPEnrollment(context.Background(), &users.BeginTOTPEnrollmentRequest{
+		MfaToken: login.MfaToken,
+	})
+	assert.Nil(t, err)
+
+	code, err := totp.GenerateCode(enrollment.Secret, time.Now())
+	assert.Nil(t, err)
+	confirmed, err := userClient.ConfirmTOTPEnrollment(context.Background(), &users.ConfirmTOTPEnrollmentRequest{
+		MfaToken: login.MfaToken,
+		Code:     code,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, confirmed.RecoveryCodes, 3)
+	assert.NotEmpty(t, confirmed.Tokens.AccessToken)
+	assert.NotEmpty(t, confirmed.Tokens.RefreshToken)
+	assert.Equal(t, "user", confirmed.Tokens.User.Role.RoleSlug)
+}
@@ -0,0 +1,90 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/identity-providers/mockidp"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Grpc_OIDCLogin(t *testing.T) {
+	idp, err := mockidp.New("user-microservice", "client secret")
+	assert.Nil(t, err)
+	defer idp.Close()
+
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	provider, err := userClient.SaveIdentityProvider(context.Background(), &users.SaveIdentityProviderRequest{
+		AccessToken:   adminLogin.AccessToken,
+		Slug:          "mock-idp",
+		Name:          "Mock IdP",
+		Issuer:        idp.Issuer(),
+		ClientId:      idp.ClientID,
+		ClientSecret:  idp.ClientSecret,
+		RedirectUrl:   "http://localhost/oidc/callback",
+		Scopes:        []string{"email", "profile"},
+		RoleClaim:     "groups",
+		RoleMapping:   map[string]string{"admins": "admin"},
+		DefaultRole:   "user",
+		AutoProvision: true,
+		Enabled:       true,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "mock-idp", provider.Slug)
+
+	_, err = userClient.BeginOIDCLogin(context.Background(), &users.BeginOIDCLoginRequest{Provider: "unknown"})
+	assert.Equal(t, status.Error(codes.NotFound, "identity provider not found"), err)
+
+	claims := map[string]interface{}{
+		"sub":                "upstream-subject",
+		"email":              "federated@example.com",
+		"email_verified":     true,
+		"preferred_username": "federated-user",
+		"groups":             []string{"admins"},
+	}
+	login := func() (*users.TokenResponse, string, error) {
+		authorization, err := userClient.BeginOIDCLogin(context.Background(), &users.BeginOIDCLoginRequest{Provider: "mock-idp"})
+		assert.Nil(t, err)
+		code, err := idp.Authorize(authorization.AuthorizationUrl, claims)
+		assert.Nil(t, err)
+
+		res, err := userClient.FinishOIDCLogin(context.Background(), &users.FinishOIDCLoginRequest{State: authorization.State, Code: code})
+		return res, authorization.State, err
+	}
+
+	// The first login provisions the user, with the mapped role.
+	res, state, err := login()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, res.AccessToken)
+	assert.Equal(t, "federated-user", res.User.Username)
+	assert.Equal(t, "admin", res.User.Role.RoleSlug)
+
+	// Later logins use the linked user.
+	claims["preferred_username"] = "renamed-upstream"
+	second, _, err := login()
+	assert.Nil(t, err)
+	assert.Equal(t, res.User.Id, second.User.Id)
+
+	// The link to the upstream account is part of the data export of the user.
+	stream, err := userClient.ExportMyData(context.Background(), &users.ExportMyDataRequest{AccessToken: second.AccessToken})
+	assert.Nil(t, err)
+	export, _, err := receiveDataExport(t, stream)
+	assert.Nil(t, err)
+	assert.Len(t, export.Identities, 1)
+	assert.Equal(t, "upstream-subject", export.Identities[0].Subject)
+	assert.Equal(t, provider.Id, export.Identities[0].ProviderID.String())
+
+	// States are single use.
+	code, err := idp.Authorize("http://localhost/?response_type=code&client_id=user-microservice&code_challenge_method=S256&code_challenge=x", claims)
+	assert.Nil(t, err)
+	_, err = userClient.FinishOIDCLogin(context.Background(), &users.FinishOIDCLoginRequest{State: state, Code: code})
+	assert.Equal(t, status.Error(codes.Unauthenticated, "invalid login"), err)
+}
@@ -0,0 +1,147 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/passkeys/softauthenticator"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+// The tokens sent to the users of an organization carry it, so they are used
+// without the organization metadata, like from a link in an email.
+func Test_Grpc_Organization_Tokens(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	organization, err := userClient.CreateOrganization(context.Background(), &users.CreateOrganizationRequest{
+		AccessToken:   adminLogin.AccessToken,
+		Slug:          "tokens",
+		Name:          "Tokens",
+		AdminUsername: "tokens-admin",
+		AdminPassword: "tokens-password",
+	})
+	assert.Nil(t, err)
+
+	organizationContext := metadata.AppendToOutgoingContext(context.Background(), "x-organization-id", organization.Id)
+	organizationAdminLogin, err := userClient.Login(organizationContext, &users.LoginRequest{
+		Username: "tokens-admin",
+		Password: "tokens-password",
+	})
+	assert.Nil(t, err)
+
+	_, err = userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken:        organizationAdminLogin.AccessToken,
+		Username:           "tokens-user",
+		Password:           "given-password",
+		Role:               "user",
+		Email:              "tokens-user@example.com",
+		MustChangePassword: true,
+	})
+	assert.Nil(t, err)
+
+	// Password change required on login.
+	login, err := userClient.Login(organizationContext, &users.LoginRequest{Username: "tokens-user", Password: "given-password"})
+	assert.Nil(t, err)
+	assert.True(t, login.PasswordChangeRequired)
+
+	changed, err := userClient.ChangePassword(context.Background(), &users.ChangePasswordRequest{
+		PasswordChangeToken: login.PasswordChangeToken,
+		NewPassword:         "chosen-password",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "tokens-user", changed.User.Username)
+
+	// Email verification.
+	verified, err := userClient.VerifyEmail(context.Background(), &users.VerifyEmailRequest{
+		Token: lastVerificationToken(t, "tokens-user@example.com"),
+	})
+	assert.Nil(t, err)
+	assert.True(t, verified.EmailVerified)
+
+	// Password reset.
+	resetToken := requestResetToken(t, organizationContext, "tokens-user", "tokens-user@example.com")
+	_, err = userClient.ResetPassword(context.Background(), &users.ResetPasswordRequest{
+		Token:       resetToken,
+		NewPassword: "reset-password",
+	})
+	assert.Nil(t, err)
+
+	login, err = userClient.Login(organizationContext, &users.LoginRequest{Username: "tokens-user", Password: "reset-password"})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, login.AccessToken)
+
+	// Multi-factor login.
+	enrollment, err := userClient.BeginTOTPEnrollment(context.Background(), &users.BeginTOTPEnrollmentRequest{
+		AccessToken: login.AccessToken,
+	})
+	assert.Nil(t, err)
+	code, err := totp.GenerateCode(enrollment.Secret, time.Now())
+	assert.Nil(t, err)
+	_, err = userClient.ConfirmTOTPEnrollment(context.Background(), &users.ConfirmTOTPEnrollmentRequest{
+		AccessToken: login.AccessToken,
+		Code:        code,
+	})
+	assert.Nil(t, err)
+
+	login, err = userClient.Login(organizationContext, &users.LoginRequest{Username: "tokens-user", Password: "reset-password"})
+	assert.Nil(t, err)
+	assert.True(t, login.MfaRequired)
+
+	code, err = totp.GenerateCode(enrollment.Secret, time.Now().Add(30*time.Second))
+	assert.Nil(t, err)
+	mfaLogin, err := userClient.VerifyMFA(context.Background(), &users.VerifyMFARequest{MfaToken: login.MfaToken, Code: code})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, mfaLogin.AccessToken)
+	assert.Equal(t, "tokens-user", mfaLogin.User.Username)
+
+	// Passkey login, begun in the organization.
+	authenticator := softauthenticator.New(passkeyOrigin)
+	registration, err := userClient.BeginPasskeyRegistration(context.Background(), &users.BeginPasskeyRegistrationRequest{
+		AccessToken: mfaLogin.AccessToken,
+	})
+	assert.Nil(t, err)
+	credential, _, err := authenticator.Register([]byte(registration.OptionsJson))
+	assert.Nil(t, err)
+	_, err = userClient.FinishPasskeyRegistration(context.Background(), &users.FinishPasskeyRegistrationRequest{
+		AccessToken:    mfaLogin.AccessToken,
+		SessionId:      registration.SessionId,
+		CredentialJson: string(credential),
+	})
+	assert.Nil(t, err)
+
+	challenge, err := userClient.BeginPasskeyLogin(organizationContext, &users.BeginPasskeyLoginRequest{})
+	assert.Nil(t, err)
+	assertion, err := authenticator.Login([]byte(challenge.OptionsJson))
+	assert.Nil(t, err)
+	passkeyLogin, err := userClient.FinishPasskeyLogin(context.Background(), &users.FinishPasskeyLoginRequest{
+		SessionId:      challenge.SessionId,
+		CredentialJson: string(assertion),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "tokens-user", passkeyLogin.User.Username)
+
+	// Invitation.
+	_, err = userClient.InviteUser(context.Background(), &users.InviteUserRequest{
+		AccessToken: organizationAdminLogin.AccessToken,
+		Username:    "tokens-invitee",
+		Role:        "user",
+		Email:       "tokens-invitee@example.com",
+	})
+	assert.Nil(t, err)
+
+	accepted, err := userClient.AcceptInvitation(context.Background(), &users.AcceptInvitationRequest{
+		Token:    lastInvitationToken(t, "tokens-invitee@example.com"),
+		Password: "invitee-password",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "tokens-invitee", accepted.User.Username)
+	assert.Equal(t, "active", accepted.User.Status)
+}
@@ -0,0 +1,93 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Grpc_Organizations(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	organization, err := userClient.CreateOrganization(context.Background(), &users.CreateOrganizationRequest{
+		AccessToken:   adminLogin.AccessToken,
+		Slug:          "acme",
+		Name:          "Acme",
+		AdminUsername: databaseSettings.DefaultUserUsername,
+		AdminPassword: "acme-password",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "acme", organization.Slug)
+	assert.Equal(t, databaseSettings.DefaultUserUsername, organization.Admin.Username)
+
+	_, err = userClient.CreateOrganization(context.Background(), &users.CreateOrganizationRequest{
+		AccessToken:   adminLogin.AccessToken,
+		Slug:          "acme",
+		Name:          "Acme again",
+		AdminUsername: "someone",
+		AdminPassword: "password",
+	})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	// Usernames are unique per organization, the same one logs in to each with its own password.
+	acmeContext := metadata.AppendToOutgoingContext(context.Background(), "x-organization-id", organization.Id)
+	_, err = userClient.Login(acmeContext, &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.NotNil(t, err)
+
+	acmeLogin, err := userClient.Login(acmeContext, &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: "acme-password",
+	})
+	assert.Nil(t, err)
+
+	// Users can only sign themselves up in the default organization.
+	_, err = userClient.Register(acmeContext, &users.RegisterRequest{
+		Username: "acme-self-registered",
+		Password: "password",
+		Email:    "acme-self-registered@example.com",
+	})
+	assert.Equal(t, status.Error(codes.PermissionDenied, "registration is disabled"), err)
+
+	// Administrators of other organizations can't create organizations nor see users outside their own.
+	_, err = userClient.CreateOrganization(context.Background(), &users.CreateOrganizationRequest{
+		AccessToken:   acmeLogin.AccessToken,
+		Slug:          "acme-subsidiary",
+		Name:          "Acme Subsidiary",
+		AdminUsername: "someone",
+		AdminPassword: "password",
+	})
+	assert.Equal(t, status.Error(codes.Unauthenticated, "incorrect permissions"), err)
+
+	acmeUsers, err := userClient.ListUsers(context.Background(), &users.ListUsersRequest{AccessToken: acmeLogin.AccessToken})
+	assert.Nil(t, err)
+	assert.Len(t, acmeUsers.Users, 1)
+	assert.Equal(t, organization.Admin.Id, acmeUsers.Users[0].Id)
+
+	_, err = userClient.SuspendUser(context.Background(), &users.UpdateUserStatusRequest{
+		AccessToken: acmeLogin.AccessToken,
+		UserId:      adminLogin.User.Id,
+		Version:     adminLogin.User.Version,
+		Reason:      "hostile takeover",
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Refreshing keeps the organization of the token.
+	refreshed, err := userClient.Refresh(context.Background(), &users.RefreshRequest{RefreshToken: acmeLogin.RefreshToken})
+	assert.Nil(t, err)
+
+	acmeUsers, err = userClient.ListUsers(context.Background(), &users.ListUsersRequest{AccessToken: refreshed.AccessToken})
+	assert.Nil(t, err)
+	assert.Len(t, acmeUsers.Users, 1)
+}
@@ -0,0 +1,96 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/passkeys/softauthenticator"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Origin the passkey ceremonies of the tests come from.
+const passkeyOrigin = "http://localhost:3000"
+
+func Test_Grpc_Passkeys(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	_, err = userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "passkey-user",
+		Password:    "password",
+		Role:        "user",
+	})
+	assert.Nil(t, err)
+
+	login, err := userClient.Login(context.Background(), &users.LoginRequest{Username: "passkey-user", Password: "password"})
+	assert.Nil(t, err)
+
+	authenticator := softauthenticator.New(passkeyOrigin)
+	registration, err := userClient.BeginPasskeyRegistration(context.Background(), &users.BeginPasskeyRegistrationRequest{
+		AccessToken: login.AccessToken,
+	})
+	assert.Nil(t, err)
+
+	credential, _, err := authenticator.Register([]byte(registration.OptionsJson))
+	assert.Nil(t, err)
+
+	passkey, err := userClient.FinishPasskeyRegistration(context.Background(), &users.FinishPasskeyRegistrationRequest{
+		AccessToken:    login.AccessToken,
+		SessionId:      registration.SessionId,
+		CredentialJson: string(credential),
+	})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, passkey.Id)
+
+	// Every challenge can only be answered once.
+	_, err = userClient.FinishPasskeyRegistration(context.Background(), &users.FinishPasskeyRegistrationRequest{
+		AccessToken:    login.AccessToken,
+		SessionId:      registration.SessionId,
+		CredentialJson: string(credential),
+	})
+	assert.Equal(t, status.Error(codes.FailedPrecondition, "invalid or expired passkey session"), err)
+
+	for i := 1; i <= 2; i++ {
+		challenge, err := userClient.BeginPasskeyLogin(context.Background(), &users.BeginPasskeyLoginRequest{})
+		assert.Nil(t, err)
+
+		assertion, err := authenticator.Login([]byte(challenge.OptionsJson))
+		assert.Nil(t, err)
+
+		tokens, err := userClient.FinishPasskeyLogin(context.Background(), &users.FinishPasskeyLoginRequest{
+			SessionId:      challenge.SessionId,
+			CredentialJson: string(assertion),
+		})
+		assert.Nil(t, err)
+		assert.NotEmpty(t, tokens.AccessToken)
+		assert.NotEmpty(t, tokens.RefreshToken)
+		assert.Equal(t, "passkey-user", tokens.User.Username)
+
+		var signCount int
+		err = db.QueryRow(`SELECT sign_count FROM passkey_credentials WHERE id = $1`, passkey.Id).Scan(&signCount)
+		assert.Nil(t, err)
+		assert.Equal(t, i, signCount)
+	}
+
+	// A copy of the authenticator that falls behind on the counter is rejected.
+	_, err = db.Exec(`UPDATE passkey_credentials SET sign_count = 100 WHERE id = $1`, passkey.Id)
+	assert.Nil(t, err)
+
+	challenge, err := userClient.BeginPasskeyLogin(context.Background(), &users.BeginPasskeyLoginRequest{})
+	assert.Nil(t, err)
+	assertion, err := authenticator.Login([]byte(challenge.OptionsJson))
+	assert.Nil(t, err)
+
+	_, err = userClient.FinishPasskeyLogin(context.Background(), &users.FinishPasskeyLoginRequest{
+		SessionId:      challenge.SessionId,
+		CredentialJson: string(assertion),
+	})
+	assert.Equal(t, status.Error(codes.Unauthenticated, "passkey verification failed"), err)
+}
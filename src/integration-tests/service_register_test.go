@@ -0,0 +1,49 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Grpc_Register(t *testing.T) {
+	res, err := userClient.Register(context.Background(), &users.RegisterRequest{
+		Username: "self-registered",
+		Password: "password",
+		Email:    "self-registered@example.com",
+	})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, res.AccessToken)
+	assert.NotEmpty(t, res.RefreshToken)
+	assert.Equal(t, "self-registered", res.User.Username)
+	assert.Equal(t, "self-registered@example.com", res.User.Email)
+	assert.False(t, res.User.EmailVerified)
+	assert.Equal(t, "user", res.User.Role.RoleSlug)
+
+	// The verification email is sent right away.
+	assert.NotEmpty(t, lastVerificationToken(t, "self-registered@example.com"))
+
+	// Self-registered users can't manage other users.
+	_, err = userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: res.AccessToken,
+		Username:    "escalated",
+		Password:    "password",
+		Role:        "admin",
+	})
+	assert.Equal(t, status.Error(codes.Unauthenticated, "incorrect permissions"), err)
+
+	_, err = userClient.Register(context.Background(), &users.RegisterRequest{
+		Username: "Self-Registered",
+		Password: "password",
+	})
+	assert.Equal(t, status.Error(codes.AlreadyExists, "username or email already taken"), err)
+
+	_, err = userClient.Register(context.Background(), &users.RegisterRequest{
+		Username: "no-password",
+	})
+	assert.Equal(t, status.Error(codes.InvalidArgument, "invalid request"), err)
+}
@@ -0,0 +1,116 @@
+package integration_tests
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var resetLinkRegexp = regexp.MustCompile(`http://localhost/reset-password\?\S+`)
+
+// Gets the token of the last reset link sent to an address.
+func lastResetToken(t *testing.T, to string) string {
+	email, ok := testMailer.LastTo(to)
+	assert.True(t, ok)
+
+	link, err := url.Parse(resetLinkRegexp.FindString(email.Body))
+	assert.Nil(t, err)
+	return link.Query().Get("token")
+}
+
+// Requests a password reset, waiting for the link sent in the background to an address, and gets its token.
+func requestResetToken(t *testing.T, ctx context.Context, usernameOrEmail string, to string) string {
+	sent := len(testMailer.Emails())
+	_, err := userClient.RequestPasswordReset(ctx, &users.RequestPasswordResetRequest{UsernameOrEmail: usernameOrEmail})
+	assert.Nil(t, err)
+
+	assert.Eventually(t, func() bool { return len(testMailer.Emails()) > sent }, 5*time.Second, 10*time.Millisecond)
+	return lastResetToken(t, to)
+}
+
+func Test_Grpc_ResetPassword(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	_, err = userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "forgetful-user",
+		Password:    "old password",
+		Role:        "admin",
+		Email:       "forgetful-user@example.com",
+	})
+	assert.Nil(t, err)
+
+	userLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "forgetful-user",
+		Password: "old password",
+	})
+	assert.Nil(t, err)
+
+	// Unknown accounts can't be told apart.
+	sent := len(testMailer.Emails())
+	_, err = userClient.RequestPasswordReset(context.Background(), &users.RequestPasswordResetRequest{
+		UsernameOrEmail: "nobody@example.com",
+	})
+	assert.Nil(t, err)
+	assert.Never(t, func() bool { return len(testMailer.Emails()) > sent }, 200*time.Millisecond, 10*time.Millisecond)
+
+	firstToken := requestResetToken(t, context.Background(), "Forgetful-User@example.com", "forgetful-user@example.com")
+	secondToken := requestResetToken(t, context.Background(), "forgetful-user", "forgetful-user@example.com")
+	assert.NotEqual(t, firstToken, secondToken)
+
+	// Rate limited, nothing else is sent.
+	sent = len(testMailer.Emails())
+	_, err = userClient.RequestPasswordReset(context.Background(), &users.RequestPasswordResetRequest{
+		UsernameOrEmail: "forgetful-user",
+	})
+	assert.Nil(t, err)
+	assert.Never(t, func() bool { return len(testMailer.Emails()) > sent }, 200*time.Millisecond, 10*time.Millisecond)
+
+	_, err = userClient.ResetPassword(context.Background(), &users.ResetPasswordRequest{
+		Token:       "not a token",
+		NewPassword: "new password",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = userClient.ResetPassword(context.Background(), &users.ResetPasswordRequest{
+		Token:       secondToken,
+		NewPassword: "new password",
+	})
+	assert.Nil(t, err)
+
+	// The other links stop working once the password is reset.
+	_, err = userClient.ResetPassword(context.Background(), &users.ResetPasswordRequest{
+		Token:       firstToken,
+		NewPassword: "another password",
+	})
+	s, _ := status.FromError(err)
+	assert.Equal(t, codes.InvalidArgument, s.Code())
+	assert.Equal(t, "invalid token", s.Message())
+
+	// The user got signed out.
+	_, err = userClient.Refresh(context.Background(), &users.RefreshRequest{RefreshToken: userLogin.RefreshToken})
+	assert.Error(t, err)
+
+	_, err = userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "forgetful-user",
+		Password: "old password",
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "forgetful-user",
+		Password: "new password",
+	})
+	assert.Nil(t, err)
+}
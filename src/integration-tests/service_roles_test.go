@@ -0,0 +1,106 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Grpc_Roles(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	role, err := userClient.CreateRole(context.Background(), &users.CreateRoleRequest{
+		AccessToken: adminLogin.AccessToken,
+		RoleSlug:    "auditor",
+		RoleLabel:   "Auditor",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "auditor", role.RoleSlug)
+	assert.Equal(t, int64(1), role.Version)
+
+	_, err = userClient.CreateRole(context.Background(), &users.CreateRoleRequest{
+		AccessToken: adminLogin.AccessToken,
+		RoleSlug:    "auditor",
+		RoleLabel:   "Other auditor",
+	})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	role, err = userClient.UpdateRole(context.Background(), &users.UpdateRoleRequest{
+		AccessToken:        adminLogin.AccessToken,
+		Id:                 role.Id,
+		RoleLabel:          "Auditors",
+		PasswordMaxAgeDays: 90,
+		Version:            role.Version,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "Auditors", role.RoleLabel)
+	assert.Equal(t, int32(90), role.PasswordMaxAgeDays)
+
+	_, err = userClient.UpdateRole(context.Background(), &users.UpdateRoleRequest{
+		AccessToken: adminLogin.AccessToken,
+		Id:          role.Id,
+		RoleLabel:   "Stale",
+		Version:     1,
+	})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	list, err := userClient.ListRoles(context.Background(), &users.ListRolesRequest{AccessToken: adminLogin.AccessToken})
+	assert.Nil(t, err)
+	slugs := make([]string, 0, len(list.Roles))
+	for _, listed := range list.Roles {
+		slugs = append(slugs, listed.RoleSlug)
+	}
+	assert.Contains(t, slugs, "admin")
+	assert.Contains(t, slugs, "auditor")
+
+	// Roles held by users can't be deleted.
+	_, err = userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "auditor-user",
+		Password:    "password",
+		Role:        "auditor",
+	})
+	assert.Nil(t, err)
+
+	_, err = userClient.DeleteRole(context.Background(), &users.DeleteRoleRequest{AccessToken: adminLogin.AccessToken, Id: role.Id, Version: role.Version})
+	assert.Equal(t, status.Error(codes.FailedPrecondition, "role is held by users or groups"), err)
+
+	userLogin, err := userClient.Login(context.Background(), &users.LoginRequest{Username: "auditor-user", Password: "password"})
+	assert.Nil(t, err)
+
+	_, err = userClient.ListRoles(context.Background(), &users.ListRolesRequest{AccessToken: userLogin.AccessToken})
+	assert.Equal(t, status.Error(codes.Unauthenticated, "incorrect permissions"), err)
+
+	for _, listed := range list.Roles {
+		if listed.RoleSlug == "user" {
+			_, err = userClient.DeleteRole(context.Background(), &users.DeleteRoleRequest{AccessToken: adminLogin.AccessToken, Id: listed.Id, Version: listed.Version})
+			assert.Equal(t, status.Error(codes.FailedPrecondition, "default roles can't be deleted"), err)
+		}
+	}
+
+	unused, err := userClient.CreateRole(context.Background(), &users.CreateRoleRequest{
+		AccessToken: adminLogin.AccessToken,
+		RoleSlug:    "temporary",
+		RoleLabel:   "Temporary",
+	})
+	assert.Nil(t, err)
+
+	_, err = userClient.DeleteRole(context.Background(), &users.DeleteRoleRequest{AccessToken: adminLogin.AccessToken, Id: unused.Id, Version: unused.Version})
+	assert.Nil(t, err)
+
+	// The slug of a deleted role can be used again.
+	_, err = userClient.CreateRole(context.Background(), &users.CreateRoleRequest{
+		AccessToken: adminLogin.AccessToken,
+		RoleSlug:    "temporary",
+		RoleLabel:   "Temporary",
+	})
+	assert.Nil(t, err)
+}
@@ -0,0 +1,122 @@
+package integration_tests
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Reads a whole users archive from its stream.
+func receiveUsersArchive(stream users.Users_ExportUsersClient) ([]byte, error) {
+	archive := []byte{}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return archive, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		archive = append(archive, chunk.Data...)
+	}
+}
+
+// Uploads an archive to the restore stream, in a single chunk.
+func restoreUsers(accessToken string, remapRoles bool, archive []byte) (*users.RestoreUsersResponse, error) {
+	stream, err := userClient.RestoreUsers(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	err = stream.Send(&users.RestoreUsersRequest{AccessToken: accessToken, RemapRoles: remapRoles, Data: archive})
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return stream.CloseAndRecv()
+}
+
+func Test_Grpc_Export_And_Restore_Users(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	backedUp, err := userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "backed-up-user",
+		Password:    "backed-up-password",
+		Role:        "user",
+		Email:       "backed-up@example.com",
+	})
+	assert.Nil(t, err)
+
+	group, err := userClient.SaveGroup(context.Background(), &users.SaveGroupRequest{AccessToken: adminLogin.AccessToken, Name: "Backed up"})
+	assert.Nil(t, err)
+	_, err = userClient.AddGroupMember(context.Background(), &users.GroupMemberRequest{
+		AccessToken: adminLogin.AccessToken,
+		GroupId:     group.Id,
+		UserId:      backedUp.Id,
+	})
+	assert.Nil(t, err)
+
+	// Deleted users that can still be restored are backed up too.
+	deleted, err := userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "backed-up-deleted-user",
+		Password:    "backed-up-password",
+		Role:        "user",
+	})
+	assert.Nil(t, err)
+	_, err = userClient.DeleteUser(context.Background(), &users.DeleteUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		UserId:      deleted.Id,
+		Version:     deleted.Version,
+	})
+	assert.Nil(t, err)
+
+	stream, err := userClient.ExportUsers(context.Background(), &users.ExportUsersRequest{})
+	assert.Nil(t, err)
+	_, err = receiveUsersArchive(stream)
+	assert.Equal(t, status.Error(codes.Unauthenticated, "invalid token"), err)
+
+	stream, err = userClient.ExportUsers(context.Background(), &users.ExportUsersRequest{AccessToken: adminLogin.AccessToken})
+	assert.Nil(t, err)
+	withoutPasswords, err := receiveUsersArchive(stream)
+	assert.Nil(t, err)
+	assert.Contains(t, string(withoutPasswords), `"username":"backed-up-user"`)
+	assert.Contains(t, string(withoutPasswords), `"username":"backed-up-deleted-user"`)
+	assert.Contains(t, string(withoutPasswords), `"deletedAt":`)
+	assert.Contains(t, string(withoutPasswords), `"name":"Backed up"`)
+	assert.Contains(t, string(withoutPasswords), `"groupMembership":{"groupId":"`+group.Id+`","userId":"`+backedUp.Id+`"}`)
+	assert.NotContains(t, string(withoutPasswords), "$2a$")
+
+	stream, err = userClient.ExportUsers(context.Background(), &users.ExportUsersRequest{AccessToken: adminLogin.AccessToken, WithPasswords: true})
+	assert.Nil(t, err)
+	archive, err := receiveUsersArchive(stream)
+	assert.Nil(t, err)
+	assert.Contains(t, string(archive), "$2a$")
+
+	// Everything in the archive is stored already.
+	report, err := restoreUsers(adminLogin.AccessToken, false, archive)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(0), report.RolesCreated)
+	assert.True(t, report.RolesMatched >= 2)
+	assert.Equal(t, int32(0), report.GroupsCreated)
+	assert.True(t, report.GroupsMatched >= 1)
+	assert.Equal(t, int32(0), report.MembershipsRestored)
+	assert.Equal(t, int32(0), report.UsersRestored)
+	assert.True(t, report.UsersSkipped > 0)
+
+	tampered := bytes.Replace(archive, []byte("backed-up@example.com"), []byte("evil@example.com"), 1)
+	_, err = restoreUsers(adminLogin.AccessToken, false, tampered)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = restoreUsers("", false, archive)
+	assert.Equal(t, status.Error(codes.Unauthenticated, "invalid token"), err)
+}
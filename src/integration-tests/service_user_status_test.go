@@ -0,0 +1,88 @@
+package integration_tests
+
+import (
+	"context"
+	"testing"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Grpc_Suspend_And_Reactivate_User(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	user, err := userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "suspended-user",
+		Password:    "password",
+		Role:        "user",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "active", user.Status)
+
+	loginReq := &users.LoginRequest{Username: "suspended-user", Password: "password"}
+	userLogin, err := userClient.Login(context.Background(), loginReq)
+	assert.Nil(t, err)
+
+	_, err = userClient.SuspendUser(context.Background(), &users.UpdateUserStatusRequest{
+		AccessToken: userLogin.AccessToken,
+		UserId:      user.Id,
+		Version:     user.Version,
+		Reason:      "spam",
+	})
+	assert.Equal(t, status.Error(codes.Unauthenticated, "incorrect permissions"), err)
+
+	suspended, err := userClient.SuspendUser(context.Background(), &users.UpdateUserStatusRequest{
+		AccessToken: adminLogin.AccessToken,
+		UserId:      user.Id,
+		Version:     user.Version,
+		Reason:      "spam",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "suspended", suspended.Status)
+	assert.Equal(t, "spam", suspended.StatusReason)
+	assert.Equal(t, user.Version+1, suspended.Version)
+
+	// Changes made from an outdated version are rejected.
+	_, err = userClient.ReactivateUser(context.Background(), &users.UpdateUserStatusRequest{
+		AccessToken: adminLogin.AccessToken,
+		UserId:      user.Id,
+		Version:     user.Version,
+	})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	// The session ended with the suspension, including the access token already issued.
+	_, err = userClient.Refresh(context.Background(), &users.RefreshRequest{RefreshToken: userLogin.RefreshToken})
+	assert.Error(t, err)
+
+	_, err = userClient.GetLoginHistory(context.Background(), &users.GetLoginHistoryRequest{AccessToken: userLogin.AccessToken})
+	assert.Equal(t, status.Error(codes.Unauthenticated, "invalid token"), err)
+
+	_, err = userClient.Login(context.Background(), loginReq)
+	assert.Equal(t, status.Error(codes.PermissionDenied, "user is not active"), err)
+
+	_, err = userClient.SuspendUser(context.Background(), &users.UpdateUserStatusRequest{
+		AccessToken: adminLogin.AccessToken,
+		UserId:      user.Id,
+		Version:     suspended.Version,
+		Reason:      "spam",
+	})
+	assert.Equal(t, status.Error(codes.FailedPrecondition, "invalid status transition"), err)
+
+	reactivated, err := userClient.ReactivateUser(context.Background(), &users.UpdateUserStatusRequest{
+		AccessToken: adminLogin.AccessToken,
+		UserId:      user.Id,
+		Version:     suspended.Version,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "active", reactivated.Status)
+
+	_, err = userClient.Login(context.Background(), loginReq)
+	assert.Nil(t, err)
+}
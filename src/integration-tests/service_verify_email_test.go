@@ -0,0 +1,97 @@
+package integration_tests
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var verificationLinkRegexp = regexp.MustCompile(`http://localhost/verify-email\?\S+`)
+
+// Gets the token of the last verification link sent to an address.
+func lastVerificationToken(t *testing.T, to string) string {
+	email, ok := testMailer.LastTo(to)
+	assert.True(t, ok)
+
+	link, err := url.Parse(verificationLinkRegexp.FindString(email.Body))
+	assert.Nil(t, err)
+	return link.Query().Get("token")
+}
+
+func Test_Grpc_VerifyEmail(t *testing.T) {
+	adminLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: databaseSettings.DefaultUserUsername,
+		Password: databaseSettings.DefaultUserPassword,
+	})
+	assert.Nil(t, err)
+
+	created, err := userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "email-user",
+		Password:    "password",
+		Role:        "admin",
+		Email:       "email-user@example.com",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "email-user@example.com", created.Email)
+	assert.False(t, created.EmailVerified)
+
+	// Same email with another casing
+	_, err = userClient.AddUser(context.Background(), &users.NewUserRequest{
+		AccessToken: adminLogin.AccessToken,
+		Username:    "email-user-2",
+		Password:    "password",
+		Role:        "admin",
+		Email:       "Email-User@example.com",
+	})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	firstToken := lastVerificationToken(t, "email-user@example.com")
+	assert.NotEmpty(t, firstToken)
+
+	// Asking for a new link invalidates the previous one.
+	userLogin, err := userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "email-user",
+		Password: "password",
+	})
+	assert.Nil(t, err)
+	assert.False(t, userLogin.User.EmailVerified)
+
+	_, err = userClient.SendVerificationEmail(context.Background(), &users.SendVerificationEmailRequest{
+		AccessToken: userLogin.AccessToken,
+	})
+	assert.Nil(t, err)
+	secondToken := lastVerificationToken(t, "email-user@example.com")
+	assert.NotEqual(t, firstToken, secondToken)
+
+	_, err = userClient.VerifyEmail(context.Background(), &users.VerifyEmailRequest{Token: firstToken})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	verified, err := userClient.VerifyEmail(context.Background(), &users.VerifyEmailRequest{Token: secondToken})
+	assert.Nil(t, err)
+	assert.True(t, verified.EmailVerified)
+
+	// Tokens can only be used once.
+	_, err = userClient.VerifyEmail(context.Background(), &users.VerifyEmailRequest{Token: secondToken})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	userLogin, err = userClient.Login(context.Background(), &users.LoginRequest{
+		Username: "email-user",
+		Password: "password",
+	})
+	assert.Nil(t, err)
+	assert.True(t, userLogin.User.EmailVerified)
+
+	_, err = userClient.SendVerificationEmail(context.Background(), &users.SendVerificationEmailRequest{
+		AccessToken: userLogin.AccessToken,
+	})
+	s, _ := status.FromError(err)
+	assert.Equal(t, codes.FailedPrecondition, s.Code())
+	assert.Equal(t, "email already verified", s.Message())
+}
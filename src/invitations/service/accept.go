@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Activates an invited user with the password and profile it chose.
+// The password follows the same policy as the imported ones. The invitation token is only
+// used up along with the activation, so it still works after a failed attempt.
+func (s DefaultInvitationService) Accept(ctx context.Context, request domain.AcceptInvitationRequest) (*domain.User, error) {
+	if len(request.Password) == 0 || len(request.Password) < s.Settings.MinPasswordLength || len(request.Password) > maxPasswordLength {
+		return nil, domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	invitation, err := s.OneTimeTokenService.Peek(ctx, domain.TokenPurposeInvitation, request.Token)
+	if err != nil {
+		return nil, err
+	}
+	// The invitee is looked up in the organization it was invited to, as the
+	// request only carries the token.
+	ctx = domain.WithOrganization(ctx, invitation.OrganizationID)
+
+	user, err := s.UserRepo.GetByUUID(ctx, invitation.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Status != domain.UserStatusPending {
+		return nil, domain.ErrInvalidToken
+	}
+
+	passwordBytes, err := bcrypt.GenerateFromPassword([]byte(request.Password), s.BcryptHashingCost)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.UserRepo.Activate(ctx, user.ID, user.Version, string(passwordBytes), domain.UserProfile{
+		FirstName: strings.TrimSpace(request.Profile.FirstName),
+		LastName:  strings.TrimSpace(request.Profile.LastName),
+	}, invitation.ID)
+	// Another attempt accepted the invitation meanwhile.
+	if errors.Is(err, domain.ErrVersionConflict) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.UserService.GetUserByUUID(ctx, user.ID)
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var acceptRequest = domain.AcceptInvitationRequest{
+	Token:    "the-token",
+	Password: "password",
+	Profile:  domain.UserProfile{FirstName: " Alice ", LastName: "Smith"},
+}
+
+func TestAccept_MissingPassword(t *testing.T) {
+	res, err := newService(nil, nil, nil, nil, nil).Accept(context.TODO(), domain.AcceptInvitationRequest{Token: "the-token"})
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	assert.Nil(t, res)
+}
+
+func TestAccept_PasswordOutsidePolicy(t *testing.T) {
+	// The invitation isn't looked up, so it still works with a valid password.
+	tokenService := new(mocks.OneTimeTokenService)
+	for _, password := range []string{"short", strings.Repeat("a", 73)} {
+		request := acceptRequest
+		request.Password = password
+		res, err := newService(nil, nil, nil, tokenService, nil).Accept(context.TODO(), request)
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		assert.Nil(t, res)
+	}
+	tokenService.AssertNotCalled(t, "Peek", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAccept_InvalidToken(t *testing.T) {
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Peek", mock.Anything, domain.TokenPurposeInvitation, "the-token").
+		Once().Return(domain.OneTimeToken{}, domain.ErrInvalidToken)
+
+	res, err := newService(nil, nil, nil, tokenService, nil).Accept(context.TODO(), acceptRequest)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	assert.Nil(t, res)
+	tokenService.AssertExpectations(t)
+}
+
+func TestAccept_UserNotInvitedAnymore(t *testing.T) {
+	cases := map[string]struct {
+		user *domain.User
+		err  error
+	}{
+		"deleted user": {nil, sql.ErrNoRows},
+		"active user":  {&domain.User{Status: domain.UserStatusActive}, nil},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			userID, invitationID := uuid.New(), uuid.New()
+			tokenService := new(mocks.OneTimeTokenService)
+			tokenService.On("Peek", mock.Anything, domain.TokenPurposeInvitation, "the-token").
+				Once().Return(domain.OneTimeToken{ID: invitationID, UserID: userID}, nil)
+			userRepo := new(mocks.UserRepository)
+			userRepo.On("GetByUUID", mock.Anything, userID).Once().Return(c.user, c.err)
+
+			res, err := newService(userRepo, nil, nil, tokenService, nil).Accept(context.TODO(), acceptRequest)
+			assert.Equal(t, domain.ErrInvalidToken, err)
+			assert.Nil(t, res)
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccept_FailedActivationKeepsInvitation(t *testing.T) {
+	cases := map[string]struct {
+		activateErr error
+		err         error
+	}{
+		"accepted meanwhile": {domain.ErrVersionConflict, domain.ErrInvalidToken},
+		"error activating":   {errors.New("boom"), errors.New("boom")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			userID, invitationID := uuid.New(), uuid.New()
+			tokenService := new(mocks.OneTimeTokenService)
+			tokenService.On("Peek", mock.Anything, domain.TokenPurposeInvitation, "the-token").
+				Once().Return(domain.OneTimeToken{ID: invitationID, UserID: userID}, nil)
+			userRepo := new(mocks.UserRepository)
+			userRepo.On("GetByUUID", mock.Anything, userID).
+				Once().Return(&domain.User{ID: userID, Status: domain.UserStatusPending, Version: 2}, nil)
+			userRepo.On("Activate", mock.Anything, userID, 2, mock.Anything, mock.Anything, invitationID).
+				Once().Return(c.activateErr)
+
+			// The token is only used up by the activation itself.
+			res, err := newService(userRepo, nil, nil, tokenService, nil).Accept(context.TODO(), acceptRequest)
+			assert.Equal(t, c.err, err)
+			assert.Nil(t, res)
+			tokenService.AssertNotCalled(t, "Consume", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAccept_Success(t *testing.T) {
+	userID, invitationID := uuid.New(), uuid.New()
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Peek", mock.Anything, domain.TokenPurposeInvitation, "the-token").
+		Once().Return(domain.OneTimeToken{ID: invitationID, UserID: userID}, nil)
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userID).
+		Once().Return(&domain.User{ID: userID, Status: domain.UserStatusPending, Version: 2}, nil)
+	userRepo.On("Activate", mock.Anything, userID, 2, mock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte("password")) == nil
+	}), domain.UserProfile{FirstName: "Alice", LastName: "Smith"}, invitationID).Once().Return(nil)
+
+	activated := &domain.User{ID: userID, Status: domain.UserStatusActive, Version: 3}
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(activated, nil)
+
+	res, err := newService(userRepo, userService, nil, tokenService, nil).Accept(context.TODO(), acceptRequest)
+	assert.Nil(t, err)
+	assert.Equal(t, activated, res)
+	tokenService.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
+
+func TestAccept_ScopedToOrganizationOfToken(t *testing.T) {
+	userID, organizationID := uuid.New(), uuid.New()
+	inOrganization := mock.MatchedBy(func(ctx context.Context) bool {
+		return domain.OrganizationFromContext(ctx) == organizationID
+	})
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Peek", mock.Anything, domain.TokenPurposeInvitation, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID, OrganizationID: organizationID}, nil)
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", inOrganization, userID).
+		Once().Return(&domain.User{ID: userID, Status: domain.UserStatusPending, Version: 2}, nil)
+	userRepo.On("Activate", inOrganization, userID, 2, mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+
+	activated := &domain.User{ID: userID, Status: domain.UserStatusActive, OrganizationID: organizationID}
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", inOrganization, userID).Once().Return(activated, nil)
+
+	res, err := newService(userRepo, userService, nil, tokenService, nil).Accept(context.TODO(), acceptRequest)
+	assert.Nil(t, err)
+	assert.Equal(t, activated, res)
+	userRepo.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets the invitations that weren't accepted nor revoked yet, oldest first.
+// Expired invitations are included, so they can be sent again.
+func (s DefaultInvitationService) GetPending(ctx context.Context) ([]domain.Invitation, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	tokens, err := s.OneTimeTokenRepo.GetByPurpose(ctx, domain.TokenPurposeInvitation)
+	if err != nil {
+		return make([]domain.Invitation, 0), err
+	}
+
+	result := make([]domain.Invitation, 0, len(tokens))
+	for _, token := range tokens {
+		user, err := s.UserService.GetUserByUUID(ctx, token.UserID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return make([]domain.Invitation, 0), err
+		}
+		if user.Status != domain.UserStatusPending {
+			continue
+		}
+
+		result = append(result, domain.Invitation{
+			User:      user,
+			ExpiresAt: token.ExpiresAt,
+			CreatedAt: token.CreatedAt,
+		})
+	}
+	return result, nil
+}
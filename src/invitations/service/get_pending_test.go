@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetPending_ErrorFetchingTokens(t *testing.T) {
+	tokenRepo := new(mocks.OneTimeTokenRepository)
+	tokenRepo.On("GetByPurpose", mock.Anything, domain.TokenPurposeInvitation).
+		Once().Return(make([]domain.OneTimeToken, 0), errors.New("boom"))
+
+	res, err := newService(nil, nil, tokenRepo, nil, nil).GetPending(context.TODO())
+	assert.Contains(t, err.Error(), "boom")
+	assert.Empty(t, res)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestGetPending_ErrorFetchingUser(t *testing.T) {
+	userID := uuid.New()
+	tokenRepo := new(mocks.OneTimeTokenRepository)
+	tokenRepo.On("GetByPurpose", mock.Anything, domain.TokenPurposeInvitation).
+		Once().Return([]domain.OneTimeToken{{UserID: userID}}, nil)
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(nil, errors.New("boom"))
+
+	res, err := newService(nil, userService, tokenRepo, nil, nil).GetPending(context.TODO())
+	assert.Contains(t, err.Error(), "boom")
+	assert.Empty(t, res)
+	userService.AssertExpectations(t)
+}
+
+func TestGetPending_Success(t *testing.T) {
+	pendingID, goneID, activeID := uuid.New(), uuid.New(), uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+	createdAt := time.Now()
+
+	tokenRepo := new(mocks.OneTimeTokenRepository)
+	tokenRepo.On("GetByPurpose", mock.Anything, domain.TokenPurposeInvitation).Once().Return([]domain.OneTimeToken{
+		{UserID: pendingID, ExpiresAt: expiresAt, CreatedAt: createdAt},
+		{UserID: goneID},
+		{UserID: activeID},
+	}, nil)
+
+	pending := &domain.User{ID: pendingID, Status: domain.UserStatusPending}
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.Anything, pendingID).Once().Return(pending, nil)
+	userService.On("GetUserByUUID", mock.Anything, goneID).Once().Return(nil, sql.ErrNoRows)
+	userService.On("GetUserByUUID", mock.Anything, activeID).Once().Return(&domain.User{ID: activeID, Status: domain.UserStatusActive}, nil)
+
+	res, err := newService(nil, userService, tokenRepo, nil, nil).GetPending(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.Invitation{{User: pending, ExpiresAt: expiresAt, CreatedAt: createdAt}}, res)
+	tokenRepo.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
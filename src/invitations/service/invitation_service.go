@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Passwords are hashed with bcrypt, which ignores anything after 72 bytes.
+const maxPasswordLength = 72
+
+type DefaultInvitationService struct {
+	Logger              *log.Logger
+	UserRepo            domain.UserRepository
+	UserService         domain.UserService
+	OneTimeTokenRepo    domain.OneTimeTokenRepository
+	OneTimeTokenService domain.OneTimeTokenService
+	Notifier            domain.Notifier
+	ContextTimeout      time.Duration
+	BcryptHashingCost   int
+	Settings            domain.InvitationSettings
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	userRepo domain.UserRepository,
+	userService domain.UserService,
+	oneTimeTokenRepo domain.OneTimeTokenRepository,
+	oneTimeTokenService domain.OneTimeTokenService,
+	notifier domain.Notifier,
+	contextTimeout time.Duration,
+	bcryptHashingCost int,
+	settings domain.InvitationSettings,
+) domain.InvitationService {
+	return DefaultInvitationService{
+		logger,
+		userRepo,
+		userService,
+		oneTimeTokenRepo,
+		oneTimeTokenService,
+		notifier,
+		contextTimeout,
+		bcryptHashingCost,
+		settings,
+	}
+}
+
+// Instantiation for tests
+func newService(
+	userRepo domain.UserRepository,
+	userService domain.UserService,
+	oneTimeTokenRepo domain.OneTimeTokenRepository,
+	oneTimeTokenService domain.OneTimeTokenService,
+	notifier domain.Notifier,
+) DefaultInvitationService {
+	return DefaultInvitationService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		userRepo,
+		userService,
+		oneTimeTokenRepo,
+		oneTimeTokenService,
+		notifier,
+		time.Duration(5 * time.Second),
+		2,
+		domain.InvitationSettings{
+			AcceptURL:         "https://example.com/accept-invitation",
+			TokenTTL:          72 * time.Hour,
+			MinPasswordLength: 8,
+		},
+	}
+}
+
+// Builds the link to accept an invitation with the token.
+func (s DefaultInvitationService) acceptLink(token string) (string, error) {
+	link, err := url.Parse(s.Settings.AcceptURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String(), nil
+}
+
+// Gets a user that is still invited, with its role.
+func (s DefaultInvitationService) pendingUser(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	user, err := s.UserService.GetUserByUUID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Status != domain.UserStatusPending {
+		return nil, domain.ErrNotAllowed
+	}
+	return user, nil
+}
+
+// Issues an invitation token for a pending user and sends it the link.
+// The invitation is kept when it can't be sent, so it can be sent again later.
+func (s DefaultInvitationService) send(ctx context.Context, user *domain.User) (domain.Invitation, error) {
+	createdAt := time.Now()
+	token, err := s.OneTimeTokenService.Issue(ctx, user.ID, domain.TokenPurposeInvitation, "", s.Settings.TokenTTL)
+	if err != nil {
+		return domain.Invitation{}, err
+	}
+
+	link, err := s.acceptLink(token)
+	if err != nil {
+		return domain.Invitation{}, err
+	}
+
+	hours := int(math.Ceil(s.Settings.TokenTTL.Hours()))
+	err = s.Notifier.Notify(ctx, user, domain.Notification{
+		Subject: "You have been invited",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nAn account was created for you. Choose your password to start using it by opening the following link:\n%s\n\nThe link expires in %d hours.\n",
+			user.Username, link, hours,
+		),
+	})
+	if err != nil {
+		s.Logger.Printf("error sending the invitation to user {%s}: %v\n", user.ID.String(), err)
+	}
+
+	return domain.Invitation{
+		User:      user,
+		ExpiresAt: createdAt.Add(s.Settings.TokenTTL),
+		CreatedAt: createdAt,
+	}, nil
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/helpers"
+)
+
+// Creates a pending user without a password, and sends it a link to choose one.
+// Fails with domain.ErrNotFound when the role doesn't exist.
+func (s DefaultInvitationService) Invite(ctx context.Context, request domain.InviteUserRequest) (domain.Invitation, error) {
+	email := strings.TrimSpace(request.Email)
+	if !helpers.IsValidEmail(email) || len(request.RoleSlug) == 0 {
+		return domain.Invitation{}, domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	user, err := s.UserService.Store(ctx, domain.StoreUserRequest{
+		Username: request.Username,
+		Email:    email,
+		RoleSlug: request.RoleSlug,
+		Status:   domain.UserStatusPending,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Invitation{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Invitation{}, err
+	}
+
+	return s.send(ctx, user)
+}
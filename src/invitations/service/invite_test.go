@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var inviteRequest = domain.InviteUserRequest{Username: "alice", Email: " alice@example.com ", RoleSlug: "user"}
+
+func TestInvite_InvalidInput(t *testing.T) {
+	s := newService(nil, nil, nil, nil, nil)
+
+	for _, request := range []domain.InviteUserRequest{
+		{Username: "alice", RoleSlug: "user"},
+		{Username: "alice", Email: "alice", RoleSlug: "user"},
+		{Username: "alice", Email: "alice@example.com"},
+	} {
+		res, err := s.Invite(context.TODO(), request)
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		assert.Empty(t, res)
+	}
+}
+
+func TestInvite_ErrorStoringUser(t *testing.T) {
+	cases := map[string]struct {
+		storeErr error
+		expected error
+	}{
+		"unknown role":   {sql.ErrNoRows, domain.ErrNotFound},
+		"username taken": {domain.ErrAlreadyExists, domain.ErrAlreadyExists},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			userService := new(mocks.UserService)
+			userService.On("Store", mock.Anything, mock.Anything).Once().Return(nil, c.storeErr)
+
+			res, err := newService(nil, userService, nil, nil, nil).Invite(context.TODO(), inviteRequest)
+			assert.Equal(t, c.expected, err)
+			assert.Empty(t, res)
+			userService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInvite_ErrorIssuingToken(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com", Status: domain.UserStatusPending}
+	userService := new(mocks.UserService)
+	userService.On("Store", mock.Anything, mock.Anything).Once().Return(user, nil)
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Issue", mock.Anything, user.ID, domain.TokenPurposeInvitation, "", 72*time.Hour).
+		Once().Return("", errors.New("boom"))
+
+	res, err := newService(nil, userService, nil, tokenService, nil).Invite(context.TODO(), inviteRequest)
+	assert.Contains(t, err.Error(), "boom")
+	assert.Empty(t, res)
+	tokenService.AssertExpectations(t)
+}
+
+func TestInvite_NotificationFailureIsOnlyLogged(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com", Status: domain.UserStatusPending}
+	userService := new(mocks.UserService)
+	userService.On("Store", mock.Anything, mock.Anything).Once().Return(user, nil)
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Issue", mock.Anything, user.ID, domain.TokenPurposeInvitation, "", 72*time.Hour).
+		Once().Return("the-token", nil)
+	notifier := new(mocks.Notifier)
+	notifier.On("Notify", mock.Anything, user, mock.AnythingOfType("domain.Notification")).
+		Once().Return(errors.New("boom"))
+
+	res, err := newService(nil, userService, nil, tokenService, notifier).Invite(context.TODO(), inviteRequest)
+	assert.Nil(t, err)
+	assert.Equal(t, user, res.User)
+	notifier.AssertExpectations(t)
+}
+
+func TestInvite_Success(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com", Status: domain.UserStatusPending}
+	userService := new(mocks.UserService)
+	userService.On("Store", mock.Anything, domain.StoreUserRequest{
+		Username: "alice",
+		Email:    "alice@example.com",
+		RoleSlug: "user",
+		Status:   domain.UserStatusPending,
+	}).Once().Return(user, nil)
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Issue", mock.Anything, user.ID, domain.TokenPurposeInvitation, "", 72*time.Hour).
+		Once().Return("the-token", nil)
+	notifier := new(mocks.Notifier)
+	notifier.On("Notify", mock.Anything, user, mock.MatchedBy(func(notification domain.Notification) bool {
+		return strings.Contains(notification.Body, "https://example.com/accept-invitation?token=the-token") &&
+			strings.Contains(notification.Body, "72 hours")
+	})).Once().Return(nil)
+
+	res, err := newService(nil, userService, nil, tokenService, notifier).Invite(context.TODO(), inviteRequest)
+	assert.Nil(t, err)
+	assert.Equal(t, user, res.User)
+	assert.Equal(t, 72*time.Hour, res.ExpiresAt.Sub(res.CreatedAt))
+	userService.AssertExpectations(t)
+	tokenService.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
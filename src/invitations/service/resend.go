@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Sends a new invitation to a pending user, the previous links stop working.
+// Fails with domain.ErrNotAllowed when the user isn't invited anymore.
+func (s DefaultInvitationService) Resend(ctx context.Context, userID uuid.UUID) (domain.Invitation, error) {
+	if userID == uuid.Nil {
+		return domain.Invitation{}, domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	user, err := s.pendingUser(ctx, userID)
+	if err != nil {
+		return domain.Invitation{}, err
+	}
+
+	if err = s.OneTimeTokenService.Revoke(ctx, user.ID, domain.TokenPurposeInvitation); err != nil {
+		return domain.Invitation{}, err
+	}
+
+	return s.send(ctx, user)
+}
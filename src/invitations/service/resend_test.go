@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestResend_InvalidID(t *testing.T) {
+	res, err := newService(nil, nil, nil, nil, nil).Resend(context.TODO(), uuid.Nil)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	assert.Empty(t, res)
+}
+
+func TestResend_UserNotInvited(t *testing.T) {
+	cases := map[string]struct {
+		user     *domain.User
+		err      error
+		expected error
+	}{
+		"unknown user": {nil, sql.ErrNoRows, domain.ErrNotFound},
+		"active user":  {&domain.User{Status: domain.UserStatusActive}, nil, domain.ErrNotAllowed},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			userID := uuid.New()
+			userService := new(mocks.UserService)
+			userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(c.user, c.err)
+
+			res, err := newService(nil, userService, nil, nil, nil).Resend(context.TODO(), userID)
+			assert.Equal(t, c.expected, err)
+			assert.Empty(t, res)
+			userService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResend_Success(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com", Status: domain.UserStatusPending}
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.Anything, user.ID).Once().Return(user, nil)
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Revoke", mock.Anything, user.ID, domain.TokenPurposeInvitation).Once().Return(nil)
+	tokenService.On("Issue", mock.Anything, user.ID, domain.TokenPurposeInvitation, "", 72*time.Hour).
+		Once().Return("the-token", nil)
+	notifier := new(mocks.Notifier)
+	notifier.On("Notify", mock.Anything, user, mock.AnythingOfType("domain.Notification")).Once().Return(nil)
+
+	res, err := newService(nil, userService, nil, tokenService, notifier).Resend(context.TODO(), user.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, user, res.User)
+	tokenService.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
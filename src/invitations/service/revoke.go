@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Revokes the invitation of a pending user, deleting the user like any other.
+// Fails with domain.ErrNotAllowed when the user isn't invited anymore.
+func (s DefaultInvitationService) Revoke(ctx context.Context, userID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	user, err := s.pendingUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.OneTimeTokenService.Revoke(ctx, user.ID, domain.TokenPurposeInvitation); err != nil {
+		return err
+	}
+
+	return s.UserRepo.Delete(ctx, user.ID, user.Version, time.Now())
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRevoke_InvalidID(t *testing.T) {
+	err := newService(nil, nil, nil, nil, nil).Revoke(context.TODO(), uuid.Nil)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestRevoke_UserNotInvited(t *testing.T) {
+	cases := map[string]struct {
+		user     *domain.User
+		err      error
+		expected error
+	}{
+		"unknown user": {nil, sql.ErrNoRows, domain.ErrNotFound},
+		"active user":  {&domain.User{Status: domain.UserStatusActive}, nil, domain.ErrNotAllowed},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			userID := uuid.New()
+			userService := new(mocks.UserService)
+			userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(c.user, c.err)
+
+			err := newService(nil, userService, nil, nil, nil).Revoke(context.TODO(), userID)
+			assert.Equal(t, c.expected, err)
+			userService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevoke_Success(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Status: domain.UserStatusPending, Version: 1}
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.Anything, user.ID).Once().Return(user, nil)
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Revoke", mock.Anything, user.ID, domain.TokenPurposeInvitation).Once().Return(nil)
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("Delete", mock.Anything, user.ID, 1, mock.AnythingOfType("time.Time")).Once().Return(nil)
+
+	err := newService(userRepo, userService, nil, tokenService, nil).Revoke(context.TODO(), user.ID)
+	assert.Nil(t, err)
+	tokenService.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
@@ -0,0 +1,84 @@
+package authenticator
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Name of the authenticator in the configured backends.
+const Backend = "ldap"
+
+// Authenticates the logins against an LDAP directory, keeping a local user for every
+// directory user with its email, names and the role its groups map to.
+type LDAPAuthenticator struct {
+	Logger   *log.Logger
+	UserRepo domain.UserRepository
+	RoleRepo domain.RoleRepository
+	Settings domain.LDAPSettings
+}
+
+// Constructor
+func New(
+	logger *log.Logger,
+	userRepo domain.UserRepository,
+	roleRepo domain.RoleRepository,
+	settings domain.LDAPSettings,
+) domain.Authenticator {
+	return LDAPAuthenticator{
+		logger,
+		userRepo,
+		roleRepo,
+		settings,
+	}
+}
+
+// Instantiation for tests
+func newAuthenticator(userRepo domain.UserRepository, roleRepo domain.RoleRepository, settings domain.LDAPSettings) LDAPAuthenticator {
+	if settings.Timeout == 0 {
+		settings.Timeout = time.Duration(2 * time.Second)
+	}
+	return LDAPAuthenticator{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		userRepo,
+		roleRepo,
+		settings,
+	}
+}
+
+// Binds to the directory as the user with its password, and gets its local user, which is
+// provisioned or updated with the details of the directory.
+// Users the directory doesn't have fail with domain.ErrNotFound, so they can be checked by
+// the other authenticators, and users that can't be provisioned with domain.ErrNotAllowed.
+// The directory is configured for the whole service, so it only serves the default organization.
+func (a LDAPAuthenticator) Authenticate(ctx context.Context, username string, password string) (*domain.User, error) {
+	// Binds without a password are anonymous, and would always succeed.
+	if len(username) == 0 || len(password) == 0 {
+		return nil, domain.ErrBadParamInput
+	}
+	if domain.OrganizationFromContext(ctx) != domain.DefaultOrganizationID {
+		return nil, domain.ErrNotFound
+	}
+
+	conn, err := a.dial(ctx)
+	if err != nil {
+		a.Logger.Printf("error connecting to the directory: %v\n", err)
+		return nil, err
+	}
+	defer conn.Close()
+
+	directoryUser, err := a.bindUser(conn, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := a.groups(conn, directoryUser)
+	if err != nil {
+		a.Logger.Printf("error getting the groups of %v: %v\n", directoryUser.DN, err)
+		return nil, err
+	}
+	return a.syncUser(ctx, username, directoryUser, groups)
+}
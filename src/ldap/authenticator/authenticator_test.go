@@ -0,0 +1,187 @@
+package authenticator
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/plagioriginal/user-microservice/ldap/mockldap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	serviceDN  = "cn=service,dc=example,dc=com"
+	aliceDN    = "uid=alice,ou=people,dc=example,dc=com"
+	adminsDN   = "cn=admins,ou=groups,dc=example,dc=com"
+	everyoneDN = "cn=everyone,ou=groups,dc=example,dc=com"
+)
+
+// Starts a directory with a search account, and alice in the admins group.
+func newDirectory(t *testing.T, start func() (*mockldap.Server, error)) *mockldap.Server {
+	server, err := start()
+	assert.Nil(t, err)
+	t.Cleanup(server.Close)
+
+	server.AddEntry("dc=example,dc=com", map[string][]string{"objectClass": {"domain"}})
+	server.AddEntry("ou=people,dc=example,dc=com", map[string][]string{"objectClass": {"organizationalUnit"}})
+	server.AddEntry("ou=groups,dc=example,dc=com", map[string][]string{"objectClass": {"organizationalUnit"}})
+	server.AddEntry(serviceDN, map[string][]string{"userPassword": {"service secret"}})
+	server.AddEntry(aliceDN, map[string][]string{
+		"objectClass":  {"inetOrgPerson"},
+		"uid":          {"alice"},
+		"mail":         {"alice@example.com"},
+		"givenName":    {"Alice"},
+		"sn":           {"Liddell"},
+		"memberOf":     {everyoneDN, adminsDN},
+		"userPassword": {"correct horse"},
+	})
+	server.AddEntry(adminsDN, map[string][]string{"objectClass": {"groupOfNames"}, "member": {aliceDN}})
+	server.AddEntry(everyoneDN, map[string][]string{"objectClass": {"groupOfNames"}, "member": {aliceDN}})
+	return server
+}
+
+// Settings searching for the users as the search account, on a directory.
+func newSettings(server *mockldap.Server) domain.LDAPSettings {
+	return domain.LDAPSettings{
+		URL:                server.URL(),
+		RootCAs:            server.RootCAs(),
+		BindDN:             serviceDN,
+		BindPassword:       "service secret",
+		BaseDN:             "dc=example,dc=com",
+		UserFilter:         "(&(objectClass=inetOrgPerson)(uid=%s))",
+		EmailAttribute:     "mail",
+		FirstNameAttribute: "givenName",
+		LastNameAttribute:  "sn",
+		GroupAttribute:     "memberOf",
+		RoleMapping:        map[string]string{"CN=Admins, OU=Groups, DC=example, DC=com": "admin"},
+		DefaultRole:        "user",
+		AutoProvision:      true,
+	}
+}
+
+// Expects alice to be provisioned with a role.
+func expectProvisioned(userRepo *mocks.UserRepository, roleRepo *mocks.RoleRepository, role domain.Role) uuid.UUID {
+	userID := uuid.New()
+	userRepo.On("GetByUsername", mock.Anything, "alice").Once().Return(nil, sql.ErrNoRows)
+	roleRepo.On("GetBySlug", mock.Anything, role.RoleSlug).Once().Return(role, nil)
+	userRepo.On("Store", mock.Anything, domain.User{
+		Username:  "alice",
+		Email:     "alice@example.com",
+		FirstName: "Alice",
+		LastName:  "Liddell",
+		RoleId:    role.ID,
+		Status:    domain.UserStatusActive,
+		Backend:   Backend,
+	}).Once().Return(&domain.User{ID: userID, Username: "alice", Email: "alice@example.com", RoleId: role.ID, Status: domain.UserStatusActive, Backend: Backend}, nil)
+	userRepo.On("MarkEmailVerified", mock.Anything, userID, "alice@example.com").Once().Return(nil)
+	return userID
+}
+
+func Test_Authenticate_FailIfNoPassword(t *testing.T) {
+	authenticator := newAuthenticator(nil, nil, domain.LDAPSettings{})
+	user, err := authenticator.Authenticate(context.TODO(), "alice", "")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func Test_Authenticate_FailIfDirectoryUnreachable(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	settings := newSettings(server)
+	server.Close()
+
+	authenticator := newAuthenticator(nil, nil, settings)
+	user, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+	assert.Nil(t, user)
+	assert.Error(t, err)
+}
+
+func Test_Authenticate_ProvisionsUserWithMappedRole(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	admin := domain.Role{ID: uuid.New(), RoleSlug: "admin"}
+
+	userRepo := new(mocks.UserRepository)
+	roleRepo := new(mocks.RoleRepository)
+	userID := expectProvisioned(userRepo, roleRepo, admin)
+
+	authenticator := newAuthenticator(userRepo, roleRepo, newSettings(server))
+	user, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+	assert.Nil(t, err)
+	assert.Equal(t, userID, user.ID)
+	assert.True(t, user.EmailVerified)
+	assert.Equal(t, &admin, user.Role)
+	assert.Equal(t, 1, server.Binds(aliceDN))
+	userRepo.AssertExpectations(t)
+	roleRepo.AssertExpectations(t)
+}
+
+func Test_Authenticate_NotFoundOutsideDefaultOrganization(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	userRepo := new(mocks.UserRepository)
+	roleRepo := new(mocks.RoleRepository)
+
+	ctx := domain.WithOrganization(context.TODO(), uuid.New())
+	authenticator := newAuthenticator(userRepo, roleRepo, newSettings(server))
+	user, err := authenticator.Authenticate(ctx, "alice", "correct horse")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.Equal(t, 0, server.Binds(aliceDN))
+	userRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	userRepo.AssertNotCalled(t, "GetByUsername", mock.Anything, mock.Anything)
+}
+
+func Test_Authenticate_NotFoundIfUserNotInDirectory(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+
+	authenticator := newAuthenticator(nil, nil, newSettings(server))
+	user, err := authenticator.Authenticate(context.TODO(), "bob", "correct horse")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func Test_Authenticate_FailIfWrongPassword(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+
+	authenticator := newAuthenticator(nil, nil, newSettings(server))
+	user, err := authenticator.Authenticate(context.TODO(), "alice", "wrong horse")
+	assert.Nil(t, user)
+	assert.Equal(t, errInvalidCredentials, err)
+	assert.Equal(t, 0, server.Binds(aliceDN))
+}
+
+func Test_Authenticate_EscapesUsernameInFilter(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+
+	authenticator := newAuthenticator(nil, nil, newSettings(server))
+	user, err := authenticator.Authenticate(context.TODO(), "*", "correct horse")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func Test_Authenticate_FailIfSearchAccountRejected(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	settings := newSettings(server)
+	settings.BindPassword = "wrong"
+
+	authenticator := newAuthenticator(nil, nil, settings)
+	user, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+	assert.Nil(t, user)
+	assert.ErrorContains(t, err, "search account")
+}
+
+func Test_Authenticate_FailIfUsernameAmbiguous(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	server.AddEntry("uid=alice,ou=contractors,dc=example,dc=com", map[string][]string{
+		"objectClass":  {"inetOrgPerson"},
+		"uid":          {"alice"},
+		"userPassword": {"correct horse"},
+	})
+
+	authenticator := newAuthenticator(nil, nil, newSettings(server))
+	user, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+	assert.Nil(t, user)
+	assert.Equal(t, errAmbiguousUser, err)
+}
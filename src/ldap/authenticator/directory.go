@@ -0,0 +1,218 @@
+package authenticator
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+var (
+	errInvalidCredentials = errors.New("invalid credentials")
+	errAmbiguousUser      = errors.New("more than one directory user matches the username")
+)
+
+// Connects to the directory, over TLS unless it's a plain ldap:// URL without StartTLS.
+// The connection and every request time out with the context, or with the settings.
+func (a LDAPAuthenticator) dial(ctx context.Context) (*ldap.Conn, error) {
+	timeout := a.Settings.Timeout
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+		timeout = time.Until(deadline)
+	}
+
+	parsed, err := url.Parse(a.Settings.URL)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		ServerName:         parsed.Hostname(),
+		RootCAs:            a.Settings.RootCAs,
+		InsecureSkipVerify: a.Settings.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	conn, err := ldap.DialURL(
+		a.Settings.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: timeout}),
+		ldap.DialWithTLSConfig(tlsConfig),
+	)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(timeout)
+
+	if a.Settings.StartTLS {
+		if err = conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// User found in the directory.
+type directoryUser struct {
+	DN    string
+	Entry *ldap.Entry
+}
+
+// Binds as a user, either straight to the DN of its template, or to the DN found searching for
+// it. Returns the user with the attributes of its entry.
+func (a LDAPAuthenticator) bindUser(conn *ldap.Conn, username string, password string) (directoryUser, error) {
+	if len(a.Settings.BindDNTemplate) > 0 {
+		dn := fmt.Sprintf(a.Settings.BindDNTemplate, ldap.EscapeDN(username))
+		if err := bindError(conn.Bind(dn, password)); err != nil {
+			// Binds to missing entries are rejected like wrong passwords, so the entry is
+			// looked for to let the next authenticators try unknown users.
+			if errors.Is(err, errInvalidCredentials) && a.missingEntry(conn, dn) {
+				return directoryUser{}, domain.ErrNotFound
+			}
+			return directoryUser{}, err
+		}
+
+		entry, err := a.searchOne(conn, dn, ldap.ScopeBaseObject, "(objectClass=*)")
+		if err != nil {
+			return directoryUser{}, err
+		}
+		return directoryUser{DN: dn, Entry: entry}, nil
+	}
+
+	if err := a.bindService(conn); err != nil {
+		return directoryUser{}, err
+	}
+
+	filter := fmt.Sprintf(a.Settings.UserFilter, ldap.EscapeFilter(username))
+	entry, err := a.searchOne(conn, a.Settings.BaseDN, ldap.ScopeWholeSubtree, filter)
+	if err != nil {
+		return directoryUser{}, err
+	}
+
+	if err = bindError(conn.Bind(entry.DN, password)); err != nil {
+		return directoryUser{}, err
+	}
+	return directoryUser{DN: entry.DN, Entry: entry}, nil
+}
+
+// Tells if there's no entry at a DN, looked for as the search account. Entries that can't be
+// looked for count as existing.
+func (a LDAPAuthenticator) missingEntry(conn *ldap.Conn, dn string) bool {
+	if err := a.bindService(conn); err != nil {
+		return false
+	}
+	_, err := a.searchOne(conn, dn, ldap.ScopeBaseObject, "(objectClass=*)")
+	return errors.Is(err, domain.ErrNotFound)
+}
+
+// Binds as the account the users are searched for with, when there's one.
+func (a LDAPAuthenticator) bindService(conn *ldap.Conn) error {
+	if len(a.Settings.BindDN) == 0 {
+		return nil
+	}
+	if err := conn.Bind(a.Settings.BindDN, a.Settings.BindPassword); err != nil {
+		return fmt.Errorf("error binding as the search account: %w", err)
+	}
+	return nil
+}
+
+// Searches for the only entry matching a filter, with the attributes of the users.
+// Fails with domain.ErrNotFound when there's none.
+func (a LDAPAuthenticator) searchOne(conn *ldap.Conn, baseDN string, scope int, filter string) (*ldap.Entry, error) {
+	result, err := conn.Search(ldap.NewSearchRequest(
+		baseDN,
+		scope,
+		ldap.NeverDerefAliases,
+		2,
+		0,
+		false,
+		filter,
+		a.userAttributes(),
+		nil,
+	))
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		return nil, domain.ErrNotFound
+	}
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, errAmbiguousUser
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(result.Entries) {
+	case 0:
+		return nil, domain.ErrNotFound
+	case 1:
+		return result.Entries[0], nil
+	}
+	return nil, errAmbiguousUser
+}
+
+// Attributes of the users that are read.
+func (a LDAPAuthenticator) userAttributes() []string {
+	attributes := []string{}
+	for _, attribute := range []string{
+		a.Settings.EmailAttribute,
+		a.Settings.FirstNameAttribute,
+		a.Settings.LastNameAttribute,
+		a.Settings.GroupAttribute,
+	} {
+		if len(attribute) > 0 {
+			attributes = append(attributes, attribute)
+		}
+	}
+	// Without any attribute, the servers send them all.
+	if len(attributes) == 0 {
+		attributes = append(attributes, "1.1")
+	}
+	return attributes
+}
+
+// Gets the DNs of the groups of a user, from its group attribute or searching for the groups
+// that have it as a member. Searches are done as the search account, when there's one.
+func (a LDAPAuthenticator) groups(conn *ldap.Conn, user directoryUser) ([]string, error) {
+	if len(a.Settings.GroupBaseDN) == 0 {
+		if len(a.Settings.GroupAttribute) == 0 {
+			return nil, nil
+		}
+		return user.Entry.GetAttributeValues(a.Settings.GroupAttribute), nil
+	}
+
+	if err := a.bindService(conn); err != nil {
+		return nil, err
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		a.Settings.GroupBaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		fmt.Sprintf(a.Settings.GroupFilter, ldap.EscapeFilter(user.DN)),
+		[]string{"1.1"},
+		nil,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, 0, len(result.Entries))
+	for _, entry := range result.Entries {
+		groups = append(groups, entry.DN)
+	}
+	return groups, nil
+}
+
+// Tells apart the binds rejected for their credentials from the other errors.
+func bindError(err error) error {
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		return errInvalidCredentials
+	}
+	return err
+}
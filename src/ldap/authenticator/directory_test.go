@@ -0,0 +1,174 @@
+package authenticator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/plagioriginal/user-microservice/ldap/mockldap"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Authenticate_OverLDAPS(t *testing.T) {
+	server := newDirectory(t, mockldap.NewTLS)
+	user := domain.Role{ID: uuid.New(), RoleSlug: "user"}
+	settings := newSettings(server)
+	settings.RoleMapping = nil
+
+	userRepo := new(mocks.UserRepository)
+	roleRepo := new(mocks.RoleRepository)
+	expectProvisioned(userRepo, roleRepo, user)
+
+	authenticator := newAuthenticator(userRepo, roleRepo, settings)
+	result, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+	assert.Nil(t, err)
+	assert.Equal(t, &user, result.Role)
+	userRepo.AssertExpectations(t)
+	roleRepo.AssertExpectations(t)
+}
+
+func Test_Authenticate_OverStartTLS(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	admin := domain.Role{ID: uuid.New(), RoleSlug: "admin"}
+	settings := newSettings(server)
+	settings.StartTLS = true
+
+	userRepo := new(mocks.UserRepository)
+	roleRepo := new(mocks.RoleRepository)
+	expectProvisioned(userRepo, roleRepo, admin)
+
+	authenticator := newAuthenticator(userRepo, roleRepo, settings)
+	_, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+	assert.Nil(t, err)
+	userRepo.AssertExpectations(t)
+	roleRepo.AssertExpectations(t)
+}
+
+func Test_Authenticate_FailIfCertificateNotTrusted(t *testing.T) {
+	for name, start := range map[string]func() (*mockldap.Server, error){"ldaps": mockldap.NewTLS, "starttls": mockldap.New} {
+		t.Run(name, func(t *testing.T) {
+			server := newDirectory(t, start)
+			settings := newSettings(server)
+			settings.StartTLS = name == "starttls"
+			settings.RootCAs = nil
+
+			authenticator := newAuthenticator(nil, nil, settings)
+			user, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+			assert.Nil(t, user)
+			assert.ErrorContains(t, err, "certificate")
+			assert.Equal(t, 0, server.Binds(serviceDN))
+		})
+	}
+}
+
+func Test_Authenticate_SkipsCertificateVerificationIfInsecure(t *testing.T) {
+	server := newDirectory(t, mockldap.NewTLS)
+	admin := domain.Role{ID: uuid.New(), RoleSlug: "admin"}
+	settings := newSettings(server)
+	settings.RootCAs = nil
+	settings.InsecureSkipVerify = true
+
+	userRepo := new(mocks.UserRepository)
+	roleRepo := new(mocks.RoleRepository)
+	expectProvisioned(userRepo, roleRepo, admin)
+
+	authenticator := newAuthenticator(userRepo, roleRepo, settings)
+	_, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+	assert.Nil(t, err)
+}
+
+func Test_Authenticate_BindsAsUserWithTemplate(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	admin := domain.Role{ID: uuid.New(), RoleSlug: "admin"}
+	settings := newSettings(server)
+	settings.BindDNTemplate = "uid=%s,ou=people,dc=example,dc=com"
+	settings.BindDN, settings.BindPassword = "", ""
+
+	userRepo := new(mocks.UserRepository)
+	roleRepo := new(mocks.RoleRepository)
+	expectProvisioned(userRepo, roleRepo, admin)
+
+	authenticator := newAuthenticator(userRepo, roleRepo, settings)
+	_, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, server.Binds(aliceDN))
+	assert.Equal(t, 0, server.Binds(serviceDN))
+	userRepo.AssertExpectations(t)
+	roleRepo.AssertExpectations(t)
+}
+
+func Test_Authenticate_FailIfWrongPasswordWithTemplate(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	settings := newSettings(server)
+	settings.BindDNTemplate = "uid=%s,ou=people,dc=example,dc=com"
+
+	authenticator := newAuthenticator(nil, nil, settings)
+	user, err := authenticator.Authenticate(context.TODO(), "alice", "wrong horse")
+	assert.Nil(t, user)
+	assert.Equal(t, errInvalidCredentials, err)
+
+	// The username can't reach other parts of the DN.
+	user, err = authenticator.Authenticate(context.TODO(), "alice,ou=people", "correct horse")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func Test_Authenticate_NotFoundIfNoEntryWithTemplate(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	settings := newSettings(server)
+	settings.BindDNTemplate = "uid=%s,ou=people,dc=example,dc=com"
+
+	// Unknown users are left to the next authenticators.
+	authenticator := newAuthenticator(nil, nil, settings)
+	user, err := authenticator.Authenticate(context.TODO(), "bob", "correct horse")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrNotFound, err)
+
+	// Without a search account the entries can't be looked for.
+	settings.BindDN, settings.BindPassword = "", ""
+	authenticator = newAuthenticator(nil, nil, settings)
+	user, err = authenticator.Authenticate(context.TODO(), "bob", "correct horse")
+	assert.Nil(t, user)
+	assert.Equal(t, errInvalidCredentials, err)
+}
+
+func Test_Authenticate_SearchesAnonymously(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	server.AllowAnonymous = true
+	admin := domain.Role{ID: uuid.New(), RoleSlug: "admin"}
+	settings := newSettings(server)
+	settings.BindDN, settings.BindPassword = "", ""
+
+	userRepo := new(mocks.UserRepository)
+	roleRepo := new(mocks.RoleRepository)
+	expectProvisioned(userRepo, roleRepo, admin)
+
+	authenticator := newAuthenticator(userRepo, roleRepo, settings)
+	_, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, server.Binds(serviceDN))
+}
+
+func Test_Authenticate_SearchesGroupsOfUser(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	admin := domain.Role{ID: uuid.New(), RoleSlug: "admin"}
+	settings := newSettings(server)
+	settings.GroupAttribute = ""
+	settings.GroupBaseDN = "ou=groups,dc=example,dc=com"
+	settings.GroupFilter = "(&(objectClass=groupOfNames)(member=%s))"
+
+	userRepo := new(mocks.UserRepository)
+	roleRepo := new(mocks.RoleRepository)
+	expectProvisioned(userRepo, roleRepo, admin)
+
+	authenticator := newAuthenticator(userRepo, roleRepo, settings)
+	result, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+	assert.Nil(t, err)
+	assert.Equal(t, &admin, result.Role)
+	// Groups are searched for as the search account.
+	assert.Equal(t, 2, server.Binds(serviceDN))
+	userRepo.AssertExpectations(t)
+	roleRepo.AssertExpectations(t)
+}
@@ -0,0 +1,133 @@
+package authenticator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets the local user of a directory user, creating it on its first login when allowed, or
+// updating it with the email, names and role of the directory when they changed.
+// Attributes that aren't configured keep their local values, as does the role when none of
+// the groups is mapped and there's no default role.
+func (a LDAPAuthenticator) syncUser(ctx context.Context, username string, directoryUser directoryUser, groups []string) (*domain.User, error) {
+	roleSlug := a.mapRole(groups)
+
+	user, err := a.UserRepo.GetByUsername(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return a.provision(ctx, username, directoryUser, roleSlug)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Only the users the directory provisioned are kept in sync with it, the local accounts,
+	// the ones of the identity providers and the invited users are never taken over.
+	if user.Backend != Backend {
+		return nil, domain.ErrNotAllowed
+	}
+
+	var role domain.Role
+	if len(roleSlug) > 0 {
+		role, err = a.RoleRepo.GetBySlug(ctx, roleSlug)
+	} else {
+		role, err = a.RoleRepo.GetByUUID(ctx, user.RoleId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	email := a.attribute(directoryUser, a.Settings.EmailAttribute, user.Email)
+	profile := domain.UserProfile{
+		FirstName: a.attribute(directoryUser, a.Settings.FirstNameAttribute, user.FirstName),
+		LastName:  a.attribute(directoryUser, a.Settings.LastNameAttribute, user.LastName),
+	}
+	changed := email != user.Email || (len(email) > 0 && !user.EmailVerified) ||
+		profile.FirstName != user.FirstName || profile.LastName != user.LastName || role.ID != user.RoleId
+	if changed {
+		if err = a.UserRepo.SyncProfile(ctx, user.ID, user.Version, email, profile, role.ID); err != nil {
+			return nil, err
+		}
+		user.Email, user.EmailVerified = email, len(email) > 0
+		user.FirstName, user.LastName = profile.FirstName, profile.LastName
+		user.RoleId = role.ID
+		user.Version++
+	}
+
+	user.Role = &role
+	return user, nil
+}
+
+// Creates the active local user of a directory user, with the role its groups map to.
+// The user has no password, so it can only log in through the directory.
+func (a LDAPAuthenticator) provision(ctx context.Context, username string, directoryUser directoryUser, roleSlug string) (*domain.User, error) {
+	if !a.Settings.AutoProvision || len(roleSlug) == 0 {
+		return nil, domain.ErrNotAllowed
+	}
+
+	role, err := a.RoleRepo.GetBySlug(ctx, roleSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	email := a.attribute(directoryUser, a.Settings.EmailAttribute, "")
+	user, err := a.UserRepo.Store(ctx, domain.User{
+		Username:  username,
+		Email:     email,
+		FirstName: a.attribute(directoryUser, a.Settings.FirstNameAttribute, ""),
+		LastName:  a.attribute(directoryUser, a.Settings.LastNameAttribute, ""),
+		RoleId:    role.ID,
+		Status:    domain.UserStatusActive,
+		Backend:   Backend,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Emails come from the directory, so they are as verified as they can be.
+	if len(email) > 0 {
+		if err = a.UserRepo.MarkEmailVerified(ctx, user.ID, email); err != nil {
+			return nil, err
+		}
+		user.EmailVerified = true
+	}
+
+	user.Role = &role
+	return user, nil
+}
+
+// Gets the role slug of the first group with a mapping, or the default role.
+func (a LDAPAuthenticator) mapRole(groups []string) string {
+	mapping := make(map[string]string, len(a.Settings.RoleMapping))
+	for group, role := range a.Settings.RoleMapping {
+		mapping[normalizeDN(group)] = role
+	}
+
+	for _, group := range groups {
+		if role, ok := mapping[normalizeDN(group)]; ok {
+			return role
+		}
+	}
+	return a.Settings.DefaultRole
+}
+
+// Gets the value of an attribute of a directory user, or the fallback when the attribute
+// isn't configured.
+func (a LDAPAuthenticator) attribute(directoryUser directoryUser, name string, fallback string) string {
+	if len(name) == 0 {
+		return fallback
+	}
+	return strings.TrimSpace(directoryUser.Entry.GetAttributeValue(name))
+}
+
+// Lowercases a DN and removes the spaces around its components, so the DNs of the groups
+// match the ones of the mapping however they are written.
+func normalizeDN(dn string) string {
+	components := strings.Split(dn, ",")
+	for i, component := range components {
+		components[i] = strings.ToLower(strings.TrimSpace(component))
+	}
+	return strings.Join(components, ",")
+}
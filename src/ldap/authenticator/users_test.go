@@ -0,0 +1,199 @@
+package authenticator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/plagioriginal/user-microservice/ldap/mockldap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_Authenticate_UpdatesExistingUser(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	admin := domain.Role{ID: uuid.New(), RoleSlug: "admin"}
+	existing := &domain.User{
+		ID:       uuid.New(),
+		Username: "alice",
+		Email:    "old@example.com",
+		RoleId:   uuid.New(),
+		Status:   domain.UserStatusActive,
+		Backend:  Backend,
+		Version:  4,
+	}
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").Once().Return(existing, nil)
+	userRepo.On("SyncProfile", mock.Anything, existing.ID, 4, "alice@example.com",
+		domain.UserProfile{FirstName: "Alice", LastName: "Liddell"}, admin.ID).Once().Return(nil)
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "admin").Once().Return(admin, nil)
+
+	authenticator := newAuthenticator(userRepo, roleRepo, newSettings(server))
+	user, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+	assert.Nil(t, err)
+	assert.Equal(t, "alice@example.com", user.Email)
+	assert.True(t, user.EmailVerified)
+	assert.Equal(t, "Alice", user.FirstName)
+	assert.Equal(t, admin.ID, user.RoleId)
+	assert.Equal(t, &admin, user.Role)
+	assert.Equal(t, 5, user.Version)
+	userRepo.AssertExpectations(t)
+	roleRepo.AssertExpectations(t)
+}
+
+func Test_Authenticate_DoesntUpdateUnchangedUser(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	admin := domain.Role{ID: uuid.New(), RoleSlug: "admin"}
+	existing := &domain.User{
+		ID:            uuid.New(),
+		Username:      "alice",
+		Email:         "alice@example.com",
+		EmailVerified: true,
+		FirstName:     "Alice",
+		LastName:      "Liddell",
+		RoleId:        admin.ID,
+		Status:        domain.UserStatusActive,
+		Backend:       Backend,
+	}
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").Once().Return(existing, nil)
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "admin").Once().Return(admin, nil)
+
+	authenticator := newAuthenticator(userRepo, roleRepo, newSettings(server))
+	user, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+	assert.Nil(t, err)
+	assert.Equal(t, existing.ID, user.ID)
+	userRepo.AssertExpectations(t)
+	userRepo.AssertNotCalled(t, "SyncProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_Authenticate_KeepsRoleIfNoGroupMapped(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	current := domain.Role{ID: uuid.New(), RoleSlug: "auditor"}
+	existing := &domain.User{
+		ID:            uuid.New(),
+		Username:      "alice",
+		Email:         "alice@example.com",
+		EmailVerified: true,
+		FirstName:     "Alice",
+		LastName:      "Liddell",
+		RoleId:        current.ID,
+		Status:        domain.UserStatusActive,
+		Backend:       Backend,
+	}
+	settings := newSettings(server)
+	settings.RoleMapping = nil
+	settings.DefaultRole = ""
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").Once().Return(existing, nil)
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetByUUID", mock.Anything, current.ID).Once().Return(current, nil)
+
+	authenticator := newAuthenticator(userRepo, roleRepo, settings)
+	user, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+	assert.Nil(t, err)
+	assert.Equal(t, &current, user.Role)
+	userRepo.AssertExpectations(t)
+	roleRepo.AssertExpectations(t)
+}
+
+func Test_Authenticate_FailIfUserNotProvisionedByDirectory(t *testing.T) {
+	cases := map[string]*domain.User{
+		"local user with a password": {ID: uuid.New(), Username: "alice", Password: "hash", Status: domain.UserStatusActive},
+		"identity provider user":     {ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive},
+		"invited user":               {ID: uuid.New(), Username: "alice", Status: domain.UserStatusPending},
+	}
+
+	for name, existing := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := newDirectory(t, mockldap.New)
+
+			userRepo := new(mocks.UserRepository)
+			userRepo.On("GetByUsername", mock.Anything, "alice").Once().Return(existing, nil)
+
+			authenticator := newAuthenticator(userRepo, nil, newSettings(server))
+			user, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+			assert.Nil(t, user)
+			assert.Equal(t, domain.ErrNotAllowed, err)
+			userRepo.AssertExpectations(t)
+			userRepo.AssertNotCalled(t, "SyncProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func Test_Authenticate_FailIfSyncError(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	admin := domain.Role{ID: uuid.New(), RoleSlug: "admin"}
+	existing := &domain.User{ID: uuid.New(), Username: "alice", RoleId: uuid.New(), Status: domain.UserStatusActive, Backend: Backend}
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").Once().Return(existing, nil)
+	userRepo.On("SyncProfile", mock.Anything, existing.ID, 0, "alice@example.com", mock.Anything, admin.ID).Once().
+		Return(domain.ErrAlreadyExists)
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "admin").Once().Return(admin, nil)
+
+	authenticator := newAuthenticator(userRepo, roleRepo, newSettings(server))
+	user, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_Authenticate_DoesntProvisionIfNotAllowed(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	settings := newSettings(server)
+	settings.AutoProvision = false
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").Once().Return(nil, sql.ErrNoRows)
+
+	authenticator := newAuthenticator(userRepo, nil, settings)
+	user, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrNotAllowed, err)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_Authenticate_DoesntProvisionWithoutRole(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	settings := newSettings(server)
+	settings.RoleMapping = map[string]string{"cn=others,ou=groups,dc=example,dc=com": "admin"}
+	settings.DefaultRole = ""
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").Once().Return(nil, sql.ErrNoRows)
+
+	authenticator := newAuthenticator(userRepo, nil, settings)
+	user, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrNotAllowed, err)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_Authenticate_FailIfProvisionError(t *testing.T) {
+	server := newDirectory(t, mockldap.New)
+	admin := domain.Role{ID: uuid.New(), RoleSlug: "admin"}
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").Once().Return(nil, sql.ErrNoRows)
+	userRepo.On("Store", mock.Anything, mock.Anything).Once().Return(nil, errors.New("boom"))
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "admin").Once().Return(admin, nil)
+
+	authenticator := newAuthenticator(userRepo, roleRepo, newSettings(server))
+	user, err := authenticator.Authenticate(context.TODO(), "alice", "correct horse")
+	assert.Nil(t, user)
+	assert.EqualError(t, err, "boom")
+	userRepo.AssertExpectations(t)
+	roleRepo.AssertExpectations(t)
+}
@@ -0,0 +1,564 @@
+// Package mockldap implements an LDAP directory in memory, so the logins against a
+// directory can be driven in tests without any real LDAP server.
+//
+// It speaks enough of LDAPv3 for the authenticator: simple binds, searches with the
+// usual filters, and StartTLS. Entries are added with AddEntry, and users bind with
+// the userPassword attribute of their entry.
+package mockldap
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+)
+
+// Operations of the protocol, as application tags.
+const (
+	opBindRequest     = 0
+	opBindResponse    = 1
+	opUnbindRequest   = 2
+	opSearchRequest   = 3
+	opSearchEntry     = 4
+	opSearchDone      = 5
+	opExtendedRequest = 23
+	opExtendedReply   = 24
+)
+
+// Result codes sent back.
+const (
+	resultSuccess            = 0
+	resultOperationsError    = 1
+	resultProtocolError      = 2
+	resultNoSuchObject       = 32
+	resultInvalidCredentials = 49
+	resultInsufficientAccess = 50
+	resultUnwillingToPerform = 53
+)
+
+// Filters of the searches, as context tags.
+const (
+	filterAnd           = 0
+	filterOr            = 1
+	filterNot           = 2
+	filterEqualityMatch = 3
+	filterSubstrings    = 4
+	filterPresent       = 7
+	substringsInitial   = 0
+	substringsAny       = 1
+	substringsFinal     = 2
+)
+
+// Scopes of the searches.
+const (
+	scopeBaseObject   = 0
+	scopeSingleLevel  = 1
+	scopeWholeSubtree = 2
+)
+
+const (
+	startTLSOID       = "1.3.6.1.4.1.1466.20037"
+	passwordAttribute = "userpassword"
+)
+
+// Entry of the directory, with its attributes named as they were added.
+type entry struct {
+	dn         string
+	attributes map[string][]string
+}
+
+// Values of an attribute, whatever the casing of its name.
+func (e entry) lookup(name string) []string {
+	for attribute, values := range e.attributes {
+		if strings.EqualFold(attribute, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+// Directory served over TCP, on ldap:// with StartTLS or on ldaps://.
+type Server struct {
+	// Searches without binding first are refused unless allowed.
+	AllowAnonymous bool
+
+	listener  net.Listener
+	tlsConfig *tls.Config
+	rootCAs   *x509.CertPool
+	scheme    string
+
+	mu      sync.Mutex
+	entries map[string]entry
+	binds   map[string]int
+	conns   map[net.Conn]bool
+	wg      sync.WaitGroup
+}
+
+// Starts a directory on ldap://, which can be upgraded with StartTLS. It must be closed once done.
+func New() (*Server, error) {
+	return start("ldap")
+}
+
+// Starts a directory on ldaps://. It must be closed once done.
+func NewTLS() (*Server, error) {
+	return start("ldaps")
+}
+
+func start(scheme string) (*Server, error) {
+	tlsConfig, rootCAs, err := selfSignedTLS()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	if scheme == "ldaps" {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	s := &Server{
+		listener:  listener,
+		tlsConfig: tlsConfig,
+		rootCAs:   rootCAs,
+		scheme:    scheme,
+		entries:   map[string]entry{},
+		binds:     map[string]int{},
+		conns:     map[net.Conn]bool{},
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Stops the directory, closing the open connections.
+func (s *Server) Close() {
+	s.listener.Close()
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// URL to connect to the directory.
+func (s *Server) URL() string {
+	return s.scheme + "://" + s.listener.Addr().String()
+}
+
+// CAs the certificate of the directory is verified with.
+func (s *Server) RootCAs() *x509.CertPool {
+	return s.rootCAs
+}
+
+// Adds or replaces an entry. Users bind with the userPassword attribute.
+func (s *Server) AddEntry(dn string, attributes map[string][]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := make(map[string][]string, len(attributes))
+	for name, values := range attributes {
+		copied[name] = append([]string{}, values...)
+	}
+	s.entries[normalizeDN(dn)] = entry{dn: dn, attributes: copied}
+}
+
+// Removes an entry, as when a user leaves.
+func (s *Server) DeleteEntry(dn string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, normalizeDN(dn))
+}
+
+// Times someone bound successfully as a DN.
+func (s *Server) Binds(dn string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.binds[normalizeDN(dn)]
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = true
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// Connection of a client, which can search once it binds as a DN.
+type session struct {
+	conn  net.Conn
+	bound bool
+}
+
+// Answers the requests of a connection until the client unbinds or goes away.
+func (s *Server) handle(conn net.Conn) {
+	sess := &session{conn: conn}
+	defer func() { sess.conn.Close() }()
+
+	for {
+		packet, err := ber.ReadPacket(sess.conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		id, ok := packet.Children[0].Value.(int64)
+		if !ok {
+			return
+		}
+
+		op := packet.Children[1]
+		switch op.Tag {
+		case opBindRequest:
+			code := s.bind(sess, op)
+			if sess.write(id, result(opBindResponse, code, "")) != nil {
+				return
+			}
+		case opUnbindRequest:
+			return
+		case opSearchRequest:
+			if s.search(sess, id, op) != nil {
+				return
+			}
+		case opExtendedRequest:
+			if s.extended(sess, id, op) != nil {
+				return
+			}
+		default:
+			// Other operations aren't needed by the clients of the directory.
+			return
+		}
+	}
+}
+
+// Binds with a simple password. An empty DN and password bind anonymously.
+func (s *Server) bind(sess *session, op *ber.Packet) int {
+	if len(op.Children) < 3 || op.Children[2].Tag != 0 {
+		return resultProtocolError
+	}
+	dn := stringValue(op.Children[1])
+	password := op.Children[2].Data.String()
+
+	sess.bound = false
+	if len(dn) == 0 && len(password) == 0 {
+		return resultSuccess
+	}
+	// Binds with a DN and no password are unauthenticated, and never log anyone in.
+	if len(password) == 0 {
+		return resultUnwillingToPerform
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, found := s.entries[normalizeDN(dn)]
+	if !found || !contains(e.lookup(passwordAttribute), password) {
+		return resultInvalidCredentials
+	}
+	sess.bound = true
+	s.binds[normalizeDN(dn)]++
+	return resultSuccess
+}
+
+// Sends the entries matching a search, followed by its result.
+func (s *Server) search(sess *session, id int64, op *ber.Packet) error {
+	if len(op.Children) < 8 {
+		return sess.write(id, result(opSearchDone, resultProtocolError, ""))
+	}
+	if !sess.bound && !s.AllowAnonymous {
+		return sess.write(id, result(opSearchDone, resultInsufficientAccess, "anonymous searches are not allowed"))
+	}
+
+	baseDN := normalizeDN(stringValue(op.Children[0]))
+	scope, _ := op.Children[1].Value.(int64)
+	filter := op.Children[6]
+	requested := map[string]bool{}
+	for _, attribute := range op.Children[7].Children {
+		requested[strings.ToLower(stringValue(attribute))] = true
+	}
+
+	s.mu.Lock()
+	if _, found := s.entries[baseDN]; !found && len(baseDN) > 0 {
+		s.mu.Unlock()
+		return sess.write(id, result(opSearchDone, resultNoSuchObject, ""))
+	}
+	matches := []*ber.Packet{}
+	for dn, e := range s.entries {
+		if !inScope(dn, baseDN, int(scope)) {
+			continue
+		}
+		matched, err := matchFilter(e, filter)
+		if err != nil {
+			s.mu.Unlock()
+			return sess.write(id, result(opSearchDone, resultOperationsError, err.Error()))
+		}
+		if matched {
+			matches = append(matches, searchEntry(e, requested))
+		}
+	}
+	s.mu.Unlock()
+
+	for _, match := range matches {
+		if err := sess.write(id, match); err != nil {
+			return err
+		}
+	}
+	return sess.write(id, result(opSearchDone, resultSuccess, ""))
+}
+
+// Answers StartTLS, upgrading the connection, and refuses any other extended operation.
+func (s *Server) extended(sess *session, id int64, op *ber.Packet) error {
+	if len(op.Children) == 0 || op.Children[0].Data.String() != startTLSOID {
+		return sess.write(id, result(opExtendedReply, resultProtocolError, "unsupported extended operation"))
+	}
+	if _, ok := sess.conn.(*tls.Conn); ok {
+		return sess.write(id, result(opExtendedReply, resultOperationsError, "already encrypted"))
+	}
+
+	if err := sess.write(id, result(opExtendedReply, resultSuccess, "")); err != nil {
+		return err
+	}
+	conn := tls.Server(sess.conn, s.tlsConfig)
+	if err := conn.Handshake(); err != nil {
+		return err
+	}
+	sess.conn = conn
+	return nil
+}
+
+// Writes a response to a request.
+func (sess *session) write(id int64, op *ber.Packet) error {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "MessageID"))
+	packet.AppendChild(op)
+	_, err := sess.conn.Write(packet.Bytes())
+	return err
+}
+
+// Result of an operation.
+func result(tag ber.Tag, code int, message string) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "resultCode"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, message, "diagnosticMessage"))
+	return op
+}
+
+// Entry found by a search, with the requested attributes, or all of them when none are.
+// Passwords are never sent.
+func searchEntry(e entry, requested map[string]bool) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, opSearchEntry, nil, "Search Result Entry")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "objectName"))
+
+	attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attributes")
+	for name, values := range e.attributes {
+		lowered := strings.ToLower(name)
+		if lowered == passwordAttribute || (len(requested) > 0 && !requested["*"] && !requested[lowered]) {
+			continue
+		}
+		attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attribute")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "vals")
+		for _, value := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "value"))
+		}
+		attribute.AppendChild(set)
+		attributes.AppendChild(attribute)
+	}
+	op.AppendChild(attributes)
+	return op
+}
+
+// Returns if an entry matches a search filter. Values are compared ignoring their case.
+func matchFilter(e entry, filter *ber.Packet) (bool, error) {
+	switch filter.Tag {
+	case filterAnd:
+		for _, child := range filter.Children {
+			matched, err := matchFilter(e, child)
+			if err != nil || !matched {
+				return false, err
+			}
+		}
+		return true, nil
+	case filterOr:
+		for _, child := range filter.Children {
+			matched, err := matchFilter(e, child)
+			if err != nil || matched {
+				return matched, err
+			}
+		}
+		return false, nil
+	case filterNot:
+		if len(filter.Children) != 1 {
+			return false, errors.New("invalid not filter")
+		}
+		matched, err := matchFilter(e, filter.Children[0])
+		return !matched, err
+	case filterEqualityMatch:
+		if len(filter.Children) != 2 {
+			return false, errors.New("invalid equality filter")
+		}
+		values := e.values(stringValue(filter.Children[0]))
+		for _, value := range values {
+			if strings.EqualFold(value, stringValue(filter.Children[1])) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case filterSubstrings:
+		if len(filter.Children) != 2 {
+			return false, errors.New("invalid substrings filter")
+		}
+		for _, value := range e.values(stringValue(filter.Children[0])) {
+			if matchSubstrings(strings.ToLower(value), filter.Children[1].Children) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case filterPresent:
+		return len(e.values(filter.Data.String())) > 0, nil
+	}
+	return false, errors.New("unsupported filter")
+}
+
+// Returns if a value has the initial, any and final parts of a substrings filter, in order.
+func matchSubstrings(value string, parts []*ber.Packet) bool {
+	for _, part := range parts {
+		substring := strings.ToLower(part.Data.String())
+		switch part.Tag {
+		case substringsInitial:
+			if !strings.HasPrefix(value, substring) {
+				return false
+			}
+			value = value[len(substring):]
+		case substringsAny:
+			index := strings.Index(value, substring)
+			if index < 0 {
+				return false
+			}
+			value = value[index+len(substring):]
+		case substringsFinal:
+			if !strings.HasSuffix(value, substring) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Values of an attribute of an entry. The objectClass is implied by every entry being present.
+func (e entry) values(name string) []string {
+	name = strings.ToLower(name)
+	if name == passwordAttribute {
+		return nil
+	}
+	if name == "objectclass" && len(e.lookup(name)) == 0 {
+		return []string{"top"}
+	}
+	return e.lookup(name)
+}
+
+// Returns if an entry is within the scope of a search.
+func inScope(dn string, baseDN string, scope int) bool {
+	switch scope {
+	case scopeBaseObject:
+		return dn == baseDN
+	case scopeSingleLevel:
+		parent := ""
+		if index := strings.Index(dn, ","); index >= 0 {
+			parent = dn[index+1:]
+		}
+		return parent == baseDN
+	case scopeWholeSubtree:
+		return len(baseDN) == 0 || dn == baseDN || strings.HasSuffix(dn, ","+baseDN)
+	}
+	return false
+}
+
+// Lowercases a DN and removes the spaces around its components, so equal DNs compare equal.
+func normalizeDN(dn string) string {
+	components := strings.Split(dn, ",")
+	for i, component := range components {
+		components[i] = strings.ToLower(strings.TrimSpace(component))
+	}
+	return strings.Join(components, ",")
+}
+
+func stringValue(packet *ber.Packet) string {
+	if value, ok := packet.Value.(string); ok {
+		return value
+	}
+	return packet.Data.String()
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Creates a self-signed certificate for 127.0.0.1 and localhost, returning the server
+// configuration and the pool the clients verify it with.
+func selfSignedTLS() (*tls.Config, *x509.CertPool, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "mockldap"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:              []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	config := &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}},
+		MinVersion:   tls.VersionTLS12,
+	}
+	return config, pool, nil
+}
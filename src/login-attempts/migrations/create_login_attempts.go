@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Creates the login attempts table
+func CreateLoginAttemptsTable(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS login_attempts(
+			attempt_key varchar(512) NOT NULL,
+			failed_attempts integer NOT NULL DEFAULT 0,
+			last_failed_at timestamptz NOT NULL DEFAULT (now()),
+			locked_until timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (attempt_key)
+		);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewCreateLoginAttemptsMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "create-login-attempts-table",
+		Up:   CreateLoginAttemptsTable,
+	}
+}
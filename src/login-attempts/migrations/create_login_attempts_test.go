@@ -0,0 +1,94 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateLoginAttempts_FailExec(t *testing.T) {
+	migration := NewCreateLoginAttemptsMigration()
+	assert.Equal(t, migration.Name, "create-login-attempts-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS login_attempts(
+			attempt_key varchar(512) NOT NULL,
+			failed_attempts integer NOT NULL DEFAULT 0,
+			last_failed_at timestamptz NOT NULL DEFAULT (now()),
+			locked_until timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (attempt_key)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestCreateLoginAttempts_TimeoutReached(t *testing.T) {
+	migration := NewCreateLoginAttemptsMigration()
+	assert.Equal(t, migration.Name, "create-login-attempts-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS login_attempts(
+			attempt_key varchar(512) NOT NULL,
+			failed_attempts integer NOT NULL DEFAULT 0,
+			last_failed_at timestamptz NOT NULL DEFAULT (now()),
+			locked_until timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (attempt_key)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestCreateLoginAttempts_Success(t *testing.T) {
+	migration := NewCreateLoginAttemptsMigration()
+	assert.Equal(t, migration.Name, "create-login-attempts-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS login_attempts(
+			attempt_key varchar(512) NOT NULL,
+			failed_attempts integer NOT NULL DEFAULT 0,
+			last_failed_at timestamptz NOT NULL DEFAULT (now()),
+			locked_until timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (attempt_key)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(50 * time.Millisecond)).
+		WillReturnResult(sqlmock.NewResult(1, 1)).
+		WillReturnError(nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"context"
+)
+
+// Deletes the failed attempts (and lockout) of a key
+func (r PostgresRepository) Delete(ctx context.Context, key string) error {
+	query := `
+		DELETE FROM login_attempts
+		WHERE attempt_key = $1
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, key)
+	return err
+}
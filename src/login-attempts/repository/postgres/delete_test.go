@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelete_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		DELETE FROM login_attempts
+		WHERE attempt_key = $1
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	repo := PostgresRepository{db}
+	err = repo.Delete(context.TODO(), "username:admin")
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestDelete_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		DELETE FROM login_attempts
+		WHERE attempt_key = $1
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs("username:admin").
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx := context.TODO()
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	repo := PostgresRepository{db}
+	err = repo.Delete(ctx, "username:admin")
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestDelete_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		DELETE FROM login_attempts
+		WHERE attempt_key = $1
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs("username:admin").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := PostgresRepository{db}
+	err = repo.Delete(context.TODO(), "username:admin")
+	assert.Nil(t, err)
+}
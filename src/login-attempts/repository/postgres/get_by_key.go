@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets the failed login attempts of a key
+func (r PostgresRepository) GetByKey(ctx context.Context, key string) (domain.LoginAttempt, error) {
+	query := `
+		SELECT attempt_key, failed_attempts, last_failed_at, locked_until
+		FROM login_attempts
+		WHERE attempt_key = $1
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.LoginAttempt{}, err
+	}
+
+	row := stmt.QueryRowContext(ctx, key)
+	return r.scanLoginAttemptRow(row)
+}
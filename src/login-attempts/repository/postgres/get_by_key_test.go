@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetByKey_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		SELECT attempt_key, failed_attempts, last_failed_at, locked_until
+		FROM login_attempts
+		WHERE attempt_key = $1
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	repo := PostgresRepository{db}
+	res, err := repo.GetByKey(context.TODO(), "username:admin")
+	assert.Equal(t, domain.LoginAttempt{}, res)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestGetByKey_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		SELECT attempt_key, failed_attempts, last_failed_at, locked_until
+		FROM login_attempts
+		WHERE attempt_key = $1
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs("username:admin").
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx := context.TODO()
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	repo := PostgresRepository{db}
+	res, err := repo.GetByKey(ctx, "username:admin")
+	assert.Equal(t, domain.LoginAttempt{}, res)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestGetByKey_Success(t *testing.T) {
+	lastFailedAt := time.Now()
+	lockedUntil := lastFailedAt.Add(time.Minute)
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	expectedResult := sqlmock.NewRows(
+		[]string{"attempt_key", "failed_attempts", "last_failed_at", "locked_until"},
+	).AddRow("username:admin", 3, lastFailedAt, lockedUntil)
+
+	query := `
+		SELECT attempt_key, failed_attempts, last_failed_at, locked_until
+		FROM login_attempts
+		WHERE attempt_key = $1
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs("username:admin").
+		WillReturnRows(expectedResult)
+
+	ctx := context.TODO()
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	repo := PostgresRepository{db}
+	res, err := repo.GetByKey(ctx, "username:admin")
+	assert.Equal(t, res, domain.LoginAttempt{
+		Key:            "username:admin",
+		FailedAttempts: 3,
+		LastFailedAt:   lastFailedAt,
+		LockedUntil:    lockedUntil,
+	})
+	assert.Nil(t, err)
+}
@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Adds a failed attempt to a key, in a single statement so that
+// concurrent replicas don't lose increments.
+// Failures that happened before the window start are forgotten.
+func (r PostgresRepository) IncrementFailures(ctx context.Context, key string, windowStart time.Time) (domain.LoginAttempt, error) {
+	query := `
+		INSERT INTO login_attempts(attempt_key, failed_attempts, last_failed_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (attempt_key) DO UPDATE
+		SET failed_attempts = CASE
+				WHEN login_attempts.last_failed_at < $3 THEN 1
+				ELSE login_attempts.failed_attempts + 1
+			END,
+			last_failed_at = EXCLUDED.last_failed_at
+		RETURNING attempt_key, failed_attempts, last_failed_at, locked_until
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.LoginAttempt{}, err
+	}
+
+	row := stmt.QueryRowContext(ctx, key, time.Now(), windowStart)
+	return r.scanLoginAttemptRow(row)
+}
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const incrementFailuresQuery = `
+		INSERT INTO login_attempts(attempt_key, failed_attempts, last_failed_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (attempt_key) DO UPDATE
+		SET failed_attempts = CASE
+				WHEN login_attempts.last_failed_at < $3 THEN 1
+				ELSE login_attempts.failed_attempts + 1
+			END,
+			last_failed_at = EXCLUDED.last_failed_at
+		RETURNING attempt_key, failed_attempts, last_failed_at, locked_until
+	`
+
+func TestIncrementFailures_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(incrementFailuresQuery)).WillReturnError(errors.New("boom"))
+
+	repo := PostgresRepository{db}
+	res, err := repo.IncrementFailures(context.TODO(), "ip:127.0.0.1", time.Now())
+	assert.Equal(t, domain.LoginAttempt{}, res)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestIncrementFailures_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	windowStart := time.Now().Add(-time.Hour)
+	mock.ExpectPrepare(regexp.QuoteMeta(incrementFailuresQuery)).
+		ExpectQuery().
+		WithArgs("ip:127.0.0.1", sqlmock.AnyArg(), windowStart).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx := context.TODO()
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	repo := PostgresRepository{db}
+	res, err := repo.IncrementFailures(ctx, "ip:127.0.0.1", windowStart)
+	assert.Equal(t, domain.LoginAttempt{}, res)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestIncrementFailures_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	windowStart := time.Now().Add(-time.Hour)
+	lastFailedAt := time.Now()
+	expectedResult := sqlmock.NewRows(
+		[]string{"attempt_key", "failed_attempts", "last_failed_at", "locked_until"},
+	).AddRow("ip:127.0.0.1", 2, lastFailedAt, windowStart)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(incrementFailuresQuery)).
+		ExpectQuery().
+		WithArgs("ip:127.0.0.1", sqlmock.AnyArg(), windowStart).
+		WillReturnRows(expectedResult)
+
+	ctx := context.TODO()
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	repo := PostgresRepository{db}
+	res, err := repo.IncrementFailures(ctx, "ip:127.0.0.1", windowStart)
+	assert.Equal(t, res, domain.LoginAttempt{
+		Key:            "ip:127.0.0.1",
+		FailedAttempts: 2,
+		LastFailedAt:   lastFailedAt,
+		LockedUntil:    windowStart,
+	})
+	assert.Nil(t, err)
+}
@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"context"
+	"time"
+)
+
+// Locks a key until a given time. Never shortens an existing lockout.
+func (r PostgresRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	query := `
+		UPDATE login_attempts
+		SET locked_until = GREATEST(locked_until, $1)
+		WHERE attempt_key = $2
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, until, key)
+	return err
+}
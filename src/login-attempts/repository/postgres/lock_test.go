@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLock_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		UPDATE login_attempts
+		SET locked_until = GREATEST(locked_until, $1)
+		WHERE attempt_key = $2
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	repo := PostgresRepository{db}
+	err = repo.Lock(context.TODO(), "username:admin", time.Now())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestLock_ExecFails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	until := time.Now().Add(time.Minute)
+	query := `
+		UPDATE login_attempts
+		SET locked_until = GREATEST(locked_until, $1)
+		WHERE attempt_key = $2
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs(until, "username:admin").
+		WillReturnError(errors.New("boom"))
+
+	repo := PostgresRepository{db}
+	err = repo.Lock(context.TODO(), "username:admin", until)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestLock_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	until := time.Now().Add(time.Minute)
+	query := `
+		UPDATE login_attempts
+		SET locked_until = GREATEST(locked_until, $1)
+		WHERE attempt_key = $2
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs(until, "username:admin").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := PostgresRepository{db}
+	err = repo.Lock(context.TODO(), "username:admin", until)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type PostgresRepository struct {
+	Db *sql.DB
+}
+
+func New(db *sql.DB) domain.LoginAttemptRepository {
+	return PostgresRepository{db}
+}
+
+// Scans a login attempt row
+func (r PostgresRepository) scanLoginAttemptRow(row *sql.Row) (domain.LoginAttempt, error) {
+	result := domain.LoginAttempt{}
+
+	err := row.Scan(
+		&result.Key,
+		&result.FailedAttempts,
+		&result.LastFailedAt,
+		&result.LockedUntil,
+	)
+	if err != nil {
+		return domain.LoginAttempt{}, err
+	}
+
+	return result, nil
+}
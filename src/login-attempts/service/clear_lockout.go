@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Clears the failed logins and lockout of a username and/or client IP.
+func (s DefaultLoginAttemptService) ClearLockout(ctx context.Context, username string, ip string) error {
+	if len(username) == 0 && len(ip) == 0 {
+		return domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	if len(username) > 0 {
+		if err := s.AttemptRepo.Delete(ctx, usernameKey(ctx, username)); err != nil {
+			return err
+		}
+	}
+
+	if len(ip) > 0 {
+		if err := s.AttemptRepo.Delete(ctx, ipKey(ctx, ip)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestClearLockout_InvalidInput(t *testing.T) {
+	err := newService(nil).ClearLockout(context.TODO(), "", "")
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestClearLockout_ErrorIfRepoFails(t *testing.T) {
+	attemptRepo := new(mocks.LoginAttemptRepository)
+	attemptRepo.On("Delete", mock.Anything, adminKey).
+		Once().Return(errors.New("boom"))
+
+	err := newService(attemptRepo).ClearLockout(context.TODO(), "admin", "10.0.0.1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	attemptRepo.AssertExpectations(t)
+}
+
+func TestClearLockout_Success(t *testing.T) {
+	attemptRepo := new(mocks.LoginAttemptRepository)
+	attemptRepo.On("Delete", mock.Anything, adminKey).
+		Once().Return(nil)
+	attemptRepo.On("Delete", mock.Anything, clientIPKey).
+		Once().Return(nil)
+
+	err := newService(attemptRepo).ClearLockout(context.TODO(), "admin", "10.0.0.1")
+	assert.Nil(t, err)
+	attemptRepo.AssertExpectations(t)
+}
+
+func TestClearLockout_OnlyIP(t *testing.T) {
+	attemptRepo := new(mocks.LoginAttemptRepository)
+	attemptRepo.On("Delete", mock.Anything, clientIPKey).
+		Once().Return(nil)
+
+	err := newService(attemptRepo).ClearLockout(context.TODO(), "", "10.0.0.1")
+	assert.Nil(t, err)
+	attemptRepo.AssertExpectations(t)
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Gets how long the login for a username/IP pair is locked for.
+// Returns zero if the login is allowed.
+func (s DefaultLoginAttemptService) GetRetryAfter(ctx context.Context, username string, ip string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	keys := []string{usernameKey(ctx, username)}
+	if len(ip) > 0 {
+		keys = append(keys, ipKey(ctx, ip))
+	}
+
+	var retryAfter time.Duration
+	for _, key := range keys {
+		attempt, err := s.AttemptRepo.GetByKey(ctx, key)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			s.Logger.Printf("error fetching login attempts of {%s}: %v\n", key, err)
+			return 0, err
+		}
+
+		if lockedFor := time.Until(attempt.LockedUntil); lockedFor > retryAfter {
+			retryAfter = lockedFor
+		}
+	}
+	return retryAfter, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetRetryAfter_ErrorIfRepoFails(t *testing.T) {
+	attemptRepo := new(mocks.LoginAttemptRepository)
+	attemptRepo.On("GetByKey", mock.Anything, adminKey).
+		Once().Return(domain.LoginAttempt{}, errors.New("boom"))
+
+	retryAfter, err := newService(attemptRepo).GetRetryAfter(context.TODO(), "admin", "10.0.0.1")
+	assert.Equal(t, time.Duration(0), retryAfter)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	attemptRepo.AssertExpectations(t)
+}
+
+func TestGetRetryAfter_NoAttempts(t *testing.T) {
+	attemptRepo := new(mocks.LoginAttemptRepository)
+	attemptRepo.On("GetByKey", mock.Anything, adminKey).
+		Once().Return(domain.LoginAttempt{}, sql.ErrNoRows)
+	attemptRepo.On("GetByKey", mock.Anything, clientIPKey).
+		Once().Return(domain.LoginAttempt{}, sql.ErrNoRows)
+
+	retryAfter, err := newService(attemptRepo).GetRetryAfter(context.TODO(), "admin", "10.0.0.1")
+	assert.Equal(t, time.Duration(0), retryAfter)
+	assert.Nil(t, err)
+	attemptRepo.AssertExpectations(t)
+}
+
+func TestGetRetryAfter_SkipsIPIfUnknown(t *testing.T) {
+	attemptRepo := new(mocks.LoginAttemptRepository)
+	attemptRepo.On("GetByKey", mock.Anything, adminKey).
+		Once().Return(domain.LoginAttempt{LockedUntil: time.Now().Add(-time.Minute)}, nil)
+
+	retryAfter, err := newService(attemptRepo).GetRetryAfter(context.TODO(), "admin", "")
+	assert.Equal(t, time.Duration(0), retryAfter)
+	assert.Nil(t, err)
+	attemptRepo.AssertExpectations(t)
+}
+
+func TestGetRetryAfter_ReturnsLongestLockout(t *testing.T) {
+	attemptRepo := new(mocks.LoginAttemptRepository)
+	attemptRepo.On("GetByKey", mock.Anything, adminKey).
+		Once().Return(domain.LoginAttempt{LockedUntil: time.Now().Add(time.Minute)}, nil)
+	attemptRepo.On("GetByKey", mock.Anything, clientIPKey).
+		Once().Return(domain.LoginAttempt{LockedUntil: time.Now().Add(time.Hour)}, nil)
+
+	retryAfter, err := newService(attemptRepo).GetRetryAfter(context.TODO(), "admin", "10.0.0.1")
+	assert.Greater(t, retryAfter, 59*time.Minute)
+	assert.LessOrEqual(t, retryAfter, time.Hour)
+	assert.Nil(t, err)
+	attemptRepo.AssertExpectations(t)
+}
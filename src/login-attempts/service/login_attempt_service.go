@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/helpers"
+)
+
+type DefaultLoginAttemptService struct {
+	Logger         *log.Logger
+	AttemptRepo    domain.LoginAttemptRepository
+	ContextTimeout time.Duration
+	Settings       domain.LoginThrottleSettings
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	attemptRepo domain.LoginAttemptRepository,
+	contextTimeout time.Duration,
+	settings domain.LoginThrottleSettings,
+) domain.LoginAttemptService {
+	return DefaultLoginAttemptService{logger, attemptRepo, contextTimeout, settings}
+}
+
+// Instantiation for tests
+func newService(attemptRepo domain.LoginAttemptRepository) DefaultLoginAttemptService {
+	return DefaultLoginAttemptService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		attemptRepo,
+		time.Duration(5 * time.Second),
+		domain.LoginThrottleSettings{
+			MaxFailedAttemptsPerUser: 3,
+			MaxFailedAttemptsPerIP:   10,
+			LockoutDuration:          time.Minute,
+			MaxLockoutDuration:       time.Hour,
+			FailureWindow:            24 * time.Hour,
+		},
+	}
+}
+
+// Key used to count the failures of a username within the organization of the context.
+// Normalized, so that changing its casing doesn't bypass the throttling.
+func usernameKey(ctx context.Context, username string) string {
+	return "username:" + domain.OrganizationFromContext(ctx).String() + ":" + helpers.NormalizeUsername(username)
+}
+
+// Key used to count the failures of a client IP within the organization of the context
+func ipKey(ctx context.Context, ip string) string {
+	return "ip:" + domain.OrganizationFromContext(ctx).String() + ":" + ip
+}
+
+// Gets the lockout for an amount of failed attempts.
+// Starts at the lockout duration once the threshold is reached and doubles
+// on each further failure, up to the max lockout duration.
+func (s DefaultLoginAttemptService) lockoutFor(failedAttempts int, threshold int) time.Duration {
+	if threshold <= 0 || failedAttempts < threshold {
+		return 0
+	}
+
+	lockout := s.Settings.LockoutDuration
+	for i := threshold; i < failedAttempts && lockout < s.Settings.MaxLockoutDuration; i++ {
+		lockout *= 2
+	}
+
+	if lockout > s.Settings.MaxLockoutDuration {
+		return s.Settings.MaxLockoutDuration
+	}
+	return lockout
+}
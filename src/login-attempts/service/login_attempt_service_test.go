@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Keys of the username and client IP used in the tests, in the default organization.
+var (
+	adminKey    = "username:" + domain.DefaultOrganizationID.String() + ":admin"
+	clientIPKey = "ip:" + domain.DefaultOrganizationID.String() + ":10.0.0.1"
+)
+
+func TestLockoutFor(t *testing.T) {
+	tests := []struct {
+		failedAttempts int
+		threshold      int
+		expected       time.Duration
+	}{
+		{0, 3, 0},
+		{2, 3, 0},
+		{3, 3, time.Minute},
+		{4, 3, 2 * time.Minute},
+		{6, 3, 8 * time.Minute},
+		{9, 3, time.Hour},
+		{1000, 3, time.Hour},
+		{10, 0, 0},
+	}
+
+	service := newService(nil)
+	for _, test := range tests {
+		assert.Equal(t, test.expected, service.lockoutFor(test.failedAttempts, test.threshold))
+	}
+}
+
+func TestKeys_ScopedToOrganization(t *testing.T) {
+	otherOrganization := uuid.New()
+	otherCtx := domain.WithOrganization(context.TODO(), otherOrganization)
+
+	assert.Equal(t, adminKey, usernameKey(context.TODO(), "Admin"))
+	assert.Equal(t, "username:"+otherOrganization.String()+":admin", usernameKey(otherCtx, "admin"))
+	assert.Equal(t, clientIPKey, ipKey(context.TODO(), "10.0.0.1"))
+	assert.Equal(t, "ip:"+otherOrganization.String()+":10.0.0.1", ipKey(otherCtx, "10.0.0.1"))
+}
+
+// The failures of a username in an organization don't lock the same username in another one.
+func TestRegisterFailure_SameUsernameInTwoOrganizations(t *testing.T) {
+	otherOrganization := uuid.New()
+	otherCtx := domain.WithOrganization(context.TODO(), otherOrganization)
+	otherKey := "username:" + otherOrganization.String() + ":admin"
+
+	attemptRepo := new(mocks.LoginAttemptRepository)
+	attemptRepo.On("IncrementFailures", mock.Anything, adminKey, mock.Anything).
+		Once().Return(domain.LoginAttempt{FailedAttempts: 3}, nil)
+	attemptRepo.On("Lock", mock.Anything, adminKey, mock.Anything).Once().Return(nil)
+	attemptRepo.On("GetByKey", mock.Anything, otherKey).Once().Return(domain.LoginAttempt{}, sql.ErrNoRows)
+
+	service := newService(attemptRepo)
+	retryAfter, err := service.RegisterFailure(context.TODO(), "admin", "")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Minute, retryAfter)
+
+	retryAfter, err = service.GetRetryAfter(otherCtx, "admin", "")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), retryAfter)
+	attemptRepo.AssertExpectations(t)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// Registers a failed login for a username/IP pair, locking them if
+// they reached their threshold. Returns how long the login is locked for.
+func (s DefaultLoginAttemptService) RegisterFailure(ctx context.Context, username string, ip string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	thresholds := map[string]int{usernameKey(ctx, username): s.Settings.MaxFailedAttemptsPerUser}
+	if len(ip) > 0 {
+		thresholds[ipKey(ctx, ip)] = s.Settings.MaxFailedAttemptsPerIP
+	}
+
+	now := time.Now()
+	var retryAfter time.Duration
+	for key, threshold := range thresholds {
+		attempt, err := s.AttemptRepo.IncrementFailures(ctx, key, now.Add(-s.Settings.FailureWindow))
+		if err != nil {
+			s.Logger.Printf("error registering failed login of {%s}: %v\n", key, err)
+			return 0, err
+		}
+
+		lockout := s.lockoutFor(attempt.FailedAttempts, threshold)
+		if lockout == 0 {
+			continue
+		}
+
+		if err = s.AttemptRepo.Lock(ctx, key, now.Add(lockout)); err != nil {
+			s.Logger.Printf("error locking login of {%s}: %v\n", key, err)
+			return 0, err
+		}
+		if lockout > retryAfter {
+			retryAfter = lockout
+		}
+	}
+	return retryAfter, nil
+}
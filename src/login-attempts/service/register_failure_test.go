@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRegisterFailure_ErrorIfIncrementFails(t *testing.T) {
+	attemptRepo := new(mocks.LoginAttemptRepository)
+	attemptRepo.On("IncrementFailures", mock.Anything, adminKey, mock.Anything).
+		Once().Return(domain.LoginAttempt{}, errors.New("boom"))
+
+	retryAfter, err := newService(attemptRepo).RegisterFailure(context.TODO(), "admin", "")
+	assert.Equal(t, time.Duration(0), retryAfter)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	attemptRepo.AssertExpectations(t)
+}
+
+func TestRegisterFailure_BelowThreshold(t *testing.T) {
+	attemptRepo := new(mocks.LoginAttemptRepository)
+	attemptRepo.On("IncrementFailures", mock.Anything, adminKey, mock.MatchedBy(func(windowStart time.Time) bool {
+		return windowStart.Before(time.Now().Add(-23 * time.Hour))
+	})).Once().Return(domain.LoginAttempt{FailedAttempts: 2}, nil)
+	attemptRepo.On("IncrementFailures", mock.Anything, clientIPKey, mock.Anything).
+		Once().Return(domain.LoginAttempt{FailedAttempts: 9}, nil)
+
+	retryAfter, err := newService(attemptRepo).RegisterFailure(context.TODO(), "admin", "10.0.0.1")
+	assert.Equal(t, time.Duration(0), retryAfter)
+	assert.Nil(t, err)
+	attemptRepo.AssertExpectations(t)
+}
+
+func TestRegisterFailure_ErrorIfLockFails(t *testing.T) {
+	attemptRepo := new(mocks.LoginAttemptRepository)
+	attemptRepo.On("IncrementFailures", mock.Anything, adminKey, mock.Anything).
+		Once().Return(domain.LoginAttempt{FailedAttempts: 3}, nil)
+	attemptRepo.On("Lock", mock.Anything, adminKey, mock.Anything).
+		Once().Return(errors.New("boom"))
+
+	retryAfter, err := newService(attemptRepo).RegisterFailure(context.TODO(), "admin", "")
+	assert.Equal(t, time.Duration(0), retryAfter)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	attemptRepo.AssertExpectations(t)
+}
+
+func TestRegisterFailure_LocksWithExponentialBackoff(t *testing.T) {
+	attemptRepo := new(mocks.LoginAttemptRepository)
+	attemptRepo.On("IncrementFailures", mock.Anything, adminKey, mock.Anything).
+		Once().Return(domain.LoginAttempt{FailedAttempts: 5}, nil)
+	attemptRepo.On("IncrementFailures", mock.Anything, clientIPKey, mock.Anything).
+		Once().Return(domain.LoginAttempt{FailedAttempts: 10}, nil)
+	attemptRepo.On("Lock", mock.Anything, adminKey, mock.MatchedBy(func(until time.Time) bool {
+		lockedFor := time.Until(until)
+		return lockedFor > 3*time.Minute && lockedFor <= 4*time.Minute
+	})).Once().Return(nil)
+	attemptRepo.On("Lock", mock.Anything, clientIPKey, mock.MatchedBy(func(until time.Time) bool {
+		lockedFor := time.Until(until)
+		return lockedFor > 0 && lockedFor <= time.Minute
+	})).Once().Return(nil)
+
+	retryAfter, err := newService(attemptRepo).RegisterFailure(context.TODO(), "admin", "10.0.0.1")
+	assert.Equal(t, 4*time.Minute, retryAfter)
+	assert.Nil(t, err)
+	attemptRepo.AssertExpectations(t)
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+)
+
+// Resets the failed logins of a username after a successful login.
+// The IP counter is kept, so that one valid account can't be used to
+// reset the throttling of a client guessing other accounts.
+func (s DefaultLoginAttemptService) RegisterSuccess(ctx context.Context, username string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	return s.AttemptRepo.Delete(ctx, usernameKey(ctx, username))
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRegisterSuccess_ErrorIfRepoFails(t *testing.T) {
+	attemptRepo := new(mocks.LoginAttemptRepository)
+	attemptRepo.On("Delete", mock.Anything, adminKey).
+		Once().Return(errors.New("boom"))
+
+	err := newService(attemptRepo).RegisterSuccess(context.TODO(), "admin")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	attemptRepo.AssertExpectations(t)
+}
+
+func TestRegisterSuccess_Success(t *testing.T) {
+	attemptRepo := new(mocks.LoginAttemptRepository)
+	attemptRepo.On("Delete", mock.Anything, adminKey).
+		Once().Return(nil)
+
+	err := newService(attemptRepo).RegisterSuccess(context.TODO(), "admin")
+	assert.Nil(t, err)
+	attemptRepo.AssertExpectations(t)
+}
+
+func TestRegisterSuccess_UsesNormalizedUsername(t *testing.T) {
+	attemptRepo := new(mocks.LoginAttemptRepository)
+	attemptRepo.On("Delete", mock.Anything, adminKey).
+		Once().Return(nil)
+
+	err := newService(attemptRepo).RegisterSuccess(context.TODO(), " Admin")
+	assert.Nil(t, err)
+	attemptRepo.AssertExpectations(t)
+}
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Creates the table with the history of the logins, refreshes and logouts of the users.
+// Failed logins with unknown usernames are kept without a user.
+func CreateLoginEventsTable(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS login_events(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			organization_id uuid NOT NULL REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			user_id uuid NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			username varchar(255) NOT NULL DEFAULT '',
+			type varchar(20) NOT NULL,
+			success boolean NOT NULL,
+			reason varchar(255) NOT NULL DEFAULT '',
+			ip varchar(64) NOT NULL DEFAULT '',
+			user_agent varchar(512) NOT NULL DEFAULT '',
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+		CREATE INDEX IF NOT EXISTS login_events_user_id_created_at_idx ON login_events (user_id, created_at DESC);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewCreateLoginEventsTableMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "create-login-events-table",
+		Up:   CreateLoginEventsTable,
+	}
+}
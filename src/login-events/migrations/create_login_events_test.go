@@ -0,0 +1,110 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateLoginEventsTable_FailExec(t *testing.T) {
+	migration := NewCreateLoginEventsTableMigration()
+	assert.Equal(t, migration.Name, "create-login-events-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS login_events(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			organization_id uuid NOT NULL REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			user_id uuid NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			username varchar(255) NOT NULL DEFAULT '',
+			type varchar(20) NOT NULL,
+			success boolean NOT NULL,
+			reason varchar(255) NOT NULL DEFAULT '',
+			ip varchar(64) NOT NULL DEFAULT '',
+			user_agent varchar(512) NOT NULL DEFAULT '',
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+		CREATE INDEX IF NOT EXISTS login_events_user_id_created_at_idx ON login_events (user_id, created_at DESC);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestCreateLoginEventsTable_TimeoutReached(t *testing.T) {
+	migration := NewCreateLoginEventsTableMigration()
+	assert.Equal(t, migration.Name, "create-login-events-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS login_events(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			organization_id uuid NOT NULL REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			user_id uuid NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			username varchar(255) NOT NULL DEFAULT '',
+			type varchar(20) NOT NULL,
+			success boolean NOT NULL,
+			reason varchar(255) NOT NULL DEFAULT '',
+			ip varchar(64) NOT NULL DEFAULT '',
+			user_agent varchar(512) NOT NULL DEFAULT '',
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+		CREATE INDEX IF NOT EXISTS login_events_user_id_created_at_idx ON login_events (user_id, created_at DESC);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestCreateLoginEventsTable_Success(t *testing.T) {
+	migration := NewCreateLoginEventsTableMigration()
+	assert.Equal(t, migration.Name, "create-login-events-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS login_events(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			organization_id uuid NOT NULL REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			user_id uuid NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			username varchar(255) NOT NULL DEFAULT '',
+			type varchar(20) NOT NULL,
+			success boolean NOT NULL,
+			reason varchar(255) NOT NULL DEFAULT '',
+			ip varchar(64) NOT NULL DEFAULT '',
+			user_agent varchar(512) NOT NULL DEFAULT '',
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+		CREATE INDEX IF NOT EXISTS login_events_user_id_created_at_idx ON login_events (user_id, created_at DESC);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
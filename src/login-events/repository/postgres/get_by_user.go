@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets the events of a user in a paginated manner, newest first.
+// The dates of the filter are inclusive for the oldest and exclusive for the newest.
+func (r PostgresRepository) GetByUser(ctx context.Context, userID uuid.UUID, filter domain.LoginEventFilter) ([]domain.LoginEvent, error) {
+	result := make([]domain.LoginEvent, 0)
+
+	query := `
+		SELECT id, user_id, username, type, success, reason, ip, user_agent, created_at
+		FROM login_events
+		WHERE user_id = $1 AND organization_id = $2
+			AND ($3::timestamptz IS NULL OR created_at >= $3)
+			AND ($4::timestamptz IS NULL OR created_at < $4)
+		ORDER BY created_at DESC, id
+		LIMIT $5 OFFSET $6
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return result, err
+	}
+
+	rows, err := stmt.QueryContext(
+		ctx,
+		userID,
+		domain.OrganizationFromContext(ctx),
+		nullTime(filter.From),
+		nullTime(filter.To),
+		filter.Limit,
+		filter.Offset,
+	)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event, err := r.scanEventRow(rows)
+		if err != nil {
+			return make([]domain.LoginEvent, 0), err
+		}
+		result = append(result, event)
+	}
+	return result, rows.Err()
+}
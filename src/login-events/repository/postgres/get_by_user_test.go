@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const getByUserQuery = `
+		SELECT id, user_id, username, type, success, reason, ip, user_agent, created_at
+		FROM login_events
+		WHERE user_id = $1 AND organization_id = $2
+			AND ($3::timestamptz IS NULL OR created_at >= $3)
+			AND ($4::timestamptz IS NULL OR created_at < $4)
+		ORDER BY created_at DESC, id
+		LIMIT $5 OFFSET $6
+	`
+
+func TestGetByUser_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getByUserQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).GetByUser(context.TODO(), uuid.New(), domain.LoginEventFilter{Limit: 10})
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestGetByUser_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(getByUserQuery)).
+		ExpectQuery().
+		WithArgs(userID, domain.DefaultOrganizationID, sql.NullTime{}, sql.NullTime{}, 10, 0).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).GetByUser(ctx, userID, domain.LoginEventFilter{Limit: 10})
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestGetByUser_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	from := time.Now().Add(-24 * time.Hour)
+	newest := domain.LoginEvent{ID: uuid.New(), UserID: userID, Username: "alice", Type: domain.LoginEventLogout, Success: true, CreatedAt: time.Now()}
+	oldest := domain.LoginEvent{ID: uuid.New(), UserID: userID, Username: "alice", Type: domain.LoginEventLogin, Reason: "invalid credentials", IP: "203.0.113.7", CreatedAt: time.Now().Add(-time.Hour)}
+	mock.ExpectPrepare(regexp.QuoteMeta(getByUserQuery)).
+		ExpectQuery().
+		WithArgs(userID, domain.DefaultOrganizationID, sql.NullTime{Time: from, Valid: true}, sql.NullTime{}, 10, 20).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(newest.ID, userID, "alice", "logout", true, "", "", "", newest.CreatedAt).
+			AddRow(oldest.ID, userID, "alice", "login", false, "invalid credentials", "203.0.113.7", "", oldest.CreatedAt))
+
+	res, err := New(db).GetByUser(context.TODO(), userID, domain.LoginEventFilter{From: from, Limit: 10, Offset: 20})
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.LoginEvent{newest, oldest}, res)
+}
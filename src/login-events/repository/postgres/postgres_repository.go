@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type PostgresRepository struct {
+	Db *sql.DB
+}
+
+func New(db *sql.DB) domain.LoginEventRepository {
+	return PostgresRepository{db}
+}
+
+// Row of a single or multi row query
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans a login event row
+func (r PostgresRepository) scanEventRow(row rowScanner) (domain.LoginEvent, error) {
+	result := domain.LoginEvent{}
+	var userID uuid.NullUUID
+
+	err := row.Scan(
+		&result.ID,
+		&userID,
+		&result.Username,
+		&result.Type,
+		&result.Success,
+		&result.Reason,
+		&result.IP,
+		&result.UserAgent,
+		&result.CreatedAt,
+	)
+	if err != nil {
+		return domain.LoginEvent{}, err
+	}
+
+	result.UserID = userID.UUID
+	return result, nil
+}
+
+// Gets a nullable time, null when the time is zero.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
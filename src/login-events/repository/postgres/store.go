@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Stores an event in the organization of the context.
+func (r PostgresRepository) Store(ctx context.Context, event domain.LoginEvent) (domain.LoginEvent, error) {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+
+	query := `
+		INSERT INTO login_events (id, organization_id, user_id, username, type, success, reason, ip, user_agent, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, user_id, username, type, success, reason, ip, user_agent, created_at
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.LoginEvent{}, err
+	}
+
+	row := stmt.QueryRowContext(
+		ctx,
+		event.ID,
+		domain.OrganizationFromContext(ctx),
+		uuid.NullUUID{UUID: event.UserID, Valid: event.UserID != uuid.Nil},
+		event.Username,
+		event.Type,
+		event.Success,
+		event.Reason,
+		event.IP,
+		event.UserAgent,
+		event.CreatedAt,
+	)
+	return r.scanEventRow(row)
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const storeQuery = `
+		INSERT INTO login_events (id, organization_id, user_id, username, type, success, reason, ip, user_agent, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, user_id, username, type, success, reason, ip, user_agent, created_at
+	`
+
+var eventColumns = []string{"id", "user_id", "username", "type", "success", "reason", "ip", "user_agent", "created_at"}
+
+func TestStore_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(storeQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).Store(context.TODO(), domain.LoginEvent{Type: domain.LoginEventLogin})
+	assert.Equal(t, err.Error(), "boom")
+	assert.Equal(t, domain.LoginEvent{}, res)
+}
+
+func TestStore_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(storeQuery)).
+		ExpectQuery().
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).Store(ctx, domain.LoginEvent{Type: domain.LoginEventLogin})
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Equal(t, domain.LoginEvent{}, res)
+}
+
+func TestStore_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	event := domain.LoginEvent{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Username:  "alice",
+		Type:      domain.LoginEventLogin,
+		Success:   true,
+		IP:        "203.0.113.7",
+		UserAgent: "grpc-go/1.56.3",
+		CreatedAt: time.Now(),
+	}
+	mock.ExpectPrepare(regexp.QuoteMeta(storeQuery)).
+		ExpectQuery().
+		WithArgs(
+			event.ID,
+			domain.DefaultOrganizationID,
+			uuid.NullUUID{UUID: event.UserID, Valid: true},
+			"alice",
+			domain.LoginEventLogin,
+			true,
+			"",
+			"203.0.113.7",
+			"grpc-go/1.56.3",
+			event.CreatedAt,
+		).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(event.ID, event.UserID, "alice", "login", true, "", "203.0.113.7", "grpc-go/1.56.3", event.CreatedAt))
+
+	res, err := New(db).Store(context.TODO(), event)
+	assert.Nil(t, err)
+	assert.Equal(t, event, res)
+}
+
+func TestStore_UnknownUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	event := domain.LoginEvent{
+		ID:        uuid.New(),
+		Username:  "nobody",
+		Type:      domain.LoginEventLogin,
+		Reason:    "invalid credentials",
+		CreatedAt: time.Now(),
+	}
+	mock.ExpectPrepare(regexp.QuoteMeta(storeQuery)).
+		ExpectQuery().
+		WithArgs(
+			event.ID,
+			domain.DefaultOrganizationID,
+			uuid.NullUUID{},
+			"nobody",
+			domain.LoginEventLogin,
+			false,
+			"invalid credentials",
+			"",
+			"",
+			event.CreatedAt,
+		).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(event.ID, nil, "nobody", "login", false, "invalid credentials", "", "", event.CreatedAt))
+
+	res, err := New(db).Store(context.TODO(), event)
+	assert.Nil(t, err)
+	assert.Equal(t, event, res)
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Pagination of the login history.
+const (
+	DefaultHistoryLimit = 20
+	MaxHistoryLimit     = 100
+)
+
+// Gets the login history of a user, newest first.
+// Users of other organizations are not found.
+func (s DefaultLoginEventService) GetHistory(ctx context.Context, userID uuid.UUID, filter domain.LoginEventFilter) ([]domain.LoginEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	if filter.Limit < 0 || filter.Limit > MaxHistoryLimit || filter.Offset < 0 {
+		return nil, domain.ErrBadParamInput
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, domain.ErrBadParamInput
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultHistoryLimit
+	}
+
+	_, err := s.UserRepo.GetByUUID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.LoginEventRepo.GetByUser(ctx, userID, filter)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetHistory_InvalidFilter(t *testing.T) {
+	now := time.Now()
+	filters := map[string]domain.LoginEventFilter{
+		"negative limit":  {Limit: -1},
+		"limit too big":   {Limit: MaxHistoryLimit + 1},
+		"negative offset": {Offset: -1},
+		"dates reversed":  {From: now, To: now.Add(-time.Hour)},
+	}
+
+	for name, filter := range filters {
+		t.Run(name, func(t *testing.T) {
+			service := newService(nil, nil)
+			res, err := service.GetHistory(context.TODO(), uuid.New(), filter)
+			assert.Nil(t, res)
+			assert.Equal(t, domain.ErrBadParamInput, err)
+		})
+	}
+}
+
+func TestGetHistory_UserNotFound(t *testing.T) {
+	userID := uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userID).Once().Return(nil, sql.ErrNoRows)
+
+	service := newService(nil, userRepo)
+	res, err := service.GetHistory(context.TODO(), userID, domain.LoginEventFilter{})
+	assert.Nil(t, res)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestGetHistory_RepositoryFails(t *testing.T) {
+	userID := uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userID).Once().Return(&domain.User{ID: userID}, nil)
+	loginEventRepo := new(mocks.LoginEventRepository)
+	loginEventRepo.On("GetByUser", mock.Anything, userID, mock.Anything).Once().Return(nil, errors.New("boom"))
+
+	service := newService(loginEventRepo, userRepo)
+	res, err := service.GetHistory(context.TODO(), userID, domain.LoginEventFilter{})
+	assert.Nil(t, res)
+	assert.Equal(t, errors.New("boom"), err)
+}
+
+func TestGetHistory_Success(t *testing.T) {
+	userID := uuid.New()
+	from := time.Now().Add(-time.Hour)
+	events := []domain.LoginEvent{{ID: uuid.New(), UserID: userID, Type: domain.LoginEventLogin, Success: true}}
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userID).Once().Return(&domain.User{ID: userID}, nil)
+	loginEventRepo := new(mocks.LoginEventRepository)
+	loginEventRepo.On("GetByUser", mock.Anything, userID, domain.LoginEventFilter{From: from, Limit: DefaultHistoryLimit, Offset: 5}).
+		Once().Return(events, nil)
+
+	service := newService(loginEventRepo, userRepo)
+	res, err := service.GetHistory(context.TODO(), userID, domain.LoginEventFilter{From: from, Offset: 5})
+	assert.Nil(t, err)
+	assert.Equal(t, events, res)
+	loginEventRepo.AssertExpectations(t)
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type DefaultLoginEventService struct {
+	Logger         *log.Logger
+	LoginEventRepo domain.LoginEventRepository
+	UserRepo       domain.UserRepository
+	ContextTimeout time.Duration
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	loginEventRepo domain.LoginEventRepository,
+	userRepo domain.UserRepository,
+	contextTimeout time.Duration,
+) domain.LoginEventService {
+	return DefaultLoginEventService{
+		logger,
+		loginEventRepo,
+		userRepo,
+		contextTimeout,
+	}
+}
+
+// Instantiation for tests
+func newService(loginEventRepo domain.LoginEventRepository, userRepo domain.UserRepository) DefaultLoginEventService {
+	return DefaultLoginEventService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		loginEventRepo,
+		userRepo,
+		time.Duration(5 * time.Second),
+	}
+}
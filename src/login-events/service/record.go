@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Limits of the columns of the client information, longer values are cut.
+const (
+	maxUsernameLength  = 255
+	maxReasonLength    = 255
+	maxIPLength        = 64
+	maxUserAgentLength = 512
+)
+
+// Records an event, looking up its user by the username when it isn't known.
+// Successful logins become the last login of the user.
+func (s DefaultLoginEventService) Record(ctx context.Context, event domain.LoginEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	event.Username = truncate(event.Username, maxUsernameLength)
+	event.Reason = truncate(event.Reason, maxReasonLength)
+	event.IP = truncate(event.IP, maxIPLength)
+	event.UserAgent = truncate(event.UserAgent, maxUserAgentLength)
+	event.CreatedAt = time.Now()
+
+	// Failed logins only know the username that was tried.
+	if event.UserID == uuid.Nil && len(event.Username) > 0 {
+		if user, err := s.UserRepo.GetByUsername(ctx, event.Username); err == nil {
+			event.UserID = user.ID
+			event.Username = user.Username
+		}
+	}
+
+	event, err := s.LoginEventRepo.Store(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	if event.Type != domain.LoginEventLogin || !event.Success || event.UserID == uuid.Nil {
+		return nil
+	}
+	return s.UserRepo.UpdateLastLogin(ctx, event.UserID, event.CreatedAt)
+}
+
+// Cuts a string to a number of bytes, without splitting its characters.
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	for length > 0 && !utf8.RuneStart(value[length]) {
+		length--
+	}
+	return value[:length]
+}
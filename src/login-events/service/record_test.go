@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Returns the event it is asked to store.
+func storedEvent(ctx context.Context, event domain.LoginEvent) domain.LoginEvent {
+	return event
+}
+
+func TestRecord_StoreFails(t *testing.T) {
+	userID := uuid.New()
+	loginEventRepo := new(mocks.LoginEventRepository)
+	loginEventRepo.On("Store", mock.Anything, mock.Anything).Once().Return(domain.LoginEvent{}, errors.New("boom"))
+	userRepo := new(mocks.UserRepository)
+
+	service := newService(loginEventRepo, userRepo)
+	err := service.Record(context.TODO(), domain.LoginEvent{UserID: userID, Type: domain.LoginEventLogin, Success: true})
+	assert.Equal(t, errors.New("boom"), err)
+	userRepo.AssertNotCalled(t, "UpdateLastLogin", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecord_SuccessfulLoginUpdatesTheLastLogin(t *testing.T) {
+	userID := uuid.New()
+	loginEventRepo := new(mocks.LoginEventRepository)
+	loginEventRepo.On("Store", mock.Anything, mock.MatchedBy(func(event domain.LoginEvent) bool {
+		return event.UserID == userID && event.IP == "203.0.113.7" && !event.CreatedAt.IsZero()
+	})).Once().Return(storedEvent, nil)
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("UpdateLastLogin", mock.Anything, userID, mock.AnythingOfType("time.Time")).Once().Return(nil)
+
+	service := newService(loginEventRepo, userRepo)
+	err := service.Record(context.TODO(), domain.LoginEvent{UserID: userID, Type: domain.LoginEventLogin, Success: true, IP: "203.0.113.7"})
+	assert.Nil(t, err)
+	loginEventRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func TestRecord_OtherEventsKeepTheLastLogin(t *testing.T) {
+	cases := map[string]domain.LoginEvent{
+		"failed login": {UserID: uuid.New(), Type: domain.LoginEventLogin, Reason: "invalid credentials"},
+		"refresh":      {UserID: uuid.New(), Type: domain.LoginEventRefresh, Success: true},
+		"logout":       {UserID: uuid.New(), Type: domain.LoginEventLogout, Success: true},
+	}
+
+	for name, event := range cases {
+		t.Run(name, func(t *testing.T) {
+			loginEventRepo := new(mocks.LoginEventRepository)
+			loginEventRepo.On("Store", mock.Anything, mock.Anything).Once().Return(storedEvent, nil)
+			userRepo := new(mocks.UserRepository)
+
+			service := newService(loginEventRepo, userRepo)
+			err := service.Record(context.TODO(), event)
+			assert.Nil(t, err)
+			loginEventRepo.AssertExpectations(t)
+			userRepo.AssertNotCalled(t, "UpdateLastLogin", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestRecord_FindsTheUserByUsername(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "Alice"}
+	loginEventRepo := new(mocks.LoginEventRepository)
+	loginEventRepo.On("Store", mock.Anything, mock.MatchedBy(func(event domain.LoginEvent) bool {
+		return event.UserID == user.ID && event.Username == "Alice"
+	})).Once().Return(storedEvent, nil)
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").Once().Return(user, nil)
+
+	service := newService(loginEventRepo, userRepo)
+	err := service.Record(context.TODO(), domain.LoginEvent{Username: "alice", Type: domain.LoginEventLogin, Reason: "invalid credentials"})
+	assert.Nil(t, err)
+	loginEventRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func TestRecord_UnknownUsername(t *testing.T) {
+	loginEventRepo := new(mocks.LoginEventRepository)
+	loginEventRepo.On("Store", mock.Anything, mock.MatchedBy(func(event domain.LoginEvent) bool {
+		return event.UserID == uuid.Nil && event.Username == "nobody"
+	})).Once().Return(storedEvent, nil)
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "nobody").Once().Return(nil, sql.ErrNoRows)
+
+	service := newService(loginEventRepo, userRepo)
+	err := service.Record(context.TODO(), domain.LoginEvent{Username: "nobody", Type: domain.LoginEventLogin, Reason: "invalid credentials"})
+	assert.Nil(t, err)
+	loginEventRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func TestRecord_TruncatesTheClientInformation(t *testing.T) {
+	loginEventRepo := new(mocks.LoginEventRepository)
+	loginEventRepo.On("Store", mock.Anything, mock.MatchedBy(func(event domain.LoginEvent) bool {
+		return len(event.UserAgent) == maxUserAgentLength && len(event.IP) == maxIPLength
+	})).Once().Return(storedEvent, nil)
+
+	service := newService(loginEventRepo, nil)
+	err := service.Record(context.TODO(), domain.LoginEvent{
+		UserID:    uuid.New(),
+		Type:      domain.LoginEventRefresh,
+		IP:        strings.Repeat("1", 100),
+		UserAgent: strings.Repeat("a", 1000),
+	})
+	assert.Nil(t, err)
+	loginEventRepo.AssertExpectations(t)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", truncate("short", 10))
+	assert.Equal(t, "trunc", truncate("truncated", 5))
+	// "é" takes two bytes, and isn't split.
+	assert.Equal(t, "caf", truncate("café", 4))
+}
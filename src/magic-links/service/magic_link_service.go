@@ -0,0 +1,94 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/mailer"
+)
+
+type DefaultMagicLinkService struct {
+	Logger              *log.Logger
+	UserRepo            domain.UserRepository
+	UserService         domain.UserService
+	OneTimeTokenService domain.OneTimeTokenService
+	Notifier            domain.Notifier
+	SendQueue           *mailer.Queue
+	ContextTimeout      time.Duration
+	Settings            domain.MagicLinkSettings
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	userRepo domain.UserRepository,
+	userService domain.UserService,
+	oneTimeTokenService domain.OneTimeTokenService,
+	notifier domain.Notifier,
+	sendQueue *mailer.Queue,
+	contextTimeout time.Duration,
+	settings domain.MagicLinkSettings,
+) domain.MagicLinkService {
+	return DefaultMagicLinkService{
+		logger,
+		userRepo,
+		userService,
+		oneTimeTokenService,
+		notifier,
+		sendQueue,
+		contextTimeout,
+		settings,
+	}
+}
+
+// Instantiation for tests
+func newService(
+	userRepo domain.UserRepository,
+	userService domain.UserService,
+	oneTimeTokenService domain.OneTimeTokenService,
+	notifier domain.Notifier,
+) DefaultMagicLinkService {
+	return DefaultMagicLinkService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		userRepo,
+		userService,
+		oneTimeTokenService,
+		notifier,
+		mailer.NewQueue(1, 10),
+		time.Duration(5 * time.Second),
+		domain.MagicLinkSettings{
+			LoginURL:             "https://example.com/magic-link",
+			TokenTTL:             10 * time.Minute,
+			MaxRequestsPerWindow: 3,
+			RequestWindow:        time.Hour,
+		},
+	}
+}
+
+// Builds the login link with the token.
+func (s DefaultMagicLinkService) loginLink(token string) (string, error) {
+	link, err := url.Parse(s.Settings.LoginURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String(), nil
+}
+
+// Hashes the nonce a link is bound to, which is stored with its token.
+// Links without a nonce aren't bound to any device.
+func hashNonce(nonce string) string {
+	if len(nonce) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(nonce))
+	return hex.EncodeToString(sum[:])
+}
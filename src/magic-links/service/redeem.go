@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets the user of a login link, with its role.
+// Links bound to a nonce are used up even when redeemed with another nonce,
+// so a link opened on the wrong device has to be requested again.
+func (s DefaultMagicLinkService) Redeem(ctx context.Context, token string, nonce string) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	link, err := s.OneTimeTokenService.Consume(ctx, domain.TokenPurposeMagicLink, token)
+	if err != nil {
+		return nil, err
+	}
+	// The user is looked up in the organization the link was issued in.
+	ctx = domain.WithOrganization(ctx, link.OrganizationID)
+
+	if len(link.Payload) > 0 && subtle.ConstantTimeCompare([]byte(link.Payload), []byte(hashNonce(nonce))) != 1 {
+		return nil, domain.ErrInvalidToken
+	}
+
+	user, err := s.UserService.GetUserByUUID(ctx, link.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive() {
+		return nil, domain.ErrUserNotActive
+	}
+	return user, nil
+}
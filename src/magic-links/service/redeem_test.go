@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRedeem_InvalidToken(t *testing.T) {
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposeMagicLink, "expired").
+		Once().Return(domain.OneTimeToken{}, domain.ErrInvalidToken)
+
+	user, err := newService(nil, nil, tokenService, nil).Redeem(context.TODO(), "expired", "")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+}
+
+func TestRedeem_WrongNonce(t *testing.T) {
+	cases := map[string]string{
+		"another device":  "other-nonce",
+		"without a nonce": "",
+	}
+
+	for name, nonce := range cases {
+		t.Run(name, func(t *testing.T) {
+			tokenService := new(mocks.OneTimeTokenService)
+			tokenService.On("Consume", mock.Anything, domain.TokenPurposeMagicLink, "the-token").
+				Once().Return(domain.OneTimeToken{UserID: uuid.New(), Payload: hashNonce("device-nonce")}, nil)
+			userService := new(mocks.UserService)
+
+			user, err := newService(nil, userService, tokenService, nil).Redeem(context.TODO(), "the-token", nonce)
+			assert.Nil(t, user)
+			assert.Equal(t, domain.ErrInvalidToken, err)
+			userService.AssertNotCalled(t, "GetUserByUUID", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestRedeem_DeletedUser(t *testing.T) {
+	userID := uuid.New()
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposeMagicLink, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID}, nil)
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(nil, sql.ErrNoRows)
+
+	user, err := newService(nil, userService, tokenService, nil).Redeem(context.TODO(), "the-token", "")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+}
+
+func TestRedeem_ScopedToOrganizationOfToken(t *testing.T) {
+	userID, organizationID := uuid.New(), uuid.New()
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposeMagicLink, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID, OrganizationID: organizationID}, nil)
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.MatchedBy(func(ctx context.Context) bool {
+		return domain.OrganizationFromContext(ctx) == organizationID
+	}), userID).Once().Return(nil, sql.ErrNoRows)
+
+	user, err := newService(nil, userService, tokenService, nil).Redeem(context.TODO(), "the-token", "")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	userService.AssertExpectations(t)
+}
+
+func TestRedeem_ErrorGettingUser(t *testing.T) {
+	userID := uuid.New()
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposeMagicLink, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID}, nil)
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(nil, errors.New("boom"))
+
+	user, err := newService(nil, userService, tokenService, nil).Redeem(context.TODO(), "the-token", "")
+	assert.Nil(t, user)
+	assert.Equal(t, "boom", err.Error())
+}
+
+func TestRedeem_InactiveUser(t *testing.T) {
+	userID := uuid.New()
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposeMagicLink, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID}, nil)
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.Anything, userID).
+		Once().Return(&domain.User{ID: userID, Status: domain.UserStatusSuspended}, nil)
+
+	user, err := newService(nil, userService, tokenService, nil).Redeem(context.TODO(), "the-token", "")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrUserNotActive, err)
+}
+
+func TestRedeem_Success(t *testing.T) {
+	cases := map[string]struct {
+		payload string
+		nonce   string
+	}{
+		"unbound link":            {"", ""},
+		"unbound link with nonce": {"", "any-nonce"},
+		"bound link":              {hashNonce("device-nonce"), "device-nonce"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			userID := uuid.New()
+			expected := &domain.User{ID: userID, Status: domain.UserStatusActive, Role: &domain.Role{RoleSlug: "user"}}
+			tokenService := new(mocks.OneTimeTokenService)
+			tokenService.On("Consume", mock.Anything, domain.TokenPurposeMagicLink, "the-token").
+				Once().Return(domain.OneTimeToken{UserID: userID, Payload: c.payload}, nil)
+			userService := new(mocks.UserService)
+			userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(expected, nil)
+
+			user, err := newService(nil, userService, tokenService, nil).Redeem(context.TODO(), "the-token", c.nonce)
+			assert.Nil(t, err)
+			assert.Equal(t, expected, user)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Sends a login link to a user, bound to the nonce when there is one.
+// Succeeds whether the user exists or not, so it can't be used to find accounts. The link is
+// sent in the background, so the request takes as long either way, and it's dropped when too
+// many are waiting to be sent.
+func (s DefaultMagicLinkService) Request(ctx context.Context, username string, nonce string) error {
+	username = strings.TrimSpace(username)
+	if len(username) == 0 {
+		return domain.ErrBadParamInput
+	}
+
+	// Keeps the organization of the request, without ending with it.
+	ctx = context.WithoutCancel(ctx)
+	queued := s.SendQueue.Enqueue(func() {
+		if err := s.sendMagicLink(ctx, username, nonce); err != nil {
+			s.Logger.Printf("error requesting a magic link: %v\n", err)
+		}
+	})
+	if !queued {
+		s.Logger.Println("too many sends waiting, dropping a magic link request")
+	}
+	return nil
+}
+
+// Issues a login token for a user, bound to the nonce when there is one, and sends the link to it.
+// Nothing is sent to unknown or inactive users, nor to users who requested too many links.
+func (s DefaultMagicLinkService) sendMagicLink(ctx context.Context, username string, nonce string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	user, err := s.UserRepo.GetByUsername(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.IsActive() {
+		s.Logger.Printf("magic link requested for inactive user {%s}\n", user.ID.String())
+		return nil
+	}
+
+	requests, err := s.OneTimeTokenService.CountIssuedSince(
+		ctx,
+		user.ID,
+		domain.TokenPurposeMagicLink,
+		time.Now().Add(-s.Settings.RequestWindow),
+	)
+	if err != nil {
+		return err
+	}
+	if requests >= s.Settings.MaxRequestsPerWindow {
+		s.Logger.Printf("too many magic links requested for user {%s}\n", user.ID.String())
+		return nil
+	}
+
+	token, err := s.OneTimeTokenService.Issue(ctx, user.ID, domain.TokenPurposeMagicLink, hashNonce(nonce), s.Settings.TokenTTL)
+	if err != nil {
+		return err
+	}
+
+	link, err := s.loginLink(token)
+	if err != nil {
+		return err
+	}
+
+	minutes := int(math.Ceil(s.Settings.TokenTTL.Minutes()))
+	err = s.Notifier.Notify(ctx, user, domain.Notification{
+		Subject: "Your login link",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nLog in to your account by opening the following link on the device you asked for it:\n%s\n\nThe link can only be used once and expires in %d minutes. If you didn't ask for it, you can ignore this message.\n",
+			user.Username, link, minutes,
+		),
+	})
+	if err != nil {
+		s.Logger.Printf("error notifying the magic link to user {%s}: %v\n", user.ID.String(), err)
+	}
+	return nil
+}
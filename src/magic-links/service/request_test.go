@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/plagioriginal/user-microservice/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequest_InvalidInput(t *testing.T) {
+	err := newService(nil, nil, nil, nil).Request(context.TODO(), "  ", "")
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestRequest_SendsInBackground(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com", Status: domain.UserStatusActive}
+	organizationID := uuid.New()
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").Once().Return(user, nil)
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("CountIssuedSince", mock.Anything, user.ID, domain.TokenPurposeMagicLink, mock.Anything).
+		Once().Return(0, nil)
+	tokenService.On("Issue", mock.Anything, user.ID, domain.TokenPurposeMagicLink, hashNonce("device-nonce"), 10*time.Minute).
+		Once().Return("the-token", nil)
+
+	sent := make(chan context.Context, 1)
+	notifier := new(mocks.Notifier)
+	notifier.On("Notify", mock.Anything, user, mock.Anything).Once().Return(nil).
+		Run(func(args mock.Arguments) { sent <- args.Get(0).(context.Context) })
+
+	// The request is answered even if it ends before the link is sent.
+	ctx, cancel := context.WithCancel(domain.WithOrganization(context.TODO(), organizationID))
+	err := newService(userRepo, nil, tokenService, notifier).Request(ctx, " alice ", "device-nonce")
+	cancel()
+	assert.Nil(t, err)
+
+	select {
+	case notifyCtx := <-sent:
+		assert.Equal(t, organizationID, domain.OrganizationFromContext(notifyCtx))
+	case <-time.After(time.Second):
+		t.Fatal("the magic link wasn't sent")
+	}
+	tokenService.AssertExpectations(t)
+}
+
+func TestRequest_SameForUnknownAndKnownUsers(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	service := newService(userRepo, nil, nil, nil)
+	service.SendQueue = mailer.NewQueue(0, 2)
+
+	// Both are only queued, before any lookup.
+	assert.Nil(t, service.Request(context.TODO(), "nobody", ""))
+	assert.Nil(t, service.Request(context.TODO(), "alice", ""))
+	userRepo.AssertNotCalled(t, "GetByUsername", mock.Anything, mock.Anything)
+
+	// And dropped the same way once too many are waiting.
+	assert.Nil(t, service.Request(context.TODO(), "alice", ""))
+	service.SendQueue.Close()
+}
+
+func TestSendMagicLink_UnknownUser(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "nobody").
+		Once().Return(nil, sql.ErrNoRows)
+
+	err := newService(userRepo, nil, nil, nil).sendMagicLink(context.TODO(), "nobody", "")
+	assert.Nil(t, err)
+	userRepo.AssertExpectations(t)
+}
+
+func TestSendMagicLink_ErrorGettingUser(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").
+		Once().Return(nil, errors.New("boom"))
+
+	err := newService(userRepo, nil, nil, nil).sendMagicLink(context.TODO(), "alice", "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+}
+
+func TestSendMagicLink_InactiveUser(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusSuspended}
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").
+		Once().Return(user, nil)
+	tokenService := new(mocks.OneTimeTokenService)
+
+	err := newService(userRepo, nil, tokenService, nil).sendMagicLink(context.TODO(), "alice", "")
+	assert.Nil(t, err)
+	tokenService.AssertNotCalled(t, "Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSendMagicLink_RateLimited(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").
+		Once().Return(user, nil)
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("CountIssuedSince", mock.Anything, user.ID, domain.TokenPurposeMagicLink, mock.MatchedBy(func(since time.Time) bool {
+		return since.Before(time.Now().Add(-59*time.Minute)) && since.After(time.Now().Add(-61*time.Minute))
+	})).Once().Return(3, nil)
+
+	// No link sent, but the caller can't tell.
+	err := newService(userRepo, nil, tokenService, nil).sendMagicLink(context.TODO(), "alice", "")
+	assert.Nil(t, err)
+	tokenService.AssertExpectations(t)
+	tokenService.AssertNotCalled(t, "Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSendMagicLink_ErrorIssuingToken(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").
+		Once().Return(user, nil)
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("CountIssuedSince", mock.Anything, user.ID, domain.TokenPurposeMagicLink, mock.Anything).
+		Once().Return(0, nil)
+	tokenService.On("Issue", mock.Anything, user.ID, domain.TokenPurposeMagicLink, "", 10*time.Minute).
+		Once().Return("", errors.New("boom"))
+
+	err := newService(userRepo, nil, tokenService, nil).sendMagicLink(context.TODO(), "alice", "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+}
+
+func TestSendMagicLink_NotifierErrorIsIgnored(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").
+		Once().Return(user, nil)
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("CountIssuedSince", mock.Anything, user.ID, domain.TokenPurposeMagicLink, mock.Anything).
+		Once().Return(0, nil)
+	tokenService.On("Issue", mock.Anything, user.ID, domain.TokenPurposeMagicLink, "", 10*time.Minute).
+		Once().Return("the-token", nil)
+
+	notifier := new(mocks.Notifier)
+	notifier.On("Notify", mock.Anything, user, mock.Anything).
+		Once().Return(errors.New("no email"))
+
+	err := newService(userRepo, nil, tokenService, notifier).sendMagicLink(context.TODO(), "alice", "")
+	assert.Nil(t, err)
+	notifier.AssertExpectations(t)
+}
+
+func TestSendMagicLink_Success(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com", Status: domain.UserStatusActive}
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").
+		Once().Return(user, nil)
+
+	// Only the hash of the nonce is stored.
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("CountIssuedSince", mock.Anything, user.ID, domain.TokenPurposeMagicLink, mock.Anything).
+		Once().Return(2, nil)
+	tokenService.On("Issue", mock.Anything, user.ID, domain.TokenPurposeMagicLink, hashNonce("device-nonce"), 10*time.Minute).
+		Once().Return("the-token", nil)
+
+	notifier := new(mocks.Notifier)
+	notifier.On("Notify", mock.Anything, user, mock.MatchedBy(func(n domain.Notification) bool {
+		return strings.Contains(n.Body, "https://example.com/magic-link?token=the-token") &&
+			strings.Contains(n.Body, "10 minutes") &&
+			!strings.Contains(n.Body, "device-nonce")
+	})).Once().Return(nil)
+
+	err := newService(userRepo, nil, tokenService, notifier).sendMagicLink(context.TODO(), "alice", "device-nonce")
+	assert.Nil(t, err)
+	tokenService.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Writes each notification as a .txt file, for local development.
+// Unlike the emails, users without an email get them too.
+type FileNotifier struct {
+	Dir string
+}
+
+func NewFileNotifier(dir string) domain.Notifier {
+	return FileNotifier{dir}
+}
+
+// Writes the notification into the directory, named after the time and the user
+func (n FileNotifier) Notify(ctx context.Context, user *domain.User, notification domain.Notification) error {
+	if user == nil {
+		return domain.ErrBadParamInput
+	}
+
+	if err := os.MkdirAll(n.Dir, 0o755); err != nil {
+		return err
+	}
+
+	content := fmt.Sprintf("To: %s <%s>\nSubject: %s\n\n%s", user.Username, user.Email, notification.Subject, notification.Body)
+	name := fmt.Sprintf("%s-%s.txt", time.Now().UTC().Format("20060102T150405.000000000"), user.ID.String())
+
+	return os.WriteFile(filepath.Join(n.Dir, name), []byte(content), 0o600)
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileNotifier_InvalidUser(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "notifications")
+	err := NewFileNotifier(dir).Notify(context.TODO(), nil, domain.Notification{Subject: "Hi"})
+	assert.Equal(t, domain.ErrBadParamInput, err)
+
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFileNotifier_Success(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "notifications")
+	user := &domain.User{ID: uuid.New(), Username: "alice"}
+
+	err := NewFileNotifier(dir).Notify(context.TODO(), user, domain.Notification{Subject: "Your login link", Body: "hello"})
+	assert.Nil(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*-"+user.ID.String()+".txt"))
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+
+	content, err := os.ReadFile(files[0])
+	assert.Nil(t, err)
+	assert.Equal(t, "To: alice <>\nSubject: Your login link\n\nhello", string(content))
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileMailer_InvalidEmail(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mails")
+	err := NewFileMailer(dir, "noreply@example.com").Send(context.TODO(), domain.Email{To: "nope"})
+	assert.Equal(t, domain.ErrBadParamInput, err)
+
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFileMailer_Success(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mails")
+	m := NewFileMailer(dir, "noreply@example.com")
+
+	assert.Nil(t, m.Send(context.TODO(), domain.Email{To: "alice@example.com", Subject: "first", Body: "hello"}))
+	assert.Nil(t, m.Send(context.TODO(), domain.Email{To: "bob@example.com", Subject: "second"}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	assert.Nil(t, err)
+	assert.Len(t, files, 2)
+
+	content, err := os.ReadFile(files[0])
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), "To: alice@example.com\r\n")
+	assert.Contains(t, string(content), "hello")
+}
@@ -0,0 +1,83 @@
+package mailer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Available mailer drivers
+const (
+	DriverSMTP   string = "smtp"
+	DriverFile   string = "file"
+	DriverMemory string = "memory"
+)
+
+// Settings to instantiate a mailer.
+type Settings struct {
+	Driver string
+	From   string
+
+	// SMTP driver
+	Host     string
+	Port     int
+	Username string
+	Password string
+
+	// File driver
+	Dir string
+}
+
+// Instantiates the mailer of the configured driver.
+func New(settings Settings) (domain.Mailer, error) {
+	switch settings.Driver {
+	case DriverSMTP:
+		return NewSMTPMailer(settings), nil
+	case DriverFile:
+		return NewFileMailer(settings.Dir, settings.From), nil
+	case DriverMemory:
+		return NewMemoryMailer(), nil
+	}
+	return nil, fmt.Errorf("unknown mailer driver %q", settings.Driver)
+}
+
+// Validates an email before it's sent.
+func validate(email domain.Email) error {
+	if strings.ContainsAny(email.To+email.Subject, "\r\n") {
+		return domain.ErrBadParamInput
+	}
+	if _, err := mail.ParseAddress(email.To); err != nil {
+		return domain.ErrBadParamInput
+	}
+	return nil
+}
+
+// Builds the RFC 5322 message of an email.
+func buildMessage(from string, email domain.Email, date time.Time) ([]byte, error) {
+	if err := validate(email); err != nil {
+		return nil, err
+	}
+	if len(from) == 0 {
+		return nil, errors.New("missing sender address")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", email.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", email.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(email.Body, "\r\n", "\n"), "\n", "\r\n"))
+	buf.WriteString("\r\n")
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,75 @@
+package mailer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	m, err := New(Settings{Driver: DriverSMTP, Host: "localhost", Port: 25})
+	assert.Nil(t, err)
+	assert.IsType(t, SMTPMailer{}, m)
+
+	m, err = New(Settings{Driver: DriverFile, Dir: t.TempDir()})
+	assert.Nil(t, err)
+	assert.IsType(t, FileMailer{}, m)
+
+	m, err = New(Settings{Driver: DriverMemory})
+	assert.Nil(t, err)
+	assert.IsType(t, &MemoryMailer{}, m)
+
+	m, err = New(Settings{Driver: "carrier-pigeon"})
+	assert.Error(t, err)
+	assert.Nil(t, m)
+}
+
+func TestBuildMessage_InvalidEmail(t *testing.T) {
+	invalidEmails := []domain.Email{
+		{To: "", Subject: "Hi"},
+		{To: "not an address", Subject: "Hi"},
+		{To: "alice@example.com\r\nBcc: eve@example.com", Subject: "Hi"},
+		{To: "alice@example.com", Subject: "Hi\r\nBcc: eve@example.com"},
+	}
+
+	for _, email := range invalidEmails {
+		message, err := buildMessage("noreply@example.com", email, time.Now())
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		assert.Nil(t, message)
+	}
+}
+
+func TestBuildMessage_MissingSender(t *testing.T) {
+	message, err := buildMessage("", domain.Email{To: "alice@example.com"}, time.Now())
+	assert.Error(t, err)
+	assert.Nil(t, message)
+}
+
+func TestBuildMessage_Success(t *testing.T) {
+	date := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	message, err := buildMessage("noreply@example.com", domain.Email{
+		To:      "alice@example.com",
+		Subject: "Verify your email",
+		Body:    "Hello\nBye",
+	}, date)
+	assert.Nil(t, err)
+
+	result := string(message)
+	assert.Contains(t, result, "From: noreply@example.com\r\n")
+	assert.Contains(t, result, "To: alice@example.com\r\n")
+	assert.Contains(t, result, "Subject: Verify your email\r\n")
+	assert.Contains(t, result, "Date: Tue, 01 Mar 2022 10:00:00 +0000\r\n")
+	assert.True(t, strings.HasSuffix(result, "\r\n\r\nHello\r\nBye\r\n"))
+}
+
+func TestBuildMessage_EncodesSubject(t *testing.T) {
+	message, err := buildMessage("noreply@example.com", domain.Email{
+		To:      "alice@example.com",
+		Subject: "Vérifiez",
+	}, time.Now())
+	assert.Nil(t, err)
+	assert.Contains(t, string(message), "Subject: =?utf-8?q?V=C3=A9rifiez?=\r\n")
+}
@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Keeps the sent emails in memory, for tests and local development.
+type MemoryMailer struct {
+	mu     sync.Mutex
+	emails []domain.Email
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Stores the email
+func (m *MemoryMailer) Send(ctx context.Context, email domain.Email) error {
+	if err := validate(email); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.emails = append(m.emails, email)
+	return nil
+}
+
+// Gets all the sent emails
+func (m *MemoryMailer) Emails() []domain.Email {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]domain.Email, len(m.emails))
+	copy(result, m.emails)
+	return result
+}
+
+// Gets the last email sent to an address
+func (m *MemoryMailer) LastTo(to string) (domain.Email, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := len(m.emails) - 1; i >= 0; i-- {
+		if m.emails[i].To == to {
+			return m.emails[i], true
+		}
+	}
+	return domain.Email{}, false
+}
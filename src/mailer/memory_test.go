@@ -0,0 +1,35 @@
+package mailer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryMailer_InvalidEmail(t *testing.T) {
+	m := NewMemoryMailer()
+	err := m.Send(context.TODO(), domain.Email{To: "nope"})
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	assert.Empty(t, m.Emails())
+}
+
+func TestMemoryMailer_Success(t *testing.T) {
+	m := NewMemoryMailer()
+	first := domain.Email{To: "alice@example.com", Subject: "first"}
+	second := domain.Email{To: "bob@example.com", Subject: "second"}
+	third := domain.Email{To: "alice@example.com", Subject: "third"}
+
+	assert.Nil(t, m.Send(context.TODO(), first))
+	assert.Nil(t, m.Send(context.TODO(), second))
+	assert.Nil(t, m.Send(context.TODO(), third))
+	assert.Equal(t, []domain.Email{first, second, third}, m.Emails())
+
+	last, ok := m.LastTo("alice@example.com")
+	assert.True(t, ok)
+	assert.Equal(t, third, last)
+
+	_, ok = m.LastTo("eve@example.com")
+	assert.False(t, ok)
+}
@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Available notifier drivers
+const (
+	NotifierEmail string = "email"
+	NotifierFile  string = "file"
+)
+
+// Instantiates the notifier of the configured driver.
+// Email notifications are sent with the mailer, file ones are written into the directory.
+func NewNotifier(driver string, mailer domain.Mailer, dir string) (domain.Notifier, error) {
+	switch driver {
+	case NotifierEmail:
+		return NewEmailNotifier(mailer), nil
+	case NotifierFile:
+		return NewFileNotifier(dir), nil
+	}
+	return nil, fmt.Errorf("unknown notifier driver %q", driver)
+}
+
+// Notifies the users by email.
+type EmailNotifier struct {
+	Mailer domain.Mailer
+}
+
+func NewEmailNotifier(mailer domain.Mailer) domain.Notifier {
+	return EmailNotifier{mailer}
+}
+
+// Sends the notification to the email of the user
+func (n EmailNotifier) Notify(ctx context.Context, user *domain.User, notification domain.Notification) error {
+	if user == nil || len(user.Email) == 0 {
+		return domain.ErrBadParamInput
+	}
+
+	return n.Mailer.Send(ctx, domain.Email{
+		To:      user.Email,
+		Subject: notification.Subject,
+		Body:    notification.Body,
+	})
+}
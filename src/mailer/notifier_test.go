@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNotifier(t *testing.T) {
+	n, err := NewNotifier(NotifierEmail, NewMemoryMailer(), "")
+	assert.Nil(t, err)
+	assert.IsType(t, EmailNotifier{}, n)
+
+	n, err = NewNotifier(NotifierFile, nil, t.TempDir())
+	assert.Nil(t, err)
+	assert.IsType(t, FileNotifier{}, n)
+
+	n, err = NewNotifier("carrier-pigeon", nil, "")
+	assert.Error(t, err)
+	assert.Nil(t, n)
+}
+
+func TestEmailNotifier_UserWithoutEmail(t *testing.T) {
+	m := NewMemoryMailer()
+	n := NewEmailNotifier(m)
+
+	err := n.Notify(context.TODO(), nil, domain.Notification{Subject: "Hi"})
+	assert.Equal(t, domain.ErrBadParamInput, err)
+
+	err = n.Notify(context.TODO(), &domain.User{Username: "alice"}, domain.Notification{Subject: "Hi"})
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	assert.Empty(t, m.Emails())
+}
+
+func TestEmailNotifier_Success(t *testing.T) {
+	m := NewMemoryMailer()
+	n := NewEmailNotifier(m)
+
+	err := n.Notify(context.TODO(), &domain.User{Email: "alice@example.com"}, domain.Notification{
+		Subject: "Hi",
+		Body:    "Hello",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.Email{{To: "alice@example.com", Subject: "Hi", Body: "Hello"}}, m.Emails())
+}
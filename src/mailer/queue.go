@@ -0,0 +1,58 @@
+package mailer
+
+import "sync"
+
+// Sends in the background with a fixed number of workers, so the requests that send
+// something take as long whether they do or not. Holds up to a limit of waiting sends,
+// and drops the ones that come when it's full.
+type Queue struct {
+	mu     sync.RWMutex
+	closed bool
+	sends  chan func()
+	wg     sync.WaitGroup
+}
+
+// Starts the workers of a queue holding up to size waiting sends.
+func NewQueue(workers int, size int) *Queue {
+	q := &Queue{sends: make(chan func(), size)}
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+	return q
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+	for send := range q.sends {
+		send()
+	}
+}
+
+// Queues a send. Returns false when it was dropped, because the queue is full or closed.
+func (q *Queue) Enqueue(send func()) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return false
+	}
+
+	select {
+	case q.sends <- send:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stops taking sends, and waits for the queued ones to be done.
+func (q *Queue) Close() {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.sends)
+	}
+	q.mu.Unlock()
+
+	q.wg.Wait()
+}
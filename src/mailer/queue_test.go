@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue_CloseWaitsForQueuedSends(t *testing.T) {
+	q := NewQueue(2, 10)
+
+	var sent int32
+	for i := 0; i < 10; i++ {
+		assert.True(t, q.Enqueue(func() { atomic.AddInt32(&sent, 1) }))
+	}
+	q.Close()
+	assert.Equal(t, int32(10), atomic.LoadInt32(&sent))
+
+	// Closed queues drop the sends.
+	assert.False(t, q.Enqueue(func() { atomic.AddInt32(&sent, 1) }))
+	q.Close()
+	assert.Equal(t, int32(10), atomic.LoadInt32(&sent))
+}
+
+func TestQueue_DropsWhenFull(t *testing.T) {
+	q := NewQueue(1, 1)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	assert.True(t, q.Enqueue(func() {
+		close(started)
+		<-release
+	}))
+	<-started
+
+	// The worker is busy, so one send waits and the next one is dropped.
+	var sent int32
+	assert.True(t, q.Enqueue(func() { atomic.AddInt32(&sent, 1) }))
+	assert.False(t, q.Enqueue(func() { atomic.AddInt32(&sent, 1) }))
+
+	close(release)
+	q.Close()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&sent))
+}
@@ -0,0 +1,105 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// Minimal SMTP server that records the received commands and data.
+func fakeSMTPServer(t *testing.T) (string, int, chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var transcript strings.Builder
+		r := bufio.NewReader(conn)
+		conn.Write([]byte("220 localhost ESMTP\r\n"))
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			transcript.WriteString(line)
+			command := strings.ToUpper(strings.TrimSpace(line))
+
+			switch {
+			case strings.HasPrefix(command, "EHLO"):
+				conn.Write([]byte("250 localhost\r\n"))
+			case strings.HasPrefix(command, "DATA"):
+				conn.Write([]byte("354 go ahead\r\n"))
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					transcript.WriteString(line)
+					if line == ".\r\n" {
+						break
+					}
+				}
+				conn.Write([]byte("250 ok\r\n"))
+			case strings.HasPrefix(command, "QUIT"):
+				conn.Write([]byte("221 bye\r\n"))
+				received <- transcript.String()
+				return
+			default:
+				conn.Write([]byte("250 ok\r\n"))
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	return host, portNumber, received
+}
+
+func TestSMTPMailer_InvalidEmail(t *testing.T) {
+	m := NewSMTPMailer(Settings{Host: "127.0.0.1", Port: 1, From: "noreply@example.com"})
+	err := m.Send(context.TODO(), domain.Email{To: "nope"})
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestSMTPMailer_ServerUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+	portNumber, _ := strconv.Atoi(port)
+
+	m := NewSMTPMailer(Settings{Host: "127.0.0.1", Port: portNumber, From: "noreply@example.com"})
+	err = m.Send(context.TODO(), domain.Email{To: "alice@example.com"})
+	assert.Error(t, err)
+}
+
+func TestSMTPMailer_Success(t *testing.T) {
+	host, port, received := fakeSMTPServer(t)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 2*time.Second)
+	defer cancel()
+
+	m := NewSMTPMailer(Settings{Host: host, Port: port, From: "Users <noreply@example.com>"})
+	err := m.Send(ctx, domain.Email{To: "alice@example.com", Subject: "Hi", Body: "hello"})
+	assert.Nil(t, err)
+
+	transcript := <-received
+	assert.Contains(t, transcript, "MAIL FROM:<noreply@example.com>")
+	assert.Contains(t, transcript, "RCPT TO:<alice@example.com>")
+	assert.Contains(t, transcript, "Subject: Hi\r\n")
+	assert.Contains(t, transcript, "hello\r\n")
+}
@@ -1,16 +1,60 @@
 package main
 
 import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	_attributesRepo "github.com/plagioriginal/user-microservice/attributes/repository/postgres"
+	_attributesService "github.com/plagioriginal/user-microservice/attributes/service"
+	_dataExportsRepo "github.com/plagioriginal/user-microservice/data-exports/repository/postgres"
+	_dataExportsService "github.com/plagioriginal/user-microservice/data-exports/service"
 	"github.com/plagioriginal/user-microservice/database"
 	_posgresConnection "github.com/plagioriginal/user-microservice/database/connection/postgres"
+	"github.com/plagioriginal/user-microservice/domain"
+	_emailVerificationService "github.com/plagioriginal/user-microservice/email-verification/service"
+	_groupsRepo "github.com/plagioriginal/user-microservice/groups/repository/postgres"
+	_groupsService "github.com/plagioriginal/user-microservice/groups/service"
+	"github.com/plagioriginal/user-microservice/helpers"
+	_identityProvidersRepo "github.com/plagioriginal/user-microservice/identity-providers/repository/postgres"
+	_identityProvidersService "github.com/plagioriginal/user-microservice/identity-providers/service"
+	_invitationsService "github.com/plagioriginal/user-microservice/invitations/service"
+	_ldapAuthenticator "github.com/plagioriginal/user-microservice/ldap/authenticator"
+	_loginAttemptsRepo "github.com/plagioriginal/user-microservice/login-attempts/repository/postgres"
+	_loginAttemptsService "github.com/plagioriginal/user-microservice/login-attempts/service"
+	_loginEventsRepo "github.com/plagioriginal/user-microservice/login-events/repository/postgres"
+	_loginEventsService "github.com/plagioriginal/user-microservice/login-events/service"
+	_magicLinksService "github.com/plagioriginal/user-microservice/magic-links/service"
+	"github.com/plagioriginal/user-microservice/mailer"
+	_mfaRepo "github.com/plagioriginal/user-microservice/mfa/repository/postgres"
+	_mfaService "github.com/plagioriginal/user-microservice/mfa/service"
+	_oneTimeTokensRepo "github.com/plagioriginal/user-microservice/one-time-tokens/repository/postgres"
+	_oneTimeTokensService "github.com/plagioriginal/user-microservice/one-time-tokens/service"
+	_organizationsRepo "github.com/plagioriginal/user-microservice/organizations/repository/postgres"
+	_organizationsService "github.com/plagioriginal/user-microservice/organizations/service"
+	_passkeysRepo "github.com/plagioriginal/user-microservice/passkeys/repository/postgres"
+	_passkeysService "github.com/plagioriginal/user-microservice/passkeys/service"
+	_passwordResetService "github.com/plagioriginal/user-microservice/password-reset/service"
 	_refreshTokensRepo "github.com/plagioriginal/user-microservice/refresh-tokens/repository/postgres"
 	_refreshTokensService "github.com/plagioriginal/user-microservice/refresh-tokens/service"
+	_registrationService "github.com/plagioriginal/user-microservice/registration/service"
 	_rolesRepo "github.com/plagioriginal/user-microservice/roles/repository/postgres"
+	_rolesService "github.com/plagioriginal/user-microservice/roles/service"
+	_userBackupCli "github.com/plagioriginal/user-microservice/user-backups/cli"
+	_userBackupService "github.com/plagioriginal/user-microservice/user-backups/service"
+	_userDeletionService "github.com/plagioriginal/user-microservice/user-deletion/service"
+	_userImportCli "github.com/plagioriginal/user-microservice/user-import/cli"
+	_userImportService "github.com/plagioriginal/user-microservice/user-import/service"
+	_usernameHistoryRepo "github.com/plagioriginal/user-microservice/username-history/repository/postgres"
 	"github.com/plagioriginal/user-microservice/users/handler"
 	_usersRepo "github.com/plagioriginal/user-microservice/users/repository/postgres"
 	users "github.com/plagioriginal/users-service-grpc/users"
@@ -23,6 +67,10 @@ import (
 
 func main() {
 	logger := log.New(os.Stdout, "users-auth: ", log.Flags())
+	// Commands write their results to the standard output, so the logs go elsewhere.
+	if len(os.Args) > 1 {
+		logger.SetOutput(os.Stderr)
+	}
 
 	db, err := _posgresConnection.Get(_posgresConnection.PostgresConnectionSettings{
 		User:     os.Getenv("DB_USER"),
@@ -50,15 +98,284 @@ func main() {
 	userRepo := _usersRepo.New(db)
 	roleRepo := _rolesRepo.New(db)
 	refreshTokenRepo := _refreshTokensRepo.New(db)
+	loginAttemptRepo := _loginAttemptsRepo.New(db)
+	oneTimeTokenRepo := _oneTimeTokensRepo.New(db)
+	mfaRepo := _mfaRepo.New(db)
+	passkeyRepo := _passkeysRepo.New(db)
+	attributeRepo := _attributesRepo.New(db)
+	groupRepo := _groupsRepo.New(db)
+	organizationRepo := _organizationsRepo.New(db)
+	loginEventRepo := _loginEventsRepo.New(db)
+	usernameHistoryRepo := _usernameHistoryRepo.New(db)
+	identityProviderRepo := _identityProvidersRepo.New(db)
+
+	mailerDriver := os.Getenv("MAILER")
+	if len(mailerDriver) == 0 {
+		mailerDriver = mailer.DriverFile
+	}
+	userMailer, err := mailer.New(mailer.Settings{
+		Driver:   mailerDriver,
+		From:     os.Getenv("MAIL_FROM"),
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     helpers.ConvertToInt(os.Getenv("SMTP_PORT"), 587),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		Dir:      os.Getenv("MAIL_DIR"),
+	})
+	if err != nil {
+		logger.Fatal(err)
+	}
 
 	// Creating all the services.
 	refreshTokenService := _refreshTokensService.New(logger, refreshTokenRepo, userRepo, timeoutContext)
-	tokenManager := tokens.NewTokenManager(jwtTokenSecret, refreshTokenService, roleRepo)
-	userService := _usersService.New(userRepo, roleRepo, timeoutContext, _usersService.ProductionBcryptCost)
+	tokenManager := tokens.NewTokenManager(jwtTokenSecret, refreshTokenService, roleRepo, attributeRepo, groupRepo, usernameHistoryRepo, userRepo)
+	authenticators, err := newAuthenticators(logger, userRepo, roleRepo)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	userService := _usersService.New(
+		userRepo,
+		roleRepo,
+		usernameHistoryRepo,
+		timeoutContext,
+		_usersService.ProductionBcryptCost,
+		domain.UsernameSettings{
+			ReservationPeriod: time.Duration(helpers.ConvertToInt(os.Getenv("USERNAME_RESERVATION_DAYS"), 30)) * 24 * time.Hour,
+		},
+		authenticators,
+	)
+	loginAttemptService := _loginAttemptsService.New(logger, loginAttemptRepo, timeoutContext, domain.LoginThrottleSettings{
+		MaxFailedAttemptsPerUser: helpers.ConvertToInt(os.Getenv("LOGIN_MAX_FAILED_ATTEMPTS_PER_USER"), 5),
+		MaxFailedAttemptsPerIP:   helpers.ConvertToInt(os.Getenv("LOGIN_MAX_FAILED_ATTEMPTS_PER_IP"), 20),
+		LockoutDuration:          time.Duration(helpers.ConvertToInt(os.Getenv("LOGIN_LOCKOUT_SECONDS"), 60)) * time.Second,
+		MaxLockoutDuration:       time.Duration(helpers.ConvertToInt(os.Getenv("LOGIN_MAX_LOCKOUT_SECONDS"), 3600)) * time.Second,
+		FailureWindow:            time.Duration(helpers.ConvertToInt(os.Getenv("LOGIN_FAILURE_WINDOW_SECONDS"), 86400)) * time.Second,
+	})
+	oneTimeTokenService := _oneTimeTokensService.New(logger, oneTimeTokenRepo, timeoutContext)
+	emailVerificationService := _emailVerificationService.New(
+		logger,
+		userRepo,
+		userService,
+		oneTimeTokenService,
+		userMailer,
+		timeoutContext,
+		domain.EmailVerificationSettings{
+			VerificationURL: os.Getenv("EMAIL_VERIFICATION_URL"),
+			TokenTTL:        time.Duration(helpers.ConvertToInt(os.Getenv("EMAIL_VERIFICATION_TTL_HOURS"), 24)) * time.Hour,
+		},
+	)
+	// The links asked for without logging in are sent in the background, by a bounded number of workers.
+	sendQueue := mailer.NewQueue(
+		helpers.ConvertToInt(os.Getenv("SEND_QUEUE_WORKERS"), 4),
+		helpers.ConvertToInt(os.Getenv("SEND_QUEUE_SIZE"), 100),
+	)
+	passwordResetService := _passwordResetService.New(
+		logger,
+		userRepo,
+		refreshTokenRepo,
+		oneTimeTokenService,
+		mailer.NewEmailNotifier(userMailer),
+		sendQueue,
+		timeoutContext,
+		_usersService.ProductionBcryptCost,
+		domain.PasswordResetSettings{
+			ResetURL:             os.Getenv("PASSWORD_RESET_URL"),
+			TokenTTL:             time.Duration(helpers.ConvertToInt(os.Getenv("PASSWORD_RESET_TTL_MINUTES"), 30)) * time.Minute,
+			MaxRequestsPerWindow: helpers.ConvertToInt(os.Getenv("PASSWORD_RESET_MAX_REQUESTS_PER_HOUR"), 3),
+			RequestWindow:        time.Hour,
+		},
+	)
+
+	// The passwords that aren't chosen through an administrator follow the same policy.
+	minPasswordLength := helpers.ConvertToInt(os.Getenv("USER_IMPORT_MIN_PASSWORD_LENGTH"), 8)
+	invitationService := _invitationsService.New(
+		logger,
+		userRepo,
+		userService,
+		oneTimeTokenRepo,
+		oneTimeTokenService,
+		mailer.NewEmailNotifier(userMailer),
+		timeoutContext,
+		_usersService.ProductionBcryptCost,
+		domain.InvitationSettings{
+			AcceptURL:         os.Getenv("INVITATION_URL"),
+			TokenTTL:          time.Duration(helpers.ConvertToInt(os.Getenv("INVITATION_TTL_HOURS"), 72)) * time.Hour,
+			MinPasswordLength: minPasswordLength,
+		},
+	)
+
+	magicLinkNotifierDriver := os.Getenv("MAGIC_LINK_NOTIFIER")
+	if len(magicLinkNotifierDriver) == 0 {
+		magicLinkNotifierDriver = mailer.NotifierEmail
+	}
+	magicLinkNotifier, err := mailer.NewNotifier(magicLinkNotifierDriver, userMailer, os.Getenv("MAGIC_LINK_NOTIFIER_DIR"))
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	magicLinkService := _magicLinksService.New(
+		logger,
+		userRepo,
+		userService,
+		oneTimeTokenService,
+		magicLinkNotifier,
+		sendQueue,
+		timeoutContext,
+		domain.MagicLinkSettings{
+			LoginURL:             os.Getenv("MAGIC_LINK_URL"),
+			TokenTTL:             time.Duration(helpers.ConvertToInt(os.Getenv("MAGIC_LINK_TTL_MINUTES"), 10)) * time.Minute,
+			MaxRequestsPerWindow: helpers.ConvertToInt(os.Getenv("MAGIC_LINK_MAX_REQUESTS_PER_HOUR"), 5),
+			RequestWindow:        time.Hour,
+		},
+	)
+
+	registrationDefaultRole := os.Getenv("REGISTRATION_DEFAULT_ROLE")
+	if registrationDefaultRole == "" {
+		registrationDefaultRole = domain.DEFAULT_ROLE_USER.RoleSlug
+	}
+	registrationService := _registrationService.New(
+		logger,
+		userService,
+		timeoutContext,
+		domain.RegistrationSettings{
+			Enabled:             helpers.ConvertToBool(os.Getenv("REGISTRATION_ENABLED"), false),
+			DefaultRoleSlug:     registrationDefaultRole,
+			AllowedEmailDomains: helpers.SplitList(os.Getenv("REGISTRATION_ALLOWED_EMAIL_DOMAINS")),
+		},
+	)
+
+	mfaEncryptionKey, err := base64.StdEncoding.DecodeString(os.Getenv("MFA_ENCRYPTION_KEY"))
+	if err != nil || len(mfaEncryptionKey) != 32 {
+		logger.Fatalln("MFA_ENCRYPTION_KEY must be 32 bytes encoded in base64")
+	}
+	mfaService := _mfaService.New(
+		logger,
+		mfaRepo,
+		timeoutContext,
+		domain.MFASettings{
+			Issuer:             os.Getenv("MFA_ISSUER"),
+			EncryptionKey:      mfaEncryptionKey,
+			RecoveryCodesCount: helpers.ConvertToInt(os.Getenv("MFA_RECOVERY_CODES"), 10),
+		},
+	)
+
+	passkeyService := _passkeysService.New(
+		logger,
+		passkeyRepo,
+		userService,
+		timeoutContext,
+		domain.PasskeySettings{
+			RPID:          os.Getenv("WEBAUTHN_RP_ID"),
+			RPDisplayName: os.Getenv("WEBAUTHN_RP_DISPLAY_NAME"),
+			RPOrigins:     helpers.SplitList(os.Getenv("WEBAUTHN_RP_ORIGINS")),
+			SessionTTL:    time.Duration(helpers.ConvertToInt(os.Getenv("WEBAUTHN_SESSION_TTL_SECONDS"), 300)) * time.Second,
+		},
+	)
+
+	oidcService := _identityProvidersService.New(
+		logger,
+		identityProviderRepo,
+		roleRepo,
+		userRepo,
+		userService,
+		timeoutContext,
+		domain.OIDCSettings{
+			SessionTTL:          time.Duration(helpers.ConvertToInt(os.Getenv("OIDC_SESSION_TTL_SECONDS"), 600)) * time.Second,
+			DiscoveryTTL:        time.Duration(helpers.ConvertToInt(os.Getenv("OIDC_DISCOVERY_TTL_MINUTES"), 60)) * time.Minute,
+			HTTPTimeout:         time.Duration(helpers.ConvertToInt(os.Getenv("OIDC_HTTP_TIMEOUT_SECONDS"), 10)) * time.Second,
+			AllowPrivateIssuers: helpers.ConvertToBool(os.Getenv("OIDC_ALLOW_PRIVATE_ISSUERS"), false),
+		},
+	)
+
+	userDeletionSettings := domain.UserDeletionSettings{
+		RetentionPeriod: time.Duration(helpers.ConvertToInt(os.Getenv("USER_DELETION_RETENTION_DAYS"), 30)) * 24 * time.Hour,
+		PurgeInterval:   time.Duration(helpers.ConvertToInt(os.Getenv("USER_PURGE_INTERVAL_MINUTES"), 60)) * time.Minute,
+		PurgeBatchSize:  helpers.ConvertToInt(os.Getenv("USER_PURGE_BATCH_SIZE"), 100),
+	}
+	userDeletionService := _userDeletionService.New(
+		logger,
+		userRepo,
+		userService,
+		timeoutContext,
+		userDeletionSettings,
+	)
+
+	dataExportService := _dataExportsService.New(
+		logger,
+		_dataExportsRepo.New(db),
+		userService,
+		refreshTokenRepo,
+		mfaRepo,
+		passkeyRepo,
+		oneTimeTokenRepo,
+		groupRepo,
+		loginEventRepo,
+		usernameHistoryRepo,
+		identityProviderRepo,
+		timeoutContext,
+	)
+
+	attributeService := _attributesService.New(logger, attributeRepo, userRepo, timeoutContext)
+	groupService := _groupsService.New(logger, groupRepo, userRepo, roleRepo, timeoutContext)
+	roleService := _rolesService.New(logger, roleRepo, timeoutContext)
+	organizationService := _organizationsService.New(logger, organizationRepo, roleRepo, userService, timeoutContext)
+	loginEventService := _loginEventsService.New(logger, loginEventRepo, userRepo, timeoutContext)
+
+	userImportService := _userImportService.New(
+		logger,
+		userRepo,
+		roleRepo,
+		usernameHistoryRepo,
+		timeoutContext,
+		_usersService.ProductionBcryptCost,
+		domain.UserImportSettings{
+			BatchSize:         helpers.ConvertToInt(os.Getenv("USER_IMPORT_BATCH_SIZE"), 100),
+			MinPasswordLength: minPasswordLength,
+			AllowSHA1Hashes:   helpers.ConvertToBool(os.Getenv("USER_IMPORT_ALLOW_SHA1_HASHES"), false),
+		},
+	)
+
+	userBackupService := _userBackupService.New(
+		logger,
+		userRepo,
+		roleRepo,
+		groupRepo,
+		timeoutContext,
+		userDeletionSettings.RetentionPeriod,
+	)
+
+	// Running as a command instead of the server.
+	commands := map[string]func(args []string) error{
+		_userImportCli.Command: func(args []string) error {
+			return _userImportCli.Run(context.Background(), userImportService, args, os.Stdin, os.Stdout)
+		},
+		_userBackupCli.ExportCommand: func(args []string) error {
+			return _userBackupCli.Export(context.Background(), userBackupService, args, os.Stdout)
+		},
+		_userBackupCli.RestoreCommand: func(args []string) error {
+			return _userBackupCli.Restore(context.Background(), userBackupService, args, os.Stdin, os.Stdout)
+		},
+	}
+	if len(os.Args) > 1 && commands[os.Args[1]] != nil {
+		if err = commands[os.Args[1]](os.Args[2:]); err != nil {
+			logger.Fatalln(err)
+		}
+		return
+	}
+
+	go userDeletionService.RunPurger(context.Background())
+
+	trustedProxies, err := handler.ParseTrustedProxies(helpers.SplitList(os.Getenv("TRUSTED_PROXIES")))
+	if err != nil {
+		logger.Fatal(err)
+	}
 
 	// @todo: refactor server instantiation.
-	gs := grpc.NewServer()
-	grpcServer := handler.NewUserGRPCHandler(logger, tokenManager, userService)
+	gs := grpc.NewServer(
+		grpc.UnaryInterceptor(handler.OrganizationUnaryInterceptor),
+		grpc.StreamInterceptor(handler.OrganizationStreamInterceptor),
+	)
+	grpcServer := handler.NewUserGRPCHandler(logger, tokenManager, userService, loginAttemptService, emailVerificationService, passwordResetService, registrationService, mfaService, passkeyService, userDeletionService, dataExportService, attributeService, userImportService, userBackupService, groupService, organizationService, loginEventService, invitationService, magicLinkService, oidcService, roleService, trustedProxies)
 	users.RegisterUsersServer(gs, grpcServer)
 
 	reflection.Register(gs)
@@ -69,8 +386,100 @@ func main() {
 		os.Exit(1)
 	}
 
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		<-signals
+		logger.Println("shutting down the gRPC server")
+		gs.GracefulStop()
+	}()
+
 	logger.Println("gRPC Server running at port: " + os.Getenv("APP_PORT"))
 	if err = gs.Serve(l); err != nil {
 		logger.Fatalln(err)
 	}
+
+	// The queued links are still sent before stopping.
+	sendQueue.Close()
+}
+
+// Creates the authenticators the logins are checked by, in the order of AUTH_BACKENDS.
+// Only the local passwords are checked when it's not set.
+func newAuthenticators(logger *log.Logger, userRepo domain.UserRepository, roleRepo domain.RoleRepository) ([]domain.Authenticator, error) {
+	backends := helpers.SplitList(os.Getenv("AUTH_BACKENDS"))
+	if len(backends) == 0 {
+		backends = []string{_usersService.LocalBackend}
+	}
+
+	authenticators := []domain.Authenticator{}
+	// Directories binding to DN templates without a search account can't tell unknown users
+	// from wrong passwords, so they never let the next backends try them.
+	unknownUsersEndChain := false
+	for _, backend := range backends {
+		if unknownUsersEndChain {
+			return nil, fmt.Errorf("authentication backend %q comes after an LDAP directory without LDAP_BIND_DN to look users up with", backend)
+		}
+		switch backend {
+		case _usersService.LocalBackend:
+			authenticators = append(authenticators, _usersService.NewLocalAuthenticator(userRepo, roleRepo, _usersService.ProductionBcryptCost))
+		case _ldapAuthenticator.Backend:
+			settings, err := ldapSettings()
+			if err != nil {
+				return nil, err
+			}
+			authenticators = append(authenticators, _ldapAuthenticator.New(logger, userRepo, roleRepo, settings))
+			unknownUsersEndChain = len(settings.BindDNTemplate) > 0 && len(settings.BindDN) == 0
+		default:
+			return nil, fmt.Errorf("unknown authentication backend %q", backend)
+		}
+	}
+	return authenticators, nil
+}
+
+// Reads the settings of the LDAP directory from the environment.
+func ldapSettings() (domain.LDAPSettings, error) {
+	settings := domain.LDAPSettings{
+		URL:                os.Getenv("LDAP_URL"),
+		StartTLS:           helpers.ConvertToBool(os.Getenv("LDAP_START_TLS"), false),
+		InsecureSkipVerify: helpers.ConvertToBool(os.Getenv("LDAP_INSECURE_SKIP_VERIFY"), false),
+		BindDNTemplate:     os.Getenv("LDAP_BIND_DN_TEMPLATE"),
+		BindDN:             os.Getenv("LDAP_BIND_DN"),
+		BindPassword:       os.Getenv("LDAP_BIND_PASSWORD"),
+		BaseDN:             os.Getenv("LDAP_BASE_DN"),
+		UserFilter:         os.Getenv("LDAP_USER_FILTER"),
+		EmailAttribute:     os.Getenv("LDAP_EMAIL_ATTRIBUTE"),
+		FirstNameAttribute: os.Getenv("LDAP_FIRST_NAME_ATTRIBUTE"),
+		LastNameAttribute:  os.Getenv("LDAP_LAST_NAME_ATTRIBUTE"),
+		GroupAttribute:     os.Getenv("LDAP_GROUP_ATTRIBUTE"),
+		GroupBaseDN:        os.Getenv("LDAP_GROUP_BASE_DN"),
+		GroupFilter:        os.Getenv("LDAP_GROUP_FILTER"),
+		DefaultRole:        os.Getenv("LDAP_DEFAULT_ROLE"),
+		AutoProvision:      helpers.ConvertToBool(os.Getenv("LDAP_AUTO_PROVISION"), false),
+		Timeout:            time.Duration(helpers.ConvertToInt(os.Getenv("LDAP_TIMEOUT_SECONDS"), 5)) * time.Second,
+	}
+	if len(settings.URL) == 0 {
+		return settings, errors.New("LDAP_URL is required with the ldap authentication backend")
+	}
+	if len(settings.BindDNTemplate) == 0 && (len(settings.BaseDN) == 0 || len(settings.UserFilter) == 0) {
+		return settings, errors.New("LDAP_BIND_DN_TEMPLATE, or LDAP_BASE_DN and LDAP_USER_FILTER, are required")
+	}
+
+	// Group DNs have commas, so the mapping is a JSON object.
+	if mapping := os.Getenv("LDAP_ROLE_MAPPING"); len(mapping) > 0 {
+		if err := json.Unmarshal([]byte(mapping), &settings.RoleMapping); err != nil {
+			return settings, fmt.Errorf("invalid LDAP_ROLE_MAPPING: %w", err)
+		}
+	}
+
+	if caFile := os.Getenv("LDAP_CA_CERT_FILE"); len(caFile) > 0 {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return settings, err
+		}
+		settings.RootCAs = x509.NewCertPool()
+		if !settings.RootCAs.AppendCertsFromPEM(pem) {
+			return settings, fmt.Errorf("no certificates in %v", caFile)
+		}
+	}
+	return settings, nil
 }
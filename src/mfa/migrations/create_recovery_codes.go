@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Creates the MFA recovery codes table
+func CreateRecoveryCodesTable(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS recovery_codes(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			code_hash varchar(128) NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id),
+			UNIQUE (user_id, code_hash)
+		);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewCreateRecoveryCodesTableMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "create-recovery-codes-table",
+		Up:   CreateRecoveryCodesTable,
+	}
+}
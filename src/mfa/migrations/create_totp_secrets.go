@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Creates the TOTP secrets table
+func CreateTOTPSecretsTable(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS totp_secrets(
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			encrypted_secret text NOT NULL,
+			confirmed_at timestamptz DEFAULT NULL,
+			last_used_step bigint NOT NULL DEFAULT 0,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			updated_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (user_id)
+		);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewCreateTOTPSecretsTableMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "create-totp-secrets-table",
+		Up:   CreateTOTPSecretsTable,
+	}
+}
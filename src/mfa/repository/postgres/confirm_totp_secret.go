@@ -0,0 +1,28 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Confirms the pending TOTP secret of a user, with the step of the code used to confirm it.
+func (r PostgresRepository) ConfirmTOTPSecret(ctx context.Context, userID uuid.UUID, step int64) error {
+	query := `
+		UPDATE totp_secrets
+		SET confirmed_at = $3, last_used_step = $2, updated_at = $3
+		WHERE user_id = $1 AND confirmed_at IS NULL
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	result, err := stmt.ExecContext(ctx, userID, step, time.Now())
+	if err != nil {
+		return err
+	}
+	return requireAffectedRows(result)
+}
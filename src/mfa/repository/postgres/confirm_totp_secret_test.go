@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirmTOTPSecret_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		UPDATE totp_secrets
+		SET confirmed_at = $3, last_used_step = $2, updated_at = $3
+		WHERE user_id = $1 AND confirmed_at IS NULL
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = New(db).ConfirmTOTPSecret(context.TODO(), uuid.New(), int64(55000000))
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestConfirmTOTPSecret_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	query := `
+		UPDATE totp_secrets
+		SET confirmed_at = $3, last_used_step = $2, updated_at = $3
+		WHERE user_id = $1 AND confirmed_at IS NULL
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs(userID, int64(55000000), anyTime{}).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = New(db).ConfirmTOTPSecret(ctx, userID, int64(55000000))
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestConfirmTOTPSecret_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	query := `
+		UPDATE totp_secrets
+		SET confirmed_at = $3, last_used_step = $2, updated_at = $3
+		WHERE user_id = $1 AND confirmed_at IS NULL
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs(userID, int64(55000000), anyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = New(db).ConfirmTOTPSecret(context.TODO(), userID, int64(55000000))
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestConfirmTOTPSecret_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	query := `
+		UPDATE totp_secrets
+		SET confirmed_at = $3, last_used_step = $2, updated_at = $3
+		WHERE user_id = $1 AND confirmed_at IS NULL
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs(userID, int64(55000000), anyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = New(db).ConfirmTOTPSecret(context.TODO(), userID, int64(55000000))
+	assert.Nil(t, err)
+}
@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets the TOTP secret of a user, confirmed or not.
+func (r PostgresRepository) GetTOTPSecret(ctx context.Context, userID uuid.UUID) (domain.TOTPSecret, error) {
+	query := `
+		SELECT user_id, encrypted_secret, confirmed_at, last_used_step, created_at, updated_at
+		FROM totp_secrets
+		WHERE user_id = $1
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.TOTPSecret{}, err
+	}
+
+	row := stmt.QueryRowContext(ctx, userID)
+	return r.scanTOTPSecretRow(row)
+}
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTOTPSecret_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		SELECT user_id, encrypted_secret, confirmed_at, last_used_step, created_at, updated_at
+		FROM totp_secrets
+		WHERE user_id = $1
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).GetTOTPSecret(context.TODO(), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestGetTOTPSecret_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	query := `
+		SELECT user_id, encrypted_secret, confirmed_at, last_used_step, created_at, updated_at
+		FROM totp_secrets
+		WHERE user_id = $1
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs(userID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).GetTOTPSecret(ctx, userID)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestGetTOTPSecret_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	query := `
+		SELECT user_id, encrypted_secret, confirmed_at, last_used_step, created_at, updated_at
+		FROM totp_secrets
+		WHERE user_id = $1
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs(userID).
+		WillReturnError(sql.ErrNoRows)
+
+	res, err := New(db).GetTOTPSecret(context.TODO(), userID)
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Empty(t, res)
+}
+
+func TestGetTOTPSecret_Pending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	createdAt := time.Now()
+	rows := sqlmock.NewRows([]string{"user_id", "encrypted_secret", "confirmed_at", "last_used_step", "created_at", "updated_at"}).
+		AddRow(userID, "encrypted", nil, 0, createdAt, createdAt)
+
+	query := `
+		SELECT user_id, encrypted_secret, confirmed_at, last_used_step, created_at, updated_at
+		FROM totp_secrets
+		WHERE user_id = $1
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs(userID).
+		WillReturnRows(rows)
+
+	res, err := New(db).GetTOTPSecret(context.TODO(), userID)
+	assert.Nil(t, err)
+	assert.Equal(t, userID, res.UserID)
+	assert.Equal(t, "encrypted", res.EncryptedSecret)
+	assert.False(t, res.IsConfirmed())
+}
+
+func TestGetTOTPSecret_Confirmed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	createdAt := time.Now()
+	rows := sqlmock.NewRows([]string{"user_id", "encrypted_secret", "confirmed_at", "last_used_step", "created_at", "updated_at"}).
+		AddRow(userID, "encrypted", createdAt, 55000000, createdAt, createdAt)
+
+	query := `
+		SELECT user_id, encrypted_secret, confirmed_at, last_used_step, created_at, updated_at
+		FROM totp_secrets
+		WHERE user_id = $1
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs(userID).
+		WillReturnRows(rows)
+
+	res, err := New(db).GetTOTPSecret(context.TODO(), userID)
+	assert.Nil(t, err)
+	assert.True(t, res.IsConfirmed())
+	assert.Equal(t, createdAt, res.ConfirmedAt)
+	assert.Equal(t, int64(55000000), res.LastUsedStep)
+}
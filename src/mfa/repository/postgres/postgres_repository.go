@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type PostgresRepository struct {
+	Db *sql.DB
+}
+
+func New(db *sql.DB) domain.MFARepository {
+	return PostgresRepository{db}
+}
+
+// Scans a TOTP secret row
+func (r PostgresRepository) scanTOTPSecretRow(row *sql.Row) (domain.TOTPSecret, error) {
+	result := domain.TOTPSecret{}
+	var confirmedAt sql.NullTime
+
+	err := row.Scan(
+		&result.UserID,
+		&result.EncryptedSecret,
+		&confirmedAt,
+		&result.LastUsedStep,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	)
+	if err != nil {
+		return domain.TOTPSecret{}, err
+	}
+
+	if confirmedAt.Valid {
+		result.ConfirmedAt = confirmedAt.Time
+	}
+	return result, nil
+}
+
+// Returns domain.ErrNotFound when a statement didn't affect any row.
+func requireAffectedRows(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package postgres
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+type anyTime struct{}
+
+// Match satisfies sqlmock.Argument interface
+func (a anyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceRecoveryCodes_ErrorBeginningTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin().WillReturnError(errors.New("boom"))
+
+	err = New(db).ReplaceRecoveryCodes(context.TODO(), uuid.New(), []string{"hash-1"})
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestReplaceRecoveryCodes_ErrorInsertingRollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM recovery_codes WHERE user_id = $1`)).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 10))
+	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO recovery_codes (user_id, code_hash, created_at) VALUES ($1, $2, $3)`)).
+		ExpectExec().
+		WithArgs(userID, "hash-1", anyTime{}).
+		WillReturnError(errors.New("boom"))
+	mock.ExpectRollback()
+
+	err = New(db).ReplaceRecoveryCodes(context.TODO(), userID, []string{"hash-1"})
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestReplaceRecoveryCodes_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM recovery_codes WHERE user_id = $1`)).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 10))
+	prepared := mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO recovery_codes (user_id, code_hash, created_at) VALUES ($1, $2, $3)`))
+	prepared.ExpectExec().
+		WithArgs(userID, "hash-1", anyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	prepared.ExpectExec().
+		WithArgs(userID, "hash-2", anyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = New(db).ReplaceRecoveryCodes(context.TODO(), userID, []string{"hash-1", "hash-2"})
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
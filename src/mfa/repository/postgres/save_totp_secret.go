@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Saves a pending TOTP secret, replacing any other pending one of the user.
+// A confirmed secret is never replaced, domain.ErrAlreadyExists is returned instead.
+func (r PostgresRepository) SaveTOTPSecret(ctx context.Context, secret domain.TOTPSecret) error {
+	query := `
+		INSERT INTO totp_secrets (user_id, encrypted_secret, confirmed_at, last_used_step, created_at, updated_at)
+		VALUES ($1, $2, NULL, 0, $3, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET encrypted_secret = EXCLUDED.encrypted_secret, last_used_step = 0, updated_at = EXCLUDED.updated_at
+		WHERE totp_secrets.confirmed_at IS NULL
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	result, err := stmt.ExecContext(ctx, secret.UserID, secret.EncryptedSecret, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err = requireAffectedRows(result); err == domain.ErrNotFound {
+		return domain.ErrAlreadyExists
+	}
+	return err
+}
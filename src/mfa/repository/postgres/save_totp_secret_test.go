@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const saveTOTPSecretQuery = `
+		INSERT INTO totp_secrets (user_id, encrypted_secret, confirmed_at, last_used_step, created_at, updated_at)
+		VALUES ($1, $2, NULL, 0, $3, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET encrypted_secret = EXCLUDED.encrypted_secret, last_used_step = 0, updated_at = EXCLUDED.updated_at
+		WHERE totp_secrets.confirmed_at IS NULL
+	`
+
+func TestSaveTOTPSecret_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(saveTOTPSecretQuery)).WillReturnError(errors.New("boom"))
+
+	err = New(db).SaveTOTPSecret(context.TODO(), domain.TOTPSecret{UserID: uuid.New(), EncryptedSecret: "encrypted"})
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestSaveTOTPSecret_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(saveTOTPSecretQuery)).
+		ExpectExec().
+		WithArgs(userID, "encrypted", anyTime{}).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = New(db).SaveTOTPSecret(ctx, domain.TOTPSecret{UserID: userID, EncryptedSecret: "encrypted"})
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestSaveTOTPSecret_AlreadyConfirmed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(saveTOTPSecretQuery)).
+		ExpectExec().
+		WithArgs(userID, "encrypted", anyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = New(db).SaveTOTPSecret(context.TODO(), domain.TOTPSecret{UserID: userID, EncryptedSecret: "encrypted"})
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+}
+
+func TestSaveTOTPSecret_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(saveTOTPSecretQuery)).
+		ExpectExec().
+		WithArgs(userID, "encrypted", anyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = New(db).SaveTOTPSecret(context.TODO(), domain.TOTPSecret{UserID: userID, EncryptedSecret: "encrypted"})
+	assert.Nil(t, err)
+}
@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Deletes a recovery code of a user, so it can only be used once.
+func (r PostgresRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	query := `
+		DELETE FROM recovery_codes
+		WHERE user_id = $1 AND code_hash = $2
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	result, err := stmt.ExecContext(ctx, userID, codeHash)
+	if err != nil {
+		return err
+	}
+	return requireAffectedRows(result)
+}
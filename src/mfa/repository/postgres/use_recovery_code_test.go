@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseRecoveryCode_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		DELETE FROM recovery_codes
+		WHERE user_id = $1 AND code_hash = $2
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = New(db).UseRecoveryCode(context.TODO(), uuid.New(), "hash")
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestUseRecoveryCode_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	query := `
+		DELETE FROM recovery_codes
+		WHERE user_id = $1 AND code_hash = $2
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs(userID, "hash").
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = New(db).UseRecoveryCode(ctx, userID, "hash")
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestUseRecoveryCode_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	query := `
+		DELETE FROM recovery_codes
+		WHERE user_id = $1 AND code_hash = $2
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs(userID, "hash").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = New(db).UseRecoveryCode(context.TODO(), userID, "hash")
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestUseRecoveryCode_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	query := `
+		DELETE FROM recovery_codes
+		WHERE user_id = $1 AND code_hash = $2
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs(userID, "hash").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = New(db).UseRecoveryCode(context.TODO(), userID, "hash")
+	assert.Nil(t, err)
+}
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Records the time step of an accepted code.
+// Only moves forward, so a step can't be used twice even by concurrent requests.
+func (r PostgresRepository) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	query := `
+		UPDATE totp_secrets
+		SET last_used_step = $2, updated_at = $3
+		WHERE user_id = $1 AND confirmed_at IS NOT NULL AND last_used_step < $2
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	result, err := stmt.ExecContext(ctx, userID, step, time.Now())
+	if err != nil {
+		return err
+	}
+	return requireAffectedRows(result)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/pquerna/otp/totp"
+)
+
+// Generates a new TOTP secret for a user, pending until confirmed with a code.
+// Starting over replaces the pending secret, but never a confirmed one.
+func (s DefaultMFAService) BeginTOTPEnrollment(ctx context.Context, user *domain.User) (domain.TOTPEnrollment, error) {
+	if user == nil {
+		return domain.TOTPEnrollment{}, domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	existing, err := s.MFARepo.GetTOTPSecret(ctx, user.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return domain.TOTPEnrollment{}, err
+	}
+	if err == nil && existing.IsConfirmed() {
+		return domain.TOTPEnrollment{}, domain.ErrAlreadyExists
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.Settings.Issuer,
+		AccountName: user.Username,
+		Period:      totpOpts.Period,
+		Digits:      totpOpts.Digits,
+		Algorithm:   totpOpts.Algorithm,
+	})
+	if err != nil {
+		return domain.TOTPEnrollment{}, err
+	}
+
+	encrypted, err := s.encrypt(key.Secret())
+	if err != nil {
+		s.Logger.Printf("error encrypting the totp secret: %v\n", err)
+		return domain.TOTPEnrollment{}, err
+	}
+
+	err = s.MFARepo.SaveTOTPSecret(ctx, domain.TOTPSecret{
+		UserID:          user.ID,
+		EncryptedSecret: encrypted,
+	})
+	if err != nil {
+		return domain.TOTPEnrollment{}, err
+	}
+
+	return domain.TOTPEnrollment{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+	}, nil
+}
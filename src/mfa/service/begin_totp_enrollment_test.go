@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBeginTOTPEnrollment_NilUser(t *testing.T) {
+	res, err := newService(nil).BeginTOTPEnrollment(context.TODO(), nil)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	assert.Empty(t, res)
+}
+
+func TestBeginTOTPEnrollment_ErrorGettingSecret(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice"}
+	mfaRepo := new(mocks.MFARepository)
+	mfaRepo.On("GetTOTPSecret", mock.Anything, user.ID).Once().
+		Return(domain.TOTPSecret{}, errors.New("boom"))
+
+	res, err := newService(mfaRepo).BeginTOTPEnrollment(context.TODO(), user)
+	assert.Equal(t, "boom", err.Error())
+	assert.Empty(t, res)
+	mfaRepo.AssertExpectations(t)
+}
+
+func TestBeginTOTPEnrollment_AlreadyEnrolled(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice"}
+	mfaRepo := new(mocks.MFARepository)
+	mfaRepo.On("GetTOTPSecret", mock.Anything, user.ID).Once().
+		Return(domain.TOTPSecret{UserID: user.ID, ConfirmedAt: time.Now()}, nil)
+
+	res, err := newService(mfaRepo).BeginTOTPEnrollment(context.TODO(), user)
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+	assert.Empty(t, res)
+	mfaRepo.AssertExpectations(t)
+}
+
+func TestBeginTOTPEnrollment_ErrorSaving(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice"}
+	mfaRepo := new(mocks.MFARepository)
+	mfaRepo.On("GetTOTPSecret", mock.Anything, user.ID).Once().
+		Return(domain.TOTPSecret{}, sql.ErrNoRows)
+	mfaRepo.On("SaveTOTPSecret", mock.Anything, mock.AnythingOfType("domain.TOTPSecret")).Once().
+		Return(domain.ErrAlreadyExists)
+
+	res, err := newService(mfaRepo).BeginTOTPEnrollment(context.TODO(), user)
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+	assert.Empty(t, res)
+	mfaRepo.AssertExpectations(t)
+}
+
+func TestBeginTOTPEnrollment_Success(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice"}
+	service := newService(nil)
+	mfaRepo := new(mocks.MFARepository)
+	service.MFARepo = mfaRepo
+
+	var saved domain.TOTPSecret
+	mfaRepo.On("GetTOTPSecret", mock.Anything, user.ID).Once().
+		Return(domain.TOTPSecret{UserID: user.ID}, nil)
+	mfaRepo.On("SaveTOTPSecret", mock.Anything, mock.AnythingOfType("domain.TOTPSecret")).Once().
+		Run(func(args mock.Arguments) { saved = args.Get(1).(domain.TOTPSecret) }).
+		Return(nil)
+
+	res, err := service.BeginTOTPEnrollment(context.TODO(), user)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, res.Secret)
+
+	uri, err := url.Parse(res.URI)
+	assert.Nil(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Tests:alice", uri.Path)
+	assert.Equal(t, res.Secret, uri.Query().Get("secret"))
+	assert.Equal(t, "Tests", uri.Query().Get("issuer"))
+
+	// Only the encrypted secret is stored.
+	assert.Equal(t, user.ID, saved.UserID)
+	assert.NotContains(t, saved.EncryptedSecret, res.Secret)
+	decrypted, err := service.decrypt(saved.EncryptedSecret)
+	assert.Nil(t, err)
+	assert.Equal(t, res.Secret, decrypted)
+	mfaRepo.AssertExpectations(t)
+}
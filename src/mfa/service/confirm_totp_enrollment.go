@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Confirms a pending enrollment with a first code from the authenticator.
+// Returns the recovery codes in plain text, they can't be retrieved again.
+func (s DefaultMFAService) ConfirmTOTPEnrollment(ctx context.Context, user *domain.User, code string) ([]string, error) {
+	if user == nil || len(code) == 0 {
+		return nil, domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	secret, err := s.MFARepo.GetTOTPSecret(ctx, user.ID)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if secret.IsConfirmed() {
+		return nil, domain.ErrAlreadyExists
+	}
+
+	plainSecret, err := s.decrypt(secret.EncryptedSecret)
+	if err != nil {
+		s.Logger.Printf("error decrypting the totp secret: %v\n", err)
+		return nil, err
+	}
+
+	step, ok := matchingStep(plainSecret, code, time.Now())
+	if !ok {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if err = s.MFARepo.ConfirmTOTPSecret(ctx, user.ID, step); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, s.Settings.RecoveryCodesCount)
+	hashes := make([]string, 0, s.Settings.RecoveryCodesCount)
+	for i := 0; i < s.Settings.RecoveryCodesCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	if err = s.MFARepo.ReplaceRecoveryCodes(ctx, user.ID, hashes); err != nil {
+		s.Logger.Printf("error storing the recovery codes: %v\n", err)
+		return nil, err
+	}
+
+	return codes, nil
+}
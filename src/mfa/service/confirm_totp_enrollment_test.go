@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Returns a pending secret of the service with the test secret.
+func pendingSecret(t *testing.T, service DefaultMFAService, userID uuid.UUID) domain.TOTPSecret {
+	encrypted, err := service.encrypt(testSecret)
+	assert.Nil(t, err)
+	return domain.TOTPSecret{UserID: userID, EncryptedSecret: encrypted}
+}
+
+func TestConfirmTOTPEnrollment_InvalidInput(t *testing.T) {
+	service := newService(nil)
+
+	res, err := service.ConfirmTOTPEnrollment(context.TODO(), nil, "123456")
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	assert.Nil(t, res)
+
+	res, err = service.ConfirmTOTPEnrollment(context.TODO(), &domain.User{ID: uuid.New()}, "")
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	assert.Nil(t, res)
+}
+
+func TestConfirmTOTPEnrollment_NoPendingEnrollment(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	mfaRepo := new(mocks.MFARepository)
+	mfaRepo.On("GetTOTPSecret", mock.Anything, user.ID).Once().
+		Return(domain.TOTPSecret{}, sql.ErrNoRows)
+
+	res, err := newService(mfaRepo).ConfirmTOTPEnrollment(context.TODO(), user, "123456")
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.Nil(t, res)
+	mfaRepo.AssertExpectations(t)
+}
+
+func TestConfirmTOTPEnrollment_AlreadyConfirmed(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	mfaRepo := new(mocks.MFARepository)
+	mfaRepo.On("GetTOTPSecret", mock.Anything, user.ID).Once().
+		Return(domain.TOTPSecret{UserID: user.ID, ConfirmedAt: time.Now()}, nil)
+
+	res, err := newService(mfaRepo).ConfirmTOTPEnrollment(context.TODO(), user, "123456")
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+	assert.Nil(t, res)
+	mfaRepo.AssertExpectations(t)
+}
+
+func TestConfirmTOTPEnrollment_WrongCode(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	service := newService(nil)
+	mfaRepo := new(mocks.MFARepository)
+	service.MFARepo = mfaRepo
+
+	mfaRepo.On("GetTOTPSecret", mock.Anything, user.ID).Once().
+		Return(pendingSecret(t, service, user.ID), nil)
+
+	res, err := service.ConfirmTOTPEnrollment(context.TODO(), user, "not-a-code")
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	assert.Nil(t, res)
+	mfaRepo.AssertExpectations(t)
+}
+
+func TestConfirmTOTPEnrollment_ErrorStoringRecoveryCodes(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	service := newService(nil)
+	mfaRepo := new(mocks.MFARepository)
+	service.MFARepo = mfaRepo
+
+	code, err := totp.GenerateCode(testSecret, time.Now())
+	assert.Nil(t, err)
+
+	mfaRepo.On("GetTOTPSecret", mock.Anything, user.ID).Once().
+		Return(pendingSecret(t, service, user.ID), nil)
+	mfaRepo.On("ConfirmTOTPSecret", mock.Anything, user.ID, mock.AnythingOfType("int64")).Once().
+		Return(nil)
+	mfaRepo.On("ReplaceRecoveryCodes", mock.Anything, user.ID, mock.Anything).Once().
+		Return(errors.New("boom"))
+
+	res, err := service.ConfirmTOTPEnrollment(context.TODO(), user, code)
+	assert.Equal(t, "boom", err.Error())
+	assert.Nil(t, res)
+	mfaRepo.AssertExpectations(t)
+}
+
+func TestConfirmTOTPEnrollment_Success(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	service := newService(nil)
+	mfaRepo := new(mocks.MFARepository)
+	service.MFARepo = mfaRepo
+
+	now := time.Now()
+	code, err := totp.GenerateCode(testSecret, now)
+	assert.Nil(t, err)
+
+	var storedHashes []string
+	mfaRepo.On("GetTOTPSecret", mock.Anything, user.ID).Once().
+		Return(pendingSecret(t, service, user.ID), nil)
+	mfaRepo.On("ConfirmTOTPSecret", mock.Anything, user.ID, mock.AnythingOfType("int64")).Once().
+		Return(nil)
+	mfaRepo.On("ReplaceRecoveryCodes", mock.Anything, user.ID, mock.Anything).Once().
+		Run(func(args mock.Arguments) { storedHashes = args.Get(2).([]string) }).
+		Return(nil)
+
+	res, err := service.ConfirmTOTPEnrollment(context.TODO(), user, code)
+	assert.Nil(t, err)
+	assert.Len(t, res, 3)
+
+	// Only the hashes of the recovery codes are stored.
+	assert.Len(t, storedHashes, 3)
+	for i, recoveryCode := range res {
+		assert.Equal(t, hashRecoveryCode(recoveryCode), storedHashes[i])
+	}
+	mfaRepo.AssertExpectations(t)
+}
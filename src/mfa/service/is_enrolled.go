@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+// Checks if a user has confirmed a TOTP enrollment.
+func (s DefaultMFAService) IsEnrolled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	secret, err := s.MFARepo.GetTOTPSecret(ctx, userID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return secret.IsConfirmed(), nil
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIsEnrolled(t *testing.T) {
+	tests := []struct {
+		name        string
+		secret      domain.TOTPSecret
+		repoErr     error
+		wantedRes   bool
+		wantedError error
+	}{
+		{"not enrolled", domain.TOTPSecret{}, sql.ErrNoRows, false, nil},
+		{"pending enrollment", domain.TOTPSecret{EncryptedSecret: "encrypted"}, nil, false, nil},
+		{"enrolled", domain.TOTPSecret{EncryptedSecret: "encrypted", ConfirmedAt: time.Now()}, nil, true, nil},
+		{"repository error", domain.TOTPSecret{}, errors.New("boom"), false, errors.New("boom")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			userID := uuid.New()
+			mfaRepo := new(mocks.MFARepository)
+			mfaRepo.On("GetTOTPSecret", mock.Anything, userID).Once().Return(test.secret, test.repoErr)
+
+			res, err := newService(mfaRepo).IsEnrolled(context.TODO(), userID)
+			assert.Equal(t, test.wantedRes, res)
+			assert.Equal(t, test.wantedError, err)
+			mfaRepo.AssertExpectations(t)
+		})
+	}
+}
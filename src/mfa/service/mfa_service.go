@@ -0,0 +1,151 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+// Length of the TOTP time steps, in seconds.
+const totpPeriod int64 = 30
+
+// Amount of random bytes of a recovery code.
+const recoveryCodeBytes int = 10
+
+var totpOpts = totp.ValidateOpts{
+	Period:    uint(totpPeriod),
+	Digits:    otp.DigitsSix,
+	Algorithm: otp.AlgorithmSHA1,
+}
+
+type DefaultMFAService struct {
+	Logger         *log.Logger
+	MFARepo        domain.MFARepository
+	ContextTimeout time.Duration
+	Settings       domain.MFASettings
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	mfaRepo domain.MFARepository,
+	contextTimeout time.Duration,
+	settings domain.MFASettings,
+) domain.MFAService {
+	return DefaultMFAService{logger, mfaRepo, contextTimeout, settings}
+}
+
+// Instantiation for tests
+func newService(mfaRepo domain.MFARepository) DefaultMFAService {
+	return DefaultMFAService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		mfaRepo,
+		time.Duration(5 * time.Second),
+		domain.MFASettings{
+			Issuer:             "Tests",
+			EncryptionKey:      []byte("0123456789abcdef0123456789abcdef"),
+			RecoveryCodesCount: 3,
+		},
+	}
+}
+
+// Encrypts a TOTP secret with AES-GCM, prefixing the nonce.
+func (s DefaultMFAService) encrypt(plaintext string) (string, error) {
+	gcm, err := s.cipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypts a TOTP secret encrypted by encrypt.
+func (s DefaultMFAService) decrypt(encrypted string) (string, error) {
+	gcm, err := s.cipher()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("encrypted secret too short")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func (s DefaultMFAService) cipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.Settings.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Finds the time step a code was generated for.
+// Codes of the previous and next steps are accepted too, to allow some clock drift.
+func matchingStep(secret string, code string, now time.Time) (int64, bool) {
+	current := now.Unix() / totpPeriod
+	for _, step := range []int64{current, current - 1, current + 1} {
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*totpPeriod, 0), totpOpts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// Generates a random recovery code, like "abcd-efgh-ijkl-mnop".
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	encoded := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))
+	groups := make([]string, 0, len(encoded)/4)
+	for i := 0; i < len(encoded); i += 4 {
+		groups = append(groups, encoded[i:i+4])
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+// Hashes a recovery code, ignoring its casing and separators.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(code)
+	normalized = strings.NewReplacer("-", "", " ", "").Replace(normalized)
+
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSecret = "JBSWY3DPEHPK3PXP"
+
+func TestEncrypt_RoundTrip(t *testing.T) {
+	service := newService(nil)
+
+	encrypted, err := service.encrypt(testSecret)
+	assert.Nil(t, err)
+	assert.NotContains(t, encrypted, testSecret)
+
+	again, err := service.encrypt(testSecret)
+	assert.Nil(t, err)
+	assert.NotEqual(t, encrypted, again, "a new nonce is used every time")
+
+	decrypted, err := service.decrypt(encrypted)
+	assert.Nil(t, err)
+	assert.Equal(t, testSecret, decrypted)
+}
+
+func TestDecrypt_WrongKey(t *testing.T) {
+	service := newService(nil)
+	encrypted, err := service.encrypt(testSecret)
+	assert.Nil(t, err)
+
+	service.Settings.EncryptionKey = []byte("another key of thirty two bytes!")
+	_, err = service.decrypt(encrypted)
+	assert.Error(t, err)
+}
+
+func TestDecrypt_Malformed(t *testing.T) {
+	service := newService(nil)
+
+	_, err := service.decrypt("not base64!")
+	assert.Error(t, err)
+
+	_, err = service.decrypt("c2hvcnQ=")
+	assert.Error(t, err)
+}
+
+func TestEncrypt_InvalidKey(t *testing.T) {
+	service := newService(nil)
+	service.Settings.EncryptionKey = []byte("too short")
+
+	_, err := service.encrypt(testSecret)
+	assert.Error(t, err)
+}
+
+func TestMatchingStep(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	current := now.Unix() / totpPeriod
+
+	for _, offset := range []int64{-1, 0, 1} {
+		code, err := totp.GenerateCodeCustom(testSecret, time.Unix((current+offset)*totpPeriod, 0), totpOpts)
+		assert.Nil(t, err)
+
+		step, ok := matchingStep(testSecret, code, now)
+		assert.True(t, ok)
+		assert.Equal(t, current+offset, step)
+	}
+
+	code, err := totp.GenerateCodeCustom(testSecret, time.Unix((current-2)*totpPeriod, 0), totpOpts)
+	assert.Nil(t, err)
+	_, ok := matchingStep(testSecret, code, now)
+	assert.False(t, ok)
+
+	_, ok = matchingStep(testSecret, "", now)
+	assert.False(t, ok)
+}
+
+func TestGenerateRecoveryCode(t *testing.T) {
+	code, err := generateRecoveryCode()
+	assert.Nil(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[a-z2-7]{4}(-[a-z2-7]{4}){3}$`), code)
+
+	other, err := generateRecoveryCode()
+	assert.Nil(t, err)
+	assert.NotEqual(t, code, other)
+}
+
+func TestHashRecoveryCode_IgnoresFormatting(t *testing.T) {
+	hash := hashRecoveryCode("abcd-efgh-ijkl-mnop")
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, hashRecoveryCode("ABCD EFGH IJKL MNOP"))
+	assert.Equal(t, hash, hashRecoveryCode("abcdefghijklmnop"))
+	assert.NotEqual(t, hash, hashRecoveryCode("abcd-efgh-ijkl-mnoq"))
+}
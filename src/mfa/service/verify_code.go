@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Verifies a code from the authenticator of an enrolled user.
+// Every code can only be used once.
+func (s DefaultMFAService) VerifyCode(ctx context.Context, userID uuid.UUID, code string) error {
+	if len(code) == 0 {
+		return domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	secret, err := s.MFARepo.GetTOTPSecret(ctx, userID)
+	if err == sql.ErrNoRows || (err == nil && !secret.IsConfirmed()) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	plainSecret, err := s.decrypt(secret.EncryptedSecret)
+	if err != nil {
+		s.Logger.Printf("error decrypting the totp secret: %v\n", err)
+		return err
+	}
+
+	step, ok := matchingStep(plainSecret, code, time.Now())
+	if !ok || step <= secret.LastUsedStep {
+		return domain.ErrInvalidToken
+	}
+
+	err = s.MFARepo.UseTOTPStep(ctx, userID, step)
+	if err == domain.ErrNotFound {
+		return domain.ErrInvalidToken
+	}
+	return err
+}
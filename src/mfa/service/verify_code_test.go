@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Returns a confirmed secret of the service with the test secret.
+func confirmedSecret(t *testing.T, service DefaultMFAService, userID uuid.UUID, lastUsedStep int64) domain.TOTPSecret {
+	secret := pendingSecret(t, service, userID)
+	secret.ConfirmedAt = time.Now()
+	secret.LastUsedStep = lastUsedStep
+	return secret
+}
+
+func TestVerifyCode_EmptyCode(t *testing.T) {
+	err := newService(nil).VerifyCode(context.TODO(), uuid.New(), "")
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestVerifyCode_NotEnrolled(t *testing.T) {
+	userID := uuid.New()
+	mfaRepo := new(mocks.MFARepository)
+	mfaRepo.On("GetTOTPSecret", mock.Anything, userID).Once().Return(domain.TOTPSecret{}, sql.ErrNoRows)
+	mfaRepo.On("GetTOTPSecret", mock.Anything, userID).Once().Return(domain.TOTPSecret{UserID: userID}, nil)
+
+	service := newService(mfaRepo)
+	assert.Equal(t, domain.ErrNotFound, service.VerifyCode(context.TODO(), userID, "123456"))
+	assert.Equal(t, domain.ErrNotFound, service.VerifyCode(context.TODO(), userID, "123456"))
+	mfaRepo.AssertExpectations(t)
+}
+
+func TestVerifyCode_WrongCode(t *testing.T) {
+	userID := uuid.New()
+	service := newService(nil)
+	mfaRepo := new(mocks.MFARepository)
+	service.MFARepo = mfaRepo
+
+	mfaRepo.On("GetTOTPSecret", mock.Anything, userID).Once().
+		Return(confirmedSecret(t, service, userID, 0), nil)
+
+	err := service.VerifyCode(context.TODO(), userID, "000000x")
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	mfaRepo.AssertExpectations(t)
+}
+
+func TestVerifyCode_ReplayedCode(t *testing.T) {
+	userID := uuid.New()
+	service := newService(nil)
+	mfaRepo := new(mocks.MFARepository)
+	service.MFARepo = mfaRepo
+
+	now := time.Now()
+	code, err := totp.GenerateCode(testSecret, now)
+	assert.Nil(t, err)
+
+	// The code's step, or a later one, was already used.
+	mfaRepo.On("GetTOTPSecret", mock.Anything, userID).Once().
+		Return(confirmedSecret(t, service, userID, now.Unix()/totpPeriod+1), nil)
+
+	err = service.VerifyCode(context.TODO(), userID, code)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	mfaRepo.AssertExpectations(t)
+}
+
+func TestVerifyCode_ConcurrentUse(t *testing.T) {
+	userID := uuid.New()
+	service := newService(nil)
+	mfaRepo := new(mocks.MFARepository)
+	service.MFARepo = mfaRepo
+
+	code, err := totp.GenerateCode(testSecret, time.Now())
+	assert.Nil(t, err)
+
+	mfaRepo.On("GetTOTPSecret", mock.Anything, userID).Once().
+		Return(confirmedSecret(t, service, userID, 0), nil)
+	mfaRepo.On("UseTOTPStep", mock.Anything, userID, mock.AnythingOfType("int64")).Once().
+		Return(domain.ErrNotFound)
+
+	err = service.VerifyCode(context.TODO(), userID, code)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	mfaRepo.AssertExpectations(t)
+}
+
+func TestVerifyCode_Success(t *testing.T) {
+	userID := uuid.New()
+	service := newService(nil)
+	mfaRepo := new(mocks.MFARepository)
+	service.MFARepo = mfaRepo
+
+	code, err := totp.GenerateCode(testSecret, time.Now())
+	assert.Nil(t, err)
+
+	mfaRepo.On("GetTOTPSecret", mock.Anything, userID).Once().
+		Return(confirmedSecret(t, service, userID, 0), nil)
+	mfaRepo.On("UseTOTPStep", mock.Anything, userID, mock.AnythingOfType("int64")).Once().
+		Return(nil)
+
+	err = service.VerifyCode(context.TODO(), userID, code)
+	assert.Nil(t, err)
+	mfaRepo.AssertExpectations(t)
+}
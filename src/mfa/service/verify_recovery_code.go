@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Verifies a recovery code of a user, which can't be used again.
+func (s DefaultMFAService) VerifyRecoveryCode(ctx context.Context, userID uuid.UUID, code string) error {
+	if len(code) == 0 {
+		return domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	err := s.MFARepo.UseRecoveryCode(ctx, userID, hashRecoveryCode(code))
+	if err == domain.ErrNotFound {
+		return domain.ErrInvalidToken
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestVerifyRecoveryCode_EmptyCode(t *testing.T) {
+	err := newService(nil).VerifyRecoveryCode(context.TODO(), uuid.New(), "")
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestVerifyRecoveryCode_UnknownCode(t *testing.T) {
+	userID := uuid.New()
+	mfaRepo := new(mocks.MFARepository)
+	mfaRepo.On("UseRecoveryCode", mock.Anything, userID, hashRecoveryCode("abcd-efgh-ijkl-mnop")).Once().
+		Return(domain.ErrNotFound)
+
+	err := newService(mfaRepo).VerifyRecoveryCode(context.TODO(), userID, "abcd-efgh-ijkl-mnop")
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	mfaRepo.AssertExpectations(t)
+}
+
+func TestVerifyRecoveryCode_RepositoryError(t *testing.T) {
+	userID := uuid.New()
+	mfaRepo := new(mocks.MFARepository)
+	mfaRepo.On("UseRecoveryCode", mock.Anything, userID, mock.Anything).Once().
+		Return(errors.New("boom"))
+
+	err := newService(mfaRepo).VerifyRecoveryCode(context.TODO(), userID, "abcd-efgh-ijkl-mnop")
+	assert.Equal(t, "boom", err.Error())
+	mfaRepo.AssertExpectations(t)
+}
+
+func TestVerifyRecoveryCode_Success(t *testing.T) {
+	userID := uuid.New()
+	mfaRepo := new(mocks.MFARepository)
+	mfaRepo.On("UseRecoveryCode", mock.Anything, userID, hashRecoveryCode("abcd-efgh-ijkl-mnop")).Once().
+		Return(nil)
+
+	err := newService(mfaRepo).VerifyRecoveryCode(context.TODO(), userID, " ABCD-EFGH-IJKL-MNOP ")
+	assert.Nil(t, err)
+	mfaRepo.AssertExpectations(t)
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Scopes the one-time tokens to the organization of their users, so the users
+// are looked up there when the tokens are used.
+func AddOrganizationScope(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		ALTER TABLE one_time_tokens ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		UPDATE one_time_tokens t SET organization_id = u.organization_id
+		FROM users u
+		WHERE u.id = t.user_id AND t.organization_id <> u.organization_id;
+		ALTER TABLE one_time_tokens ALTER COLUMN organization_id DROP DEFAULT;
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewAddOrganizationScopeMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "add-one-time-tokens-organization-scope",
+		Up:   AddOrganizationScope,
+	}
+}
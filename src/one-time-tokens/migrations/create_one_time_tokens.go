@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Creates the one-time tokens table
+func CreateOneTimeTokensTable(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS one_time_tokens(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			purpose varchar(64) NOT NULL,
+			token_hash varchar(128) NOT NULL UNIQUE,
+			payload text NOT NULL DEFAULT '',
+			expires_at timestamptz NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewCreateOneTimeTokensMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "create-one-time-tokens-table",
+		Up:   CreateOneTimeTokensTable,
+	}
+}
@@ -0,0 +1,103 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateOneTimeTokens_FailExec(t *testing.T) {
+	migration := NewCreateOneTimeTokensMigration()
+	assert.Equal(t, migration.Name, "create-one-time-tokens-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS one_time_tokens(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			purpose varchar(64) NOT NULL,
+			token_hash varchar(128) NOT NULL UNIQUE,
+			payload text NOT NULL DEFAULT '',
+			expires_at timestamptz NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestCreateOneTimeTokens_TimeoutReached(t *testing.T) {
+	migration := NewCreateOneTimeTokensMigration()
+	assert.Equal(t, migration.Name, "create-one-time-tokens-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS one_time_tokens(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			purpose varchar(64) NOT NULL,
+			token_hash varchar(128) NOT NULL UNIQUE,
+			payload text NOT NULL DEFAULT '',
+			expires_at timestamptz NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestCreateOneTimeTokens_Success(t *testing.T) {
+	migration := NewCreateOneTimeTokensMigration()
+	assert.Equal(t, migration.Name, "create-one-time-tokens-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS one_time_tokens(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			purpose varchar(64) NOT NULL,
+			token_hash varchar(128) NOT NULL UNIQUE,
+			payload text NOT NULL DEFAULT '',
+			expires_at timestamptz NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(50 * time.Millisecond)).
+		WillReturnResult(sqlmock.NewResult(1, 1)).
+		WillReturnError(nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Deletes a token by its hash and returns it, so it can only be used once
+func (r PostgresRepository) Consume(ctx context.Context, purpose string, tokenHash string) (domain.OneTimeToken, error) {
+	query := `
+		DELETE FROM one_time_tokens
+		WHERE token_hash = $1 AND purpose = $2
+		RETURNING id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.OneTimeToken{}, err
+	}
+
+	row := stmt.QueryRowContext(ctx, tokenHash, purpose)
+	return r.scanOneTimeTokenRow(row)
+}
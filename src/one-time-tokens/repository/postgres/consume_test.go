@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsume_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		DELETE FROM one_time_tokens
+		WHERE token_hash = $1 AND purpose = $2
+		RETURNING id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).Consume(context.TODO(), domain.TokenPurposeEmailVerification, "hash")
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestConsume_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		DELETE FROM one_time_tokens
+		WHERE token_hash = $1 AND purpose = $2
+		RETURNING id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs("hash", domain.TokenPurposeEmailVerification).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).Consume(ctx, domain.TokenPurposeEmailVerification, "hash")
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestConsume_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		DELETE FROM one_time_tokens
+		WHERE token_hash = $1 AND purpose = $2
+		RETURNING id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs("hash", domain.TokenPurposeEmailVerification).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "purpose", "token_hash", "payload", "expires_at", "created_at", "organization_id"}))
+
+	res, err := New(db).Consume(context.TODO(), domain.TokenPurposeEmailVerification, "hash")
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Empty(t, res)
+}
+
+func TestConsume_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	token := domain.OneTimeToken{
+		ID:             uuid.New(),
+		UserID:         uuid.New(),
+		Purpose:        domain.TokenPurposeEmailVerification,
+		TokenHash:      "hash",
+		Payload:        "alice@example.com",
+		ExpiresAt:      time.Now().Add(time.Hour),
+		CreatedAt:      time.Now(),
+		OrganizationID: domain.DefaultOrganizationID,
+	}
+
+	query := `
+		DELETE FROM one_time_tokens
+		WHERE token_hash = $1 AND purpose = $2
+		RETURNING id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id
+	`
+	expectedResult := sqlmock.NewRows(
+		[]string{"id", "user_id", "purpose", "token_hash", "payload", "expires_at", "created_at", "organization_id"},
+	).AddRow(token.ID, token.UserID, token.Purpose, token.TokenHash, token.Payload, token.ExpiresAt, token.CreatedAt, token.OrganizationID)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs("hash", domain.TokenPurposeEmailVerification).
+		WillReturnRows(expectedResult)
+
+	res, err := New(db).Consume(context.TODO(), domain.TokenPurposeEmailVerification, "hash")
+	assert.Nil(t, err)
+	assert.Equal(t, token, res)
+}
@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Counts the tokens of a user for a purpose created since a given time
+func (r PostgresRepository) CountByUserSince(ctx context.Context, userID uuid.UUID, purpose string, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM one_time_tokens
+		WHERE user_id = $1 AND purpose = $2 AND created_at >= $3
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = stmt.QueryRowContext(ctx, userID, purpose, since).Scan(&count)
+	return count, err
+}
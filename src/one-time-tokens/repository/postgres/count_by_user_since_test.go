@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountByUserSince_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		SELECT COUNT(*)
+		FROM one_time_tokens
+		WHERE user_id = $1 AND purpose = $2 AND created_at >= $3
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	count, err := New(db).CountByUserSince(context.TODO(), uuid.New(), domain.TokenPurposePasswordReset, time.Now())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Equal(t, 0, count)
+}
+
+func TestCountByUserSince_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	since := time.Now()
+	query := `
+		SELECT COUNT(*)
+		FROM one_time_tokens
+		WHERE user_id = $1 AND purpose = $2 AND created_at >= $3
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs(userID, domain.TokenPurposePasswordReset, since).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	count, err := New(db).CountByUserSince(ctx, userID, domain.TokenPurposePasswordReset, since)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Equal(t, 0, count)
+}
+
+func TestCountByUserSince_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	since := time.Now()
+	query := `
+		SELECT COUNT(*)
+		FROM one_time_tokens
+		WHERE user_id = $1 AND purpose = $2 AND created_at >= $3
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs(userID, domain.TokenPurposePasswordReset, since).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	count, err := New(db).CountByUserSince(context.TODO(), userID, domain.TokenPurposePasswordReset, since)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+}
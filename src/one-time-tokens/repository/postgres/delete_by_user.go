@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Deletes all the tokens of a user for a purpose
+func (r PostgresRepository) DeleteByUser(ctx context.Context, userID uuid.UUID, purpose string) error {
+	query := `
+		DELETE FROM one_time_tokens
+		WHERE user_id = $1 AND purpose = $2
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, userID, purpose)
+	return err
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteByUser_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		DELETE FROM one_time_tokens
+		WHERE user_id = $1 AND purpose = $2
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = New(db).DeleteByUser(context.TODO(), uuid.New(), domain.TokenPurposeEmailVerification)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestDeleteByUser_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	query := `
+		DELETE FROM one_time_tokens
+		WHERE user_id = $1 AND purpose = $2
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs(userID, domain.TokenPurposeEmailVerification).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = New(db).DeleteByUser(ctx, userID, domain.TokenPurposeEmailVerification)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestDeleteByUser_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	query := `
+		DELETE FROM one_time_tokens
+		WHERE user_id = $1 AND purpose = $2
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs(userID, domain.TokenPurposeEmailVerification).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = New(db).DeleteByUser(context.TODO(), userID, domain.TokenPurposeEmailVerification)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const getByHashQuery = `
+		SELECT id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id
+		FROM one_time_tokens
+		WHERE token_hash = $1 AND purpose = $2
+	`
+
+func TestGetByHash_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getByHashQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).GetByHash(context.TODO(), domain.TokenPurposeInvitation, "hash")
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestGetByHash_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getByHashQuery)).
+		ExpectQuery().
+		WithArgs("hash", domain.TokenPurposeInvitation).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "purpose", "token_hash", "payload", "expires_at", "created_at", "organization_id"}))
+
+	res, err := New(db).GetByHash(context.TODO(), domain.TokenPurposeInvitation, "hash")
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Empty(t, res)
+}
+
+func TestGetByHash_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	token := domain.OneTimeToken{
+		ID:             uuid.New(),
+		UserID:         uuid.New(),
+		Purpose:        domain.TokenPurposeInvitation,
+		TokenHash:      "hash",
+		ExpiresAt:      time.Now().Add(time.Hour),
+		CreatedAt:      time.Now(),
+		OrganizationID: domain.DefaultOrganizationID,
+	}
+	rows := sqlmock.NewRows(
+		[]string{"id", "user_id", "purpose", "token_hash", "payload", "expires_at", "created_at", "organization_id"},
+	).AddRow(token.ID, token.UserID, token.Purpose, token.TokenHash, token.Payload, token.ExpiresAt, token.CreatedAt, token.OrganizationID)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getByHashQuery)).
+		ExpectQuery().
+		WithArgs("hash", domain.TokenPurposeInvitation).
+		WillReturnRows(rows)
+
+	res, err := New(db).GetByHash(context.TODO(), domain.TokenPurposeInvitation, "hash")
+	assert.Nil(t, err)
+	assert.Equal(t, token, res)
+}
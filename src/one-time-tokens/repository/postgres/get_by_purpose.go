@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets the tokens for a purpose that weren't used yet, of the users of the organization.
+func (r PostgresRepository) GetByPurpose(ctx context.Context, purpose string) ([]domain.OneTimeToken, error) {
+	result := make([]domain.OneTimeToken, 0)
+
+	query := `
+		SELECT t.id, t.user_id, t.purpose, t.token_hash, t.payload, t.expires_at, t.created_at, t.organization_id
+		FROM one_time_tokens t
+		INNER JOIN users u ON u.id = t.user_id
+		WHERE t.purpose = $1 AND u.organization_id = $2 AND u.deleted_at IS NULL
+		ORDER BY t.created_at
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return result, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, purpose, domain.OrganizationFromContext(ctx))
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		token, err := r.scanOneTimeTokenRow(rows)
+		if err != nil {
+			return make([]domain.OneTimeToken, 0), err
+		}
+		result = append(result, token)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const getByPurposeQuery = `
+		SELECT t.id, t.user_id, t.purpose, t.token_hash, t.payload, t.expires_at, t.created_at, t.organization_id
+		FROM one_time_tokens t
+		INNER JOIN users u ON u.id = t.user_id
+		WHERE t.purpose = $1 AND u.organization_id = $2 AND u.deleted_at IS NULL
+		ORDER BY t.created_at
+	`
+
+func TestGetByPurpose_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getByPurposeQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).GetByPurpose(context.TODO(), domain.TokenPurposeInvitation)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestGetByPurpose_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getByPurposeQuery)).
+		ExpectQuery().
+		WithArgs(domain.TokenPurposeInvitation, domain.DefaultOrganizationID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).GetByPurpose(ctx, domain.TokenPurposeInvitation)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestGetByPurpose_ErrorScanning(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getByPurposeQuery)).
+		ExpectQuery().
+		WithArgs(domain.TokenPurposeInvitation, domain.DefaultOrganizationID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+
+	res, err := New(db).GetByPurpose(context.TODO(), domain.TokenPurposeInvitation)
+	assert.Error(t, err)
+	assert.Empty(t, res)
+}
+
+func TestGetByPurpose_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	tokenID := uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+	createdAt := time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(getByPurposeQuery)).
+		ExpectQuery().
+		WithArgs(domain.TokenPurposeInvitation, domain.DefaultOrganizationID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "purpose", "token_hash", "payload", "expires_at", "created_at", "organization_id"}).
+			AddRow(tokenID, userID, domain.TokenPurposeInvitation, "hash", "", expiresAt, createdAt, domain.DefaultOrganizationID))
+
+	res, err := New(db).GetByPurpose(context.TODO(), domain.TokenPurposeInvitation)
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.OneTimeToken{{
+		ID:             tokenID,
+		UserID:         userID,
+		Purpose:        domain.TokenPurposeInvitation,
+		TokenHash:      "hash",
+		ExpiresAt:      expiresAt,
+		CreatedAt:      createdAt,
+		OrganizationID: domain.DefaultOrganizationID,
+	}}, res)
+}
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets all the tokens issued to a user that weren't used yet.
+func (r PostgresRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]domain.OneTimeToken, error) {
+	result := make([]domain.OneTimeToken, 0)
+
+	query := `
+		SELECT id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id
+		FROM one_time_tokens
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return result, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, userID)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		token, err := r.scanOneTimeTokenRow(rows)
+		if err != nil {
+			return make([]domain.OneTimeToken, 0), err
+		}
+		result = append(result, token)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const getByUserQuery = `
+		SELECT id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id
+		FROM one_time_tokens
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+func TestGetByUser_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getByUserQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).GetByUser(context.TODO(), uuid.New())
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestGetByUser_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(getByUserQuery)).
+		ExpectQuery().
+		WithArgs(userID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).GetByUser(ctx, userID)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestGetByUser_ErrorScanning(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(getByUserQuery)).
+		ExpectQuery().
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+
+	res, err := New(db).GetByUser(context.TODO(), userID)
+	assert.Error(t, err)
+	assert.Empty(t, res)
+}
+
+func TestGetByUser_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	tokenID := uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+	createdAt := time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(getByUserQuery)).
+		ExpectQuery().
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "purpose", "token_hash", "payload", "expires_at", "created_at", "organization_id"}).
+			AddRow(tokenID, userID, domain.TokenPurposeEmailVerification, "hash", "user@example.com", expiresAt, createdAt, domain.DefaultOrganizationID))
+
+	res, err := New(db).GetByUser(context.TODO(), userID)
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.OneTimeToken{{
+		ID:             tokenID,
+		UserID:         userID,
+		Purpose:        domain.TokenPurposeEmailVerification,
+		TokenHash:      "hash",
+		Payload:        "user@example.com",
+		ExpiresAt:      expiresAt,
+		CreatedAt:      createdAt,
+		OrganizationID: domain.DefaultOrganizationID,
+	}}, res)
+}
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type PostgresRepository struct {
+	Db *sql.DB
+}
+
+func New(db *sql.DB) domain.OneTimeTokenRepository {
+	return PostgresRepository{db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans a one-time token row
+func (r PostgresRepository) scanOneTimeTokenRow(row rowScanner) (domain.OneTimeToken, error) {
+	result := domain.OneTimeToken{}
+
+	err := row.Scan(
+		&result.ID,
+		&result.UserID,
+		&result.Purpose,
+		&result.TokenHash,
+		&result.Payload,
+		&result.ExpiresAt,
+		&result.CreatedAt,
+		&result.OrganizationID,
+	)
+	if err != nil {
+		return domain.OneTimeToken{}, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Stores a one-time token into the DB
+func (r PostgresRepository) Store(ctx context.Context, token domain.OneTimeToken) (domain.OneTimeToken, error) {
+	query := `
+		INSERT INTO one_time_tokens(id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.OneTimeToken{}, err
+	}
+
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	if token.OrganizationID == uuid.Nil {
+		token.OrganizationID = domain.OrganizationFromContext(ctx)
+	}
+
+	row := stmt.QueryRowContext(ctx,
+		token.ID,
+		token.UserID,
+		token.Purpose,
+		token.TokenHash,
+		token.Payload,
+		token.ExpiresAt,
+		token.CreatedAt,
+		token.OrganizationID,
+	)
+	return r.scanOneTimeTokenRow(row)
+}
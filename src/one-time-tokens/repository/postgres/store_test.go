@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		INSERT INTO one_time_tokens(id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).Store(context.TODO(), domain.OneTimeToken{})
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestStore_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	token := domain.OneTimeToken{
+		ID:             uuid.New(),
+		UserID:         uuid.New(),
+		Purpose:        domain.TokenPurposeEmailVerification,
+		TokenHash:      "hash",
+		Payload:        "alice@example.com",
+		ExpiresAt:      time.Now().Add(time.Hour),
+		CreatedAt:      time.Now(),
+		OrganizationID: domain.DefaultOrganizationID,
+	}
+
+	query := `
+		INSERT INTO one_time_tokens(id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs(token.ID, token.UserID, token.Purpose, token.TokenHash, token.Payload, token.ExpiresAt, token.CreatedAt, token.OrganizationID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).Store(ctx, token)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestStore_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	token := domain.OneTimeToken{
+		ID:             uuid.New(),
+		UserID:         uuid.New(),
+		Purpose:        domain.TokenPurposeEmailVerification,
+		TokenHash:      "hash",
+		Payload:        "alice@example.com",
+		ExpiresAt:      time.Now().Add(time.Hour),
+		CreatedAt:      time.Now(),
+		OrganizationID: domain.DefaultOrganizationID,
+	}
+
+	query := `
+		INSERT INTO one_time_tokens(id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id
+	`
+	expectedResult := sqlmock.NewRows(
+		[]string{"id", "user_id", "purpose", "token_hash", "payload", "expires_at", "created_at", "organization_id"},
+	).AddRow(token.ID, token.UserID, token.Purpose, token.TokenHash, token.Payload, token.ExpiresAt, token.CreatedAt, token.OrganizationID)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs(token.ID, token.UserID, token.Purpose, token.TokenHash, token.Payload, token.ExpiresAt, token.CreatedAt, token.OrganizationID).
+		WillReturnRows(expectedResult)
+
+	res, err := New(db).Store(context.TODO(), token)
+	assert.Nil(t, err)
+	assert.Equal(t, token, res)
+}
+
+func TestStore_OrganizationFromContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	organizationID := uuid.New()
+	token := domain.OneTimeToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Purpose:   domain.TokenPurposePasswordReset,
+		TokenHash: "hash",
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+	}
+
+	query := `
+		INSERT INTO one_time_tokens(id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, user_id, purpose, token_hash, payload, expires_at, created_at, organization_id
+	`
+	expectedResult := sqlmock.NewRows(
+		[]string{"id", "user_id", "purpose", "token_hash", "payload", "expires_at", "created_at", "organization_id"},
+	).AddRow(token.ID, token.UserID, token.Purpose, token.TokenHash, token.Payload, token.ExpiresAt, token.CreatedAt, organizationID)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs(token.ID, token.UserID, token.Purpose, token.TokenHash, token.Payload, token.ExpiresAt, token.CreatedAt, organizationID).
+		WillReturnRows(expectedResult)
+
+	res, err := New(db).Store(domain.WithOrganization(context.TODO(), organizationID), token)
+	assert.Nil(t, err)
+	assert.Equal(t, organizationID, res.OrganizationID)
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Uses a token, which can't be used again afterwards.
+// Unknown and expired tokens are invalid.
+func (s DefaultOneTimeTokenService) Consume(ctx context.Context, purpose string, token string) (domain.OneTimeToken, error) {
+	if len(purpose) == 0 || len(token) == 0 {
+		return domain.OneTimeToken{}, domain.ErrInvalidToken
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	result, err := s.TokenRepo.Consume(ctx, purpose, hashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.OneTimeToken{}, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return domain.OneTimeToken{}, err
+	}
+
+	if !result.ExpiresAt.After(time.Now()) {
+		return domain.OneTimeToken{}, domain.ErrInvalidToken
+	}
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestConsume_EmptyToken(t *testing.T) {
+	res, err := newService(nil).Consume(context.TODO(), domain.TokenPurposeEmailVerification, "")
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	assert.Empty(t, res)
+}
+
+func TestConsume_UnknownToken(t *testing.T) {
+	tokenRepo := new(mocks.OneTimeTokenRepository)
+	tokenRepo.On("Consume", mock.Anything, domain.TokenPurposeEmailVerification, hashToken("token")).
+		Once().Return(domain.OneTimeToken{}, sql.ErrNoRows)
+
+	res, err := newService(tokenRepo).Consume(context.TODO(), domain.TokenPurposeEmailVerification, "token")
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	assert.Empty(t, res)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestConsume_ErrorIfRepoFails(t *testing.T) {
+	tokenRepo := new(mocks.OneTimeTokenRepository)
+	tokenRepo.On("Consume", mock.Anything, domain.TokenPurposeEmailVerification, hashToken("token")).
+		Once().Return(domain.OneTimeToken{}, errors.New("boom"))
+
+	res, err := newService(tokenRepo).Consume(context.TODO(), domain.TokenPurposeEmailVerification, "token")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	assert.Empty(t, res)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestConsume_ExpiredToken(t *testing.T) {
+	tokenRepo := new(mocks.OneTimeTokenRepository)
+	tokenRepo.On("Consume", mock.Anything, domain.TokenPurposeEmailVerification, hashToken("token")).
+		Once().Return(domain.OneTimeToken{
+		UserID:    uuid.New(),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}, nil)
+
+	res, err := newService(tokenRepo).Consume(context.TODO(), domain.TokenPurposeEmailVerification, "token")
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	assert.Empty(t, res)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestConsume_Success(t *testing.T) {
+	stored := domain.OneTimeToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Purpose:   domain.TokenPurposeEmailVerification,
+		TokenHash: hashToken("token"),
+		Payload:   "alice@example.com",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	tokenRepo := new(mocks.OneTimeTokenRepository)
+	tokenRepo.On("Consume", mock.Anything, domain.TokenPurposeEmailVerification, hashToken("token")).
+		Once().Return(stored, nil)
+
+	res, err := newService(tokenRepo).Consume(context.TODO(), domain.TokenPurposeEmailVerification, "token")
+	assert.Nil(t, err)
+	assert.Equal(t, stored, res)
+	tokenRepo.AssertExpectations(t)
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Counts the tokens issued to a user for a purpose since a given time.
+// Used to rate limit the tokens sent to a user.
+func (s DefaultOneTimeTokenService) CountIssuedSince(ctx context.Context, userID uuid.UUID, purpose string, since time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	return s.TokenRepo.CountByUserSince(ctx, userID, purpose, since)
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCountIssuedSince_ErrorIfRepoFails(t *testing.T) {
+	userID := uuid.New()
+	since := time.Now().Add(-time.Hour)
+
+	tokenRepo := new(mocks.OneTimeTokenRepository)
+	tokenRepo.On("CountByUserSince", mock.Anything, userID, domain.TokenPurposePasswordReset, since).
+		Once().Return(0, errors.New("boom"))
+
+	count, err := newService(tokenRepo).CountIssuedSince(context.TODO(), userID, domain.TokenPurposePasswordReset, since)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	assert.Equal(t, 0, count)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestCountIssuedSince_Success(t *testing.T) {
+	userID := uuid.New()
+	since := time.Now().Add(-time.Hour)
+
+	tokenRepo := new(mocks.OneTimeTokenRepository)
+	tokenRepo.On("CountByUserSince", mock.Anything, userID, domain.TokenPurposePasswordReset, since).
+		Once().Return(3, nil)
+
+	count, err := newService(tokenRepo).CountIssuedSince(context.TODO(), userID, domain.TokenPurposePasswordReset, since)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+	tokenRepo.AssertExpectations(t)
+}
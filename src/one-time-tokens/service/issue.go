@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Issues a new token for a user, returning it in plain text.
+func (s DefaultOneTimeTokenService) Issue(
+	ctx context.Context,
+	userID uuid.UUID,
+	purpose string,
+	payload string,
+	ttl time.Duration,
+) (string, error) {
+	if userID == uuid.Nil || len(purpose) == 0 || ttl <= 0 {
+		return "", domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	token, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = s.TokenRepo.Store(ctx, domain.OneTimeToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		Payload:   payload,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		s.Logger.Printf("error storing %s token: %v\n", purpose, err)
+		return "", err
+	}
+
+	return token, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIssue_InvalidInput(t *testing.T) {
+	s := newService(nil)
+
+	token, err := s.Issue(context.TODO(), uuid.Nil, domain.TokenPurposeEmailVerification, "", time.Hour)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	assert.Empty(t, token)
+
+	token, err = s.Issue(context.TODO(), uuid.New(), "", "", time.Hour)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	assert.Empty(t, token)
+
+	token, err = s.Issue(context.TODO(), uuid.New(), domain.TokenPurposeEmailVerification, "", 0)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	assert.Empty(t, token)
+}
+
+func TestIssue_ErrorIfRepoFails(t *testing.T) {
+	tokenRepo := new(mocks.OneTimeTokenRepository)
+	tokenRepo.On("Store", mock.Anything, mock.AnythingOfType("domain.OneTimeToken")).
+		Once().Return(domain.OneTimeToken{}, errors.New("boom"))
+
+	token, err := newService(tokenRepo).
+		Issue(context.TODO(), uuid.New(), domain.TokenPurposeEmailVerification, "", time.Hour)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	assert.Empty(t, token)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestIssue_Success(t *testing.T) {
+	userID := uuid.New()
+	var stored domain.OneTimeToken
+
+	tokenRepo := new(mocks.OneTimeTokenRepository)
+	tokenRepo.On("Store", mock.Anything, mock.AnythingOfType("domain.OneTimeToken")).
+		Once().
+		Run(func(args mock.Arguments) {
+			stored = args.Get(1).(domain.OneTimeToken)
+		}).
+		Return(domain.OneTimeToken{}, nil)
+
+	token, err := newService(tokenRepo).
+		Issue(context.TODO(), userID, domain.TokenPurposeEmailVerification, "alice@example.com", time.Hour)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, token)
+
+	// Only the hash of the token is stored.
+	assert.Equal(t, hashToken(token), stored.TokenHash)
+	assert.NotEqual(t, token, stored.TokenHash)
+	assert.Equal(t, userID, stored.UserID)
+	assert.Equal(t, domain.TokenPurposeEmailVerification, stored.Purpose)
+	assert.Equal(t, "alice@example.com", stored.Payload)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresAt, time.Minute)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestIssue_TokensAreUnique(t *testing.T) {
+	tokenRepo := new(mocks.OneTimeTokenRepository)
+	tokenRepo.On("Store", mock.Anything, mock.AnythingOfType("domain.OneTimeToken")).
+		Return(domain.OneTimeToken{}, nil)
+
+	s := newService(tokenRepo)
+	first, _ := s.Issue(context.TODO(), uuid.New(), domain.TokenPurposeEmailVerification, "", time.Hour)
+	second, _ := s.Issue(context.TODO(), uuid.New(), domain.TokenPurposeEmailVerification, "", time.Hour)
+	assert.NotEqual(t, first, second)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Amount of random bytes of a token
+const tokenBytes int = 32
+
+type DefaultOneTimeTokenService struct {
+	Logger         *log.Logger
+	TokenRepo      domain.OneTimeTokenRepository
+	ContextTimeout time.Duration
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	tokenRepo domain.OneTimeTokenRepository,
+	contextTimeout time.Duration,
+) domain.OneTimeTokenService {
+	return DefaultOneTimeTokenService{logger, tokenRepo, contextTimeout}
+}
+
+// Instantiation for tests
+func newService(tokenRepo domain.OneTimeTokenRepository) DefaultOneTimeTokenService {
+	return DefaultOneTimeTokenService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		tokenRepo,
+		time.Duration(5 * time.Second),
+	}
+}
+
+// Generates a random url-safe token
+func generateToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hashes a token, so it isn't stored in plain text
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPeek_EmptyToken(t *testing.T) {
+	res, err := newService(nil).Peek(context.TODO(), domain.TokenPurposeInvitation, "")
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	assert.Empty(t, res)
+}
+
+func TestPeek_UnknownToken(t *testing.T) {
+	tokenRepo := new(mocks.OneTimeTokenRepository)
+	tokenRepo.On("GetByHash", mock.Anything, domain.TokenPurposeInvitation, hashToken("token")).
+		Once().Return(domain.OneTimeToken{}, sql.ErrNoRows)
+
+	res, err := newService(tokenRepo).Peek(context.TODO(), domain.TokenPurposeInvitation, "token")
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	assert.Empty(t, res)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestPeek_ExpiredToken(t *testing.T) {
+	tokenRepo := new(mocks.OneTimeTokenRepository)
+	tokenRepo.On("GetByHash", mock.Anything, domain.TokenPurposeInvitation, hashToken("token")).
+		Once().Return(domain.OneTimeToken{UserID: uuid.New(), ExpiresAt: time.Now().Add(-time.Minute)}, nil)
+
+	res, err := newService(tokenRepo).Peek(context.TODO(), domain.TokenPurposeInvitation, "token")
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	assert.Empty(t, res)
+}
+
+func TestPeek_Success(t *testing.T) {
+	token := domain.OneTimeToken{ID: uuid.New(), UserID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour)}
+	tokenRepo := new(mocks.OneTimeTokenRepository)
+	tokenRepo.On("GetByHash", mock.Anything, domain.TokenPurposeInvitation, hashToken("token")).
+		Once().Return(token, nil)
+
+	// The token isn't used up.
+	res, err := newService(tokenRepo).Peek(context.TODO(), domain.TokenPurposeInvitation, "token")
+	assert.Nil(t, err)
+	assert.Equal(t, token, res)
+	tokenRepo.AssertNotCalled(t, "Consume", mock.Anything, mock.Anything, mock.Anything)
+}
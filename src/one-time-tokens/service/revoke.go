@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Revokes all the tokens of a user for a purpose.
+func (s DefaultOneTimeTokenService) Revoke(ctx context.Context, userID uuid.UUID, purpose string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	return s.TokenRepo.DeleteByUser(ctx, userID, purpose)
+}
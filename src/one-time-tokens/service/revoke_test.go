@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRevoke_ErrorIfRepoFails(t *testing.T) {
+	userID := uuid.New()
+
+	tokenRepo := new(mocks.OneTimeTokenRepository)
+	tokenRepo.On("DeleteByUser", mock.Anything, userID, domain.TokenPurposeEmailVerification).
+		Once().Return(errors.New("boom"))
+
+	err := newService(tokenRepo).Revoke(context.TODO(), userID, domain.TokenPurposeEmailVerification)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestRevoke_Success(t *testing.T) {
+	userID := uuid.New()
+
+	tokenRepo := new(mocks.OneTimeTokenRepository)
+	tokenRepo.On("DeleteByUser", mock.Anything, userID, domain.TokenPurposeEmailVerification).
+		Once().Return(nil)
+
+	err := newService(tokenRepo).Revoke(context.TODO(), userID, domain.TokenPurposeEmailVerification)
+	assert.Nil(t, err)
+	tokenRepo.AssertExpectations(t)
+}
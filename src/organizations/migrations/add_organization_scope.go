@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Scopes the roles, users, refresh tokens and groups to an organization, moving the existing
+// ones into the default organization. Usernames, emails, role slugs and group names
+// become unique per organization.
+func AddOrganizationScope(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		ALTER TABLE roles ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		ALTER TABLE roles ALTER COLUMN organization_id DROP DEFAULT;
+		ALTER TABLE roles DROP CONSTRAINT IF EXISTS roles_role_slug_key;
+		ALTER TABLE roles DROP CONSTRAINT IF EXISTS roles_role_label_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS roles_organization_id_role_slug_key ON roles (organization_id, role_slug);
+		CREATE UNIQUE INDEX IF NOT EXISTS roles_organization_id_role_label_key ON roles (organization_id, role_label);
+
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		ALTER TABLE users ALTER COLUMN organization_id DROP DEFAULT;
+		ALTER TABLE users DROP CONSTRAINT IF EXISTS users_username_key;
+		DROP INDEX IF EXISTS users_normalized_username_key;
+		DROP INDEX IF EXISTS users_email_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS users_organization_id_normalized_username_key ON users (organization_id, normalized_username);
+		CREATE UNIQUE INDEX IF NOT EXISTS users_organization_id_email_key ON users (organization_id, lower(email)) WHERE email <> '';
+
+		ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		ALTER TABLE refresh_tokens ALTER COLUMN organization_id DROP DEFAULT;
+
+		ALTER TABLE groups ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		ALTER TABLE groups ALTER COLUMN organization_id DROP DEFAULT;
+		ALTER TABLE groups DROP CONSTRAINT IF EXISTS groups_normalized_name_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS groups_organization_id_normalized_name_key ON groups (organization_id, normalized_name);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewAddOrganizationScopeMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "add-organization-scope",
+		Up:   AddOrganizationScope,
+	}
+}
@@ -0,0 +1,146 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddOrganizationScope_FailExec(t *testing.T) {
+	migration := NewAddOrganizationScopeMigration()
+	assert.Equal(t, migration.Name, "add-organization-scope")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		ALTER TABLE roles ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		ALTER TABLE roles ALTER COLUMN organization_id DROP DEFAULT;
+		ALTER TABLE roles DROP CONSTRAINT IF EXISTS roles_role_slug_key;
+		ALTER TABLE roles DROP CONSTRAINT IF EXISTS roles_role_label_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS roles_organization_id_role_slug_key ON roles (organization_id, role_slug);
+		CREATE UNIQUE INDEX IF NOT EXISTS roles_organization_id_role_label_key ON roles (organization_id, role_label);
+
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		ALTER TABLE users ALTER COLUMN organization_id DROP DEFAULT;
+		ALTER TABLE users DROP CONSTRAINT IF EXISTS users_username_key;
+		DROP INDEX IF EXISTS users_normalized_username_key;
+		DROP INDEX IF EXISTS users_email_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS users_organization_id_normalized_username_key ON users (organization_id, normalized_username);
+		CREATE UNIQUE INDEX IF NOT EXISTS users_organization_id_email_key ON users (organization_id, lower(email)) WHERE email <> '';
+
+		ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		ALTER TABLE refresh_tokens ALTER COLUMN organization_id DROP DEFAULT;
+
+		ALTER TABLE groups ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		ALTER TABLE groups ALTER COLUMN organization_id DROP DEFAULT;
+		ALTER TABLE groups DROP CONSTRAINT IF EXISTS groups_normalized_name_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS groups_organization_id_normalized_name_key ON groups (organization_id, normalized_name);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestAddOrganizationScope_TimeoutReached(t *testing.T) {
+	migration := NewAddOrganizationScopeMigration()
+	assert.Equal(t, migration.Name, "add-organization-scope")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		ALTER TABLE roles ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		ALTER TABLE roles ALTER COLUMN organization_id DROP DEFAULT;
+		ALTER TABLE roles DROP CONSTRAINT IF EXISTS roles_role_slug_key;
+		ALTER TABLE roles DROP CONSTRAINT IF EXISTS roles_role_label_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS roles_organization_id_role_slug_key ON roles (organization_id, role_slug);
+		CREATE UNIQUE INDEX IF NOT EXISTS roles_organization_id_role_label_key ON roles (organization_id, role_label);
+
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		ALTER TABLE users ALTER COLUMN organization_id DROP DEFAULT;
+		ALTER TABLE users DROP CONSTRAINT IF EXISTS users_username_key;
+		DROP INDEX IF EXISTS users_normalized_username_key;
+		DROP INDEX IF EXISTS users_email_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS users_organization_id_normalized_username_key ON users (organization_id, normalized_username);
+		CREATE UNIQUE INDEX IF NOT EXISTS users_organization_id_email_key ON users (organization_id, lower(email)) WHERE email <> '';
+
+		ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		ALTER TABLE refresh_tokens ALTER COLUMN organization_id DROP DEFAULT;
+
+		ALTER TABLE groups ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		ALTER TABLE groups ALTER COLUMN organization_id DROP DEFAULT;
+		ALTER TABLE groups DROP CONSTRAINT IF EXISTS groups_normalized_name_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS groups_organization_id_normalized_name_key ON groups (organization_id, normalized_name);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestAddOrganizationScope_Success(t *testing.T) {
+	migration := NewAddOrganizationScopeMigration()
+	assert.Equal(t, migration.Name, "add-organization-scope")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		ALTER TABLE roles ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		ALTER TABLE roles ALTER COLUMN organization_id DROP DEFAULT;
+		ALTER TABLE roles DROP CONSTRAINT IF EXISTS roles_role_slug_key;
+		ALTER TABLE roles DROP CONSTRAINT IF EXISTS roles_role_label_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS roles_organization_id_role_slug_key ON roles (organization_id, role_slug);
+		CREATE UNIQUE INDEX IF NOT EXISTS roles_organization_id_role_label_key ON roles (organization_id, role_label);
+
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		ALTER TABLE users ALTER COLUMN organization_id DROP DEFAULT;
+		ALTER TABLE users DROP CONSTRAINT IF EXISTS users_username_key;
+		DROP INDEX IF EXISTS users_normalized_username_key;
+		DROP INDEX IF EXISTS users_email_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS users_organization_id_normalized_username_key ON users (organization_id, normalized_username);
+		CREATE UNIQUE INDEX IF NOT EXISTS users_organization_id_email_key ON users (organization_id, lower(email)) WHERE email <> '';
+
+		ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		ALTER TABLE refresh_tokens ALTER COLUMN organization_id DROP DEFAULT;
+
+		ALTER TABLE groups ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		ALTER TABLE groups ALTER COLUMN organization_id DROP DEFAULT;
+		ALTER TABLE groups DROP CONSTRAINT IF EXISTS groups_normalized_name_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS groups_organization_id_normalized_name_key ON groups (organization_id, normalized_name);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Creates the table of the organizations, along with the default one
+// existing data is moved into.
+func CreateOrganizationsTable(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS organizations(
+			id uuid NOT NULL,
+			slug varchar(63) NOT NULL UNIQUE,
+			name varchar(255) NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			updated_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+
+		INSERT INTO organizations(id, slug, name)
+		VALUES ('00000000-0000-0000-0000-000000000001', 'default', 'Default')
+		ON CONFLICT (id) DO NOTHING;
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewCreateOrganizationsTableMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "create-organizations-table",
+		Up:   CreateOrganizationsTable,
+	}
+}
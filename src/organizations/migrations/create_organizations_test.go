@@ -0,0 +1,104 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateOrganizationsTable_FailExec(t *testing.T) {
+	migration := NewCreateOrganizationsTableMigration()
+	assert.Equal(t, migration.Name, "create-organizations-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS organizations(
+			id uuid NOT NULL,
+			slug varchar(63) NOT NULL UNIQUE,
+			name varchar(255) NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			updated_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+
+		INSERT INTO organizations(id, slug, name)
+		VALUES ('00000000-0000-0000-0000-000000000001', 'default', 'Default')
+		ON CONFLICT (id) DO NOTHING;
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestCreateOrganizationsTable_TimeoutReached(t *testing.T) {
+	migration := NewCreateOrganizationsTableMigration()
+	assert.Equal(t, migration.Name, "create-organizations-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS organizations(
+			id uuid NOT NULL,
+			slug varchar(63) NOT NULL UNIQUE,
+			name varchar(255) NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			updated_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+
+		INSERT INTO organizations(id, slug, name)
+		VALUES ('00000000-0000-0000-0000-000000000001', 'default', 'Default')
+		ON CONFLICT (id) DO NOTHING;
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestCreateOrganizationsTable_Success(t *testing.T) {
+	migration := NewCreateOrganizationsTableMigration()
+	assert.Equal(t, migration.Name, "create-organizations-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS organizations(
+			id uuid NOT NULL,
+			slug varchar(63) NOT NULL UNIQUE,
+			name varchar(255) NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			updated_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+
+		INSERT INTO organizations(id, slug, name)
+		VALUES ('00000000-0000-0000-0000-000000000001', 'default', 'Default')
+		ON CONFLICT (id) DO NOTHING;
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
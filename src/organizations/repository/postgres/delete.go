@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Deletes an organization. Its roles, users, refresh tokens and groups go along with it.
+func (r PostgresRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	stmt, err := r.Db.PrepareContext(ctx, `DELETE FROM organizations WHERE id = $1`)
+	if err != nil {
+		return err
+	}
+
+	result, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets an organization.
+func (r PostgresRepository) GetByUUID(ctx context.Context, id uuid.UUID) (domain.Organization, error) {
+	query := `
+		SELECT id, slug, name, created_at, updated_at
+		FROM organizations
+		WHERE id = $1
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.Organization{}, err
+	}
+
+	result, err := r.scanOrganizationRow(stmt.QueryRowContext(ctx, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Organization{}, domain.ErrNotFound
+	}
+	return result, err
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const getByUUIDQuery = `
+		SELECT id, slug, name, created_at, updated_at
+		FROM organizations
+		WHERE id = $1
+	`
+
+func TestGetByUUID_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getByUUIDQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).GetByUUID(context.TODO(), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestGetByUUID_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(getByUUIDQuery)).
+		ExpectQuery().
+		WithArgs(id).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).GetByUUID(ctx, id)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestGetByUUID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(getByUUIDQuery)).
+		ExpectQuery().
+		WithArgs(id).
+		WillReturnError(sql.ErrNoRows)
+
+	res, err := New(db).GetByUUID(context.TODO(), id)
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.Empty(t, res)
+}
+
+func TestGetByUUID_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	createdAt := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "slug", "name", "created_at", "updated_at"}).
+		AddRow(id, "acme", "Acme", createdAt, createdAt)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getByUUIDQuery)).
+		ExpectQuery().
+		WithArgs(id).
+		WillReturnRows(rows)
+
+	res, err := New(db).GetByUUID(context.TODO(), id)
+	assert.Nil(t, err)
+	assert.Equal(t, domain.Organization{ID: id, Slug: "acme", Name: "Acme", CreatedAt: createdAt, UpdatedAt: createdAt}, res)
+}
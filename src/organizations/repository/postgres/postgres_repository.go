@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Postgres error code for unique constraint violations
+const uniqueViolationCode = "23505"
+
+type PostgresRepository struct {
+	Db *sql.DB
+}
+
+func New(db *sql.DB) domain.OrganizationRepository {
+	return PostgresRepository{db}
+}
+
+// Row of a single or multi row query
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans an organization row
+func (r PostgresRepository) scanOrganizationRow(row rowScanner) (domain.Organization, error) {
+	result := domain.Organization{}
+	err := row.Scan(
+		&result.ID,
+		&result.Slug,
+		&result.Name,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	)
+	if err != nil {
+		return domain.Organization{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Stores a new organization.
+// domain.ErrAlreadyExists is returned when another organization has the same slug.
+func (r PostgresRepository) Store(ctx context.Context, organization domain.Organization) (domain.Organization, error) {
+	if organization.ID == uuid.Nil {
+		organization.ID = uuid.New()
+	}
+
+	query := `
+		INSERT INTO organizations (id, slug, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		RETURNING id, slug, name, created_at, updated_at
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.Organization{}, err
+	}
+
+	row := stmt.QueryRowContext(ctx, organization.ID, organization.Slug, organization.Name, time.Now())
+
+	result, err := r.scanOrganizationRow(row)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+		return domain.Organization{}, domain.ErrAlreadyExists
+	}
+	return result, err
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const storeQuery = `
+		INSERT INTO organizations (id, slug, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		RETURNING id, slug, name, created_at, updated_at
+	`
+
+func TestStore_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(storeQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).Store(context.TODO(), domain.Organization{Slug: "acme", Name: "Acme"})
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestStore_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	organization := domain.Organization{ID: uuid.New(), Slug: "acme", Name: "Acme"}
+	mock.ExpectPrepare(regexp.QuoteMeta(storeQuery)).
+		ExpectQuery().
+		WithArgs(organization.ID, "acme", "Acme", anyTime{}).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).Store(ctx, organization)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestStore_AlreadyExists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	organization := domain.Organization{ID: uuid.New(), Slug: "acme", Name: "Acme"}
+	mock.ExpectPrepare(regexp.QuoteMeta(storeQuery)).
+		ExpectQuery().
+		WithArgs(organization.ID, "acme", "Acme", anyTime{}).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	res, err := New(db).Store(context.TODO(), organization)
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+	assert.Empty(t, res)
+}
+
+func TestStore_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	createdAt := time.Now()
+	id := uuid.New()
+	rows := sqlmock.NewRows([]string{"id", "slug", "name", "created_at", "updated_at"}).
+		AddRow(id, "acme", "Acme", createdAt, createdAt)
+	mock.ExpectPrepare(regexp.QuoteMeta(storeQuery)).
+		ExpectQuery().
+		WithArgs(sqlmock.AnyArg(), "acme", "Acme", anyTime{}).
+		WillReturnRows(rows)
+
+	res, err := New(db).Store(context.TODO(), domain.Organization{Slug: "acme", Name: "Acme"})
+	assert.Nil(t, err)
+	assert.Equal(t, domain.Organization{ID: id, Slug: "acme", Name: "Acme", CreatedAt: createdAt, UpdatedAt: createdAt}, res)
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Creates an organization with the default roles and its first administrator.
+// Nothing is kept when any of them can't be created.
+func (s DefaultOrganizationService) Create(ctx context.Context, organization domain.Organization, admin domain.StoreUserRequest) (domain.Organization, *domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	organization.Slug = strings.ToLower(strings.TrimSpace(organization.Slug))
+	organization.Name = strings.TrimSpace(organization.Name)
+	if !organization.IsValid() {
+		return domain.Organization{}, nil, domain.ErrBadParamInput
+	}
+
+	organization, err := s.OrganizationRepo.Store(ctx, organization)
+	if err != nil {
+		return domain.Organization{}, nil, err
+	}
+
+	user, err := s.populate(domain.WithOrganization(ctx, organization.ID), admin)
+	if err != nil {
+		// Deleting the organization deletes whatever was created in it.
+		if deleteErr := s.OrganizationRepo.Delete(ctx, organization.ID); deleteErr != nil {
+			s.Logger.Printf("error deleting the organization {%s} that failed to be created: %v\n", organization.ID.String(), deleteErr)
+		}
+		return domain.Organization{}, nil, err
+	}
+	return organization, user, nil
+}
+
+// Stores the default roles and the administrator in the organization of the context.
+func (s DefaultOrganizationService) populate(ctx context.Context, admin domain.StoreUserRequest) (*domain.User, error) {
+	for _, role := range []domain.Role{domain.DEFAULT_ROLE_ADMIN, domain.DEFAULT_ROLE_USER} {
+		if _, err := s.RoleRepo.Store(ctx, role); err != nil {
+			s.Logger.Printf("error adding role %s to the organization: %v\n", role.RoleSlug, err)
+			return nil, err
+		}
+	}
+
+	admin.RoleSlug = domain.DEFAULT_ROLE_ADMIN.RoleSlug
+	return s.UserService.Store(ctx, admin)
+}
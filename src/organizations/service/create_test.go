@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreate_InvalidOrganization(t *testing.T) {
+	organizations := map[string]domain.Organization{
+		"missing slug":     {Name: "Acme"},
+		"invalid slug":     {Slug: "acme corp", Name: "Acme"},
+		"slug too short":   {Slug: "a", Name: "Acme"},
+		"missing name":     {Slug: "acme"},
+		"only blank names": {Slug: "acme", Name: "   "},
+	}
+
+	for name, organization := range organizations {
+		t.Run(name, func(t *testing.T) {
+			res, user, err := newService(nil, nil, nil).Create(context.TODO(), organization, domain.StoreUserRequest{})
+			assert.Equal(t, domain.ErrBadParamInput, err)
+			assert.Empty(t, res)
+			assert.Nil(t, user)
+		})
+	}
+}
+
+func TestCreate_SlugAlreadyTaken(t *testing.T) {
+	organizationRepo := new(mocks.OrganizationRepository)
+	organizationRepo.On("Store", mock.Anything, domain.Organization{Slug: "acme", Name: "Acme"}).
+		Once().Return(domain.Organization{}, domain.ErrAlreadyExists)
+
+	_, _, err := newService(organizationRepo, nil, nil).Create(context.TODO(), domain.Organization{Slug: " ACME ", Name: "Acme"}, domain.StoreUserRequest{})
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+	organizationRepo.AssertExpectations(t)
+}
+
+func TestCreate_DeletesTheOrganizationWhenTheAdminFails(t *testing.T) {
+	organization := domain.Organization{ID: uuid.New(), Slug: "acme", Name: "Acme"}
+	organizationRepo := new(mocks.OrganizationRepository)
+	organizationRepo.On("Store", mock.Anything, mock.Anything).Once().Return(organization, nil)
+	organizationRepo.On("Delete", mock.Anything, organization.ID).Once().Return(nil)
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("Store", mock.Anything, mock.Anything).Twice().Return(domain.Role{}, nil)
+	userService := new(mocks.UserService)
+	userService.On("Store", mock.Anything, mock.Anything).Once().Return(nil, domain.ErrBadParamInput)
+
+	res, user, err := newService(organizationRepo, roleRepo, userService).Create(context.TODO(), organization, domain.StoreUserRequest{Username: "admin"})
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	assert.Empty(t, res)
+	assert.Nil(t, user)
+	organizationRepo.AssertExpectations(t)
+}
+
+func TestCreate_DeletesTheOrganizationWhenTheRolesFail(t *testing.T) {
+	organization := domain.Organization{ID: uuid.New(), Slug: "acme", Name: "Acme"}
+	organizationRepo := new(mocks.OrganizationRepository)
+	organizationRepo.On("Store", mock.Anything, mock.Anything).Once().Return(organization, nil)
+	organizationRepo.On("Delete", mock.Anything, organization.ID).Once().Return(nil)
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("Store", mock.Anything, mock.Anything).Once().Return(domain.Role{}, errors.New("boom"))
+
+	_, _, err := newService(organizationRepo, roleRepo, nil).Create(context.TODO(), organization, domain.StoreUserRequest{Username: "admin"})
+	assert.Equal(t, "boom", err.Error())
+	organizationRepo.AssertExpectations(t)
+}
+
+func TestCreate_Success(t *testing.T) {
+	organization := domain.Organization{ID: uuid.New(), Slug: "acme", Name: "Acme"}
+	admin := &domain.User{ID: uuid.New(), Username: "admin", OrganizationID: organization.ID}
+	inOrganization := mock.MatchedBy(func(ctx context.Context) bool {
+		return domain.OrganizationFromContext(ctx) == organization.ID
+	})
+
+	organizationRepo := new(mocks.OrganizationRepository)
+	organizationRepo.On("Store", mock.Anything, mock.Anything).Once().Return(organization, nil)
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("Store", inOrganization, domain.DEFAULT_ROLE_ADMIN).Once().Return(domain.Role{}, nil)
+	roleRepo.On("Store", inOrganization, domain.DEFAULT_ROLE_USER).Once().Return(domain.Role{}, nil)
+	userService := new(mocks.UserService)
+	userService.On("Store", inOrganization, domain.StoreUserRequest{Username: "admin", Password: "password", RoleSlug: "admin"}).
+		Once().Return(admin, nil)
+
+	res, user, err := newService(organizationRepo, roleRepo, userService).
+		Create(context.TODO(), domain.Organization{Slug: "acme", Name: "Acme"}, domain.StoreUserRequest{Username: "admin", Password: "password", RoleSlug: "user"})
+	assert.Nil(t, err)
+	assert.Equal(t, organization, res)
+	assert.Equal(t, admin, user)
+	organizationRepo.AssertExpectations(t)
+	roleRepo.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type DefaultOrganizationService struct {
+	Logger           *log.Logger
+	OrganizationRepo domain.OrganizationRepository
+	RoleRepo         domain.RoleRepository
+	UserService      domain.UserService
+	ContextTimeout   time.Duration
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	organizationRepo domain.OrganizationRepository,
+	roleRepo domain.RoleRepository,
+	userService domain.UserService,
+	contextTimeout time.Duration,
+) domain.OrganizationService {
+	return DefaultOrganizationService{
+		logger,
+		organizationRepo,
+		roleRepo,
+		userService,
+		contextTimeout,
+	}
+}
+
+// Instantiation for tests
+func newService(organizationRepo domain.OrganizationRepository, roleRepo domain.RoleRepository, userService domain.UserService) DefaultOrganizationService {
+	return DefaultOrganizationService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		organizationRepo,
+		roleRepo,
+		userService,
+		time.Duration(5 * time.Second),
+	}
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Keeps the organization a passkey ceremony began in, so the user is looked up
+// there when it finishes. Logins already in progress are left in the default organization.
+func AddSessionOrganization(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		ALTER TABLE passkey_sessions ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		UPDATE passkey_sessions s SET organization_id = u.organization_id
+		FROM users u
+		WHERE u.id = s.user_id AND s.organization_id <> u.organization_id;
+		ALTER TABLE passkey_sessions ALTER COLUMN organization_id DROP DEFAULT;
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewAddSessionOrganizationMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "add-passkey-sessions-organization",
+		Up:   AddSessionOrganization,
+	}
+}
@@ -0,0 +1,86 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddSessionOrganization_FailExec(t *testing.T) {
+	migration := NewAddSessionOrganizationMigration()
+	assert.Equal(t, migration.Name, "add-passkey-sessions-organization")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		ALTER TABLE passkey_sessions ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		UPDATE passkey_sessions s SET organization_id = u.organization_id
+		FROM users u
+		WHERE u.id = s.user_id AND s.organization_id <> u.organization_id;
+		ALTER TABLE passkey_sessions ALTER COLUMN organization_id DROP DEFAULT;
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestAddSessionOrganization_TimeoutReached(t *testing.T) {
+	migration := NewAddSessionOrganizationMigration()
+	assert.Equal(t, migration.Name, "add-passkey-sessions-organization")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		ALTER TABLE passkey_sessions ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		UPDATE passkey_sessions s SET organization_id = u.organization_id
+		FROM users u
+		WHERE u.id = s.user_id AND s.organization_id <> u.organization_id;
+		ALTER TABLE passkey_sessions ALTER COLUMN organization_id DROP DEFAULT;
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestAddSessionOrganization_Success(t *testing.T) {
+	migration := NewAddSessionOrganizationMigration()
+	assert.Equal(t, migration.Name, "add-passkey-sessions-organization")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		ALTER TABLE passkey_sessions ADD COLUMN IF NOT EXISTS organization_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+			REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE;
+		UPDATE passkey_sessions s SET organization_id = u.organization_id
+		FROM users u
+		WHERE u.id = s.user_id AND s.organization_id <> u.organization_id;
+		ALTER TABLE passkey_sessions ALTER COLUMN organization_id DROP DEFAULT;
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
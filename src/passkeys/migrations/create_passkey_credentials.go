@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Creates the WebAuthn credentials (passkeys) table
+func CreatePasskeyCredentialsTable(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS passkey_credentials(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			credential_id bytea NOT NULL UNIQUE,
+			public_key bytea NOT NULL,
+			attestation_type varchar(64) NOT NULL,
+			aaguid bytea,
+			sign_count bigint NOT NULL DEFAULT 0,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			last_used_at timestamptz,
+			PRIMARY KEY (id)
+		);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewCreatePasskeyCredentialsTableMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "create-passkey-credentials-table",
+		Up:   CreatePasskeyCredentialsTable,
+	}
+}
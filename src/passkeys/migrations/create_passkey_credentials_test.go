@@ -0,0 +1,104 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePasskeyCredentialsTable_FailExec(t *testing.T) {
+	migration := NewCreatePasskeyCredentialsTableMigration()
+	assert.Equal(t, migration.Name, "create-passkey-credentials-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS passkey_credentials(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			credential_id bytea NOT NULL UNIQUE,
+			public_key bytea NOT NULL,
+			attestation_type varchar(64) NOT NULL,
+			aaguid bytea,
+			sign_count bigint NOT NULL DEFAULT 0,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			last_used_at timestamptz,
+			PRIMARY KEY (id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestCreatePasskeyCredentialsTable_TimeoutReached(t *testing.T) {
+	migration := NewCreatePasskeyCredentialsTableMigration()
+	assert.Equal(t, migration.Name, "create-passkey-credentials-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS passkey_credentials(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			credential_id bytea NOT NULL UNIQUE,
+			public_key bytea NOT NULL,
+			attestation_type varchar(64) NOT NULL,
+			aaguid bytea,
+			sign_count bigint NOT NULL DEFAULT 0,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			last_used_at timestamptz,
+			PRIMARY KEY (id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestCreatePasskeyCredentialsTable_Success(t *testing.T) {
+	migration := NewCreatePasskeyCredentialsTableMigration()
+	assert.Equal(t, migration.Name, "create-passkey-credentials-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS passkey_credentials(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			credential_id bytea NOT NULL UNIQUE,
+			public_key bytea NOT NULL,
+			attestation_type varchar(64) NOT NULL,
+			aaguid bytea,
+			sign_count bigint NOT NULL DEFAULT 0,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			last_used_at timestamptz,
+			PRIMARY KEY (id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Creates the table keeping the WebAuthn ceremonies in progress
+func CreatePasskeySessionsTable(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS passkey_sessions(
+			id uuid NOT NULL,
+			user_id uuid REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			ceremony varchar(32) NOT NULL,
+			data text NOT NULL,
+			expires_at timestamptz NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewCreatePasskeySessionsTableMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "create-passkey-sessions-table",
+		Up:   CreatePasskeySessionsTable,
+	}
+}
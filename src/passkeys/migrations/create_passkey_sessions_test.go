@@ -0,0 +1,95 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePasskeySessionsTable_FailExec(t *testing.T) {
+	migration := NewCreatePasskeySessionsTableMigration()
+	assert.Equal(t, migration.Name, "create-passkey-sessions-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS passkey_sessions(
+			id uuid NOT NULL,
+			user_id uuid REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			ceremony varchar(32) NOT NULL,
+			data text NOT NULL,
+			expires_at timestamptz NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestCreatePasskeySessionsTable_TimeoutReached(t *testing.T) {
+	migration := NewCreatePasskeySessionsTableMigration()
+	assert.Equal(t, migration.Name, "create-passkey-sessions-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS passkey_sessions(
+			id uuid NOT NULL,
+			user_id uuid REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			ceremony varchar(32) NOT NULL,
+			data text NOT NULL,
+			expires_at timestamptz NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestCreatePasskeySessionsTable_Success(t *testing.T) {
+	migration := NewCreatePasskeySessionsTableMigration()
+	assert.Equal(t, migration.Name, "create-passkey-sessions-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS passkey_sessions(
+			id uuid NOT NULL,
+			user_id uuid REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			ceremony varchar(32) NOT NULL,
+			data text NOT NULL,
+			expires_at timestamptz NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT (now()),
+			PRIMARY KEY (id)
+		);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets and deletes a ceremony session, so its challenge can only be answered once.
+// Returns sql.ErrNoRows when there's no such session for the ceremony.
+func (r PostgresRepository) ConsumeSession(ctx context.Context, id uuid.UUID, ceremony string) (domain.PasskeySession, error) {
+	query := `
+		DELETE FROM passkey_sessions
+		WHERE id = $1 AND ceremony = $2
+		RETURNING id, user_id, ceremony, data, expires_at, organization_id
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.PasskeySession{}, err
+	}
+
+	result := domain.PasskeySession{}
+	err = stmt.QueryRowContext(ctx, id, ceremony).Scan(
+		&result.ID,
+		&result.UserID,
+		&result.Ceremony,
+		&result.Data,
+		&result.ExpiresAt,
+		&result.OrganizationID,
+	)
+	if err != nil {
+		return domain.PasskeySession{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const consumeSessionQuery = `
+		DELETE FROM passkey_sessions
+		WHERE id = $1 AND ceremony = $2
+		RETURNING id, user_id, ceremony, data, expires_at, organization_id
+	`
+
+func TestConsumeSession_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(consumeSessionQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).ConsumeSession(context.TODO(), uuid.New(), domain.PasskeyCeremonyLogin)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestConsumeSession_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(consumeSessionQuery)).
+		ExpectQuery().
+		WithArgs(id, "login").
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).ConsumeSession(ctx, id, domain.PasskeyCeremonyLogin)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestConsumeSession_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(consumeSessionQuery)).
+		ExpectQuery().
+		WithArgs(id, "registration").
+		WillReturnError(sql.ErrNoRows)
+
+	res, err := New(db).ConsumeSession(context.TODO(), id, domain.PasskeyCeremonyRegistration)
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Empty(t, res)
+}
+
+func TestConsumeSession_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	userID := uuid.New()
+	expiresAt := time.Now().Add(time.Minute)
+	organizationID := uuid.New()
+	rows := sqlmock.NewRows([]string{"id", "user_id", "ceremony", "data", "expires_at", "organization_id"}).
+		AddRow(id, userID, "registration", "{}", expiresAt, organizationID)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(consumeSessionQuery)).
+		ExpectQuery().
+		WithArgs(id, "registration").
+		WillReturnRows(rows)
+
+	res, err := New(db).ConsumeSession(context.TODO(), id, domain.PasskeyCeremonyRegistration)
+	assert.Nil(t, err)
+	assert.Equal(t, id, res.ID)
+	assert.Equal(t, uuid.NullUUID{UUID: userID, Valid: true}, res.UserID)
+	assert.Equal(t, "{}", res.Data)
+	assert.Equal(t, expiresAt, res.ExpiresAt)
+	assert.Equal(t, organizationID, res.OrganizationID)
+	assert.False(t, res.IsExpired())
+}
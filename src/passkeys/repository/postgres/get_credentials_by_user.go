@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets all the credentials registered by a user.
+func (r PostgresRepository) GetCredentialsByUser(ctx context.Context, userID uuid.UUID) ([]domain.PasskeyCredential, error) {
+	result := make([]domain.PasskeyCredential, 0)
+
+	query := `
+		SELECT id, user_id, credential_id, public_key, attestation_type, aaguid, sign_count, created_at, last_used_at
+		FROM passkey_credentials
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return result, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, userID)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		credential, err := r.scanCredentialRow(rows)
+		if err != nil {
+			return make([]domain.PasskeyCredential, 0), err
+		}
+		result = append(result, credential)
+	}
+	return result, rows.Err()
+}
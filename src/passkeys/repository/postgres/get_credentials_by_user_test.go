@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const getCredentialsByUserQuery = `
+		SELECT id, user_id, credential_id, public_key, attestation_type, aaguid, sign_count, created_at, last_used_at
+		FROM passkey_credentials
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+func TestGetCredentialsByUser_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getCredentialsByUserQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).GetCredentialsByUser(context.TODO(), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestGetCredentialsByUser_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(getCredentialsByUserQuery)).
+		ExpectQuery().
+		WithArgs(userID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).GetCredentialsByUser(ctx, userID)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestGetCredentialsByUser_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	firstID := uuid.New()
+	secondID := uuid.New()
+	createdAt := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "user_id", "credential_id", "public_key", "attestation_type", "aaguid", "sign_count", "created_at", "last_used_at"}).
+		AddRow(firstID, userID, []byte("first"), []byte("key"), "none", nil, 0, createdAt, nil).
+		AddRow(secondID, userID, []byte("second"), []byte("key"), "none", nil, 12, createdAt, createdAt)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getCredentialsByUserQuery)).
+		ExpectQuery().
+		WithArgs(userID).
+		WillReturnRows(rows)
+
+	res, err := New(db).GetCredentialsByUser(context.TODO(), userID)
+	assert.Nil(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, firstID, res[0].ID)
+	assert.True(t, res[0].LastUsedAt.IsZero())
+	assert.Equal(t, secondID, res[1].ID)
+	assert.Equal(t, uint32(12), res[1].SignCount)
+	assert.Equal(t, createdAt, res[1].LastUsedAt)
+}
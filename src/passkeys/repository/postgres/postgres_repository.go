@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Postgres error code for unique constraint violations
+const uniqueViolationCode = "23505"
+
+type PostgresRepository struct {
+	Db *sql.DB
+}
+
+func New(db *sql.DB) domain.PasskeyRepository {
+	return PostgresRepository{db}
+}
+
+// Row of a single or multi row query
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans a passkey credential row
+func (r PostgresRepository) scanCredentialRow(row rowScanner) (domain.PasskeyCredential, error) {
+	result := domain.PasskeyCredential{}
+	var signCount int64
+	var lastUsedAt sql.NullTime
+
+	err := row.Scan(
+		&result.ID,
+		&result.UserID,
+		&result.CredentialID,
+		&result.PublicKey,
+		&result.AttestationType,
+		&result.AAGUID,
+		&signCount,
+		&result.CreatedAt,
+		&lastUsedAt,
+	)
+	if err != nil {
+		return domain.PasskeyCredential{}, err
+	}
+
+	result.SignCount = uint32(signCount)
+	if lastUsedAt.Valid {
+		result.LastUsedAt = lastUsedAt.Time
+	}
+	return result, nil
+}
+
+// Returns domain.ErrNotFound when a statement didn't affect any row.
+func requireAffectedRows(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
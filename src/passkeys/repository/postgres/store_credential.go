@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Stores a credential registered by a user.
+// The credential ID is unique across all users, domain.ErrAlreadyExists is returned otherwise.
+func (r PostgresRepository) StoreCredential(ctx context.Context, credential domain.PasskeyCredential) (domain.PasskeyCredential, error) {
+	if credential.ID == uuid.Nil {
+		credential.ID = uuid.New()
+	}
+
+	query := `
+		INSERT INTO passkey_credentials (id, user_id, credential_id, public_key, attestation_type, aaguid, sign_count, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, user_id, credential_id, public_key, attestation_type, aaguid, sign_count, created_at, last_used_at
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.PasskeyCredential{}, err
+	}
+
+	row := stmt.QueryRowContext(ctx,
+		credential.ID,
+		credential.UserID,
+		credential.CredentialID,
+		credential.PublicKey,
+		credential.AttestationType,
+		credential.AAGUID,
+		int64(credential.SignCount),
+		time.Now(),
+	)
+
+	result, err := r.scanCredentialRow(row)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+		return domain.PasskeyCredential{}, domain.ErrAlreadyExists
+	}
+	return result, err
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const storeCredentialQuery = `
+		INSERT INTO passkey_credentials (id, user_id, credential_id, public_key, attestation_type, aaguid, sign_count, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, user_id, credential_id, public_key, attestation_type, aaguid, sign_count, created_at, last_used_at
+	`
+
+func TestStoreCredential_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(storeCredentialQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).StoreCredential(context.TODO(), domain.PasskeyCredential{UserID: uuid.New()})
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestStoreCredential_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	credential := domain.PasskeyCredential{
+		ID:              uuid.New(),
+		UserID:          uuid.New(),
+		CredentialID:    []byte("credential-id"),
+		PublicKey:       []byte("public-key"),
+		AttestationType: "none",
+		AAGUID:          make([]byte, 16),
+	}
+	mock.ExpectPrepare(regexp.QuoteMeta(storeCredentialQuery)).
+		ExpectQuery().
+		WithArgs(credential.ID, credential.UserID, credential.CredentialID, credential.PublicKey, "none", credential.AAGUID, int64(0), anyTime{}).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).StoreCredential(ctx, credential)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestStoreCredential_AlreadyExists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	credential := domain.PasskeyCredential{
+		ID:              uuid.New(),
+		UserID:          uuid.New(),
+		CredentialID:    []byte("credential-id"),
+		PublicKey:       []byte("public-key"),
+		AttestationType: "none",
+		AAGUID:          make([]byte, 16),
+	}
+	mock.ExpectPrepare(regexp.QuoteMeta(storeCredentialQuery)).
+		ExpectQuery().
+		WithArgs(credential.ID, credential.UserID, credential.CredentialID, credential.PublicKey, "none", credential.AAGUID, int64(0), anyTime{}).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	res, err := New(db).StoreCredential(context.TODO(), credential)
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+	assert.Empty(t, res)
+}
+
+func TestStoreCredential_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	credential := domain.PasskeyCredential{
+		UserID:          uuid.New(),
+		CredentialID:    []byte("credential-id"),
+		PublicKey:       []byte("public-key"),
+		AttestationType: "none",
+		AAGUID:          make([]byte, 16),
+		SignCount:       3,
+	}
+	id := uuid.New()
+	createdAt := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "user_id", "credential_id", "public_key", "attestation_type", "aaguid", "sign_count", "created_at", "last_used_at"}).
+		AddRow(id, credential.UserID, credential.CredentialID, credential.PublicKey, "none", credential.AAGUID, 3, createdAt, nil)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(storeCredentialQuery)).
+		ExpectQuery().
+		WithArgs(sqlmock.AnyArg(), credential.UserID, credential.CredentialID, credential.PublicKey, "none", credential.AAGUID, int64(3), anyTime{}).
+		WillReturnRows(rows)
+
+	res, err := New(db).StoreCredential(context.TODO(), credential)
+	assert.Nil(t, err)
+	assert.Equal(t, id, res.ID)
+	assert.Equal(t, credential.CredentialID, res.CredentialID)
+	assert.Equal(t, uint32(3), res.SignCount)
+	assert.Equal(t, createdAt, res.CreatedAt)
+	assert.True(t, res.LastUsedAt.IsZero())
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Stores the state of a ceremony until its second step, in the organization of the context
+// when the session doesn't have one.
+func (r PostgresRepository) StoreSession(ctx context.Context, session domain.PasskeySession) error {
+	query := `
+		INSERT INTO passkey_sessions (id, user_id, ceremony, data, expires_at, created_at, organization_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	if session.OrganizationID == uuid.Nil {
+		session.OrganizationID = domain.OrganizationFromContext(ctx)
+	}
+
+	_, err = stmt.ExecContext(ctx,
+		session.ID,
+		session.UserID,
+		session.Ceremony,
+		session.Data,
+		session.ExpiresAt,
+		time.Now(),
+		session.OrganizationID,
+	)
+	return err
+}
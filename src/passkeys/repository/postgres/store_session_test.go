@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const storeSessionQuery = `
+		INSERT INTO passkey_sessions (id, user_id, ceremony, data, expires_at, created_at, organization_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+func TestStoreSession_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(storeSessionQuery)).WillReturnError(errors.New("boom"))
+
+	err = New(db).StoreSession(context.TODO(), domain.PasskeySession{ID: uuid.New()})
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestStoreSession_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	session := domain.PasskeySession{
+		ID:        uuid.New(),
+		Ceremony:  domain.PasskeyCeremonyLogin,
+		Data:      "{}",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	mock.ExpectPrepare(regexp.QuoteMeta(storeSessionQuery)).
+		ExpectExec().
+		WithArgs(session.ID, session.UserID, "login", "{}", session.ExpiresAt, anyTime{}, domain.DefaultOrganizationID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = New(db).StoreSession(ctx, session)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestStoreSession_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	organizationID := uuid.New()
+	session := domain.PasskeySession{
+		ID:        uuid.New(),
+		UserID:    uuid.NullUUID{UUID: uuid.New(), Valid: true},
+		Ceremony:  domain.PasskeyCeremonyRegistration,
+		Data:      "{}",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	mock.ExpectPrepare(regexp.QuoteMeta(storeSessionQuery)).
+		ExpectExec().
+		WithArgs(session.ID, session.UserID, "registration", "{}", session.ExpiresAt, anyTime{}, organizationID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = New(db).StoreSession(domain.WithOrganization(context.TODO(), organizationID), session)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,28 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Saves the signature counter reported on the last use of a credential.
+func (r PostgresRepository) UpdateCredentialUsage(ctx context.Context, id uuid.UUID, signCount uint32) error {
+	query := `
+		UPDATE passkey_credentials
+		SET sign_count = $2, last_used_at = $3
+		WHERE id = $1
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	result, err := stmt.ExecContext(ctx, id, int64(signCount), time.Now())
+	if err != nil {
+		return err
+	}
+	return requireAffectedRows(result)
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const updateCredentialUsageQuery = `
+		UPDATE passkey_credentials
+		SET sign_count = $2, last_used_at = $3
+		WHERE id = $1
+	`
+
+func TestUpdateCredentialUsage_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(updateCredentialUsageQuery)).WillReturnError(errors.New("boom"))
+
+	err = New(db).UpdateCredentialUsage(context.TODO(), uuid.New(), 4)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestUpdateCredentialUsage_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(updateCredentialUsageQuery)).
+		ExpectExec().
+		WithArgs(id, int64(4), anyTime{}).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = New(db).UpdateCredentialUsage(ctx, id, 4)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestUpdateCredentialUsage_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(updateCredentialUsageQuery)).
+		ExpectExec().
+		WithArgs(id, int64(4), anyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = New(db).UpdateCredentialUsage(context.TODO(), id, 4)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestUpdateCredentialUsage_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(updateCredentialUsageQuery)).
+		ExpectExec().
+		WithArgs(id, int64(4), anyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = New(db).UpdateCredentialUsage(context.TODO(), id, 4)
+	assert.Nil(t, err)
+}
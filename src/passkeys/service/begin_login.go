@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Starts a passkey login.
+// No user is needed, the authenticator picks one of its passkeys for the relying party.
+func (s DefaultPasskeyService) BeginLogin(ctx context.Context) (domain.PasskeyChallenge, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	wa, err := s.webAuthn()
+	if err != nil {
+		s.Logger.Printf("error configuring webauthn: %v\n", err)
+		return domain.PasskeyChallenge{}, err
+	}
+
+	options, data, err := wa.BeginDiscoverableLogin()
+	if err != nil {
+		s.Logger.Printf("error beginning the passkey login: %v\n", err)
+		return domain.PasskeyChallenge{}, err
+	}
+
+	return s.storeSession(ctx, domain.PasskeyCeremonyLogin, uuid.NullUUID{}, options, data)
+}
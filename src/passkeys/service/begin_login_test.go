@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBeginLogin_ErrorStoringSession(t *testing.T) {
+	passkeyRepo := new(mocks.PasskeyRepository)
+	passkeyRepo.On("StoreSession", mock.Anything, mock.AnythingOfType("domain.PasskeySession")).Once().
+		Return(errors.New("boom"))
+
+	res, err := newService(passkeyRepo, nil).BeginLogin(context.TODO())
+	assert.Equal(t, "boom", err.Error())
+	assert.Empty(t, res)
+	passkeyRepo.AssertExpectations(t)
+}
+
+func TestBeginLogin_Success(t *testing.T) {
+	passkeyRepo := new(mocks.PasskeyRepository)
+	session := captureSession(passkeyRepo)
+
+	res, err := newService(passkeyRepo, nil).BeginLogin(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, session.ID, res.SessionID)
+	assert.Equal(t, domain.PasskeyCeremonyLogin, session.Ceremony)
+	assert.Equal(t, uuid.NullUUID{}, session.UserID)
+
+	options := struct {
+		PublicKey struct {
+			Challenge        string `json:"challenge"`
+			RelyingPartyID   string `json:"rpId"`
+			UserVerification string `json:"userVerification"`
+		} `json:"publicKey"`
+	}{}
+	assert.Nil(t, json.Unmarshal(res.Options, &options))
+	assert.NotEmpty(t, options.PublicKey.Challenge)
+	assert.Equal(t, "localhost", options.PublicKey.RelyingPartyID)
+	assert.Equal(t, "required", options.PublicKey.UserVerification)
+	passkeyRepo.AssertExpectations(t)
+}
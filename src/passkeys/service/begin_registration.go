@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Starts the registration of a new passkey for a user.
+// Passkeys the user already has are excluded, so an authenticator can't register twice.
+func (s DefaultPasskeyService) BeginRegistration(ctx context.Context, user *domain.User) (domain.PasskeyChallenge, error) {
+	if user == nil {
+		return domain.PasskeyChallenge{}, domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	wa, err := s.webAuthn()
+	if err != nil {
+		s.Logger.Printf("error configuring webauthn: %v\n", err)
+		return domain.PasskeyChallenge{}, err
+	}
+
+	waUser, _, err := s.webAuthnUser(ctx, user)
+	if err != nil {
+		return domain.PasskeyChallenge{}, err
+	}
+
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(waUser.credentials))
+	for _, credential := range waUser.credentials {
+		exclusions = append(exclusions, credential.Descriptor())
+	}
+
+	options, data, err := wa.BeginRegistration(waUser, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		s.Logger.Printf("error beginning the passkey registration: %v\n", err)
+		return domain.PasskeyChallenge{}, err
+	}
+
+	return s.storeSession(ctx, domain.PasskeyCeremonyRegistration, uuid.NullUUID{UUID: user.ID, Valid: true}, options, data)
+}
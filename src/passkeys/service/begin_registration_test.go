@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBeginRegistration_NilUser(t *testing.T) {
+	res, err := newService(nil, nil).BeginRegistration(context.TODO(), nil)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	assert.Empty(t, res)
+}
+
+func TestBeginRegistration_ErrorGettingCredentials(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice"}
+	passkeyRepo := new(mocks.PasskeyRepository)
+	passkeyRepo.On("GetCredentialsByUser", mock.Anything, user.ID).Once().
+		Return([]domain.PasskeyCredential{}, errors.New("boom"))
+
+	res, err := newService(passkeyRepo, nil).BeginRegistration(context.TODO(), user)
+	assert.Equal(t, "boom", err.Error())
+	assert.Empty(t, res)
+	passkeyRepo.AssertExpectations(t)
+}
+
+func TestBeginRegistration_ErrorStoringSession(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice"}
+	passkeyRepo := new(mocks.PasskeyRepository)
+	passkeyRepo.On("GetCredentialsByUser", mock.Anything, user.ID).Once().
+		Return([]domain.PasskeyCredential{}, nil)
+	passkeyRepo.On("StoreSession", mock.Anything, mock.AnythingOfType("domain.PasskeySession")).Once().
+		Return(errors.New("boom"))
+
+	res, err := newService(passkeyRepo, nil).BeginRegistration(context.TODO(), user)
+	assert.Equal(t, "boom", err.Error())
+	assert.Empty(t, res)
+	passkeyRepo.AssertExpectations(t)
+}
+
+func TestBeginRegistration_Success(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice"}
+	existing := domain.PasskeyCredential{ID: uuid.New(), CredentialID: []byte("existing-credential")}
+	passkeyRepo := new(mocks.PasskeyRepository)
+	passkeyRepo.On("GetCredentialsByUser", mock.Anything, user.ID).Once().
+		Return([]domain.PasskeyCredential{existing}, nil)
+	session := captureSession(passkeyRepo)
+
+	res, err := newService(passkeyRepo, nil).BeginRegistration(context.TODO(), user)
+	assert.Nil(t, err)
+	assert.Equal(t, session.ID, res.SessionID)
+	assert.Equal(t, domain.PasskeyCeremonyRegistration, session.Ceremony)
+	assert.Equal(t, uuid.NullUUID{UUID: user.ID, Valid: true}, session.UserID)
+	assert.False(t, session.IsExpired())
+
+	options := struct {
+		PublicKey struct {
+			RelyingParty struct {
+				ID string `json:"id"`
+			} `json:"rp"`
+			User struct {
+				Name string `json:"name"`
+			} `json:"user"`
+			Challenge          string `json:"challenge"`
+			ExcludeCredentials []struct {
+				ID string `json:"id"`
+			} `json:"excludeCredentials"`
+		} `json:"publicKey"`
+	}{}
+	assert.Nil(t, json.Unmarshal(res.Options, &options))
+	assert.Equal(t, "localhost", options.PublicKey.RelyingParty.ID)
+	assert.Equal(t, "alice", options.PublicKey.User.Name)
+	assert.NotEmpty(t, options.PublicKey.Challenge)
+	assert.Contains(t, session.Data, options.PublicKey.Challenge)
+	assert.Len(t, options.PublicKey.ExcludeCredentials, 1)
+	passkeyRepo.AssertExpectations(t)
+}
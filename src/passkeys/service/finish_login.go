@@ -0,0 +1,85 @@
+package service
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Verifies the assertion of a passkey and returns the user it belongs to.
+// The signature counter is saved, and a counter that didn't increase is taken
+// as a cloned authenticator, failing with domain.ErrNotAllowed.
+// Users that are not active can't log in, failing with domain.ErrUserNotActive.
+func (s DefaultPasskeyService) FinishLogin(ctx context.Context, sessionID uuid.UUID, response []byte) (*domain.User, error) {
+	if len(response) == 0 {
+		return nil, domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	session, data, err := s.consumeSession(ctx, sessionID, domain.PasskeyCeremonyLogin)
+	if err != nil {
+		return nil, err
+	}
+	// The user is looked up in the organization the login began in.
+	ctx = domain.WithOrganization(ctx, session.OrganizationID)
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, domain.ErrBadParamInput
+	}
+
+	wa, err := s.webAuthn()
+	if err != nil {
+		s.Logger.Printf("error configuring webauthn: %v\n", err)
+		return nil, err
+	}
+
+	var user *domain.User
+	var stored []domain.PasskeyCredential
+	findUser := func(rawID, userHandle []byte) (webauthn.User, error) {
+		userID, err := uuid.FromBytes(userHandle)
+		if err != nil {
+			return nil, err
+		}
+
+		user, err = s.UserService.GetUserByUUID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		waUser, credentials, err := s.webAuthnUser(ctx, user)
+		stored = credentials
+		return waUser, err
+	}
+
+	credential, err := wa.ValidateDiscoverableLogin(findUser, data, parsed)
+	if err != nil {
+		s.Logger.Printf("error verifying the passkey assertion: %v\n", err)
+		return nil, domain.ErrNotAllowed
+	}
+	if credential.Authenticator.CloneWarning {
+		s.Logger.Printf("possibly cloned passkey used by user %v\n", user.ID)
+		return nil, domain.ErrNotAllowed
+	}
+
+	for _, storedCredential := range stored {
+		if bytes.Equal(storedCredential.CredentialID, credential.ID) {
+			err = s.PasskeyRepo.UpdateCredentialUsage(ctx, storedCredential.ID, credential.Authenticator.SignCount)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	if !user.IsActive() {
+		return nil, domain.ErrUserNotActive
+	}
+	return user, nil
+}
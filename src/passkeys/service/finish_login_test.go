@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/plagioriginal/user-microservice/passkeys/softauthenticator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Begins a login, signs it with the authenticator and returns the session and response to finish it.
+func beginLogin(t *testing.T, service DefaultPasskeyService, passkeyRepo *mocks.PasskeyRepository, authenticator *softauthenticator.Authenticator) (domain.PasskeySession, []byte) {
+	session := captureSession(passkeyRepo)
+
+	challenge, err := service.BeginLogin(context.TODO())
+	assert.Nil(t, err)
+
+	response, err := authenticator.Login(challenge.Options)
+	assert.Nil(t, err)
+	return *session, response
+}
+
+func TestFinishLogin_EmptyResponse(t *testing.T) {
+	res, err := newService(nil, nil).FinishLogin(context.TODO(), uuid.New(), nil)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	assert.Nil(t, res)
+}
+
+func TestFinishLogin_SessionNotFound(t *testing.T) {
+	sessionID := uuid.New()
+	passkeyRepo := new(mocks.PasskeyRepository)
+	passkeyRepo.On("ConsumeSession", mock.Anything, sessionID, domain.PasskeyCeremonyLogin).Once().
+		Return(domain.PasskeySession{}, sql.ErrNoRows)
+
+	res, err := newService(passkeyRepo, nil).FinishLogin(context.TODO(), sessionID, []byte("{}"))
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	assert.Nil(t, res)
+	passkeyRepo.AssertExpectations(t)
+}
+
+func TestFinishLogin_MalformedResponse(t *testing.T) {
+	session := domain.PasskeySession{
+		ID:        uuid.New(),
+		Ceremony:  domain.PasskeyCeremonyLogin,
+		Data:      "{}",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	passkeyRepo := new(mocks.PasskeyRepository)
+	passkeyRepo.On("ConsumeSession", mock.Anything, session.ID, domain.PasskeyCeremonyLogin).Once().
+		Return(session, nil)
+
+	res, err := newService(passkeyRepo, nil).FinishLogin(context.TODO(), session.ID, []byte("not json"))
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	assert.Nil(t, res)
+	passkeyRepo.AssertExpectations(t)
+}
+
+func TestFinishLogin_UnknownUser(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	authenticator := softauthenticator.New("http://localhost:8080")
+	registerPasskey(t, user, authenticator)
+
+	passkeyRepo := new(mocks.PasskeyRepository)
+	userService := new(mocks.UserService)
+	service := newService(passkeyRepo, userService)
+	session, response := beginLogin(t, service, passkeyRepo, authenticator)
+
+	passkeyRepo.On("ConsumeSession", mock.Anything, session.ID, domain.PasskeyCeremonyLogin).Once().
+		Return(session, nil)
+	userService.On("GetUserByUUID", mock.Anything, user.ID).Once().Return(nil, sql.ErrNoRows)
+
+	res, err := service.FinishLogin(context.TODO(), session.ID, response)
+	assert.Equal(t, domain.ErrNotAllowed, err)
+	assert.Nil(t, res)
+	passkeyRepo.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
+
+func TestFinishLogin_UserLookedUpInOrganizationOfSession(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	authenticator := softauthenticator.New("http://localhost:8080")
+	registerPasskey(t, user, authenticator)
+
+	passkeyRepo := new(mocks.PasskeyRepository)
+	userService := new(mocks.UserService)
+	service := newService(passkeyRepo, userService)
+	session, response := beginLogin(t, service, passkeyRepo, authenticator)
+	session.OrganizationID = uuid.New()
+
+	passkeyRepo.On("ConsumeSession", mock.Anything, session.ID, domain.PasskeyCeremonyLogin).Once().
+		Return(session, nil)
+	userService.On("GetUserByUUID", mock.MatchedBy(func(ctx context.Context) bool {
+		return domain.OrganizationFromContext(ctx) == session.OrganizationID
+	}), user.ID).Once().Return(nil, sql.ErrNoRows)
+
+	res, err := service.FinishLogin(context.TODO(), session.ID, response)
+	assert.Equal(t, domain.ErrNotAllowed, err)
+	assert.Nil(t, res)
+	userService.AssertExpectations(t)
+}
+
+func TestFinishLogin_UnknownCredential(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	authenticator := softauthenticator.New("http://localhost:8080")
+	registerPasskey(t, user, authenticator)
+
+	passkeyRepo := new(mocks.PasskeyRepository)
+	userService := new(mocks.UserService)
+	service := newService(passkeyRepo, userService)
+	session, response := beginLogin(t, service, passkeyRepo, authenticator)
+
+	passkeyRepo.On("ConsumeSession", mock.Anything, session.ID, domain.PasskeyCeremonyLogin).Once().
+		Return(session, nil)
+	userService.On("GetUserByUUID", mock.Anything, user.ID).Once().Return(user, nil)
+	passkeyRepo.On("GetCredentialsByUser", mock.Anything, user.ID).Once().
+		Return([]domain.PasskeyCredential{}, nil)
+
+	res, err := service.FinishLogin(context.TODO(), session.ID, response)
+	assert.Equal(t, domain.ErrNotAllowed, err)
+	assert.Nil(t, res)
+	passkeyRepo.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
+
+func TestFinishLogin_ClonedAuthenticator(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	authenticator := softauthenticator.New("http://localhost:8080")
+	credential := registerPasskey(t, user, authenticator)
+	// Another copy of the authenticator was already used more times
+	credential.SignCount = 5
+
+	passkeyRepo := new(mocks.PasskeyRepository)
+	userService := new(mocks.UserService)
+	service := newService(passkeyRepo, userService)
+	session, response := beginLogin(t, service, passkeyRepo, authenticator)
+
+	passkeyRepo.On("ConsumeSession", mock.Anything, session.ID, domain.PasskeyCeremonyLogin).Once().
+		Return(session, nil)
+	userService.On("GetUserByUUID", mock.Anything, user.ID).Once().Return(user, nil)
+	passkeyRepo.On("GetCredentialsByUser", mock.Anything, user.ID).Once().
+		Return([]domain.PasskeyCredential{credential}, nil)
+
+	res, err := service.FinishLogin(context.TODO(), session.ID, response)
+	assert.Equal(t, domain.ErrNotAllowed, err)
+	assert.Nil(t, res)
+	passkeyRepo.AssertNotCalled(t, "UpdateCredentialUsage", mock.Anything, mock.Anything, mock.Anything)
+	passkeyRepo.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
+
+func TestFinishLogin_UserNotActive(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusSuspended}
+	authenticator := softauthenticator.New("http://localhost:8080")
+	credential := registerPasskey(t, user, authenticator)
+
+	passkeyRepo := new(mocks.PasskeyRepository)
+	userService := new(mocks.UserService)
+	service := newService(passkeyRepo, userService)
+	session, response := beginLogin(t, service, passkeyRepo, authenticator)
+
+	passkeyRepo.On("ConsumeSession", mock.Anything, session.ID, domain.PasskeyCeremonyLogin).Once().
+		Return(session, nil)
+	userService.On("GetUserByUUID", mock.Anything, user.ID).Once().Return(user, nil)
+	passkeyRepo.On("GetCredentialsByUser", mock.Anything, user.ID).Once().
+		Return([]domain.PasskeyCredential{credential}, nil)
+	passkeyRepo.On("UpdateCredentialUsage", mock.Anything, credential.ID, uint32(1)).Once().Return(nil)
+
+	res, err := service.FinishLogin(context.TODO(), session.ID, response)
+	assert.Equal(t, domain.ErrUserNotActive, err)
+	assert.Nil(t, res)
+	passkeyRepo.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
+
+func TestFinishLogin_Success(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	authenticator := softauthenticator.New("http://localhost:8080")
+	credential := registerPasskey(t, user, authenticator)
+
+	passkeyRepo := new(mocks.PasskeyRepository)
+	userService := new(mocks.UserService)
+	service := newService(passkeyRepo, userService)
+
+	for signCount := uint32(1); signCount <= 2; signCount++ {
+		session, response := beginLogin(t, service, passkeyRepo, authenticator)
+
+		passkeyRepo.On("ConsumeSession", mock.Anything, session.ID, domain.PasskeyCeremonyLogin).Once().
+			Return(session, nil)
+		userService.On("GetUserByUUID", mock.Anything, user.ID).Once().Return(user, nil)
+		passkeyRepo.On("GetCredentialsByUser", mock.Anything, user.ID).Once().
+			Return([]domain.PasskeyCredential{credential}, nil)
+		passkeyRepo.On("UpdateCredentialUsage", mock.Anything, credential.ID, signCount).Once().Return(nil)
+
+		res, err := service.FinishLogin(context.TODO(), session.ID, response)
+		assert.Nil(t, err)
+		assert.Equal(t, user, res)
+		credential.SignCount = signCount
+	}
+	passkeyRepo.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
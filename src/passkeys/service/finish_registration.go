@@ -0,0 +1,66 @@
+package service
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Verifies the attestation of the authenticator and stores the new passkey.
+// Returns domain.ErrInvalidToken for unknown, expired or foreign sessions
+// and domain.ErrNotAllowed when the attestation can't be verified.
+func (s DefaultPasskeyService) FinishRegistration(
+	ctx context.Context,
+	user *domain.User,
+	sessionID uuid.UUID,
+	response []byte,
+) (domain.PasskeyCredential, error) {
+	if user == nil || len(response) == 0 {
+		return domain.PasskeyCredential{}, domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	session, data, err := s.consumeSession(ctx, sessionID, domain.PasskeyCeremonyRegistration)
+	if err != nil {
+		return domain.PasskeyCredential{}, err
+	}
+	if !session.UserID.Valid || session.UserID.UUID != user.ID {
+		return domain.PasskeyCredential{}, domain.ErrInvalidToken
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return domain.PasskeyCredential{}, domain.ErrBadParamInput
+	}
+
+	wa, err := s.webAuthn()
+	if err != nil {
+		s.Logger.Printf("error configuring webauthn: %v\n", err)
+		return domain.PasskeyCredential{}, err
+	}
+
+	waUser, _, err := s.webAuthnUser(ctx, user)
+	if err != nil {
+		return domain.PasskeyCredential{}, err
+	}
+
+	credential, err := wa.CreateCredential(waUser, data, parsed)
+	if err != nil {
+		s.Logger.Printf("error verifying the passkey attestation: %v\n", err)
+		return domain.PasskeyCredential{}, domain.ErrNotAllowed
+	}
+
+	return s.PasskeyRepo.StoreCredential(ctx, domain.PasskeyCredential{
+		UserID:          user.ID,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+	})
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/plagioriginal/user-microservice/passkeys/softauthenticator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFinishRegistration_EmptyResponse(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	res, err := newService(nil, nil).FinishRegistration(context.TODO(), user, uuid.New(), nil)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	assert.Empty(t, res)
+}
+
+func TestFinishRegistration_SessionNotFound(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	sessionID := uuid.New()
+	passkeyRepo := new(mocks.PasskeyRepository)
+	passkeyRepo.On("ConsumeSession", mock.Anything, sessionID, domain.PasskeyCeremonyRegistration).Once().
+		Return(domain.PasskeySession{}, sql.ErrNoRows)
+
+	res, err := newService(passkeyRepo, nil).FinishRegistration(context.TODO(), user, sessionID, []byte("{}"))
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	assert.Empty(t, res)
+	passkeyRepo.AssertExpectations(t)
+}
+
+func TestFinishRegistration_ExpiredSession(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	session := domain.PasskeySession{
+		ID:        uuid.New(),
+		UserID:    uuid.NullUUID{UUID: user.ID, Valid: true},
+		Ceremony:  domain.PasskeyCeremonyRegistration,
+		Data:      "{}",
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}
+	passkeyRepo := new(mocks.PasskeyRepository)
+	passkeyRepo.On("ConsumeSession", mock.Anything, session.ID, domain.PasskeyCeremonyRegistration).Once().
+		Return(session, nil)
+
+	res, err := newService(passkeyRepo, nil).FinishRegistration(context.TODO(), user, session.ID, []byte("{}"))
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	assert.Empty(t, res)
+	passkeyRepo.AssertExpectations(t)
+}
+
+func TestFinishRegistration_SessionOfAnotherUser(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	session := domain.PasskeySession{
+		ID:        uuid.New(),
+		UserID:    uuid.NullUUID{UUID: uuid.New(), Valid: true},
+		Ceremony:  domain.PasskeyCeremonyRegistration,
+		Data:      "{}",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	passkeyRepo := new(mocks.PasskeyRepository)
+	passkeyRepo.On("ConsumeSession", mock.Anything, session.ID, domain.PasskeyCeremonyRegistration).Once().
+		Return(session, nil)
+
+	res, err := newService(passkeyRepo, nil).FinishRegistration(context.TODO(), user, session.ID, []byte("{}"))
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	assert.Empty(t, res)
+	passkeyRepo.AssertExpectations(t)
+}
+
+func TestFinishRegistration_MalformedResponse(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	session := domain.PasskeySession{
+		ID:        uuid.New(),
+		UserID:    uuid.NullUUID{UUID: user.ID, Valid: true},
+		Ceremony:  domain.PasskeyCeremonyRegistration,
+		Data:      "{}",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	passkeyRepo := new(mocks.PasskeyRepository)
+	passkeyRepo.On("ConsumeSession", mock.Anything, session.ID, domain.PasskeyCeremonyRegistration).Once().
+		Return(session, nil)
+
+	res, err := newService(passkeyRepo, nil).FinishRegistration(context.TODO(), user, session.ID, []byte("not json"))
+	assert.Equal(t, domain.ErrBadParamInput, err)
+	assert.Empty(t, res)
+	passkeyRepo.AssertExpectations(t)
+}
+
+func TestFinishRegistration_WrongOrigin(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice"}
+	passkeyRepo := new(mocks.PasskeyRepository)
+	service := newService(passkeyRepo, nil)
+
+	passkeyRepo.On("GetCredentialsByUser", mock.Anything, user.ID).Return([]domain.PasskeyCredential{}, nil)
+	session := captureSession(passkeyRepo)
+
+	challenge, err := service.BeginRegistration(context.TODO(), user)
+	assert.Nil(t, err)
+
+	response, _, err := softauthenticator.New("https://evil.example.com").Register(challenge.Options)
+	assert.Nil(t, err)
+
+	passkeyRepo.On("ConsumeSession", mock.Anything, challenge.SessionID, domain.PasskeyCeremonyRegistration).Once().
+		Return(*session, nil)
+
+	res, err := service.FinishRegistration(context.TODO(), user, challenge.SessionID, response)
+	assert.Equal(t, domain.ErrNotAllowed, err)
+	assert.Empty(t, res)
+	passkeyRepo.AssertNotCalled(t, "StoreCredential", mock.Anything, mock.Anything)
+	passkeyRepo.AssertExpectations(t)
+}
+
+func TestFinishRegistration_Success(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice"}
+	authenticator := softauthenticator.New("http://localhost:8080")
+
+	credential := registerPasskey(t, user, authenticator)
+	assert.NotEqual(t, uuid.Nil, credential.ID)
+	assert.Equal(t, user.ID, credential.UserID)
+	assert.Equal(t, authenticator.Credentials[0].ID, credential.CredentialID)
+	assert.NotEmpty(t, credential.PublicKey)
+	assert.Equal(t, "none", credential.AttestationType)
+	assert.Equal(t, uint32(0), credential.SignCount)
+	assert.Equal(t, user.ID[:], authenticator.Credentials[0].UserHandle)
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type DefaultPasskeyService struct {
+	Logger         *log.Logger
+	PasskeyRepo    domain.PasskeyRepository
+	UserService    domain.UserService
+	ContextTimeout time.Duration
+	Settings       domain.PasskeySettings
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	passkeyRepo domain.PasskeyRepository,
+	userService domain.UserService,
+	contextTimeout time.Duration,
+	settings domain.PasskeySettings,
+) domain.PasskeyService {
+	return DefaultPasskeyService{logger, passkeyRepo, userService, contextTimeout, settings}
+}
+
+// Instantiation for tests
+func newService(passkeyRepo domain.PasskeyRepository, userService domain.UserService) DefaultPasskeyService {
+	return DefaultPasskeyService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		passkeyRepo,
+		userService,
+		time.Duration(5 * time.Second),
+		domain.PasskeySettings{
+			RPID:          "localhost",
+			RPDisplayName: "Tests",
+			RPOrigins:     []string{"http://localhost:8080"},
+			SessionTTL:    time.Duration(5 * time.Minute),
+		},
+	}
+}
+
+// Adapts a user and its credentials to the WebAuthn library.
+type webAuthnUser struct {
+	user        *domain.User
+	credentials []webauthn.Credential
+}
+
+func (u webAuthnUser) WebAuthnID() []byte {
+	return u.user.ID[:]
+}
+
+func (u webAuthnUser) WebAuthnName() string {
+	return u.user.Username
+}
+
+func (u webAuthnUser) WebAuthnDisplayName() string {
+	return u.user.Username
+}
+
+func (u webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+func (u webAuthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+// Gets the relying party for the settings.
+// Passkeys must be discoverable and always verify the user, so they can replace both the password and MFA.
+func (s DefaultPasskeyService) webAuthn() (*webauthn.WebAuthn, error) {
+	timeout := webauthn.TimeoutConfig{Enforce: true, Timeout: s.Settings.SessionTTL, TimeoutUVD: s.Settings.SessionTTL}
+
+	return webauthn.New(&webauthn.Config{
+		RPID:          s.Settings.RPID,
+		RPDisplayName: s.Settings.RPDisplayName,
+		RPOrigins:     s.Settings.RPOrigins,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			UserVerification:   protocol.VerificationRequired,
+		},
+		Timeouts: webauthn.TimeoutsConfig{Login: timeout, Registration: timeout},
+	})
+}
+
+// Gets a user with its credentials, ready for the WebAuthn library.
+func (s DefaultPasskeyService) webAuthnUser(ctx context.Context, user *domain.User) (webAuthnUser, []domain.PasskeyCredential, error) {
+	stored, err := s.PasskeyRepo.GetCredentialsByUser(ctx, user.ID)
+	if err != nil {
+		return webAuthnUser{}, nil, err
+	}
+
+	credentials := make([]webauthn.Credential, 0, len(stored))
+	for _, credential := range stored {
+		credentials = append(credentials, webauthn.Credential{
+			ID:              credential.CredentialID,
+			PublicKey:       credential.PublicKey,
+			AttestationType: credential.AttestationType,
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    credential.AAGUID,
+				SignCount: credential.SignCount,
+			},
+		})
+	}
+	return webAuthnUser{user, credentials}, stored, nil
+}
+
+// Saves the state of a ceremony and builds the challenge to send to the client.
+func (s DefaultPasskeyService) storeSession(
+	ctx context.Context,
+	ceremony string,
+	userID uuid.NullUUID,
+	options interface{},
+	data *webauthn.SessionData,
+) (domain.PasskeyChallenge, error) {
+	encodedOptions, err := json.Marshal(options)
+	if err != nil {
+		return domain.PasskeyChallenge{}, err
+	}
+
+	encodedData, err := json.Marshal(data)
+	if err != nil {
+		return domain.PasskeyChallenge{}, err
+	}
+
+	session := domain.PasskeySession{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Ceremony:  ceremony,
+		Data:      string(encodedData),
+		ExpiresAt: time.Now().Add(s.Settings.SessionTTL),
+	}
+	if err = s.PasskeyRepo.StoreSession(ctx, session); err != nil {
+		return domain.PasskeyChallenge{}, err
+	}
+	return domain.PasskeyChallenge{SessionID: session.ID, Options: encodedOptions}, nil
+}
+
+// Consumes the session of a ceremony, returning domain.ErrInvalidToken if it doesn't exist or expired.
+func (s DefaultPasskeyService) consumeSession(ctx context.Context, id uuid.UUID, ceremony string) (domain.PasskeySession, webauthn.SessionData, error) {
+	session, err := s.PasskeyRepo.ConsumeSession(ctx, id, ceremony)
+	if err == sql.ErrNoRows || (err == nil && session.IsExpired()) {
+		return domain.PasskeySession{}, webauthn.SessionData{}, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return domain.PasskeySession{}, webauthn.SessionData{}, err
+	}
+
+	data := webauthn.SessionData{}
+	if err = json.Unmarshal([]byte(session.Data), &data); err != nil {
+		return domain.PasskeySession{}, webauthn.SessionData{}, err
+	}
+	return session, data, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/plagioriginal/user-microservice/passkeys/softauthenticator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Sets the repository to keep the next stored session in the returned pointer.
+func captureSession(passkeyRepo *mocks.PasskeyRepository) *domain.PasskeySession {
+	session := &domain.PasskeySession{}
+	passkeyRepo.On("StoreSession", mock.Anything, mock.AnythingOfType("domain.PasskeySession")).Once().
+		Run(func(args mock.Arguments) {
+			*session = args.Get(1).(domain.PasskeySession)
+		}).
+		Return(nil)
+	return session
+}
+
+// Registers a passkey of the user on the authenticator through the service, returning the stored credential.
+func registerPasskey(t *testing.T, user *domain.User, authenticator *softauthenticator.Authenticator) domain.PasskeyCredential {
+	passkeyRepo := new(mocks.PasskeyRepository)
+	service := newService(passkeyRepo, nil)
+
+	passkeyRepo.On("GetCredentialsByUser", mock.Anything, user.ID).Return([]domain.PasskeyCredential{}, nil)
+	session := captureSession(passkeyRepo)
+
+	challenge, err := service.BeginRegistration(context.TODO(), user)
+	assert.Nil(t, err)
+
+	response, _, err := authenticator.Register(challenge.Options)
+	assert.Nil(t, err)
+
+	passkeyRepo.On("ConsumeSession", mock.Anything, challenge.SessionID, domain.PasskeyCeremonyRegistration).Once().
+		Return(*session, nil)
+	passkeyRepo.On("StoreCredential", mock.Anything, mock.AnythingOfType("domain.PasskeyCredential")).Once().
+		Return(func(ctx context.Context, credential domain.PasskeyCredential) domain.PasskeyCredential {
+			credential.ID = uuid.New()
+			return credential
+		}, nil)
+
+	credential, err := service.FinishRegistration(context.TODO(), user, challenge.SessionID, response)
+	assert.Nil(t, err)
+	passkeyRepo.AssertExpectations(t)
+	return credential
+}
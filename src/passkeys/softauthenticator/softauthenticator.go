@@ -0,0 +1,216 @@
+// Package softauthenticator implements a WebAuthn authenticator in software,
+// so the passkey ceremonies can be driven in tests without any hardware.
+//
+// It answers the options issued by the relying party the same way a browser and a
+// platform authenticator would, with "none" attestation, ES256 keys and discoverable
+// credentials that are always user verified.
+package softauthenticator
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+// Authenticator data flags
+const (
+	flagUserPresent            = 0x01
+	flagUserVerified           = 0x04
+	flagAttestedCredentialData = 0x40
+)
+
+// Credential created by the authenticator.
+type Credential struct {
+	ID         []byte
+	UserHandle []byte
+	SignCount  uint32
+	key        *ecdsa.PrivateKey
+}
+
+// Software authenticator bound to an origin, keeping its credentials in memory.
+type Authenticator struct {
+	Origin      string
+	AAGUID      [16]byte
+	Credentials []*Credential
+}
+
+func New(origin string) *Authenticator {
+	return &Authenticator{Origin: origin}
+}
+
+// Options of a registration ceremony, as sent to navigator.credentials.create
+type creationOptions struct {
+	PublicKey struct {
+		RelyingParty struct {
+			ID string `json:"id"`
+		} `json:"rp"`
+		User struct {
+			ID protocol.URLEncodedBase64 `json:"id"`
+		} `json:"user"`
+		Challenge protocol.URLEncodedBase64 `json:"challenge"`
+	} `json:"publicKey"`
+}
+
+// Options of a login ceremony, as sent to navigator.credentials.get
+type requestOptions struct {
+	PublicKey struct {
+		RelyingPartyID     string                          `json:"rpId"`
+		Challenge          protocol.URLEncodedBase64       `json:"challenge"`
+		AllowedCredentials []protocol.CredentialDescriptor `json:"allowCredentials"`
+	} `json:"publicKey"`
+}
+
+// CBOR attestation object with the "none" format
+type attestationObject struct {
+	Format       string                 `cbor:"fmt"`
+	AttStatement map[string]interface{} `cbor:"attStmt"`
+	AuthData     []byte                 `cbor:"authData"`
+}
+
+// Creates a new credential for the options of a registration ceremony.
+// Returns the JSON response to send back to the relying party.
+func (a *Authenticator) Register(options []byte) ([]byte, *Credential, error) {
+	opts := creationOptions{}
+	if err := json.Unmarshal(options, &opts); err != nil {
+		return nil, nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	credential := &Credential{ID: make([]byte, 32), UserHandle: opts.PublicKey.User.ID, key: key}
+	if _, err = rand.Read(credential.ID); err != nil {
+		return nil, nil, err
+	}
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: key.X.FillBytes(make([]byte, 32)),
+		YCoord: key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	authData := a.authenticatorData(opts.PublicKey.RelyingParty.ID, flagUserPresent|flagUserVerified|flagAttestedCredentialData, 0)
+	authData = append(authData, a.AAGUID[:]...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(credential.ID)))
+	authData = append(authData, credential.ID...)
+	authData = append(authData, publicKey...)
+
+	attestation, err := webauthncbor.Marshal(attestationObject{
+		Format:       "none",
+		AttStatement: map[string]interface{}{},
+		AuthData:     authData,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clientData, err := a.clientData(protocol.CreateCeremony, opts.PublicKey.Challenge)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response, err := json.Marshal(map[string]interface{}{
+		"id":    protocol.URLEncodedBase64(credential.ID).String(),
+		"rawId": protocol.URLEncodedBase64(credential.ID),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    protocol.URLEncodedBase64(clientData),
+			"attestationObject": protocol.URLEncodedBase64(attestation),
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	a.Credentials = append(a.Credentials, credential)
+	return response, credential, nil
+}
+
+// Signs the challenge of a login ceremony with the last created credential allowed by the options.
+// Returns the JSON response to send back to the relying party.
+func (a *Authenticator) Login(options []byte) ([]byte, error) {
+	opts := requestOptions{}
+	if err := json.Unmarshal(options, &opts); err != nil {
+		return nil, err
+	}
+
+	credential := a.credentialFor(opts.PublicKey.AllowedCredentials)
+	if credential == nil {
+		return nil, errors.New("no credential available for the ceremony")
+	}
+	credential.SignCount++
+
+	authData := a.authenticatorData(opts.PublicKey.RelyingPartyID, flagUserPresent|flagUserVerified, credential.SignCount)
+	clientData, err := a.clientData(protocol.AssertCeremony, opts.PublicKey.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, credential.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"id":    protocol.URLEncodedBase64(credential.ID).String(),
+		"rawId": protocol.URLEncodedBase64(credential.ID),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    protocol.URLEncodedBase64(clientData),
+			"authenticatorData": protocol.URLEncodedBase64(authData),
+			"signature":         protocol.URLEncodedBase64(signature),
+			"userHandle":        protocol.URLEncodedBase64(credential.UserHandle),
+		},
+	})
+}
+
+// Gets the most recent credential, restricted to the allowed ones when there are any.
+func (a *Authenticator) credentialFor(allowed []protocol.CredentialDescriptor) *Credential {
+	for i := len(a.Credentials) - 1; i >= 0; i-- {
+		credential := a.Credentials[i]
+		if len(allowed) == 0 {
+			return credential
+		}
+		for _, descriptor := range allowed {
+			if string(descriptor.CredentialID) == string(credential.ID) {
+				return credential
+			}
+		}
+	}
+	return nil
+}
+
+// Builds the fixed part of the authenticator data.
+func (a *Authenticator) authenticatorData(rpID string, flags byte, signCount uint32) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	data := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, signCount)
+}
+
+// Builds the client data a browser would collect for the ceremony.
+func (a *Authenticator) clientData(ceremony protocol.CeremonyType, challenge protocol.URLEncodedBase64) ([]byte, error) {
+	return json.Marshal(protocol.CollectedClientData{
+		Type:      ceremony,
+		Challenge: challenge.String(),
+		Origin:    a.Origin,
+	})
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"io/ioutil"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/mailer"
+)
+
+type DefaultPasswordResetService struct {
+	Logger              *log.Logger
+	UserRepo            domain.UserRepository
+	RefreshTokenRepo    domain.RefreshTokenRepository
+	OneTimeTokenService domain.OneTimeTokenService
+	Notifier            domain.Notifier
+	SendQueue           *mailer.Queue
+	ContextTimeout      time.Duration
+	BcryptHashingCost   int
+	Settings            domain.PasswordResetSettings
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	userRepo domain.UserRepository,
+	refreshTokenRepo domain.RefreshTokenRepository,
+	oneTimeTokenService domain.OneTimeTokenService,
+	notifier domain.Notifier,
+	sendQueue *mailer.Queue,
+	contextTimeout time.Duration,
+	bcryptHashingCost int,
+	settings domain.PasswordResetSettings,
+) domain.PasswordResetService {
+	return DefaultPasswordResetService{
+		logger,
+		userRepo,
+		refreshTokenRepo,
+		oneTimeTokenService,
+		notifier,
+		sendQueue,
+		contextTimeout,
+		bcryptHashingCost,
+		settings,
+	}
+}
+
+// Instantiation for tests
+func newService(
+	userRepo domain.UserRepository,
+	refreshTokenRepo domain.RefreshTokenRepository,
+	oneTimeTokenService domain.OneTimeTokenService,
+	notifier domain.Notifier,
+) DefaultPasswordResetService {
+	return DefaultPasswordResetService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		userRepo,
+		refreshTokenRepo,
+		oneTimeTokenService,
+		notifier,
+		mailer.NewQueue(1, 10),
+		time.Duration(5 * time.Second),
+		2,
+		domain.PasswordResetSettings{
+			ResetURL:             "https://example.com/reset-password",
+			TokenTTL:             30 * time.Minute,
+			MaxRequestsPerWindow: 3,
+			RequestWindow:        time.Hour,
+		},
+	}
+}
+
+// Builds the reset link with the token.
+func (s DefaultPasswordResetService) resetLink(token string) (string, error) {
+	link, err := url.Parse(s.Settings.ResetURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String(), nil
+}
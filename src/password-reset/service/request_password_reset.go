@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/helpers"
+)
+
+// Sends a password reset link to a user, found by username or email.
+// Succeeds whether the user exists or not, so it can't be used to find accounts. The link is
+// sent in the background, so the request takes as long either way, and it's dropped when too
+// many are waiting to be sent.
+func (s DefaultPasswordResetService) RequestPasswordReset(ctx context.Context, usernameOrEmail string) error {
+	login := strings.TrimSpace(usernameOrEmail)
+	if len(login) == 0 {
+		return domain.ErrBadParamInput
+	}
+
+	// Keeps the organization of the request, without ending with it.
+	ctx = context.WithoutCancel(ctx)
+	queued := s.SendQueue.Enqueue(func() {
+		if err := s.sendPasswordReset(ctx, login); err != nil {
+			s.Logger.Printf("error requesting a password reset: %v\n", err)
+		}
+	})
+	if !queued {
+		s.Logger.Println("too many sends waiting, dropping a password reset request")
+	}
+	return nil
+}
+
+// Issues a password reset token for a user, found by username or email, and sends the link to it.
+// Nothing is sent to unknown users, directory users, nor to users who requested too many resets.
+func (s DefaultPasswordResetService) sendPasswordReset(ctx context.Context, login string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	user, err := s.findUser(ctx, login)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Directory users change their password in the directory.
+	if len(user.Backend) > 0 {
+		return nil
+	}
+
+	requests, err := s.OneTimeTokenService.CountIssuedSince(
+		ctx,
+		user.ID,
+		domain.TokenPurposePasswordReset,
+		time.Now().Add(-s.Settings.RequestWindow),
+	)
+	if err != nil {
+		return err
+	}
+	if requests >= s.Settings.MaxRequestsPerWindow {
+		s.Logger.Printf("too many password resets requested for user {%s}\n", user.ID.String())
+		return nil
+	}
+
+	token, err := s.OneTimeTokenService.Issue(ctx, user.ID, domain.TokenPurposePasswordReset, "", s.Settings.TokenTTL)
+	if err != nil {
+		return err
+	}
+
+	link, err := s.resetLink(token)
+	if err != nil {
+		return err
+	}
+
+	minutes := int(math.Ceil(s.Settings.TokenTTL.Minutes()))
+	err = s.Notifier.Notify(ctx, user, domain.Notification{
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nA password reset was requested for your account. Choose a new password by opening the following link:\n%s\n\nThe link expires in %d minutes. If you didn't ask for it, you can ignore this message.\n",
+			user.Username, link, minutes,
+		),
+	})
+	if err != nil {
+		s.Logger.Printf("error notifying the password reset to user {%s}: %v\n", user.ID.String(), err)
+	}
+	return nil
+}
+
+// Finds a user by username, or by email if none has that username.
+func (s DefaultPasswordResetService) findUser(ctx context.Context, login string) (*domain.User, error) {
+	user, err := s.UserRepo.GetByUsername(ctx, login)
+	if errors.Is(err, sql.ErrNoRows) && helpers.IsValidEmail(login) {
+		return s.UserRepo.GetByEmail(ctx, login)
+	}
+	return user, err
+}
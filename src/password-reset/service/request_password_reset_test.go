@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/plagioriginal/user-microservice/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequestPasswordReset_InvalidInput(t *testing.T) {
+	err := newService(nil, nil, nil, nil).RequestPasswordReset(context.TODO(), "  ")
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestRequestPasswordReset_SendsInBackground(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com"}
+	organizationID := uuid.New()
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").Once().Return(user, nil)
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("CountIssuedSince", mock.Anything, user.ID, domain.TokenPurposePasswordReset, mock.Anything).
+		Once().Return(0, nil)
+	tokenService.On("Issue", mock.Anything, user.ID, domain.TokenPurposePasswordReset, "", 30*time.Minute).
+		Once().Return("the-token", nil)
+
+	sent := make(chan context.Context, 1)
+	notifier := new(mocks.Notifier)
+	notifier.On("Notify", mock.Anything, user, mock.Anything).Once().Return(nil).
+		Run(func(args mock.Arguments) { sent <- args.Get(0).(context.Context) })
+
+	// The request is answered even if it ends before the link is sent.
+	ctx, cancel := context.WithCancel(domain.WithOrganization(context.TODO(), organizationID))
+	err := newService(userRepo, nil, tokenService, notifier).RequestPasswordReset(ctx, " alice ")
+	cancel()
+	assert.Nil(t, err)
+
+	select {
+	case notifyCtx := <-sent:
+		assert.Equal(t, organizationID, domain.OrganizationFromContext(notifyCtx))
+	case <-time.After(time.Second):
+		t.Fatal("the password reset wasn't sent")
+	}
+	tokenService.AssertExpectations(t)
+}
+
+func TestRequestPasswordReset_DroppedWhenTooManyWaiting(t *testing.T) {
+	service := newService(new(mocks.UserRepository), nil, nil, nil)
+	service.SendQueue = mailer.NewQueue(0, 0)
+
+	// The request is answered the same, and nothing is looked up.
+	err := service.RequestPasswordReset(context.TODO(), "alice")
+	assert.Nil(t, err)
+	service.SendQueue.Close()
+}
+
+func TestSendPasswordReset_UnknownUsername(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "nobody").
+		Once().Return(nil, sql.ErrNoRows)
+
+	err := newService(userRepo, nil, nil, nil).sendPasswordReset(context.TODO(), "nobody")
+	assert.Nil(t, err)
+	userRepo.AssertExpectations(t)
+}
+
+func TestSendPasswordReset_UnknownEmail(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "nobody@example.com").
+		Once().Return(nil, sql.ErrNoRows)
+	userRepo.On("GetByEmail", mock.Anything, "nobody@example.com").
+		Once().Return(nil, sql.ErrNoRows)
+
+	err := newService(userRepo, nil, nil, nil).sendPasswordReset(context.TODO(), "nobody@example.com")
+	assert.Nil(t, err)
+	userRepo.AssertExpectations(t)
+}
+
+func TestSendPasswordReset_DirectoryUser(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").
+		Once().Return(&domain.User{ID: uuid.New(), Username: "alice", Backend: "ldap"}, nil)
+	tokenService := new(mocks.OneTimeTokenService)
+
+	err := newService(userRepo, nil, tokenService, nil).sendPasswordReset(context.TODO(), "alice")
+	assert.Nil(t, err)
+	tokenService.AssertNotCalled(t, "Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSendPasswordReset_ErrorGettingUser(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").
+		Once().Return(nil, errors.New("boom"))
+
+	err := newService(userRepo, nil, nil, nil).sendPasswordReset(context.TODO(), "alice")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	userRepo.AssertExpectations(t)
+}
+
+func TestSendPasswordReset_RateLimited(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com"}
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").
+		Once().Return(user, nil)
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("CountIssuedSince", mock.Anything, user.ID, domain.TokenPurposePasswordReset, mock.MatchedBy(func(since time.Time) bool {
+		return since.Before(time.Now().Add(-59*time.Minute)) && since.After(time.Now().Add(-61*time.Minute))
+	})).Once().Return(3, nil)
+
+	// No token issued, but the caller can't tell.
+	err := newService(userRepo, nil, tokenService, nil).sendPasswordReset(context.TODO(), "alice")
+	assert.Nil(t, err)
+	tokenService.AssertExpectations(t)
+}
+
+func TestSendPasswordReset_ErrorIssuingToken(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com"}
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").
+		Once().Return(user, nil)
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("CountIssuedSince", mock.Anything, user.ID, domain.TokenPurposePasswordReset, mock.Anything).
+		Once().Return(0, nil)
+	tokenService.On("Issue", mock.Anything, user.ID, domain.TokenPurposePasswordReset, "", 30*time.Minute).
+		Once().Return("", errors.New("boom"))
+
+	err := newService(userRepo, nil, tokenService, nil).sendPasswordReset(context.TODO(), "alice")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	tokenService.AssertExpectations(t)
+}
+
+func TestSendPasswordReset_NotificationFailureIsHidden(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice"}
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "alice").
+		Once().Return(user, nil)
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("CountIssuedSince", mock.Anything, user.ID, domain.TokenPurposePasswordReset, mock.Anything).
+		Once().Return(0, nil)
+	tokenService.On("Issue", mock.Anything, user.ID, domain.TokenPurposePasswordReset, "", 30*time.Minute).
+		Once().Return("the-token", nil)
+
+	notifier := new(mocks.Notifier)
+	notifier.On("Notify", mock.Anything, user, mock.AnythingOfType("domain.Notification")).
+		Once().Return(domain.ErrBadParamInput)
+
+	err := newService(userRepo, nil, tokenService, notifier).sendPasswordReset(context.TODO(), "alice")
+	assert.Nil(t, err)
+	notifier.AssertExpectations(t)
+}
+
+func TestSendPasswordReset_SuccessByEmail(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com"}
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "Alice@example.com").
+		Once().Return(nil, sql.ErrNoRows)
+	userRepo.On("GetByEmail", mock.Anything, "Alice@example.com").
+		Once().Return(user, nil)
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("CountIssuedSince", mock.Anything, user.ID, domain.TokenPurposePasswordReset, mock.Anything).
+		Once().Return(2, nil)
+	tokenService.On("Issue", mock.Anything, user.ID, domain.TokenPurposePasswordReset, "", 30*time.Minute).
+		Once().Return("the-token", nil)
+
+	notifier := new(mocks.Notifier)
+	notifier.On("Notify", mock.Anything, user, mock.MatchedBy(func(n domain.Notification) bool {
+		return n.Subject == "Reset your password" &&
+			strings.Contains(n.Body, "Hello alice") &&
+			strings.Contains(n.Body, "https://example.com/reset-password?token=the-token") &&
+			strings.Contains(n.Body, "expires in 30 minutes")
+	})).Once().Return(nil)
+
+	err := newService(userRepo, nil, tokenService, notifier).sendPasswordReset(context.TODO(), "Alice@example.com")
+	assert.Nil(t, err)
+	userRepo.AssertExpectations(t)
+	tokenService.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Sets a new password with a reset token, which counts as a password change.
+// Signs the user out by deleting its refresh token.
+func (s DefaultPasswordResetService) ResetPassword(ctx context.Context, token string, newPassword string) error {
+	if len(newPassword) == 0 {
+		return domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	reset, err := s.OneTimeTokenService.Consume(ctx, domain.TokenPurposePasswordReset, token)
+	if err != nil {
+		return err
+	}
+	// The user is looked up in the organization the token was issued in.
+	ctx = domain.WithOrganization(ctx, reset.OrganizationID)
+
+	user, err := s.UserRepo.GetByUUID(ctx, reset.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	// Directory users change their password in the directory, not with a local one.
+	if len(user.Backend) > 0 {
+		return domain.ErrInvalidToken
+	}
+
+	passwordBytes, err := bcrypt.GenerateFromPassword([]byte(newPassword), s.BcryptHashingCost)
+	if err != nil {
+		return err
+	}
+
+	if err = s.UserRepo.ChangePassword(ctx, user.ID, string(passwordBytes)); err != nil {
+		return err
+	}
+
+	if user.RefreshTokenId.Valid {
+		if err = s.RefreshTokenRepo.Delete(ctx, user.RefreshTokenId.UUID); err != nil {
+			return err
+		}
+	}
+
+	// The other links sent to the user stop working.
+	if err = s.OneTimeTokenService.Revoke(ctx, user.ID, domain.TokenPurposePasswordReset); err != nil {
+		s.Logger.Printf("error revoking the password resets of user {%s}: %v\n", user.ID.String(), err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestResetPassword_EmptyPassword(t *testing.T) {
+	err := newService(nil, nil, nil, nil).ResetPassword(context.TODO(), "the-token", "")
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestResetPassword_InvalidToken(t *testing.T) {
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposePasswordReset, "the-token").
+		Once().Return(domain.OneTimeToken{}, domain.ErrInvalidToken)
+
+	err := newService(nil, nil, tokenService, nil).ResetPassword(context.TODO(), "the-token", "new password")
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	tokenService.AssertExpectations(t)
+}
+
+func TestResetPassword_UserDeleted(t *testing.T) {
+	userID := uuid.New()
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposePasswordReset, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID}, nil)
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userID).
+		Once().Return(nil, sql.ErrNoRows)
+
+	err := newService(userRepo, nil, tokenService, nil).ResetPassword(context.TODO(), "the-token", "new password")
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	userRepo.AssertExpectations(t)
+}
+
+func TestResetPassword_DirectoryUser(t *testing.T) {
+	userID := uuid.New()
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposePasswordReset, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID}, nil)
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userID).
+		Once().Return(&domain.User{ID: userID, Backend: "ldap"}, nil)
+
+	err := newService(userRepo, nil, tokenService, nil).ResetPassword(context.TODO(), "the-token", "new password")
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	userRepo.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResetPassword_ScopedToOrganizationOfToken(t *testing.T) {
+	userID, organizationID := uuid.New(), uuid.New()
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposePasswordReset, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID, OrganizationID: organizationID}, nil)
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.MatchedBy(func(ctx context.Context) bool {
+		return domain.OrganizationFromContext(ctx) == organizationID
+	}), userID).Once().Return(nil, sql.ErrNoRows)
+
+	err := newService(userRepo, nil, tokenService, nil).ResetPassword(context.TODO(), "the-token", "new password")
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	userRepo.AssertExpectations(t)
+}
+
+func TestResetPassword_ErrorUpdatingPassword(t *testing.T) {
+	userID := uuid.New()
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposePasswordReset, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID}, nil)
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userID).
+		Once().Return(&domain.User{ID: userID}, nil)
+	userRepo.On("ChangePassword", mock.Anything, userID, mock.AnythingOfType("string")).
+		Once().Return(errors.New("boom"))
+
+	err := newService(userRepo, nil, tokenService, nil).ResetPassword(context.TODO(), "the-token", "new password")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	userRepo.AssertExpectations(t)
+}
+
+func TestResetPassword_ErrorDeletingRefreshToken(t *testing.T) {
+	userID, refreshTokenID := uuid.New(), uuid.New()
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposePasswordReset, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID}, nil)
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userID).
+		Once().Return(&domain.User{ID: userID, RefreshTokenId: uuid.NullUUID{UUID: refreshTokenID, Valid: true}}, nil)
+	userRepo.On("ChangePassword", mock.Anything, userID, mock.AnythingOfType("string")).
+		Once().Return(nil)
+
+	refreshTokenRepo := new(mocks.RefreshTokenRepository)
+	refreshTokenRepo.On("Delete", mock.Anything, refreshTokenID).
+		Once().Return(errors.New("boom"))
+
+	err := newService(userRepo, refreshTokenRepo, tokenService, nil).ResetPassword(context.TODO(), "the-token", "new password")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	refreshTokenRepo.AssertExpectations(t)
+}
+
+func TestResetPassword_Success(t *testing.T) {
+	userID, refreshTokenID := uuid.New(), uuid.New()
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposePasswordReset, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID}, nil)
+	tokenService.On("Revoke", mock.Anything, userID, domain.TokenPurposePasswordReset).
+		Once().Return(nil)
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userID).
+		Once().Return(&domain.User{ID: userID, RefreshTokenId: uuid.NullUUID{UUID: refreshTokenID, Valid: true}}, nil)
+	userRepo.On("ChangePassword", mock.Anything, userID, mock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte("new password")) == nil
+	})).Once().Return(nil)
+
+	refreshTokenRepo := new(mocks.RefreshTokenRepository)
+	refreshTokenRepo.On("Delete", mock.Anything, refreshTokenID).
+		Once().Return(nil)
+
+	err := newService(userRepo, refreshTokenRepo, tokenService, nil).ResetPassword(context.TODO(), "the-token", "new password")
+	assert.Nil(t, err)
+	tokenService.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+	refreshTokenRepo.AssertExpectations(t)
+}
+
+func TestResetPassword_SuccessWithoutRefreshToken(t *testing.T) {
+	userID := uuid.New()
+
+	tokenService := new(mocks.OneTimeTokenService)
+	tokenService.On("Consume", mock.Anything, domain.TokenPurposePasswordReset, "the-token").
+		Once().Return(domain.OneTimeToken{UserID: userID}, nil)
+	tokenService.On("Revoke", mock.Anything, userID, domain.TokenPurposePasswordReset).
+		Once().Return(errors.New("revoking errors are only logged"))
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userID).
+		Once().Return(&domain.User{ID: userID}, nil)
+	userRepo.On("ChangePassword", mock.Anything, userID, mock.AnythingOfType("string")).
+		Once().Return(nil)
+
+	err := newService(userRepo, nil, tokenService, nil).ResetPassword(context.TODO(), "the-token", "new password")
+	assert.Nil(t, err)
+	tokenService.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
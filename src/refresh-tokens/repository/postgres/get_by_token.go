@@ -10,7 +10,7 @@ import (
 // Gets a token
 func (r PostgresRepository) GetByToken(ctx context.Context, token uuid.UUID) (domain.RefreshToken, error) {
 	query := `
-		SELECT id, token, valid_until
+		SELECT id, token, valid_until, organization_id
 		FROM refresh_tokens
 		WHERE token = $1
 	`
@@ -27,6 +27,7 @@ func (r PostgresRepository) GetByToken(ctx context.Context, token uuid.UUID) (do
 		&result.Id,
 		&result.Token,
 		&result.ValidUntil,
+		&result.OrganizationID,
 	)
 	if err != nil {
 		return domain.RefreshToken{}, err
@@ -19,7 +19,7 @@ func TestGetByToken_ErrorPreparingContext(t *testing.T) {
 	defer db.Close()
 
 	query := `
-		SELECT id, token, valid_until
+		SELECT id, token, valid_until, organization_id
 		FROM refresh_tokens
 		WHERE token = $1
 	`
@@ -39,7 +39,7 @@ func TestGetByToken_TimeoutReached(t *testing.T) {
 
 	id := uuid.New()
 	query := `
-		SELECT id, token, valid_until
+		SELECT id, token, valid_until, organization_id
 		FROM refresh_tokens
 		WHERE token = $1
 	`
@@ -69,11 +69,11 @@ func TestGetByToken_Success(t *testing.T) {
 	defer db.Close()
 
 	expectedResult := sqlmock.NewRows(
-		[]string{"id", "token", "valid_until"},
-	).AddRow(id, token, createdAt)
+		[]string{"id", "token", "valid_until", "organization_id"},
+	).AddRow(id, token, createdAt, domain.DefaultOrganizationID)
 
 	query := `
-		SELECT id, token, valid_until
+		SELECT id, token, valid_until, organization_id
 		FROM refresh_tokens
 		WHERE token = $1
 	`
@@ -91,5 +91,6 @@ func TestGetByToken_Success(t *testing.T) {
 	assert.Equal(t, res.Id, id)
 	assert.Equal(t, res.Token, token)
 	assert.Equal(t, res.ValidUntil, createdAt)
+	assert.Equal(t, res.OrganizationID, domain.DefaultOrganizationID)
 	assert.Nil(t, err)
 }
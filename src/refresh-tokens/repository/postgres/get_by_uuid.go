@@ -10,7 +10,7 @@ import (
 // Gets a single token by UUID
 func (r PostgresRepository) GetByUUID(ctx context.Context, id uuid.UUID) (domain.RefreshToken, error) {
 	query := `
-		SELECT id, token, valid_until
+		SELECT id, token, valid_until, organization_id
 		FROM refresh_tokens
 		WHERE id = $1
 	`
@@ -26,6 +26,7 @@ func (r PostgresRepository) GetByUUID(ctx context.Context, id uuid.UUID) (domain
 		&result.Id,
 		&result.Token,
 		&result.ValidUntil,
+		&result.OrganizationID,
 	)
 
 	if err != nil {
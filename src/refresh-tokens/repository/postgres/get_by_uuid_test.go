@@ -19,7 +19,7 @@ func Test_GetByUuid_ErrorPreparingContext(t *testing.T) {
 	defer db.Close()
 
 	query := `
-		SELECT id, token, valid_until
+		SELECT id, token, valid_until, organization_id
 		FROM refresh_tokens
 		WHERE id = $1
 	`
@@ -39,7 +39,7 @@ func Test_GetByUuid_TimeoutReached(t *testing.T) {
 
 	id := uuid.New()
 	query := `
-		SELECT id, token, valid_until
+		SELECT id, token, valid_until, organization_id
 		FROM refresh_tokens
 		WHERE id = $1
 	`
@@ -69,11 +69,11 @@ func Test_GetByUuid_Success(t *testing.T) {
 	defer db.Close()
 
 	expectedResult := sqlmock.NewRows(
-		[]string{"id", "token", "valid_until"},
-	).AddRow(id, token, createdAt)
+		[]string{"id", "token", "valid_until", "organization_id"},
+	).AddRow(id, token, createdAt, domain.DefaultOrganizationID)
 
 	query := `
-		SELECT id, token, valid_until
+		SELECT id, token, valid_until, organization_id
 		FROM refresh_tokens
 		WHERE id = $1
 	`
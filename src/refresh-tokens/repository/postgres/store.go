@@ -10,9 +10,9 @@ import (
 // Stores the token into the DB
 func (r PostgresRepository) Store(ctx context.Context, token domain.RefreshToken) (domain.RefreshToken, error) {
 	query := `
-		INSERT INTO refresh_tokens(id, token, valid_until)
-		VALUES ($1, $2, $3)
-		RETURNING id, token, valid_until
+		INSERT INTO refresh_tokens(id, token, valid_until, organization_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, token, valid_until, organization_id
 	`
 
 	stmt, err := r.Db.PrepareContext(ctx, query)
@@ -23,13 +23,17 @@ func (r PostgresRepository) Store(ctx context.Context, token domain.RefreshToken
 	if token.Id == uuid.Nil {
 		token.Id = uuid.New()
 	}
+	if token.OrganizationID == uuid.Nil {
+		token.OrganizationID = domain.OrganizationFromContext(ctx)
+	}
 
 	result := domain.RefreshToken{}
-	row := stmt.QueryRowContext(ctx, token.Id, token.Token, token.ValidUntil)
+	row := stmt.QueryRowContext(ctx, token.Id, token.Token, token.ValidUntil, token.OrganizationID)
 	err = row.Scan(
 		&result.Id,
 		&result.Token,
 		&result.ValidUntil,
+		&result.OrganizationID,
 	)
 
 	if err != nil {
@@ -22,13 +22,13 @@ func TestStore_FailPrepareQuery(t *testing.T) {
 	validUntil := time.Now()
 
 	query := `
-		INSERT INTO refresh_tokens(id, token, valid_until)
-		VALUES ($1, $2, $3)
-		RETURNING id, token, valid_until
+		INSERT INTO refresh_tokens(id, token, valid_until, organization_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, token, valid_until, organization_id
 	`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs(id, token, validUntil).
+		WithArgs(id, token, validUntil, domain.DefaultOrganizationID).
 		WillReturnError(errors.New("boom"))
 
 	res, err := New(db).Store(context.TODO(), domain.RefreshToken{
@@ -51,13 +51,13 @@ func TestStore_TimeoutReached(t *testing.T) {
 	validUntil := time.Now()
 
 	query := `
-		INSERT INTO refresh_tokens(id, token, valid_until)
-		VALUES ($1, $2, $3)
-		RETURNING id, token, valid_until
+		INSERT INTO refresh_tokens(id, token, valid_until, organization_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, token, valid_until, organization_id
 	`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs(id, token, validUntil).
+		WithArgs(id, token, validUntil, domain.DefaultOrganizationID).
 		WillDelayFor(time.Duration(time.Millisecond * 150)).
 		WillReturnError(errors.New("doessn't matter"))
 
@@ -83,17 +83,17 @@ func TestStore_Success(t *testing.T) {
 	validUntil := time.Now()
 
 	query := `
-		INSERT INTO refresh_tokens(id, token, valid_until)
-		VALUES ($1, $2, $3)
-		RETURNING id, token, valid_until
+		INSERT INTO refresh_tokens(id, token, valid_until, organization_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, token, valid_until, organization_id
 	`
 
-	expectedResult := sqlmock.NewRows([]string{"id", "token", "valid_until"})
-	expectedResult.AddRow(id, token, validUntil)
+	expectedResult := sqlmock.NewRows([]string{"id", "token", "valid_until", "organization_id"})
+	expectedResult.AddRow(id, token, validUntil, domain.DefaultOrganizationID)
 
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs(id, token, validUntil).
+		WithArgs(id, token, validUntil, domain.DefaultOrganizationID).
 		WillReturnError(nil).
 		WillReturnRows(expectedResult)
 
@@ -107,8 +107,9 @@ func TestStore_Success(t *testing.T) {
 	})
 	assert.Nil(t, err)
 	assert.Equal(t, res, domain.RefreshToken{
-		Id:         id,
-		Token:      token,
-		ValidUntil: validUntil,
+		Id:             id,
+		Token:          token,
+		ValidUntil:     validUntil,
+		OrganizationID: domain.DefaultOrganizationID,
 	})
 }
@@ -41,8 +41,9 @@ func (s DefaultRefreshTokenService) GenerateRefreshToken(ctx context.Context, us
 
 	newToken := uuid.New()
 	refreshTokenIn := domain.RefreshToken{
-		Token:      newToken,
-		ValidUntil: validUntil,
+		Token:          newToken,
+		ValidUntil:     validUntil,
+		OrganizationID: user.OrganizationID,
 	}
 
 	refreshToken, err := s.TokenRepo.Store(ctx, refreshTokenIn)
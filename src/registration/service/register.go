@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/helpers"
+)
+
+// Registers a new user with the default role.
+// The registration settings are global, so users can only sign themselves up in the default organization.
+func (s DefaultRegistrationService) Register(ctx context.Context, request domain.RegisterUserRequest) (*domain.User, error) {
+	if !s.Settings.Enabled || domain.OrganizationFromContext(ctx) != domain.DefaultOrganizationID {
+		return nil, domain.ErrRegistrationDisabled
+	}
+
+	email := strings.TrimSpace(request.Email)
+	if len(s.Settings.AllowedEmailDomains) > 0 {
+		if !helpers.IsValidEmail(email) {
+			return nil, domain.ErrBadParamInput
+		}
+		if !s.isEmailDomainAllowed(email) {
+			return nil, domain.ErrEmailDomainNotAllowed
+		}
+	}
+
+	if len(request.Password) == 0 {
+		return nil, domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	return s.UserService.Store(ctx, domain.StoreUserRequest{
+		Username: request.Username,
+		Email:    email,
+		Password: request.Password,
+		RoleSlug: s.Settings.DefaultRoleSlug,
+	})
+}
+
+// Checks if the domain of an email is in the allowed ones.
+func (s DefaultRegistrationService) isEmailDomainAllowed(email string) bool {
+	emailDomain := email[strings.LastIndex(email, "@")+1:]
+	for _, allowed := range s.Settings.AllowedEmailDomains {
+		if strings.EqualFold(emailDomain, strings.TrimPrefix(allowed, "@")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var enabledSettings = domain.RegistrationSettings{
+	Enabled:         true,
+	DefaultRoleSlug: domain.DEFAULT_ROLE_USER.RoleSlug,
+}
+
+func TestRegister_Disabled(t *testing.T) {
+	user, err := newService(nil, domain.RegistrationSettings{}).Register(context.TODO(), domain.RegisterUserRequest{
+		Username: "alice",
+		Password: "password",
+	})
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrRegistrationDisabled, err)
+}
+
+func TestRegister_DisabledOutsideDefaultOrganization(t *testing.T) {
+	userService := new(mocks.UserService)
+	ctx := domain.WithOrganization(context.TODO(), uuid.New())
+
+	user, err := newService(userService, enabledSettings).Register(ctx, domain.RegisterUserRequest{
+		Username: "alice",
+		Password: "password",
+	})
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrRegistrationDisabled, err)
+	userService.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+}
+
+func TestRegister_EmptyPassword(t *testing.T) {
+	user, err := newService(nil, enabledSettings).Register(context.TODO(), domain.RegisterUserRequest{
+		Username: "alice",
+	})
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestRegister_EmailDomainRestrictions(t *testing.T) {
+	settings := enabledSettings
+	settings.AllowedEmailDomains = []string{"example.com", "@example.org"}
+	s := newService(nil, settings)
+
+	tests := []struct {
+		email     string
+		wantedErr error
+	}{
+		{"", domain.ErrBadParamInput},
+		{"alice", domain.ErrBadParamInput},
+		{"alice@evil.com", domain.ErrEmailDomainNotAllowed},
+		{"alice@sub.example.com", domain.ErrEmailDomainNotAllowed},
+		{"alice@example.com.evil.com", domain.ErrEmailDomainNotAllowed},
+	}
+
+	for _, test := range tests {
+		user, err := s.Register(context.TODO(), domain.RegisterUserRequest{
+			Username: "alice",
+			Email:    test.email,
+			Password: "password",
+		})
+		assert.Nil(t, user)
+		assert.Equal(t, test.wantedErr, err, test.email)
+	}
+}
+
+func TestRegister_AllowedEmailDomain(t *testing.T) {
+	settings := enabledSettings
+	settings.AllowedEmailDomains = []string{"example.com", "@example.org"}
+
+	userService := new(mocks.UserService)
+	userService.On("Store", mock.Anything, domain.StoreUserRequest{
+		Username: "alice",
+		Email:    "alice@Example.ORG",
+		Password: "password",
+		RoleSlug: "user",
+	}).Once().Return(&domain.User{ID: uuid.New()}, nil)
+
+	user, err := newService(userService, settings).Register(context.TODO(), domain.RegisterUserRequest{
+		Username: "alice",
+		Email:    " alice@Example.ORG ",
+		Password: "password",
+	})
+	assert.Nil(t, err)
+	assert.NotNil(t, user)
+	userService.AssertExpectations(t)
+}
+
+func TestRegister_ErrorStoringUser(t *testing.T) {
+	userService := new(mocks.UserService)
+	userService.On("Store", mock.Anything, mock.AnythingOfType("domain.StoreUserRequest")).
+		Once().Return(nil, errors.New("boom"))
+
+	user, err := newService(userService, enabledSettings).Register(context.TODO(), domain.RegisterUserRequest{
+		Username: "alice",
+		Password: "password",
+	})
+	assert.Nil(t, user)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	userService.AssertExpectations(t)
+}
+
+func TestRegister_UsesConfiguredRole(t *testing.T) {
+	settings := enabledSettings
+	settings.DefaultRoleSlug = "member"
+
+	userService := new(mocks.UserService)
+	userService.On("Store", mock.Anything, domain.StoreUserRequest{
+		Username: "alice",
+		Password: "password",
+		RoleSlug: "member",
+	}).Once().Return(&domain.User{ID: uuid.New()}, nil)
+
+	user, err := newService(userService, settings).Register(context.TODO(), domain.RegisterUserRequest{
+		Username: "alice",
+		Password: "password",
+	})
+	assert.Nil(t, err)
+	assert.NotNil(t, user)
+	userService.AssertExpectations(t)
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type DefaultRegistrationService struct {
+	Logger         *log.Logger
+	UserService    domain.UserService
+	ContextTimeout time.Duration
+	Settings       domain.RegistrationSettings
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	userService domain.UserService,
+	contextTimeout time.Duration,
+	settings domain.RegistrationSettings,
+) domain.RegistrationService {
+	return DefaultRegistrationService{logger, userService, contextTimeout, settings}
+}
+
+// Instantiation for tests
+func newService(userService domain.UserService, settings domain.RegistrationSettings) DefaultRegistrationService {
+	return DefaultRegistrationService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		userService,
+		time.Duration(5 * time.Second),
+		settings,
+	}
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Adds the days the passwords of the users of a role are valid for, 0 when they never expire.
+func AddPasswordMaxAge(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		ALTER TABLE IF EXISTS roles
+		ADD COLUMN IF NOT EXISTS password_max_age_days integer NOT NULL DEFAULT 0;
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewAddPasswordMaxAgeMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "add-role-password-max-age",
+		Up:   AddPasswordMaxAge,
+	}
+}
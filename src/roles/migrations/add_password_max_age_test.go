@@ -0,0 +1,74 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddPasswordMaxAge_FailExec(t *testing.T) {
+	migration := NewAddPasswordMaxAgeMigration()
+	assert.Equal(t, migration.Name, "add-role-password-max-age")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		ALTER TABLE IF EXISTS roles
+		ADD COLUMN IF NOT EXISTS password_max_age_days integer NOT NULL DEFAULT 0;
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestAddPasswordMaxAge_TimeoutReached(t *testing.T) {
+	migration := NewAddPasswordMaxAgeMigration()
+	assert.Equal(t, migration.Name, "add-role-password-max-age")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		ALTER TABLE IF EXISTS roles
+		ADD COLUMN IF NOT EXISTS password_max_age_days integer NOT NULL DEFAULT 0;
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestAddPasswordMaxAge_Success(t *testing.T) {
+	migration := NewAddPasswordMaxAgeMigration()
+	assert.Equal(t, migration.Name, "add-role-password-max-age")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		ALTER TABLE IF EXISTS roles
+		ADD COLUMN IF NOT EXISTS password_max_age_days integer NOT NULL DEFAULT 0;
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
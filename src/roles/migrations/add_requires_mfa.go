@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Adds the flag forcing the users of a role to use multi-factor authentication.
+func AddRequiresMFA(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		ALTER TABLE IF EXISTS roles
+		ADD COLUMN IF NOT EXISTS requires_mfa boolean NOT NULL DEFAULT false;
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewAddRequiresMFAMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "add-role-requires-mfa",
+		Up:   AddRequiresMFA,
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -33,34 +34,34 @@ func TestAddDefaultRoles_SkipIfRoleExists(t *testing.T) {
 	assert.Nil(t, err)
 
 	createdAt := time.Now()
-	expectedResult := sqlmock.NewRows([]string{"id", "role_slug", "role_label", "created_at", "updated_at"})
-	expectedResult.AddRow(uuid.New(), "admin", "Administrator", createdAt, createdAt)
-	expectedResult2 := sqlmock.NewRows([]string{"id", "role_slug", "role_label", "created_at", "updated_at"})
+	expectedResult := sqlmock.NewRows([]string{"id", "role_slug", "role_label", "requires_mfa", "password_max_age_days", "version", "created_at", "updated_at"})
+	expectedResult.AddRow(uuid.New(), "admin", "Administrator", false, 0, 1, createdAt, createdAt)
+	expectedResult2 := sqlmock.NewRows([]string{"id", "role_slug", "role_label", "requires_mfa", "password_max_age_days", "version", "created_at", "updated_at"})
 
-	query := `SELECT id, role_slug, role_label, created_at, updated_at FROM roles WHERE deleted_at IS NULL and role_slug=$1;`
+	query := `SELECT id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at FROM roles WHERE deleted_at IS NULL and role_slug=$1 and organization_id=$2;`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs("admin").
+		WithArgs("admin", domain.DefaultOrganizationID).
 		WillReturnRows(expectedResult).
 		WillReturnError(nil)
 
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs("user").
+		WithArgs("user", domain.DefaultOrganizationID).
 		WillReturnRows(expectedResult2).
 		WillReturnError(errors.New("not existant"))
 
-	insertedResult := sqlmock.NewRows([]string{"id", "role_slug", "role_label", "created_at", "updated_at"})
-	insertedResult.AddRow(uuid.New(), "user", "User", createdAt, createdAt)
+	insertedResult := sqlmock.NewRows([]string{"id", "role_slug", "role_label", "requires_mfa", "password_max_age_days", "version", "created_at", "updated_at"})
+	insertedResult.AddRow(uuid.New(), "user", "User", false, 0, 1, createdAt, createdAt)
 
 	query = `
-		INSERT INTO roles (id, role_slug, role_label, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5) 
-		RETURNING id, role_slug, role_label, created_at, updated_at
+		INSERT INTO roles (id, role_slug, role_label, requires_mfa, password_max_age_days, created_at, updated_at, organization_id) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
+		RETURNING id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at
 	`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs(sqlmock.AnyArg(), "user", "User", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WithArgs(sqlmock.AnyArg(), "user", "User", false, 0, sqlmock.AnyArg(), sqlmock.AnyArg(), domain.DefaultOrganizationID).
 		WillReturnRows(insertedResult).
 		WillReturnError(nil)
 
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Adds the version of the roles, incremented on every change to detect concurrent ones.
+func AddVersion(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		ALTER TABLE IF EXISTS roles
+		ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewAddVersionMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "add-role-version",
+		Up:   AddVersion,
+	}
+}
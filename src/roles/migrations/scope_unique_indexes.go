@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Makes the slugs and labels of the roles unique among the roles that aren't deleted,
+// so deleted roles don't keep them taken.
+func ScopeUniqueIndexes(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		DROP INDEX IF EXISTS roles_organization_id_role_slug_key;
+		DROP INDEX IF EXISTS roles_organization_id_role_label_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS roles_organization_id_role_slug_key ON roles (organization_id, role_slug) WHERE deleted_at IS NULL;
+		CREATE UNIQUE INDEX IF NOT EXISTS roles_organization_id_role_label_key ON roles (organization_id, role_label) WHERE deleted_at IS NULL;
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewScopeUniqueIndexesMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "scope-role-unique-indexes",
+		Up:   ScopeUniqueIndexes,
+	}
+}
@@ -0,0 +1,80 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopeUniqueIndexes_FailExec(t *testing.T) {
+	migration := NewScopeUniqueIndexesMigration()
+	assert.Equal(t, migration.Name, "scope-role-unique-indexes")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		DROP INDEX IF EXISTS roles_organization_id_role_slug_key;
+		DROP INDEX IF EXISTS roles_organization_id_role_label_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS roles_organization_id_role_slug_key ON roles (organization_id, role_slug) WHERE deleted_at IS NULL;
+		CREATE UNIQUE INDEX IF NOT EXISTS roles_organization_id_role_label_key ON roles (organization_id, role_label) WHERE deleted_at IS NULL;
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestScopeUniqueIndexes_TimeoutReached(t *testing.T) {
+	migration := NewScopeUniqueIndexesMigration()
+	assert.Equal(t, migration.Name, "scope-role-unique-indexes")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		DROP INDEX IF EXISTS roles_organization_id_role_slug_key;
+		DROP INDEX IF EXISTS roles_organization_id_role_label_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS roles_organization_id_role_slug_key ON roles (organization_id, role_slug) WHERE deleted_at IS NULL;
+		CREATE UNIQUE INDEX IF NOT EXISTS roles_organization_id_role_label_key ON roles (organization_id, role_label) WHERE deleted_at IS NULL;
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestScopeUniqueIndexes_Success(t *testing.T) {
+	migration := NewScopeUniqueIndexesMigration()
+	assert.Equal(t, migration.Name, "scope-role-unique-indexes")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		DROP INDEX IF EXISTS roles_organization_id_role_slug_key;
+		DROP INDEX IF EXISTS roles_organization_id_role_label_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS roles_organization_id_role_slug_key ON roles (organization_id, role_slug) WHERE deleted_at IS NULL;
+		CREATE UNIQUE INDEX IF NOT EXISTS roles_organization_id_role_label_key ON roles (organization_id, role_label) WHERE deleted_at IS NULL;
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Soft deletes a role at the given version, unless a user (deleted ones included, as they
+// can be restored) or a group still holds it.
+// The role is locked first, so no user or group can be given it while it is being deleted.
+func (r Repository) Delete(ctx context.Context, id uuid.UUID, version int, deletedAt time.Time) error {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT version FROM roles WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL FOR UPDATE`
+
+	var current int
+	err = tx.QueryRowContext(ctx, query, id, domain.OrganizationFromContext(ctx)).Scan(&current)
+	if err == sql.ErrNoRows {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if current != version {
+		return domain.ErrVersionConflict
+	}
+
+	query = `
+		SELECT EXISTS (SELECT 1 FROM users WHERE role_id = $1)
+		OR EXISTS (SELECT 1 FROM group_roles WHERE role_id = $1)
+	`
+
+	var inUse bool
+	if err = tx.QueryRowContext(ctx, query, id).Scan(&inUse); err != nil {
+		return err
+	}
+	if inUse {
+		return domain.ErrRoleInUse
+	}
+
+	query = `UPDATE roles SET deleted_at = $2, updated_at = $2, version = version + 1 WHERE id = $1`
+	if _, err = tx.ExecContext(ctx, query, id, deletedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	lockQuery  = `SELECT version FROM roles WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL FOR UPDATE`
+	inUseQuery = `
+		SELECT EXISTS (SELECT 1 FROM users WHERE role_id = $1)
+		OR EXISTS (SELECT 1 FROM group_roles WHERE role_id = $1)
+	`
+	deleteQuery = `UPDATE roles SET deleted_at = $2, updated_at = $2, version = version + 1 WHERE id = $1`
+)
+
+func TestDelete_ErrorBeginningTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin().WillReturnError(errors.New("boom"))
+
+	err = New(db).Delete(context.TODO(), uuid.New(), 1, time.Now())
+	assert.EqualError(t, err, "boom")
+}
+
+func TestDelete_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+		WithArgs(id, domain.DefaultOrganizationID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = New(db).Delete(ctx, id, 1, time.Now())
+	assert.EqualError(t, err, "canceling query due to user request")
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+		WithArgs(id, domain.DefaultOrganizationID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}))
+	mock.ExpectRollback()
+
+	err = New(db).Delete(context.TODO(), id, 1, time.Now())
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+		WithArgs(id, domain.DefaultOrganizationID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+	mock.ExpectRollback()
+
+	err = New(db).Delete(context.TODO(), id, 1, time.Now())
+	assert.Equal(t, domain.ErrVersionConflict, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete_InUse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+		WithArgs(id, domain.DefaultOrganizationID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(inUseQuery)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"in_use"}).AddRow(true))
+	mock.ExpectRollback()
+
+	err = New(db).Delete(context.TODO(), id, 1, time.Now())
+	assert.Equal(t, domain.ErrRoleInUse, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	deletedAt := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+		WithArgs(id, domain.DefaultOrganizationID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(inUseQuery)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"in_use"}).AddRow(false))
+	mock.ExpectExec(regexp.QuoteMeta(deleteQuery)).
+		WithArgs(id, deletedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = New(db).Delete(context.TODO(), id, 1, deletedAt)
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -3,12 +3,16 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/plagioriginal/user-microservice/domain"
 )
 
+const uniqueViolationCode = "23505"
+
 type Repository struct {
 	Db *sql.DB
 }
@@ -21,8 +25,8 @@ func New(db *sql.DB) domain.RoleRepository {
 func (r Repository) Fetch(ctx context.Context) ([]domain.Role, error) {
 	result := make([]domain.Role, 0)
 
-	query := `SELECT id, role_slug, role_label, created_at, updated_at FROM roles WHERE deleted_at IS NULL;`
-	rows, err := r.Db.QueryContext(ctx, query)
+	query := `SELECT id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at FROM roles WHERE deleted_at IS NULL and organization_id=$1;`
+	rows, err := r.Db.QueryContext(ctx, query, domain.OrganizationFromContext(ctx))
 	if err != nil {
 		return result, err
 	}
@@ -33,6 +37,9 @@ func (r Repository) Fetch(ctx context.Context) ([]domain.Role, error) {
 			&role.ID,
 			&role.RoleSlug,
 			&role.RoleLabel,
+			&role.RequiresMFA,
+			&role.PasswordMaxAgeDays,
+			&role.Version,
 			&role.CreatedAt,
 			&role.UpdatedAt,
 		)
@@ -48,18 +55,21 @@ func (r Repository) Fetch(ctx context.Context) ([]domain.Role, error) {
 // Gets role by slug
 func (r Repository) GetBySlug(ctx context.Context, slug string) (domain.Role, error) {
 	result := domain.Role{}
-	query := `SELECT id, role_slug, role_label, created_at, updated_at FROM roles WHERE deleted_at IS NULL and role_slug=$1;`
+	query := `SELECT id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at FROM roles WHERE deleted_at IS NULL and role_slug=$1 and organization_id=$2;`
 
 	stmt, err := r.Db.PrepareContext(ctx, query)
 	if err != nil {
 		return result, err
 	}
 
-	row := stmt.QueryRowContext(ctx, slug)
+	row := stmt.QueryRowContext(ctx, slug, domain.OrganizationFromContext(ctx))
 	err = row.Scan(
 		&result.ID,
 		&result.RoleSlug,
 		&result.RoleLabel,
+		&result.RequiresMFA,
+		&result.PasswordMaxAgeDays,
+		&result.Version,
 		&result.CreatedAt,
 		&result.UpdatedAt,
 	)
@@ -70,18 +80,21 @@ func (r Repository) GetBySlug(ctx context.Context, slug string) (domain.Role, er
 // Gets role by UUID
 func (r Repository) GetByUUID(ctx context.Context, uuid uuid.UUID) (domain.Role, error) {
 	result := domain.Role{}
-	query := `SELECT id, role_slug, role_label, created_at, updated_at FROM roles WHERE deleted_at IS NULL and id=$1;`
+	query := `SELECT id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at FROM roles WHERE deleted_at IS NULL and id=$1 and organization_id=$2;`
 
 	stmt, err := r.Db.PrepareContext(ctx, query)
 	if err != nil {
 		return result, err
 	}
 
-	row := stmt.QueryRowContext(ctx, uuid)
+	row := stmt.QueryRowContext(ctx, uuid, domain.OrganizationFromContext(ctx))
 	err = row.Scan(
 		&result.ID,
 		&result.RoleSlug,
 		&result.RoleLabel,
+		&result.RequiresMFA,
+		&result.PasswordMaxAgeDays,
+		&result.Version,
 		&result.CreatedAt,
 		&result.UpdatedAt,
 	)
@@ -89,7 +102,8 @@ func (r Repository) GetByUUID(ctx context.Context, uuid uuid.UUID) (domain.Role,
 	return result, err
 }
 
-// Stores a new role into the DB
+// Stores a new role into the DB.
+// Fails when another role of the organization has the same slug or label.
 func (r Repository) Store(ctx context.Context, role domain.Role) (domain.Role, error) {
 	result := domain.Role{}
 
@@ -98,9 +112,9 @@ func (r Repository) Store(ctx context.Context, role domain.Role) (domain.Role, e
 	}
 
 	query := `
-		INSERT INTO roles (id, role_slug, role_label, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5) 
-		RETURNING id, role_slug, role_label, created_at, updated_at
+		INSERT INTO roles (id, role_slug, role_label, requires_mfa, password_max_age_days, created_at, updated_at, organization_id) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
+		RETURNING id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at
 	`
 
 	stmt, err := r.Db.PrepareContext(ctx, query)
@@ -108,16 +122,23 @@ func (r Repository) Store(ctx context.Context, role domain.Role) (domain.Role, e
 		return result, err
 	}
 
-	row := stmt.QueryRowContext(ctx, role.ID, role.RoleSlug, role.RoleLabel, time.Now(), time.Now())
+	row := stmt.QueryRowContext(ctx, role.ID, role.RoleSlug, role.RoleLabel, role.RequiresMFA, role.PasswordMaxAgeDays, time.Now(), time.Now(), domain.OrganizationFromContext(ctx))
 
 	err = row.Scan(
 		&result.ID,
 		&result.RoleSlug,
 		&result.RoleLabel,
+		&result.RequiresMFA,
+		&result.PasswordMaxAgeDays,
+		&result.Version,
 		&result.CreatedAt,
 		&result.UpdatedAt,
 	)
 
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+		return domain.Role{}, domain.ErrAlreadyExists
+	}
 	if err != nil {
 		return domain.Role{}, err
 	}
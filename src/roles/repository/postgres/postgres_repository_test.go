@@ -10,6 +10,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/plagioriginal/user-microservice/domain"
 	"github.com/stretchr/testify/assert"
 )
@@ -26,7 +27,7 @@ func TestFetch_FailQuery(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.Nil(t, err)
 
-	query := `SELECT id, role_slug, role_label, created_at, updated_at FROM roles WHERE deleted_at IS NULL;`
+	query := `SELECT id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at FROM roles WHERE deleted_at IS NULL and organization_id=$1;`
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
 		WillReturnError(errors.New("boom"))
 
@@ -41,7 +42,7 @@ func TestFetch_TimeoutReached(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.Nil(t, err)
 
-	query := `SELECT id, role_slug, role_label, created_at, updated_at FROM roles WHERE deleted_at IS NULL;`
+	query := `SELECT id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at FROM roles WHERE deleted_at IS NULL and organization_id=$1;`
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
 		WillDelayFor(time.Duration(time.Millisecond * 150)).
 		WillReturnError(errors.New("doessn't matter"))
@@ -63,18 +64,20 @@ func TestFetch_Success(t *testing.T) {
 	roleIdOne := uuid.New()
 	roleIdTwo := uuid.New()
 
-	expectedResult := sqlmock.NewRows([]string{"id", "role_slug", "role_label", "created_at", "updated_at"})
+	expectedResult := sqlmock.NewRows([]string{"id", "role_slug", "role_label", "requires_mfa", "password_max_age_days", "version", "created_at", "updated_at"})
 
 	expectedResult.
-		AddRow(roleIdOne, "slug", "Slug Role", createdAt, createdAt).
-		AddRow(roleIdTwo, "slug2", "Slug Role2", createdAt, createdAt)
+		AddRow(roleIdOne, "slug", "Slug Role", false, 0, 1, createdAt, createdAt).
+		AddRow(roleIdTwo, "slug2", "Slug Role2", false, 0, 1, createdAt, createdAt)
 
-	query := `SELECT id, role_slug, role_label, created_at, updated_at FROM roles WHERE deleted_at IS NULL;`
+	organizationID := uuid.New()
+	query := `SELECT id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at FROM roles WHERE deleted_at IS NULL and organization_id=$1;`
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(organizationID).
 		WillReturnError(nil).
 		WillReturnRows(expectedResult)
 
-	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(time.Millisecond*150))
+	ctx, cancel := context.WithTimeout(domain.WithOrganization(context.TODO(), organizationID), time.Duration(time.Millisecond*150))
 	defer cancel()
 
 	res, err := New(db).Fetch(ctx)
@@ -84,6 +87,7 @@ func TestFetch_Success(t *testing.T) {
 			ID:        roleIdOne,
 			RoleSlug:  "slug",
 			RoleLabel: "Slug Role",
+			Version:   1,
 			CreatedAt: createdAt,
 			UpdatedAt: createdAt,
 			DeletedAt: time.Time{},
@@ -92,6 +96,7 @@ func TestFetch_Success(t *testing.T) {
 			ID:        roleIdTwo,
 			RoleSlug:  "slug2",
 			RoleLabel: "Slug Role2",
+			Version:   1,
 			CreatedAt: createdAt,
 			UpdatedAt: createdAt,
 			DeletedAt: time.Time{},
@@ -103,10 +108,10 @@ func TestGetBySlug_FailPrepareQuery(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.Nil(t, err)
 
-	query := `SELECT id, role_slug, role_label, created_at, updated_at FROM roles WHERE deleted_at IS NULL and role_slug=$1;`
+	query := `SELECT id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at FROM roles WHERE deleted_at IS NULL and role_slug=$1 and organization_id=$2;`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs("slug").
+		WithArgs("slug", domain.DefaultOrganizationID).
 		WillReturnError(errors.New("boom"))
 
 	res, err := New(db).GetBySlug(context.TODO(), "slug")
@@ -120,10 +125,10 @@ func TestGetBySlug_TimeoutReached(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.Nil(t, err)
 
-	query := `SELECT id, role_slug, role_label, created_at, updated_at FROM roles WHERE deleted_at IS NULL and role_slug=$1;`
+	query := `SELECT id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at FROM roles WHERE deleted_at IS NULL and role_slug=$1 and organization_id=$2;`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs("slug").
+		WithArgs("slug", domain.DefaultOrganizationID).
 		WillDelayFor(time.Duration(time.Millisecond * 150)).
 		WillReturnError(errors.New("doessn't matter"))
 
@@ -143,13 +148,13 @@ func TestGetBySlug_Success(t *testing.T) {
 	createdAt := time.Now()
 	roleId := uuid.New()
 
-	expectedResult := sqlmock.NewRows([]string{"id", "role_slug", "role_label", "created_at", "updated_at"})
-	expectedResult.AddRow(roleId, "slug", "Slug Role", createdAt, createdAt)
+	expectedResult := sqlmock.NewRows([]string{"id", "role_slug", "role_label", "requires_mfa", "password_max_age_days", "version", "created_at", "updated_at"})
+	expectedResult.AddRow(roleId, "slug", "Slug Role", false, 0, 1, createdAt, createdAt)
 
-	query := `SELECT id, role_slug, role_label, created_at, updated_at FROM roles WHERE deleted_at IS NULL and role_slug=$1;`
+	query := `SELECT id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at FROM roles WHERE deleted_at IS NULL and role_slug=$1 and organization_id=$2;`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs("slug").
+		WithArgs("slug", domain.DefaultOrganizationID).
 		WillReturnError(nil).
 		WillReturnRows(expectedResult)
 
@@ -162,6 +167,7 @@ func TestGetBySlug_Success(t *testing.T) {
 		ID:        roleId,
 		RoleSlug:  "slug",
 		RoleLabel: "Slug Role",
+		Version:   1,
 		CreatedAt: createdAt,
 		UpdatedAt: createdAt,
 		DeletedAt: time.Time{},
@@ -173,10 +179,10 @@ func TestGetByUuid_FailPrepareQuery(t *testing.T) {
 	assert.Nil(t, err)
 
 	id := uuid.New()
-	query := `SELECT id, role_slug, role_label, created_at, updated_at FROM roles WHERE deleted_at IS NULL and id=$1;`
+	query := `SELECT id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at FROM roles WHERE deleted_at IS NULL and id=$1 and organization_id=$2;`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs(id).
+		WithArgs(id, domain.DefaultOrganizationID).
 		WillReturnError(errors.New("boom"))
 
 	res, err := New(db).GetByUUID(context.TODO(), id)
@@ -191,10 +197,10 @@ func TestGetByUuid_TimeoutReached(t *testing.T) {
 	assert.Nil(t, err)
 
 	id := uuid.New()
-	query := `SELECT id, role_slug, role_label, created_at, updated_at FROM roles WHERE deleted_at IS NULL and id=$1;`
+	query := `SELECT id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at FROM roles WHERE deleted_at IS NULL and id=$1 and organization_id=$2;`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs(id).
+		WithArgs(id, domain.DefaultOrganizationID).
 		WillDelayFor(time.Duration(time.Millisecond * 150)).
 		WillReturnError(errors.New("doessn't matter"))
 
@@ -214,14 +220,14 @@ func TestGetByUuid_Success(t *testing.T) {
 	createdAt := time.Now()
 	roleId := uuid.New()
 
-	expectedResult := sqlmock.NewRows([]string{"id", "role_slug", "role_label", "created_at", "updated_at"})
+	expectedResult := sqlmock.NewRows([]string{"id", "role_slug", "role_label", "requires_mfa", "password_max_age_days", "version", "created_at", "updated_at"})
 
-	expectedResult.AddRow(roleId, "slug", "Slug Role", createdAt, createdAt)
+	expectedResult.AddRow(roleId, "slug", "Slug Role", false, 0, 1, createdAt, createdAt)
 
-	query := `SELECT id, role_slug, role_label, created_at, updated_at FROM roles WHERE deleted_at IS NULL and id=$1;`
+	query := `SELECT id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at FROM roles WHERE deleted_at IS NULL and id=$1 and organization_id=$2;`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs(roleId).
+		WithArgs(roleId, domain.DefaultOrganizationID).
 		WillReturnError(nil).
 		WillReturnRows(expectedResult)
 
@@ -234,6 +240,7 @@ func TestGetByUuid_Success(t *testing.T) {
 		ID:        roleId,
 		RoleSlug:  "slug",
 		RoleLabel: "Slug Role",
+		Version:   1,
 		CreatedAt: createdAt,
 		UpdatedAt: createdAt,
 		DeletedAt: time.Time{},
@@ -246,13 +253,13 @@ func TestStore_FailPrepareQuery(t *testing.T) {
 
 	id := uuid.New()
 	query := `
-		INSERT INTO roles (id, role_slug, role_label, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5) 
-		RETURNING id, role_slug, role_label, created_at, updated_at
+		INSERT INTO roles (id, role_slug, role_label, requires_mfa, password_max_age_days, created_at, updated_at, organization_id) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
+		RETURNING id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at
 	`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs(id, "slug", "label", anyTime{}, anyTime{}).
+		WithArgs(id, "slug", "label", false, 0, anyTime{}, anyTime{}, domain.DefaultOrganizationID).
 		WillReturnError(errors.New("boom"))
 
 	res, err := New(db).Store(context.TODO(), domain.Role{
@@ -272,13 +279,13 @@ func TestStore_TimeoutReached(t *testing.T) {
 
 	id := uuid.New()
 	query := `
-		INSERT INTO roles (id, role_slug, role_label, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5) 
-		RETURNING id, role_slug, role_label, created_at, updated_at
+		INSERT INTO roles (id, role_slug, role_label, requires_mfa, password_max_age_days, created_at, updated_at, organization_id) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
+		RETURNING id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at
 	`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs(id, "slug", "label", anyTime{}, anyTime{}).
+		WithArgs(id, "slug", "label", false, 0, anyTime{}, anyTime{}, domain.DefaultOrganizationID).
 		WillDelayFor(time.Duration(time.Millisecond * 150)).
 		WillReturnError(errors.New("doessn't matter"))
 
@@ -302,18 +309,18 @@ func TestStore_Success(t *testing.T) {
 	createdAt := time.Now()
 	roleId := uuid.New()
 
-	expectedResult := sqlmock.NewRows([]string{"id", "role_slug", "role_label", "created_at", "updated_at"})
-	expectedResult.AddRow(roleId, "slug", "label", createdAt, createdAt)
+	expectedResult := sqlmock.NewRows([]string{"id", "role_slug", "role_label", "requires_mfa", "password_max_age_days", "version", "created_at", "updated_at"})
+	expectedResult.AddRow(roleId, "slug", "label", false, 0, 1, createdAt, createdAt)
 
 	id := uuid.New()
 	query := `
-		INSERT INTO roles (id, role_slug, role_label, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5) 
-		RETURNING id, role_slug, role_label, created_at, updated_at
+		INSERT INTO roles (id, role_slug, role_label, requires_mfa, password_max_age_days, created_at, updated_at, organization_id) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
+		RETURNING id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at
 	`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs(id, "slug", "label", anyTime{}, anyTime{}).
+		WithArgs(id, "slug", "label", false, 0, anyTime{}, anyTime{}, domain.DefaultOrganizationID).
 		WillReturnError(nil).
 		WillReturnRows(expectedResult)
 
@@ -330,8 +337,29 @@ func TestStore_Success(t *testing.T) {
 		ID:        roleId,
 		RoleSlug:  "slug",
 		RoleLabel: "label",
+		Version:   1,
 		CreatedAt: createdAt,
 		UpdatedAt: createdAt,
 		DeletedAt: time.Time{},
 	})
 }
+
+func TestStore_AlreadyExists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	id := uuid.New()
+	query := `
+		INSERT INTO roles (id, role_slug, role_label, requires_mfa, password_max_age_days, created_at, updated_at, organization_id) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
+		RETURNING id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs(id, "slug", "label", false, 0, anyTime{}, anyTime{}, domain.DefaultOrganizationID).
+		WillReturnError(&pq.Error{Code: uniqueViolationCode})
+
+	res, err := New(db).Store(context.TODO(), domain.Role{ID: id, RoleSlug: "slug", RoleLabel: "label"})
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+	assert.Empty(t, res)
+}
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Updates the label and password policies of a role, as long as it is still at its version.
+// The slug of a role never changes, as the access tokens refer to the roles by it.
+func (r Repository) Update(ctx context.Context, role domain.Role) (domain.Role, error) {
+	result := domain.Role{}
+	query := `
+		UPDATE roles
+		SET role_label = $1, requires_mfa = $2, password_max_age_days = $3, updated_at = $4, version = version + 1
+		WHERE id = $5 AND organization_id = $6 AND version = $7 AND deleted_at IS NULL
+		RETURNING id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return result, err
+	}
+
+	row := stmt.QueryRowContext(
+		ctx,
+		role.RoleLabel,
+		role.RequiresMFA,
+		role.PasswordMaxAgeDays,
+		time.Now(),
+		role.ID,
+		domain.OrganizationFromContext(ctx),
+		role.Version,
+	)
+	err = row.Scan(
+		&result.ID,
+		&result.RoleSlug,
+		&result.RoleLabel,
+		&result.RequiresMFA,
+		&result.PasswordMaxAgeDays,
+		&result.Version,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+		return domain.Role{}, domain.ErrAlreadyExists
+	}
+	if err == sql.ErrNoRows {
+		return domain.Role{}, r.missedUpdateError(ctx, role.ID)
+	}
+	if err != nil {
+		return domain.Role{}, err
+	}
+	return result, nil
+}
+
+// Tells why an update of a role at a version didn't change it: domain.ErrNotFound when
+// there is no such role, or domain.ErrVersionConflict when it is at another version.
+func (r Repository) missedUpdateError(ctx context.Context, id uuid.UUID) error {
+	query := `SELECT EXISTS (SELECT 1 FROM roles WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL)`
+
+	var exists bool
+	if err := r.Db.QueryRowContext(ctx, query, id, domain.OrganizationFromContext(ctx)).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrNotFound
+	}
+	return domain.ErrVersionConflict
+}
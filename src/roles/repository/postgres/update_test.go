@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const updateQuery = `
+		UPDATE roles
+		SET role_label = $1, requires_mfa = $2, password_max_age_days = $3, updated_at = $4, version = version + 1
+		WHERE id = $5 AND organization_id = $6 AND version = $7 AND deleted_at IS NULL
+		RETURNING id, role_slug, role_label, requires_mfa, password_max_age_days, version, created_at, updated_at
+	`
+
+const missedUpdateQuery = `SELECT EXISTS (SELECT 1 FROM roles WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL)`
+
+func newUpdatedRole() domain.Role {
+	return domain.Role{ID: uuid.New(), RoleLabel: "Auditor", RequiresMFA: true, PasswordMaxAgeDays: 90, Version: 2}
+}
+
+func TestUpdate_FailPrepareQuery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(updateQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).Update(context.TODO(), newUpdatedRole())
+	assert.EqualError(t, err, "boom")
+	assert.Empty(t, res)
+}
+
+func TestUpdate_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	role := newUpdatedRole()
+	mock.ExpectPrepare(regexp.QuoteMeta(updateQuery)).
+		ExpectQuery().
+		WithArgs("Auditor", true, 90, anyTime{}, role.ID, domain.DefaultOrganizationID, 2).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).Update(ctx, role)
+	assert.EqualError(t, err, "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestUpdate_AlreadyExists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	role := newUpdatedRole()
+	mock.ExpectPrepare(regexp.QuoteMeta(updateQuery)).
+		ExpectQuery().
+		WithArgs("Auditor", true, 90, anyTime{}, role.ID, domain.DefaultOrganizationID, 2).
+		WillReturnError(&pq.Error{Code: uniqueViolationCode})
+
+	res, err := New(db).Update(context.TODO(), role)
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+	assert.Empty(t, res)
+}
+
+func TestUpdate_MissedUpdate(t *testing.T) {
+	cases := map[string]struct {
+		exists   bool
+		expected error
+	}{
+		"not found":        {false, domain.ErrNotFound},
+		"version conflict": {true, domain.ErrVersionConflict},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.Nil(t, err)
+			defer db.Close()
+
+			role := newUpdatedRole()
+			mock.ExpectPrepare(regexp.QuoteMeta(updateQuery)).
+				ExpectQuery().
+				WithArgs("Auditor", true, 90, anyTime{}, role.ID, domain.DefaultOrganizationID, 2).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			mock.ExpectQuery(regexp.QuoteMeta(missedUpdateQuery)).
+				WithArgs(role.ID, domain.DefaultOrganizationID).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(c.exists))
+
+			res, err := New(db).Update(context.TODO(), role)
+			assert.Equal(t, c.expected, err)
+			assert.Empty(t, res)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdate_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	role := newUpdatedRole()
+	createdAt := time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(updateQuery)).
+		ExpectQuery().
+		WithArgs("Auditor", true, 90, anyTime{}, role.ID, domain.DefaultOrganizationID, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role_slug", "role_label", "requires_mfa", "password_max_age_days", "version", "created_at", "updated_at"}).
+			AddRow(role.ID, "auditor", "Auditor", true, 90, 3, createdAt, createdAt))
+
+	res, err := New(db).Update(context.TODO(), role)
+	assert.Nil(t, err)
+	assert.Equal(t, domain.Role{
+		ID:                 role.ID,
+		RoleSlug:           "auditor",
+		RoleLabel:          "Auditor",
+		RequiresMFA:        true,
+		PasswordMaxAgeDays: 90,
+		Version:            3,
+		CreatedAt:          createdAt,
+		UpdatedAt:          createdAt,
+	}, res)
+}
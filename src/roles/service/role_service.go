@@ -0,0 +1,37 @@
+package service
+
+import (
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type DefaultRoleService struct {
+	Logger         *log.Logger
+	RoleRepo       domain.RoleRepository
+	ContextTimeout time.Duration
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	roleRepo domain.RoleRepository,
+	contextTimeout time.Duration,
+) domain.RoleService {
+	return DefaultRoleService{
+		logger,
+		roleRepo,
+		contextTimeout,
+	}
+}
+
+// Instantiation for tests
+func newService(roleRepo domain.RoleRepository) DefaultRoleService {
+	return DefaultRoleService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		roleRepo,
+		time.Duration(5 * time.Second),
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Longest label of a role, as stored in the DB.
+const maxRoleLabelLength = 255
+
+// Gets all the roles of the organization.
+func (s DefaultRoleService) GetAll(ctx context.Context) ([]domain.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	return s.RoleRepo.Fetch(ctx)
+}
+
+// Creates a role, with a new ID.
+func (s DefaultRoleService) Store(ctx context.Context, role domain.Role) (domain.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	role.RoleSlug = strings.TrimSpace(role.RoleSlug)
+	role.RoleLabel = strings.TrimSpace(role.RoleLabel)
+	if !role.HasValidSlug() || !isValidRole(role) {
+		return domain.Role{}, domain.ErrBadParamInput
+	}
+
+	role.ID = uuid.Nil
+	return s.RoleRepo.Store(ctx, role)
+}
+
+// Gets a role by its slug.
+func (s DefaultRoleService) GetBySlug(ctx context.Context, slug string) (domain.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	role, err := s.RoleRepo.GetBySlug(ctx, slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Role{}, domain.ErrNotFound
+	}
+	return role, err
+}
+
+// Updates the label and password policies of a role at its version. Its slug stays the same.
+func (s DefaultRoleService) Update(ctx context.Context, role domain.Role) (domain.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	role.RoleLabel = strings.TrimSpace(role.RoleLabel)
+	if role.ID == uuid.Nil || !isValidRole(role) {
+		return domain.Role{}, domain.ErrBadParamInput
+	}
+
+	return s.RoleRepo.Update(ctx, role)
+}
+
+// Deletes a role at a version, as long as no user or group holds it.
+// The default roles are never deleted, as the organizations rely on them.
+func (s DefaultRoleService) Delete(ctx context.Context, id uuid.UUID, version int) error {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	if id == uuid.Nil {
+		return domain.ErrBadParamInput
+	}
+
+	role, err := s.RoleRepo.GetByUUID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if role.IsBuiltIn() {
+		return domain.ErrNotAllowed
+	}
+
+	return s.RoleRepo.Delete(ctx, id, version, time.Now())
+}
+
+// Returns if the label and password policies of a role can be stored.
+func isValidRole(role domain.Role) bool {
+	length := utf8.RuneCountInString(role.RoleLabel)
+	return length > 0 && length <= maxRoleLabelLength && role.PasswordMaxAgeDays >= 0
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetAll_ErrorFetching(t *testing.T) {
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("Fetch", mock.Anything).Once().Return(nil, errors.New("boom"))
+
+	res, err := newService(roleRepo).GetAll(context.TODO())
+	assert.EqualError(t, err, "boom")
+	assert.Nil(t, res)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestGetAll_Success(t *testing.T) {
+	roles := []domain.Role{{ID: uuid.New(), RoleSlug: "admin"}, {ID: uuid.New(), RoleSlug: "user"}}
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("Fetch", mock.Anything).Once().Return(roles, nil)
+
+	res, err := newService(roleRepo).GetAll(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, roles, res)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestStore_InvalidInput(t *testing.T) {
+	cases := map[string]domain.Role{
+		"no slug":          {RoleLabel: "Auditor"},
+		"uppercase slug":   {RoleSlug: "Auditor", RoleLabel: "Auditor"},
+		"slug with spaces": {RoleSlug: "audit team", RoleLabel: "Auditor"},
+		"no label":         {RoleSlug: "auditor", RoleLabel: "  "},
+		"long label":       {RoleSlug: "auditor", RoleLabel: strings.Repeat("a", 256)},
+		"negative max age": {RoleSlug: "auditor", RoleLabel: "Auditor", PasswordMaxAgeDays: -1},
+	}
+
+	for name, role := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := newService(nil).Store(context.TODO(), role)
+			assert.Equal(t, domain.ErrBadParamInput, err)
+		})
+	}
+}
+
+func TestStore_AlreadyExists(t *testing.T) {
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("Store", mock.Anything, mock.Anything).Once().Return(domain.Role{}, domain.ErrAlreadyExists)
+
+	_, err := newService(roleRepo).Store(context.TODO(), domain.Role{RoleSlug: "admin", RoleLabel: "Admin"})
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestStore_Success(t *testing.T) {
+	stored := domain.Role{ID: uuid.New(), RoleSlug: "auditor", RoleLabel: "Auditor", RequiresMFA: true, Version: 1}
+	roleRepo := new(mocks.RoleRepository)
+	// Roles are stored trimmed, and never with IDs picked by the callers.
+	roleRepo.On("Store", mock.Anything, domain.Role{RoleSlug: "auditor", RoleLabel: "Auditor", RequiresMFA: true}).
+		Once().Return(stored, nil)
+
+	res, err := newService(roleRepo).Store(context.TODO(), domain.Role{
+		ID:          uuid.New(),
+		RoleSlug:    " auditor ",
+		RoleLabel:   " Auditor ",
+		RequiresMFA: true,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, stored, res)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestGetBySlug_NotFound(t *testing.T) {
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "auditor").Once().Return(domain.Role{}, sql.ErrNoRows)
+
+	_, err := newService(roleRepo).GetBySlug(context.TODO(), "auditor")
+	assert.Equal(t, domain.ErrNotFound, err)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestUpdate_InvalidInput(t *testing.T) {
+	cases := map[string]domain.Role{
+		"no id":            {RoleLabel: "Auditor"},
+		"no label":         {ID: uuid.New(), RoleLabel: " "},
+		"negative max age": {ID: uuid.New(), RoleLabel: "Auditor", PasswordMaxAgeDays: -1},
+	}
+
+	for name, role := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := newService(nil).Update(context.TODO(), role)
+			assert.Equal(t, domain.ErrBadParamInput, err)
+		})
+	}
+}
+
+func TestUpdate_RepositoryError(t *testing.T) {
+	role := domain.Role{ID: uuid.New(), RoleLabel: "Auditor", Version: 2}
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("Update", mock.Anything, role).Once().Return(domain.Role{}, domain.ErrVersionConflict)
+
+	_, err := newService(roleRepo).Update(context.TODO(), role)
+	assert.Equal(t, domain.ErrVersionConflict, err)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestUpdate_Success(t *testing.T) {
+	role := domain.Role{ID: uuid.New(), RoleLabel: "Auditor", PasswordMaxAgeDays: 30, Version: 2}
+	updated := role
+	updated.RoleSlug = "auditor"
+	updated.Version = 3
+
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("Update", mock.Anything, role).Once().Return(updated, nil)
+
+	role.RoleLabel = " Auditor "
+	res, err := newService(roleRepo).Update(context.TODO(), role)
+	assert.Nil(t, err)
+	assert.Equal(t, updated, res)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestDelete_InvalidID(t *testing.T) {
+	err := newService(nil).Delete(context.TODO(), uuid.Nil, 1)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	id := uuid.New()
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetByUUID", mock.Anything, id).Once().Return(domain.Role{}, sql.ErrNoRows)
+
+	err := newService(roleRepo).Delete(context.TODO(), id, 1)
+	assert.Equal(t, domain.ErrNotFound, err)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestDelete_FailIfBuiltIn(t *testing.T) {
+	for _, slug := range []string{"admin", "user"} {
+		t.Run(slug, func(t *testing.T) {
+			id := uuid.New()
+			roleRepo := new(mocks.RoleRepository)
+			roleRepo.On("GetByUUID", mock.Anything, id).Once().Return(domain.Role{ID: id, RoleSlug: slug}, nil)
+
+			err := newService(roleRepo).Delete(context.TODO(), id, 1)
+			assert.Equal(t, domain.ErrNotAllowed, err)
+			roleRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestDelete_FailIfInUse(t *testing.T) {
+	id := uuid.New()
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetByUUID", mock.Anything, id).Once().Return(domain.Role{ID: id, RoleSlug: "auditor"}, nil)
+	roleRepo.On("Delete", mock.Anything, id, 4, mock.Anything).Once().Return(domain.ErrRoleInUse)
+
+	err := newService(roleRepo).Delete(context.TODO(), id, 4)
+	assert.Equal(t, domain.ErrRoleInUse, err)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestDelete_Success(t *testing.T) {
+	id := uuid.New()
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetByUUID", mock.Anything, id).Once().Return(domain.Role{ID: id, RoleSlug: "auditor"}, nil)
+	roleRepo.On("Delete", mock.Anything, id, 4, mock.Anything).Once().Return(nil)
+
+	err := newService(roleRepo).Delete(context.TODO(), id, 4)
+	assert.Nil(t, err)
+	roleRepo.AssertExpectations(t)
+}
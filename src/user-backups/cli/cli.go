@@ -0,0 +1,101 @@
+// Package cli exports and restores the users from the command line, with the same
+// archives as the ExportUsers and RestoreUsers RPCs.
+//
+// Usage:
+//
+//	main export-users [-with-passwords] [-output <file>]
+//	main restore-users [-remap-roles] <file>
+//
+// Archives are written to the standard output unless given an output file, and read
+// from the standard input when the file is "-". The restore report is written as indented JSON.
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Names of the commands, as the first argument of the binary.
+const (
+	ExportCommand  = "export-users"
+	RestoreCommand = "restore-users"
+)
+
+// Runs the export with the arguments after the command name.
+// Output files are removed when the export fails, so no truncated archive is left behind.
+func Export(ctx context.Context, service domain.UserBackupService, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet(ExportCommand, flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	withPasswords := flags.Bool("with-passwords", false, "include the password hashes of the users")
+	output := flags.String("output", "", "file to write the archive to (the standard output by default)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errors.New("usage: " + ExportCommand + " [-with-passwords] [-output <file>]")
+	}
+
+	if len(*output) == 0 {
+		w := bufio.NewWriter(stdout)
+		if err := service.Export(ctx, w, *withPasswords); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+
+	f, err := os.OpenFile(*output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	err = service.Export(ctx, w, *withPasswords)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(*output)
+	}
+	return err
+}
+
+// Runs the restore with the arguments after the command name.
+func Restore(ctx context.Context, service domain.UserBackupService, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet(RestoreCommand, flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	remapRoles := flags.Bool("remap-roles", false, "use the IDs of the existing roles with the same slugs")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: " + RestoreCommand + " [-remap-roles] <file>")
+	}
+
+	file := stdin
+	if path := flags.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		file = f
+	}
+
+	report, err := service.Restore(ctx, file, *remapRoles)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
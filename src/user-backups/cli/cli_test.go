@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Writes an archive of a single line into the export writer.
+func writeArchive(args mock.Arguments) {
+	io.WriteString(args.Get(1).(io.Writer), "archive\n")
+}
+
+func TestExport_Usage(t *testing.T) {
+	err := Export(context.TODO(), nil, []string{"users.ndjson"}, &bytes.Buffer{})
+	assert.EqualError(t, err, "usage: export-users [-with-passwords] [-output <file>]")
+}
+
+func TestExport_Stdout(t *testing.T) {
+	service := new(mocks.UserBackupService)
+	service.On("Export", mock.Anything, mock.Anything, true).Once().Run(writeArchive).Return(nil)
+
+	out := &bytes.Buffer{}
+	assert.Nil(t, Export(context.TODO(), service, []string{"-with-passwords"}, out))
+	assert.Equal(t, "archive\n", out.String())
+	service.AssertExpectations(t)
+}
+
+func TestExport_OutputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.ndjson")
+	service := new(mocks.UserBackupService)
+	service.On("Export", mock.Anything, mock.Anything, false).Once().Run(writeArchive).Return(nil)
+
+	assert.Nil(t, Export(context.TODO(), service, []string{"-output", path}, &bytes.Buffer{}))
+	content, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "archive\n", string(content))
+
+	// Existing archives aren't overwritten.
+	err = Export(context.TODO(), service, []string{"-output", path}, &bytes.Buffer{})
+	assert.True(t, errors.Is(err, os.ErrExist))
+	service.AssertExpectations(t)
+}
+
+func TestExport_RemovesTheOutputOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.ndjson")
+	service := new(mocks.UserBackupService)
+	service.On("Export", mock.Anything, mock.Anything, false).Once().Run(writeArchive).Return(errors.New("boom"))
+
+	err := Export(context.TODO(), service, []string{"-output", path}, &bytes.Buffer{})
+	assert.EqualError(t, err, "boom")
+	_, err = os.Stat(path)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestRestore_Usage(t *testing.T) {
+	err := Restore(context.TODO(), nil, []string{"-remap-roles"}, nil, &bytes.Buffer{})
+	assert.EqualError(t, err, "usage: restore-users [-remap-roles] <file>")
+}
+
+func TestRestore_FileNotFound(t *testing.T) {
+	err := Restore(context.TODO(), nil, []string{filepath.Join(t.TempDir(), "missing.ndjson")}, nil, &bytes.Buffer{})
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestRestore_Error(t *testing.T) {
+	service := new(mocks.UserBackupService)
+	service.On("Restore", mock.Anything, mock.Anything, false).Once().Return(domain.UserRestoreReport{}, errors.New("boom"))
+
+	out := &bytes.Buffer{}
+	err := Restore(context.TODO(), service, []string{"-"}, strings.NewReader(""), out)
+	assert.EqualError(t, err, "boom")
+	assert.Empty(t, out.String())
+}
+
+func TestRestore_Success(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.ndjson")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("archive\n"), 0600))
+
+	var file string
+	service := new(mocks.UserBackupService)
+	service.On("Restore", mock.Anything, mock.Anything, true).Once().
+		Run(func(args mock.Arguments) {
+			content, _ := ioutil.ReadAll(args.Get(1).(io.Reader))
+			file = string(content)
+		}).
+		Return(domain.UserRestoreReport{RolesMatched: 2, UsersRestored: 3}, nil)
+
+	out := &bytes.Buffer{}
+	assert.Nil(t, Restore(context.TODO(), service, []string{"-remap-roles", path}, nil, out))
+	assert.Equal(t, "archive\n", file)
+	assert.Contains(t, out.String(), `"usersRestored": 3`)
+	service.AssertExpectations(t)
+}
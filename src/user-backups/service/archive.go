@@ -0,0 +1,182 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Longest line accepted on a restore.
+const maxArchiveLineLength = 1024 * 1024
+
+// Prefix of the checksums of the archives.
+const checksumPrefix = "sha256:"
+
+// Writes the records of an archive, one per line, keeping the checksum of the lines.
+type archiveWriter struct {
+	w        io.Writer
+	checksum hash.Hash
+	records  int
+}
+
+func newArchiveWriter(w io.Writer) *archiveWriter {
+	return &archiveWriter{w: w, checksum: sha256.New()}
+}
+
+// Writes a record of the archive.
+func (a *archiveWriter) write(record domain.UserBackupRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if _, err = a.w.Write(line); err != nil {
+		return err
+	}
+	a.checksum.Write(line)
+	a.records++
+	return nil
+}
+
+// Writes the footer, with the checksum of every record written.
+func (a *archiveWriter) close() error {
+	footer := domain.UserBackupRecord{
+		Type: domain.UserBackupRecordFooter,
+		Footer: &domain.UserBackupFooter{
+			Records:  a.records,
+			Checksum: checksumPrefix + hex.EncodeToString(a.checksum.Sum(nil)),
+		},
+	}
+	line, err := json.Marshal(footer)
+	if err != nil {
+		return err
+	}
+	_, err = a.w.Write(append(line, '\n'))
+	return err
+}
+
+// Contents of a verified archive.
+type archive struct {
+	header      domain.UserBackupHeader
+	roles       []domain.Role
+	groups      []domain.UserBackupGroup
+	users       []domain.UserBackupUser
+	assignments map[uuid.UUID]domain.UserBackupRoleAssignment
+	memberships []domain.UserBackupGroupMembership
+}
+
+// Reads an archive, checking its version, the order of its records and its checksum.
+// Errors of invalid archives wrap domain.ErrBadParamInput.
+func readArchive(r io.Reader) (archive, error) {
+	result := archive{assignments: map[uuid.UUID]domain.UserBackupRoleAssignment{}}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxArchiveLineLength)
+	checksum := sha256.New()
+
+	invalid := func(line int, format string, args ...interface{}) error {
+		return fmt.Errorf("%w: line %d: %s", domain.ErrBadParamInput, line, fmt.Sprintf(format, args...))
+	}
+
+	// The records have to come in this order.
+	order := map[domain.UserBackupRecordType]int{
+		domain.UserBackupRecordHeader:          0,
+		domain.UserBackupRecordRole:            1,
+		domain.UserBackupRecordGroup:           2,
+		domain.UserBackupRecordUser:            3,
+		domain.UserBackupRecordRoleAssignment:  4,
+		domain.UserBackupRecordGroupMembership: 5,
+		domain.UserBackupRecordFooter:          6,
+	}
+	records, section := 0, -1
+	var footer *domain.UserBackupFooter
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if footer != nil {
+			return archive{}, invalid(line, "records after the footer")
+		}
+
+		record := domain.UserBackupRecord{}
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&record); err != nil {
+			return archive{}, invalid(line, "invalid JSON: %v", err)
+		}
+
+		position, known := order[record.Type]
+		if !known {
+			return archive{}, invalid(line, "unknown record %q", record.Type)
+		}
+		if (line == 1) != (position == 0) || position < section {
+			return archive{}, invalid(line, "unexpected %s record", record.Type)
+		}
+		section = position
+
+		switch record.Type {
+		case domain.UserBackupRecordHeader:
+			if record.Header == nil || record.Header.FormatVersion < 1 || record.Header.FormatVersion > domain.UserBackupFormatVersion {
+				return archive{}, invalid(line, "unsupported format version")
+			}
+			result.header = *record.Header
+		case domain.UserBackupRecordRole:
+			if record.Role == nil || record.Role.ID == uuid.Nil || len(record.Role.RoleSlug) == 0 {
+				return archive{}, invalid(line, "invalid role")
+			}
+			result.roles = append(result.roles, *record.Role)
+		case domain.UserBackupRecordGroup:
+			if record.Group == nil || record.Group.ID == uuid.Nil || len(record.Group.Name) == 0 {
+				return archive{}, invalid(line, "invalid group")
+			}
+			result.groups = append(result.groups, *record.Group)
+		case domain.UserBackupRecordUser:
+			if record.User == nil || record.User.ID == uuid.Nil || len(record.User.Username) == 0 || !record.User.Status.IsValid() {
+				return archive{}, invalid(line, "invalid user")
+			}
+			result.users = append(result.users, *record.User)
+		case domain.UserBackupRecordRoleAssignment:
+			if record.RoleAssignment == nil || record.RoleAssignment.UserID == uuid.Nil || record.RoleAssignment.RoleID == uuid.Nil {
+				return archive{}, invalid(line, "invalid role assignment")
+			}
+			result.assignments[record.RoleAssignment.UserID] = *record.RoleAssignment
+		case domain.UserBackupRecordGroupMembership:
+			if record.GroupMembership == nil || record.GroupMembership.GroupID == uuid.Nil || record.GroupMembership.UserID == uuid.Nil {
+				return archive{}, invalid(line, "invalid group membership")
+			}
+			result.memberships = append(result.memberships, *record.GroupMembership)
+		case domain.UserBackupRecordFooter:
+			if record.Footer == nil {
+				return archive{}, invalid(line, "invalid footer")
+			}
+			footer = record.Footer
+			continue
+		}
+
+		checksum.Write(scanner.Bytes())
+		checksum.Write([]byte{'\n'})
+		records++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return archive{}, fmt.Errorf("%w: %v", domain.ErrBadParamInput, err)
+	}
+	if line == 0 {
+		return archive{}, fmt.Errorf("%w: empty archive", domain.ErrBadParamInput)
+	}
+	if footer == nil {
+		return archive{}, fmt.Errorf("%w: missing footer, the archive is truncated", domain.ErrBadParamInput)
+	}
+	if footer.Records != records || footer.Checksum != checksumPrefix+hex.EncodeToString(checksum.Sum(nil)) {
+		return archive{}, fmt.Errorf("%w: checksum mismatch, the archive is corrupted", domain.ErrBadParamInput)
+	}
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadArchive_Invalid(t *testing.T) {
+	valid := string(exportArchive(t, backupUsers(), true))
+	lines := strings.SplitAfter(valid, "\n")
+	header, roles, groups, footer := lines[0], lines[1]+lines[2], lines[3], lines[13]
+	users, assignments, memberships := lines[4]+lines[5]+lines[6], lines[7]+lines[8]+lines[9], lines[10]+lines[11]+lines[12]
+
+	cases := map[string]string{
+		"empty":                    "",
+		"not JSON":                 "cenas\n",
+		"missing header":           roles + groups + users + assignments + memberships + footer,
+		"two headers":              header + header + roles + groups + users + assignments + memberships + footer,
+		"wrong order":              header + users + roles + groups + assignments + memberships + footer,
+		"memberships before users": header + roles + groups + memberships + users + assignments + footer,
+		"unknown record":           header + `{"type":"cenas"}` + "\n" + footer,
+		"unknown field":            header + `{"type":"role","cenas":{}}` + "\n" + footer,
+		"missing footer":           header + roles + groups + users + assignments + memberships,
+		"after the footer":         valid + roles,
+		"missing record":           header + roles + groups + lines[4] + assignments + memberships + footer,
+		"tampered":                 strings.Replace(valid, "alice hash", "eve hash", 1),
+		"unknown version":          strings.Replace(header, `"formatVersion":2`, `"formatVersion":3`, 1) + roles + groups + users + assignments + memberships + footer,
+		"invalid user":             header + roles + `{"type":"user","user":{"username":"x"}}` + "\n" + footer,
+		"invalid group":            header + roles + `{"type":"group","group":{"name":""}}` + "\n" + footer,
+		"invalid membership":       header + roles + `{"type":"groupMembership","groupMembership":{}}` + "\n" + footer,
+	}
+
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := readArchive(strings.NewReader(content))
+			assert.True(t, errors.Is(err, domain.ErrBadParamInput), err)
+		})
+	}
+}
+
+func TestReadArchive_Success(t *testing.T) {
+	archive := exportArchive(t, nil, true)
+
+	result, err := readArchive(bytes.NewReader(archive))
+	assert.Nil(t, err)
+	assert.Len(t, result.roles, 2)
+	assert.Empty(t, result.users)
+	assert.Empty(t, result.assignments)
+}
+
+func TestReadArchive_OlderVersion(t *testing.T) {
+	buffer := bytes.Buffer{}
+	writer := newArchiveWriter(&buffer)
+	assert.Nil(t, writer.write(domain.UserBackupRecord{Type: domain.UserBackupRecordHeader, Header: &domain.UserBackupHeader{FormatVersion: 1}}))
+	assert.Nil(t, writer.write(domain.UserBackupRecord{Type: domain.UserBackupRecordRole, Role: &userRole}))
+	assert.Nil(t, writer.close())
+
+	result, err := readArchive(&buffer)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.header.FormatVersion)
+	assert.Equal(t, []domain.Role{userRole}, result.roles)
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Writes the archive with every role, group, user, role assignment and group membership,
+// ending with a footer holding the checksum of the rest. The users are read in pages, as
+// the archive is written: first the current ones, then the deleted ones that can still be restored.
+func (s DefaultUserBackupService) Export(ctx context.Context, w io.Writer, withPasswords bool) error {
+	archive := newArchiveWriter(w)
+	err := archive.write(domain.UserBackupRecord{
+		Type: domain.UserBackupRecordHeader,
+		Header: &domain.UserBackupHeader{
+			FormatVersion: domain.UserBackupFormatVersion,
+			CreatedAt:     time.Now().UTC(),
+			WithPasswords: withPasswords,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	roles, err := s.fetchRoles(ctx)
+	if err != nil {
+		return err
+	}
+	slugs := make(map[uuid.UUID]string, len(roles))
+	for i := range roles {
+		slugs[roles[i].ID] = roles[i].RoleSlug
+		if err = archive.write(domain.UserBackupRecord{Type: domain.UserBackupRecordRole, Role: &roles[i]}); err != nil {
+			return err
+		}
+	}
+
+	groups, err := s.fetchGroups(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range groups {
+		if err = archive.write(domain.UserBackupRecord{Type: domain.UserBackupRecordGroup, Group: &groups[i]}); err != nil {
+			return err
+		}
+	}
+
+	assignments := make([]domain.UserBackupRoleAssignment, 0)
+	exported := map[uuid.UUID]bool{}
+	deletedAfter := time.Now().Add(-s.RetentionPeriod)
+	for _, deleted := range []bool{false, true} {
+		for offset := 0; ; offset += exportPageSize {
+			page, err := s.listUsers(ctx, deleted, deletedAfter, offset)
+			if err != nil {
+				return err
+			}
+
+			for _, user := range page {
+				record := backupUser(user, withPasswords)
+				if err = archive.write(domain.UserBackupRecord{Type: domain.UserBackupRecordUser, User: &record}); err != nil {
+					return err
+				}
+				assignments = append(assignments, domain.UserBackupRoleAssignment{
+					UserID:   user.ID,
+					RoleID:   user.RoleId,
+					RoleSlug: slugs[user.RoleId],
+				})
+				exported[user.ID] = true
+			}
+			if len(page) < exportPageSize {
+				break
+			}
+		}
+	}
+
+	for i := range assignments {
+		if err = archive.write(domain.UserBackupRecord{Type: domain.UserBackupRecordRoleAssignment, RoleAssignment: &assignments[i]}); err != nil {
+			return err
+		}
+	}
+
+	for _, group := range groups {
+		members, err := s.getMembers(ctx, group.ID)
+		if err != nil {
+			return err
+		}
+		for _, userID := range members {
+			// Members purged since the users were read are left out.
+			if !exported[userID] {
+				continue
+			}
+			membership := domain.UserBackupGroupMembership{GroupID: group.ID, UserID: userID}
+			if err = archive.write(domain.UserBackupRecord{Type: domain.UserBackupRecordGroupMembership, GroupMembership: &membership}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = archive.close(); err != nil {
+		return err
+	}
+	s.Logger.Printf("exported %d roles, %d groups and %d users\n", len(roles), len(groups), len(assignments))
+	return nil
+}
+
+// Gets every role, ordered by slug.
+func (s DefaultUserBackupService) fetchRoles(ctx context.Context) ([]domain.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	roles, err := s.RoleRepo.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].RoleSlug < roles[j].RoleSlug })
+	return roles, nil
+}
+
+// Gets every group with the IDs of its roles, ordered by name.
+func (s DefaultUserBackupService) fetchGroups(ctx context.Context) ([]domain.UserBackupGroup, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	groups, err := s.GroupRepo.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.UserBackupGroup, 0, len(groups))
+	for _, group := range groups {
+		roles, err := s.GroupRepo.GetRoles(ctx, group.ID)
+		if err != nil {
+			return nil, err
+		}
+		roleIDs := make([]uuid.UUID, 0, len(roles))
+		for _, role := range roles {
+			roleIDs = append(roleIDs, role.ID)
+		}
+		result = append(result, domain.UserBackupGroup{
+			ID:          group.ID,
+			Name:        group.Name,
+			Description: group.Description,
+			RoleIDs:     roleIDs,
+		})
+	}
+	return result, nil
+}
+
+// Gets a page of the current users, or of the users deleted after a time.
+func (s DefaultUserBackupService) listUsers(ctx context.Context, deleted bool, deletedAfter time.Time, offset int) ([]domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	if deleted {
+		return s.UserRepo.ListDeleted(ctx, deletedAfter, exportPageSize, offset)
+	}
+	return s.UserRepo.List(ctx, domain.UserFilter{Limit: exportPageSize, Offset: offset})
+}
+
+func (s DefaultUserBackupService) getMembers(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	return s.GroupRepo.GetMembers(ctx, groupID)
+}
+
+// Converts a user to its backup record.
+func backupUser(user domain.User, withPasswords bool) domain.UserBackupUser {
+	result := domain.UserBackupUser{
+		ID:            user.ID,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		Status:        user.Status,
+		StatusReason:  user.StatusReason,
+		Attributes:    user.Attributes,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+	if withPasswords {
+		result.PasswordHash = user.Password
+	}
+	if !user.DeletedAt.IsZero() {
+		deletedAt := user.DeletedAt
+		result.DeletedAt = &deletedAt
+	}
+	return result
+}
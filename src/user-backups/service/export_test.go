@@ -0,0 +1,192 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	userRole  = domain.Role{ID: uuid.New(), RoleSlug: "user", RoleLabel: "User"}
+	adminRole = domain.Role{ID: uuid.New(), RoleSlug: "admin", RoleLabel: "Administrator"}
+)
+
+// Users of the exports in the tests, the last one deleted.
+func backupUsers() []domain.User {
+	createdAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	deletedAt := time.Date(2022, 2, 3, 4, 5, 6, 0, time.UTC)
+	return []domain.User{
+		{ID: uuid.New(), Username: "alice", Email: "alice@example.com", EmailVerified: true, Password: "alice hash", RoleId: adminRole.ID, Status: domain.UserStatusActive, Attributes: domain.Attributes{"plan": "pro"}, CreatedAt: createdAt, UpdatedAt: createdAt},
+		{ID: uuid.New(), Username: "bob", Password: "bob hash", RoleId: userRole.ID, Status: domain.UserStatusSuspended, StatusReason: "fraud", Attributes: domain.Attributes{}, CreatedAt: createdAt, UpdatedAt: createdAt},
+		{ID: uuid.New(), Username: "carol", Password: "carol hash", RoleId: userRole.ID, Status: domain.UserStatusActive, Attributes: domain.Attributes{}, CreatedAt: createdAt, UpdatedAt: deletedAt, DeletedAt: deletedAt},
+	}
+}
+
+// Group of the exports in the tests, granting the admin role.
+var salesGroup = domain.Group{ID: uuid.New(), Name: "Sales", Description: "Sales team"}
+
+// Mocks the repositories to export the given users, with a page size big enough for all of them.
+// Every user is a member of the sales group, along with a user purged in the meantime.
+func exportRepos(users []domain.User) (*mocks.UserRepository, *mocks.RoleRepository, *mocks.GroupRepository) {
+	current, deleted := []domain.User{}, []domain.User{}
+	members := []uuid.UUID{}
+	for _, user := range users {
+		if user.DeletedAt.IsZero() {
+			current = append(current, user)
+		} else {
+			deleted = append(deleted, user)
+		}
+		members = append(members, user.ID)
+	}
+	members = append(members, uuid.New())
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("List", mock.Anything, domain.UserFilter{Limit: exportPageSize}).Once().Return(current, nil)
+	userRepo.On("ListDeleted", mock.Anything, mock.Anything, exportPageSize, 0).Once().Return(deleted, nil)
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("Fetch", mock.Anything).Once().Return([]domain.Role{userRole, adminRole}, nil)
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("Fetch", mock.Anything).Once().Return([]domain.Group{salesGroup}, nil)
+	groupRepo.On("GetRoles", mock.Anything, salesGroup.ID).Once().Return([]domain.Role{adminRole}, nil)
+	groupRepo.On("GetMembers", mock.Anything, salesGroup.ID).Once().Return(members, nil)
+	return userRepo, roleRepo, groupRepo
+}
+
+// Exports the given users.
+func exportArchive(t *testing.T, users []domain.User, withPasswords bool) []byte {
+	archive := bytes.Buffer{}
+	assert.Nil(t, newService(exportRepos(users)).Export(context.TODO(), &archive, withPasswords))
+	return archive.Bytes()
+}
+
+func TestExport_ErrorFetchingRoles(t *testing.T) {
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("Fetch", mock.Anything).Once().Return(nil, errors.New("boom"))
+
+	err := newService(nil, roleRepo, nil).Export(context.TODO(), &bytes.Buffer{}, false)
+	assert.EqualError(t, err, "boom")
+}
+
+func TestExport_ErrorFetchingGroups(t *testing.T) {
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("Fetch", mock.Anything).Once().Return([]domain.Role{userRole}, nil)
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("Fetch", mock.Anything).Once().Return(nil, errors.New("boom"))
+
+	err := newService(nil, roleRepo, groupRepo).Export(context.TODO(), &bytes.Buffer{}, false)
+	assert.EqualError(t, err, "boom")
+}
+
+func TestExport_ErrorListingUsers(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("List", mock.Anything, domain.UserFilter{Limit: exportPageSize}).Once().Return(nil, errors.New("boom"))
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("Fetch", mock.Anything).Once().Return([]domain.Role{userRole}, nil)
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("Fetch", mock.Anything).Once().Return([]domain.Group{}, nil)
+
+	err := newService(userRepo, roleRepo, groupRepo).Export(context.TODO(), &bytes.Buffer{}, false)
+	assert.EqualError(t, err, "boom")
+}
+
+func TestExport_ErrorListingDeletedUsers(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("List", mock.Anything, domain.UserFilter{Limit: exportPageSize}).Once().Return([]domain.User{}, nil)
+	userRepo.On("ListDeleted", mock.Anything, mock.Anything, exportPageSize, 0).Once().Return(nil, errors.New("boom"))
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("Fetch", mock.Anything).Once().Return([]domain.Role{userRole}, nil)
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("Fetch", mock.Anything).Once().Return([]domain.Group{}, nil)
+
+	err := newService(userRepo, roleRepo, groupRepo).Export(context.TODO(), &bytes.Buffer{}, false)
+	assert.EqualError(t, err, "boom")
+}
+
+func TestExport_ReadsEveryPage(t *testing.T) {
+	page := make([]domain.User, exportPageSize)
+	for i := range page {
+		page[i] = domain.User{ID: uuid.New(), Username: "user", RoleId: userRole.ID, Status: domain.UserStatusActive}
+	}
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("List", mock.Anything, domain.UserFilter{Limit: exportPageSize}).Once().Return(page, nil)
+	userRepo.On("List", mock.Anything, domain.UserFilter{Limit: exportPageSize, Offset: exportPageSize}).Once().Return(backupUsers()[:1], nil)
+	userRepo.On("ListDeleted", mock.Anything, mock.Anything, exportPageSize, 0).Once().Return([]domain.User{}, nil)
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("Fetch", mock.Anything).Once().Return([]domain.Role{userRole, adminRole}, nil)
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("Fetch", mock.Anything).Once().Return([]domain.Group{}, nil)
+
+	archive := bytes.Buffer{}
+	assert.Nil(t, newService(userRepo, roleRepo, groupRepo).Export(context.TODO(), &archive, false))
+
+	result, err := readArchive(&archive)
+	assert.Nil(t, err)
+	assert.Len(t, result.users, exportPageSize+1)
+	assert.Len(t, result.assignments, exportPageSize+1)
+	userRepo.AssertExpectations(t)
+}
+
+func TestExport_DeletedUsersWithinTheRetentionPeriod(t *testing.T) {
+	userRepo, roleRepo, groupRepo := exportRepos(backupUsers())
+	service := newService(userRepo, roleRepo, groupRepo)
+
+	before := time.Now()
+	assert.Nil(t, service.Export(context.TODO(), &bytes.Buffer{}, false))
+
+	deletedAfter := userRepo.Calls[1].Arguments.Get(1).(time.Time)
+	assert.WithinDuration(t, before.Add(-service.RetentionPeriod), deletedAfter, time.Second)
+	userRepo.AssertExpectations(t)
+}
+
+func TestExport_Success(t *testing.T) {
+	users := backupUsers()
+	archive := exportArchive(t, users, true)
+
+	lines := strings.Split(strings.TrimSuffix(string(archive), "\n"), "\n")
+	assert.Len(t, lines, 14)
+	assert.Contains(t, lines[0], `"type":"header"`)
+	assert.Contains(t, lines[1], `"roleSlug":"admin"`)
+	assert.Contains(t, lines[2], `"roleSlug":"user"`)
+	assert.Contains(t, lines[3], `"type":"group"`)
+	assert.Contains(t, lines[4], `"passwordHash":"alice hash"`)
+	assert.Contains(t, lines[6], `"deletedAt":"2022-02-03T04:05:06Z"`)
+	assert.Contains(t, lines[10], `"type":"groupMembership"`)
+	assert.Contains(t, lines[13], `"type":"footer"`)
+
+	result, err := readArchive(bytes.NewReader(archive))
+	assert.Nil(t, err)
+	assert.Equal(t, domain.UserBackupFormatVersion, result.header.FormatVersion)
+	assert.True(t, result.header.WithPasswords)
+	assert.Equal(t, []domain.Role{adminRole, userRole}, result.roles)
+	assert.Equal(t, []domain.UserBackupGroup{{ID: salesGroup.ID, Name: "Sales", Description: "Sales team", RoleIDs: []uuid.UUID{adminRole.ID}}}, result.groups)
+	assert.Equal(t, backupUser(users[0], true), result.users[0])
+	assert.Nil(t, result.users[0].DeletedAt)
+	assert.Equal(t, users[2].DeletedAt, *result.users[2].DeletedAt)
+	assert.Equal(t, domain.UserBackupRoleAssignment{UserID: users[1].ID, RoleID: userRole.ID, RoleSlug: "user"}, result.assignments[users[1].ID])
+	// The purged member is left out.
+	assert.Equal(t, []domain.UserBackupGroupMembership{
+		{GroupID: salesGroup.ID, UserID: users[0].ID},
+		{GroupID: salesGroup.ID, UserID: users[1].ID},
+		{GroupID: salesGroup.ID, UserID: users[2].ID},
+	}, result.memberships)
+}
+
+func TestExport_WithoutPasswords(t *testing.T) {
+	archive := exportArchive(t, backupUsers(), false)
+	assert.NotContains(t, string(archive), "hash")
+
+	result, err := readArchive(bytes.NewReader(archive))
+	assert.Nil(t, err)
+	assert.False(t, result.header.WithPasswords)
+	assert.Empty(t, result.users[0].PasswordHash)
+}
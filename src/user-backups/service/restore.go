@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Restores the roles, groups and users of an archive, skipping the ones already stored, so
+// restoring the same archive again changes nothing. Roles are matched by slug: the ones
+// existing with another ID fail the restore, unless remapping them to the existing IDs.
+// Groups are matched by name. The restored users get back their group memberships, and the
+// ones that were deleted are restored deleted, to be brought back within the retention period.
+// Users restored from archives without passwords have to reset them to log in.
+func (s DefaultUserBackupService) Restore(ctx context.Context, r io.Reader, remapRoles bool) (domain.UserRestoreReport, error) {
+	archive, err := readArchive(r)
+	if err != nil {
+		return domain.UserRestoreReport{}, err
+	}
+
+	report := domain.UserRestoreReport{}
+	roleIDs, err := s.restoreRoles(ctx, archive.roles, remapRoles, &report)
+	if err != nil {
+		return domain.UserRestoreReport{}, err
+	}
+	groupIDs, err := s.restoreGroups(ctx, archive.groups, roleIDs, &report)
+	if err != nil {
+		return domain.UserRestoreReport{}, err
+	}
+	for _, membership := range archive.memberships {
+		if _, ok := groupIDs[membership.GroupID]; !ok {
+			return domain.UserRestoreReport{}, fmt.Errorf("%w: user %v is a member of an unknown group", domain.ErrBadParamInput, membership.UserID)
+		}
+	}
+
+	toStore := make([]domain.User, 0, len(archive.users))
+	for _, user := range archive.users {
+		assignment, ok := archive.assignments[user.ID]
+		if !ok {
+			return domain.UserRestoreReport{}, fmt.Errorf("%w: user %v has no role assignment", domain.ErrBadParamInput, user.ID)
+		}
+		roleID, ok := roleIDs[assignment.RoleID]
+		if !ok {
+			return domain.UserRestoreReport{}, fmt.Errorf("%w: user %v has an unknown role", domain.ErrBadParamInput, user.ID)
+		}
+
+		deletedAt := time.Time{}
+		if user.DeletedAt != nil {
+			deletedAt = *user.DeletedAt
+		}
+		toStore = append(toStore, domain.User{
+			ID:            user.ID,
+			FirstName:     user.FirstName,
+			LastName:      user.LastName,
+			Username:      user.Username,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			Password:      user.PasswordHash,
+			RoleId:        roleID,
+			Status:        user.Status,
+			StatusReason:  user.StatusReason,
+			Attributes:    user.Attributes,
+			CreatedAt:     user.CreatedAt,
+			UpdatedAt:     user.UpdatedAt,
+			DeletedAt:     deletedAt,
+		})
+	}
+
+	restored := map[uuid.UUID]bool{}
+	if len(toStore) > 0 {
+		ids, err := s.UserRepo.StoreMany(ctx, toStore, restoreBatchSize)
+		if err != nil {
+			return domain.UserRestoreReport{}, err
+		}
+		report.UsersRestored = len(ids)
+		for _, id := range ids {
+			restored[id] = true
+		}
+	}
+	report.UsersSkipped = len(toStore) - report.UsersRestored
+
+	// The memberships of the skipped users are left as they are.
+	for _, membership := range archive.memberships {
+		if !restored[membership.UserID] {
+			continue
+		}
+		if err = s.addMember(ctx, groupIDs[membership.GroupID], membership.UserID); err != nil {
+			return domain.UserRestoreReport{}, err
+		}
+		report.MembershipsRestored++
+	}
+
+	s.Logger.Printf("restored %d roles, %d groups and %d users\n", report.RolesCreated, report.GroupsCreated, report.UsersRestored)
+	return report, nil
+}
+
+// Creates the roles of an archive that don't exist yet, matching the others by slug.
+// Returns the IDs the archived role IDs map to.
+func (s DefaultUserBackupService) restoreRoles(ctx context.Context, roles []domain.Role, remapRoles bool, report *domain.UserRestoreReport) (map[uuid.UUID]uuid.UUID, error) {
+	result := make(map[uuid.UUID]uuid.UUID, len(roles))
+
+	for _, role := range roles {
+		existing, err := s.roleBySlug(ctx, role.RoleSlug)
+		if err != nil {
+			return nil, err
+		}
+
+		if existing != nil {
+			if existing.ID != role.ID && !remapRoles {
+				return nil, fmt.Errorf("%w: role %q exists with another ID, restore remapping the roles", domain.ErrBadParamInput, role.RoleSlug)
+			}
+			result[role.ID] = existing.ID
+			report.RolesMatched++
+			continue
+		}
+
+		stored, err := s.storeRole(ctx, role)
+		if err != nil {
+			return nil, err
+		}
+		result[role.ID] = stored.ID
+		report.RolesCreated++
+	}
+	return result, nil
+}
+
+// Creates the groups of an archive that don't exist yet, with their roles, matching the
+// others by name. Returns the IDs the archived group IDs map to.
+func (s DefaultUserBackupService) restoreGroups(ctx context.Context, groups []domain.UserBackupGroup, roleIDs map[uuid.UUID]uuid.UUID, report *domain.UserRestoreReport) (map[uuid.UUID]uuid.UUID, error) {
+	result := make(map[uuid.UUID]uuid.UUID, len(groups))
+	if len(groups) == 0 {
+		return result, nil
+	}
+
+	existing, err := s.fetchGroupsByName(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		if id, ok := existing[groupName(group.Name)]; ok {
+			result[group.ID] = id
+			report.GroupsMatched++
+			continue
+		}
+
+		groupRoleIDs := make([]uuid.UUID, 0, len(group.RoleIDs))
+		for _, roleID := range group.RoleIDs {
+			id, ok := roleIDs[roleID]
+			if !ok {
+				return nil, fmt.Errorf("%w: group %q has an unknown role", domain.ErrBadParamInput, group.Name)
+			}
+			groupRoleIDs = append(groupRoleIDs, id)
+		}
+
+		stored, err := s.storeGroup(ctx, group, groupRoleIDs)
+		if err != nil {
+			return nil, err
+		}
+		result[group.ID] = stored.ID
+		existing[groupName(group.Name)] = stored.ID
+		report.GroupsCreated++
+	}
+	return result, nil
+}
+
+// Gets the IDs of the existing groups by their name, ignoring the case.
+func (s DefaultUserBackupService) fetchGroupsByName(ctx context.Context) (map[string]uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	groups, err := s.GroupRepo.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]uuid.UUID, len(groups))
+	for _, group := range groups {
+		result[groupName(group.Name)] = group.ID
+	}
+	return result, nil
+}
+
+// Creates a group with the ID it had, assigning it its roles.
+func (s DefaultUserBackupService) storeGroup(ctx context.Context, group domain.UserBackupGroup, roleIDs []uuid.UUID) (domain.Group, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	stored, err := s.GroupRepo.Save(ctx, domain.Group{ID: group.ID, Name: group.Name, Description: group.Description})
+	if err != nil {
+		return domain.Group{}, err
+	}
+	for _, roleID := range roleIDs {
+		if err = s.GroupRepo.AssignRole(ctx, stored.ID, roleID); err != nil {
+			return domain.Group{}, err
+		}
+	}
+	return stored, nil
+}
+
+func (s DefaultUserBackupService) addMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	return s.GroupRepo.AddMember(ctx, groupID, userID)
+}
+
+// Group names are unique regardless of their casing.
+func groupName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Gets a role by its slug, nil when it doesn't exist.
+func (s DefaultUserBackupService) roleBySlug(ctx context.Context, slug string) (*domain.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	role, err := s.RoleRepo.GetBySlug(ctx, slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (s DefaultUserBackupService) storeRole(ctx context.Context, role domain.Role) (domain.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	return s.RoleRepo.Store(ctx, role)
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRestore_InvalidArchive(t *testing.T) {
+	report, err := newService(nil, nil, nil).Restore(context.TODO(), strings.NewReader("cenas\n"), false)
+	assert.True(t, errors.Is(err, domain.ErrBadParamInput))
+	assert.Equal(t, domain.UserRestoreReport{}, report)
+}
+
+func TestRestore_RoleWithAnotherID(t *testing.T) {
+	archive := exportArchive(t, backupUsers(), true)
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "admin").Once().Return(domain.Role{ID: uuid.New(), RoleSlug: "admin"}, nil)
+
+	report, err := newService(nil, roleRepo, nil).Restore(context.TODO(), bytes.NewReader(archive), false)
+	assert.True(t, errors.Is(err, domain.ErrBadParamInput))
+	assert.Contains(t, err.Error(), `role "admin" exists with another ID`)
+	assert.Equal(t, domain.UserRestoreReport{}, report)
+	roleRepo.AssertExpectations(t)
+}
+
+func TestRestore_ErrorStoringRole(t *testing.T) {
+	archive := exportArchive(t, backupUsers(), true)
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "admin").Once().Return(domain.Role{}, sql.ErrNoRows)
+	roleRepo.On("Store", mock.Anything, adminRole).Once().Return(domain.Role{}, errors.New("boom"))
+
+	_, err := newService(nil, roleRepo, nil).Restore(context.TODO(), bytes.NewReader(archive), false)
+	assert.EqualError(t, err, "boom")
+	roleRepo.AssertExpectations(t)
+}
+
+func TestRestore_ErrorStoringUsers(t *testing.T) {
+	archive := exportArchive(t, backupUsers(), true)
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "admin").Once().Return(adminRole, nil)
+	roleRepo.On("GetBySlug", mock.Anything, "user").Once().Return(userRole, nil)
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("Fetch", mock.Anything).Once().Return([]domain.Group{salesGroup}, nil)
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("StoreMany", mock.Anything, mock.Anything, restoreBatchSize).Once().Return(nil, errors.New("boom"))
+
+	_, err := newService(userRepo, roleRepo, groupRepo).Restore(context.TODO(), bytes.NewReader(archive), false)
+	assert.EqualError(t, err, "boom")
+	userRepo.AssertExpectations(t)
+}
+
+func TestRestore_ErrorStoringGroup(t *testing.T) {
+	archive := exportArchive(t, backupUsers(), true)
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "admin").Once().Return(adminRole, nil)
+	roleRepo.On("GetBySlug", mock.Anything, "user").Once().Return(userRole, nil)
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("Fetch", mock.Anything).Once().Return([]domain.Group{}, nil)
+	groupRepo.On("Save", mock.Anything, mock.Anything).Once().Return(domain.Group{}, errors.New("boom"))
+
+	_, err := newService(nil, roleRepo, groupRepo).Restore(context.TODO(), bytes.NewReader(archive), false)
+	assert.EqualError(t, err, "boom")
+	groupRepo.AssertExpectations(t)
+}
+
+func TestRestore_Success(t *testing.T) {
+	users := backupUsers()
+	archive := exportArchive(t, users, true)
+
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "admin").Once().Return(domain.Role{}, sql.ErrNoRows)
+	roleRepo.On("Store", mock.Anything, adminRole).Once().Return(adminRole, nil)
+	roleRepo.On("GetBySlug", mock.Anything, "user").Once().Return(userRole, nil)
+
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("Fetch", mock.Anything).Once().Return([]domain.Group{}, nil)
+	groupRepo.On("Save", mock.Anything, domain.Group{ID: salesGroup.ID, Name: "Sales", Description: "Sales team"}).Once().Return(salesGroup, nil)
+	groupRepo.On("AssignRole", mock.Anything, salesGroup.ID, adminRole.ID).Once().Return(nil)
+	groupRepo.On("AddMember", mock.Anything, salesGroup.ID, users[1].ID).Once().Return(nil)
+	groupRepo.On("AddMember", mock.Anything, salesGroup.ID, users[2].ID).Once().Return(nil)
+
+	var stored []domain.User
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("StoreMany", mock.Anything, mock.Anything, restoreBatchSize).Once().
+		Run(func(args mock.Arguments) { stored = args.Get(1).([]domain.User) }).
+		Return([]uuid.UUID{users[1].ID, users[2].ID}, nil)
+
+	report, err := newService(userRepo, roleRepo, groupRepo).Restore(context.TODO(), bytes.NewReader(archive), false)
+	assert.Nil(t, err)
+	// Alice existed already, and keeps the groups she has.
+	assert.Equal(t, domain.UserRestoreReport{RolesCreated: 1, RolesMatched: 1, GroupsCreated: 1, UsersRestored: 2, UsersSkipped: 1, MembershipsRestored: 2}, report)
+	assert.Equal(t, users, stored)
+	roleRepo.AssertExpectations(t)
+	groupRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func TestRestore_RemapRoles(t *testing.T) {
+	users := backupUsers()
+	archive := exportArchive(t, users, false)
+
+	targetAdmin := domain.Role{ID: uuid.New(), RoleSlug: "admin"}
+	targetUser := domain.Role{ID: uuid.New(), RoleSlug: "user"}
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "admin").Once().Return(targetAdmin, nil)
+	roleRepo.On("GetBySlug", mock.Anything, "user").Once().Return(targetUser, nil)
+
+	// The group exists with another ID, so its members are added to it.
+	targetGroup := domain.Group{ID: uuid.New(), Name: "SALES"}
+	groupRepo := new(mocks.GroupRepository)
+	groupRepo.On("Fetch", mock.Anything).Once().Return([]domain.Group{targetGroup}, nil)
+	for _, user := range users {
+		groupRepo.On("AddMember", mock.Anything, targetGroup.ID, user.ID).Once().Return(nil)
+	}
+
+	var stored []domain.User
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("StoreMany", mock.Anything, mock.Anything, restoreBatchSize).Once().
+		Run(func(args mock.Arguments) { stored = args.Get(1).([]domain.User) }).
+		Return([]uuid.UUID{users[0].ID, users[1].ID, users[2].ID}, nil)
+
+	report, err := newService(userRepo, roleRepo, groupRepo).Restore(context.TODO(), bytes.NewReader(archive), true)
+	assert.Nil(t, err)
+	assert.Equal(t, domain.UserRestoreReport{RolesMatched: 2, GroupsMatched: 1, UsersRestored: 3, MembershipsRestored: 3}, report)
+	assert.Equal(t, targetAdmin.ID, stored[0].RoleId)
+	assert.Equal(t, targetUser.ID, stored[1].RoleId)
+	assert.Empty(t, stored[0].Password)
+	roleRepo.AssertExpectations(t)
+	groupRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
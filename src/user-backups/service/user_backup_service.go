@@ -0,0 +1,57 @@
+package service
+
+import (
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+const (
+	// Users read by each query of an export.
+	exportPageSize = 500
+	// Users inserted by each statement of a restore.
+	restoreBatchSize = 500
+)
+
+type DefaultUserBackupService struct {
+	Logger         *log.Logger
+	UserRepo       domain.UserRepository
+	RoleRepo       domain.RoleRepository
+	GroupRepo      domain.GroupRepository
+	ContextTimeout time.Duration
+	// How long deleted users can be restored, the ones deleted since are exported.
+	RetentionPeriod time.Duration
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	userRepo domain.UserRepository,
+	roleRepo domain.RoleRepository,
+	groupRepo domain.GroupRepository,
+	contextTimeout time.Duration,
+	retentionPeriod time.Duration,
+) domain.UserBackupService {
+	return DefaultUserBackupService{
+		logger,
+		userRepo,
+		roleRepo,
+		groupRepo,
+		contextTimeout,
+		retentionPeriod,
+	}
+}
+
+// Instantiation for tests
+func newService(userRepo domain.UserRepository, roleRepo domain.RoleRepository, groupRepo domain.GroupRepository) DefaultUserBackupService {
+	return DefaultUserBackupService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		userRepo,
+		roleRepo,
+		groupRepo,
+		time.Duration(5 * time.Second),
+		30 * 24 * time.Hour,
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Soft deletes a user at a version, ending its session.
+// It can be restored until the retention period is over.
+func (s DefaultUserDeletionService) Delete(ctx context.Context, id uuid.UUID, version int) error {
+	if id == uuid.Nil || version <= 0 {
+		return domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	return s.UserRepo.Delete(ctx, id, version, time.Now())
+}
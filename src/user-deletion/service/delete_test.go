@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDelete_InvalidID(t *testing.T) {
+	err := newService(nil, nil).Delete(context.TODO(), uuid.Nil, 1)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestDelete_InvalidVersion(t *testing.T) {
+	err := newService(nil, nil).Delete(context.TODO(), uuid.New(), 0)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestDelete_VersionConflict(t *testing.T) {
+	userID := uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("Delete", mock.Anything, userID, 1, mock.AnythingOfType("time.Time")).
+		Once().Return(domain.ErrVersionConflict)
+
+	err := newService(userRepo, nil).Delete(context.TODO(), userID, 1)
+	assert.Equal(t, domain.ErrVersionConflict, err)
+	userRepo.AssertExpectations(t)
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	userID := uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("Delete", mock.Anything, userID, 1, mock.AnythingOfType("time.Time")).
+		Once().Return(domain.ErrNotFound)
+
+	err := newService(userRepo, nil).Delete(context.TODO(), userID, 1)
+	assert.Equal(t, domain.ErrNotFound, err)
+	userRepo.AssertExpectations(t)
+}
+
+func TestDelete_Success(t *testing.T) {
+	userID := uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("Delete", mock.Anything, userID, 1, mock.AnythingOfType("time.Time")).
+		Once().Return(nil)
+
+	err := newService(userRepo, nil).Delete(context.TODO(), userID, 1)
+	assert.Nil(t, err)
+	userRepo.AssertExpectations(t)
+}
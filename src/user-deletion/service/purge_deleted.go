@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// Permanently removes the users deleted longer than the retention period ago,
+// in batches, freeing their usernames. Returns how many were purged.
+func (s DefaultUserDeletionService) PurgeDeleted(ctx context.Context) (int64, error) {
+	deletedBefore := time.Now().Add(-s.Settings.RetentionPeriod)
+
+	var total int64
+	for {
+		purged, err := s.purgeBatch(ctx, deletedBefore)
+		total += purged
+		if err != nil {
+			return total, err
+		}
+		if purged == 0 || purged < int64(s.Settings.PurgeBatchSize) {
+			return total, nil
+		}
+	}
+}
+
+// Purges a single batch, each with its own timeout.
+func (s DefaultUserDeletionService) purgeBatch(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	return s.UserRepo.PurgeDeleted(ctx, deletedBefore, s.Settings.PurgeBatchSize)
+}
+
+// Purges the deleted users on every interval, until the context is done.
+// Safe to run on every replica, since concurrent purges skip each other's rows.
+func (s DefaultUserDeletionService) RunPurger(ctx context.Context) {
+	ticker := time.NewTicker(s.Settings.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeDeleted(ctx)
+		if err != nil {
+			s.Logger.Printf("error purging deleted users: %v\n", err)
+		} else if purged > 0 {
+			s.Logger.Printf("purged %d deleted users\n", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
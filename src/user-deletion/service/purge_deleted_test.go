@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPurgeDeleted_Error(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("PurgeDeleted", mock.Anything, mock.MatchedBy(withinRetention), 100).
+		Once().Return(int64(0), errors.New("boom"))
+
+	purged, err := newService(userRepo, nil).PurgeDeleted(context.TODO())
+	assert.Equal(t, int64(0), purged)
+	assert.Equal(t, "boom", err.Error())
+	userRepo.AssertExpectations(t)
+}
+
+func TestPurgeDeleted_PurgesInBatches(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("PurgeDeleted", mock.Anything, mock.MatchedBy(withinRetention), 100).
+		Twice().Return(int64(100), nil)
+	userRepo.On("PurgeDeleted", mock.Anything, mock.MatchedBy(withinRetention), 100).
+		Once().Return(int64(42), nil)
+
+	purged, err := newService(userRepo, nil).PurgeDeleted(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(242), purged)
+	userRepo.AssertExpectations(t)
+}
+
+func TestRunPurger_StopsWithContext(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("PurgeDeleted", mock.Anything, mock.Anything, 100).Return(int64(0), nil)
+
+	service := newService(userRepo, nil)
+	service.Settings.PurgeInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 35*time.Millisecond)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		service.RunPurger(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the purger didn't stop with its context")
+	}
+	assert.GreaterOrEqual(t, len(userRepo.Calls), 2)
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Restores a deleted user, as long as it is within the retention period.
+func (s DefaultUserDeletionService) Restore(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	if id == uuid.Nil {
+		return nil, domain.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	err := s.UserRepo.Restore(ctx, id, time.Now().Add(-s.Settings.RetentionPeriod))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.UserService.GetUserByUUID(ctx, id)
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Matches the start of the retention window of the test service.
+func withinRetention(deletedAfter time.Time) bool {
+	expected := time.Now().Add(-30 * 24 * time.Hour)
+	return deletedAfter.Sub(expected).Abs() < time.Minute
+}
+
+func TestRestore_InvalidID(t *testing.T) {
+	user, err := newService(nil, nil).Restore(context.TODO(), uuid.Nil)
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func TestRestore_OutsideRetentionPeriod(t *testing.T) {
+	userID := uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("Restore", mock.Anything, userID, mock.MatchedBy(withinRetention)).
+		Once().Return(domain.ErrNotFound)
+
+	user, err := newService(userRepo, nil).Restore(context.TODO(), userID)
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrNotFound, err)
+	userRepo.AssertExpectations(t)
+}
+
+func TestRestore_Success(t *testing.T) {
+	userID := uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("Restore", mock.Anything, userID, mock.MatchedBy(withinRetention)).
+		Once().Return(nil)
+
+	restored := &domain.User{ID: userID, Username: "alice"}
+	userService := new(mocks.UserService)
+	userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(restored, nil)
+
+	user, err := newService(userRepo, userService).Restore(context.TODO(), userID)
+	assert.Nil(t, err)
+	assert.Equal(t, restored, user)
+	userRepo.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
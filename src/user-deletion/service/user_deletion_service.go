@@ -0,0 +1,49 @@
+package service
+
+import (
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type DefaultUserDeletionService struct {
+	Logger         *log.Logger
+	UserRepo       domain.UserRepository
+	UserService    domain.UserService
+	ContextTimeout time.Duration
+	Settings       domain.UserDeletionSettings
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	userRepo domain.UserRepository,
+	userService domain.UserService,
+	contextTimeout time.Duration,
+	settings domain.UserDeletionSettings,
+) domain.UserDeletionService {
+	return DefaultUserDeletionService{
+		logger,
+		userRepo,
+		userService,
+		contextTimeout,
+		settings,
+	}
+}
+
+// Instantiation for tests
+func newService(userRepo domain.UserRepository, userService domain.UserService) DefaultUserDeletionService {
+	return DefaultUserDeletionService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		userRepo,
+		userService,
+		time.Duration(5 * time.Second),
+		domain.UserDeletionSettings{
+			RetentionPeriod: 30 * 24 * time.Hour,
+			PurgeInterval:   time.Hour,
+			PurgeBatchSize:  100,
+		},
+	}
+}
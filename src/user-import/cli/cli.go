@@ -0,0 +1,79 @@
+// Package cli imports users from the command line, with the same rules as the ImportUsers RPC.
+//
+// Usage: main import-users [-format csv|jsonl] [-dry-run] <file>
+//
+// The file is read from the standard input when it is "-", and the format is guessed from
+// its extension when not given. The report is written as indented JSON.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Name of the command, as the first argument of the binary.
+const Command = "import-users"
+
+// Runs the import with the arguments after the command name.
+// Fails when the import couldn't run or some of its rows failed, after writing the report.
+func Run(ctx context.Context, service domain.UserImportService, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet(Command, flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	format := flags.String("format", "", "format of the file, csv or jsonl (guessed from the extension by default)")
+	dryRun := flags.Bool("dry-run", false, "only validate the users, without storing them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: " + Command + " [-format csv|jsonl] [-dry-run] <file>")
+	}
+
+	path := flags.Arg(0)
+	importFormat := domain.UserImportFormat(*format)
+	if len(importFormat) == 0 {
+		importFormat = guessFormat(path)
+	}
+
+	file := stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		file = f
+	}
+
+	report, err := service.Import(ctx, file, importFormat, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(report); err != nil {
+		return err
+	}
+
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", report.Failed, len(report.Rows))
+	}
+	return nil
+}
+
+// Guesses the format of a file from its extension, JSON lines unless it is a CSV file.
+func guessFormat(path string) domain.UserImportFormat {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return domain.UserImportFormatCSV
+	}
+	return domain.UserImportFormatJSONLines
+}
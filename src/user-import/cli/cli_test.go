@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRun_Usage(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := Run(context.TODO(), nil, []string{"-dry-run"}, nil, out)
+	assert.EqualError(t, err, "usage: import-users [-format csv|jsonl] [-dry-run] <file>")
+
+	err = Run(context.TODO(), nil, []string{"-unknown", "users.csv"}, nil, out)
+	assert.Error(t, err)
+}
+
+func TestRun_FileNotFound(t *testing.T) {
+	err := Run(context.TODO(), nil, []string{filepath.Join(t.TempDir(), "missing.csv")}, nil, &bytes.Buffer{})
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestRun_ImportError(t *testing.T) {
+	service := new(mocks.UserImportService)
+	service.On("Import", mock.Anything, mock.Anything, domain.UserImportFormatJSONLines, false).Once().
+		Return(domain.UserImportReport{}, errors.New("boom"))
+
+	out := &bytes.Buffer{}
+	err := Run(context.TODO(), service, []string{"-"}, strings.NewReader(""), out)
+	assert.EqualError(t, err, "boom")
+	assert.Empty(t, out.String())
+	service.AssertExpectations(t)
+}
+
+func TestRun_GuessesTheFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.CSV")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("username,password\nalice,secret123\n"), 0600))
+
+	var file string
+	service := new(mocks.UserImportService)
+	service.On("Import", mock.Anything, mock.Anything, domain.UserImportFormatCSV, true).Once().
+		Run(func(args mock.Arguments) {
+			content, _ := ioutil.ReadAll(args.Get(1).(io.Reader))
+			file = string(content)
+		}).
+		Return(domain.UserImportReport{DryRun: true, Created: 1, Rows: []domain.UserImportRow{{Line: 2, Username: "alice", Status: domain.UserImportRowCreated}}}, nil)
+
+	out := &bytes.Buffer{}
+	err := Run(context.TODO(), service, []string{"-dry-run", path}, nil, out)
+	assert.Nil(t, err)
+	assert.Equal(t, "username,password\nalice,secret123\n", file)
+	assert.Contains(t, out.String(), `"created": 1`)
+	service.AssertExpectations(t)
+}
+
+func TestRun_FailedRows(t *testing.T) {
+	service := new(mocks.UserImportService)
+	service.On("Import", mock.Anything, mock.Anything, domain.UserImportFormatCSV, false).Once().
+		Return(domain.UserImportReport{Failed: 1, Rows: []domain.UserImportRow{
+			{Line: 2, Username: "alice", Status: domain.UserImportRowFailed, Reason: "invalid email"},
+			{Line: 3, Username: "bob", Status: domain.UserImportRowSkipped, Reason: "duplicated username in the import"},
+		}}, nil)
+
+	out := &bytes.Buffer{}
+	err := Run(context.TODO(), service, []string{"-format", "csv", "-"}, strings.NewReader(""), out)
+	assert.EqualError(t, err, "1 of 2 rows failed")
+	assert.Contains(t, out.String(), `"reason": "invalid email"`)
+	service.AssertExpectations(t)
+}
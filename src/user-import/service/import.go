@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/helpers"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Imports the users of a CSV or JSON lines file, reporting the outcome of every row.
+// Every row is validated first, and the valid ones are then stored in batches inside a
+// single transaction. On dry runs the rows are only validated.
+func (s DefaultUserImportService) Import(ctx context.Context, r io.Reader, format domain.UserImportFormat, dryRun bool) (domain.UserImportReport, error) {
+	rows, err := readRows(r, format)
+	if err != nil {
+		return domain.UserImportReport{}, err
+	}
+
+	report := domain.UserImportReport{DryRun: dryRun, Rows: make([]domain.UserImportRow, len(rows))}
+	roles := map[string]*domain.Role{}
+	usernames := map[string]bool{}
+	emails := map[string]bool{}
+	toStore := make([]domain.User, 0, len(rows))
+
+	for i, row := range rows {
+		result := &report.Rows[i]
+		result.Line = row.line
+		result.Username = strings.TrimSpace(row.record.Username)
+
+		if row.err != nil {
+			result.Status, result.Reason = domain.UserImportRowFailed, row.err.Error()
+			continue
+		}
+		if reason := s.validateRecord(row.record); len(reason) > 0 {
+			result.Status, result.Reason = domain.UserImportRowFailed, reason
+			continue
+		}
+
+		normalized := helpers.NormalizeUsername(result.Username)
+		email := strings.ToLower(strings.TrimSpace(row.record.Email))
+		if usernames[normalized] {
+			result.Status, result.Reason = domain.UserImportRowSkipped, "duplicated username in the import"
+			continue
+		}
+		if len(email) > 0 && emails[email] {
+			result.Status, result.Reason = domain.UserImportRowSkipped, "duplicated email in the import"
+			continue
+		}
+		usernames[normalized] = true
+		if len(email) > 0 {
+			emails[email] = true
+		}
+
+		// The same checks as when adding a single user.
+		reason, err := s.conflict(ctx, result.Username, email)
+		if err != nil {
+			return domain.UserImportReport{}, err
+		}
+		if len(reason) > 0 {
+			result.Status, result.Reason = domain.UserImportRowFailed, reason
+			continue
+		}
+
+		role, err := s.role(ctx, row.record.Role, roles)
+		if err != nil {
+			return domain.UserImportReport{}, err
+		}
+		if role == nil {
+			result.Status, result.Reason = domain.UserImportRowFailed, fmt.Sprintf("role %q doesn't exist", row.record.Role)
+			continue
+		}
+
+		result.Status = domain.UserImportRowCreated
+		if dryRun {
+			continue
+		}
+
+		password, err := s.passwordHash(row.record)
+		if err != nil {
+			return domain.UserImportReport{}, err
+		}
+		result.UserID = uuid.New()
+		toStore = append(toStore, domain.User{
+			ID:       result.UserID,
+			Username: result.Username,
+			Email:    strings.TrimSpace(row.record.Email),
+			Password: password,
+			RoleId:   role.ID,
+		})
+	}
+
+	if len(toStore) > 0 {
+		if err = s.store(ctx, toStore, report.Rows); err != nil {
+			return domain.UserImportReport{}, err
+		}
+	}
+
+	for _, row := range report.Rows {
+		switch row.Status {
+		case domain.UserImportRowCreated:
+			report.Created++
+		case domain.UserImportRowSkipped:
+			report.Skipped++
+		case domain.UserImportRowFailed:
+			report.Failed++
+		}
+	}
+	return report, nil
+}
+
+// Gets the reason a record is invalid, if it is.
+func (s DefaultUserImportService) validateRecord(record domain.UserImportRecord) string {
+	if len(helpers.NormalizeUsername(strings.TrimSpace(record.Username))) == 0 {
+		return "invalid username"
+	}
+	email := strings.TrimSpace(record.Email)
+	if len(email) > 0 && !helpers.IsValidEmail(email) {
+		return "invalid email"
+	}
+	if len(record.PasswordHash) > 0 {
+		if len(record.Password) > 0 {
+			return "password and password hash can't both be set"
+		}
+		return s.validatePasswordHash(record.PasswordHash)
+	}
+	if len(record.Password) < s.Settings.MinPasswordLength {
+		return fmt.Sprintf("password must have at least %d characters", s.Settings.MinPasswordLength)
+	}
+	if len(record.Password) > maxPasswordLength {
+		return fmt.Sprintf("password must have at most %d bytes", maxPasswordLength)
+	}
+	return ""
+}
+
+// Gets the reason an imported password hash can't be stored, if it can't.
+// Besides the legacy schemes, bcrypt hashes are stored as they are.
+func (s DefaultUserImportService) validatePasswordHash(hash string) string {
+	if _, err := bcrypt.Cost([]byte(hash)); err == nil {
+		return ""
+	}
+	scheme := helpers.LegacyPasswordScheme(hash)
+	if len(scheme) == 0 {
+		return "unknown password hash scheme"
+	}
+	if scheme == helpers.LegacyPasswordSchemeSaltedSHA1 && !s.Settings.AllowSHA1Hashes {
+		return "salted SHA-1 password hashes aren't allowed"
+	}
+	if err := helpers.ValidateLegacyPasswordHash(hash); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// Gets the hash to store of a valid record, hashing its password
+// unless it comes hashed already.
+func (s DefaultUserImportService) passwordHash(record domain.UserImportRecord) (string, error) {
+	if len(record.PasswordHash) > 0 {
+		return record.PasswordHash, nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(record.Password), s.BcryptHashingCost)
+	return string(hash), err
+}
+
+// Gets the reason a username and an email can't be stored, if they can't: they belong
+// to an existing user, or the username is reserved for the user that gave it up.
+func (s DefaultUserImportService) conflict(ctx context.Context, username string, email string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	usernameTaken, emailTaken, err := s.UserRepo.FindConflicts(ctx, username, email)
+	if err != nil {
+		return "", err
+	}
+	if usernameTaken {
+		return "username already exists", nil
+	}
+	if emailTaken {
+		return "email already exists", nil
+	}
+
+	reserved, err := s.UsernameHistoryRepo.IsReserved(ctx, username, uuid.Nil, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if reserved {
+		return "username is reserved", nil
+	}
+	return "", nil
+}
+
+// Gets a role by its slug, the default user role when empty, caching the lookups.
+// Returns nil when the role doesn't exist.
+func (s DefaultUserImportService) role(ctx context.Context, slug string, cache map[string]*domain.Role) (*domain.Role, error) {
+	slug = strings.TrimSpace(slug)
+	if len(slug) == 0 {
+		slug = domain.DEFAULT_ROLE_USER.RoleSlug
+	}
+	if role, ok := cache[slug]; ok {
+		return role, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	role, err := s.RoleRepo.GetBySlug(ctx, slug)
+	if err == sql.ErrNoRows {
+		cache[slug] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cache[slug] = &role
+	return &role, nil
+}
+
+// Stores the validated users, failing the ones that conflicted with
+// users created in the meantime with the reason they did.
+func (s DefaultUserImportService) store(ctx context.Context, users []domain.User, rows []domain.UserImportRow) error {
+	ids, err := s.UserRepo.StoreMany(ctx, users, s.Settings.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	stored := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		stored[id] = true
+	}
+	emails := make(map[uuid.UUID]string, len(users))
+	for _, user := range users {
+		emails[user.ID] = user.Email
+	}
+
+	for i := range rows {
+		if rows[i].Status != domain.UserImportRowCreated || stored[rows[i].UserID] {
+			continue
+		}
+		reason, err := s.conflict(ctx, rows[i].Username, emails[rows[i].UserID])
+		if err != nil {
+			return err
+		}
+		if len(reason) == 0 {
+			reason = "username or email already exists"
+		}
+		rows[i].Status, rows[i].Reason = domain.UserImportRowFailed, reason
+		rows[i].UserID = uuid.Nil
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	userRole  = domain.Role{ID: uuid.New(), RoleSlug: "user"}
+	adminRole = domain.Role{ID: uuid.New(), RoleSlug: "admin"}
+)
+
+const importFile = `username,email,password,role
+alice,alice@example.com,secret123,
+bob,,secret456,admin
+Alice,,secret789,
+dave,alice@example.com,secret000,
+erin,,short,
+frank,,secret111,editor
+existing,,secret222,
+grace,Taken@example.com,secret333,
+heidi,,secret444,
+`
+
+// Usernames and emails of the users that exist already.
+func existingUsers() map[string]bool {
+	return map[string]bool{"existing": true, "taken@example.com": true}
+}
+
+func newImportService(existing map[string]bool) (DefaultUserImportService, *mocks.UserRepository, *mocks.RoleRepository) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("FindConflicts", mock.Anything, mock.Anything, mock.Anything).Maybe().Return(
+		func(ctx context.Context, username string, email string) bool {
+			return existing[strings.ToLower(username)]
+		},
+		func(ctx context.Context, username string, email string) bool { return existing[strings.ToLower(email)] },
+		nil,
+	)
+
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "user").Maybe().Return(userRole, nil)
+	roleRepo.On("GetBySlug", mock.Anything, "admin").Maybe().Return(adminRole, nil)
+	roleRepo.On("GetBySlug", mock.Anything, "editor").Maybe().Return(domain.Role{}, sql.ErrNoRows)
+
+	usernameHistoryRepo := new(mocks.UsernameHistoryRepository)
+	usernameHistoryRepo.On("IsReserved", mock.Anything, "heidi", uuid.Nil, mock.Anything).Maybe().Return(true, nil)
+	usernameHistoryRepo.On("IsReserved", mock.Anything, mock.Anything, uuid.Nil, mock.Anything).Maybe().Return(false, nil)
+
+	return newService(userRepo, roleRepo, usernameHistoryRepo), userRepo, roleRepo
+}
+
+func TestImport_InvalidFile(t *testing.T) {
+	service, _, _ := newImportService(existingUsers())
+
+	report, err := service.Import(context.TODO(), strings.NewReader("name\n"), domain.UserImportFormatCSV, false)
+	assert.True(t, errors.Is(err, domain.ErrBadParamInput))
+	assert.Equal(t, domain.UserImportReport{}, report)
+}
+
+func TestImport_ErrorCheckingConflicts(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("FindConflicts", mock.Anything, "alice", "").Once().Return(false, false, errors.New("boom"))
+
+	report, err := newService(userRepo, nil, nil).Import(context.TODO(), strings.NewReader("username,password\nalice,secret123\n"), domain.UserImportFormatCSV, false)
+	assert.Equal(t, "boom", err.Error())
+	assert.Equal(t, domain.UserImportReport{}, report)
+	userRepo.AssertExpectations(t)
+}
+
+func TestImport_ErrorCheckingReservedUsernames(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("FindConflicts", mock.Anything, "alice", "").Once().Return(false, false, nil)
+	usernameHistoryRepo := new(mocks.UsernameHistoryRepository)
+	usernameHistoryRepo.On("IsReserved", mock.Anything, "alice", uuid.Nil, mock.Anything).Once().Return(false, errors.New("boom"))
+
+	report, err := newService(userRepo, nil, usernameHistoryRepo).Import(context.TODO(), strings.NewReader("username,password\nalice,secret123\n"), domain.UserImportFormatCSV, false)
+	assert.Equal(t, "boom", err.Error())
+	assert.Equal(t, domain.UserImportReport{}, report)
+	usernameHistoryRepo.AssertExpectations(t)
+}
+
+func TestImport_ErrorGettingRole(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("FindConflicts", mock.Anything, "alice", "").Once().Return(false, false, nil)
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "user").Once().Return(domain.Role{}, errors.New("boom"))
+	usernameHistoryRepo := new(mocks.UsernameHistoryRepository)
+	usernameHistoryRepo.On("IsReserved", mock.Anything, "alice", uuid.Nil, mock.Anything).Once().Return(false, nil)
+
+	report, err := newService(userRepo, roleRepo, usernameHistoryRepo).Import(context.TODO(), strings.NewReader("username,password\nalice,secret123\n"), domain.UserImportFormatCSV, false)
+	assert.Equal(t, "boom", err.Error())
+	assert.Equal(t, domain.UserImportReport{}, report)
+}
+
+func TestImport_DryRun(t *testing.T) {
+	service, userRepo, _ := newImportService(existingUsers())
+
+	report, err := service.Import(context.TODO(), strings.NewReader(importFile), domain.UserImportFormatCSV, true)
+	assert.Nil(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 2, report.Skipped)
+	assert.Equal(t, 5, report.Failed)
+	assert.Equal(t, []domain.UserImportRow{
+		{Line: 2, Username: "alice", Status: domain.UserImportRowCreated},
+		{Line: 3, Username: "bob", Status: domain.UserImportRowCreated},
+		{Line: 4, Username: "Alice", Status: domain.UserImportRowSkipped, Reason: "duplicated username in the import"},
+		{Line: 5, Username: "dave", Status: domain.UserImportRowSkipped, Reason: "duplicated email in the import"},
+		{Line: 6, Username: "erin", Status: domain.UserImportRowFailed, Reason: "password must have at least 8 characters"},
+		{Line: 7, Username: "frank", Status: domain.UserImportRowFailed, Reason: `role "editor" doesn't exist`},
+		{Line: 8, Username: "existing", Status: domain.UserImportRowFailed, Reason: "username already exists"},
+		{Line: 9, Username: "grace", Status: domain.UserImportRowFailed, Reason: "email already exists"},
+		{Line: 10, Username: "heidi", Status: domain.UserImportRowFailed, Reason: "username is reserved"},
+	}, report.Rows)
+	userRepo.AssertNotCalled(t, "StoreMany", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestImport_ErrorStoring(t *testing.T) {
+	service, userRepo, _ := newImportService(existingUsers())
+	userRepo.On("StoreMany", mock.Anything, mock.Anything, 2).Once().Return(nil, errors.New("boom"))
+
+	report, err := service.Import(context.TODO(), strings.NewReader(importFile), domain.UserImportFormatCSV, false)
+	assert.Equal(t, "boom", err.Error())
+	assert.Equal(t, domain.UserImportReport{}, report)
+}
+
+func TestImport_Success(t *testing.T) {
+	existing := existingUsers()
+	service, userRepo, _ := newImportService(existing)
+
+	var stored []domain.User
+	userRepo.On("StoreMany", mock.Anything, mock.Anything, 2).Once().
+		Run(func(args mock.Arguments) {
+			stored = args.Get(1).([]domain.User)
+			// Bob was created by someone else while importing.
+			existing["bob"] = true
+		}).
+		Return(func(ctx context.Context, users []domain.User, batchSize int) []uuid.UUID {
+			return []uuid.UUID{users[0].ID}
+		}, nil)
+
+	report, err := service.Import(context.TODO(), strings.NewReader(importFile), domain.UserImportFormatCSV, false)
+	assert.Nil(t, err)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 2, report.Skipped)
+	assert.Equal(t, 6, report.Failed)
+
+	assert.Len(t, stored, 2)
+	assert.Equal(t, "alice", stored[0].Username)
+	assert.Equal(t, "alice@example.com", stored[0].Email)
+	assert.Equal(t, userRole.ID, stored[0].RoleId)
+	assert.Nil(t, bcrypt.CompareHashAndPassword([]byte(stored[0].Password), []byte("secret123")))
+	assert.Equal(t, adminRole.ID, stored[1].RoleId)
+
+	assert.Equal(t, domain.UserImportRow{Line: 2, Username: "alice", Status: domain.UserImportRowCreated, UserID: stored[0].ID}, report.Rows[0])
+	assert.Equal(t, domain.UserImportRow{Line: 3, Username: "bob", Status: domain.UserImportRowFailed, Reason: "username already exists"}, report.Rows[1])
+	userRepo.AssertExpectations(t)
+}
+
+const importHashesFile = `{"username":"django","passwordHash":"pbkdf2_sha256$1000$saltsalt$qQDPSZa3Ormyy9oK1Pu0ZLLwP2Svzmx3yu8OvDdq/J0="}
+{"username":"rails","passwordHash":"$2a$04$0VTz6ISSoACIOt1wDBT/R.S8K/WYOh0DR.mX2RqMk5nClSwxEmO3O"}
+{"username":"ancient","passwordHash":"sha1$saltsalt$599c8acd3f623920c9f30c903535e864275a2e35"}
+{"username":"unknown","passwordHash":"md5$saltsalt$d41d8cd98f00b204e9800998ecf8427e"}
+{"username":"broken","passwordHash":"scrypt$15$saltsalt$8$1$5eJHz+r0WlGIVtbszqsCUg=="}
+{"username":"both","password":"secret123","passwordHash":"sha1$saltsalt$599c8acd3f623920c9f30c903535e864275a2e35"}
+`
+
+func TestImport_PasswordHashes(t *testing.T) {
+	service, userRepo, _ := newImportService(existingUsers())
+
+	var stored []domain.User
+	userRepo.On("StoreMany", mock.Anything, mock.Anything, 2).Once().
+		Run(func(args mock.Arguments) { stored = args.Get(1).([]domain.User) }).
+		Return(func(ctx context.Context, users []domain.User, batchSize int) []uuid.UUID {
+			return []uuid.UUID{users[0].ID, users[1].ID}
+		}, nil)
+
+	report, err := service.Import(context.TODO(), strings.NewReader(importHashesFile), domain.UserImportFormatJSONLines, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 4, report.Failed)
+	assert.Equal(t, "salted SHA-1 password hashes aren't allowed", report.Rows[2].Reason)
+	assert.Equal(t, "unknown password hash scheme", report.Rows[3].Reason)
+	assert.Equal(t, "invalid legacy password hash", report.Rows[4].Reason)
+	assert.Equal(t, "password and password hash can't both be set", report.Rows[5].Reason)
+
+	assert.Len(t, stored, 2)
+	assert.Equal(t, "pbkdf2_sha256$1000$saltsalt$qQDPSZa3Ormyy9oK1Pu0ZLLwP2Svzmx3yu8OvDdq/J0=", stored[0].Password)
+	assert.Equal(t, "$2a$04$0VTz6ISSoACIOt1wDBT/R.S8K/WYOh0DR.mX2RqMk5nClSwxEmO3O", stored[1].Password)
+	userRepo.AssertExpectations(t)
+}
+
+func TestImport_SHA1HashesWhenAllowed(t *testing.T) {
+	service, _, _ := newImportService(existingUsers())
+	service.Settings.AllowSHA1Hashes = true
+
+	report, err := service.Import(context.TODO(), strings.NewReader(importHashesFile), domain.UserImportFormatJSONLines, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, report.Created)
+	assert.Equal(t, domain.UserImportRowCreated, report.Rows[2].Status)
+}
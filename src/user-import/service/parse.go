@@ -0,0 +1,128 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Longest line accepted on JSON lines imports
+const maxJSONLineLength = 1024 * 1024
+
+// Row read from an import, with the reason it couldn't be read when it failed.
+type parsedRow struct {
+	line   int
+	record domain.UserImportRecord
+	err    error
+}
+
+// Reads the rows of an import.
+// Errors that make the whole file unreadable wrap domain.ErrBadParamInput.
+func readRows(r io.Reader, format domain.UserImportFormat) ([]parsedRow, error) {
+	switch format {
+	case domain.UserImportFormatCSV:
+		return readCSVRows(r)
+	case domain.UserImportFormatJSONLines:
+		return readJSONRows(r)
+	}
+	return nil, fmt.Errorf("%w: unknown format %q", domain.ErrBadParamInput, format)
+}
+
+// Reads a CSV file whose header names the columns of the records.
+func readCSVRows(r io.Reader) ([]parsedRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: missing header", domain.ErrBadParamInput)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrBadParamInput, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "username", "email", "password", "password_hash", "role":
+			columns[name] = i
+		default:
+			return nil, fmt.Errorf("%w: unknown column %q", domain.ErrBadParamInput, name)
+		}
+	}
+	if _, ok := columns["username"]; !ok {
+		return nil, fmt.Errorf("%w: missing column %q", domain.ErrBadParamInput, "username")
+	}
+	_, hasPassword := columns["password"]
+	_, hasPasswordHash := columns["password_hash"]
+	if !hasPassword && !hasPasswordHash {
+		return nil, fmt.Errorf("%w: missing column %q", domain.ErrBadParamInput, "password")
+	}
+
+	field := func(fields []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(fields) {
+			return strings.TrimSpace(fields[i])
+		}
+		return ""
+	}
+
+	result := make([]parsedRow, 0)
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			return result, nil
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := parsedRow{line: line}
+		if errors.Is(err, csv.ErrFieldCount) {
+			row.err = errors.New("wrong number of fields")
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrBadParamInput, err)
+		}
+
+		row.record = domain.UserImportRecord{
+			Username:     field(fields, "username"),
+			Email:        field(fields, "email"),
+			Password:     field(fields, "password"),
+			PasswordHash: field(fields, "password_hash"),
+			Role:         field(fields, "role"),
+		}
+		result = append(result, row)
+	}
+}
+
+// Reads a file with one JSON record per line, ignoring the blank lines.
+func readJSONRows(r io.Reader) ([]parsedRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLineLength)
+
+	result := make([]parsedRow, 0)
+	for line := 1; scanner.Scan(); line++ {
+		content := bytes.TrimSpace(scanner.Bytes())
+		if len(content) == 0 {
+			continue
+		}
+
+		row := parsedRow{line: line}
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row.record); err != nil {
+			row.err = fmt.Errorf("invalid JSON: %v", err)
+		}
+		result = append(result, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrBadParamInput, err)
+	}
+	return result, nil
+}
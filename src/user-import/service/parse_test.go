@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadRows_UnknownFormat(t *testing.T) {
+	rows, err := readRows(strings.NewReader(""), "xml")
+	assert.Nil(t, rows)
+	assert.True(t, errors.Is(err, domain.ErrBadParamInput))
+}
+
+func TestReadRows_CSVInvalidHeader(t *testing.T) {
+	cases := map[string]string{
+		"empty file":       "",
+		"unknown column":   "username,password,age\n",
+		"missing password": "username,email\n",
+		"broken quotes":    "username,\"password\n",
+	}
+
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			rows, err := readRows(strings.NewReader(content), domain.UserImportFormatCSV)
+			assert.Nil(t, rows)
+			assert.True(t, errors.Is(err, domain.ErrBadParamInput))
+		})
+	}
+}
+
+func TestReadRows_CSV(t *testing.T) {
+	content := "Username, Password, Email\n" +
+		"alice, secret123, alice@example.com\n" +
+		"bob,secret456\n" +
+		"carol,secret789,\n"
+
+	rows, err := readRows(strings.NewReader(content), domain.UserImportFormatCSV)
+	assert.Nil(t, err)
+	assert.Len(t, rows, 3)
+
+	assert.Equal(t, parsedRow{line: 2, record: domain.UserImportRecord{Username: "alice", Password: "secret123", Email: "alice@example.com"}}, rows[0])
+	assert.Equal(t, 3, rows[1].line)
+	assert.EqualError(t, rows[1].err, "wrong number of fields")
+	assert.Equal(t, parsedRow{line: 4, record: domain.UserImportRecord{Username: "carol", Password: "secret789"}}, rows[2])
+}
+
+func TestReadRows_CSVPasswordHashes(t *testing.T) {
+	content := "username,password_hash\n" +
+		"alice,sha1$saltsalt$599c8acd3f623920c9f30c903535e864275a2e35\n"
+
+	rows, err := readRows(strings.NewReader(content), domain.UserImportFormatCSV)
+	assert.Nil(t, err)
+	assert.Equal(t, []parsedRow{
+		{line: 2, record: domain.UserImportRecord{Username: "alice", PasswordHash: "sha1$saltsalt$599c8acd3f623920c9f30c903535e864275a2e35"}},
+	}, rows)
+}
+
+func TestReadRows_JSONLines(t *testing.T) {
+	content := `{"username":"alice","password":"secret123","role":"admin"}` + "\n" +
+		"\n" +
+		`{"username":"bob","password":"secret456","age":30}` + "\n" +
+		`not json`
+
+	rows, err := readRows(strings.NewReader(content), domain.UserImportFormatJSONLines)
+	assert.Nil(t, err)
+	assert.Len(t, rows, 3)
+
+	assert.Equal(t, parsedRow{line: 1, record: domain.UserImportRecord{Username: "alice", Password: "secret123", Role: "admin"}}, rows[0])
+	assert.Equal(t, 3, rows[1].line)
+	assert.Contains(t, rows[1].err.Error(), `unknown field "age"`)
+	assert.Equal(t, 4, rows[2].line)
+	assert.Contains(t, rows[2].err.Error(), "invalid JSON")
+}
+
+func TestReadRows_JSONLineTooLong(t *testing.T) {
+	content := `{"username":"` + strings.Repeat("a", maxJSONLineLength) + `"}`
+
+	rows, err := readRows(strings.NewReader(content), domain.UserImportFormatJSONLines)
+	assert.Nil(t, rows)
+	assert.True(t, errors.Is(err, domain.ErrBadParamInput))
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Passwords are hashed with bcrypt, which ignores anything after 72 bytes.
+const maxPasswordLength = 72
+
+type DefaultUserImportService struct {
+	Logger              *log.Logger
+	UserRepo            domain.UserRepository
+	RoleRepo            domain.RoleRepository
+	UsernameHistoryRepo domain.UsernameHistoryRepository
+	ContextTimeout      time.Duration
+	BcryptHashingCost   int
+	Settings            domain.UserImportSettings
+}
+
+// New service Instantiation
+func New(
+	logger *log.Logger,
+	userRepo domain.UserRepository,
+	roleRepo domain.RoleRepository,
+	usernameHistoryRepo domain.UsernameHistoryRepository,
+	contextTimeout time.Duration,
+	bcryptHashingCost int,
+	settings domain.UserImportSettings,
+) domain.UserImportService {
+	return DefaultUserImportService{
+		logger,
+		userRepo,
+		roleRepo,
+		usernameHistoryRepo,
+		contextTimeout,
+		bcryptHashingCost,
+		settings,
+	}
+}
+
+// Instantiation for tests
+func newService(userRepo domain.UserRepository, roleRepo domain.RoleRepository, usernameHistoryRepo domain.UsernameHistoryRepository) DefaultUserImportService {
+	return DefaultUserImportService{
+		log.New(ioutil.Discard, "tests: ", log.Flags()),
+		userRepo,
+		roleRepo,
+		usernameHistoryRepo,
+		time.Duration(5 * time.Second),
+		2,
+		domain.UserImportSettings{
+			BatchSize:         2,
+			MinPasswordLength: 8,
+		},
+	}
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Creates the table with the previous usernames of the users.
+// Old usernames are reserved for their users until a given date, so they can't be taken over.
+func CreateUsernameHistoryTable(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS username_history(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			organization_id uuid NOT NULL REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			old_username varchar(255) NOT NULL,
+			normalized_old_username varchar(255) NOT NULL,
+			new_username varchar(255) NOT NULL,
+			changed_at timestamptz NOT NULL DEFAULT (now()),
+			reserved_until timestamptz NOT NULL,
+			PRIMARY KEY (id)
+		);
+		CREATE INDEX IF NOT EXISTS username_history_user_id_changed_at_idx ON username_history (user_id, changed_at DESC);
+		CREATE INDEX IF NOT EXISTS username_history_reserved_idx ON username_history (organization_id, normalized_old_username, reserved_until);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewCreateUsernameHistoryTableMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "create-username-history-table",
+		Up:   CreateUsernameHistoryTable,
+	}
+}
@@ -0,0 +1,107 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateUsernameHistoryTable_FailExec(t *testing.T) {
+	migration := NewCreateUsernameHistoryTableMigration()
+	assert.Equal(t, migration.Name, "create-username-history-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS username_history(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			organization_id uuid NOT NULL REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			old_username varchar(255) NOT NULL,
+			normalized_old_username varchar(255) NOT NULL,
+			new_username varchar(255) NOT NULL,
+			changed_at timestamptz NOT NULL DEFAULT (now()),
+			reserved_until timestamptz NOT NULL,
+			PRIMARY KEY (id)
+		);
+		CREATE INDEX IF NOT EXISTS username_history_user_id_changed_at_idx ON username_history (user_id, changed_at DESC);
+		CREATE INDEX IF NOT EXISTS username_history_reserved_idx ON username_history (organization_id, normalized_old_username, reserved_until);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestCreateUsernameHistoryTable_TimeoutReached(t *testing.T) {
+	migration := NewCreateUsernameHistoryTableMigration()
+	assert.Equal(t, migration.Name, "create-username-history-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS username_history(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			organization_id uuid NOT NULL REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			old_username varchar(255) NOT NULL,
+			normalized_old_username varchar(255) NOT NULL,
+			new_username varchar(255) NOT NULL,
+			changed_at timestamptz NOT NULL DEFAULT (now()),
+			reserved_until timestamptz NOT NULL,
+			PRIMARY KEY (id)
+		);
+		CREATE INDEX IF NOT EXISTS username_history_user_id_changed_at_idx ON username_history (user_id, changed_at DESC);
+		CREATE INDEX IF NOT EXISTS username_history_reserved_idx ON username_history (organization_id, normalized_old_username, reserved_until);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestCreateUsernameHistoryTable_Success(t *testing.T) {
+	migration := NewCreateUsernameHistoryTableMigration()
+	assert.Equal(t, migration.Name, "create-username-history-table")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		CREATE TABLE IF NOT EXISTS username_history(
+			id uuid DEFAULT uuid_generate_v4() NOT NULL,
+			organization_id uuid NOT NULL REFERENCES organizations(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+			old_username varchar(255) NOT NULL,
+			normalized_old_username varchar(255) NOT NULL,
+			new_username varchar(255) NOT NULL,
+			changed_at timestamptz NOT NULL DEFAULT (now()),
+			reserved_until timestamptz NOT NULL,
+			PRIMARY KEY (id)
+		);
+		CREATE INDEX IF NOT EXISTS username_history_user_id_changed_at_idx ON username_history (user_id, changed_at DESC);
+		CREATE INDEX IF NOT EXISTS username_history_reserved_idx ON username_history (organization_id, normalized_old_username, reserved_until);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets the changes of the username of a user, newest first.
+func (r PostgresRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]domain.UsernameChange, error) {
+	result := make([]domain.UsernameChange, 0)
+
+	query := `
+		SELECT id, user_id, old_username, new_username, changed_at, reserved_until
+		FROM username_history
+		WHERE user_id = $1 AND organization_id = $2
+		ORDER BY changed_at DESC, id
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return result, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, userID, domain.OrganizationFromContext(ctx))
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		change, err := r.scanChangeRow(rows)
+		if err != nil {
+			return make([]domain.UsernameChange, 0), err
+		}
+		result = append(result, change)
+	}
+	return result, rows.Err()
+}
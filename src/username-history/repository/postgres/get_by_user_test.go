@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const getByUserQuery = `
+		SELECT id, user_id, old_username, new_username, changed_at, reserved_until
+		FROM username_history
+		WHERE user_id = $1 AND organization_id = $2
+		ORDER BY changed_at DESC, id
+	`
+
+func TestGetByUser_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getByUserQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).GetByUser(context.TODO(), uuid.New())
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, res)
+}
+
+func TestGetByUser_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(getByUserQuery)).
+		ExpectQuery().
+		WithArgs(userID, domain.DefaultOrganizationID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).GetByUser(ctx, userID)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, res)
+}
+
+func TestGetByUser_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	changedAt := time.Now()
+	expected := []domain.UsernameChange{
+		{ID: uuid.New(), UserID: userID, OldUsername: "bob", NewUsername: "carol", ChangedAt: changedAt, ReservedUntil: changedAt.Add(time.Hour)},
+		{ID: uuid.New(), UserID: userID, OldUsername: "alice", NewUsername: "bob", ChangedAt: changedAt.Add(-time.Hour), ReservedUntil: changedAt},
+	}
+	rows := sqlmock.NewRows(changeColumns)
+	for _, change := range expected {
+		rows.AddRow(change.ID, change.UserID, change.OldUsername, change.NewUsername, change.ChangedAt, change.ReservedUntil)
+	}
+	mock.ExpectPrepare(regexp.QuoteMeta(getByUserQuery)).
+		ExpectQuery().
+		WithArgs(userID, domain.DefaultOrganizationID).
+		WillReturnRows(rows)
+
+	res, err := New(db).GetByUser(context.TODO(), userID)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, res)
+}
@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/helpers"
+)
+
+// Returns if a username was given up by another user of the organization of the context
+// and is still reserved at a given time.
+func (r PostgresRepository) IsReserved(ctx context.Context, username string, exceptUserID uuid.UUID, at time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM username_history
+			WHERE organization_id = $1 AND normalized_old_username = $2 AND user_id <> $3 AND reserved_until > $4
+		)
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return false, err
+	}
+
+	var reserved bool
+	err = stmt.QueryRowContext(
+		ctx,
+		domain.OrganizationFromContext(ctx),
+		helpers.NormalizeUsername(username),
+		exceptUserID,
+		at,
+	).Scan(&reserved)
+	return reserved, err
+}
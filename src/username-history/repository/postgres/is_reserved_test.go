@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const isReservedQuery = `
+		SELECT EXISTS (
+			SELECT 1 FROM username_history
+			WHERE organization_id = $1 AND normalized_old_username = $2 AND user_id <> $3 AND reserved_until > $4
+		)
+	`
+
+func TestIsReserved_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(isReservedQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).IsReserved(context.TODO(), "alice", uuid.Nil, time.Now())
+	assert.Equal(t, err.Error(), "boom")
+	assert.False(t, res)
+}
+
+func TestIsReserved_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	at := time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(isReservedQuery)).
+		ExpectQuery().
+		WithArgs(domain.DefaultOrganizationID, "alice", uuid.Nil, at).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).IsReserved(ctx, "alice", uuid.Nil, at)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.False(t, res)
+}
+
+func TestIsReserved_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	at := time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(isReservedQuery)).
+		ExpectQuery().
+		WithArgs(domain.DefaultOrganizationID, "alice", userID, at).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	res, err := New(db).IsReserved(context.TODO(), " ALICE ", userID, at)
+	assert.Nil(t, err)
+	assert.True(t, res)
+}
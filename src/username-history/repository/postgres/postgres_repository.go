@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+type PostgresRepository struct {
+	Db *sql.DB
+}
+
+func New(db *sql.DB) domain.UsernameHistoryRepository {
+	return PostgresRepository{db}
+}
+
+// Row of a single or multi row query
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans a username change row
+func (r PostgresRepository) scanChangeRow(row rowScanner) (domain.UsernameChange, error) {
+	result := domain.UsernameChange{}
+
+	err := row.Scan(
+		&result.ID,
+		&result.UserID,
+		&result.OldUsername,
+		&result.NewUsername,
+		&result.ChangedAt,
+		&result.ReservedUntil,
+	)
+	if err != nil {
+		return domain.UsernameChange{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/helpers"
+)
+
+// Stores a change of username in the organization of the context.
+func (r PostgresRepository) Store(ctx context.Context, change domain.UsernameChange) (domain.UsernameChange, error) {
+	if change.ID == uuid.Nil {
+		change.ID = uuid.New()
+	}
+
+	query := `
+		INSERT INTO username_history (id, organization_id, user_id, old_username, normalized_old_username, new_username, changed_at, reserved_until)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, user_id, old_username, new_username, changed_at, reserved_until
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.UsernameChange{}, err
+	}
+
+	row := stmt.QueryRowContext(
+		ctx,
+		change.ID,
+		domain.OrganizationFromContext(ctx),
+		change.UserID,
+		change.OldUsername,
+		helpers.NormalizeUsername(change.OldUsername),
+		change.NewUsername,
+		change.ChangedAt,
+		change.ReservedUntil,
+	)
+	return r.scanChangeRow(row)
+}
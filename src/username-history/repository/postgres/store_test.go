@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const storeQuery = `
+		INSERT INTO username_history (id, organization_id, user_id, old_username, normalized_old_username, new_username, changed_at, reserved_until)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, user_id, old_username, new_username, changed_at, reserved_until
+	`
+
+var changeColumns = []string{"id", "user_id", "old_username", "new_username", "changed_at", "reserved_until"}
+
+func TestStore_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(storeQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).Store(context.TODO(), domain.UsernameChange{OldUsername: "alice", NewUsername: "bob"})
+	assert.Equal(t, err.Error(), "boom")
+	assert.Equal(t, domain.UsernameChange{}, res)
+}
+
+func TestStore_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(storeQuery)).
+		ExpectQuery().
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).Store(ctx, domain.UsernameChange{OldUsername: "alice", NewUsername: "bob"})
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Equal(t, domain.UsernameChange{}, res)
+}
+
+func TestStore_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	change := domain.UsernameChange{
+		ID:            uuid.New(),
+		UserID:        uuid.New(),
+		OldUsername:   "Alice",
+		NewUsername:   "bob",
+		ChangedAt:     time.Now(),
+		ReservedUntil: time.Now().Add(30 * 24 * time.Hour),
+	}
+	organizationID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(storeQuery)).
+		ExpectQuery().
+		WithArgs(
+			change.ID,
+			organizationID,
+			change.UserID,
+			"Alice",
+			"alice",
+			"bob",
+			change.ChangedAt,
+			change.ReservedUntil,
+		).
+		WillReturnRows(sqlmock.NewRows(changeColumns).
+			AddRow(change.ID, change.UserID, "Alice", "bob", change.ChangedAt, change.ReservedUntil))
+
+	res, err := New(db).Store(domain.WithOrganization(context.TODO(), organizationID), change)
+	assert.Nil(t, err)
+	assert.Equal(t, change, res)
+}
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Returns if a user changed from a username after a given time.
+// User IDs are unique across organizations, so it isn't scoped to the one of the context,
+// letting tokens be checked before knowing their organization.
+func (r PostgresRepository) WasRenamedSince(ctx context.Context, userID uuid.UUID, username string, since time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM username_history
+			WHERE user_id = $1 AND old_username = $2 AND changed_at >= $3
+		)
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return false, err
+	}
+
+	var renamed bool
+	err = stmt.QueryRowContext(ctx, userID, username, since).Scan(&renamed)
+	return renamed, err
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const wasRenamedSinceQuery = `
+		SELECT EXISTS (
+			SELECT 1 FROM username_history
+			WHERE user_id = $1 AND old_username = $2 AND changed_at >= $3
+		)
+	`
+
+func TestWasRenamedSince_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(wasRenamedSinceQuery)).WillReturnError(errors.New("boom"))
+
+	res, err := New(db).WasRenamedSince(context.TODO(), uuid.New(), "alice", time.Now())
+	assert.Equal(t, err.Error(), "boom")
+	assert.False(t, res)
+}
+
+func TestWasRenamedSince_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	since := time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(wasRenamedSinceQuery)).
+		ExpectQuery().
+		WithArgs(userID, "alice", since).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	res, err := New(db).WasRenamedSince(ctx, userID, "alice", since)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.False(t, res)
+}
+
+func TestWasRenamedSince_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userID := uuid.New()
+	since := time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(wasRenamedSinceQuery)).
+		ExpectQuery().
+		WithArgs(userID, "alice", since).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	res, err := New(db).WasRenamedSince(context.TODO(), userID, "alice", since)
+	assert.Nil(t, err)
+	assert.True(t, res)
+}
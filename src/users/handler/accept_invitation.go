@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Activates an invited user with its password and profile, that is signed in right away.
+func (srv UserGRPCHandler) AcceptInvitation(ctx context.Context, in *users.AcceptInvitationRequest) (*users.TokenResponse, error) {
+	if in == nil || len(in.Token) == 0 || len(in.Password) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	user, err := srv.invitationService.Accept(ctx, domain.AcceptInvitationRequest{
+		Token:    in.GetToken(),
+		Password: in.GetPassword(),
+		Profile: domain.UserProfile{
+			FirstName: in.GetFirstName(),
+			LastName:  in.GetLastName(),
+		},
+	})
+	switch {
+	case errors.Is(err, domain.ErrInvalidToken):
+		return nil, status.Error(codes.InvalidArgument, "invalid token")
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	case err != nil:
+		srv.l.Printf("error accepting an invitation: %v\n", err)
+		return nil, status.Error(codes.Internal, "error accepting invitation")
+	}
+
+	// Users of a role requiring MFA have to enroll before getting the tokens.
+	challenge, err := srv.mfaChallenge(ctx, user)
+	if err != nil || challenge != nil {
+		return challenge, err
+	}
+
+	token, err := srv.tokenManager.GenerateTokens(ctx, user)
+	if err != nil {
+		srv.l.Printf("error generating tokens on accepting an invitation: %v\n", err)
+		return nil, status.Error(codes.Internal, "error generating tokens")
+	}
+
+	return &users.TokenResponse{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		User:         userResponse(user),
+	}, nil
+}
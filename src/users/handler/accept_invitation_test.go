@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAcceptInvitation_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+
+	for _, req := range []*users.AcceptInvitationRequest{nil, {Token: "the-token"}, {Password: "password"}} {
+		res, err := service.AcceptInvitation(context.TODO(), req)
+		assert.Nil(t, res)
+		assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+	}
+}
+
+func TestAcceptInvitation_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"invalid token":    {domain.ErrInvalidToken, status.Error(codes.InvalidArgument, "invalid token")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error accepting invitation")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			service := newHandler(nil, nil, nil)
+			invitationService := new(mocks.InvitationService)
+			service.invitationService = invitationService
+			invitationService.On("Accept", mock.Anything, mock.Anything).Once().Return(nil, c.serviceErr)
+
+			res, err := service.AcceptInvitation(context.TODO(), &users.AcceptInvitationRequest{Token: "the-token", Password: "password"})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			invitationService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAcceptInvitation_Success(t *testing.T) {
+	userID := uuid.New()
+	user := &domain.User{ID: userID, Username: "alice", FirstName: "Alice", Status: domain.UserStatusActive}
+
+	tokenHandler := new(mocks.AccessTokenHandler)
+	service := newHandler(tokenHandler, nil, nil)
+	invitationService := new(mocks.InvitationService)
+	service.invitationService = invitationService
+	mfaService := new(mocks.MFAService)
+	service.mfaService = mfaService
+
+	invitationService.On("Accept", mock.Anything, domain.AcceptInvitationRequest{
+		Token:    "the-token",
+		Password: "password",
+		Profile:  domain.UserProfile{FirstName: "Alice", LastName: "Smith"},
+	}).Once().Return(user, nil)
+	mfaService.On("IsEnrolled", mock.Anything, userID).Once().Return(false, nil)
+	tokenHandler.On("GenerateTokens", mock.Anything, user).Once().
+		Return(domain.TokenResponse{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil)
+
+	res, err := service.AcceptInvitation(context.TODO(), &users.AcceptInvitationRequest{
+		Token:     "the-token",
+		Password:  "password",
+		FirstName: "Alice",
+		LastName:  "Smith",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.TokenResponse{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+		User:         &users.UserResponse{Id: userID.String(), Username: "alice", FirstName: "Alice", Status: "active"},
+	}, res)
+	invitationService.AssertExpectations(t)
+	tokenHandler.AssertExpectations(t)
+}
@@ -0,0 +1,13 @@
+package handler
+
+import (
+	"context"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+)
+
+// Adds a user to a group. The user inherits the roles of the group on its next refresh.
+// Only for administrators.
+func (srv UserGRPCHandler) AddGroupMember(ctx context.Context, in *users.GroupMemberRequest) (*users.EmptyResponse, error) {
+	return srv.changeGroupMembership(ctx, in, true)
+}
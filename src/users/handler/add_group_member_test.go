@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAddGroupMember_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.AddGroupMember(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	service, _ = newGroupHandler()
+	res, err = service.AddGroupMember(context.TODO(), &users.GroupMemberRequest{AccessToken: "cenas", GroupId: uuid.NewString(), UserId: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+}
+
+func TestAddGroupMember_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"not found":        {domain.ErrNotFound, status.Error(codes.NotFound, "group member not found")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error changing group members")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			groupID, userID := uuid.New(), uuid.New()
+			service, groupService := newGroupHandler()
+			groupService.On("AddMember", mock.Anything, groupID, userID).Once().Return(c.serviceErr)
+
+			res, err := service.AddGroupMember(context.TODO(), &users.GroupMemberRequest{AccessToken: "cenas", GroupId: groupID.String(), UserId: userID.String()})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			groupService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddGroupMember_Success(t *testing.T) {
+	groupID, userID := uuid.New(), uuid.New()
+	service, groupService := newGroupHandler()
+	groupService.On("AddMember", mock.Anything, groupID, userID).Once().Return(nil)
+
+	res, err := service.AddGroupMember(context.TODO(), &users.GroupMemberRequest{AccessToken: "cenas", GroupId: groupID.String(), UserId: userID.String()})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.EmptyResponse{}, res)
+	groupService.AssertExpectations(t)
+}
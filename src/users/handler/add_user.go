@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"errors"
 
 	"github.com/plagioriginal/user-microservice/domain"
 	users "github.com/plagioriginal/users-service-grpc/users"
@@ -11,29 +12,13 @@ import (
 
 // Adds a new user.
 func (srv UserGRPCHandler) AddUser(ctx context.Context, in *users.NewUserRequest) (*users.UserResponse, error) {
-	if in == nil || len(in.AccessToken) == 0 {
+	if in == nil {
 		return nil, status.Error(codes.Unauthenticated, "invalid token")
 	}
 
-	token, err := srv.tokenManager.ParseJWT(in.AccessToken)
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
 	if err != nil {
-		srv.l.Println("error parsing jwt token in add-user: " + err.Error())
-		return nil, status.Error(codes.Unauthenticated, "invalid token")
-	}
-
-	if !srv.tokenManager.IsJWTokenValid(token) {
-		srv.l.Printf("invalid token %v\n", token)
-		return nil, status.Error(codes.Unauthenticated, "invalid token")
-	}
-
-	tokenRole, err := srv.tokenManager.GetUserRoleFromToken(token)
-	if err != nil {
-		srv.l.Printf("error getting role from token: %v\n", err)
-		return nil, status.Error(codes.InvalidArgument, "invalid token")
-	}
-
-	if tokenRole != domain.DEFAULT_ROLE_ADMIN.RoleSlug {
-		return nil, status.Error(codes.Unauthenticated, "incorrect permissions")
+		return nil, err
 	}
 
 	if len(in.Username) == 0 || len(in.Password) == 0 || len(in.Role) == 0 {
@@ -41,25 +26,32 @@ func (srv UserGRPCHandler) AddUser(ctx context.Context, in *users.NewUserRequest
 	}
 
 	user, err := srv.userService.Store(ctx, domain.StoreUserRequest{
-		Username: in.GetUsername(),
-		Password: in.GetPassword(),
-		RoleSlug: in.GetRole(),
+		Username:           in.GetUsername(),
+		Email:              in.GetEmail(),
+		Password:           in.GetPassword(),
+		RoleSlug:           in.GetRole(),
+		MustChangePassword: in.GetMustChangePassword(),
 	})
 
+	if errors.Is(err, domain.ErrBadParamInput) {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	if errors.Is(err, domain.ErrAlreadyExists) {
+		return nil, status.Error(codes.AlreadyExists, "username or email already taken")
+	}
+
 	if err != nil {
 		srv.l.Printf("error storing a user: %v\n", err)
 		return nil, status.Error(codes.Internal, "error storing user")
 	}
 
-	return &users.UserResponse{
-		Id:        user.ID.String(),
-		Username:  user.Username,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Role: &users.UserResponse_RoleResponse{
-			Id:        user.RoleId.String(),
-			RoleLabel: user.Role.RoleLabel,
-			RoleSlug:  user.Role.RoleSlug,
-		},
-	}, nil
+	// The user is created either way, the email can be sent again later.
+	if len(user.Email) > 0 {
+		if err = srv.emailVerificationService.SendVerificationEmail(ctx, user); err != nil {
+			srv.l.Printf("error sending the verification email: %v\n", err)
+		}
+	}
+
+	return userResponse(user), nil
 }
@@ -17,7 +17,7 @@ import (
 )
 
 func TestAddUser_InvalidInput(t *testing.T) {
-	service := newHandler(nil, nil)
+	service := newHandler(nil, nil, nil)
 	res, err := service.AddUser(context.TODO(), nil)
 	assert.Nil(t, res)
 	assert.Error(t, err)
@@ -31,7 +31,7 @@ func TestAddUser_InvalidInput(t *testing.T) {
 
 func TestAddUser_ErrorParsingToken(t *testing.T) {
 	accessTokenManager := new(mocks.AccessTokenHandler)
-	service := newHandler(accessTokenManager, nil)
+	service := newHandler(accessTokenManager, nil, nil)
 
 	accessTokenManager.On("ParseJWT", "cenas").Once().Return(nil, errors.New("boom"))
 	res, err := service.AddUser(context.TODO(), &users.NewUserRequest{
@@ -45,11 +45,11 @@ func TestAddUser_ErrorParsingToken(t *testing.T) {
 
 func TestAddUser_ErrorIfTokenIsInvalid(t *testing.T) {
 	accessTokenManager := new(mocks.AccessTokenHandler)
-	service := newHandler(accessTokenManager, nil)
+	service := newHandler(accessTokenManager, nil, nil)
 
 	mockToken := &jwt.Token{Raw: "mock token"}
 	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
-	accessTokenManager.On("IsJWTokenValid", mockToken).Once().Return(false)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(false)
 	res, err := service.AddUser(context.TODO(), &users.NewUserRequest{
 		AccessToken: "cenas",
 	})
@@ -62,12 +62,12 @@ func TestAddUser_ErrorIfTokenIsInvalid(t *testing.T) {
 
 func TestAddUser_ErrorGettingUserFromToken(t *testing.T) {
 	accessTokenManager := new(mocks.AccessTokenHandler)
-	service := newHandler(accessTokenManager, nil)
+	service := newHandler(accessTokenManager, nil, nil)
 
 	mockToken := &jwt.Token{Raw: "mock token"}
 	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
-	accessTokenManager.On("IsJWTokenValid", mockToken).Once().Return(true)
-	accessTokenManager.On("GetUserRoleFromToken", mockToken).Once().Return("", errors.New("boom"))
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return(nil, errors.New("boom"))
 	res, err := service.AddUser(context.TODO(), &users.NewUserRequest{
 		AccessToken: "cenas",
 	})
@@ -80,12 +80,12 @@ func TestAddUser_ErrorGettingUserFromToken(t *testing.T) {
 
 func TestAddUser_UserDoesntHaveProperRole(t *testing.T) {
 	accessTokenManager := new(mocks.AccessTokenHandler)
-	service := newHandler(accessTokenManager, nil)
+	service := newHandler(accessTokenManager, nil, nil)
 
 	mockToken := &jwt.Token{Raw: "mock token"}
 	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
-	accessTokenManager.On("IsJWTokenValid", mockToken).Once().Return(true)
-	accessTokenManager.On("GetUserRoleFromToken", mockToken).Once().Return("user", nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"user"}, nil)
 	res, err := service.AddUser(context.TODO(), &users.NewUserRequest{
 		AccessToken: "cenas",
 	})
@@ -98,12 +98,13 @@ func TestAddUser_UserDoesntHaveProperRole(t *testing.T) {
 
 func TestAddUser_InvalidRequestInParameters(t *testing.T) {
 	accessTokenManager := new(mocks.AccessTokenHandler)
-	service := newHandler(accessTokenManager, nil)
+	service := newHandler(accessTokenManager, nil, nil)
 
 	mockToken := &jwt.Token{Raw: "mock token"}
 	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
-	accessTokenManager.On("IsJWTokenValid", mockToken).Once().Return(true)
-	accessTokenManager.On("GetUserRoleFromToken", mockToken).Once().Return("admin", nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"admin"}, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Once().Return(domain.DefaultOrganizationID, nil)
 	res, err := service.AddUser(context.TODO(), &users.NewUserRequest{
 		AccessToken: "cenas",
 	})
@@ -117,12 +118,13 @@ func TestAddUser_InvalidRequestInParameters(t *testing.T) {
 func TestAddUser_ErrorUserStoring(t *testing.T) {
 	accessTokenManager := new(mocks.AccessTokenHandler)
 	userService := new(mocks.UserService)
-	service := newHandler(accessTokenManager, userService)
+	service := newHandler(accessTokenManager, userService, nil)
 
 	mockToken := &jwt.Token{Raw: "mock token"}
 	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
-	accessTokenManager.On("IsJWTokenValid", mockToken).Once().Return(true)
-	accessTokenManager.On("GetUserRoleFromToken", mockToken).Once().Return("admin", nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"admin"}, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Once().Return(domain.DefaultOrganizationID, nil)
 
 	userService.On("Store", mock.Anything, domain.StoreUserRequest{
 		Username: "username",
@@ -147,12 +149,13 @@ func TestAddUser_ErrorUserStoring(t *testing.T) {
 func TestAddUser_Success(t *testing.T) {
 	accessTokenManager := new(mocks.AccessTokenHandler)
 	userService := new(mocks.UserService)
-	service := newHandler(accessTokenManager, userService)
+	service := newHandler(accessTokenManager, userService, nil)
 
 	mockToken := &jwt.Token{Raw: "mock token"}
 	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
-	accessTokenManager.On("IsJWTokenValid", mockToken).Once().Return(true)
-	accessTokenManager.On("GetUserRoleFromToken", mockToken).Once().Return("admin", nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"admin"}, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Once().Return(domain.DefaultOrganizationID, nil)
 
 	userId := uuid.New()
 	roleId := uuid.New()
@@ -195,3 +198,134 @@ func TestAddUser_Success(t *testing.T) {
 	accessTokenManager.AssertExpectations(t)
 	userService.AssertExpectations(t)
 }
+
+func TestAddUser_UsernameAlreadyTaken(t *testing.T) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	userService := new(mocks.UserService)
+	service := newHandler(accessTokenManager, userService, nil)
+
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"admin"}, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Once().Return(domain.DefaultOrganizationID, nil)
+
+	userService.On("Store", mock.Anything, domain.StoreUserRequest{
+		Username: "Username",
+		Password: "password",
+		RoleSlug: "user",
+	}).Once().Return(nil, domain.ErrAlreadyExists)
+
+	res, err := service.AddUser(context.TODO(), &users.NewUserRequest{
+		AccessToken: "cenas",
+		Username:    "Username",
+		Password:    "password",
+		Role:        "user",
+	})
+
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.AlreadyExists, "username or email already taken"))
+	accessTokenManager.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
+
+func TestAddUser_InvalidEmail(t *testing.T) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	userService := new(mocks.UserService)
+	service := newHandler(accessTokenManager, userService, nil)
+
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"admin"}, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Once().Return(domain.DefaultOrganizationID, nil)
+
+	userService.On("Store", mock.Anything, domain.StoreUserRequest{
+		Username: "username",
+		Email:    "nope",
+		Password: "password",
+		RoleSlug: "user",
+	}).Once().Return(nil, domain.ErrBadParamInput)
+
+	res, err := service.AddUser(context.TODO(), &users.NewUserRequest{
+		AccessToken: "cenas",
+		Username:    "username",
+		Password:    "password",
+		Role:        "user",
+		Email:       "nope",
+	})
+
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+	userService.AssertExpectations(t)
+}
+
+func TestAddUser_SendsVerificationEmail(t *testing.T) {
+	userId := uuid.New()
+	roleId := uuid.New()
+	user := &domain.User{
+		ID:       userId,
+		Username: "username",
+		Email:    "user@example.com",
+		RoleId:   roleId,
+		Role: &domain.Role{
+			ID:        roleId,
+			RoleLabel: "User",
+			RoleSlug:  "user",
+		},
+	}
+
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	userService := new(mocks.UserService)
+	emailVerificationService := new(mocks.EmailVerificationService)
+	service := newHandler(accessTokenManager, userService, nil)
+	service.emailVerificationService = emailVerificationService
+
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Twice().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Twice().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Twice().Return([]string{"admin"}, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Twice().Return(domain.DefaultOrganizationID, nil)
+
+	userService.On("Store", mock.Anything, domain.StoreUserRequest{
+		Username: "username",
+		Email:    "user@example.com",
+		Password: "password",
+		RoleSlug: "user",
+	}).Twice().Return(user, nil)
+
+	emailVerificationService.On("SendVerificationEmail", mock.Anything, user).
+		Once().Return(nil)
+	emailVerificationService.On("SendVerificationEmail", mock.Anything, user).
+		Once().Return(errors.New("boom"))
+
+	req := &users.NewUserRequest{
+		AccessToken: "cenas",
+		Username:    "username",
+		Password:    "password",
+		Role:        "user",
+		Email:       "user@example.com",
+	}
+	expected := &users.UserResponse{
+		Id:       userId.String(),
+		Username: "username",
+		Email:    "user@example.com",
+		Role: &users.UserResponse_RoleResponse{
+			Id:        roleId.String(),
+			RoleLabel: "User",
+			RoleSlug:  "user",
+		},
+	}
+
+	res, err := service.AddUser(context.TODO(), req)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, res)
+
+	// Failing to send the email doesn't fail the creation.
+	res, err = service.AddUser(context.TODO(), req)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, res)
+
+	userService.AssertExpectations(t)
+	emailVerificationService.AssertExpectations(t)
+}
@@ -0,0 +1,13 @@
+package handler
+
+import (
+	"context"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+)
+
+// Assigns a role to a group, which its members inherit on their next refresh.
+// Only for administrators.
+func (srv UserGRPCHandler) AssignGroupRole(ctx context.Context, in *users.GroupRoleRequest) (*users.GroupResponse, error) {
+	return srv.changeGroupRole(ctx, in, true)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAssignGroupRole_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.AssignGroupRole(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	service, _ = newGroupHandler()
+	res, err = service.AssignGroupRole(context.TODO(), &users.GroupRoleRequest{AccessToken: "cenas", GroupId: "cenas", Role: "editor"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+}
+
+func TestAssignGroupRole_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"missing role":     {domain.ErrBadParamInput, status.Error(codes.InvalidArgument, "invalid request")},
+		"not found":        {domain.ErrNotFound, status.Error(codes.NotFound, "group role not found")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error changing group roles")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			groupID := uuid.New()
+			service, groupService := newGroupHandler()
+			groupService.On("AssignRole", mock.Anything, groupID, "editor").Once().Return(domain.Group{}, c.serviceErr)
+
+			res, err := service.AssignGroupRole(context.TODO(), &users.GroupRoleRequest{AccessToken: "cenas", GroupId: groupID.String(), Role: "editor"})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			groupService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAssignGroupRole_Success(t *testing.T) {
+	group := domain.Group{ID: uuid.New(), Name: "Editors", Roles: []domain.Role{{ID: uuid.New(), RoleSlug: "editor"}}}
+	service, groupService := newGroupHandler()
+	groupService.On("AssignRole", mock.Anything, group.ID, "editor").Once().Return(group, nil)
+
+	res, err := service.AssignGroupRole(context.TODO(), &users.GroupRoleRequest{AccessToken: "cenas", GroupId: group.ID.String(), Role: "editor"})
+	assert.Nil(t, err)
+	assert.Equal(t, group.ID.String(), res.Id)
+	assert.Equal(t, []string{"editor"}, res.Roles)
+	groupService.AssertExpectations(t)
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Checks that an access token is valid and belongs to an administrator.
+// Returns the context scoped to the organization of the administrator, who can only
+// manage the users of its own organization, or the gRPC error to send back otherwise.
+func (srv UserGRPCHandler) authorizeAdmin(ctx context.Context, accessToken string) (context.Context, error) {
+	if len(accessToken) == 0 {
+		return ctx, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	token, err := srv.tokenManager.ParseJWT(accessToken)
+	if err != nil {
+		srv.l.Println("error parsing jwt token: " + err.Error())
+		return ctx, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	if !srv.tokenManager.IsJWTokenValid(ctx, token) {
+		srv.l.Printf("invalid token %v\n", token)
+		return ctx, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	tokenRoles, err := srv.tokenManager.GetUserRolesFromToken(token)
+	if err != nil {
+		srv.l.Printf("error getting roles from token: %v\n", err)
+		return ctx, status.Error(codes.InvalidArgument, "invalid token")
+	}
+
+	// Administrators can be so through their own role or one of their groups.
+	for _, role := range tokenRoles {
+		if role == domain.DEFAULT_ROLE_ADMIN.RoleSlug {
+			return srv.scopeToToken(ctx, token)
+		}
+	}
+	return ctx, status.Error(codes.Unauthenticated, "incorrect permissions")
+}
+
+// Checks that an access token is valid and belongs to an administrator of the default organization,
+// who manages what is shared by every organization.
+func (srv UserGRPCHandler) authorizeOperator(ctx context.Context, accessToken string) (context.Context, error) {
+	ctx, err := srv.authorizeAdmin(ctx, accessToken)
+	if err != nil {
+		return ctx, err
+	}
+	if domain.OrganizationFromContext(ctx) != domain.DefaultOrganizationID {
+		return ctx, status.Error(codes.Unauthenticated, "incorrect permissions")
+	}
+	return ctx, nil
+}
+
+// Checks that an access token is valid and gets the ID of its user, along with
+// the context scoped to the organization of the user.
+// Returns the gRPC error to send back otherwise.
+func (srv UserGRPCHandler) authenticate(ctx context.Context, accessToken string) (context.Context, uuid.UUID, error) {
+	if len(accessToken) == 0 {
+		return ctx, uuid.Nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	token, err := srv.tokenManager.ParseJWT(accessToken)
+	if err != nil {
+		srv.l.Println("error parsing jwt token: " + err.Error())
+		return ctx, uuid.Nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	if !srv.tokenManager.IsJWTokenValid(ctx, token) {
+		srv.l.Printf("invalid token %v\n", token)
+		return ctx, uuid.Nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	userID, err := srv.tokenManager.GetUserIDFromToken(token)
+	if err != nil {
+		srv.l.Printf("error getting user from token: %v\n", err)
+		return ctx, uuid.Nil, status.Error(codes.InvalidArgument, "invalid token")
+	}
+
+	ctx, err = srv.scopeToToken(ctx, token)
+	if err != nil {
+		return ctx, uuid.Nil, err
+	}
+	return ctx, userID, nil
+}
+
+// Gets the user a request acts on: the authenticated user when no ID is given,
+// otherwise any user as long as the caller is an administrator.
+// Also returns if the caller is an administrator.
+// The context is scoped to the organization of the caller.
+func (srv UserGRPCHandler) targetUser(ctx context.Context, accessToken string, userID string) (context.Context, uuid.UUID, bool, error) {
+	ctx, callerID, err := srv.authenticate(ctx, accessToken)
+	if err != nil {
+		return ctx, uuid.Nil, false, err
+	}
+	_, err = srv.authorizeAdmin(ctx, accessToken)
+	isAdmin := err == nil
+
+	if len(userID) == 0 {
+		return ctx, callerID, isAdmin, nil
+	}
+
+	targetID, err := uuid.Parse(userID)
+	if err != nil {
+		return ctx, uuid.Nil, false, status.Error(codes.InvalidArgument, "invalid request")
+	}
+	if targetID != callerID && !isAdmin {
+		return ctx, uuid.Nil, false, status.Error(codes.Unauthenticated, "incorrect permissions")
+	}
+	return ctx, targetID, isAdmin, nil
+}
+
+// Scopes a context to the organization of the user of a token.
+// The organization of the token wins over the one asked for in the metadata.
+func (srv UserGRPCHandler) scopeToToken(ctx context.Context, token *jwt.Token) (context.Context, error) {
+	organizationID, err := srv.tokenManager.GetOrganizationIDFromToken(token)
+	if err != nil {
+		srv.l.Printf("error getting organization from token: %v\n", err)
+		return ctx, status.Error(codes.InvalidArgument, "invalid token")
+	}
+	return domain.WithOrganization(ctx, organizationID), nil
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Handler whose token "cenas" belongs to an administrator of an organization.
+func newOrganizationAdminHandler(organizationID uuid.UUID) (UserGRPCHandler, *mocks.AccessTokenHandler) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"admin"}, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Once().Return(organizationID, nil)
+	return newHandler(accessTokenManager, nil, nil), accessTokenManager
+}
+
+func TestAuthorizeAdmin_ScopesToTheOrganizationOfTheToken(t *testing.T) {
+	organizationID := uuid.New()
+	service, accessTokenManager := newOrganizationAdminHandler(organizationID)
+
+	// The organization asked for in the metadata doesn't matter once there is a token.
+	ctx := domain.WithOrganization(context.TODO(), uuid.New())
+	ctx, err := service.authorizeAdmin(ctx, "cenas")
+	assert.Nil(t, err)
+	assert.Equal(t, organizationID, domain.OrganizationFromContext(ctx))
+	accessTokenManager.AssertExpectations(t)
+}
+
+func TestAuthorizeOperator_AdminOfAnotherOrganization(t *testing.T) {
+	service, accessTokenManager := newOrganizationAdminHandler(uuid.New())
+
+	_, err := service.authorizeOperator(context.TODO(), "cenas")
+	assert.Equal(t, status.Error(codes.Unauthenticated, "incorrect permissions"), err)
+	accessTokenManager.AssertExpectations(t)
+}
+
+func TestAuthorizeOperator_AdminOfTheDefaultOrganization(t *testing.T) {
+	service, accessTokenManager := newOrganizationAdminHandler(domain.DefaultOrganizationID)
+
+	ctx, err := service.authorizeOperator(context.TODO(), "cenas")
+	assert.Nil(t, err)
+	assert.Equal(t, domain.DefaultOrganizationID, domain.OrganizationFromContext(ctx))
+	accessTokenManager.AssertExpectations(t)
+}
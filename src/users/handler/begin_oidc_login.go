@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Starts a login with an identity provider of the organization, returning where to send the user.
+func (srv UserGRPCHandler) BeginOIDCLogin(ctx context.Context, in *users.BeginOIDCLoginRequest) (*users.OIDCAuthorizationResponse, error) {
+	if in == nil || len(in.Provider) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	authorization, err := srv.oidcService.BeginLogin(ctx, in.GetProvider())
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "identity provider not found")
+	case err != nil:
+		srv.l.Printf("error beginning the login with an identity provider: %v\n", err)
+		return nil, status.Error(codes.Internal, "error logging in with identity provider")
+	}
+
+	return &users.OIDCAuthorizationResponse{
+		AuthorizationUrl: authorization.AuthorizationURL,
+		State:            authorization.State,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBeginOIDCLogin_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+
+	for _, req := range []*users.BeginOIDCLoginRequest{nil, {}} {
+		res, err := service.BeginOIDCLogin(context.TODO(), req)
+		assert.Nil(t, res)
+		assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+	}
+}
+
+func TestBeginOIDCLogin_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"unknown provider": {domain.ErrNotFound, status.Error(codes.NotFound, "identity provider not found")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error logging in with identity provider")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			service := newHandler(nil, nil, nil)
+			oidcService := new(mocks.OIDCService)
+			service.oidcService = oidcService
+			oidcService.On("BeginLogin", mock.Anything, "corporate").Once().Return(domain.OIDCAuthorization{}, c.serviceErr)
+
+			res, err := service.BeginOIDCLogin(context.TODO(), &users.BeginOIDCLoginRequest{Provider: "corporate"})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+		})
+	}
+}
+
+func TestBeginOIDCLogin_Success(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	oidcService := new(mocks.OIDCService)
+	service.oidcService = oidcService
+	oidcService.On("BeginLogin", mock.Anything, "corporate").Once().Return(domain.OIDCAuthorization{
+		AuthorizationURL: "https://idp.example.com/authorize?state=the-state",
+		State:            "the-state",
+	}, nil)
+
+	res, err := service.BeginOIDCLogin(context.TODO(), &users.BeginOIDCLoginRequest{Provider: "corporate"})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.OIDCAuthorizationResponse{
+		AuthorizationUrl: "https://idp.example.com/authorize?state=the-state",
+		State:            "the-state",
+	}, res)
+}
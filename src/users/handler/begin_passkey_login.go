@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"context"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Starts a passkey login, the passkey picked in the authenticator identifies the user.
+func (srv UserGRPCHandler) BeginPasskeyLogin(ctx context.Context, in *users.BeginPasskeyLoginRequest) (*users.PasskeyChallengeResponse, error) {
+	challenge, err := srv.passkeyService.BeginLogin(ctx)
+	if err != nil {
+		srv.l.Printf("error beginning the passkey login: %v\n", err)
+		return nil, status.Error(codes.Internal, "error logging in with passkey")
+	}
+
+	return &users.PasskeyChallengeResponse{
+		SessionId:   challenge.SessionID.String(),
+		OptionsJson: string(challenge.Options),
+	}, nil
+}
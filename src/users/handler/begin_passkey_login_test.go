@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBeginPasskeyLogin_ServiceError(t *testing.T) {
+	passkeyService := new(mocks.PasskeyService)
+	service := newHandler(nil, nil, nil)
+	service.passkeyService = passkeyService
+	passkeyService.On("BeginLogin", mock.Anything).Once().Return(domain.PasskeyChallenge{}, errors.New("boom"))
+
+	res, err := service.BeginPasskeyLogin(context.TODO(), &users.BeginPasskeyLoginRequest{})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Internal, "error logging in with passkey"))
+	passkeyService.AssertExpectations(t)
+}
+
+func TestBeginPasskeyLogin_Success(t *testing.T) {
+	sessionID := uuid.New()
+	passkeyService := new(mocks.PasskeyService)
+	service := newHandler(nil, nil, nil)
+	service.passkeyService = passkeyService
+	passkeyService.On("BeginLogin", mock.Anything).Once().
+		Return(domain.PasskeyChallenge{SessionID: sessionID, Options: []byte(`{"publicKey":{}}`)}, nil)
+
+	res, err := service.BeginPasskeyLogin(context.TODO(), &users.BeginPasskeyLoginRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, sessionID.String(), res.SessionId)
+	assert.Equal(t, `{"publicKey":{}}`, res.OptionsJson)
+	passkeyService.AssertExpectations(t)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"context"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Starts the registration of a passkey for the logged in user.
+func (srv UserGRPCHandler) BeginPasskeyRegistration(ctx context.Context, in *users.BeginPasskeyRegistrationRequest) (*users.PasskeyChallengeResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, userID, err := srv.authenticate(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := srv.userService.GetUserByUUID(ctx, userID)
+	if err != nil {
+		srv.l.Printf("error getting the user registering a passkey: %v\n", err)
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+
+	challenge, err := srv.passkeyService.BeginRegistration(ctx, user)
+	if err != nil {
+		srv.l.Printf("error beginning the passkey registration: %v\n", err)
+		return nil, status.Error(codes.Internal, "error registering passkey")
+	}
+
+	return &users.PasskeyChallengeResponse{
+		SessionId:   challenge.SessionID.String(),
+		OptionsJson: string(challenge.Options),
+	}, nil
+}
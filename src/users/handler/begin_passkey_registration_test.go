@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Handler with a valid access token for the user.
+func newPasskeyRegistrationHandler(userID uuid.UUID) (UserGRPCHandler, *mocks.UserService, *mocks.PasskeyService) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	userService := new(mocks.UserService)
+	passkeyService := new(mocks.PasskeyService)
+
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserIDFromToken", mockToken).Once().Return(userID, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Once().Return(domain.DefaultOrganizationID, nil)
+
+	service := newHandler(accessTokenManager, userService, nil)
+	service.passkeyService = passkeyService
+	return service, userService, passkeyService
+}
+
+func TestBeginPasskeyRegistration_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.BeginPasskeyRegistration(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	res, err = service.BeginPasskeyRegistration(context.TODO(), &users.BeginPasskeyRegistrationRequest{})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestBeginPasskeyRegistration_UserNotFound(t *testing.T) {
+	userID := uuid.New()
+	service, userService, _ := newPasskeyRegistrationHandler(userID)
+	userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(nil, errors.New("boom"))
+
+	res, err := service.BeginPasskeyRegistration(context.TODO(), &users.BeginPasskeyRegistrationRequest{AccessToken: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.NotFound, "user not found"))
+	userService.AssertExpectations(t)
+}
+
+func TestBeginPasskeyRegistration_ServiceError(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	service, userService, passkeyService := newPasskeyRegistrationHandler(user.ID)
+	userService.On("GetUserByUUID", mock.Anything, user.ID).Once().Return(user, nil)
+	passkeyService.On("BeginRegistration", mock.Anything, user).Once().
+		Return(domain.PasskeyChallenge{}, errors.New("boom"))
+
+	res, err := service.BeginPasskeyRegistration(context.TODO(), &users.BeginPasskeyRegistrationRequest{AccessToken: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Internal, "error registering passkey"))
+	passkeyService.AssertExpectations(t)
+}
+
+func TestBeginPasskeyRegistration_Success(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	sessionID := uuid.New()
+	service, userService, passkeyService := newPasskeyRegistrationHandler(user.ID)
+	userService.On("GetUserByUUID", mock.Anything, user.ID).Once().Return(user, nil)
+	passkeyService.On("BeginRegistration", mock.Anything, user).Once().
+		Return(domain.PasskeyChallenge{SessionID: sessionID, Options: []byte(`{"publicKey":{}}`)}, nil)
+
+	res, err := service.BeginPasskeyRegistration(context.TODO(), &users.BeginPasskeyRegistrationRequest{AccessToken: "cenas"})
+	assert.Nil(t, err)
+	assert.Equal(t, sessionID.String(), res.SessionId)
+	assert.Equal(t, `{"publicKey":{}}`, res.OptionsJson)
+	userService.AssertExpectations(t)
+	passkeyService.AssertExpectations(t)
+}
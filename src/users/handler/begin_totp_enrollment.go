@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Starts the TOTP enrollment, returning the secret to add to an authenticator app.
+func (srv UserGRPCHandler) BeginTOTPEnrollment(ctx context.Context, in *users.BeginTOTPEnrollmentRequest) (*users.TOTPEnrollmentResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, user, _, err := srv.enrollingUser(ctx, in.GetAccessToken(), in.GetMfaToken())
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment, err := srv.mfaService.BeginTOTPEnrollment(ctx, user)
+	if errors.Is(err, domain.ErrAlreadyExists) {
+		return nil, status.Error(codes.FailedPrecondition, "mfa already enabled")
+	}
+	if err != nil {
+		srv.l.Printf("error beginning the totp enrollment: %v\n", err)
+		return nil, status.Error(codes.Internal, "error enrolling mfa")
+	}
+
+	return &users.TOTPEnrollmentResponse{
+		Secret:     enrollment.Secret,
+		OtpauthUri: enrollment.URI,
+	}, nil
+}
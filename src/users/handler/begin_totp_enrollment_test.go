@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Handler with a valid access token for the user.
+func newMFAEnrollmentHandler(user *domain.User) (UserGRPCHandler, *mocks.AccessTokenHandler, *mocks.MFAService) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	userService := new(mocks.UserService)
+	mfaService := new(mocks.MFAService)
+
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Maybe().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Maybe().Return(true)
+	accessTokenManager.On("GetUserIDFromToken", mockToken).Maybe().Return(user.ID, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Maybe().Return(domain.DefaultOrganizationID, nil)
+	accessTokenManager.On("GetUserIDFromMFAChallenge", "mfa-token").Maybe().Return(user.ID, domain.DefaultOrganizationID, nil)
+	userService.On("GetUserByUUID", mock.Anything, user.ID).Once().Return(user, nil)
+
+	service := newHandler(accessTokenManager, userService, nil)
+	service.mfaService = mfaService
+	return service, accessTokenManager, mfaService
+}
+
+func TestBeginTOTPEnrollment_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.BeginTOTPEnrollment(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	res, err = service.BeginTOTPEnrollment(context.TODO(), &users.BeginTOTPEnrollmentRequest{})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestBeginTOTPEnrollment_InvalidMFAToken(t *testing.T) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	service := newHandler(accessTokenManager, nil, nil)
+	accessTokenManager.On("GetUserIDFromMFAChallenge", "mfa-token").Once().Return(uuid.Nil, uuid.Nil, domain.ErrInvalidToken)
+
+	res, err := service.BeginTOTPEnrollment(context.TODO(), &users.BeginTOTPEnrollmentRequest{MfaToken: "mfa-token"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+	accessTokenManager.AssertExpectations(t)
+}
+
+func TestBeginTOTPEnrollment_UserNotFound(t *testing.T) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	userService := new(mocks.UserService)
+	service := newHandler(accessTokenManager, userService, nil)
+
+	userID := uuid.New()
+	accessTokenManager.On("GetUserIDFromMFAChallenge", "mfa-token").Once().Return(userID, domain.DefaultOrganizationID, nil)
+	userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(nil, errors.New("boom"))
+
+	res, err := service.BeginTOTPEnrollment(context.TODO(), &users.BeginTOTPEnrollmentRequest{MfaToken: "mfa-token"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.NotFound, "user not found"))
+	userService.AssertExpectations(t)
+}
+
+func TestBeginTOTPEnrollment_ServiceErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantedErr error
+	}{
+		{"already enabled", domain.ErrAlreadyExists, status.Error(codes.FailedPrecondition, "mfa already enabled")},
+		{"unexpected error", errors.New("boom"), status.Error(codes.Internal, "error enrolling mfa")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+			service, _, mfaService := newMFAEnrollmentHandler(user)
+			mfaService.On("BeginTOTPEnrollment", mock.Anything, user).Once().
+				Return(domain.TOTPEnrollment{}, test.err)
+
+			res, err := service.BeginTOTPEnrollment(context.TODO(), &users.BeginTOTPEnrollmentRequest{AccessToken: "cenas"})
+			assert.Nil(t, res)
+			assert.Equal(t, test.wantedErr, err)
+			mfaService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBeginTOTPEnrollment_Success(t *testing.T) {
+	requests := []*users.BeginTOTPEnrollmentRequest{
+		{AccessToken: "cenas"},
+		{MfaToken: "mfa-token"},
+	}
+
+	for _, req := range requests {
+		user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+		service, _, mfaService := newMFAEnrollmentHandler(user)
+		mfaService.On("BeginTOTPEnrollment", mock.Anything, user).Once().
+			Return(domain.TOTPEnrollment{Secret: "SECRET", URI: "otpauth://totp/Tests:alice?secret=SECRET"}, nil)
+
+		res, err := service.BeginTOTPEnrollment(context.TODO(), req)
+		assert.Nil(t, err)
+		assert.Equal(t, &users.TOTPEnrollmentResponse{
+			Secret:     "SECRET",
+			OtpauthUri: "otpauth://totp/Tests:alice?secret=SECRET",
+		}, res)
+		mfaService.AssertExpectations(t)
+	}
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Changes the password of a user, either logged in and giving its current password,
+// or in the middle of a login that requires a password change, which then goes on
+// with the second factor or the tokens.
+func (srv UserGRPCHandler) ChangePassword(ctx context.Context, in *users.ChangePasswordRequest) (*users.TokenResponse, error) {
+	if in == nil || len(in.NewPassword) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	viaLogin := len(in.AccessToken) == 0 && len(in.PasswordChangeToken) > 0
+	if !viaLogin && len(in.CurrentPassword) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	var userID uuid.UUID
+	var err error
+	if viaLogin {
+		var organizationID uuid.UUID
+		userID, organizationID, err = srv.tokenManager.GetUserIDFromPasswordChangeToken(in.GetPasswordChangeToken())
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		ctx = domain.WithOrganization(ctx, organizationID)
+	} else {
+		ctx, userID, err = srv.authenticate(ctx, in.GetAccessToken())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := srv.userService.GetUserByUUID(ctx, userID)
+	if err != nil {
+		srv.l.Printf("error getting the user changing its password: %v\n", err)
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	if !user.IsActive() {
+		return nil, status.Error(codes.PermissionDenied, "user is not active")
+	}
+	// Tokens stop working once the password is changed, so they can't be used to change it again.
+	if viaLogin && !user.PasswordChangeRequired(time.Now()) {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	if !viaLogin {
+		if err = srv.checkCurrentPassword(ctx, user, in.GetCurrentPassword()); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err = srv.userService.ChangePassword(ctx, user.ID, in.GetNewPassword())
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	case errors.Is(err, domain.ErrNotAllowed):
+		return nil, status.Error(codes.InvalidArgument, "new password must be different")
+	case errors.Is(err, domain.ErrNoLocalPassword):
+		return nil, status.Error(codes.FailedPrecondition, "user has no local password")
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "user not found")
+	case err != nil:
+		srv.l.Printf("error changing the password: %v\n", err)
+		return nil, status.Error(codes.Internal, "error changing password")
+	}
+
+	if viaLogin {
+		challenge, err := srv.mfaChallenge(ctx, user)
+		if err != nil || challenge != nil {
+			return challenge, err
+		}
+	}
+
+	token, err := srv.tokenManager.GenerateTokens(ctx, user)
+	if err != nil {
+		srv.l.Printf("error generating tokens on password change: %v\n", err)
+		return nil, status.Error(codes.Internal, "error generating tokens")
+	}
+	if viaLogin {
+		srv.recordLoginEvent(ctx, domain.LoginEventLogin, user, "", "")
+	}
+
+	return &users.TokenResponse{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		User:         userResponse(user),
+	}, nil
+}
+
+// Checks the current password of a logged in user changing it.
+// Wrong passwords count towards the login throttling of the user.
+func (srv UserGRPCHandler) checkCurrentPassword(ctx context.Context, user *domain.User, password string) error {
+	ip := srv.clientIP(ctx)
+	retryAfter, err := srv.loginAttemptService.GetRetryAfter(ctx, user.Username, ip)
+	if err != nil {
+		srv.l.Printf("error checking the login attempts: %v\n", err)
+		return status.Error(codes.Internal, "error checking login attempts")
+	}
+	if retryAfter > 0 {
+		return tooManyAttemptsError(retryAfter)
+	}
+
+	_, err = srv.userService.GetUserByLogin(ctx, domain.GetUserRequest{
+		Username: user.Username,
+		Password: password,
+	})
+	if err != nil {
+		retryAfter, throttleErr := srv.loginAttemptService.RegisterFailure(ctx, user.Username, ip)
+		if throttleErr != nil {
+			srv.l.Printf("error registering failed password check: %v\n", throttleErr)
+		}
+		if retryAfter > 0 {
+			return tooManyAttemptsError(retryAfter)
+		}
+		return status.Error(codes.InvalidArgument, "invalid current password")
+	}
+	return nil
+}
+
+// Gets the response sent instead of the tokens when a user has to change its password to log in.
+// Returns nil when the tokens can be sent right away.
+// Every way of getting the tokens asks for it, so the password can't be skipped with another one.
+func (srv UserGRPCHandler) passwordChangeChallenge(user *domain.User) (*users.TokenResponse, error) {
+	if !user.PasswordChangeRequired(time.Now()) {
+		return nil, nil
+	}
+
+	token, err := srv.tokenManager.GeneratePasswordChangeToken(user)
+	if err != nil {
+		srv.l.Printf("error generating the password change token: %v\n", err)
+		return nil, status.Error(codes.Internal, "error generating tokens")
+	}
+
+	return &users.TokenResponse{
+		PasswordChangeRequired: true,
+		PasswordChangeToken:    token,
+	}, nil
+}
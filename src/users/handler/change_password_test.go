@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Mocks used by the password change handler.
+type changePasswordMocks struct {
+	tokenHandler        *mocks.AccessTokenHandler
+	userService         *mocks.UserService
+	loginAttemptService *mocks.LoginAttemptService
+	mfaService          *mocks.MFAService
+	loginEventService   *mocks.LoginEventService
+}
+
+// Handler for a user that can be found, with a valid access token "cenas"
+// and a valid password change token "password-change-token".
+func newChangePasswordHandler(user *domain.User) (UserGRPCHandler, changePasswordMocks) {
+	m := changePasswordMocks{
+		tokenHandler:        new(mocks.AccessTokenHandler),
+		userService:         new(mocks.UserService),
+		loginAttemptService: new(mocks.LoginAttemptService),
+		mfaService:          new(mocks.MFAService),
+	}
+
+	mockToken := &jwt.Token{Raw: "mock token"}
+	m.tokenHandler.On("ParseJWT", "cenas").Maybe().Return(mockToken, nil)
+	m.tokenHandler.On("IsJWTokenValid", mock.Anything, mockToken).Maybe().Return(true)
+	m.tokenHandler.On("GetUserIDFromToken", mockToken).Maybe().Return(user.ID, nil)
+	m.tokenHandler.On("GetOrganizationIDFromToken", mockToken).Maybe().Return(domain.DefaultOrganizationID, nil)
+	m.tokenHandler.On("GetUserIDFromPasswordChangeToken", "password-change-token").Maybe().Return(user.ID, domain.DefaultOrganizationID, nil)
+	m.userService.On("GetUserByUUID", mock.Anything, user.ID).Maybe().Return(user, nil)
+
+	service := newHandler(m.tokenHandler, m.userService, m.loginAttemptService)
+	service.mfaService = m.mfaService
+	m.loginEventService = withLoginEvents(&service)
+	return service, m
+}
+
+func TestChangePassword_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	requests := []*users.ChangePasswordRequest{
+		nil,
+		{PasswordChangeToken: "password-change-token"},
+		{AccessToken: "cenas", NewPassword: "new-password"},
+	}
+
+	for _, req := range requests {
+		res, err := service.ChangePassword(context.TODO(), req)
+		assert.Nil(t, res)
+		assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+	}
+}
+
+func TestChangePassword_InvalidPasswordChangeToken(t *testing.T) {
+	tokenHandler := new(mocks.AccessTokenHandler)
+	tokenHandler.On("GetUserIDFromPasswordChangeToken", "expired").Once().Return(uuid.Nil, uuid.Nil, domain.ErrInvalidToken)
+	service := newHandler(tokenHandler, nil, nil)
+
+	res, err := service.ChangePassword(context.TODO(), &users.ChangePasswordRequest{
+		PasswordChangeToken: "expired",
+		NewPassword:         "new-password",
+	})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestChangePassword_UserNotActive(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "admin", Status: domain.UserStatusSuspended}
+	service, m := newChangePasswordHandler(user)
+
+	res, err := service.ChangePassword(context.TODO(), &users.ChangePasswordRequest{
+		PasswordChangeToken: "password-change-token",
+		NewPassword:         "new-password",
+	})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.PermissionDenied, "user is not active"))
+	m.userService.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestChangePassword_ViaLoginNoLongerRequired(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "admin", Status: domain.UserStatusActive, PasswordChangedAt: time.Now()}
+	service, m := newChangePasswordHandler(user)
+
+	res, err := service.ChangePassword(context.TODO(), &users.ChangePasswordRequest{
+		PasswordChangeToken: "password-change-token",
+		NewPassword:         "new-password",
+	})
+	assert.Nil(t, res)
+	assert.Equal(t, status.Error(codes.Unauthenticated, "invalid token"), err)
+	m.userService.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything)
+	m.tokenHandler.AssertNotCalled(t, "GenerateTokens", mock.Anything, mock.Anything)
+}
+
+func TestChangePassword_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"same password":     {domain.ErrNotAllowed, status.Error(codes.InvalidArgument, "new password must be different")},
+		"user not found":    {domain.ErrNotFound, status.Error(codes.NotFound, "user not found")},
+		"no local password": {domain.ErrNoLocalPassword, status.Error(codes.FailedPrecondition, "user has no local password")},
+		"unexpected error":  {errors.New("boom"), status.Error(codes.Internal, "error changing password")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			user := &domain.User{ID: uuid.New(), Username: "admin", Status: domain.UserStatusActive, Password: "hash", MustChangePassword: true}
+			service, m := newChangePasswordHandler(user)
+			m.userService.On("ChangePassword", mock.Anything, user.ID, "new-password").Once().Return(nil, c.serviceErr)
+
+			res, err := service.ChangePassword(context.TODO(), &users.ChangePasswordRequest{
+				PasswordChangeToken: "password-change-token",
+				NewPassword:         "new-password",
+			})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			m.userService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestChangePassword_ViaLoginSuccess(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "admin", Status: domain.UserStatusActive, Password: "hash", MustChangePassword: true}
+	changed := &domain.User{ID: user.ID, Username: "admin", Status: domain.UserStatusActive, Version: 2}
+	service, m := newChangePasswordHandler(user)
+	m.userService.On("ChangePassword", mock.Anything, user.ID, "new-password").Once().Return(changed, nil)
+	m.mfaService.On("IsEnrolled", mock.Anything, user.ID).Once().Return(false, nil)
+	m.tokenHandler.On("GenerateTokens", mock.Anything, changed).Once().
+		Return(domain.TokenResponse{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil)
+
+	res, err := service.ChangePassword(context.TODO(), &users.ChangePasswordRequest{
+		PasswordChangeToken: "password-change-token",
+		NewPassword:         "new-password",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.TokenResponse{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+		User:         &users.UserResponse{Id: user.ID.String(), Username: "admin", Status: "active", Version: 2},
+	}, res)
+	m.userService.AssertExpectations(t)
+	m.tokenHandler.AssertExpectations(t)
+	assertLoginEventRecorded(t, m.loginEventService, domain.LoginEventLogin, user.ID, "")
+}
+
+func TestChangePassword_ViaLoginScopedToOrganizationOfToken(t *testing.T) {
+	organizationID := uuid.New()
+	user := &domain.User{ID: uuid.New(), Username: "admin", Status: domain.UserStatusActive, Password: "hash", MustChangePassword: true, OrganizationID: organizationID}
+	inOrganization := mock.MatchedBy(func(ctx context.Context) bool {
+		return domain.OrganizationFromContext(ctx) == organizationID
+	})
+	tokenHandler := new(mocks.AccessTokenHandler)
+	userService := new(mocks.UserService)
+	service := newHandler(tokenHandler, userService, nil)
+
+	tokenHandler.On("GetUserIDFromPasswordChangeToken", "password-change-token").Once().Return(user.ID, organizationID, nil)
+	userService.On("GetUserByUUID", inOrganization, user.ID).Once().Return(user, nil)
+	userService.On("ChangePassword", inOrganization, user.ID, "new-password").Once().Return(nil, domain.ErrNotAllowed)
+
+	res, err := service.ChangePassword(context.TODO(), &users.ChangePasswordRequest{
+		PasswordChangeToken: "password-change-token",
+		NewPassword:         "new-password",
+	})
+	assert.Nil(t, res)
+	assert.Equal(t, status.Error(codes.InvalidArgument, "new password must be different"), err)
+	userService.AssertExpectations(t)
+}
+
+func TestChangePassword_ViaLoginRequiresMFA(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "admin", Status: domain.UserStatusActive, Password: "hash", MustChangePassword: true}
+	service, m := newChangePasswordHandler(user)
+	m.userService.On("ChangePassword", mock.Anything, user.ID, "new-password").Once().Return(user, nil)
+	m.mfaService.On("IsEnrolled", mock.Anything, user.ID).Once().Return(true, nil)
+	m.tokenHandler.On("GenerateMFAChallenge", user).Once().Return("mfa-token", nil)
+
+	res, err := service.ChangePassword(context.TODO(), &users.ChangePasswordRequest{
+		PasswordChangeToken: "password-change-token",
+		NewPassword:         "new-password",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.TokenResponse{MfaRequired: true, MfaToken: "mfa-token"}, res)
+	m.tokenHandler.AssertNotCalled(t, "GenerateTokens", mock.Anything, mock.Anything)
+}
+
+func TestChangePassword_WrongCurrentPassword(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	service, m := newChangePasswordHandler(user)
+	m.loginAttemptService.On("GetRetryAfter", mock.Anything, "alice", "").Once().Return(time.Duration(0), nil)
+	m.userService.On("GetUserByLogin", mock.Anything, domain.GetUserRequest{Username: "alice", Password: "wrong"}).
+		Once().Return(nil, errors.New("mismatched password"))
+	m.loginAttemptService.On("RegisterFailure", mock.Anything, "alice", "").Once().Return(time.Duration(0), nil)
+
+	res, err := service.ChangePassword(context.TODO(), &users.ChangePasswordRequest{
+		AccessToken:     "cenas",
+		CurrentPassword: "wrong",
+		NewPassword:     "new-password",
+	})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid current password"))
+	m.loginAttemptService.AssertExpectations(t)
+	m.userService.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestChangePassword_LoggedInSuccess(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	service, m := newChangePasswordHandler(user)
+	m.loginAttemptService.On("GetRetryAfter", mock.Anything, "alice", "").Once().Return(time.Duration(0), nil)
+	m.userService.On("GetUserByLogin", mock.Anything, domain.GetUserRequest{Username: "alice", Password: "old-password"}).
+		Once().Return(user, nil)
+	m.userService.On("ChangePassword", mock.Anything, user.ID, "new-password").Once().Return(user, nil)
+	m.tokenHandler.On("GenerateTokens", mock.Anything, user).Once().
+		Return(domain.TokenResponse{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil)
+
+	res, err := service.ChangePassword(context.TODO(), &users.ChangePasswordRequest{
+		AccessToken:     "cenas",
+		CurrentPassword: "old-password",
+		NewPassword:     "new-password",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "access-token", res.AccessToken)
+	m.userService.AssertExpectations(t)
+	m.mfaService.AssertNotCalled(t, "IsEnrolled", mock.Anything, mock.Anything)
+	m.loginEventService.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Renames the logged in user, or any user for administrators.
+// The access tokens with the old username stop working, so the user has to refresh them.
+func (srv UserGRPCHandler) ChangeUsername(ctx context.Context, in *users.ChangeUsernameRequest) (*users.UserResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, userID, _, err := srv.targetUser(ctx, in.AccessToken, in.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Version <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing version")
+	}
+
+	user, err := srv.userService.ChangeUsername(ctx, userID, int(in.Version), in.Username)
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid username")
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, domain.ErrAlreadyExists):
+		return nil, status.Error(codes.AlreadyExists, "username already taken")
+	case errors.Is(err, domain.ErrVersionConflict):
+		return nil, errUserVersionConflict
+	case err != nil:
+		srv.l.Printf("error changing the username: %v\n", err)
+		return nil, status.Error(codes.Internal, "error changing username")
+	}
+
+	return userResponse(user), nil
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestChangeUsername_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.ChangeUsername(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestChangeUsername_OtherUserNeedsAdmin(t *testing.T) {
+	service, userService, _ := newAttributesHandler(uuid.New(), "user")
+
+	res, err := service.ChangeUsername(context.TODO(), &users.ChangeUsernameRequest{AccessToken: "cenas", UserId: uuid.NewString(), Version: 1, Username: "bob"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "incorrect permissions"))
+	userService.AssertNotCalled(t, "ChangeUsername", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestChangeUsername_MissingVersion(t *testing.T) {
+	service, userService, _ := newAttributesHandler(uuid.New(), "user")
+
+	res, err := service.ChangeUsername(context.TODO(), &users.ChangeUsernameRequest{AccessToken: "cenas", Username: "bob"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "missing version"))
+	userService.AssertNotCalled(t, "ChangeUsername", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestChangeUsername_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"invalid username": {domain.ErrBadParamInput, status.Error(codes.InvalidArgument, "invalid username")},
+		"user not found":   {domain.ErrNotFound, status.Error(codes.NotFound, "user not found")},
+		"username taken":   {domain.ErrAlreadyExists, status.Error(codes.AlreadyExists, "username already taken")},
+		"version conflict": {domain.ErrVersionConflict, errUserVersionConflict},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error changing username")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			userID := uuid.New()
+			service, userService, _ := newAttributesHandler(uuid.New(), "admin")
+			userService.On("ChangeUsername", mock.Anything, userID, 1, "bob").Once().Return(nil, c.serviceErr)
+
+			res, err := service.ChangeUsername(context.TODO(), &users.ChangeUsernameRequest{AccessToken: "cenas", UserId: userID.String(), Version: 1, Username: "bob"})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			userService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestChangeUsername_Success(t *testing.T) {
+	userID, roleID := uuid.New(), uuid.New()
+	service, userService, _ := newAttributesHandler(userID, "user")
+	userService.On("ChangeUsername", mock.Anything, userID, 1, "bob").Once().Return(&domain.User{
+		ID:       userID,
+		Username: "bob",
+		Version:  2,
+		RoleId:   roleID,
+		Role:     &domain.Role{ID: roleID, RoleSlug: "user", RoleLabel: "User"},
+	}, nil)
+
+	res, err := service.ChangeUsername(context.TODO(), &users.ChangeUsernameRequest{AccessToken: "cenas", Version: 1, Username: "bob"})
+	assert.Nil(t, err)
+	assert.Equal(t, userID.String(), res.Id)
+	assert.Equal(t, "bob", res.Username)
+	assert.Equal(t, int64(2), res.Version)
+	userService.AssertExpectations(t)
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"context"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Clears the failed logins and lockout of a username and/or client IP.
+// Only for administrators.
+func (srv UserGRPCHandler) ClearLoginLockout(ctx context.Context, in *users.ClearLoginLockoutRequest) (*users.EmptyResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(in.Username) == 0 && len(in.Ip) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	if err := srv.loginAttemptService.ClearLockout(ctx, in.GetUsername(), in.GetIp()); err != nil {
+		srv.l.Printf("error clearing login lockout: %v\n", err)
+		return nil, status.Error(codes.Internal, "error clearing lockout")
+	}
+
+	return &users.EmptyResponse{}, nil
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClearLoginLockout_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.ClearLoginLockout(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	res, err = service.ClearLoginLockout(context.TODO(), &users.ClearLoginLockoutRequest{})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestClearLoginLockout_UserDoesntHaveProperRole(t *testing.T) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	service := newHandler(accessTokenManager, nil, nil)
+
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"user"}, nil)
+
+	res, err := service.ClearLoginLockout(context.TODO(), &users.ClearLoginLockoutRequest{
+		AccessToken: "cenas",
+		Username:    "admin",
+	})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "incorrect permissions"))
+	accessTokenManager.AssertExpectations(t)
+}
+
+func TestClearLoginLockout_InvalidRequestInParameters(t *testing.T) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	service := newHandler(accessTokenManager, nil, nil)
+
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"admin"}, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Once().Return(domain.DefaultOrganizationID, nil)
+
+	res, err := service.ClearLoginLockout(context.TODO(), &users.ClearLoginLockoutRequest{
+		AccessToken: "cenas",
+	})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+	accessTokenManager.AssertExpectations(t)
+}
+
+func TestClearLoginLockout_ErrorClearing(t *testing.T) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	loginAttemptService := new(mocks.LoginAttemptService)
+	service := newHandler(accessTokenManager, nil, loginAttemptService)
+
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"admin"}, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Once().Return(domain.DefaultOrganizationID, nil)
+	loginAttemptService.On("ClearLockout", mock.Anything, "admin", "10.0.0.1").Once().Return(errors.New("boom"))
+
+	res, err := service.ClearLoginLockout(context.TODO(), &users.ClearLoginLockoutRequest{
+		AccessToken: "cenas",
+		Username:    "admin",
+		Ip:          "10.0.0.1",
+	})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Internal, "error clearing lockout"))
+	accessTokenManager.AssertExpectations(t)
+	loginAttemptService.AssertExpectations(t)
+}
+
+func TestClearLoginLockout_Success(t *testing.T) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	loginAttemptService := new(mocks.LoginAttemptService)
+	service := newHandler(accessTokenManager, nil, loginAttemptService)
+
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"admin"}, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Once().Return(domain.DefaultOrganizationID, nil)
+	loginAttemptService.On("ClearLockout", mock.Anything, "admin", "").Once().Return(nil)
+
+	res, err := service.ClearLoginLockout(context.TODO(), &users.ClearLoginLockoutRequest{
+		AccessToken: "cenas",
+		Username:    "admin",
+	})
+	assert.Equal(t, res, &users.EmptyResponse{})
+	assert.Nil(t, err)
+	accessTokenManager.AssertExpectations(t)
+	loginAttemptService.AssertExpectations(t)
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Parses the addresses of the proxies allowed to forward the IP of the clients, as IPs or CIDRs.
+func ParseTrustedProxies(values []string) ([]*net.IPNet, error) {
+	result := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", value)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			result = append(result, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+		}
+		result = append(result, network)
+	}
+	return result, nil
+}
+
+// Gets the IP of the client making the request.
+// Requests coming through a trusted proxy, like the API Gateway, are from the last IP of the
+// x-forwarded-for metadata that isn't of a trusted proxy, or else from x-real-ip.
+// Otherwise the forwarded IPs can be made up by the clients, so the address of the gRPC peer is used.
+func (srv UserGRPCHandler) clientIP(ctx context.Context) string {
+	ip := peerIP(ctx)
+	if !srv.isTrustedProxy(ip) {
+		return ip
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ip
+	}
+
+	var forwarded []string
+	for _, value := range md.Get("x-forwarded-for") {
+		forwarded = append(forwarded, strings.Split(value, ",")...)
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		if !srv.isTrustedProxy(hop) {
+			return hop
+		}
+	}
+
+	if realIP := md.Get("x-real-ip"); len(realIP) > 0 && net.ParseIP(strings.TrimSpace(realIP[0])) != nil {
+		return strings.TrimSpace(realIP[0])
+	}
+	return ip
+}
+
+// Returns if an IP is of one of the trusted proxies.
+func (srv UserGRPCHandler) isTrustedProxy(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range srv.trustedProxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Gets the IP of the gRPC peer making the request.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// Gets the user agent of the client making the request.
+// The API Gateway forwards the one of the original client, otherwise
+// the one of the gRPC client is used.
+func userAgent(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, key := range []string{"x-forwarded-user-agent", "user-agent"} {
+		if values := md.Get(key); len(values) > 0 && len(values[0]) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
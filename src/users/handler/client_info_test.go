@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Context of a request from a gRPC peer, with some metadata.
+func peerContext(ip string, pairs ...string) context.Context {
+	ctx := peer.NewContext(context.TODO(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 5432},
+	})
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(pairs...))
+}
+
+func newProxiedHandler(t *testing.T, proxies ...string) UserGRPCHandler {
+	trustedProxies, err := ParseTrustedProxies(proxies)
+	assert.Nil(t, err)
+	service := newHandler(nil, nil, nil)
+	service.trustedProxies = trustedProxies
+	return service
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.2", "192.168.0.0/16", "::1"})
+	assert.Nil(t, err)
+	assert.Len(t, proxies, 3)
+	assert.True(t, proxies[0].Contains(net.ParseIP("10.0.0.2")))
+	assert.False(t, proxies[0].Contains(net.ParseIP("10.0.0.3")))
+	assert.True(t, proxies[1].Contains(net.ParseIP("192.168.4.1")))
+	assert.True(t, proxies[2].Contains(net.ParseIP("::1")))
+
+	for _, invalid := range []string{"gateway", "10.0.0.0/33"} {
+		_, err = ParseTrustedProxies([]string{invalid})
+		assert.Error(t, err)
+	}
+}
+
+func TestClientIP_NoInformation(t *testing.T) {
+	assert.Equal(t, "", newHandler(nil, nil, nil).clientIP(context.TODO()))
+}
+
+func TestClientIP_FromPeer(t *testing.T) {
+	assert.Equal(t, "10.0.0.2", newHandler(nil, nil, nil).clientIP(peerContext("10.0.0.2")))
+}
+
+func TestClientIP_IgnoresForwardedMetadataOfUntrustedPeers(t *testing.T) {
+	service := newProxiedHandler(t, "10.0.0.1")
+
+	ctx := peerContext("10.0.0.2", "x-forwarded-for", "203.0.113.7", "x-real-ip", "203.0.113.8")
+	assert.Equal(t, "10.0.0.2", service.clientIP(ctx))
+}
+
+func TestClientIP_FromForwardedMetadataOfTrustedProxies(t *testing.T) {
+	service := newProxiedHandler(t, "10.0.0.0/24")
+
+	// The client may prepend made up IPs, so the last one not of a proxy is the one of the client.
+	forwardedCtx := peerContext("10.0.0.2", "x-forwarded-for", "198.51.100.1, 203.0.113.7, 10.0.0.3")
+	assert.Equal(t, "203.0.113.7", service.clientIP(forwardedCtx))
+
+	realIPCtx := peerContext("10.0.0.2", "x-real-ip", "203.0.113.8")
+	assert.Equal(t, "203.0.113.8", service.clientIP(realIPCtx))
+
+	invalidCtx := peerContext("10.0.0.2", "x-forwarded-for", "not an ip")
+	assert.Equal(t, "10.0.0.2", service.clientIP(invalidCtx))
+}
+
+func TestUserAgent(t *testing.T) {
+	assert.Equal(t, "", userAgent(context.TODO()))
+
+	ctx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs("user-agent", "grpc-go/1.56.3"))
+	assert.Equal(t, "grpc-go/1.56.3", userAgent(ctx))
+
+	forwardedCtx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs(
+		"user-agent", "grpc-go/1.56.3",
+		"x-forwarded-user-agent", "Mozilla/5.0",
+	))
+	assert.Equal(t, "Mozilla/5.0", userAgent(forwardedCtx))
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Confirms the TOTP enrollment with a first code, returning the recovery codes.
+// When done in the middle of a login, the login is completed as well.
+func (srv UserGRPCHandler) ConfirmTOTPEnrollment(ctx context.Context, in *users.ConfirmTOTPEnrollmentRequest) (*users.ConfirmTOTPEnrollmentResponse, error) {
+	if in == nil || len(in.Code) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx, user, viaChallenge, err := srv.enrollingUser(ctx, in.GetAccessToken(), in.GetMfaToken())
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := srv.mfaService.ConfirmTOTPEnrollment(ctx, user, in.GetCode())
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.FailedPrecondition, "no pending mfa enrollment")
+	case errors.Is(err, domain.ErrAlreadyExists):
+		return nil, status.Error(codes.FailedPrecondition, "mfa already enabled")
+	case errors.Is(err, domain.ErrInvalidToken):
+		return nil, status.Error(codes.InvalidArgument, "invalid code")
+	case err != nil:
+		srv.l.Printf("error confirming the totp enrollment: %v\n", err)
+		return nil, status.Error(codes.Internal, "error enrolling mfa")
+	}
+
+	result := &users.ConfirmTOTPEnrollmentResponse{RecoveryCodes: recoveryCodes}
+	if !viaChallenge {
+		return result, nil
+	}
+
+	token, err := srv.tokenManager.GenerateTokens(ctx, user)
+	if err != nil {
+		srv.l.Printf("error generating tokens on mfa enrollment: %v\n", err)
+		return nil, status.Error(codes.Internal, "error generating tokens")
+	}
+
+	result.Tokens = &users.TokenResponse{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		User:         userResponse(user),
+	}
+	return result, nil
+}
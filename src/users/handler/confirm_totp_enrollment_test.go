@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConfirmTOTPEnrollment_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	invalidRequests := []*users.ConfirmTOTPEnrollmentRequest{
+		nil,
+		{AccessToken: "cenas"},
+	}
+
+	for _, req := range invalidRequests {
+		res, err := service.ConfirmTOTPEnrollment(context.TODO(), req)
+		assert.Nil(t, res)
+		assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+	}
+
+	res, err := service.ConfirmTOTPEnrollment(context.TODO(), &users.ConfirmTOTPEnrollmentRequest{Code: "123456"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestConfirmTOTPEnrollment_ServiceErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantedErr error
+	}{
+		{"no pending enrollment", domain.ErrNotFound, status.Error(codes.FailedPrecondition, "no pending mfa enrollment")},
+		{"already enabled", domain.ErrAlreadyExists, status.Error(codes.FailedPrecondition, "mfa already enabled")},
+		{"wrong code", domain.ErrInvalidToken, status.Error(codes.InvalidArgument, "invalid code")},
+		{"unexpected error", errors.New("boom"), status.Error(codes.Internal, "error enrolling mfa")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+			service, _, mfaService := newMFAEnrollmentHandler(user)
+			mfaService.On("ConfirmTOTPEnrollment", mock.Anything, user, "123456").Once().Return(nil, test.err)
+
+			res, err := service.ConfirmTOTPEnrollment(context.TODO(), &users.ConfirmTOTPEnrollmentRequest{
+				AccessToken: "cenas",
+				Code:        "123456",
+			})
+			assert.Nil(t, res)
+			assert.Equal(t, test.wantedErr, err)
+			mfaService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConfirmTOTPEnrollment_LoggedIn(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	service, accessTokenManager, mfaService := newMFAEnrollmentHandler(user)
+	mfaService.On("ConfirmTOTPEnrollment", mock.Anything, user, "123456").Once().
+		Return([]string{"code-1", "code-2"}, nil)
+
+	res, err := service.ConfirmTOTPEnrollment(context.TODO(), &users.ConfirmTOTPEnrollmentRequest{
+		AccessToken: "cenas",
+		Code:        "123456",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.ConfirmTOTPEnrollmentResponse{RecoveryCodes: []string{"code-1", "code-2"}}, res)
+	accessTokenManager.AssertNotCalled(t, "GenerateTokens", mock.Anything, mock.Anything)
+	mfaService.AssertExpectations(t)
+}
+
+func TestConfirmTOTPEnrollment_DuringLogin(t *testing.T) {
+	roleID := uuid.New()
+	user := &domain.User{
+		ID:       uuid.New(),
+		Username: "alice",
+		RoleId:   roleID,
+		Status:   domain.UserStatusActive,
+		Role:     &domain.Role{ID: roleID, RoleSlug: "admin", RoleLabel: "Administrator", RequiresMFA: true},
+	}
+	service, accessTokenManager, mfaService := newMFAEnrollmentHandler(user)
+	mfaService.On("ConfirmTOTPEnrollment", mock.Anything, user, "123456").Once().
+		Return([]string{"code-1", "code-2"}, nil)
+	accessTokenManager.On("GenerateTokens", mock.Anything, user).Once().
+		Return(domain.TokenResponse{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil)
+
+	res, err := service.ConfirmTOTPEnrollment(context.TODO(), &users.ConfirmTOTPEnrollmentRequest{
+		MfaToken: "mfa-token",
+		Code:     "123456",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.ConfirmTOTPEnrollmentResponse{
+		RecoveryCodes: []string{"code-1", "code-2"},
+		Tokens: &users.TokenResponse{
+			AccessToken:  "access-token",
+			RefreshToken: "refresh-token",
+			User: &users.UserResponse{
+				Id:       user.ID.String(),
+				Username: "alice",
+				Status:   "active",
+				Role: &users.UserResponse_RoleResponse{
+					Id:        roleID.String(),
+					RoleLabel: "Administrator",
+					RoleSlug:  "admin",
+				},
+			},
+		},
+	}, res)
+	accessTokenManager.AssertExpectations(t)
+	mfaService.AssertExpectations(t)
+}
+
+func TestConfirmTOTPEnrollment_ErrorGeneratingTokens(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	service, accessTokenManager, mfaService := newMFAEnrollmentHandler(user)
+	mfaService.On("ConfirmTOTPEnrollment", mock.Anything, user, "123456").Once().
+		Return([]string{"code-1"}, nil)
+	accessTokenManager.On("GenerateTokens", mock.Anything, user).Once().
+		Return(domain.TokenResponse{}, errors.New("boom"))
+
+	res, err := service.ConfirmTOTPEnrollment(context.TODO(), &users.ConfirmTOTPEnrollmentRequest{
+		MfaToken: "mfa-token",
+		Code:     "123456",
+	})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Internal, "error generating tokens"))
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Creates an organization along with its first administrator.
+// Only for administrators of the default organization.
+func (srv UserGRPCHandler) CreateOrganization(ctx context.Context, in *users.CreateOrganizationRequest) (*users.OrganizationResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeOperator(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	organization, admin, err := srv.organizationService.Create(
+		ctx,
+		domain.Organization{Slug: in.Slug, Name: in.Name},
+		domain.StoreUserRequest{Username: in.AdminUsername, Password: in.AdminPassword, Email: in.AdminEmail},
+	)
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	case errors.Is(err, domain.ErrAlreadyExists):
+		return nil, status.Error(codes.AlreadyExists, "organization slug already in use")
+	case err != nil:
+		srv.l.Printf("error creating the organization: %v\n", err)
+		return nil, status.Error(codes.Internal, "error creating organization")
+	}
+
+	return &users.OrganizationResponse{
+		Id:    organization.ID.String(),
+		Slug:  organization.Slug,
+		Name:  organization.Name,
+		Admin: userResponse(admin),
+	}, nil
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newCreateOrganizationHandler() (UserGRPCHandler, *mocks.OrganizationService) {
+	organizationService := new(mocks.OrganizationService)
+	service, _ := newOrganizationAdminHandler(domain.DefaultOrganizationID)
+	service.organizationService = organizationService
+	return service, organizationService
+}
+
+func validCreateOrganizationRequest() *users.CreateOrganizationRequest {
+	return &users.CreateOrganizationRequest{
+		AccessToken:   "cenas",
+		Slug:          "acme",
+		Name:          "Acme",
+		AdminUsername: "wile",
+		AdminPassword: "password",
+		AdminEmail:    "wile@acme.com",
+	}
+}
+
+func TestCreateOrganization_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.CreateOrganization(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestCreateOrganization_AdminOfAnotherOrganization(t *testing.T) {
+	organizationService := new(mocks.OrganizationService)
+	service, _ := newOrganizationAdminHandler(uuid.New())
+	service.organizationService = organizationService
+
+	res, err := service.CreateOrganization(context.TODO(), validCreateOrganizationRequest())
+	assert.Nil(t, res)
+	assert.Equal(t, status.Error(codes.Unauthenticated, "incorrect permissions"), err)
+	organizationService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateOrganization_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"invalid organization": {domain.ErrBadParamInput, status.Error(codes.InvalidArgument, "invalid request")},
+		"slug in use":          {domain.ErrAlreadyExists, status.Error(codes.AlreadyExists, "organization slug already in use")},
+		"unexpected error":     {errors.New("boom"), status.Error(codes.Internal, "error creating organization")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, organizationService := newCreateOrganizationHandler()
+			organizationService.On("Create", mock.Anything, mock.Anything, mock.Anything).Once().Return(domain.Organization{}, nil, c.serviceErr)
+
+			res, err := service.CreateOrganization(context.TODO(), validCreateOrganizationRequest())
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			organizationService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateOrganization_Success(t *testing.T) {
+	organization := domain.Organization{ID: uuid.New(), Slug: "acme", Name: "Acme"}
+	admin := &domain.User{ID: uuid.New(), Username: "wile", Email: "wile@acme.com", OrganizationID: organization.ID}
+
+	service, organizationService := newCreateOrganizationHandler()
+	organizationService.On(
+		"Create",
+		mock.Anything,
+		domain.Organization{Slug: "acme", Name: "Acme"},
+		domain.StoreUserRequest{Username: "wile", Password: "password", Email: "wile@acme.com"},
+	).Once().Return(organization, admin, nil)
+
+	res, err := service.CreateOrganization(context.TODO(), validCreateOrganizationRequest())
+	assert.Nil(t, err)
+	assert.Equal(t, organization.ID.String(), res.Id)
+	assert.Equal(t, "acme", res.Slug)
+	assert.Equal(t, "Acme", res.Name)
+	assert.Equal(t, admin.ID.String(), res.Admin.Id)
+	organizationService.AssertExpectations(t)
+}
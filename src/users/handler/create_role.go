@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Creates a role in the organization.
+// Only for administrators.
+func (srv UserGRPCHandler) CreateRole(ctx context.Context, in *users.CreateRoleRequest) (*users.RoleResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := srv.roleService.Store(ctx, domain.Role{
+		RoleSlug:           in.RoleSlug,
+		RoleLabel:          in.RoleLabel,
+		RequiresMFA:        in.RequiresMfa,
+		PasswordMaxAgeDays: int(in.PasswordMaxAgeDays),
+	})
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid role")
+	case errors.Is(err, domain.ErrAlreadyExists):
+		return nil, status.Error(codes.AlreadyExists, "role already exists")
+	case err != nil:
+		srv.l.Printf("error creating the role: %v\n", err)
+		return nil, status.Error(codes.Internal, "error creating role")
+	}
+
+	return roleResponse(role), nil
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateRole_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.CreateRole(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestCreateRole_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"invalid role":     {domain.ErrBadParamInput, status.Error(codes.InvalidArgument, "invalid role")},
+		"already exists":   {domain.ErrAlreadyExists, status.Error(codes.AlreadyExists, "role already exists")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error creating role")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, roleService := newRoleHandler()
+			roleService.On("Store", mock.Anything, mock.Anything).Once().Return(domain.Role{}, c.serviceErr)
+
+			res, err := service.CreateRole(context.TODO(), &users.CreateRoleRequest{AccessToken: "cenas", RoleSlug: "auditor"})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+		})
+	}
+}
+
+func TestCreateRole_Success(t *testing.T) {
+	role := domain.Role{ID: uuid.New(), RoleSlug: "auditor", RoleLabel: "Auditor", RequiresMFA: true, PasswordMaxAgeDays: 30, Version: 1}
+	service, roleService := newRoleHandler()
+	roleService.On("Store", mock.Anything, domain.Role{
+		RoleSlug:           "auditor",
+		RoleLabel:          "Auditor",
+		RequiresMFA:        true,
+		PasswordMaxAgeDays: 30,
+	}).Once().Return(role, nil)
+
+	res, err := service.CreateRole(context.TODO(), &users.CreateRoleRequest{
+		AccessToken:        "cenas",
+		RoleSlug:           "auditor",
+		RoleLabel:          "Auditor",
+		RequiresMfa:        true,
+		PasswordMaxAgeDays: 30,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, roleResponse(role), res)
+	roleService.AssertExpectations(t)
+}
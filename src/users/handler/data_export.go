@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Size of the chunks the data exports are streamed in.
+const dataExportChunkSize = 32 * 1024
+
+// Stream of data export chunks, shared by the user and administrator exports.
+type dataExportStream interface {
+	Context() context.Context
+	Send(*users.DataExportChunk) error
+}
+
+// Exports the data of a user and streams the document in chunks.
+func (srv UserGRPCHandler) streamDataExport(ctx context.Context, stream dataExportStream, userID uuid.UUID, requestedBy uuid.UUID) error {
+	data, err := srv.dataExportService.Export(ctx, userID, requestedBy)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case err != nil:
+		srv.l.Printf("error exporting the user data: %v\n", err)
+		return status.Error(codes.Internal, "error exporting data")
+	}
+
+	for start := 0; start < len(data); start += dataExportChunkSize {
+		end := start + dataExportChunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		if err = stream.Send(&users.DataExportChunk{Data: data[start:end]}); err != nil {
+			srv.l.Printf("error sending the data export: %v\n", err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Deletes the definition of a custom attribute, removing it from every user.
+// Only for administrators.
+func (srv UserGRPCHandler) DeleteAttributeDefinition(ctx context.Context, in *users.DeleteAttributeDefinitionRequest) (*users.EmptyResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeOperator(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	err = srv.attributeService.DeleteDefinition(ctx, in.Name)
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "attribute not found")
+	case err != nil:
+		srv.l.Printf("error deleting the attribute definition: %v\n", err)
+		return nil, status.Error(codes.Internal, "error deleting attribute definition")
+	}
+
+	return &users.EmptyResponse{}, nil
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDeleteAttributeDefinition_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.DeleteAttributeDefinition(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestDeleteAttributeDefinition_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"invalid name":     {domain.ErrBadParamInput, status.Error(codes.InvalidArgument, "invalid request")},
+		"not found":        {domain.ErrNotFound, status.Error(codes.NotFound, "attribute not found")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error deleting attribute definition")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			attributeService := new(mocks.AttributeService)
+			service, _ := newAdminHandler(nil)
+			service.attributeService = attributeService
+			attributeService.On("DeleteDefinition", mock.Anything, "locale").Once().Return(c.serviceErr)
+
+			res, err := service.DeleteAttributeDefinition(context.TODO(), &users.DeleteAttributeDefinitionRequest{AccessToken: "cenas", Name: "locale"})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			attributeService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteAttributeDefinition_Success(t *testing.T) {
+	attributeService := new(mocks.AttributeService)
+	service, accessTokenManager := newAdminHandler(nil)
+	service.attributeService = attributeService
+	attributeService.On("DeleteDefinition", mock.Anything, "locale").Once().Return(nil)
+
+	res, err := service.DeleteAttributeDefinition(context.TODO(), &users.DeleteAttributeDefinitionRequest{AccessToken: "cenas", Name: "locale"})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.EmptyResponse{}, res)
+	accessTokenManager.AssertExpectations(t)
+	attributeService.AssertExpectations(t)
+}
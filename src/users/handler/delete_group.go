@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Deletes a group. Its members lose its roles on their next refresh.
+// Only for administrators.
+func (srv UserGRPCHandler) DeleteGroup(ctx context.Context, in *users.DeleteGroupRequest) (*users.EmptyResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	groupID, err := uuid.Parse(in.Id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	err = srv.groupService.Delete(ctx, groupID)
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "group not found")
+	case err != nil:
+		srv.l.Printf("error deleting the group: %v\n", err)
+		return nil, status.Error(codes.Internal, "error deleting group")
+	}
+
+	return &users.EmptyResponse{}, nil
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Deletes an identity provider of the organization. Its users stay, but can't log in with it anymore.
+// Only for administrators.
+func (srv UserGRPCHandler) DeleteIdentityProvider(ctx context.Context, in *users.DeleteIdentityProviderRequest) (*users.EmptyResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	err = srv.oidcService.DeleteProvider(ctx, in.Slug)
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "identity provider not found")
+	case err != nil:
+		srv.l.Printf("error deleting the identity provider: %v\n", err)
+		return nil, status.Error(codes.Internal, "error deleting identity provider")
+	}
+
+	return &users.EmptyResponse{}, nil
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDeleteIdentityProvider_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.DeleteIdentityProvider(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestDeleteIdentityProvider_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"no slug":          {domain.ErrBadParamInput, status.Error(codes.InvalidArgument, "invalid request")},
+		"not found":        {domain.ErrNotFound, status.Error(codes.NotFound, "identity provider not found")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error deleting identity provider")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, oidcService := newIdentityProviderHandler()
+			oidcService.On("DeleteProvider", mock.Anything, "corporate").Once().Return(c.serviceErr)
+
+			res, err := service.DeleteIdentityProvider(context.TODO(), &users.DeleteIdentityProviderRequest{AccessToken: "cenas", Slug: "corporate"})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+		})
+	}
+}
+
+func TestDeleteIdentityProvider_Success(t *testing.T) {
+	service, oidcService := newIdentityProviderHandler()
+	oidcService.On("DeleteProvider", mock.Anything, "corporate").Once().Return(nil)
+
+	res, err := service.DeleteIdentityProvider(context.TODO(), &users.DeleteIdentityProviderRequest{AccessToken: "cenas", Slug: "corporate"})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.EmptyResponse{}, res)
+	oidcService.AssertExpectations(t)
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Deletes a role no user or group holds anymore. The default roles can't be deleted.
+// Only for administrators.
+func (srv UserGRPCHandler) DeleteRole(ctx context.Context, in *users.DeleteRoleRequest) (*users.EmptyResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	roleID, err := uuid.Parse(in.Id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+	if in.Version <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing version")
+	}
+
+	err = srv.roleService.Delete(ctx, roleID, int(in.Version))
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "role not found")
+	case errors.Is(err, domain.ErrNotAllowed):
+		return nil, status.Error(codes.FailedPrecondition, "default roles can't be deleted")
+	case errors.Is(err, domain.ErrRoleInUse):
+		return nil, status.Error(codes.FailedPrecondition, "role is held by users or groups")
+	case errors.Is(err, domain.ErrVersionConflict):
+		return nil, errRoleVersionConflict
+	case err != nil:
+		srv.l.Printf("error deleting the role: %v\n", err)
+		return nil, status.Error(codes.Internal, "error deleting role")
+	}
+
+	return &users.EmptyResponse{}, nil
+}
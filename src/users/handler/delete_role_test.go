@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDeleteRole_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.DeleteRole(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	service, _ = newRoleHandler()
+	res, err = service.DeleteRole(context.TODO(), &users.DeleteRoleRequest{AccessToken: "cenas", Id: "nope", Version: 1})
+	assert.Nil(t, res)
+	assert.Equal(t, status.Error(codes.InvalidArgument, "invalid request"), err)
+
+	service, _ = newRoleHandler()
+	res, err = service.DeleteRole(context.TODO(), &users.DeleteRoleRequest{AccessToken: "cenas", Id: uuid.NewString()})
+	assert.Nil(t, res)
+	assert.Equal(t, status.Error(codes.InvalidArgument, "missing version"), err)
+}
+
+func TestDeleteRole_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"not found":        {domain.ErrNotFound, status.Error(codes.NotFound, "role not found")},
+		"default role":     {domain.ErrNotAllowed, status.Error(codes.FailedPrecondition, "default roles can't be deleted")},
+		"in use":           {domain.ErrRoleInUse, status.Error(codes.FailedPrecondition, "role is held by users or groups")},
+		"version conflict": {domain.ErrVersionConflict, errRoleVersionConflict},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error deleting role")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			id := uuid.New()
+			service, roleService := newRoleHandler()
+			roleService.On("Delete", mock.Anything, id, 2).Once().Return(c.serviceErr)
+
+			res, err := service.DeleteRole(context.TODO(), &users.DeleteRoleRequest{AccessToken: "cenas", Id: id.String(), Version: 2})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+		})
+	}
+}
+
+func TestDeleteRole_Success(t *testing.T) {
+	id := uuid.New()
+	service, roleService := newRoleHandler()
+	roleService.On("Delete", mock.Anything, id, 2).Once().Return(nil)
+
+	res, err := service.DeleteRole(context.TODO(), &users.DeleteRoleRequest{AccessToken: "cenas", Id: id.String(), Version: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.EmptyResponse{}, res)
+	roleService.AssertExpectations(t)
+}
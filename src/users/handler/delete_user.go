@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Deletes a user, ending their session. It can be restored until it's purged.
+// Only for administrators.
+func (srv UserGRPCHandler) DeleteUser(ctx context.Context, in *users.DeleteUserRequest) (*users.EmptyResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(in.UserId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+	if in.Version <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing version")
+	}
+
+	err = srv.userDeletionService.Delete(ctx, userID, int(in.Version))
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, domain.ErrVersionConflict):
+		return nil, errUserVersionConflict
+	case err != nil:
+		srv.l.Printf("error deleting the user: %v\n", err)
+		return nil, status.Error(codes.Internal, "error deleting user")
+	}
+
+	return &users.EmptyResponse{}, nil
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDeleteUser_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.DeleteUser(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	res, err = service.DeleteUser(context.TODO(), &users.DeleteUserRequest{})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestDeleteUser_InvalidUserID(t *testing.T) {
+	service, accessTokenManager := newAdminHandler(nil)
+
+	res, err := service.DeleteUser(context.TODO(), &users.DeleteUserRequest{AccessToken: "cenas", UserId: "cenas", Version: 1})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+	accessTokenManager.AssertExpectations(t)
+}
+
+func TestDeleteUser_MissingVersion(t *testing.T) {
+	service, accessTokenManager := newAdminHandler(nil)
+
+	res, err := service.DeleteUser(context.TODO(), &users.DeleteUserRequest{AccessToken: "cenas", UserId: uuid.NewString()})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "missing version"))
+	accessTokenManager.AssertExpectations(t)
+}
+
+func TestDeleteUser_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"user not found":   {domain.ErrNotFound, status.Error(codes.NotFound, "user not found")},
+		"version conflict": {domain.ErrVersionConflict, errUserVersionConflict},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error deleting user")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			userID := uuid.New()
+			userDeletionService := new(mocks.UserDeletionService)
+			service, _ := newAdminHandler(nil)
+			service.userDeletionService = userDeletionService
+			userDeletionService.On("Delete", mock.Anything, userID, 1).Once().Return(c.serviceErr)
+
+			res, err := service.DeleteUser(context.TODO(), &users.DeleteUserRequest{AccessToken: "cenas", UserId: userID.String(), Version: 1})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			userDeletionService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteUser_Success(t *testing.T) {
+	userID := uuid.New()
+	userDeletionService := new(mocks.UserDeletionService)
+	service, accessTokenManager := newAdminHandler(nil)
+	service.userDeletionService = userDeletionService
+	userDeletionService.On("Delete", mock.Anything, userID, 1).Once().Return(nil)
+
+	res, err := service.DeleteUser(context.TODO(), &users.DeleteUserRequest{AccessToken: "cenas", UserId: userID.String(), Version: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.EmptyResponse{}, res)
+	accessTokenManager.AssertExpectations(t)
+	userDeletionService.AssertExpectations(t)
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Streams everything stored about the logged in user as a JSON document.
+func (srv UserGRPCHandler) ExportMyData(in *users.ExportMyDataRequest, stream users.Users_ExportMyDataServer) error {
+	if in == nil {
+		return status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, userID, err := srv.authenticate(stream.Context(), in.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	return srv.streamDataExport(ctx, stream, userID, userID)
+}
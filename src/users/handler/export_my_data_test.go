@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server stream keeping the chunks sent to it.
+type fakeDataExportStream struct {
+	grpc.ServerStream
+	chunks  []*users.DataExportChunk
+	sendErr error
+}
+
+func (s *fakeDataExportStream) Context() context.Context {
+	return context.TODO()
+}
+
+func (s *fakeDataExportStream) Send(chunk *users.DataExportChunk) error {
+	if s.sendErr != nil {
+		return s.sendErr
+	}
+	s.chunks = append(s.chunks, chunk)
+	return nil
+}
+
+// Handler with an access token "cenas" that belongs to the given user.
+func newDataExportHandler(userID uuid.UUID) (UserGRPCHandler, *mocks.DataExportService) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserIDFromToken", mockToken).Once().Return(userID, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Once().Return(domain.DefaultOrganizationID, nil)
+
+	dataExportService := new(mocks.DataExportService)
+	service := newHandler(accessTokenManager, nil, nil)
+	service.dataExportService = dataExportService
+	return service, dataExportService
+}
+
+func TestExportMyData_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	stream := &fakeDataExportStream{}
+
+	err := service.ExportMyData(nil, stream)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	err = service.ExportMyData(&users.ExportMyDataRequest{}, stream)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+	assert.Empty(t, stream.chunks)
+}
+
+func TestExportMyData_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"user not found":   {domain.ErrNotFound, status.Error(codes.NotFound, "user not found")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error exporting data")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			userID := uuid.New()
+			service, dataExportService := newDataExportHandler(userID)
+			dataExportService.On("Export", mock.Anything, userID, userID).Once().Return(nil, c.serviceErr)
+
+			stream := &fakeDataExportStream{}
+			err := service.ExportMyData(&users.ExportMyDataRequest{AccessToken: "cenas"}, stream)
+			assert.Equal(t, c.expected, err)
+			assert.Empty(t, stream.chunks)
+			dataExportService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExportMyData_ErrorSending(t *testing.T) {
+	userID := uuid.New()
+	service, dataExportService := newDataExportHandler(userID)
+	dataExportService.On("Export", mock.Anything, userID, userID).Once().Return([]byte(`{}`), nil)
+
+	stream := &fakeDataExportStream{sendErr: errors.New("boom")}
+	err := service.ExportMyData(&users.ExportMyDataRequest{AccessToken: "cenas"}, stream)
+	assert.Equal(t, "boom", err.Error())
+}
+
+func TestExportMyData_StreamsInChunks(t *testing.T) {
+	userID := uuid.New()
+	document := bytes.Repeat([]byte("a"), 2*dataExportChunkSize+10)
+	service, dataExportService := newDataExportHandler(userID)
+	dataExportService.On("Export", mock.Anything, userID, userID).Once().Return(document, nil)
+
+	stream := &fakeDataExportStream{}
+	err := service.ExportMyData(&users.ExportMyDataRequest{AccessToken: "cenas"}, stream)
+	assert.Nil(t, err)
+	assert.Len(t, stream.chunks, 3)
+	assert.Len(t, stream.chunks[2].Data, 10)
+
+	received := []byte{}
+	for _, chunk := range stream.chunks {
+		received = append(received, chunk.Data...)
+	}
+	assert.Equal(t, document, received)
+	dataExportService.AssertExpectations(t)
+}
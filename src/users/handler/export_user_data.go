@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"github.com/google/uuid"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Streams everything stored about a user as a JSON document,
+// for the data-subject requests that don't come through the user themselves.
+// Only for administrators.
+func (srv UserGRPCHandler) ExportUserData(in *users.ExportUserDataRequest, stream users.Users_ExportUserDataServer) error {
+	if in == nil {
+		return status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	if _, err := srv.authorizeAdmin(stream.Context(), in.AccessToken); err != nil {
+		return err
+	}
+	ctx, adminID, err := srv.authenticate(stream.Context(), in.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	userID, err := uuid.Parse(in.UserId)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	return srv.streamDataExport(ctx, stream, userID, adminID)
+}
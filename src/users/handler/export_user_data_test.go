@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExportUserData_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	err := service.ExportUserData(nil, &fakeDataExportStream{})
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestExportUserData_UserDoesntHaveProperRole(t *testing.T) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	service := newHandler(accessTokenManager, nil, nil)
+
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"user"}, nil)
+
+	err := service.ExportUserData(&users.ExportUserDataRequest{AccessToken: "cenas", UserId: uuid.NewString()}, &fakeDataExportStream{})
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "incorrect permissions"))
+	accessTokenManager.AssertExpectations(t)
+}
+
+// Handler with an access token "cenas" that belongs to the given administrator.
+func newAdminDataExportHandler(adminID uuid.UUID) (UserGRPCHandler, *mocks.DataExportService) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Twice().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Twice().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"admin"}, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Twice().Return(domain.DefaultOrganizationID, nil)
+	accessTokenManager.On("GetUserIDFromToken", mockToken).Once().Return(adminID, nil)
+
+	dataExportService := new(mocks.DataExportService)
+	service := newHandler(accessTokenManager, nil, nil)
+	service.dataExportService = dataExportService
+	return service, dataExportService
+}
+
+func TestExportUserData_InvalidUserID(t *testing.T) {
+	service, _ := newAdminDataExportHandler(uuid.New())
+
+	err := service.ExportUserData(&users.ExportUserDataRequest{AccessToken: "cenas", UserId: "cenas"}, &fakeDataExportStream{})
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+}
+
+func TestExportUserData_Success(t *testing.T) {
+	adminID := uuid.New()
+	userID := uuid.New()
+	service, dataExportService := newAdminDataExportHandler(adminID)
+	dataExportService.On("Export", mock.Anything, userID, adminID).Once().Return([]byte(`{"formatVersion":1}`), nil)
+
+	stream := &fakeDataExportStream{}
+	err := service.ExportUserData(&users.ExportUserDataRequest{AccessToken: "cenas", UserId: userID.String()}, stream)
+	assert.Nil(t, err)
+	assert.Len(t, stream.chunks, 1)
+	assert.Equal(t, `{"formatVersion":1}`, string(stream.chunks[0].Data))
+	dataExportService.AssertExpectations(t)
+}
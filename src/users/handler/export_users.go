@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"bufio"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Writer sending what is written as data export chunks, of at most dataExportChunkSize bytes.
+type chunkWriter struct {
+	stream dataExportStream
+}
+
+func (w chunkWriter) Write(p []byte) (int, error) {
+	for start := 0; start < len(p); start += dataExportChunkSize {
+		end := start + dataExportChunkSize
+		if end > len(p) {
+			end = len(p)
+		}
+		chunk := make([]byte, end-start)
+		copy(chunk, p[start:end])
+		if err := w.stream.Send(&users.DataExportChunk{Data: chunk}); err != nil {
+			return start, err
+		}
+	}
+	return len(p), nil
+}
+
+// Streams the archive with every role and user, for backups and copying them to other environments.
+// Only for administrators.
+func (srv UserGRPCHandler) ExportUsers(in *users.ExportUsersRequest, stream users.Users_ExportUsersServer) error {
+	if in == nil {
+		return status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(stream.Context(), in.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	// Archives are streamed as they are written, in chunks of the size of the buffer.
+	w := bufio.NewWriterSize(chunkWriter{stream}, dataExportChunkSize)
+	err = srv.userBackupService.Export(ctx, w, in.WithPasswords)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		srv.l.Printf("error exporting the users: %v\n", err)
+		return status.Error(codes.Internal, "error exporting users")
+	}
+	return nil
+}
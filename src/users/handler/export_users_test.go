@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Handler with an administrator access token "cenas", backing up with a mocked service.
+func newUserBackupHandler() (UserGRPCHandler, *mocks.UserBackupService) {
+	service, _ := newAdminHandler(nil)
+	userBackupService := new(mocks.UserBackupService)
+	service.userBackupService = userBackupService
+	return service, userBackupService
+}
+
+func TestExportUsers_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	err := service.ExportUsers(nil, &fakeDataExportStream{})
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	err = service.ExportUsers(&users.ExportUsersRequest{}, &fakeDataExportStream{})
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestExportUsers_ServiceError(t *testing.T) {
+	service, userBackupService := newUserBackupHandler()
+	userBackupService.On("Export", mock.Anything, mock.Anything, false).Once().Return(errors.New("boom"))
+
+	err := service.ExportUsers(&users.ExportUsersRequest{AccessToken: "cenas"}, &fakeDataExportStream{})
+	assert.Equal(t, err, status.Error(codes.Internal, "error exporting users"))
+	userBackupService.AssertExpectations(t)
+}
+
+func TestExportUsers_SendError(t *testing.T) {
+	service, userBackupService := newUserBackupHandler()
+	userBackupService.On("Export", mock.Anything, mock.Anything, false).Once().
+		Run(func(args mock.Arguments) { io.WriteString(args.Get(1).(io.Writer), "archive\n") }).
+		Return(nil)
+
+	err := service.ExportUsers(&users.ExportUsersRequest{AccessToken: "cenas"}, &fakeDataExportStream{sendErr: errors.New("boom")})
+	assert.Equal(t, err, status.Error(codes.Internal, "error exporting users"))
+}
+
+func TestExportUsers_Success(t *testing.T) {
+	service, userBackupService := newUserBackupHandler()
+	archive := bytes.Repeat([]byte("a"), dataExportChunkSize+10)
+	userBackupService.On("Export", mock.Anything, mock.Anything, true).Once().
+		Run(func(args mock.Arguments) { args.Get(1).(io.Writer).Write(archive) }).
+		Return(nil)
+
+	stream := &fakeDataExportStream{}
+	err := service.ExportUsers(&users.ExportUsersRequest{AccessToken: "cenas", WithPasswords: true}, stream)
+	assert.Nil(t, err)
+
+	received := []byte{}
+	for _, chunk := range stream.chunks {
+		assert.LessOrEqual(t, len(chunk.Data), dataExportChunkSize)
+		received = append(received, chunk.Data...)
+	}
+	assert.Equal(t, archive, received)
+	userBackupService.AssertExpectations(t)
+}
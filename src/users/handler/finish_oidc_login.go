@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Logs a user in with the code an identity provider sent it back with, linking or
+// provisioning its local user on its first login.
+func (srv UserGRPCHandler) FinishOIDCLogin(ctx context.Context, in *users.FinishOIDCLoginRequest) (*users.TokenResponse, error) {
+	if in == nil || len(in.State) == 0 || len(in.Code) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	user, err := srv.oidcService.FinishLogin(ctx, in.GetState(), in.GetCode())
+	switch {
+	case errors.Is(err, domain.ErrInvalidToken):
+		return nil, status.Error(codes.Unauthenticated, "invalid login")
+	case errors.Is(err, domain.ErrNotAllowed):
+		return nil, status.Error(codes.PermissionDenied, "user not allowed to log in with identity provider")
+	case errors.Is(err, domain.ErrAlreadyExists):
+		return nil, status.Error(codes.AlreadyExists, "a user with the same username or email already exists")
+	case errors.Is(err, domain.ErrUserNotActive):
+		return nil, status.Error(codes.PermissionDenied, "user is not active")
+	case err != nil:
+		srv.l.Printf("error finishing the login with an identity provider: %v\n", err)
+		return nil, status.Error(codes.Internal, "error logging in with identity provider")
+	}
+
+	// Local passwords that have to be changed still are, as they can be used to log in too.
+	challenge, err := srv.passwordChangeChallenge(user)
+	if err != nil || challenge != nil {
+		return challenge, err
+	}
+
+	// The provider only replaces the password, roles requiring MFA still need the second factor.
+	challenge, err = srv.mfaChallenge(ctx, user)
+	if err != nil || challenge != nil {
+		return challenge, err
+	}
+
+	token, err := srv.tokenManager.GenerateTokens(ctx, user)
+	if err != nil {
+		srv.l.Printf("error generating tokens on identity provider login: %v\n", err)
+		return nil, status.Error(codes.Internal, "error generating tokens")
+	}
+	srv.recordLoginEvent(ctx, domain.LoginEventLogin, user, "", "")
+
+	return &users.TokenResponse{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		User:         userResponse(user),
+	}, nil
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFinishOIDCLogin_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+
+	for _, req := range []*users.FinishOIDCLoginRequest{nil, {State: "the-state"}, {Code: "the-code"}} {
+		res, err := service.FinishOIDCLogin(context.TODO(), req)
+		assert.Nil(t, res)
+		assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+	}
+}
+
+func TestFinishOIDCLogin_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"invalid login":    {domain.ErrInvalidToken, status.Error(codes.Unauthenticated, "invalid login")},
+		"not allowed":      {domain.ErrNotAllowed, status.Error(codes.PermissionDenied, "user not allowed to log in with identity provider")},
+		"already exists":   {domain.ErrAlreadyExists, status.Error(codes.AlreadyExists, "a user with the same username or email already exists")},
+		"inactive user":    {domain.ErrUserNotActive, status.Error(codes.PermissionDenied, "user is not active")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error logging in with identity provider")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			service := newHandler(nil, nil, nil)
+			oidcService := new(mocks.OIDCService)
+			service.oidcService = oidcService
+			oidcService.On("FinishLogin", mock.Anything, "the-state", "the-code").Once().Return(nil, c.serviceErr)
+
+			res, err := service.FinishOIDCLogin(context.TODO(), &users.FinishOIDCLoginRequest{State: "the-state", Code: "the-code"})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+		})
+	}
+}
+
+func TestFinishOIDCLogin_MFA(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "jdoe", Status: domain.UserStatusActive}
+	tokenHandler := new(mocks.AccessTokenHandler)
+	service := newHandler(tokenHandler, nil, nil)
+	oidcService := new(mocks.OIDCService)
+	service.oidcService = oidcService
+	mfaService := new(mocks.MFAService)
+	service.mfaService = mfaService
+
+	oidcService.On("FinishLogin", mock.Anything, "the-state", "the-code").Once().Return(user, nil)
+	mfaService.On("IsEnrolled", mock.Anything, user.ID).Once().Return(true, nil)
+	tokenHandler.On("GenerateMFAChallenge", user).Once().Return("mfa-token", nil)
+
+	res, err := service.FinishOIDCLogin(context.TODO(), &users.FinishOIDCLoginRequest{State: "the-state", Code: "the-code"})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.TokenResponse{MfaRequired: true, MfaToken: "mfa-token"}, res)
+	tokenHandler.AssertNotCalled(t, "GenerateTokens", mock.Anything, mock.Anything)
+}
+
+func TestFinishOIDCLogin_PasswordChangeRequired(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "jdoe", Status: domain.UserStatusActive, Password: "hash", MustChangePassword: true}
+	tokenHandler := new(mocks.AccessTokenHandler)
+	service := newHandler(tokenHandler, nil, nil)
+	oidcService := new(mocks.OIDCService)
+	service.oidcService = oidcService
+	mfaService := new(mocks.MFAService)
+	service.mfaService = mfaService
+
+	oidcService.On("FinishLogin", mock.Anything, "the-state", "the-code").Once().Return(user, nil)
+	tokenHandler.On("GeneratePasswordChangeToken", user).Once().Return("password-change-token", nil)
+
+	res, err := service.FinishOIDCLogin(context.TODO(), &users.FinishOIDCLoginRequest{State: "the-state", Code: "the-code"})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.TokenResponse{PasswordChangeRequired: true, PasswordChangeToken: "password-change-token"}, res)
+	tokenHandler.AssertNotCalled(t, "GenerateTokens", mock.Anything, mock.Anything)
+	mfaService.AssertNotCalled(t, "IsEnrolled", mock.Anything, mock.Anything)
+	tokenHandler.AssertExpectations(t)
+}
+
+func TestFinishOIDCLogin_Success(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "jdoe", Status: domain.UserStatusActive}
+	tokenHandler := new(mocks.AccessTokenHandler)
+	service := newHandler(tokenHandler, nil, nil)
+	loginEventService := withLoginEvents(&service)
+	oidcService := new(mocks.OIDCService)
+	service.oidcService = oidcService
+	mfaService := new(mocks.MFAService)
+	service.mfaService = mfaService
+
+	oidcService.On("FinishLogin", mock.Anything, "the-state", "the-code").Once().Return(user, nil)
+	mfaService.On("IsEnrolled", mock.Anything, user.ID).Once().Return(false, nil)
+	tokenHandler.On("GenerateTokens", mock.Anything, user).Once().
+		Return(domain.TokenResponse{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil)
+
+	res, err := service.FinishOIDCLogin(context.TODO(), &users.FinishOIDCLoginRequest{State: "the-state", Code: "the-code"})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.TokenResponse{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+		User:         &users.UserResponse{Id: user.ID.String(), Username: "jdoe", Status: "active"},
+	}, res)
+	tokenHandler.AssertExpectations(t)
+	assertLoginEventRecorded(t, loginEventService, domain.LoginEventLogin, user.ID, "")
+}
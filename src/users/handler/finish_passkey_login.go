@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Logs in with a passkey.
+// Passkeys always verify the user on the authenticator, so no other factor is asked for.
+func (srv UserGRPCHandler) FinishPasskeyLogin(ctx context.Context, in *users.FinishPasskeyLoginRequest) (*users.TokenResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	sessionID, err := uuid.Parse(in.SessionId)
+	if err != nil || len(in.CredentialJson) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	user, err := srv.passkeyService.FinishLogin(ctx, sessionID, []byte(in.CredentialJson))
+	switch {
+	case errors.Is(err, domain.ErrInvalidToken):
+		return nil, status.Error(codes.FailedPrecondition, "invalid or expired passkey session")
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid credential")
+	case errors.Is(err, domain.ErrNotAllowed):
+		srv.recordLoginEvent(ctx, domain.LoginEventLogin, nil, "", "passkey verification failed")
+		return nil, status.Error(codes.Unauthenticated, "passkey verification failed")
+	case errors.Is(err, domain.ErrUserNotActive):
+		srv.recordLoginEvent(ctx, domain.LoginEventLogin, nil, "", "user is not active")
+		return nil, status.Error(codes.PermissionDenied, "user is not active")
+	case err != nil:
+		srv.l.Printf("error finishing the passkey login: %v\n", err)
+		return nil, status.Error(codes.Internal, "error logging in with passkey")
+	}
+
+	challenge, err := srv.passwordChangeChallenge(user)
+	if err != nil || challenge != nil {
+		return challenge, err
+	}
+
+	token, err := srv.tokenManager.GenerateTokens(ctx, user)
+	if err != nil {
+		srv.l.Printf("error generating tokens on passkey login: %v\n", err)
+		return nil, status.Error(codes.Internal, "error generating tokens")
+	}
+	srv.recordLoginEvent(ctx, domain.LoginEventLogin, user, "", "")
+
+	return &users.TokenResponse{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		User:         userResponse(user),
+	}, nil
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFinishPasskeyLogin_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	tests := []*users.FinishPasskeyLoginRequest{
+		nil,
+		{SessionId: "not an uuid", CredentialJson: "{}"},
+		{SessionId: uuid.NewString()},
+	}
+	for _, req := range tests {
+		res, err := service.FinishPasskeyLogin(context.TODO(), req)
+		assert.Nil(t, res)
+		assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+	}
+}
+
+func TestFinishPasskeyLogin_ServiceErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantedErr error
+	}{
+		{"invalid session", domain.ErrInvalidToken, status.Error(codes.FailedPrecondition, "invalid or expired passkey session")},
+		{"malformed credential", domain.ErrBadParamInput, status.Error(codes.InvalidArgument, "invalid credential")},
+		{"failed verification", domain.ErrNotAllowed, status.Error(codes.Unauthenticated, "passkey verification failed")},
+		{"unexpected error", errors.New("boom"), status.Error(codes.Internal, "error logging in with passkey")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionID := uuid.New()
+			passkeyService := new(mocks.PasskeyService)
+			service := newHandler(nil, nil, nil)
+			withLoginEvents(&service)
+			service.passkeyService = passkeyService
+			passkeyService.On("FinishLogin", mock.Anything, sessionID, []byte("{}")).Once().Return(nil, tt.err)
+
+			res, err := service.FinishPasskeyLogin(context.TODO(), &users.FinishPasskeyLoginRequest{
+				SessionId:      sessionID.String(),
+				CredentialJson: "{}",
+			})
+			assert.Nil(t, res)
+			assert.Equal(t, tt.wantedErr, err)
+			passkeyService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFinishPasskeyLogin_ErrorGeneratingTokens(t *testing.T) {
+	sessionID := uuid.New()
+	user := &domain.User{ID: uuid.New(), Username: "alice"}
+	tokenHandler := new(mocks.AccessTokenHandler)
+	passkeyService := new(mocks.PasskeyService)
+	service := newHandler(tokenHandler, nil, nil)
+	withLoginEvents(&service)
+	service.passkeyService = passkeyService
+	passkeyService.On("FinishLogin", mock.Anything, sessionID, []byte("{}")).Once().Return(user, nil)
+	tokenHandler.On("GenerateTokens", mock.Anything, user).Once().Return(domain.TokenResponse{}, errors.New("boom"))
+
+	res, err := service.FinishPasskeyLogin(context.TODO(), &users.FinishPasskeyLoginRequest{
+		SessionId:      sessionID.String(),
+		CredentialJson: "{}",
+	})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Internal, "error generating tokens"))
+	tokenHandler.AssertExpectations(t)
+}
+
+func TestFinishPasskeyLogin_PasswordChangeRequired(t *testing.T) {
+	sessionID := uuid.New()
+	user := &domain.User{ID: uuid.New(), Username: "alice", Password: "hash", MustChangePassword: true}
+	tokenHandler := new(mocks.AccessTokenHandler)
+	passkeyService := new(mocks.PasskeyService)
+	service := newHandler(tokenHandler, nil, nil)
+	service.passkeyService = passkeyService
+	passkeyService.On("FinishLogin", mock.Anything, sessionID, []byte("{}")).Once().Return(user, nil)
+	tokenHandler.On("GeneratePasswordChangeToken", user).Once().Return("password-change-token", nil)
+
+	res, err := service.FinishPasskeyLogin(context.TODO(), &users.FinishPasskeyLoginRequest{
+		SessionId:      sessionID.String(),
+		CredentialJson: "{}",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.TokenResponse{PasswordChangeRequired: true, PasswordChangeToken: "password-change-token"}, res)
+	tokenHandler.AssertNotCalled(t, "GenerateTokens", mock.Anything, mock.Anything)
+	tokenHandler.AssertExpectations(t)
+}
+
+func TestFinishPasskeyLogin_NoPasswordToChange(t *testing.T) {
+	sessionID := uuid.New()
+	user := &domain.User{
+		ID:                uuid.New(),
+		Username:          "alice",
+		PasswordChangedAt: time.Now().AddDate(0, 0, -91),
+		Role:              &domain.Role{RoleSlug: "user", PasswordMaxAgeDays: 90},
+	}
+	tokenHandler := new(mocks.AccessTokenHandler)
+	passkeyService := new(mocks.PasskeyService)
+	service := newHandler(tokenHandler, nil, nil)
+	withLoginEvents(&service)
+	service.passkeyService = passkeyService
+	passkeyService.On("FinishLogin", mock.Anything, sessionID, []byte("{}")).Once().Return(user, nil)
+	tokenHandler.On("GenerateTokens", mock.Anything, user).Once().
+		Return(domain.TokenResponse{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil)
+
+	res, err := service.FinishPasskeyLogin(context.TODO(), &users.FinishPasskeyLoginRequest{
+		SessionId:      sessionID.String(),
+		CredentialJson: "{}",
+	})
+	assert.Nil(t, err)
+	assert.False(t, res.PasswordChangeRequired)
+	assert.Equal(t, "access-token", res.AccessToken)
+	tokenHandler.AssertNotCalled(t, "GeneratePasswordChangeToken", mock.Anything)
+}
+
+func TestFinishPasskeyLogin_Success(t *testing.T) {
+	sessionID := uuid.New()
+	user := &domain.User{ID: uuid.New(), Username: "alice"}
+	tokenHandler := new(mocks.AccessTokenHandler)
+	passkeyService := new(mocks.PasskeyService)
+	service := newHandler(tokenHandler, nil, nil)
+	loginEventService := withLoginEvents(&service)
+	service.passkeyService = passkeyService
+	passkeyService.On("FinishLogin", mock.Anything, sessionID, []byte("{}")).Once().Return(user, nil)
+	tokenHandler.On("GenerateTokens", mock.Anything, user).Once().
+		Return(domain.TokenResponse{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil)
+
+	res, err := service.FinishPasskeyLogin(context.TODO(), &users.FinishPasskeyLoginRequest{
+		SessionId:      sessionID.String(),
+		CredentialJson: "{}",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "access-token", res.AccessToken)
+	assert.Equal(t, "refresh-token", res.RefreshToken)
+	assert.Equal(t, "alice", res.User.Username)
+	assert.False(t, res.MfaRequired)
+	passkeyService.AssertExpectations(t)
+	tokenHandler.AssertExpectations(t)
+	assertLoginEventRecorded(t, loginEventService, domain.LoginEventLogin, user.ID, "")
+}
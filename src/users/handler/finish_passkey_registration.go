@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Verifies the new credential of the authenticator and saves it as a passkey of the logged in user.
+func (srv UserGRPCHandler) FinishPasskeyRegistration(ctx context.Context, in *users.FinishPasskeyRegistrationRequest) (*users.PasskeyResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	sessionID, err := uuid.Parse(in.SessionId)
+	if err != nil || len(in.CredentialJson) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx, userID, err := srv.authenticate(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := srv.userService.GetUserByUUID(ctx, userID)
+	if err != nil {
+		srv.l.Printf("error getting the user registering a passkey: %v\n", err)
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+
+	credential, err := srv.passkeyService.FinishRegistration(ctx, user, sessionID, []byte(in.CredentialJson))
+	switch {
+	case errors.Is(err, domain.ErrInvalidToken):
+		return nil, status.Error(codes.FailedPrecondition, "invalid or expired passkey session")
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid credential")
+	case errors.Is(err, domain.ErrNotAllowed):
+		return nil, status.Error(codes.PermissionDenied, "passkey verification failed")
+	case errors.Is(err, domain.ErrAlreadyExists):
+		return nil, status.Error(codes.AlreadyExists, "passkey already registered")
+	case err != nil:
+		srv.l.Printf("error finishing the passkey registration: %v\n", err)
+		return nil, status.Error(codes.Internal, "error registering passkey")
+	}
+
+	return &users.PasskeyResponse{
+		Id:        credential.ID.String(),
+		CreatedAt: credential.CreatedAt.Format(time.RFC3339),
+	}, nil
+}
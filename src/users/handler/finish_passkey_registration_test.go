@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFinishPasskeyRegistration_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.FinishPasskeyRegistration(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	tests := []*users.FinishPasskeyRegistrationRequest{
+		{AccessToken: "cenas", SessionId: "not an uuid", CredentialJson: "{}"},
+		{AccessToken: "cenas", SessionId: uuid.NewString()},
+	}
+	for _, req := range tests {
+		res, err = service.FinishPasskeyRegistration(context.TODO(), req)
+		assert.Nil(t, res)
+		assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+	}
+}
+
+func TestFinishPasskeyRegistration_ServiceErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantedErr error
+	}{
+		{"invalid session", domain.ErrInvalidToken, status.Error(codes.FailedPrecondition, "invalid or expired passkey session")},
+		{"malformed credential", domain.ErrBadParamInput, status.Error(codes.InvalidArgument, "invalid credential")},
+		{"failed verification", domain.ErrNotAllowed, status.Error(codes.PermissionDenied, "passkey verification failed")},
+		{"already registered", domain.ErrAlreadyExists, status.Error(codes.AlreadyExists, "passkey already registered")},
+		{"unexpected error", errors.New("boom"), status.Error(codes.Internal, "error registering passkey")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &domain.User{ID: uuid.New()}
+			sessionID := uuid.New()
+			service, userService, passkeyService := newPasskeyRegistrationHandler(user.ID)
+			userService.On("GetUserByUUID", mock.Anything, user.ID).Once().Return(user, nil)
+			passkeyService.On("FinishRegistration", mock.Anything, user, sessionID, []byte("{}")).Once().
+				Return(domain.PasskeyCredential{}, tt.err)
+
+			res, err := service.FinishPasskeyRegistration(context.TODO(), &users.FinishPasskeyRegistrationRequest{
+				AccessToken:    "cenas",
+				SessionId:      sessionID.String(),
+				CredentialJson: "{}",
+			})
+			assert.Nil(t, res)
+			assert.Equal(t, tt.wantedErr, err)
+			passkeyService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFinishPasskeyRegistration_Success(t *testing.T) {
+	user := &domain.User{ID: uuid.New()}
+	sessionID := uuid.New()
+	credential := domain.PasskeyCredential{ID: uuid.New(), UserID: user.ID, CreatedAt: time.Now()}
+	service, userService, passkeyService := newPasskeyRegistrationHandler(user.ID)
+	userService.On("GetUserByUUID", mock.Anything, user.ID).Once().Return(user, nil)
+	passkeyService.On("FinishRegistration", mock.Anything, user, sessionID, []byte("{}")).Once().
+		Return(credential, nil)
+
+	res, err := service.FinishPasskeyRegistration(context.TODO(), &users.FinishPasskeyRegistrationRequest{
+		AccessToken:    "cenas",
+		SessionId:      sessionID.String(),
+		CredentialJson: "{}",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, credential.ID.String(), res.Id)
+	assert.Equal(t, credential.CreatedAt.Format(time.RFC3339), res.CreatedAt)
+	userService.AssertExpectations(t)
+	passkeyService.AssertExpectations(t)
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"context"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Gets the schema of the custom attributes, so clients know which ones they can edit.
+func (srv UserGRPCHandler) GetAttributeDefinitions(ctx context.Context, in *users.GetAttributeDefinitionsRequest) (*users.AttributeDefinitionsResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, _, err := srv.authenticate(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions, err := srv.attributeService.GetDefinitions(ctx)
+	if err != nil {
+		srv.l.Printf("error getting the attribute definitions: %v\n", err)
+		return nil, status.Error(codes.Internal, "error getting attribute definitions")
+	}
+
+	result := &users.AttributeDefinitionsResponse{}
+	for _, definition := range definitions {
+		result.Definitions = append(result.Definitions, attributeDefinitionResponse(definition))
+	}
+	return result, nil
+}
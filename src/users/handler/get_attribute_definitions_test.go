@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Handler with an access token "cenas" that belongs to any logged in user.
+func newUserHandler() (UserGRPCHandler, *mocks.AttributeService) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserIDFromToken", mockToken).Once().Return(uuid.New(), nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Once().Return(domain.DefaultOrganizationID, nil)
+
+	attributeService := new(mocks.AttributeService)
+	service := newHandler(accessTokenManager, nil, nil)
+	service.attributeService = attributeService
+	return service, attributeService
+}
+
+func TestGetAttributeDefinitions_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.GetAttributeDefinitions(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	res, err = service.GetAttributeDefinitions(context.TODO(), &users.GetAttributeDefinitionsRequest{})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestGetAttributeDefinitions_ServiceError(t *testing.T) {
+	service, attributeService := newUserHandler()
+	attributeService.On("GetDefinitions", mock.Anything).Once().Return(nil, errors.New("boom"))
+
+	res, err := service.GetAttributeDefinitions(context.TODO(), &users.GetAttributeDefinitionsRequest{AccessToken: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, status.Error(codes.Internal, "error getting attribute definitions"), err)
+	attributeService.AssertExpectations(t)
+}
+
+func TestGetAttributeDefinitions_Success(t *testing.T) {
+	service, attributeService := newUserHandler()
+	attributeService.On("GetDefinitions", mock.Anything).Once().Return([]domain.AttributeDefinition{
+		{Name: "department", Type: domain.AttributeTypeString, Required: true, InToken: true},
+		{Name: "timezone", Type: domain.AttributeTypeString, UserEditable: true},
+	}, nil)
+
+	res, err := service.GetAttributeDefinitions(context.TODO(), &users.GetAttributeDefinitionsRequest{AccessToken: "cenas"})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.AttributeDefinitionsResponse{Definitions: []*users.AttributeDefinitionResponse{
+		{Name: "department", Type: "string", Required: true, InToken: true},
+		{Name: "timezone", Type: "string", UserEditable: true},
+	}}, res)
+	attributeService.AssertExpectations(t)
+}
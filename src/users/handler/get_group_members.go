@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Gets the IDs of the members of a group.
+// Only for administrators.
+func (srv UserGRPCHandler) GetGroupMembers(ctx context.Context, in *users.GetGroupMembersRequest) (*users.GroupMembersResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	groupID, err := uuid.Parse(in.GroupId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	members, err := srv.groupService.GetMembers(ctx, groupID)
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "group not found")
+	case err != nil:
+		srv.l.Printf("error getting the members of the group: %v\n", err)
+		return nil, status.Error(codes.Internal, "error getting group members")
+	}
+
+	result := &users.GroupMembersResponse{GroupId: groupID.String(), UserIds: make([]string, 0, len(members))}
+	for _, member := range members {
+		result.UserIds = append(result.UserIds, member.String())
+	}
+	return result, nil
+}
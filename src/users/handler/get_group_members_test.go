@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetGroupMembers_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.GetGroupMembers(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	service, _ = newGroupHandler()
+	res, err = service.GetGroupMembers(context.TODO(), &users.GetGroupMembersRequest{AccessToken: "cenas", GroupId: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+}
+
+func TestGetGroupMembers_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"not found":        {domain.ErrNotFound, status.Error(codes.NotFound, "group not found")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error getting group members")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			groupID := uuid.New()
+			service, groupService := newGroupHandler()
+			groupService.On("GetMembers", mock.Anything, groupID).Once().Return(nil, c.serviceErr)
+
+			res, err := service.GetGroupMembers(context.TODO(), &users.GetGroupMembersRequest{AccessToken: "cenas", GroupId: groupID.String()})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			groupService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetGroupMembers_Success(t *testing.T) {
+	groupID, userID := uuid.New(), uuid.New()
+	service, groupService := newGroupHandler()
+	groupService.On("GetMembers", mock.Anything, groupID).Once().Return([]uuid.UUID{userID}, nil)
+
+	res, err := service.GetGroupMembers(context.TODO(), &users.GetGroupMembersRequest{AccessToken: "cenas", GroupId: groupID.String()})
+	assert.Nil(t, err)
+	assert.Equal(t, groupID.String(), res.GroupId)
+	assert.Equal(t, []string{userID.String()}, res.UserIds)
+	groupService.AssertExpectations(t)
+}
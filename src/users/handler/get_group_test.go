@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetGroup_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.GetGroup(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	service, _ = newGroupHandler()
+	res, err = service.GetGroup(context.TODO(), &users.GetGroupRequest{AccessToken: "cenas", Id: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+}
+
+func TestGetGroup_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"not found":        {domain.ErrNotFound, status.Error(codes.NotFound, "group not found")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error getting group")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			groupID := uuid.New()
+			service, groupService := newGroupHandler()
+			groupService.On("Get", mock.Anything, groupID).Once().Return(domain.Group{}, c.serviceErr)
+
+			res, err := service.GetGroup(context.TODO(), &users.GetGroupRequest{AccessToken: "cenas", Id: groupID.String()})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			groupService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetGroup_Success(t *testing.T) {
+	group := domain.Group{ID: uuid.New(), Name: "Editors", Roles: []domain.Role{}}
+	service, groupService := newGroupHandler()
+	groupService.On("Get", mock.Anything, group.ID).Once().Return(group, nil)
+
+	res, err := service.GetGroup(context.TODO(), &users.GetGroupRequest{AccessToken: "cenas", Id: group.ID.String()})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.GroupResponse{Id: group.ID.String(), Name: "Editors", Roles: []string{}}, res)
+	groupService.AssertExpectations(t)
+}
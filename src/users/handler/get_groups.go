@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"context"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Gets all the groups, with their roles.
+// Only for administrators.
+func (srv UserGRPCHandler) GetGroups(ctx context.Context, in *users.GetGroupsRequest) (*users.GroupsResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := srv.groupService.GetAll(ctx)
+	if err != nil {
+		srv.l.Printf("error getting the groups: %v\n", err)
+		return nil, status.Error(codes.Internal, "error getting groups")
+	}
+
+	result := &users.GroupsResponse{}
+	for _, group := range groups {
+		result.Groups = append(result.Groups, groupResponse(group))
+	}
+	return result, nil
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Handler with an administrator access token "cenas" and a group service.
+func newGroupHandler() (UserGRPCHandler, *mocks.GroupService) {
+	groupService := new(mocks.GroupService)
+	service, _ := newAdminHandler(nil)
+	service.groupService = groupService
+	return service, groupService
+}
+
+func TestGetGroups_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.GetGroups(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	res, err = service.GetGroups(context.TODO(), &users.GetGroupsRequest{})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestGetGroups_UserDoesntHaveProperRole(t *testing.T) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	service := newHandler(accessTokenManager, nil, nil)
+
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"user", "editor"}, nil)
+
+	res, err := service.GetGroups(context.TODO(), &users.GetGroupsRequest{AccessToken: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "incorrect permissions"))
+	accessTokenManager.AssertExpectations(t)
+}
+
+func TestGetGroups_ServiceError(t *testing.T) {
+	service, groupService := newGroupHandler()
+	groupService.On("GetAll", mock.Anything).Once().Return(nil, errors.New("boom"))
+
+	res, err := service.GetGroups(context.TODO(), &users.GetGroupsRequest{AccessToken: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, status.Error(codes.Internal, "error getting groups"), err)
+	groupService.AssertExpectations(t)
+}
+
+func TestGetGroups_Success(t *testing.T) {
+	// Administrators through one of their groups are administrators as well.
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"user", "admin"}, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Once().Return(domain.DefaultOrganizationID, nil)
+
+	groupService := new(mocks.GroupService)
+	service := newHandler(accessTokenManager, nil, nil)
+	service.groupService = groupService
+
+	groupID := uuid.New()
+	groupService.On("GetAll", mock.Anything).Once().Return([]domain.Group{{
+		ID:          groupID,
+		Name:        "Editors",
+		Description: "Can edit",
+		Roles:       []domain.Role{{ID: uuid.New(), RoleSlug: "editor"}},
+	}}, nil)
+
+	res, err := service.GetGroups(context.TODO(), &users.GetGroupsRequest{AccessToken: "cenas"})
+	assert.Nil(t, err)
+	assert.Len(t, res.Groups, 1)
+	assert.Equal(t, groupID.String(), res.Groups[0].Id)
+	assert.Equal(t, "Editors", res.Groups[0].Name)
+	assert.Equal(t, "Can edit", res.Groups[0].Description)
+	assert.Equal(t, []string{"editor"}, res.Groups[0].Roles)
+	accessTokenManager.AssertExpectations(t)
+	groupService.AssertExpectations(t)
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"context"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Gets the identity providers of the organization.
+// Only for administrators.
+func (srv UserGRPCHandler) GetIdentityProviders(ctx context.Context, in *users.GetIdentityProvidersRequest) (*users.IdentityProvidersResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	providers, err := srv.oidcService.GetProviders(ctx)
+	if err != nil {
+		srv.l.Printf("error getting the identity providers: %v\n", err)
+		return nil, status.Error(codes.Internal, "error getting identity providers")
+	}
+
+	result := &users.IdentityProvidersResponse{}
+	for _, provider := range providers {
+		result.Providers = append(result.Providers, identityProviderResponse(provider))
+	}
+	return result, nil
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Handler of an administrator, with the OIDC service mocked.
+func newIdentityProviderHandler() (UserGRPCHandler, *mocks.OIDCService) {
+	oidcService := new(mocks.OIDCService)
+	service, _ := newAdminHandler(nil)
+	service.oidcService = oidcService
+	return service, oidcService
+}
+
+func TestGetIdentityProviders_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.GetIdentityProviders(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	res, err = service.GetIdentityProviders(context.TODO(), &users.GetIdentityProvidersRequest{})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestGetIdentityProviders_ServiceError(t *testing.T) {
+	service, oidcService := newIdentityProviderHandler()
+	oidcService.On("GetProviders", mock.Anything).Once().Return(nil, errors.New("boom"))
+
+	res, err := service.GetIdentityProviders(context.TODO(), &users.GetIdentityProvidersRequest{AccessToken: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, status.Error(codes.Internal, "error getting identity providers"), err)
+}
+
+func TestGetIdentityProviders_Success(t *testing.T) {
+	provider := domain.IdentityProvider{
+		ID:           uuid.New(),
+		Slug:         "corporate",
+		Issuer:       "https://idp.example.com",
+		ClientID:     "users-service",
+		ClientSecret: "secret",
+		RoleMapping:  map[string]string{"it-admins": "admin"},
+		Enabled:      true,
+	}
+	service, oidcService := newIdentityProviderHandler()
+	oidcService.On("GetProviders", mock.Anything).Once().Return([]domain.IdentityProvider{provider}, nil)
+
+	res, err := service.GetIdentityProviders(context.TODO(), &users.GetIdentityProvidersRequest{AccessToken: "cenas"})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.IdentityProvidersResponse{
+		Providers: []*users.IdentityProviderResponse{{
+			Id:          provider.ID.String(),
+			Slug:        "corporate",
+			Issuer:      "https://idp.example.com",
+			ClientId:    "users-service",
+			RoleMapping: map[string]string{"it-admins": "admin"},
+			Enabled:     true,
+		}},
+	}, res)
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"context"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Gets the invitations that weren't accepted nor revoked yet.
+// Only for administrators.
+func (srv UserGRPCHandler) GetInvitations(ctx context.Context, in *users.GetInvitationsRequest) (*users.InvitationsResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := srv.invitationService.GetPending(ctx)
+	if err != nil {
+		srv.l.Printf("error getting the invitations: %v\n", err)
+		return nil, status.Error(codes.Internal, "error getting invitations")
+	}
+
+	result := &users.InvitationsResponse{}
+	for _, invitation := range invitations {
+		result.Invitations = append(result.Invitations, invitationResponse(invitation))
+	}
+	return result, nil
+}
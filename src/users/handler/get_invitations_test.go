@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetInvitations_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.GetInvitations(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestGetInvitations_ServiceError(t *testing.T) {
+	service, invitationService := newInvitationHandler()
+	invitationService.On("GetPending", mock.Anything).Once().Return(nil, errors.New("boom"))
+
+	res, err := service.GetInvitations(context.TODO(), &users.GetInvitationsRequest{AccessToken: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Internal, "error getting invitations"))
+	invitationService.AssertExpectations(t)
+}
+
+func TestGetInvitations_Success(t *testing.T) {
+	userID := uuid.New()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	service, invitationService := newInvitationHandler()
+	invitationService.On("GetPending", mock.Anything).Once().Return([]domain.Invitation{{
+		User:      &domain.User{ID: userID, Username: "alice", Status: domain.UserStatusPending},
+		ExpiresAt: createdAt.Add(time.Hour),
+		CreatedAt: createdAt,
+	}}, nil)
+
+	res, err := service.GetInvitations(context.TODO(), &users.GetInvitationsRequest{AccessToken: "cenas"})
+	assert.Nil(t, err)
+	assert.Len(t, res.Invitations, 1)
+	assert.Equal(t, userID.String(), res.Invitations[0].User.Id)
+	assert.Equal(t, "2024-05-01T11:00:00Z", res.Invitations[0].ExpiresAt)
+	invitationService.AssertExpectations(t)
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"context"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Counts the users whose passwords still have the legacy hash they were imported with.
+// Only for administrators.
+func (srv UserGRPCHandler) GetLegacyPasswordReport(ctx context.Context, in *users.GetLegacyPasswordReportRequest) (*users.LegacyPasswordReportResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := srv.userService.GetLegacyPasswordReport(ctx)
+	if err != nil {
+		srv.l.Printf("error getting the legacy password report: %v\n", err)
+		return nil, status.Error(codes.Internal, "error getting legacy password report")
+	}
+
+	result := &users.LegacyPasswordReportResponse{
+		Total:   int32(report.Total),
+		Schemes: make(map[string]int32, len(report.Schemes)),
+	}
+	for scheme, count := range report.Schemes {
+		result.Schemes[scheme] = int32(count)
+	}
+	return result, nil
+}
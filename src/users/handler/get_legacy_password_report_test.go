@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetLegacyPasswordReport_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.GetLegacyPasswordReport(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	res, err = service.GetLegacyPasswordReport(context.TODO(), &users.GetLegacyPasswordReportRequest{})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestGetLegacyPasswordReport_ServiceError(t *testing.T) {
+	userService := new(mocks.UserService)
+	service, _ := newAdminHandler(userService)
+	userService.On("GetLegacyPasswordReport", mock.Anything).Once().
+		Return(domain.LegacyPasswordReport{}, errors.New("boom"))
+
+	res, err := service.GetLegacyPasswordReport(context.TODO(), &users.GetLegacyPasswordReportRequest{AccessToken: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Internal, "error getting legacy password report"))
+	userService.AssertExpectations(t)
+}
+
+func TestGetLegacyPasswordReport_Success(t *testing.T) {
+	userService := new(mocks.UserService)
+	service, accessTokenManager := newAdminHandler(userService)
+	userService.On("GetLegacyPasswordReport", mock.Anything).Once().
+		Return(domain.LegacyPasswordReport{Total: 3, Schemes: map[string]int{"sha1": 3, "scrypt": 0}}, nil)
+
+	res, err := service.GetLegacyPasswordReport(context.TODO(), &users.GetLegacyPasswordReportRequest{AccessToken: "cenas"})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), res.Total)
+	assert.Equal(t, map[string]int32{"sha1": 3, "scrypt": 0}, res.Schemes)
+	userService.AssertExpectations(t)
+	accessTokenManager.AssertExpectations(t)
+}
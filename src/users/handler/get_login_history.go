@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Gets the logins, refreshes and logouts of the logged in user, or of any user for administrators.
+func (srv UserGRPCHandler) GetLoginHistory(ctx context.Context, in *users.GetLoginHistoryRequest) (*users.LoginHistoryResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, userID, _, err := srv.targetUser(ctx, in.AccessToken, in.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := domain.LoginEventFilter{Limit: int(in.Limit), Offset: int(in.Offset)}
+	if filter.From, err = parseOptionalDate(in.From); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid from date")
+	}
+	if filter.To, err = parseOptionalDate(in.To); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid to date")
+	}
+
+	events, err := srv.loginEventService.GetHistory(ctx, userID, filter)
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid filter")
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "user not found")
+	case err != nil:
+		srv.l.Printf("error getting the login history: %v\n", err)
+		return nil, status.Error(codes.Internal, "error getting login history")
+	}
+
+	response := &users.LoginHistoryResponse{UserId: userID.String()}
+	for _, event := range events {
+		response.Events = append(response.Events, &users.LoginEventResponse{
+			Id:        event.ID.String(),
+			Type:      string(event.Type),
+			Success:   event.Success,
+			Reason:    event.Reason,
+			Ip:        event.IP,
+			UserAgent: event.UserAgent,
+			CreatedAt: event.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return response, nil
+}
+
+// Parses an RFC 3339 date, the zero time when it is empty.
+func parseOptionalDate(value string) (time.Time, error) {
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Handler with an access token "cenas" that belongs to the given user, with the given role.
+func newLoginHistoryHandler(callerID uuid.UUID, role string) (UserGRPCHandler, *mocks.LoginEventService) {
+	service, _, _ := newAttributesHandler(callerID, role)
+	loginEventService := new(mocks.LoginEventService)
+	service.loginEventService = loginEventService
+	return service, loginEventService
+}
+
+func TestGetLoginHistory_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.GetLoginHistory(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestGetLoginHistory_OtherUserNeedsAdmin(t *testing.T) {
+	service, loginEventService := newLoginHistoryHandler(uuid.New(), "user")
+
+	res, err := service.GetLoginHistory(context.TODO(), &users.GetLoginHistoryRequest{AccessToken: "cenas", UserId: uuid.NewString()})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "incorrect permissions"))
+	loginEventService.AssertNotCalled(t, "GetHistory", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetLoginHistory_InvalidDates(t *testing.T) {
+	cases := map[string]struct {
+		request  *users.GetLoginHistoryRequest
+		expected error
+	}{
+		"from": {&users.GetLoginHistoryRequest{AccessToken: "cenas", From: "yesterday"}, status.Error(codes.InvalidArgument, "invalid from date")},
+		"to":   {&users.GetLoginHistoryRequest{AccessToken: "cenas", To: "2024-13-01"}, status.Error(codes.InvalidArgument, "invalid to date")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, _ := newLoginHistoryHandler(uuid.New(), "user")
+			res, err := service.GetLoginHistory(context.TODO(), c.request)
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+		})
+	}
+}
+
+func TestGetLoginHistory_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"invalid filter":   {domain.ErrBadParamInput, status.Error(codes.InvalidArgument, "invalid filter")},
+		"user not found":   {domain.ErrNotFound, status.Error(codes.NotFound, "user not found")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error getting login history")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			userID := uuid.New()
+			service, loginEventService := newLoginHistoryHandler(uuid.New(), "admin")
+			loginEventService.On("GetHistory", mock.Anything, userID, mock.Anything).Once().Return(nil, c.serviceErr)
+
+			res, err := service.GetLoginHistory(context.TODO(), &users.GetLoginHistoryRequest{AccessToken: "cenas", UserId: userID.String()})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			loginEventService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetLoginHistory_Success(t *testing.T) {
+	userID := uuid.New()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	event := domain.LoginEvent{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Type:      domain.LoginEventLogin,
+		Reason:    "invalid credentials",
+		IP:        "203.0.113.7",
+		UserAgent: "grpc-go/1.56.3",
+		CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+	}
+
+	service, loginEventService := newLoginHistoryHandler(userID, "user")
+	loginEventService.On("GetHistory", mock.Anything, userID, domain.LoginEventFilter{From: from, To: to, Limit: 10, Offset: 10}).
+		Once().Return([]domain.LoginEvent{event}, nil)
+
+	res, err := service.GetLoginHistory(context.TODO(), &users.GetLoginHistoryRequest{
+		AccessToken: "cenas",
+		From:        "2024-01-01T00:00:00Z",
+		To:          "2024-02-01T00:00:00Z",
+		Limit:       10,
+		Offset:      10,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.LoginHistoryResponse{
+		UserId: userID.String(),
+		Events: []*users.LoginEventResponse{{
+			Id:        event.ID.String(),
+			Type:      "login",
+			Success:   false,
+			Reason:    "invalid credentials",
+			Ip:        "203.0.113.7",
+			UserAgent: "grpc-go/1.56.3",
+			CreatedAt: "2024-01-15T10:30:00Z",
+		}},
+	}, res)
+	loginEventService.AssertExpectations(t)
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"context"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Gets the custom attributes of the logged in user, or of any user for administrators.
+func (srv UserGRPCHandler) GetUserAttributes(ctx context.Context, in *users.GetUserAttributesRequest) (*users.UserAttributesResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, userID, _, err := srv.targetUser(ctx, in.AccessToken, in.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := srv.userService.GetUserByUUID(ctx, userID)
+	if err != nil {
+		srv.l.Printf("error getting the user with the attributes: %v\n", err)
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+
+	result, err := userAttributesResponse(user)
+	if err != nil {
+		srv.l.Printf("error encoding the attributes: %v\n", err)
+		return nil, status.Error(codes.Internal, "error getting attributes")
+	}
+	return result, nil
+}
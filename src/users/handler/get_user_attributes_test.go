@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Handler with an access token "cenas" that belongs to the given user, with the given role.
+func newAttributesHandler(callerID uuid.UUID, role string) (UserGRPCHandler, *mocks.UserService, *mocks.AttributeService) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Twice().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Twice().Return(true)
+	accessTokenManager.On("GetUserIDFromToken", mockToken).Once().Return(callerID, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Maybe().Return(domain.DefaultOrganizationID, nil)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{role}, nil)
+
+	userService := new(mocks.UserService)
+	attributeService := new(mocks.AttributeService)
+	service := newHandler(accessTokenManager, userService, nil)
+	service.attributeService = attributeService
+	return service, userService, attributeService
+}
+
+func TestGetUserAttributes_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.GetUserAttributes(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestGetUserAttributes_InvalidUserID(t *testing.T) {
+	service, _, _ := newAttributesHandler(uuid.New(), "admin")
+
+	res, err := service.GetUserAttributes(context.TODO(), &users.GetUserAttributesRequest{AccessToken: "cenas", UserId: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+}
+
+func TestGetUserAttributes_OtherUserNeedsAdmin(t *testing.T) {
+	service, _, _ := newAttributesHandler(uuid.New(), "user")
+
+	res, err := service.GetUserAttributes(context.TODO(), &users.GetUserAttributesRequest{AccessToken: "cenas", UserId: uuid.NewString()})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "incorrect permissions"))
+}
+
+func TestGetUserAttributes_UserNotFound(t *testing.T) {
+	userID := uuid.New()
+	service, userService, _ := newAttributesHandler(uuid.New(), "admin")
+	userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(nil, sql.ErrNoRows)
+
+	res, err := service.GetUserAttributes(context.TODO(), &users.GetUserAttributesRequest{AccessToken: "cenas", UserId: userID.String()})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.NotFound, "user not found"))
+	userService.AssertExpectations(t)
+}
+
+func TestGetUserAttributes_Success(t *testing.T) {
+	t.Run("own attributes", func(t *testing.T) {
+		userID := uuid.New()
+		service, userService, _ := newAttributesHandler(userID, "user")
+		userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(&domain.User{ID: userID}, nil)
+
+		res, err := service.GetUserAttributes(context.TODO(), &users.GetUserAttributesRequest{AccessToken: "cenas"})
+		assert.Nil(t, err)
+		assert.Equal(t, &users.UserAttributesResponse{UserId: userID.String(), AttributesJson: "{}"}, res)
+		userService.AssertExpectations(t)
+	})
+
+	t.Run("administrator getting another user", func(t *testing.T) {
+		userID := uuid.New()
+		service, userService, _ := newAttributesHandler(uuid.New(), "admin")
+		userService.On("GetUserByUUID", mock.Anything, userID).Once().
+			Return(&domain.User{ID: userID, Attributes: domain.Attributes{"timezone": "UTC"}}, nil)
+
+		res, err := service.GetUserAttributes(context.TODO(), &users.GetUserAttributesRequest{AccessToken: "cenas", UserId: userID.String()})
+		assert.Nil(t, err)
+		assert.Equal(t, &users.UserAttributesResponse{UserId: userID.String(), AttributesJson: `{"timezone":"UTC"}`}, res)
+		userService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Converts a group (with its roles) to the gRPC response.
+func groupResponse(group domain.Group) *users.GroupResponse {
+	result := &users.GroupResponse{
+		Id:          group.ID.String(),
+		Name:        group.Name,
+		Description: group.Description,
+		Roles:       make([]string, 0, len(group.Roles)),
+	}
+	for _, role := range group.Roles {
+		result.Roles = append(result.Roles, role.RoleSlug)
+	}
+	return result
+}
+
+// Adds or removes a member of a group on behalf of an administrator.
+func (srv UserGRPCHandler) changeGroupMembership(ctx context.Context, in *users.GroupMemberRequest, add bool) (*users.EmptyResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	groupID, groupErr := uuid.Parse(in.GroupId)
+	userID, userErr := uuid.Parse(in.UserId)
+	if groupErr != nil || userErr != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	if add {
+		err = srv.groupService.AddMember(ctx, groupID, userID)
+	} else {
+		err = srv.groupService.RemoveMember(ctx, groupID, userID)
+	}
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "group member not found")
+	case err != nil:
+		srv.l.Printf("error changing the members of the group: %v\n", err)
+		return nil, status.Error(codes.Internal, "error changing group members")
+	}
+
+	return &users.EmptyResponse{}, nil
+}
+
+// Assigns or unassigns a role of a group on behalf of an administrator.
+func (srv UserGRPCHandler) changeGroupRole(ctx context.Context, in *users.GroupRoleRequest, assign bool) (*users.GroupResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	groupID, err := uuid.Parse(in.GroupId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	var group domain.Group
+	if assign {
+		group, err = srv.groupService.AssignRole(ctx, groupID, in.Role)
+	} else {
+		group, err = srv.groupService.UnassignRole(ctx, groupID, in.Role)
+	}
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "group role not found")
+	case err != nil:
+		srv.l.Printf("error changing the roles of the group: %v\n", err)
+		return nil, status.Error(codes.Internal, "error changing group roles")
+	}
+
+	return groupResponse(group), nil
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"io/ioutil"
 	"log"
+	"net"
 
 	"github.com/plagioriginal/user-microservice/domain"
 	users "github.com/plagioriginal/users-service-grpc/users"
@@ -10,27 +11,91 @@ import (
 
 type UserGRPCHandler struct {
 	users.UnimplementedUsersServer
-	l            *log.Logger
-	tokenManager domain.AccessTokenHandler
-	userService  domain.UserService
+	l                        *log.Logger
+	tokenManager             domain.AccessTokenHandler
+	userService              domain.UserService
+	loginAttemptService      domain.LoginAttemptService
+	emailVerificationService domain.EmailVerificationService
+	passwordResetService     domain.PasswordResetService
+	registrationService      domain.RegistrationService
+	mfaService               domain.MFAService
+	passkeyService           domain.PasskeyService
+	userDeletionService      domain.UserDeletionService
+	dataExportService        domain.DataExportService
+	attributeService         domain.AttributeService
+	userImportService        domain.UserImportService
+	userBackupService        domain.UserBackupService
+	groupService             domain.GroupService
+	organizationService      domain.OrganizationService
+	loginEventService        domain.LoginEventService
+	invitationService        domain.InvitationService
+	magicLinkService         domain.MagicLinkService
+	oidcService              domain.OIDCService
+	roleService              domain.RoleService
+	// Proxies whose forwarded client IPs are trusted.
+	trustedProxies []*net.IPNet
 }
 
 func NewUserGRPCHandler(
 	l *log.Logger,
 	tokenManager domain.AccessTokenHandler,
 	userService domain.UserService,
+	loginAttemptService domain.LoginAttemptService,
+	emailVerificationService domain.EmailVerificationService,
+	passwordResetService domain.PasswordResetService,
+	registrationService domain.RegistrationService,
+	mfaService domain.MFAService,
+	passkeyService domain.PasskeyService,
+	userDeletionService domain.UserDeletionService,
+	dataExportService domain.DataExportService,
+	attributeService domain.AttributeService,
+	userImportService domain.UserImportService,
+	userBackupService domain.UserBackupService,
+	groupService domain.GroupService,
+	organizationService domain.OrganizationService,
+	loginEventService domain.LoginEventService,
+	invitationService domain.InvitationService,
+	magicLinkService domain.MagicLinkService,
+	oidcService domain.OIDCService,
+	roleService domain.RoleService,
+	trustedProxies []*net.IPNet,
 ) users.UsersServer {
 	return UserGRPCHandler{
-		l:            l,
-		tokenManager: tokenManager,
-		userService:  userService,
+		l:                        l,
+		tokenManager:             tokenManager,
+		userService:              userService,
+		loginAttemptService:      loginAttemptService,
+		emailVerificationService: emailVerificationService,
+		passwordResetService:     passwordResetService,
+		registrationService:      registrationService,
+		mfaService:               mfaService,
+		passkeyService:           passkeyService,
+		userDeletionService:      userDeletionService,
+		dataExportService:        dataExportService,
+		attributeService:         attributeService,
+		userImportService:        userImportService,
+		userBackupService:        userBackupService,
+		groupService:             groupService,
+		organizationService:      organizationService,
+		loginEventService:        loginEventService,
+		invitationService:        invitationService,
+		magicLinkService:         magicLinkService,
+		oidcService:              oidcService,
+		roleService:              roleService,
+		trustedProxies:           trustedProxies,
 	}
 }
 
-func newHandler(tokenManager domain.AccessTokenHandler, userService domain.UserService) users.UsersServer {
+// Used for tests, the other dependencies can be set on the returned handler.
+func newHandler(
+	tokenManager domain.AccessTokenHandler,
+	userService domain.UserService,
+	loginAttemptService domain.LoginAttemptService,
+) UserGRPCHandler {
 	return UserGRPCHandler{
-		l:            log.New(ioutil.Discard, "tests: ", log.Flags()),
-		tokenManager: tokenManager,
-		userService:  userService,
+		l:                   log.New(ioutil.Discard, "tests: ", log.Flags()),
+		tokenManager:        tokenManager,
+		userService:         userService,
+		loginAttemptService: loginAttemptService,
 	}
 }
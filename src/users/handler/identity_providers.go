@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+)
+
+// Converts an identity provider to the gRPC response, without its client secret.
+func identityProviderResponse(provider domain.IdentityProvider) *users.IdentityProviderResponse {
+	return &users.IdentityProviderResponse{
+		Id:            provider.ID.String(),
+		Slug:          provider.Slug,
+		Name:          provider.Name,
+		Issuer:        provider.Issuer,
+		ClientId:      provider.ClientID,
+		RedirectUrl:   provider.RedirectURL,
+		Scopes:        provider.Scopes,
+		RoleClaim:     provider.RoleClaim,
+		RoleMapping:   provider.RoleMapping,
+		DefaultRole:   provider.DefaultRole,
+		LinkByEmail:   provider.LinkByEmail,
+		AutoProvision: provider.AutoProvision,
+		Enabled:       provider.Enabled,
+	}
+}
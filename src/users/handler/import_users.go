@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Largest file accepted by an import.
+const importUsersMaxSize = 16 * 1024 * 1024
+
+// Imports the users of a CSV or JSON lines file streamed in chunks,
+// answering with the outcome of every row.
+// Only for administrators.
+func (srv UserGRPCHandler) ImportUsers(stream users.Users_ImportUsersServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.Unauthenticated, "invalid token")
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx, err := srv.authorizeAdmin(stream.Context(), first.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	file := bytes.NewBuffer(first.Data)
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if file.Len()+len(chunk.Data) > importUsersMaxSize {
+			return status.Error(codes.ResourceExhausted, "import too large")
+		}
+		file.Write(chunk.Data)
+	}
+
+	report, err := srv.userImportService.Import(ctx, file, domain.UserImportFormat(first.Format), first.DryRun)
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		srv.l.Printf("error importing the users: %v\n", err)
+		return status.Error(codes.Internal, "error importing users")
+	}
+
+	return stream.SendAndClose(importUsersResponse(report))
+}
+
+// Converts the report of an import to the gRPC response.
+func importUsersResponse(report domain.UserImportReport) *users.ImportUsersResponse {
+	result := &users.ImportUsersResponse{
+		DryRun:  report.DryRun,
+		Created: int32(report.Created),
+		Skipped: int32(report.Skipped),
+		Failed:  int32(report.Failed),
+	}
+
+	for _, row := range report.Rows {
+		imported := &users.ImportedUserRow{
+			Line:     int32(row.Line),
+			Username: row.Username,
+			Status:   string(row.Status),
+			Reason:   row.Reason,
+		}
+		if row.Status == domain.UserImportRowCreated && !report.DryRun {
+			imported.UserId = row.UserID.String()
+		}
+		result.Rows = append(result.Rows, imported)
+	}
+	return result
+}
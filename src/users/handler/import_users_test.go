@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Client stream sending the given requests, keeping the response.
+type fakeImportUsersStream struct {
+	grpc.ServerStream
+	requests []*users.ImportUsersRequest
+	response *users.ImportUsersResponse
+}
+
+func (s *fakeImportUsersStream) Context() context.Context {
+	return context.TODO()
+}
+
+func (s *fakeImportUsersStream) Recv() (*users.ImportUsersRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	request := s.requests[0]
+	s.requests = s.requests[1:]
+	return request, nil
+}
+
+func (s *fakeImportUsersStream) SendAndClose(response *users.ImportUsersResponse) error {
+	s.response = response
+	return nil
+}
+
+// Handler with an administrator access token "cenas", importing with a mocked service.
+func newImportUsersHandler() (UserGRPCHandler, *mocks.UserImportService) {
+	service, _ := newAdminHandler(nil)
+	userImportService := new(mocks.UserImportService)
+	service.userImportService = userImportService
+	return service, userImportService
+}
+
+func TestImportUsers_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	err := service.ImportUsers(&fakeImportUsersStream{})
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	err = service.ImportUsers(&fakeImportUsersStream{requests: []*users.ImportUsersRequest{{Format: "csv"}}})
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestImportUsers_UserDoesntHaveProperRole(t *testing.T) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	service := newHandler(accessTokenManager, nil, nil)
+
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"user"}, nil)
+
+	err := service.ImportUsers(&fakeImportUsersStream{requests: []*users.ImportUsersRequest{{AccessToken: "cenas"}}})
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "incorrect permissions"))
+	accessTokenManager.AssertExpectations(t)
+}
+
+func TestImportUsers_TooLarge(t *testing.T) {
+	service, _ := newImportUsersHandler()
+
+	err := service.ImportUsers(&fakeImportUsersStream{requests: []*users.ImportUsersRequest{
+		{AccessToken: "cenas", Format: "csv", Data: make([]byte, importUsersMaxSize)},
+		{Data: []byte("a")},
+	}})
+	assert.Equal(t, err, status.Error(codes.ResourceExhausted, "import too large"))
+}
+
+func TestImportUsers_ServiceErrors(t *testing.T) {
+	invalidFile := fmt.Errorf("%w: missing column %q", domain.ErrBadParamInput, "password")
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"invalid file":     {invalidFile, status.Error(codes.InvalidArgument, invalidFile.Error())},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error importing users")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, userImportService := newImportUsersHandler()
+			userImportService.On("Import", mock.Anything, mock.Anything, domain.UserImportFormatCSV, false).Once().
+				Return(domain.UserImportReport{}, c.serviceErr)
+
+			err := service.ImportUsers(&fakeImportUsersStream{requests: []*users.ImportUsersRequest{{AccessToken: "cenas", Format: "csv"}}})
+			assert.Equal(t, c.expected, err)
+			userImportService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestImportUsers_Success(t *testing.T) {
+	service, userImportService := newImportUsersHandler()
+	userID := uuid.New()
+
+	var file string
+	userImportService.On("Import", mock.Anything, mock.Anything, domain.UserImportFormatJSONLines, false).Once().
+		Run(func(args mock.Arguments) {
+			content, _ := ioutil.ReadAll(args.Get(1).(io.Reader))
+			file = string(content)
+		}).
+		Return(domain.UserImportReport{
+			Created: 1,
+			Failed:  1,
+			Rows: []domain.UserImportRow{
+				{Line: 1, Username: "alice", Status: domain.UserImportRowCreated, UserID: userID},
+				{Line: 2, Username: "bob", Status: domain.UserImportRowFailed, Reason: "invalid email"},
+			},
+		}, nil)
+
+	stream := &fakeImportUsersStream{requests: []*users.ImportUsersRequest{
+		{AccessToken: "cenas", Format: "jsonl", Data: []byte(`{"username":"alice",`)},
+		{Data: []byte(`"password":"secret123"}` + "\n")},
+		{Data: []byte(`{"username":"bob","password":"secret123","email":"bob"}`)},
+	}}
+	err := service.ImportUsers(stream)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"username":"alice","password":"secret123"}`+"\n"+`{"username":"bob","password":"secret123","email":"bob"}`, file)
+	assert.Equal(t, &users.ImportUsersResponse{
+		Created: 1,
+		Failed:  1,
+		Rows: []*users.ImportedUserRow{
+			{Line: 1, Username: "alice", Status: "created", UserId: userID.String()},
+			{Line: 2, Username: "bob", Status: "failed", Reason: "invalid email"},
+		},
+	}, stream.response)
+	userImportService.AssertExpectations(t)
+}
@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+)
+
+// Converts an invitation (with its user) to the gRPC response.
+func invitationResponse(invitation domain.Invitation) *users.InvitationResponse {
+	return &users.InvitationResponse{
+		User:      userResponse(invitation.User),
+		ExpiresAt: invitation.ExpiresAt.Format(time.RFC3339),
+		CreatedAt: invitation.CreatedAt.Format(time.RFC3339),
+	}
+}
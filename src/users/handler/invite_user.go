@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Invites a new user, who chooses its own password with the link sent to its email.
+// Only for administrators.
+func (srv UserGRPCHandler) InviteUser(ctx context.Context, in *users.InviteUserRequest) (*users.InvitationResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(in.Username) == 0 || len(in.Role) == 0 || len(in.Email) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	invitation, err := srv.invitationService.Invite(ctx, domain.InviteUserRequest{
+		Username: in.GetUsername(),
+		Email:    in.GetEmail(),
+		RoleSlug: in.GetRole(),
+	})
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "role not found")
+	case errors.Is(err, domain.ErrAlreadyExists):
+		return nil, status.Error(codes.AlreadyExists, "username or email already taken")
+	case err != nil:
+		srv.l.Printf("error inviting a user: %v\n", err)
+		return nil, status.Error(codes.Internal, "error inviting user")
+	}
+
+	return invitationResponse(invitation), nil
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Handler with an administrator access token "cenas" and a mocked invitation service.
+func newInvitationHandler() (UserGRPCHandler, *mocks.InvitationService) {
+	service, _ := newAdminHandler(nil)
+	invitationService := new(mocks.InvitationService)
+	service.invitationService = invitationService
+	return service, invitationService
+}
+
+func TestInviteUser_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.InviteUser(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	service, invitationService := newInvitationHandler()
+	res, err = service.InviteUser(context.TODO(), &users.InviteUserRequest{AccessToken: "cenas", Username: "alice", Role: "user"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+	invitationService.AssertNotCalled(t, "Invite", mock.Anything, mock.Anything)
+}
+
+func TestInviteUser_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"invalid email":    {domain.ErrBadParamInput, status.Error(codes.InvalidArgument, "invalid request")},
+		"unknown role":     {domain.ErrNotFound, status.Error(codes.NotFound, "role not found")},
+		"username taken":   {domain.ErrAlreadyExists, status.Error(codes.AlreadyExists, "username or email already taken")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error inviting user")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, invitationService := newInvitationHandler()
+			invitationService.On("Invite", mock.Anything, mock.Anything).Once().Return(domain.Invitation{}, c.serviceErr)
+
+			res, err := service.InviteUser(context.TODO(), &users.InviteUserRequest{
+				AccessToken: "cenas",
+				Username:    "alice",
+				Role:        "user",
+				Email:       "alice@example.com",
+			})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			invitationService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInviteUser_Success(t *testing.T) {
+	userID := uuid.New()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	service, invitationService := newInvitationHandler()
+	invitationService.On("Invite", mock.Anything, domain.InviteUserRequest{
+		Username: "alice",
+		Email:    "alice@example.com",
+		RoleSlug: "user",
+	}).Once().Return(domain.Invitation{
+		User:      &domain.User{ID: userID, Username: "alice", Status: domain.UserStatusPending, Version: 1},
+		ExpiresAt: createdAt.Add(72 * time.Hour),
+		CreatedAt: createdAt,
+	}, nil)
+
+	res, err := service.InviteUser(context.TODO(), &users.InviteUserRequest{
+		AccessToken: "cenas",
+		Username:    "alice",
+		Role:        "user",
+		Email:       "alice@example.com",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.InvitationResponse{
+		User:      &users.UserResponse{Id: userID.String(), Username: "alice", Status: "pending", Version: 1},
+		ExpiresAt: "2024-05-04T10:00:00Z",
+		CreatedAt: "2024-05-01T10:00:00Z",
+	}, res)
+	invitationService.AssertExpectations(t)
+}
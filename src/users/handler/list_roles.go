@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"context"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Gets all the roles of the organization.
+// Only for administrators.
+func (srv UserGRPCHandler) ListRoles(ctx context.Context, in *users.ListRolesRequest) (*users.RolesResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := srv.roleService.GetAll(ctx)
+	if err != nil {
+		srv.l.Printf("error getting the roles: %v\n", err)
+		return nil, status.Error(codes.Internal, "error getting roles")
+	}
+
+	result := &users.RolesResponse{}
+	for _, role := range roles {
+		result.Roles = append(result.Roles, roleResponse(role))
+	}
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Handler of an administrator, with the role service mocked.
+func newRoleHandler() (UserGRPCHandler, *mocks.RoleService) {
+	roleService := new(mocks.RoleService)
+	service, _ := newAdminHandler(nil)
+	service.roleService = roleService
+	return service, roleService
+}
+
+func TestListRoles_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.ListRoles(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	res, err = service.ListRoles(context.TODO(), &users.ListRolesRequest{})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestListRoles_ServiceError(t *testing.T) {
+	service, roleService := newRoleHandler()
+	roleService.On("GetAll", mock.Anything).Once().Return(nil, errors.New("boom"))
+
+	res, err := service.ListRoles(context.TODO(), &users.ListRolesRequest{AccessToken: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, status.Error(codes.Internal, "error getting roles"), err)
+}
+
+func TestListRoles_Success(t *testing.T) {
+	role := domain.Role{
+		ID:                 uuid.New(),
+		RoleSlug:           "auditor",
+		RoleLabel:          "Auditor",
+		RequiresMFA:        true,
+		PasswordMaxAgeDays: 90,
+		Version:            2,
+	}
+	service, roleService := newRoleHandler()
+	roleService.On("GetAll", mock.Anything).Once().Return([]domain.Role{role}, nil)
+
+	res, err := service.ListRoles(context.TODO(), &users.ListRolesRequest{AccessToken: "cenas"})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.RolesResponse{
+		Roles: []*users.RoleResponse{{
+			Id:                 role.ID.String(),
+			RoleSlug:           "auditor",
+			RoleLabel:          "Auditor",
+			RequiresMfa:        true,
+			PasswordMaxAgeDays: 90,
+			Version:            2,
+		}},
+	}, res)
+}
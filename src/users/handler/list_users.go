@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Lists the users in a paginated manner, optionally filtered by their custom attributes.
+// Only for administrators.
+func (srv UserGRPCHandler) ListUsers(ctx context.Context, in *users.ListUsersRequest) (*users.ListUsersResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := domain.UserFilter{Limit: int(in.Limit), Offset: int(in.Offset)}
+	if len(in.AttributesJson) > 0 {
+		if err := json.Unmarshal([]byte(in.AttributesJson), &filter.Attributes); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "attributes must be a JSON object")
+		}
+	}
+
+	result, err := srv.userService.List(ctx, filter)
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid pagination")
+	case err != nil:
+		srv.l.Printf("error listing the users: %v\n", err)
+		return nil, status.Error(codes.Internal, "error listing users")
+	}
+
+	response := &users.ListUsersResponse{}
+	for i := range result {
+		response.Users = append(response.Users, userResponse(&result[i]))
+	}
+	return response, nil
+}
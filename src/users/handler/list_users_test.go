@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListUsers_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.ListUsers(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestListUsers_InvalidFilter(t *testing.T) {
+	service, _ := newAdminHandler(nil)
+
+	res, err := service.ListUsers(context.TODO(), &users.ListUsersRequest{AccessToken: "cenas", AttributesJson: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "attributes must be a JSON object"))
+}
+
+func TestListUsers_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"invalid pagination": {domain.ErrBadParamInput, status.Error(codes.InvalidArgument, "invalid pagination")},
+		"unexpected error":   {errors.New("boom"), status.Error(codes.Internal, "error listing users")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			userService := new(mocks.UserService)
+			service, _ := newAdminHandler(userService)
+			userService.On("List", mock.Anything, domain.UserFilter{Limit: 1000}).Once().Return(nil, c.serviceErr)
+
+			res, err := service.ListUsers(context.TODO(), &users.ListUsersRequest{AccessToken: "cenas", Limit: 1000})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			userService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListUsers_Success(t *testing.T) {
+	userID := uuid.New()
+	userService := new(mocks.UserService)
+	service, accessTokenManager := newAdminHandler(userService)
+	userService.On("List", mock.Anything, domain.UserFilter{
+		Attributes: domain.Attributes{"department": "sales"},
+		Limit:      10,
+		Offset:     30,
+	}).Once().Return([]domain.User{{
+		ID:         userID,
+		Username:   "alice",
+		Status:     domain.UserStatusActive,
+		Attributes: domain.Attributes{"department": "sales"},
+	}}, nil)
+
+	res, err := service.ListUsers(context.TODO(), &users.ListUsersRequest{
+		AccessToken:    "cenas",
+		AttributesJson: `{"department":"sales"}`,
+		Limit:          10,
+		Offset:         30,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.ListUsersResponse{Users: []*users.UserResponse{{
+		Id:             userID.String(),
+		Username:       "alice",
+		Status:         "active",
+		AttributesJson: `{"department":"sales"}`,
+	}}}, res)
+	accessTokenManager.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
@@ -2,11 +2,17 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
 
 	"github.com/plagioriginal/user-microservice/domain"
 	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 // Gets the tokens for the login
@@ -15,38 +21,84 @@ func (srv UserGRPCHandler) Login(ctx context.Context, in *users.LoginRequest) (*
 		return nil, status.Error(codes.NotFound, "resource found")
 	}
 
+	ip := srv.clientIP(ctx)
+	retryAfter, err := srv.loginAttemptService.GetRetryAfter(ctx, in.GetUsername(), ip)
+	if err != nil {
+		srv.l.Printf("error checking the login attempts: %v\n", err)
+		return nil, status.Error(codes.Internal, "error checking login attempts")
+	}
+	if retryAfter > 0 {
+		srv.recordLoginEvent(ctx, domain.LoginEventLogin, nil, in.GetUsername(), "too many attempts")
+		return nil, tooManyAttemptsError(retryAfter)
+	}
+
 	user, err := srv.userService.GetUserByLogin(ctx, domain.GetUserRequest{
 		Username: in.GetUsername(),
 		Password: in.GetPassword(),
 	})
 
+	// The password was right, so it doesn't count as a failed login.
+	if errors.Is(err, domain.ErrUserNotActive) {
+		srv.recordLoginEvent(ctx, domain.LoginEventLogin, nil, in.GetUsername(), "user is not active")
+		return nil, status.Error(codes.PermissionDenied, "user is not active")
+	}
 	if err != nil {
 		srv.l.Printf("error getting the user by login: %v\n", err)
+		srv.recordLoginEvent(ctx, domain.LoginEventLogin, nil, in.GetUsername(), "invalid credentials")
+
+		retryAfter, throttleErr := srv.loginAttemptService.RegisterFailure(ctx, in.GetUsername(), ip)
+		if throttleErr != nil {
+			srv.l.Printf("error registering failed login: %v\n", throttleErr)
+		}
+		if retryAfter > 0 {
+			return nil, tooManyAttemptsError(retryAfter)
+		}
 		return nil, status.Error(codes.NotFound, "resource found")
 	}
 
+	if err = srv.loginAttemptService.RegisterSuccess(ctx, in.GetUsername()); err != nil {
+		srv.l.Printf("error resetting the failed logins: %v\n", err)
+	}
+
+	// The password has to be changed first, the second factor is asked for afterwards.
+	challenge, err := srv.passwordChangeChallenge(user)
+	if err != nil || challenge != nil {
+		return challenge, err
+	}
+
+	// The tokens are only sent once the second factor is verified, which is when the login is recorded.
+	challenge, err = srv.mfaChallenge(ctx, user)
+	if err != nil || challenge != nil {
+		return challenge, err
+	}
+
 	// Generates the tokens of said user.
 	token, err := srv.tokenManager.GenerateTokens(ctx, user)
 	if err != nil {
 		srv.l.Printf("error generating tokens on login: %v\n", err)
 		return nil, status.Error(codes.Internal, "error generating tokens")
 	}
+	srv.recordLoginEvent(ctx, domain.LoginEventLogin, user, "", "")
 
 	result := &users.TokenResponse{
 		AccessToken:  token.AccessToken,
 		RefreshToken: token.RefreshToken,
-		User: &users.UserResponse{
-			Id:        user.ID.String(),
-			Username:  user.Username,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Role: &users.UserResponse_RoleResponse{
-				Id:        user.RoleId.String(),
-				RoleLabel: user.Role.RoleLabel,
-				RoleSlug:  user.Role.RoleSlug,
-			},
-		},
+		User:         userResponse(user),
 	}
 
 	return result, nil
 }
+
+// Error sent when a login is locked, with a hint of when to retry.
+func tooManyAttemptsError(retryAfter time.Duration) error {
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	st := status.New(codes.ResourceExhausted, fmt.Sprintf("too many login attempts, retry after %d seconds", seconds))
+
+	detailed, err := st.WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(time.Duration(seconds) * time.Second),
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Records a login, refresh or logout attempt along with the client that made it.
+// The attempt failed when there is a reason. Without a user, the username tried is kept.
+// Failing to record it doesn't fail the request.
+func (srv UserGRPCHandler) recordLoginEvent(ctx context.Context, eventType domain.LoginEventType, user *domain.User, username string, reason string) {
+	event := domain.LoginEvent{
+		Username:  username,
+		Type:      eventType,
+		Success:   len(reason) == 0,
+		Reason:    reason,
+		IP:        srv.clientIP(ctx),
+		UserAgent: userAgent(ctx),
+	}
+	if user != nil {
+		event.UserID = user.ID
+		event.Username = user.Username
+		// Refreshes and logouts aren't scoped to the organization of the user otherwise.
+		ctx = domain.WithOrganization(ctx, user.OrganizationID)
+	}
+
+	if err := srv.loginEventService.Record(ctx, event); err != nil {
+		srv.l.Printf("error recording the %s event: %v\n", eventType, err)
+	}
+}
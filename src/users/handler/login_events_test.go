@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Sets a login event service on the handler that records any event.
+func withLoginEvents(srv *UserGRPCHandler) *mocks.LoginEventService {
+	loginEventService := new(mocks.LoginEventService)
+	loginEventService.On("Record", mock.Anything, mock.Anything).Maybe().Return(nil)
+	srv.loginEventService = loginEventService
+	return loginEventService
+}
+
+// Asserts that an event with the type, user and reason was recorded.
+func assertLoginEventRecorded(t *testing.T, loginEventService *mocks.LoginEventService, eventType domain.LoginEventType, userID uuid.UUID, reason string) {
+	loginEventService.AssertCalled(t, "Record", mock.Anything, mock.MatchedBy(func(event domain.LoginEvent) bool {
+		return event.Type == eventType && event.UserID == userID && event.Reason == reason && event.Success == (len(reason) == 0)
+	}))
+}
+
+func TestRecordLoginEvent_UnknownUser(t *testing.T) {
+	loginEventService := new(mocks.LoginEventService)
+	loginEventService.On("Record", mock.Anything, domain.LoginEvent{
+		Username:  "nobody",
+		Type:      domain.LoginEventLogin,
+		Reason:    "invalid credentials",
+		IP:        "203.0.113.7",
+		UserAgent: "grpc-go/1.56.3",
+	}).Once().Return(nil)
+
+	service := newHandler(nil, nil, nil)
+	service.loginEventService = loginEventService
+
+	ctx := peer.NewContext(context.TODO(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 5432}})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("user-agent", "grpc-go/1.56.3"))
+	service.recordLoginEvent(ctx, domain.LoginEventLogin, nil, "nobody", "invalid credentials")
+	loginEventService.AssertExpectations(t)
+}
+
+func TestRecordLoginEvent_ScopedToTheOrganizationOfTheUser(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", OrganizationID: uuid.New()}
+	loginEventService := new(mocks.LoginEventService)
+	loginEventService.On("Record", mock.MatchedBy(func(ctx context.Context) bool {
+		return domain.OrganizationFromContext(ctx) == user.OrganizationID
+	}), domain.LoginEvent{UserID: user.ID, Username: "alice", Type: domain.LoginEventLogout, Success: true}).Once().Return(nil)
+
+	service := newHandler(nil, nil, nil)
+	service.loginEventService = loginEventService
+
+	service.recordLoginEvent(context.TODO(), domain.LoginEventLogout, user, "", "")
+	loginEventService.AssertExpectations(t)
+}
+
+func TestRecordLoginEvent_FailingToRecordIsIgnored(t *testing.T) {
+	loginEventService := new(mocks.LoginEventService)
+	loginEventService.On("Record", mock.Anything, mock.Anything).Once().Return(errors.New("boom"))
+
+	service := newHandler(nil, nil, nil)
+	service.loginEventService = loginEventService
+
+	assert.NotPanics(t, func() {
+		service.recordLoginEvent(context.TODO(), domain.LoginEventRefresh, nil, "", "invalid refresh token")
+	})
+	loginEventService.AssertExpectations(t)
+}
@@ -3,7 +3,9 @@ package handler
 import (
 	"context"
 	"errors"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/plagioriginal/user-microservice/domain"
@@ -11,12 +13,14 @@ import (
 	users "github.com/plagioriginal/users-service-grpc/users"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
 func TestLogin_InvalidInput(t *testing.T) {
-	service := newHandler(nil, nil)
+	service := newHandler(nil, nil, nil)
 	res, err := service.Login(context.TODO(), nil)
 	assert.Nil(t, res)
 	assert.Error(t, err)
@@ -28,9 +32,60 @@ func TestLogin_InvalidInput(t *testing.T) {
 	assert.Equal(t, err, status.Error(codes.NotFound, "resource found"))
 }
 
+func TestLogin_ErrorCheckingLoginAttempts(t *testing.T) {
+	loginAttemptService := new(mocks.LoginAttemptService)
+	service := newHandler(nil, nil, loginAttemptService)
+
+	loginAttemptService.On("GetRetryAfter", mock.Anything, "username", "").
+		Once().Return(time.Duration(0), errors.New("boom"))
+
+	res, err := service.Login(context.TODO(), &users.LoginRequest{
+		Username: "username",
+		Password: "password",
+	})
+	assert.Nil(t, res)
+	assert.Error(t, err)
+	assert.Equal(t, err, status.Error(codes.Internal, "error checking login attempts"))
+	loginAttemptService.AssertExpectations(t)
+}
+
+func TestLogin_LockedOut(t *testing.T) {
+	loginAttemptService := new(mocks.LoginAttemptService)
+	service := newHandler(nil, nil, loginAttemptService)
+	loginEventService := withLoginEvents(&service)
+
+	ctx := peer.NewContext(context.TODO(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 5432}})
+	loginAttemptService.On("GetRetryAfter", mock.Anything, "username", "203.0.113.7").
+		Once().Return(90*time.Second+time.Millisecond, nil)
+
+	res, err := service.Login(ctx, &users.LoginRequest{
+		Username: "username",
+		Password: "password",
+	})
+	assert.Nil(t, res)
+	assert.Error(t, err)
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	assert.Equal(t, "too many login attempts, retry after 91 seconds", st.Message())
+	assert.Len(t, st.Details(), 1)
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	assert.True(t, ok)
+	assert.Equal(t, 91*time.Second, retryInfo.RetryDelay.AsDuration())
+	loginAttemptService.AssertExpectations(t)
+	assertLoginEventRecorded(t, loginEventService, domain.LoginEventLogin, uuid.Nil, "too many attempts")
+}
+
 func TestLogin_ErrorGettingTheUserByLogin(t *testing.T) {
 	userService := new(mocks.UserService)
-	service := newHandler(nil, userService)
+	loginAttemptService := new(mocks.LoginAttemptService)
+	service := newHandler(nil, userService, loginAttemptService)
+	loginEventService := withLoginEvents(&service)
+
+	loginAttemptService.On("GetRetryAfter", mock.Anything, "username", "").
+		Once().Return(time.Duration(0), nil)
+	loginAttemptService.On("RegisterFailure", mock.Anything, "username", "").
+		Once().Return(time.Duration(0), nil)
 
 	userService.On("GetUserByLogin", mock.Anything, domain.GetUserRequest{
 		Username: "username",
@@ -45,17 +100,56 @@ func TestLogin_ErrorGettingTheUserByLogin(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, err, status.Error(codes.NotFound, "resource found"))
 	userService.AssertExpectations(t)
+	loginAttemptService.AssertExpectations(t)
+	assertLoginEventRecorded(t, loginEventService, domain.LoginEventLogin, uuid.Nil, "invalid credentials")
+}
+
+func TestLogin_FailureTriggersLockout(t *testing.T) {
+	userService := new(mocks.UserService)
+	loginAttemptService := new(mocks.LoginAttemptService)
+	service := newHandler(nil, userService, loginAttemptService)
+	withLoginEvents(&service)
+
+	loginAttemptService.On("GetRetryAfter", mock.Anything, "username", "").
+		Once().Return(time.Duration(0), nil)
+	loginAttemptService.On("RegisterFailure", mock.Anything, "username", "").
+		Once().Return(time.Minute, nil)
+
+	userService.On("GetUserByLogin", mock.Anything, domain.GetUserRequest{
+		Username: "username",
+		Password: "password",
+	}).Once().Return(nil, errors.New("boom"))
+
+	res, err := service.Login(context.TODO(), &users.LoginRequest{
+		Username: "username",
+		Password: "password",
+	})
+	assert.Nil(t, res)
+	assert.Error(t, err)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	userService.AssertExpectations(t)
+	loginAttemptService.AssertExpectations(t)
 }
 
 func TestLogin_ErrorGeneratingToken(t *testing.T) {
 	userService := new(mocks.UserService)
 	tokenHandler := new(mocks.AccessTokenHandler)
-	service := newHandler(tokenHandler, userService)
+	loginAttemptService := new(mocks.LoginAttemptService)
+	mfaService := new(mocks.MFAService)
+	service := newHandler(tokenHandler, userService, loginAttemptService)
+	withLoginEvents(&service)
+	service.mfaService = mfaService
+
+	loginAttemptService.On("GetRetryAfter", mock.Anything, "username", "").
+		Once().Return(time.Duration(0), nil)
+	loginAttemptService.On("RegisterSuccess", mock.Anything, "username").
+		Once().Return(nil)
 
 	userService.On("GetUserByLogin", mock.Anything, domain.GetUserRequest{
 		Username: "username",
 		Password: "password",
 	}).Once().Return(&domain.User{}, nil)
+	mfaService.On("IsEnrolled", mock.Anything, uuid.Nil).Once().Return(false, nil)
 
 	tokenHandler.On("GenerateTokens", mock.Anything, &domain.User{}).Once().
 		Return(domain.TokenResponse{}, errors.New("boom"))
@@ -69,12 +163,22 @@ func TestLogin_ErrorGeneratingToken(t *testing.T) {
 	assert.Equal(t, err, status.Error(codes.Internal, "error generating tokens"))
 	userService.AssertExpectations(t)
 	tokenHandler.AssertExpectations(t)
+	loginAttemptService.AssertExpectations(t)
 }
 
 func TestLogin_Success(t *testing.T) {
 	userService := new(mocks.UserService)
 	tokenHandler := new(mocks.AccessTokenHandler)
-	service := newHandler(tokenHandler, userService)
+	loginAttemptService := new(mocks.LoginAttemptService)
+	mfaService := new(mocks.MFAService)
+	service := newHandler(tokenHandler, userService, loginAttemptService)
+	loginEventService := withLoginEvents(&service)
+	service.mfaService = mfaService
+
+	loginAttemptService.On("GetRetryAfter", mock.Anything, "username", "").
+		Once().Return(time.Duration(0), nil)
+	loginAttemptService.On("RegisterSuccess", mock.Anything, "username").
+		Once().Return(nil)
 
 	userId := uuid.New()
 	roleId := uuid.New()
@@ -94,6 +198,7 @@ func TestLogin_Success(t *testing.T) {
 		Username: "username",
 		Password: "password",
 	}).Once().Return(user, nil)
+	mfaService.On("IsEnrolled", mock.Anything, userId).Once().Return(false, nil)
 
 	tokenHandler.On("GenerateTokens", mock.Anything, user).Once().
 		Return(domain.TokenResponse{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil)
@@ -120,4 +225,166 @@ func TestLogin_Success(t *testing.T) {
 	assert.Nil(t, err)
 	userService.AssertExpectations(t)
 	tokenHandler.AssertExpectations(t)
+	loginAttemptService.AssertExpectations(t)
+	assertLoginEventRecorded(t, loginEventService, domain.LoginEventLogin, userId, "")
+}
+
+func TestLogin_MFA(t *testing.T) {
+	tests := []struct {
+		name        string
+		enrolled    bool
+		requiresMFA bool
+		wantedRes   *users.TokenResponse
+	}{
+		{
+			name:      "enrolled user",
+			enrolled:  true,
+			wantedRes: &users.TokenResponse{MfaRequired: true, MfaToken: "mfa-token"},
+		},
+		{
+			name:        "role requires mfa",
+			requiresMFA: true,
+			wantedRes:   &users.TokenResponse{MfaRequired: true, MfaToken: "mfa-token", MfaEnrollmentRequired: true},
+		},
+		{
+			name:        "enrolled user with a role requiring mfa",
+			enrolled:    true,
+			requiresMFA: true,
+			wantedRes:   &users.TokenResponse{MfaRequired: true, MfaToken: "mfa-token"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			userService := new(mocks.UserService)
+			tokenHandler := new(mocks.AccessTokenHandler)
+			loginAttemptService := new(mocks.LoginAttemptService)
+			mfaService := new(mocks.MFAService)
+			service := newHandler(tokenHandler, userService, loginAttemptService)
+			withLoginEvents(&service)
+			service.mfaService = mfaService
+
+			user := &domain.User{
+				ID:       uuid.New(),
+				Username: "username",
+				Role:     &domain.Role{RoleSlug: "admin", RequiresMFA: test.requiresMFA},
+			}
+
+			loginAttemptService.On("GetRetryAfter", mock.Anything, "username", "").
+				Once().Return(time.Duration(0), nil)
+			loginAttemptService.On("RegisterSuccess", mock.Anything, "username").
+				Once().Return(nil)
+			userService.On("GetUserByLogin", mock.Anything, mock.AnythingOfType("domain.GetUserRequest")).
+				Once().Return(user, nil)
+			mfaService.On("IsEnrolled", mock.Anything, user.ID).Once().Return(test.enrolled, nil)
+			tokenHandler.On("GenerateMFAChallenge", user).Once().Return("mfa-token", nil)
+
+			res, err := service.Login(context.TODO(), &users.LoginRequest{
+				Username: "username",
+				Password: "password",
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, test.wantedRes, res)
+			tokenHandler.AssertNotCalled(t, "GenerateTokens", mock.Anything, mock.Anything)
+			tokenHandler.AssertExpectations(t)
+			mfaService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLogin_PasswordChangeRequired(t *testing.T) {
+	tests := []struct {
+		name string
+		user domain.User
+	}{
+		{
+			name: "user flagged to change its password",
+			user: domain.User{Password: "hash", MustChangePassword: true, PasswordChangedAt: time.Now(), Role: &domain.Role{RoleSlug: "admin"}},
+		},
+		{
+			name: "password older than the role allows",
+			user: domain.User{Password: "hash", PasswordChangedAt: time.Now().AddDate(0, 0, -91), Role: &domain.Role{RoleSlug: "admin", PasswordMaxAgeDays: 90}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			userService := new(mocks.UserService)
+			tokenHandler := new(mocks.AccessTokenHandler)
+			loginAttemptService := new(mocks.LoginAttemptService)
+			mfaService := new(mocks.MFAService)
+			service := newHandler(tokenHandler, userService, loginAttemptService)
+			withLoginEvents(&service)
+			service.mfaService = mfaService
+
+			user := test.user
+			user.ID = uuid.New()
+			user.Username = "username"
+
+			loginAttemptService.On("GetRetryAfter", mock.Anything, "username", "").
+				Once().Return(time.Duration(0), nil)
+			loginAttemptService.On("RegisterSuccess", mock.Anything, "username").
+				Once().Return(nil)
+			userService.On("GetUserByLogin", mock.Anything, mock.AnythingOfType("domain.GetUserRequest")).
+				Once().Return(&user, nil)
+			tokenHandler.On("GeneratePasswordChangeToken", &user).Once().Return("password-change-token", nil)
+
+			res, err := service.Login(context.TODO(), &users.LoginRequest{
+				Username: "username",
+				Password: "password",
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, &users.TokenResponse{PasswordChangeRequired: true, PasswordChangeToken: "password-change-token"}, res)
+			tokenHandler.AssertNotCalled(t, "GenerateTokens", mock.Anything, mock.Anything)
+			mfaService.AssertNotCalled(t, "IsEnrolled", mock.Anything, mock.Anything)
+			tokenHandler.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLogin_ErrorCheckingMFA(t *testing.T) {
+	userService := new(mocks.UserService)
+	loginAttemptService := new(mocks.LoginAttemptService)
+	mfaService := new(mocks.MFAService)
+	service := newHandler(nil, userService, loginAttemptService)
+	withLoginEvents(&service)
+	service.mfaService = mfaService
+
+	user := &domain.User{ID: uuid.New(), Username: "username"}
+	loginAttemptService.On("GetRetryAfter", mock.Anything, "username", "").
+		Once().Return(time.Duration(0), nil)
+	loginAttemptService.On("RegisterSuccess", mock.Anything, "username").
+		Once().Return(nil)
+	userService.On("GetUserByLogin", mock.Anything, mock.AnythingOfType("domain.GetUserRequest")).
+		Once().Return(user, nil)
+	mfaService.On("IsEnrolled", mock.Anything, user.ID).Once().Return(false, errors.New("boom"))
+
+	res, err := service.Login(context.TODO(), &users.LoginRequest{
+		Username: "username",
+		Password: "password",
+	})
+	assert.Nil(t, res)
+	assert.Equal(t, status.Error(codes.Internal, "error checking mfa"), err)
+	mfaService.AssertExpectations(t)
+}
+
+func TestLogin_UserNotActive(t *testing.T) {
+	userService := new(mocks.UserService)
+	loginAttemptService := new(mocks.LoginAttemptService)
+	service := newHandler(nil, userService, loginAttemptService)
+	withLoginEvents(&service)
+
+	loginAttemptService.On("GetRetryAfter", mock.Anything, "username", "").
+		Once().Return(time.Duration(0), nil)
+	userService.On("GetUserByLogin", mock.Anything, mock.AnythingOfType("domain.GetUserRequest")).
+		Once().Return(nil, domain.ErrUserNotActive)
+
+	res, err := service.Login(context.TODO(), &users.LoginRequest{
+		Username: "username",
+		Password: "password",
+	})
+	assert.Nil(t, res)
+	assert.Equal(t, status.Error(codes.PermissionDenied, "user is not active"), err)
+	userService.AssertExpectations(t)
+	loginAttemptService.AssertExpectations(t)
 }
@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 
+	"github.com/plagioriginal/user-microservice/domain"
 	users "github.com/plagioriginal/users-service-grpc/users"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -15,11 +16,14 @@ func (srv UserGRPCHandler) Logout(ctx context.Context, in *users.RefreshRequest)
 		return nil, status.Error(codes.InvalidArgument, "invalid input")
 	}
 
-	isDeleted := srv.tokenManager.DeleteRefreshToken(ctx, in.RefreshToken)
+	user, isDeleted := srv.tokenManager.DeleteRefreshToken(ctx, in.RefreshToken)
 
+	reason := ""
 	if !isDeleted {
 		srv.l.Println("error deleting token: " + in.RefreshToken)
+		reason = "invalid refresh token"
 	}
+	srv.recordLoginEvent(ctx, domain.LoginEventLogout, user, "", reason)
 
 	return &users.TokenResponse{
 		AccessToken:  "",
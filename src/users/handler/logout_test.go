@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
 	"github.com/plagioriginal/user-microservice/domain/mocks"
 	users "github.com/plagioriginal/users-service-grpc/users"
 	"github.com/stretchr/testify/assert"
@@ -13,7 +15,7 @@ import (
 )
 
 func TestLogout_InvalidInput(t *testing.T) {
-	service := newHandler(nil, nil)
+	service := newHandler(nil, nil, nil)
 	res, err := service.Logout(context.TODO(), nil)
 	assert.Nil(t, res)
 	assert.Error(t, err)
@@ -26,14 +28,30 @@ func TestLogout_InvalidInput(t *testing.T) {
 }
 
 func TestLogout_Success(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice"}
 	tokenManager := new(mocks.AccessTokenHandler)
-	service := newHandler(tokenManager, nil)
+	service := newHandler(tokenManager, nil, nil)
+	loginEventService := withLoginEvents(&service)
 
-	tokenManager.On("DeleteRefreshToken", mock.Anything, "cenas").Once().Return(true)
+	tokenManager.On("DeleteRefreshToken", mock.Anything, "cenas").Once().Return(user, true)
 	res, err := service.Logout(context.TODO(), &users.RefreshRequest{RefreshToken: "cenas"})
 	assert.Nil(t, err)
 	assert.Equal(t, res, &users.TokenResponse{
 		AccessToken:  "",
 		RefreshToken: "",
 	})
+	assertLoginEventRecorded(t, loginEventService, domain.LoginEventLogout, user.ID, "")
+}
+
+func TestLogout_UnknownRefreshToken(t *testing.T) {
+	tokenManager := new(mocks.AccessTokenHandler)
+	service := newHandler(tokenManager, nil, nil)
+	loginEventService := withLoginEvents(&service)
+
+	// Logging out always succeeds, but the failure is recorded.
+	tokenManager.On("DeleteRefreshToken", mock.Anything, "cenas").Once().Return(nil, false)
+	res, err := service.Logout(context.TODO(), &users.RefreshRequest{RefreshToken: "cenas"})
+	assert.Nil(t, err)
+	assert.NotNil(t, res)
+	assertLoginEventRecorded(t, loginEventService, domain.LoginEventLogout, uuid.Nil, "invalid refresh token")
 }
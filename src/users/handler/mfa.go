@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Gets the MFA challenge to send instead of the tokens, when the user is enrolled
+// or their role requires MFA. Returns nil when the tokens can be sent right away.
+func (srv UserGRPCHandler) mfaChallenge(ctx context.Context, user *domain.User) (*users.TokenResponse, error) {
+	enrolled, err := srv.mfaService.IsEnrolled(ctx, user.ID)
+	if err != nil {
+		srv.l.Printf("error checking the mfa enrollment: %v\n", err)
+		return nil, status.Error(codes.Internal, "error checking mfa")
+	}
+
+	requiresMFA := user.Role != nil && user.Role.RequiresMFA
+	if !enrolled && !requiresMFA {
+		return nil, nil
+	}
+
+	challenge, err := srv.tokenManager.GenerateMFAChallenge(user)
+	if err != nil {
+		srv.l.Printf("error generating the mfa challenge: %v\n", err)
+		return nil, status.Error(codes.Internal, "error generating tokens")
+	}
+
+	return &users.TokenResponse{
+		MfaRequired:           true,
+		MfaToken:              challenge,
+		MfaEnrollmentRequired: !enrolled,
+	}, nil
+}
+
+// Gets the user enrolling in MFA, either logged in or in the middle of a login
+// that requires MFA, with the context scoped to its organization. Also returns if it was the latter.
+func (srv UserGRPCHandler) enrollingUser(ctx context.Context, accessToken string, mfaToken string) (context.Context, *domain.User, bool, error) {
+	viaChallenge := len(accessToken) == 0 && len(mfaToken) > 0
+
+	var userID uuid.UUID
+	var err error
+	if viaChallenge {
+		var organizationID uuid.UUID
+		userID, organizationID, err = srv.tokenManager.GetUserIDFromMFAChallenge(mfaToken)
+		if err != nil {
+			return ctx, nil, false, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		ctx = domain.WithOrganization(ctx, organizationID)
+	} else {
+		ctx, userID, err = srv.authenticate(ctx, accessToken)
+		if err != nil {
+			return ctx, nil, false, err
+		}
+	}
+
+	user, err := srv.userService.GetUserByUUID(ctx, userID)
+	if err != nil {
+		srv.l.Printf("error getting the user to enroll: %v\n", err)
+		return ctx, nil, false, status.Error(codes.NotFound, "user not found")
+	}
+	if !user.IsActive() {
+		return ctx, nil, false, status.Error(codes.PermissionDenied, "user is not active")
+	}
+	return ctx, user, viaChallenge, nil
+}
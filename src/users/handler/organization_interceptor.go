@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata key with the ID of the organization a request is for.
+const organizationMetadataKey = "x-organization-id"
+
+// Scopes the unary requests to the organization in their metadata, the default one when there is none.
+// Requests with an access token are scoped to the organization of the token instead.
+func OrganizationUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := organizationFromMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// Scopes the streams to the organization in their metadata, the default one when there is none.
+func OrganizationStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := organizationFromMetadata(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, organizationStream{ss, ctx})
+}
+
+// Stream with the context scoped to an organization.
+type organizationStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s organizationStream) Context() context.Context {
+	return s.ctx
+}
+
+// Gets the context scoped to the organization in the metadata of a request.
+func organizationFromMetadata(ctx context.Context) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+
+	values := md.Get(organizationMetadataKey)
+	if len(values) == 0 || len(values[0]) == 0 {
+		return ctx, nil
+	}
+
+	organizationID, err := uuid.Parse(values[0])
+	if err != nil {
+		return ctx, status.Error(codes.InvalidArgument, "invalid organization")
+	}
+	return domain.WithOrganization(ctx, organizationID), nil
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Handler returning the organization its context is scoped to.
+func organizationHandler(ctx context.Context, req interface{}) (interface{}, error) {
+	return domain.OrganizationFromContext(ctx), nil
+}
+
+func TestOrganizationUnaryInterceptor_WithoutMetadata(t *testing.T) {
+	res, err := OrganizationUnaryInterceptor(context.TODO(), nil, &grpc.UnaryServerInfo{}, organizationHandler)
+	assert.Nil(t, err)
+	assert.Equal(t, domain.DefaultOrganizationID, res)
+}
+
+func TestOrganizationUnaryInterceptor_InvalidOrganization(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs("x-organization-id", "cenas"))
+
+	res, err := OrganizationUnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, organizationHandler)
+	assert.Nil(t, res)
+	assert.Equal(t, status.Error(codes.InvalidArgument, "invalid organization"), err)
+}
+
+func TestOrganizationUnaryInterceptor_Success(t *testing.T) {
+	organizationID := uuid.New()
+	ctx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs("x-organization-id", organizationID.String()))
+
+	res, err := OrganizationUnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, organizationHandler)
+	assert.Nil(t, err)
+	assert.Equal(t, organizationID, res)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Merges a JSON object into the custom attributes of the logged in user, or of any user for administrators.
+// Users can only change the attributes defined as user editable.
+func (srv UserGRPCHandler) PatchUserAttributes(ctx context.Context, in *users.PatchUserAttributesRequest) (*users.UserAttributesResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, userID, isAdmin, err := srv.targetUser(ctx, in.AccessToken, in.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Version <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing version")
+	}
+
+	patch := domain.Attributes{}
+	if err = json.Unmarshal([]byte(in.AttributesJson), &patch); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "attributes must be a JSON object")
+	}
+
+	user, err := srv.attributeService.Patch(ctx, userID, int(in.Version), patch, isAdmin)
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrNotAllowed):
+		return nil, status.Error(codes.PermissionDenied, "attribute can only be changed by administrators")
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, domain.ErrVersionConflict):
+		return nil, errUserVersionConflict
+	case err != nil:
+		srv.l.Printf("error patching the attributes: %v\n", err)
+		return nil, status.Error(codes.Internal, "error patching attributes")
+	}
+
+	result, err := userAttributesResponse(user)
+	if err != nil {
+		srv.l.Printf("error encoding the attributes: %v\n", err)
+		return nil, status.Error(codes.Internal, "error patching attributes")
+	}
+	return result, nil
+}
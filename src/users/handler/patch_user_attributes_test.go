@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPatchUserAttributes_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.PatchUserAttributes(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestPatchUserAttributes_InvalidJSON(t *testing.T) {
+	service, _, _ := newAttributesHandler(uuid.New(), "user")
+
+	res, err := service.PatchUserAttributes(context.TODO(), &users.PatchUserAttributesRequest{AccessToken: "cenas", Version: 1, AttributesJson: `["timezone"]`})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "attributes must be a JSON object"))
+}
+
+func TestPatchUserAttributes_MissingVersion(t *testing.T) {
+	service, _, attributeService := newAttributesHandler(uuid.New(), "user")
+
+	res, err := service.PatchUserAttributes(context.TODO(), &users.PatchUserAttributesRequest{AccessToken: "cenas", AttributesJson: `{"timezone":"UTC"}`})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "missing version"))
+	attributeService.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchUserAttributes_ServiceErrors(t *testing.T) {
+	wrongType := fmt.Errorf("%w: attribute %q must be a number", domain.ErrBadParamInput, "seniority")
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"invalid attributes": {wrongType, status.Error(codes.InvalidArgument, wrongType.Error())},
+		"not user editable":  {domain.ErrNotAllowed, status.Error(codes.PermissionDenied, "attribute can only be changed by administrators")},
+		"user not found":     {domain.ErrNotFound, status.Error(codes.NotFound, "user not found")},
+		"version conflict":   {domain.ErrVersionConflict, errUserVersionConflict},
+		"unexpected error":   {errors.New("boom"), status.Error(codes.Internal, "error patching attributes")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			userID := uuid.New()
+			service, _, attributeService := newAttributesHandler(userID, "user")
+			attributeService.On("Patch", mock.Anything, userID, 1, domain.Attributes{"seniority": "senior"}, false).Once().Return(nil, c.serviceErr)
+
+			res, err := service.PatchUserAttributes(context.TODO(), &users.PatchUserAttributesRequest{AccessToken: "cenas", Version: 1, AttributesJson: `{"seniority":"senior"}`})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			attributeService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPatchUserAttributes_Success(t *testing.T) {
+	t.Run("own attributes", func(t *testing.T) {
+		userID := uuid.New()
+		service, _, attributeService := newAttributesHandler(userID, "user")
+		attributeService.On("Patch", mock.Anything, userID, 1, domain.Attributes{"timezone": "UTC", "locale": nil}, false).Once().
+			Return(&domain.User{ID: userID, Version: 2, Attributes: domain.Attributes{"timezone": "UTC"}}, nil)
+
+		res, err := service.PatchUserAttributes(context.TODO(), &users.PatchUserAttributesRequest{
+			AccessToken:    "cenas",
+			UserId:         userID.String(),
+			Version:        1,
+			AttributesJson: `{"timezone":"UTC","locale":null}`,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, &users.UserAttributesResponse{UserId: userID.String(), Version: 2, AttributesJson: `{"timezone":"UTC"}`}, res)
+		attributeService.AssertExpectations(t)
+	})
+
+	t.Run("administrator patching another user", func(t *testing.T) {
+		userID := uuid.New()
+		service, _, attributeService := newAttributesHandler(uuid.New(), "admin")
+		attributeService.On("Patch", mock.Anything, userID, 1, domain.Attributes{"department": "sales"}, true).Once().
+			Return(&domain.User{ID: userID, Version: 2, Attributes: domain.Attributes{"department": "sales"}}, nil)
+
+		res, err := service.PatchUserAttributes(context.TODO(), &users.PatchUserAttributesRequest{
+			AccessToken:    "cenas",
+			UserId:         userID.String(),
+			Version:        1,
+			AttributesJson: `{"department":"sales"}`,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, &users.UserAttributesResponse{UserId: userID.String(), Version: 2, AttributesJson: `{"department":"sales"}`}, res)
+		attributeService.AssertExpectations(t)
+	})
+}
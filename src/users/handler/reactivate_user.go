@@ -0,0 +1,14 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+)
+
+// Reactivates a suspended or deactivated user, so they can log in again.
+// Only for administrators.
+func (srv UserGRPCHandler) ReactivateUser(ctx context.Context, in *users.UpdateUserStatusRequest) (*users.UserResponse, error) {
+	return srv.changeUserStatus(ctx, in, domain.UserStatusActive)
+}
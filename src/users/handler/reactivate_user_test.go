@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReactivateUser_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.ReactivateUser(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestReactivateUser_NotSuspended(t *testing.T) {
+	userID := uuid.New()
+	userService := new(mocks.UserService)
+	service, _ := newAdminHandler(userService)
+	userService.On("ChangeStatus", mock.Anything, userID, 1, domain.UserStatusActive, "").
+		Once().Return(nil, domain.ErrNotAllowed)
+
+	res, err := service.ReactivateUser(context.TODO(), &users.UpdateUserStatusRequest{
+		AccessToken: "cenas",
+		UserId:      userID.String(),
+		Version:     1,
+	})
+	assert.Nil(t, res)
+	assert.Equal(t, status.Error(codes.FailedPrecondition, "invalid status transition"), err)
+	userService.AssertExpectations(t)
+}
+
+func TestReactivateUser_Success(t *testing.T) {
+	userID := uuid.New()
+	userService := new(mocks.UserService)
+	service, accessTokenManager := newAdminHandler(userService)
+	userService.On("ChangeStatus", mock.Anything, userID, 1, domain.UserStatusActive, "appeal accepted").
+		Once().Return(&domain.User{
+		ID:           userID,
+		Username:     "alice",
+		Status:       domain.UserStatusActive,
+		StatusReason: "appeal accepted",
+	}, nil)
+
+	res, err := service.ReactivateUser(context.TODO(), &users.UpdateUserStatusRequest{
+		AccessToken: "cenas",
+		UserId:      userID.String(),
+		Version:     1,
+		Reason:      "appeal accepted",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "active", res.Status)
+	assert.Equal(t, "appeal accepted", res.StatusReason)
+	accessTokenManager.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
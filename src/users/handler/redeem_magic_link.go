@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Logs a user in with the token of a login link, from the device that asked for it.
+func (srv UserGRPCHandler) RedeemMagicLink(ctx context.Context, in *users.RedeemMagicLinkRequest) (*users.TokenResponse, error) {
+	if in == nil || len(in.Token) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	user, err := srv.magicLinkService.Redeem(ctx, in.GetToken(), in.GetNonce())
+	switch {
+	case errors.Is(err, domain.ErrInvalidToken):
+		return nil, status.Error(codes.InvalidArgument, "invalid token")
+	case errors.Is(err, domain.ErrUserNotActive):
+		return nil, status.Error(codes.PermissionDenied, "user is not active")
+	case err != nil:
+		srv.l.Printf("error redeeming a magic link: %v\n", err)
+		return nil, status.Error(codes.Internal, "error redeeming magic link")
+	}
+
+	// The link doesn't replace changing the password when it is required.
+	challenge, err := srv.passwordChangeChallenge(user)
+	if err != nil || challenge != nil {
+		return challenge, err
+	}
+
+	// The link only replaces the password, the second factor is still needed.
+	challenge, err = srv.mfaChallenge(ctx, user)
+	if err != nil || challenge != nil {
+		return challenge, err
+	}
+
+	token, err := srv.tokenManager.GenerateTokens(ctx, user)
+	if err != nil {
+		srv.l.Printf("error generating tokens on magic link: %v\n", err)
+		return nil, status.Error(codes.Internal, "error generating tokens")
+	}
+	srv.recordLoginEvent(ctx, domain.LoginEventLogin, user, "", "")
+
+	return &users.TokenResponse{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		User:         userResponse(user),
+	}, nil
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRedeemMagicLink_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+
+	for _, req := range []*users.RedeemMagicLinkRequest{nil, {Nonce: "device-nonce"}} {
+		res, err := service.RedeemMagicLink(context.TODO(), req)
+		assert.Nil(t, res)
+		assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+	}
+}
+
+func TestRedeemMagicLink_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"invalid token":    {domain.ErrInvalidToken, status.Error(codes.InvalidArgument, "invalid token")},
+		"inactive user":    {domain.ErrUserNotActive, status.Error(codes.PermissionDenied, "user is not active")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error redeeming magic link")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			service := newHandler(nil, nil, nil)
+			magicLinkService := new(mocks.MagicLinkService)
+			service.magicLinkService = magicLinkService
+			magicLinkService.On("Redeem", mock.Anything, "the-token", "device-nonce").Once().Return(nil, c.serviceErr)
+
+			res, err := service.RedeemMagicLink(context.TODO(), &users.RedeemMagicLinkRequest{Token: "the-token", Nonce: "device-nonce"})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+		})
+	}
+}
+
+func TestRedeemMagicLink_MFA(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	tokenHandler := new(mocks.AccessTokenHandler)
+	service := newHandler(tokenHandler, nil, nil)
+	magicLinkService := new(mocks.MagicLinkService)
+	service.magicLinkService = magicLinkService
+	mfaService := new(mocks.MFAService)
+	service.mfaService = mfaService
+
+	magicLinkService.On("Redeem", mock.Anything, "the-token", "").Once().Return(user, nil)
+	mfaService.On("IsEnrolled", mock.Anything, user.ID).Once().Return(true, nil)
+	tokenHandler.On("GenerateMFAChallenge", user).Once().Return("mfa-token", nil)
+
+	res, err := service.RedeemMagicLink(context.TODO(), &users.RedeemMagicLinkRequest{Token: "the-token"})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.TokenResponse{MfaRequired: true, MfaToken: "mfa-token"}, res)
+	tokenHandler.AssertNotCalled(t, "GenerateTokens", mock.Anything, mock.Anything)
+}
+
+func TestRedeemMagicLink_PasswordChangeRequired(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive, Password: "hash", MustChangePassword: true}
+	tokenHandler := new(mocks.AccessTokenHandler)
+	service := newHandler(tokenHandler, nil, nil)
+	magicLinkService := new(mocks.MagicLinkService)
+	service.magicLinkService = magicLinkService
+	mfaService := new(mocks.MFAService)
+	service.mfaService = mfaService
+
+	magicLinkService.On("Redeem", mock.Anything, "the-token", "").Once().Return(user, nil)
+	tokenHandler.On("GeneratePasswordChangeToken", user).Once().Return("password-change-token", nil)
+
+	res, err := service.RedeemMagicLink(context.TODO(), &users.RedeemMagicLinkRequest{Token: "the-token"})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.TokenResponse{PasswordChangeRequired: true, PasswordChangeToken: "password-change-token"}, res)
+	tokenHandler.AssertNotCalled(t, "GenerateTokens", mock.Anything, mock.Anything)
+	mfaService.AssertNotCalled(t, "IsEnrolled", mock.Anything, mock.Anything)
+	tokenHandler.AssertExpectations(t)
+}
+
+func TestRedeemMagicLink_Success(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	tokenHandler := new(mocks.AccessTokenHandler)
+	service := newHandler(tokenHandler, nil, nil)
+	loginEventService := withLoginEvents(&service)
+	magicLinkService := new(mocks.MagicLinkService)
+	service.magicLinkService = magicLinkService
+	mfaService := new(mocks.MFAService)
+	service.mfaService = mfaService
+
+	magicLinkService.On("Redeem", mock.Anything, "the-token", "device-nonce").Once().Return(user, nil)
+	mfaService.On("IsEnrolled", mock.Anything, user.ID).Once().Return(false, nil)
+	tokenHandler.On("GenerateTokens", mock.Anything, user).Once().
+		Return(domain.TokenResponse{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil)
+
+	res, err := service.RedeemMagicLink(context.TODO(), &users.RedeemMagicLinkRequest{Token: "the-token", Nonce: "device-nonce"})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.TokenResponse{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+		User:         &users.UserResponse{Id: user.ID.String(), Username: "alice", Status: "active"},
+	}, res)
+	tokenHandler.AssertExpectations(t)
+	assertLoginEventRecorded(t, loginEventService, domain.LoginEventLogin, user.ID, "")
+}
@@ -2,8 +2,10 @@ package handler
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
 	users "github.com/plagioriginal/users-service-grpc/users"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -22,24 +24,23 @@ func (srv UserGRPCHandler) Refresh(ctx context.Context, in *users.RefreshRequest
 	}
 
 	tokens, err := srv.tokenManager.RefreshAllTokens(ctx, oldRefreshToken)
+	if errors.Is(err, domain.ErrUserNotActive) {
+		srv.recordLoginEvent(ctx, domain.LoginEventRefresh, &tokens.User, "", "user is not active")
+		return nil, status.Error(codes.PermissionDenied, "user is not active")
+	}
+	// The session ends until the password is changed, which gives new tokens.
+	if errors.Is(err, domain.ErrPasswordChangeRequired) {
+		return srv.passwordChangeChallenge(&tokens.User)
+	}
 	if err != nil {
 		srv.l.Printf("error generating tokens on refresh: %v\n", err)
+		srv.recordLoginEvent(ctx, domain.LoginEventRefresh, nil, "", "invalid refresh token")
 		return nil, status.Error(codes.Internal, "error generating tokens")
 	}
-	user := tokens.User
+	srv.recordLoginEvent(ctx, domain.LoginEventRefresh, &tokens.User, "", "")
 	return &users.TokenResponse{
 		AccessToken:  tokens.AccessToken,
 		RefreshToken: tokens.RefreshToken,
-		User: &users.UserResponse{
-			Id:        user.ID.String(),
-			Username:  user.Username,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Role: &users.UserResponse_RoleResponse{
-				Id:        user.RoleId.String(),
-				RoleLabel: user.Role.RoleLabel,
-				RoleSlug:  user.Role.RoleSlug,
-			},
-		},
+		User:         userResponse(&tokens.User),
 	}, nil
 }
@@ -16,7 +16,7 @@ import (
 )
 
 func TestRefresh_InvalidInput(t *testing.T) {
-	service := newHandler(nil, nil)
+	service := newHandler(nil, nil, nil)
 	res, err := service.Refresh(context.TODO(), nil)
 	assert.Nil(t, res)
 	assert.Error(t, err)
@@ -29,7 +29,7 @@ func TestRefresh_InvalidInput(t *testing.T) {
 }
 
 func TestRefresh_ErrorParsingOldToken(t *testing.T) {
-	service := newHandler(nil, nil)
+	service := newHandler(nil, nil, nil)
 	res, err := service.Refresh(context.TODO(), &users.RefreshRequest{
 		RefreshToken: "cenas",
 	})
@@ -40,7 +40,8 @@ func TestRefresh_ErrorParsingOldToken(t *testing.T) {
 
 func TestRefresh_ErrorRefreshingTokens(t *testing.T) {
 	tokenHandler := new(mocks.AccessTokenHandler)
-	service := newHandler(tokenHandler, nil)
+	service := newHandler(tokenHandler, nil, nil)
+	loginEventService := withLoginEvents(&service)
 
 	oldRefreshToken, err := uuid.Parse("a20b5aec-7000-4828-ad56-9d30675a49f2")
 	assert.Nil(t, err)
@@ -56,12 +57,57 @@ func TestRefresh_ErrorRefreshingTokens(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, err, status.Error(codes.Internal, "error generating tokens"))
 	tokenHandler.AssertExpectations(t)
+	assertLoginEventRecorded(t, loginEventService, domain.LoginEventRefresh, uuid.Nil, "invalid refresh token")
+}
+
+func TestRefresh_UserNotActive(t *testing.T) {
+	tokenHandler := new(mocks.AccessTokenHandler)
+	service := newHandler(tokenHandler, nil, nil)
+	loginEventService := withLoginEvents(&service)
+
+	oldRefreshToken, err := uuid.Parse("a20b5aec-7000-4828-ad56-9d30675a49f2")
+	assert.Nil(t, err)
+
+	userID := uuid.New()
+	tokenHandler.On("RefreshAllTokens", mock.Anything, oldRefreshToken).
+		Once().
+		Return(domain.TokenResponse{User: domain.User{ID: userID, Status: domain.UserStatusSuspended}}, domain.ErrUserNotActive)
+
+	res, err := service.Refresh(context.TODO(), &users.RefreshRequest{
+		RefreshToken: "a20b5aec-7000-4828-ad56-9d30675a49f2",
+	})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.PermissionDenied, "user is not active"))
+	tokenHandler.AssertExpectations(t)
+	assertLoginEventRecorded(t, loginEventService, domain.LoginEventRefresh, userID, "user is not active")
+}
+
+func TestRefresh_PasswordChangeRequired(t *testing.T) {
+	tokenHandler := new(mocks.AccessTokenHandler)
+	service := newHandler(tokenHandler, nil, nil)
+
+	oldRefreshToken, err := uuid.Parse("a20b5aec-7000-4828-ad56-9d30675a49f2")
+	assert.Nil(t, err)
+
+	user := domain.User{ID: uuid.New(), Status: domain.UserStatusActive, Password: "hash", MustChangePassword: true}
+	tokenHandler.On("RefreshAllTokens", mock.Anything, oldRefreshToken).
+		Once().
+		Return(domain.TokenResponse{User: user}, domain.ErrPasswordChangeRequired)
+	tokenHandler.On("GeneratePasswordChangeToken", &user).Once().Return("password-change-token", nil)
+
+	res, err := service.Refresh(context.TODO(), &users.RefreshRequest{
+		RefreshToken: "a20b5aec-7000-4828-ad56-9d30675a49f2",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.TokenResponse{PasswordChangeRequired: true, PasswordChangeToken: "password-change-token"}, res)
+	tokenHandler.AssertExpectations(t)
 }
 
 func TestRefresh_Success(t *testing.T) {
 	tokenHandler := new(mocks.AccessTokenHandler)
 	userService := new(mocks.UserService)
-	service := newHandler(tokenHandler, userService)
+	service := newHandler(tokenHandler, userService, nil)
+	loginEventService := withLoginEvents(&service)
 
 	oldRefreshToken, err := uuid.Parse("a20b5aec-7000-4828-ad56-9d30675a49f2")
 	assert.Nil(t, err)
@@ -107,4 +153,5 @@ func TestRefresh_Success(t *testing.T) {
 	assert.Nil(t, err)
 	tokenHandler.AssertExpectations(t)
 	userService.AssertExpectations(t)
+	assertLoginEventRecorded(t, loginEventService, domain.LoginEventRefresh, userId, "")
 }
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Registers a new user, that is signed in right away.
+func (srv UserGRPCHandler) Register(ctx context.Context, in *users.RegisterRequest) (*users.TokenResponse, error) {
+	if in == nil || len(in.Username) == 0 || len(in.Password) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	user, err := srv.registrationService.Register(ctx, domain.RegisterUserRequest{
+		Username: in.GetUsername(),
+		Email:    in.GetEmail(),
+		Password: in.GetPassword(),
+	})
+
+	switch {
+	case errors.Is(err, domain.ErrRegistrationDisabled):
+		return nil, status.Error(codes.PermissionDenied, "registration is disabled")
+	case errors.Is(err, domain.ErrEmailDomainNotAllowed):
+		return nil, status.Error(codes.PermissionDenied, "email domain not allowed")
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	case errors.Is(err, domain.ErrAlreadyExists):
+		return nil, status.Error(codes.AlreadyExists, "username or email already taken")
+	case err != nil:
+		srv.l.Printf("error registering a user: %v\n", err)
+		return nil, status.Error(codes.Internal, "error registering user")
+	}
+
+	// The user is created either way, the email can be sent again later.
+	if len(user.Email) > 0 {
+		if err = srv.emailVerificationService.SendVerificationEmail(ctx, user); err != nil {
+			srv.l.Printf("error sending the verification email: %v\n", err)
+		}
+	}
+
+	// Users of a role requiring MFA have to enroll before getting the tokens.
+	challenge, err := srv.mfaChallenge(ctx, user)
+	if err != nil || challenge != nil {
+		return challenge, err
+	}
+
+	token, err := srv.tokenManager.GenerateTokens(ctx, user)
+	if err != nil {
+		srv.l.Printf("error generating tokens on register: %v\n", err)
+		return nil, status.Error(codes.Internal, "error generating tokens")
+	}
+
+	return &users.TokenResponse{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		User:         userResponse(user),
+	}, nil
+}
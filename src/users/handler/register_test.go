@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRegister_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	invalidRequests := []*users.RegisterRequest{
+		nil,
+		{},
+		{Username: "alice"},
+		{Password: "password"},
+	}
+
+	for _, req := range invalidRequests {
+		res, err := service.Register(context.TODO(), req)
+		assert.Nil(t, res)
+		assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+	}
+}
+
+func TestRegister_ServiceErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantedErr error
+	}{
+		{"disabled", domain.ErrRegistrationDisabled, status.Error(codes.PermissionDenied, "registration is disabled")},
+		{"email domain", domain.ErrEmailDomainNotAllowed, status.Error(codes.PermissionDenied, "email domain not allowed")},
+		{"invalid input", domain.ErrBadParamInput, status.Error(codes.InvalidArgument, "invalid request")},
+		{"already taken", domain.ErrAlreadyExists, status.Error(codes.AlreadyExists, "username or email already taken")},
+		{"unexpected error", errors.New("boom"), status.Error(codes.Internal, "error registering user")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registrationService := new(mocks.RegistrationService)
+			service := newHandler(nil, nil, nil)
+			service.registrationService = registrationService
+
+			registrationService.On("Register", mock.Anything, domain.RegisterUserRequest{
+				Username: "alice",
+				Password: "password",
+			}).Once().Return(nil, test.err)
+
+			res, err := service.Register(context.TODO(), &users.RegisterRequest{
+				Username: "alice",
+				Password: "password",
+			})
+			assert.Nil(t, res)
+			assert.Equal(t, test.wantedErr, err)
+			registrationService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRegister_ErrorGeneratingTokens(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice"}
+	tokenHandler := new(mocks.AccessTokenHandler)
+	registrationService := new(mocks.RegistrationService)
+	mfaService := new(mocks.MFAService)
+	service := newHandler(tokenHandler, nil, nil)
+	service.registrationService = registrationService
+	service.mfaService = mfaService
+
+	registrationService.On("Register", mock.Anything, mock.AnythingOfType("domain.RegisterUserRequest")).
+		Once().Return(user, nil)
+	mfaService.On("IsEnrolled", mock.Anything, user.ID).Once().Return(false, nil)
+	tokenHandler.On("GenerateTokens", mock.Anything, user).Once().
+		Return(domain.TokenResponse{}, errors.New("boom"))
+
+	res, err := service.Register(context.TODO(), &users.RegisterRequest{
+		Username: "alice",
+		Password: "password",
+	})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Internal, "error generating tokens"))
+	tokenHandler.AssertExpectations(t)
+}
+
+func TestRegister_Success(t *testing.T) {
+	userId, roleId := uuid.New(), uuid.New()
+	user := &domain.User{
+		ID:       userId,
+		Username: "alice",
+		Email:    "alice@example.com",
+		RoleId:   roleId,
+		Role: &domain.Role{
+			ID:        roleId,
+			RoleSlug:  "user",
+			RoleLabel: "User",
+		},
+	}
+
+	tokenHandler := new(mocks.AccessTokenHandler)
+	registrationService := new(mocks.RegistrationService)
+	emailVerificationService := new(mocks.EmailVerificationService)
+	service := newHandler(tokenHandler, nil, nil)
+	service.registrationService = registrationService
+	service.emailVerificationService = emailVerificationService
+	mfaService := new(mocks.MFAService)
+	service.mfaService = mfaService
+	mfaService.On("IsEnrolled", mock.Anything, userId).Once().Return(false, nil)
+
+	registrationService.On("Register", mock.Anything, domain.RegisterUserRequest{
+		Username: "alice",
+		Email:    "alice@example.com",
+		Password: "password",
+	}).Once().Return(user, nil)
+	emailVerificationService.On("SendVerificationEmail", mock.Anything, user).
+		Once().Return(errors.New("only logged"))
+	tokenHandler.On("GenerateTokens", mock.Anything, user).Once().
+		Return(domain.TokenResponse{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil)
+
+	res, err := service.Register(context.TODO(), &users.RegisterRequest{
+		Username: "alice",
+		Email:    "alice@example.com",
+		Password: "password",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.TokenResponse{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+		User: &users.UserResponse{
+			Id:       userId.String(),
+			Username: "alice",
+			Email:    "alice@example.com",
+			Role: &users.UserResponse_RoleResponse{
+				Id:        roleId.String(),
+				RoleLabel: "User",
+				RoleSlug:  "user",
+			},
+		},
+	}, res)
+	registrationService.AssertExpectations(t)
+	emailVerificationService.AssertExpectations(t)
+	tokenHandler.AssertExpectations(t)
+}
+
+func TestRegister_RoleRequiresMFA(t *testing.T) {
+	user := &domain.User{
+		ID:       uuid.New(),
+		Username: "alice",
+		Role:     &domain.Role{RoleSlug: "user", RequiresMFA: true},
+	}
+
+	tokenHandler := new(mocks.AccessTokenHandler)
+	registrationService := new(mocks.RegistrationService)
+	mfaService := new(mocks.MFAService)
+	service := newHandler(tokenHandler, nil, nil)
+	service.registrationService = registrationService
+	service.mfaService = mfaService
+
+	registrationService.On("Register", mock.Anything, mock.AnythingOfType("domain.RegisterUserRequest")).
+		Once().Return(user, nil)
+	mfaService.On("IsEnrolled", mock.Anything, user.ID).Once().Return(false, nil)
+	tokenHandler.On("GenerateMFAChallenge", user).Once().Return("mfa-token", nil)
+
+	res, err := service.Register(context.TODO(), &users.RegisterRequest{
+		Username: "alice",
+		Password: "password",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.TokenResponse{
+		MfaRequired:           true,
+		MfaToken:              "mfa-token",
+		MfaEnrollmentRequired: true,
+	}, res)
+	tokenHandler.AssertExpectations(t)
+}
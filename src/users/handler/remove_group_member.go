@@ -0,0 +1,13 @@
+package handler
+
+import (
+	"context"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+)
+
+// Removes a user from a group. The user loses the roles of the group on its next refresh.
+// Only for administrators.
+func (srv UserGRPCHandler) RemoveGroupMember(ctx context.Context, in *users.GroupMemberRequest) (*users.EmptyResponse, error) {
+	return srv.changeGroupMembership(ctx, in, false)
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Sends a login link to a user.
+// Succeeds even if the user doesn't exist.
+func (srv UserGRPCHandler) RequestMagicLink(ctx context.Context, in *users.RequestMagicLinkRequest) (*users.EmptyResponse, error) {
+	if in == nil || len(in.Username) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	err := srv.magicLinkService.Request(ctx, in.GetUsername(), in.GetNonce())
+	if errors.Is(err, domain.ErrBadParamInput) {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+	if err != nil {
+		srv.l.Printf("error requesting a magic link: %v\n", err)
+		return nil, status.Error(codes.Internal, "error requesting magic link")
+	}
+
+	return &users.EmptyResponse{}, nil
+}
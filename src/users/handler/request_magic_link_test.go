@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequestMagicLink_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+
+	for _, req := range []*users.RequestMagicLinkRequest{nil, {Nonce: "device-nonce"}} {
+		res, err := service.RequestMagicLink(context.TODO(), req)
+		assert.Nil(t, res)
+		assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+	}
+}
+
+func TestRequestMagicLink_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"blank username":   {domain.ErrBadParamInput, status.Error(codes.InvalidArgument, "invalid request")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error requesting magic link")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			service := newHandler(nil, nil, nil)
+			magicLinkService := new(mocks.MagicLinkService)
+			service.magicLinkService = magicLinkService
+			magicLinkService.On("Request", mock.Anything, "alice", "").Once().Return(c.serviceErr)
+
+			res, err := service.RequestMagicLink(context.TODO(), &users.RequestMagicLinkRequest{Username: "alice"})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+		})
+	}
+}
+
+func TestRequestMagicLink_Success(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	magicLinkService := new(mocks.MagicLinkService)
+	service.magicLinkService = magicLinkService
+	magicLinkService.On("Request", mock.Anything, "alice", "device-nonce").Once().Return(nil)
+
+	res, err := service.RequestMagicLink(context.TODO(), &users.RequestMagicLinkRequest{Username: "alice", Nonce: "device-nonce"})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.EmptyResponse{}, res)
+	magicLinkService.AssertExpectations(t)
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Sends a password reset link to a user.
+// Succeeds even if the user doesn't exist.
+func (srv UserGRPCHandler) RequestPasswordReset(ctx context.Context, in *users.RequestPasswordResetRequest) (*users.EmptyResponse, error) {
+	if in == nil || len(in.UsernameOrEmail) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	err := srv.passwordResetService.RequestPasswordReset(ctx, in.UsernameOrEmail)
+	if errors.Is(err, domain.ErrBadParamInput) {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+	if err != nil {
+		srv.l.Printf("error requesting a password reset: %v\n", err)
+		return nil, status.Error(codes.Internal, "error requesting password reset")
+	}
+
+	return &users.EmptyResponse{}, nil
+}
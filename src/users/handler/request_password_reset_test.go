@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequestPasswordReset_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.RequestPasswordReset(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+
+	res, err = service.RequestPasswordReset(context.TODO(), &users.RequestPasswordResetRequest{})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+}
+
+func TestRequestPasswordReset_ServiceError(t *testing.T) {
+	passwordResetService := new(mocks.PasswordResetService)
+	service := newHandler(nil, nil, nil)
+	service.passwordResetService = passwordResetService
+
+	passwordResetService.On("RequestPasswordReset", mock.Anything, "alice").
+		Once().Return(errors.New("boom"))
+
+	res, err := service.RequestPasswordReset(context.TODO(), &users.RequestPasswordResetRequest{UsernameOrEmail: "alice"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Internal, "error requesting password reset"))
+	passwordResetService.AssertExpectations(t)
+}
+
+func TestRequestPasswordReset_Success(t *testing.T) {
+	passwordResetService := new(mocks.PasswordResetService)
+	service := newHandler(nil, nil, nil)
+	service.passwordResetService = passwordResetService
+
+	passwordResetService.On("RequestPasswordReset", mock.Anything, "alice@example.com").
+		Once().Return(nil)
+
+	res, err := service.RequestPasswordReset(context.TODO(), &users.RequestPasswordResetRequest{UsernameOrEmail: "alice@example.com"})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.EmptyResponse{}, res)
+	passwordResetService.AssertExpectations(t)
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Sends a new invitation link to an invited user, the previous one stops working.
+// Only for administrators.
+func (srv UserGRPCHandler) ResendInvitation(ctx context.Context, in *users.InvitationRequest) (*users.InvitationResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(in.UserId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	invitation, err := srv.invitationService.Resend(ctx, userID)
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, domain.ErrNotAllowed):
+		return nil, status.Error(codes.FailedPrecondition, "user is not invited")
+	case err != nil:
+		srv.l.Printf("error resending the invitation: %v\n", err)
+		return nil, status.Error(codes.Internal, "error resending invitation")
+	}
+
+	return invitationResponse(invitation), nil
+}
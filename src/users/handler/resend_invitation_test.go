@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestResendInvitation_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.ResendInvitation(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	service, _ = newInvitationHandler()
+	res, err = service.ResendInvitation(context.TODO(), &users.InvitationRequest{AccessToken: "cenas", UserId: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+}
+
+func TestResendInvitation_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"user not found":   {domain.ErrNotFound, status.Error(codes.NotFound, "user not found")},
+		"already accepted": {domain.ErrNotAllowed, status.Error(codes.FailedPrecondition, "user is not invited")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error resending invitation")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			userID := uuid.New()
+			service, invitationService := newInvitationHandler()
+			invitationService.On("Resend", mock.Anything, userID).Once().Return(domain.Invitation{}, c.serviceErr)
+
+			res, err := service.ResendInvitation(context.TODO(), &users.InvitationRequest{AccessToken: "cenas", UserId: userID.String()})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			invitationService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResendInvitation_Success(t *testing.T) {
+	userID := uuid.New()
+	service, invitationService := newInvitationHandler()
+	invitationService.On("Resend", mock.Anything, userID).Once().Return(domain.Invitation{
+		User:      &domain.User{ID: userID, Status: domain.UserStatusPending},
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+	}, nil)
+
+	res, err := service.ResendInvitation(context.TODO(), &users.InvitationRequest{AccessToken: "cenas", UserId: userID.String()})
+	assert.Nil(t, err)
+	assert.Equal(t, userID.String(), res.User.Id)
+	invitationService.AssertExpectations(t)
+}
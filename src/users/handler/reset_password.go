@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Sets a new password with a reset token.
+func (srv UserGRPCHandler) ResetPassword(ctx context.Context, in *users.ResetPasswordRequest) (*users.EmptyResponse, error) {
+	if in == nil || len(in.Token) == 0 || len(in.NewPassword) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	err := srv.passwordResetService.ResetPassword(ctx, in.Token, in.NewPassword)
+	if errors.Is(err, domain.ErrInvalidToken) {
+		return nil, status.Error(codes.InvalidArgument, "invalid token")
+	}
+	if errors.Is(err, domain.ErrBadParamInput) {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+	if err != nil {
+		srv.l.Printf("error resetting a password: %v\n", err)
+		return nil, status.Error(codes.Internal, "error resetting password")
+	}
+
+	return &users.EmptyResponse{}, nil
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestResetPassword_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	invalidRequests := []*users.ResetPasswordRequest{
+		nil,
+		{},
+		{Token: "the-token"},
+		{NewPassword: "new password"},
+	}
+
+	for _, req := range invalidRequests {
+		res, err := service.ResetPassword(context.TODO(), req)
+		assert.Nil(t, res)
+		assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+	}
+}
+
+func TestResetPassword_ServiceErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantedErr error
+	}{
+		{"invalid token", domain.ErrInvalidToken, status.Error(codes.InvalidArgument, "invalid token")},
+		{"unexpected error", errors.New("boom"), status.Error(codes.Internal, "error resetting password")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			passwordResetService := new(mocks.PasswordResetService)
+			service := newHandler(nil, nil, nil)
+			service.passwordResetService = passwordResetService
+
+			passwordResetService.On("ResetPassword", mock.Anything, "the-token", "new password").
+				Once().Return(test.err)
+
+			res, err := service.ResetPassword(context.TODO(), &users.ResetPasswordRequest{
+				Token:       "the-token",
+				NewPassword: "new password",
+			})
+			assert.Nil(t, res)
+			assert.Equal(t, test.wantedErr, err)
+			passwordResetService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResetPassword_Success(t *testing.T) {
+	passwordResetService := new(mocks.PasswordResetService)
+	service := newHandler(nil, nil, nil)
+	service.passwordResetService = passwordResetService
+
+	passwordResetService.On("ResetPassword", mock.Anything, "the-token", "new password").
+		Once().Return(nil)
+
+	res, err := service.ResetPassword(context.TODO(), &users.ResetPasswordRequest{
+		Token:       "the-token",
+		NewPassword: "new password",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.EmptyResponse{}, res)
+	passwordResetService.AssertExpectations(t)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Sent back when a user changed since the version the caller had of it,
+// which has to get the user again before retrying.
+var errUserVersionConflict = status.Error(codes.Aborted, "user changed since the given version")
+
+// Converts a user (with its role) to the gRPC response.
+func userResponse(user *domain.User) *users.UserResponse {
+	result := &users.UserResponse{
+		Id:                 user.ID.String(),
+		Username:           user.Username,
+		FirstName:          user.FirstName,
+		LastName:           user.LastName,
+		Email:              user.Email,
+		EmailVerified:      user.EmailVerified,
+		Status:             string(user.Status),
+		StatusReason:       user.StatusReason,
+		Version:            int64(user.Version),
+		MustChangePassword: user.MustChangePassword,
+	}
+
+	if !user.LastLoginAt.IsZero() {
+		result.LastLoginAt = user.LastLoginAt.Format(time.RFC3339)
+	}
+
+	if len(user.Attributes) > 0 {
+		if attributes, err := json.Marshal(user.Attributes); err == nil {
+			result.AttributesJson = string(attributes)
+		}
+	}
+
+	if user.Role != nil {
+		result.Role = &users.UserResponse_RoleResponse{
+			Id:        user.RoleId.String(),
+			RoleLabel: user.Role.RoleLabel,
+			RoleSlug:  user.Role.RoleSlug,
+			Version:   int64(user.Role.Version),
+		}
+	}
+	return result
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Restores a deleted user that wasn't purged yet.
+// Only for administrators.
+func (srv UserGRPCHandler) RestoreUser(ctx context.Context, in *users.RestoreUserRequest) (*users.UserResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(in.UserId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	user, err := srv.userDeletionService.Restore(ctx, userID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "deleted user not found or past the retention period")
+	case err != nil:
+		srv.l.Printf("error restoring the user: %v\n", err)
+		return nil, status.Error(codes.Internal, "error restoring user")
+	}
+
+	return userResponse(user), nil
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRestoreUser_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.RestoreUser(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestRestoreUser_InvalidUserID(t *testing.T) {
+	service, accessTokenManager := newAdminHandler(nil)
+
+	res, err := service.RestoreUser(context.TODO(), &users.RestoreUserRequest{AccessToken: "cenas", UserId: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+	accessTokenManager.AssertExpectations(t)
+}
+
+func TestRestoreUser_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"not restorable":   {domain.ErrNotFound, status.Error(codes.NotFound, "deleted user not found or past the retention period")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error restoring user")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			userID := uuid.New()
+			userDeletionService := new(mocks.UserDeletionService)
+			service, _ := newAdminHandler(nil)
+			service.userDeletionService = userDeletionService
+			userDeletionService.On("Restore", mock.Anything, userID).Once().Return(nil, c.serviceErr)
+
+			res, err := service.RestoreUser(context.TODO(), &users.RestoreUserRequest{AccessToken: "cenas", UserId: userID.String()})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			userDeletionService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestoreUser_Success(t *testing.T) {
+	userID := uuid.New()
+	userDeletionService := new(mocks.UserDeletionService)
+	service, accessTokenManager := newAdminHandler(nil)
+	service.userDeletionService = userDeletionService
+	userDeletionService.On("Restore", mock.Anything, userID).Once().
+		Return(&domain.User{ID: userID, Username: "alice", Status: domain.UserStatusActive}, nil)
+
+	res, err := service.RestoreUser(context.TODO(), &users.RestoreUserRequest{AccessToken: "cenas", UserId: userID.String()})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.UserResponse{Id: userID.String(), Username: "alice", Status: "active"}, res)
+	accessTokenManager.AssertExpectations(t)
+	userDeletionService.AssertExpectations(t)
+}
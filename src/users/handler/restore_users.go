@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Largest archive accepted by a restore.
+const restoreUsersMaxSize = 256 * 1024 * 1024
+
+// Restores the roles and users of an archive streamed in chunks, skipping the ones
+// already stored. Only for administrators.
+func (srv UserGRPCHandler) RestoreUsers(stream users.Users_RestoreUsersServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.Unauthenticated, "invalid token")
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx, err := srv.authorizeAdmin(stream.Context(), first.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	archive := bytes.NewBuffer(first.Data)
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if archive.Len()+len(chunk.Data) > restoreUsersMaxSize {
+			return status.Error(codes.ResourceExhausted, "archive too large")
+		}
+		archive.Write(chunk.Data)
+	}
+
+	report, err := srv.userBackupService.Restore(ctx, archive, first.RemapRoles)
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		srv.l.Printf("error restoring the users: %v\n", err)
+		return status.Error(codes.Internal, "error restoring users")
+	}
+
+	return stream.SendAndClose(&users.RestoreUsersResponse{
+		RolesCreated:        int32(report.RolesCreated),
+		RolesMatched:        int32(report.RolesMatched),
+		GroupsCreated:       int32(report.GroupsCreated),
+		GroupsMatched:       int32(report.GroupsMatched),
+		UsersRestored:       int32(report.UsersRestored),
+		UsersSkipped:        int32(report.UsersSkipped),
+		MembershipsRestored: int32(report.MembershipsRestored),
+	})
+}
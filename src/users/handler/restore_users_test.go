@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Client stream sending the given requests, keeping the response.
+type fakeRestoreUsersStream struct {
+	grpc.ServerStream
+	requests []*users.RestoreUsersRequest
+	response *users.RestoreUsersResponse
+}
+
+func (s *fakeRestoreUsersStream) Context() context.Context {
+	return context.TODO()
+}
+
+func (s *fakeRestoreUsersStream) Recv() (*users.RestoreUsersRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	request := s.requests[0]
+	s.requests = s.requests[1:]
+	return request, nil
+}
+
+func (s *fakeRestoreUsersStream) SendAndClose(response *users.RestoreUsersResponse) error {
+	s.response = response
+	return nil
+}
+
+func TestRestoreUsers_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	err := service.RestoreUsers(&fakeRestoreUsersStream{})
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	err = service.RestoreUsers(&fakeRestoreUsersStream{requests: []*users.RestoreUsersRequest{{RemapRoles: true}}})
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestRestoreUsers_TooLarge(t *testing.T) {
+	service, _ := newUserBackupHandler()
+
+	err := service.RestoreUsers(&fakeRestoreUsersStream{requests: []*users.RestoreUsersRequest{
+		{AccessToken: "cenas", Data: make([]byte, restoreUsersMaxSize)},
+		{Data: []byte("a")},
+	}})
+	assert.Equal(t, err, status.Error(codes.ResourceExhausted, "archive too large"))
+}
+
+func TestRestoreUsers_ServiceErrors(t *testing.T) {
+	corrupted := fmt.Errorf("%w: checksum mismatch, the archive is corrupted", domain.ErrBadParamInput)
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"invalid archive":  {corrupted, status.Error(codes.InvalidArgument, corrupted.Error())},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error restoring users")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, userBackupService := newUserBackupHandler()
+			userBackupService.On("Restore", mock.Anything, mock.Anything, false).Once().
+				Return(domain.UserRestoreReport{}, c.serviceErr)
+
+			err := service.RestoreUsers(&fakeRestoreUsersStream{requests: []*users.RestoreUsersRequest{{AccessToken: "cenas"}}})
+			assert.Equal(t, c.expected, err)
+			userBackupService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestoreUsers_Success(t *testing.T) {
+	service, userBackupService := newUserBackupHandler()
+
+	var archive string
+	userBackupService.On("Restore", mock.Anything, mock.Anything, true).Once().
+		Run(func(args mock.Arguments) {
+			content, _ := ioutil.ReadAll(args.Get(1).(io.Reader))
+			archive = string(content)
+		}).
+		Return(domain.UserRestoreReport{RolesCreated: 1, RolesMatched: 2, GroupsCreated: 5, GroupsMatched: 6, UsersRestored: 3, UsersSkipped: 4, MembershipsRestored: 7}, nil)
+
+	stream := &fakeRestoreUsersStream{requests: []*users.RestoreUsersRequest{
+		{AccessToken: "cenas", RemapRoles: true, Data: []byte(`{"type":`)},
+		{Data: []byte(`"header"}` + "\n")},
+	}}
+	err := service.RestoreUsers(stream)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"header"}`+"\n", archive)
+	assert.Equal(t, &users.RestoreUsersResponse{RolesCreated: 1, RolesMatched: 2, GroupsCreated: 5, GroupsMatched: 6, UsersRestored: 3, UsersSkipped: 4, MembershipsRestored: 7}, stream.response)
+	userBackupService.AssertExpectations(t)
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Revokes the invitation of a user, which is deleted.
+// Only for administrators.
+func (srv UserGRPCHandler) RevokeInvitation(ctx context.Context, in *users.InvitationRequest) (*users.EmptyResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(in.UserId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	err = srv.invitationService.Revoke(ctx, userID)
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, domain.ErrNotAllowed):
+		return nil, status.Error(codes.FailedPrecondition, "user is not invited")
+	case err != nil:
+		srv.l.Printf("error revoking the invitation: %v\n", err)
+		return nil, status.Error(codes.Internal, "error revoking invitation")
+	}
+
+	return &users.EmptyResponse{}, nil
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Sent back when a role changed since the version the caller had of it,
+// which has to get the role again before retrying.
+var errRoleVersionConflict = status.Error(codes.Aborted, "role changed since the given version")
+
+// Converts a role to the gRPC response.
+func roleResponse(role domain.Role) *users.RoleResponse {
+	return &users.RoleResponse{
+		Id:                 role.ID.String(),
+		RoleLabel:          role.RoleLabel,
+		RoleSlug:           role.RoleSlug,
+		Version:            int64(role.Version),
+		RequiresMfa:        role.RequiresMFA,
+		PasswordMaxAgeDays: int32(role.PasswordMaxAgeDays),
+	}
+}
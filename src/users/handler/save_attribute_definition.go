@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Creates or updates the definition of a custom attribute.
+// Only for administrators.
+func (srv UserGRPCHandler) SaveAttributeDefinition(ctx context.Context, in *users.SaveAttributeDefinitionRequest) (*users.AttributeDefinitionResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeOperator(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	definition, err := srv.attributeService.SaveDefinition(ctx, domain.AttributeDefinition{
+		Name:         in.Name,
+		Type:         domain.AttributeType(in.Type),
+		Required:     in.Required,
+		UserEditable: in.UserEditable,
+		InToken:      in.InToken,
+	})
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid attribute definition")
+	case errors.Is(err, domain.ErrNotAllowed):
+		return nil, status.Error(codes.FailedPrecondition, "the type of an attribute can't be changed")
+	case err != nil:
+		srv.l.Printf("error saving the attribute definition: %v\n", err)
+		return nil, status.Error(codes.Internal, "error saving attribute definition")
+	}
+
+	return attributeDefinitionResponse(definition), nil
+}
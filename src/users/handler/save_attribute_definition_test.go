@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSaveAttributeDefinition_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.SaveAttributeDefinition(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestSaveAttributeDefinition_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"invalid definition": {domain.ErrBadParamInput, status.Error(codes.InvalidArgument, "invalid attribute definition")},
+		"type changed":       {domain.ErrNotAllowed, status.Error(codes.FailedPrecondition, "the type of an attribute can't be changed")},
+		"unexpected error":   {errors.New("boom"), status.Error(codes.Internal, "error saving attribute definition")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			attributeService := new(mocks.AttributeService)
+			service, _ := newAdminHandler(nil)
+			service.attributeService = attributeService
+			attributeService.On("SaveDefinition", mock.Anything, mock.Anything).Once().Return(domain.AttributeDefinition{}, c.serviceErr)
+
+			res, err := service.SaveAttributeDefinition(context.TODO(), &users.SaveAttributeDefinitionRequest{AccessToken: "cenas", Name: "seniority", Type: "number"})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			attributeService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSaveAttributeDefinition_Success(t *testing.T) {
+	definition := domain.AttributeDefinition{Name: "timezone", Type: domain.AttributeTypeString, Required: true, UserEditable: true, InToken: true}
+	attributeService := new(mocks.AttributeService)
+	service, accessTokenManager := newAdminHandler(nil)
+	service.attributeService = attributeService
+	attributeService.On("SaveDefinition", mock.Anything, definition).Once().Return(definition, nil)
+
+	res, err := service.SaveAttributeDefinition(context.TODO(), &users.SaveAttributeDefinitionRequest{
+		AccessToken:  "cenas",
+		Name:         "timezone",
+		Type:         "string",
+		Required:     true,
+		UserEditable: true,
+		InToken:      true,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.AttributeDefinitionResponse{Name: "timezone", Type: "string", Required: true, UserEditable: true, InToken: true}, res)
+	accessTokenManager.AssertExpectations(t)
+	attributeService.AssertExpectations(t)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Creates a group, or updates it when the request has its ID.
+// Only for administrators.
+func (srv UserGRPCHandler) SaveGroup(ctx context.Context, in *users.SaveGroupRequest) (*users.GroupResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	group := domain.Group{Name: in.Name, Description: in.Description}
+	if len(in.Id) > 0 {
+		groupID, err := uuid.Parse(in.Id)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid request")
+		}
+		group.ID = groupID
+	}
+
+	group, err = srv.groupService.Save(ctx, group)
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid group")
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "group not found")
+	case errors.Is(err, domain.ErrAlreadyExists):
+		return nil, status.Error(codes.AlreadyExists, "group name already in use")
+	case err != nil:
+		srv.l.Printf("error saving the group: %v\n", err)
+		return nil, status.Error(codes.Internal, "error saving group")
+	}
+
+	return groupResponse(group), nil
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSaveGroup_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.SaveGroup(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	service, _ = newGroupHandler()
+	res, err = service.SaveGroup(context.TODO(), &users.SaveGroupRequest{AccessToken: "cenas", Id: "cenas", Name: "Editors"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+}
+
+func TestSaveGroup_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"invalid group":    {domain.ErrBadParamInput, status.Error(codes.InvalidArgument, "invalid group")},
+		"not found":        {domain.ErrNotFound, status.Error(codes.NotFound, "group not found")},
+		"name in use":      {domain.ErrAlreadyExists, status.Error(codes.AlreadyExists, "group name already in use")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error saving group")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, groupService := newGroupHandler()
+			groupService.On("Save", mock.Anything, domain.Group{Name: "Editors"}).Once().Return(domain.Group{}, c.serviceErr)
+
+			res, err := service.SaveGroup(context.TODO(), &users.SaveGroupRequest{AccessToken: "cenas", Name: "Editors"})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			groupService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSaveGroup_Success(t *testing.T) {
+	group := domain.Group{ID: uuid.New(), Name: "Editors", Description: "Can edit"}
+	service, groupService := newGroupHandler()
+	groupService.On("Save", mock.Anything, group).Once().Return(group, nil)
+
+	res, err := service.SaveGroup(context.TODO(), &users.SaveGroupRequest{
+		AccessToken: "cenas",
+		Id:          group.ID.String(),
+		Name:        "Editors",
+		Description: "Can edit",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, group.ID.String(), res.Id)
+	assert.Equal(t, "Can edit", res.Description)
+	assert.Empty(t, res.Roles)
+	groupService.AssertExpectations(t)
+}
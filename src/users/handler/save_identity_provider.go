@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Creates or updates an identity provider of the organization.
+// Only for administrators.
+func (srv UserGRPCHandler) SaveIdentityProvider(ctx context.Context, in *users.SaveIdentityProviderRequest) (*users.IdentityProviderResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := srv.oidcService.SaveProvider(ctx, domain.IdentityProvider{
+		Slug:          in.Slug,
+		Name:          in.Name,
+		Issuer:        in.Issuer,
+		ClientID:      in.ClientId,
+		ClientSecret:  in.ClientSecret,
+		RedirectURL:   in.RedirectUrl,
+		Scopes:        in.Scopes,
+		RoleClaim:     in.RoleClaim,
+		RoleMapping:   in.RoleMapping,
+		DefaultRole:   in.DefaultRole,
+		LinkByEmail:   in.LinkByEmail,
+		AutoProvision: in.AutoProvision,
+		Enabled:       in.Enabled,
+	})
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid identity provider")
+	case err != nil:
+		srv.l.Printf("error saving the identity provider: %v\n", err)
+		return nil, status.Error(codes.Internal, "error saving identity provider")
+	}
+
+	return identityProviderResponse(provider), nil
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSaveIdentityProvider_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.SaveIdentityProvider(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestSaveIdentityProvider_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"invalid provider": {domain.ErrBadParamInput, status.Error(codes.InvalidArgument, "invalid identity provider")},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error saving identity provider")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, oidcService := newIdentityProviderHandler()
+			oidcService.On("SaveProvider", mock.Anything, mock.Anything).Once().Return(domain.IdentityProvider{}, c.serviceErr)
+
+			res, err := service.SaveIdentityProvider(context.TODO(), &users.SaveIdentityProviderRequest{AccessToken: "cenas", Slug: "corporate"})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+		})
+	}
+}
+
+func TestSaveIdentityProvider_Success(t *testing.T) {
+	provider := domain.IdentityProvider{
+		Slug:          "corporate",
+		Name:          "Corporate",
+		Issuer:        "https://idp.example.com",
+		ClientID:      "users-service",
+		ClientSecret:  "secret",
+		RedirectURL:   "https://app.example.com/callback",
+		Scopes:        []string{"email"},
+		RoleClaim:     "groups",
+		RoleMapping:   map[string]string{"it-admins": "admin"},
+		DefaultRole:   "user",
+		LinkByEmail:   true,
+		AutoProvision: true,
+		Enabled:       true,
+	}
+	saved := provider
+	saved.ID = uuid.New()
+
+	service, oidcService := newIdentityProviderHandler()
+	oidcService.On("SaveProvider", mock.Anything, provider).Once().Return(saved, nil)
+
+	res, err := service.SaveIdentityProvider(context.TODO(), &users.SaveIdentityProviderRequest{
+		AccessToken:   "cenas",
+		Slug:          "corporate",
+		Name:          "Corporate",
+		Issuer:        "https://idp.example.com",
+		ClientId:      "users-service",
+		ClientSecret:  "secret",
+		RedirectUrl:   "https://app.example.com/callback",
+		Scopes:        []string{"email"},
+		RoleClaim:     "groups",
+		RoleMapping:   map[string]string{"it-admins": "admin"},
+		DefaultRole:   "user",
+		LinkByEmail:   true,
+		AutoProvision: true,
+		Enabled:       true,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.IdentityProviderResponse{
+		Id:            saved.ID.String(),
+		Slug:          "corporate",
+		Name:          "Corporate",
+		Issuer:        "https://idp.example.com",
+		ClientId:      "users-service",
+		RedirectUrl:   "https://app.example.com/callback",
+		Scopes:        []string{"email"},
+		RoleClaim:     "groups",
+		RoleMapping:   map[string]string{"it-admins": "admin"},
+		DefaultRole:   "user",
+		LinkByEmail:   true,
+		AutoProvision: true,
+		Enabled:       true,
+	}, res)
+	oidcService.AssertExpectations(t)
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Sends (again) the verification email to the logged in user.
+func (srv UserGRPCHandler) SendVerificationEmail(ctx context.Context, in *users.SendVerificationEmailRequest) (*users.EmptyResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, userID, err := srv.authenticate(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := srv.userService.GetUserByUUID(ctx, userID)
+	if err != nil {
+		srv.l.Printf("error getting the user to verify: %v\n", err)
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+
+	err = srv.emailVerificationService.SendVerificationEmail(ctx, user)
+	if errors.Is(err, domain.ErrBadParamInput) {
+		return nil, status.Error(codes.FailedPrecondition, "user has no email")
+	}
+	if errors.Is(err, domain.ErrNotAllowed) {
+		return nil, status.Error(codes.FailedPrecondition, "email already verified")
+	}
+	if err != nil {
+		srv.l.Printf("error sending the verification email: %v\n", err)
+		return nil, status.Error(codes.Internal, "error sending verification email")
+	}
+
+	return &users.EmptyResponse{}, nil
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Handler with a valid access token for the user.
+func newSendVerificationEmailHandler(userID uuid.UUID) (UserGRPCHandler, *mocks.UserService, *mocks.EmailVerificationService) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	userService := new(mocks.UserService)
+	emailVerificationService := new(mocks.EmailVerificationService)
+
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserIDFromToken", mockToken).Once().Return(userID, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Once().Return(domain.DefaultOrganizationID, nil)
+
+	service := newHandler(accessTokenManager, userService, nil)
+	service.emailVerificationService = emailVerificationService
+	return service, userService, emailVerificationService
+}
+
+func TestSendVerificationEmail_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.SendVerificationEmail(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	res, err = service.SendVerificationEmail(context.TODO(), &users.SendVerificationEmailRequest{})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestSendVerificationEmail_InvalidToken(t *testing.T) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	service := newHandler(accessTokenManager, nil, nil)
+
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(false)
+
+	res, err := service.SendVerificationEmail(context.TODO(), &users.SendVerificationEmailRequest{AccessToken: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+	accessTokenManager.AssertExpectations(t)
+}
+
+func TestSendVerificationEmail_UserNotFound(t *testing.T) {
+	userID := uuid.New()
+	service, userService, _ := newSendVerificationEmailHandler(userID)
+	userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(nil, errors.New("boom"))
+
+	res, err := service.SendVerificationEmail(context.TODO(), &users.SendVerificationEmailRequest{AccessToken: "cenas"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.NotFound, "user not found"))
+	userService.AssertExpectations(t)
+}
+
+func TestSendVerificationEmail_ServiceErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantedErr error
+	}{
+		{"no email", domain.ErrBadParamInput, status.Error(codes.FailedPrecondition, "user has no email")},
+		{"already verified", domain.ErrNotAllowed, status.Error(codes.FailedPrecondition, "email already verified")},
+		{"unexpected error", errors.New("boom"), status.Error(codes.Internal, "error sending verification email")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			userID := uuid.New()
+			user := &domain.User{ID: userID}
+			service, userService, emailVerificationService := newSendVerificationEmailHandler(userID)
+			userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(user, nil)
+			emailVerificationService.On("SendVerificationEmail", mock.Anything, user).Once().Return(test.err)
+
+			res, err := service.SendVerificationEmail(context.TODO(), &users.SendVerificationEmailRequest{AccessToken: "cenas"})
+			assert.Nil(t, res)
+			assert.Equal(t, test.wantedErr, err)
+			emailVerificationService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSendVerificationEmail_Success(t *testing.T) {
+	userID := uuid.New()
+	user := &domain.User{ID: userID, Email: "user@example.com"}
+	service, userService, emailVerificationService := newSendVerificationEmailHandler(userID)
+	userService.On("GetUserByUUID", mock.Anything, userID).Once().Return(user, nil)
+	emailVerificationService.On("SendVerificationEmail", mock.Anything, user).Once().Return(nil)
+
+	res, err := service.SendVerificationEmail(context.TODO(), &users.SendVerificationEmailRequest{AccessToken: "cenas"})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.EmptyResponse{}, res)
+	userService.AssertExpectations(t)
+	emailVerificationService.AssertExpectations(t)
+}
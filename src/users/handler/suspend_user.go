@@ -0,0 +1,14 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+)
+
+// Suspends a user with a reason, ending their sessions.
+// Only for administrators.
+func (srv UserGRPCHandler) SuspendUser(ctx context.Context, in *users.UpdateUserStatusRequest) (*users.UserResponse, error) {
+	return srv.changeUserStatus(ctx, in, domain.UserStatusSuspended)
+}
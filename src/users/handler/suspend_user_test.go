@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Handler with an access token "cenas" that belongs to an administrator.
+func newAdminHandler(userService *mocks.UserService) (UserGRPCHandler, *mocks.AccessTokenHandler) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"admin"}, nil)
+	accessTokenManager.On("GetOrganizationIDFromToken", mockToken).Once().Return(domain.DefaultOrganizationID, nil)
+	return newHandler(accessTokenManager, userService, nil), accessTokenManager
+}
+
+func TestSuspendUser_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.SuspendUser(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	res, err = service.SuspendUser(context.TODO(), &users.UpdateUserStatusRequest{})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+}
+
+func TestSuspendUser_UserDoesntHaveProperRole(t *testing.T) {
+	accessTokenManager := new(mocks.AccessTokenHandler)
+	service := newHandler(accessTokenManager, nil, nil)
+
+	mockToken := &jwt.Token{Raw: "mock token"}
+	accessTokenManager.On("ParseJWT", "cenas").Once().Return(mockToken, nil)
+	accessTokenManager.On("IsJWTokenValid", mock.Anything, mockToken).Once().Return(true)
+	accessTokenManager.On("GetUserRolesFromToken", mockToken).Once().Return([]string{"user"}, nil)
+
+	res, err := service.SuspendUser(context.TODO(), &users.UpdateUserStatusRequest{
+		AccessToken: "cenas",
+		UserId:      uuid.NewString(),
+		Reason:      "spam",
+	})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "incorrect permissions"))
+	accessTokenManager.AssertExpectations(t)
+}
+
+func TestSuspendUser_InvalidRequestInParameters(t *testing.T) {
+	requests := map[string]*users.UpdateUserStatusRequest{
+		"invalid user id": {AccessToken: "cenas", UserId: "cenas", Version: 1, Reason: "spam"},
+		"missing reason":  {AccessToken: "cenas", UserId: uuid.NewString(), Version: 1},
+	}
+
+	for name, req := range requests {
+		t.Run(name, func(t *testing.T) {
+			service, accessTokenManager := newAdminHandler(nil)
+
+			res, err := service.SuspendUser(context.TODO(), req)
+			assert.Nil(t, res)
+			assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+			accessTokenManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSuspendUser_MissingVersion(t *testing.T) {
+	userService := new(mocks.UserService)
+	service, accessTokenManager := newAdminHandler(userService)
+
+	res, err := service.SuspendUser(context.TODO(), &users.UpdateUserStatusRequest{
+		AccessToken: "cenas",
+		UserId:      uuid.NewString(),
+		Reason:      "spam",
+	})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "missing version"))
+	accessTokenManager.AssertExpectations(t)
+	userService.AssertNotCalled(t, "ChangeStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSuspendUser_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"user not found":    {domain.ErrNotFound, status.Error(codes.NotFound, "user not found")},
+		"already suspended": {domain.ErrNotAllowed, status.Error(codes.FailedPrecondition, "invalid status transition")},
+		"version conflict":  {domain.ErrVersionConflict, errUserVersionConflict},
+		"unexpected error":  {errors.New("boom"), status.Error(codes.Internal, "error changing user status")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			userID := uuid.New()
+			userService := new(mocks.UserService)
+			service, _ := newAdminHandler(userService)
+			userService.On("ChangeStatus", mock.Anything, userID, 1, domain.UserStatusSuspended, "spam").
+				Once().Return(nil, c.serviceErr)
+
+			res, err := service.SuspendUser(context.TODO(), &users.UpdateUserStatusRequest{
+				AccessToken: "cenas",
+				UserId:      userID.String(),
+				Version:     1,
+				Reason:      "spam",
+			})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+			userService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSuspendUser_Success(t *testing.T) {
+	userID := uuid.New()
+	userService := new(mocks.UserService)
+	service, accessTokenManager := newAdminHandler(userService)
+	userService.On("ChangeStatus", mock.Anything, userID, 1, domain.UserStatusSuspended, "spam").
+		Once().Return(&domain.User{
+		ID:           userID,
+		Username:     "alice",
+		Status:       domain.UserStatusSuspended,
+		StatusReason: "spam",
+		Version:      2,
+	}, nil)
+
+	res, err := service.SuspendUser(context.TODO(), &users.UpdateUserStatusRequest{
+		AccessToken: "cenas",
+		UserId:      userID.String(),
+		Version:     1,
+		Reason:      "spam",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.UserResponse{
+		Id:           userID.String(),
+		Username:     "alice",
+		Status:       "suspended",
+		StatusReason: "spam",
+		Version:      2,
+	}, res)
+	accessTokenManager.AssertExpectations(t)
+	userService.AssertExpectations(t)
+}
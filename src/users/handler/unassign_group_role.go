@@ -0,0 +1,13 @@
+package handler
+
+import (
+	"context"
+
+	users "github.com/plagioriginal/users-service-grpc/users"
+)
+
+// Removes a role from a group, which its members lose on their next refresh.
+// Only for administrators.
+func (srv UserGRPCHandler) UnassignGroupRole(ctx context.Context, in *users.GroupRoleRequest) (*users.GroupResponse, error) {
+	return srv.changeGroupRole(ctx, in, false)
+}
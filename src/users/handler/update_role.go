@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Updates the label and password policies of a role. The users with the role get the
+// new policies on their next login or refresh.
+// Only for administrators.
+func (srv UserGRPCHandler) UpdateRole(ctx context.Context, in *users.UpdateRoleRequest) (*users.RoleResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	roleID, err := uuid.Parse(in.Id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+	if in.Version <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing version")
+	}
+
+	role, err := srv.roleService.Update(ctx, domain.Role{
+		ID:                 roleID,
+		RoleLabel:          in.RoleLabel,
+		RequiresMFA:        in.RequiresMfa,
+		PasswordMaxAgeDays: int(in.PasswordMaxAgeDays),
+		Version:            int(in.Version),
+	})
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return nil, status.Error(codes.InvalidArgument, "invalid role")
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "role not found")
+	case errors.Is(err, domain.ErrAlreadyExists):
+		return nil, status.Error(codes.AlreadyExists, "role label already in use")
+	case errors.Is(err, domain.ErrVersionConflict):
+		return nil, errRoleVersionConflict
+	case err != nil:
+		srv.l.Printf("error updating the role: %v\n", err)
+		return nil, status.Error(codes.Internal, "error updating role")
+	}
+
+	return roleResponse(role), nil
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUpdateRole_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.UpdateRole(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+
+	service, _ = newRoleHandler()
+	res, err = service.UpdateRole(context.TODO(), &users.UpdateRoleRequest{AccessToken: "cenas", Id: "nope", Version: 1})
+	assert.Nil(t, res)
+	assert.Equal(t, status.Error(codes.InvalidArgument, "invalid request"), err)
+
+	service, _ = newRoleHandler()
+	res, err = service.UpdateRole(context.TODO(), &users.UpdateRoleRequest{AccessToken: "cenas", Id: uuid.NewString()})
+	assert.Nil(t, res)
+	assert.Equal(t, status.Error(codes.InvalidArgument, "missing version"), err)
+}
+
+func TestUpdateRole_ServiceErrors(t *testing.T) {
+	cases := map[string]struct {
+		serviceErr error
+		expected   error
+	}{
+		"invalid role":     {domain.ErrBadParamInput, status.Error(codes.InvalidArgument, "invalid role")},
+		"not found":        {domain.ErrNotFound, status.Error(codes.NotFound, "role not found")},
+		"label in use":     {domain.ErrAlreadyExists, status.Error(codes.AlreadyExists, "role label already in use")},
+		"version conflict": {domain.ErrVersionConflict, errRoleVersionConflict},
+		"unexpected error": {errors.New("boom"), status.Error(codes.Internal, "error updating role")},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			service, roleService := newRoleHandler()
+			roleService.On("Update", mock.Anything, mock.Anything).Once().Return(domain.Role{}, c.serviceErr)
+
+			res, err := service.UpdateRole(context.TODO(), &users.UpdateRoleRequest{AccessToken: "cenas", Id: uuid.NewString(), Version: 1})
+			assert.Nil(t, res)
+			assert.Equal(t, c.expected, err)
+		})
+	}
+}
+
+func TestUpdateRole_Success(t *testing.T) {
+	id := uuid.New()
+	updated := domain.Role{ID: id, RoleSlug: "auditor", RoleLabel: "Auditors", PasswordMaxAgeDays: 60, Version: 3}
+	service, roleService := newRoleHandler()
+	roleService.On("Update", mock.Anything, domain.Role{
+		ID:                 id,
+		RoleLabel:          "Auditors",
+		PasswordMaxAgeDays: 60,
+		Version:            2,
+	}).Once().Return(updated, nil)
+
+	res, err := service.UpdateRole(context.TODO(), &users.UpdateRoleRequest{
+		AccessToken:        "cenas",
+		Id:                 id.String(),
+		RoleLabel:          "Auditors",
+		PasswordMaxAgeDays: 60,
+		Version:            2,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, roleResponse(updated), res)
+	roleService.AssertExpectations(t)
+}
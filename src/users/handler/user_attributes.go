@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"encoding/json"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+)
+
+// Converts the attributes of a user to the gRPC response.
+func userAttributesResponse(user *domain.User) (*users.UserAttributesResponse, error) {
+	attributes := user.Attributes
+	if attributes == nil {
+		attributes = domain.Attributes{}
+	}
+	encoded, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, err
+	}
+	return &users.UserAttributesResponse{
+		UserId:         user.ID.String(),
+		AttributesJson: string(encoded),
+		Version:        int64(user.Version),
+	}, nil
+}
+
+// Converts an attribute definition to the gRPC response.
+func attributeDefinitionResponse(definition domain.AttributeDefinition) *users.AttributeDefinitionResponse {
+	return &users.AttributeDefinitionResponse{
+		Name:         definition.Name,
+		Type:         string(definition.Type),
+		Required:     definition.Required,
+		UserEditable: definition.UserEditable,
+		InToken:      definition.InToken,
+	}
+}
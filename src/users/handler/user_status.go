@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Moves a user to another status on behalf of an administrator.
+// Suspensions must always say why.
+func (srv UserGRPCHandler) changeUserStatus(ctx context.Context, in *users.UpdateUserStatusRequest, userStatus domain.UserStatus) (*users.UserResponse, error) {
+	if in == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ctx, err := srv.authorizeAdmin(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(in.UserId)
+	if err != nil || (userStatus == domain.UserStatusSuspended && len(in.Reason) == 0) {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+	if in.Version <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing version")
+	}
+
+	user, err := srv.userService.ChangeStatus(ctx, userID, int(in.Version), userStatus, in.GetReason())
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, domain.ErrNotAllowed):
+		return nil, status.Error(codes.FailedPrecondition, "invalid status transition")
+	case errors.Is(err, domain.ErrVersionConflict):
+		return nil, errUserVersionConflict
+	case err != nil:
+		srv.l.Printf("error changing the user status to %v: %v\n", userStatus, err)
+		return nil, status.Error(codes.Internal, "error changing user status")
+	}
+
+	return userResponse(user), nil
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Verifies the email of a user with the token sent to it.
+func (srv UserGRPCHandler) VerifyEmail(ctx context.Context, in *users.VerifyEmailRequest) (*users.UserResponse, error) {
+	if in == nil || len(in.Token) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid token")
+	}
+
+	user, err := srv.emailVerificationService.VerifyEmail(ctx, in.Token)
+	if errors.Is(err, domain.ErrInvalidToken) {
+		return nil, status.Error(codes.InvalidArgument, "invalid token")
+	}
+	if err != nil {
+		srv.l.Printf("error verifying an email: %v\n", err)
+		return nil, status.Error(codes.Internal, "error verifying email")
+	}
+
+	return userResponse(user), nil
+}
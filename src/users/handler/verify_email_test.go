@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestVerifyEmail_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	res, err := service.VerifyEmail(context.TODO(), nil)
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid token"))
+
+	res, err = service.VerifyEmail(context.TODO(), &users.VerifyEmailRequest{})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid token"))
+}
+
+func TestVerifyEmail_InvalidToken(t *testing.T) {
+	emailVerificationService := new(mocks.EmailVerificationService)
+	service := newHandler(nil, nil, nil)
+	service.emailVerificationService = emailVerificationService
+
+	emailVerificationService.On("VerifyEmail", mock.Anything, "the-token").
+		Once().Return(nil, domain.ErrInvalidToken)
+
+	res, err := service.VerifyEmail(context.TODO(), &users.VerifyEmailRequest{Token: "the-token"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid token"))
+	emailVerificationService.AssertExpectations(t)
+}
+
+func TestVerifyEmail_ErrorVerifying(t *testing.T) {
+	emailVerificationService := new(mocks.EmailVerificationService)
+	service := newHandler(nil, nil, nil)
+	service.emailVerificationService = emailVerificationService
+
+	emailVerificationService.On("VerifyEmail", mock.Anything, "the-token").
+		Once().Return(nil, errors.New("boom"))
+
+	res, err := service.VerifyEmail(context.TODO(), &users.VerifyEmailRequest{Token: "the-token"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Internal, "error verifying email"))
+	emailVerificationService.AssertExpectations(t)
+}
+
+func TestVerifyEmail_Success(t *testing.T) {
+	userID, roleID := uuid.New(), uuid.New()
+	emailVerificationService := new(mocks.EmailVerificationService)
+	service := newHandler(nil, nil, nil)
+	service.emailVerificationService = emailVerificationService
+
+	emailVerificationService.On("VerifyEmail", mock.Anything, "the-token").
+		Once().Return(&domain.User{
+		ID:            userID,
+		Username:      "username",
+		Email:         "user@example.com",
+		EmailVerified: true,
+		RoleId:        roleID,
+		Role:          &domain.Role{ID: roleID, RoleLabel: "User", RoleSlug: "user"},
+	}, nil)
+
+	res, err := service.VerifyEmail(context.TODO(), &users.VerifyEmailRequest{Token: "the-token"})
+	assert.Nil(t, err)
+	assert.Equal(t, &users.UserResponse{
+		Id:            userID.String(),
+		Username:      "username",
+		Email:         "user@example.com",
+		EmailVerified: true,
+		Role: &users.UserResponse_RoleResponse{
+			Id:        roleID.String(),
+			RoleLabel: "User",
+			RoleSlug:  "user",
+		},
+	}, res)
+	emailVerificationService.AssertExpectations(t)
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Completes a login with the second factor, exchanging the MFA token for the tokens.
+// Failures count towards the login throttling of the user.
+func (srv UserGRPCHandler) VerifyMFA(ctx context.Context, in *users.VerifyMFARequest) (*users.TokenResponse, error) {
+	if in == nil || len(in.MfaToken) == 0 || (len(in.Code) == 0 && len(in.RecoveryCode) == 0) {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	userID, organizationID, err := srv.tokenManager.GetUserIDFromMFAChallenge(in.GetMfaToken())
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	// The user is looked up, and its attempts throttled, in the organization it logged in to.
+	ctx = domain.WithOrganization(ctx, organizationID)
+
+	user, err := srv.userService.GetUserByUUID(ctx, userID)
+	if err != nil {
+		srv.l.Printf("error getting the user of the mfa challenge: %v\n", err)
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	if !user.IsActive() {
+		srv.recordLoginEvent(ctx, domain.LoginEventLogin, user, "", "user is not active")
+		return nil, status.Error(codes.PermissionDenied, "user is not active")
+	}
+
+	ip := srv.clientIP(ctx)
+	retryAfter, err := srv.loginAttemptService.GetRetryAfter(ctx, user.Username, ip)
+	if err != nil {
+		srv.l.Printf("error checking the login attempts: %v\n", err)
+		return nil, status.Error(codes.Internal, "error checking login attempts")
+	}
+	if retryAfter > 0 {
+		srv.recordLoginEvent(ctx, domain.LoginEventLogin, user, "", "too many attempts")
+		return nil, tooManyAttemptsError(retryAfter)
+	}
+
+	if len(in.Code) > 0 {
+		err = srv.mfaService.VerifyCode(ctx, user.ID, in.GetCode())
+	} else {
+		err = srv.mfaService.VerifyRecoveryCode(ctx, user.ID, in.GetRecoveryCode())
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return nil, status.Error(codes.FailedPrecondition, "mfa enrollment required")
+	case errors.Is(err, domain.ErrInvalidToken):
+		srv.recordLoginEvent(ctx, domain.LoginEventLogin, user, "", "invalid mfa code")
+		retryAfter, throttleErr := srv.loginAttemptService.RegisterFailure(ctx, user.Username, ip)
+		if throttleErr != nil {
+			srv.l.Printf("error registering failed mfa: %v\n", throttleErr)
+		}
+		if retryAfter > 0 {
+			return nil, tooManyAttemptsError(retryAfter)
+		}
+		return nil, status.Error(codes.InvalidArgument, "invalid code")
+	case err != nil:
+		srv.l.Printf("error verifying the mfa code: %v\n", err)
+		return nil, status.Error(codes.Internal, "error verifying mfa")
+	}
+
+	if err = srv.loginAttemptService.RegisterSuccess(ctx, user.Username); err != nil {
+		srv.l.Printf("error resetting the failed logins: %v\n", err)
+	}
+
+	token, err := srv.tokenManager.GenerateTokens(ctx, user)
+	if err != nil {
+		srv.l.Printf("error generating tokens on mfa: %v\n", err)
+		return nil, status.Error(codes.Internal, "error generating tokens")
+	}
+	srv.recordLoginEvent(ctx, domain.LoginEventLogin, user, "", "")
+
+	return &users.TokenResponse{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		User:         userResponse(user),
+	}, nil
+}
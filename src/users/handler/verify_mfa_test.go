@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	users "github.com/plagioriginal/users-service-grpc/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type verifyMFAMocks struct {
+	tokenHandler        *mocks.AccessTokenHandler
+	userService         *mocks.UserService
+	loginAttemptService *mocks.LoginAttemptService
+	mfaService          *mocks.MFAService
+	loginEventService   *mocks.LoginEventService
+}
+
+// Handler with a valid MFA token for the user, which isn't locked out.
+func newVerifyMFAHandler(user *domain.User) (UserGRPCHandler, verifyMFAMocks) {
+	m := verifyMFAMocks{
+		new(mocks.AccessTokenHandler),
+		new(mocks.UserService),
+		new(mocks.LoginAttemptService),
+		new(mocks.MFAService),
+		nil,
+	}
+
+	m.tokenHandler.On("GetUserIDFromMFAChallenge", "mfa-token").Once().Return(user.ID, domain.DefaultOrganizationID, nil)
+	m.userService.On("GetUserByUUID", mock.Anything, user.ID).Once().Return(user, nil)
+	m.loginAttemptService.On("GetRetryAfter", mock.Anything, user.Username, "").Once().Return(time.Duration(0), nil)
+
+	service := newHandler(m.tokenHandler, m.userService, m.loginAttemptService)
+	m.loginEventService = withLoginEvents(&service)
+	service.mfaService = m.mfaService
+	return service, m
+}
+
+func TestVerifyMFA_InvalidInput(t *testing.T) {
+	service := newHandler(nil, nil, nil)
+	invalidRequests := []*users.VerifyMFARequest{
+		nil,
+		{},
+		{MfaToken: "mfa-token"},
+		{Code: "123456"},
+	}
+
+	for _, req := range invalidRequests {
+		res, err := service.VerifyMFA(context.TODO(), req)
+		assert.Nil(t, res)
+		assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid request"))
+	}
+}
+
+func TestVerifyMFA_InvalidMFAToken(t *testing.T) {
+	tokenHandler := new(mocks.AccessTokenHandler)
+	service := newHandler(tokenHandler, nil, nil)
+	tokenHandler.On("GetUserIDFromMFAChallenge", "mfa-token").Once().Return(uuid.Nil, uuid.Nil, domain.ErrInvalidToken)
+
+	res, err := service.VerifyMFA(context.TODO(), &users.VerifyMFARequest{MfaToken: "mfa-token", Code: "123456"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Unauthenticated, "invalid token"))
+	tokenHandler.AssertExpectations(t)
+}
+
+func TestVerifyMFA_UserNotActive(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusSuspended}
+	tokenHandler := new(mocks.AccessTokenHandler)
+	userService := new(mocks.UserService)
+	service := newHandler(tokenHandler, userService, nil)
+	withLoginEvents(&service)
+
+	tokenHandler.On("GetUserIDFromMFAChallenge", "mfa-token").Once().Return(user.ID, domain.DefaultOrganizationID, nil)
+	userService.On("GetUserByUUID", mock.Anything, user.ID).Once().Return(user, nil)
+
+	res, err := service.VerifyMFA(context.TODO(), &users.VerifyMFARequest{MfaToken: "mfa-token", Code: "123456"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.PermissionDenied, "user is not active"))
+	userService.AssertExpectations(t)
+}
+
+func TestVerifyMFA_LockedOut(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	tokenHandler := new(mocks.AccessTokenHandler)
+	userService := new(mocks.UserService)
+	loginAttemptService := new(mocks.LoginAttemptService)
+	service := newHandler(tokenHandler, userService, loginAttemptService)
+	withLoginEvents(&service)
+
+	tokenHandler.On("GetUserIDFromMFAChallenge", "mfa-token").Once().Return(user.ID, domain.DefaultOrganizationID, nil)
+	userService.On("GetUserByUUID", mock.Anything, user.ID).Once().Return(user, nil)
+	loginAttemptService.On("GetRetryAfter", mock.Anything, "alice", "").Once().Return(30*time.Second, nil)
+
+	res, err := service.VerifyMFA(context.TODO(), &users.VerifyMFARequest{MfaToken: "mfa-token", Code: "123456"})
+	assert.Nil(t, res)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	loginAttemptService.AssertExpectations(t)
+}
+
+func TestVerifyMFA_ScopedToOrganizationOfChallenge(t *testing.T) {
+	organizationID := uuid.New()
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive, OrganizationID: organizationID}
+	inOrganization := mock.MatchedBy(func(ctx context.Context) bool {
+		return domain.OrganizationFromContext(ctx) == organizationID
+	})
+	tokenHandler := new(mocks.AccessTokenHandler)
+	userService := new(mocks.UserService)
+	loginAttemptService := new(mocks.LoginAttemptService)
+	service := newHandler(tokenHandler, userService, loginAttemptService)
+	withLoginEvents(&service)
+
+	tokenHandler.On("GetUserIDFromMFAChallenge", "mfa-token").Once().Return(user.ID, organizationID, nil)
+	userService.On("GetUserByUUID", inOrganization, user.ID).Once().Return(user, nil)
+	loginAttemptService.On("GetRetryAfter", inOrganization, "alice", "").Once().Return(30*time.Second, nil)
+
+	res, err := service.VerifyMFA(context.TODO(), &users.VerifyMFARequest{MfaToken: "mfa-token", Code: "123456"})
+	assert.Nil(t, res)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	userService.AssertExpectations(t)
+	loginAttemptService.AssertExpectations(t)
+}
+
+func TestVerifyMFA_NotEnrolled(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	service, m := newVerifyMFAHandler(user)
+	m.mfaService.On("VerifyCode", mock.Anything, user.ID, "123456").Once().Return(domain.ErrNotFound)
+
+	res, err := service.VerifyMFA(context.TODO(), &users.VerifyMFARequest{MfaToken: "mfa-token", Code: "123456"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.FailedPrecondition, "mfa enrollment required"))
+	m.mfaService.AssertExpectations(t)
+}
+
+func TestVerifyMFA_WrongCode(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	service, m := newVerifyMFAHandler(user)
+	m.mfaService.On("VerifyCode", mock.Anything, user.ID, "123456").Once().Return(domain.ErrInvalidToken)
+	m.loginAttemptService.On("RegisterFailure", mock.Anything, "alice", "").Once().Return(time.Duration(0), nil)
+
+	res, err := service.VerifyMFA(context.TODO(), &users.VerifyMFARequest{MfaToken: "mfa-token", Code: "123456"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.InvalidArgument, "invalid code"))
+	m.mfaService.AssertExpectations(t)
+	m.loginAttemptService.AssertExpectations(t)
+	assertLoginEventRecorded(t, m.loginEventService, domain.LoginEventLogin, user.ID, "invalid mfa code")
+}
+
+func TestVerifyMFA_WrongCodeTriggersLockout(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	service, m := newVerifyMFAHandler(user)
+	m.mfaService.On("VerifyRecoveryCode", mock.Anything, user.ID, "abcd-efgh").Once().Return(domain.ErrInvalidToken)
+	m.loginAttemptService.On("RegisterFailure", mock.Anything, "alice", "").Once().Return(time.Minute, nil)
+
+	res, err := service.VerifyMFA(context.TODO(), &users.VerifyMFARequest{MfaToken: "mfa-token", RecoveryCode: "abcd-efgh"})
+	assert.Nil(t, res)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	m.loginAttemptService.AssertExpectations(t)
+}
+
+func TestVerifyMFA_UnexpectedError(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Username: "alice", Status: domain.UserStatusActive}
+	service, m := newVerifyMFAHandler(user)
+	m.mfaService.On("VerifyCode", mock.Anything, user.ID, "123456").Once().Return(errors.New("boom"))
+
+	res, err := service.VerifyMFA(context.TODO(), &users.VerifyMFARequest{MfaToken: "mfa-token", Code: "123456"})
+	assert.Nil(t, res)
+	assert.Equal(t, err, status.Error(codes.Internal, "error verifying mfa"))
+}
+
+func TestVerifyMFA_Success(t *testing.T) {
+	requests := []*users.VerifyMFARequest{
+		{MfaToken: "mfa-token", Code: "123456"},
+		{MfaToken: "mfa-token", RecoveryCode: "abcd-efgh"},
+	}
+
+	for _, req := range requests {
+		roleID := uuid.New()
+		user := &domain.User{
+			ID:       uuid.New(),
+			Username: "alice",
+			RoleId:   roleID,
+			Status:   domain.UserStatusActive,
+			Role:     &domain.Role{ID: roleID, RoleSlug: "admin", RoleLabel: "Administrator"},
+		}
+		service, m := newVerifyMFAHandler(user)
+		if len(req.Code) > 0 {
+			m.mfaService.On("VerifyCode", mock.Anything, user.ID, req.Code).Once().Return(nil)
+		} else {
+			m.mfaService.On("VerifyRecoveryCode", mock.Anything, user.ID, req.RecoveryCode).Once().Return(nil)
+		}
+		m.loginAttemptService.On("RegisterSuccess", mock.Anything, "alice").Once().Return(nil)
+		m.tokenHandler.On("GenerateTokens", mock.Anything, user).Once().
+			Return(domain.TokenResponse{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil)
+
+		res, err := service.VerifyMFA(context.TODO(), req)
+		assert.Nil(t, err)
+		assert.Equal(t, &users.TokenResponse{
+			AccessToken:  "access-token",
+			RefreshToken: "refresh-token",
+			User: &users.UserResponse{
+				Id:       user.ID.String(),
+				Username: "alice",
+				Status:   "active",
+				Role: &users.UserResponse_RoleResponse{
+					Id:        roleID.String(),
+					RoleLabel: "Administrator",
+					RoleSlug:  "admin",
+				},
+			},
+		}, res)
+		m.tokenHandler.AssertExpectations(t)
+		m.mfaService.AssertExpectations(t)
+		m.loginAttemptService.AssertExpectations(t)
+		assertLoginEventRecorded(t, m.loginEventService, domain.LoginEventLogin, user.ID, "")
+	}
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Adds the column with the custom attributes of the users.
+// The index allows filtering the users by their attributes.
+func AddAttributes(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		ALTER TABLE IF EXISTS users
+		ADD COLUMN IF NOT EXISTS attributes jsonb NOT NULL DEFAULT '{}';
+		CREATE INDEX IF NOT EXISTS users_attributes_idx ON users USING GIN (attributes jsonb_path_ops);
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewAddAttributesMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "add-user-attributes",
+		Up:   AddAttributes,
+	}
+}
@@ -0,0 +1,77 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddAttributes_FailExec(t *testing.T) {
+	migration := NewAddAttributesMigration()
+	assert.Equal(t, migration.Name, "add-user-attributes")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		ALTER TABLE IF EXISTS users
+		ADD COLUMN IF NOT EXISTS attributes jsonb NOT NULL DEFAULT '{}';
+		CREATE INDEX IF NOT EXISTS users_attributes_idx ON users USING GIN (attributes jsonb_path_ops);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestAddAttributes_TimeoutReached(t *testing.T) {
+	migration := NewAddAttributesMigration()
+	assert.Equal(t, migration.Name, "add-user-attributes")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		ALTER TABLE IF EXISTS users
+		ADD COLUMN IF NOT EXISTS attributes jsonb NOT NULL DEFAULT '{}';
+		CREATE INDEX IF NOT EXISTS users_attributes_idx ON users USING GIN (attributes jsonb_path_ops);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestAddAttributes_Success(t *testing.T) {
+	migration := NewAddAttributesMigration()
+	assert.Equal(t, migration.Name, "add-user-attributes")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		ALTER TABLE IF EXISTS users
+		ADD COLUMN IF NOT EXISTS attributes jsonb NOT NULL DEFAULT '{}';
+		CREATE INDEX IF NOT EXISTS users_attributes_idx ON users USING GIN (attributes jsonb_path_ops);
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Adds the authentication backend that provisioned the users and keeps them in sync.
+func AddBackend(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		ALTER TABLE IF EXISTS users
+		ADD COLUMN IF NOT EXISTS backend text NOT NULL DEFAULT '';
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewAddBackendMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "add-user-backend",
+		Up:   AddBackend,
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/plagioriginal/user-microservice/domain"
 	"github.com/plagioriginal/user-microservice/helpers"
 	_rolesRepo "github.com/plagioriginal/user-microservice/roles/repository/postgres"
+	_usernameHistoryRepo "github.com/plagioriginal/user-microservice/username-history/repository/postgres"
 	_usersRepo "github.com/plagioriginal/user-microservice/users/repository/postgres"
 	_usersService "github.com/plagioriginal/user-microservice/users/service"
 )
@@ -38,8 +39,11 @@ func AddDefaultUser(bcryptCost int) func(ctx context.Context, db *sql.DB, logger
 		userService := _usersService.New(
 			userRepo,
 			roleRepo,
+			_usernameHistoryRepo.New(db),
 			timeoutDuration,
 			bcryptCost,
+			domain.UsernameSettings{},
+			nil,
 		)
 
 		user, _ := userRepo.GetByUsername(ctx, defaultUserUsername)
@@ -49,10 +53,12 @@ func AddDefaultUser(bcryptCost int) func(ctx context.Context, db *sql.DB, logger
 			return nil
 		}
 
+		// The password comes from the environment, so it has to be changed on the first login.
 		req := domain.StoreUserRequest{
-			Username: defaultUserUsername,
-			Password: defaultUserPassword,
-			RoleSlug: adminRoleSlug,
+			Username:           defaultUserUsername,
+			Password:           defaultUserPassword,
+			RoleSlug:           adminRoleSlug,
+			MustChangePassword: true,
 		}
 		if _, err := userService.Store(ctx, req); err != nil {
 			logger.Printf("error storing user on add-default-user migration: %v", err)
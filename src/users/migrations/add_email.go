@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Adds the email columns to the users table.
+// Emails are optional, but unique regardless of their casing.
+func AddEmail(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		ALTER TABLE IF EXISTS users
+		ADD COLUMN IF NOT EXISTS email varchar(255) NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS email_verified boolean NOT NULL DEFAULT false;
+		CREATE UNIQUE INDEX IF NOT EXISTS users_email_key ON users (lower(email)) WHERE email <> '';
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewAddEmailMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "add-email",
+		Up:   AddEmail,
+	}
+}
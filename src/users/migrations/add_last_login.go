@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Adds when the users last logged in, empty for the ones that never did.
+func AddLastLogin(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		ALTER TABLE IF EXISTS users
+		ADD COLUMN IF NOT EXISTS last_login_at timestamptz NULL;
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewAddLastLoginMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "add-user-last-login",
+		Up:   AddLastLogin,
+	}
+}
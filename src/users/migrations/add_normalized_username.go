@@ -0,0 +1,102 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/database/migrations"
+	"github.com/plagioriginal/user-microservice/helpers"
+)
+
+// Adds the normalized username column, used for case-insensitive lookups
+// and uniqueness. The username column keeps the casing the user chose.
+// Fails if existing usernames collide once normalized, listing them, so
+// they can be renamed before migrating.
+func AddNormalizedUsername(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		ALTER TABLE IF EXISTS users
+		ADD COLUMN IF NOT EXISTS normalized_username varchar(255) DEFAULT NULL
+	`
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT id, username, normalized_username FROM users`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type userToNormalize struct {
+		id         uuid.UUID
+		username   string
+		normalized string
+	}
+
+	usernamesByNormalized := make(map[string][]string)
+	toUpdate := make([]userToNormalize, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		var username string
+		var normalized sql.NullString
+		if err := rows.Scan(&id, &username, &normalized); err != nil {
+			return err
+		}
+
+		expected := helpers.NormalizeUsername(username)
+		usernamesByNormalized[expected] = append(usernamesByNormalized[expected], username)
+		if !normalized.Valid || normalized.String != expected {
+			toUpdate = append(toUpdate, userToNormalize{id, username, expected})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	hasCollisions := false
+	for normalized, usernames := range usernamesByNormalized {
+		if len(usernames) > 1 {
+			hasCollisions = true
+			logger.Printf("usernames [%s] collide as {%s}\n", strings.Join(usernames, ", "), normalized)
+		}
+	}
+	if hasCollisions {
+		return errors.New("usernames collide once normalized, rename them before migrating")
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, user := range toUpdate {
+		_, err := tx.ExecContext(ctx, `UPDATE users SET normalized_username = $1 WHERE id = $2`, user.normalized, user.id)
+		if err != nil {
+			logger.Printf("error normalizing username {%s}: %v\n", user.username, err)
+			return err
+		}
+	}
+
+	query = `
+		ALTER TABLE users ALTER COLUMN normalized_username SET NOT NULL;
+		CREATE UNIQUE INDEX IF NOT EXISTS users_normalized_username_key ON users (normalized_username);
+	`
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Creates a new migration
+func NewAddNormalizedUsernameMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "add-normalized-username",
+		Up:   AddNormalizedUsername,
+	}
+}
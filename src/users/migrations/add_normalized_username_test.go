@@ -0,0 +1,87 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	addNormalizedUsernameColumnQuery = `
+		ALTER TABLE IF EXISTS users
+		ADD COLUMN IF NOT EXISTS normalized_username varchar(255) DEFAULT NULL
+	`
+	selectUsernamesQuery     = `SELECT id, username, normalized_username FROM users`
+	updateNormalizedQuery    = `UPDATE users SET normalized_username = $1 WHERE id = $2`
+	addNormalizedUniqueQuery = `
+		ALTER TABLE users ALTER COLUMN normalized_username SET NOT NULL;
+		CREATE UNIQUE INDEX IF NOT EXISTS users_normalized_username_key ON users (normalized_username);
+	`
+)
+
+func TestAddNormalizedUsername_FailExec(t *testing.T) {
+	migration := NewAddNormalizedUsernameMigration()
+	assert.Equal(t, migration.Name, "add-normalized-username")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	mock.ExpectExec(regexp.QuoteMeta(addNormalizedUsernameColumnQuery)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestAddNormalizedUsername_FailsOnCollisions(t *testing.T) {
+	migration := NewAddNormalizedUsernameMigration()
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	mock.ExpectExec(regexp.QuoteMeta(addNormalizedUsernameColumnQuery)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(selectUsernamesQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "normalized_username"}).
+			AddRow(uuid.New(), "Alice", nil).
+			AddRow(uuid.New(), "alice", nil).
+			AddRow(uuid.New(), "bob", nil))
+
+	err = migration.Up(context.TODO(), db, log.New(ioutil.Discard, "tests: ", log.Flags()))
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "usernames collide once normalized, rename them before migrating")
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestAddNormalizedUsername_Success(t *testing.T) {
+	migration := NewAddNormalizedUsernameMigration()
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	aliceId, bobId := uuid.New(), uuid.New()
+	mock.ExpectExec(regexp.QuoteMeta(addNormalizedUsernameColumnQuery)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(selectUsernamesQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "normalized_username"}).
+			AddRow(aliceId, "Alice", nil).
+			AddRow(bobId, "bob", "bob"))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(updateNormalizedQuery)).
+		WithArgs("alice", aliceId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(addNormalizedUniqueQuery)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err = migration.Up(context.TODO(), db, log.New(ioutil.Discard, "tests: ", log.Flags()))
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Adds the flag forcing the users to change their password on their next login,
+// and when they last changed it, which is the time of the migration for the existing users.
+func AddPasswordChange(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		ALTER TABLE IF EXISTS users
+		ADD COLUMN IF NOT EXISTS must_change_password boolean NOT NULL DEFAULT false,
+		ADD COLUMN IF NOT EXISTS password_changed_at timestamptz NOT NULL DEFAULT NOW();
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewAddPasswordChangeMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "add-user-password-change",
+		Up:   AddPasswordChange,
+	}
+}
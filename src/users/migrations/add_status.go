@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+)
+
+// Adds the lifecycle status columns to the users table.
+// Existing users are active.
+func AddStatus(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	query := `
+		ALTER TABLE IF EXISTS users
+		ADD COLUMN IF NOT EXISTS status varchar(16) NOT NULL DEFAULT 'active'
+			CHECK (status IN ('pending', 'active', 'suspended', 'deactivated')),
+		ADD COLUMN IF NOT EXISTS status_reason text NOT NULL DEFAULT '';
+	`
+
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+// Creates a new migration
+func NewAddStatusMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "add-user-status",
+		Up:   AddStatus,
+	}
+}
@@ -0,0 +1,80 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddStatus_FailExec(t *testing.T) {
+	migration := NewAddStatusMigration()
+	assert.Equal(t, migration.Name, "add-user-status")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		ALTER TABLE IF EXISTS users
+		ADD COLUMN IF NOT EXISTS status varchar(16) NOT NULL DEFAULT 'active'
+			CHECK (status IN ('pending', 'active', 'suspended', 'deactivated')),
+		ADD COLUMN IF NOT EXISTS status_reason text NOT NULL DEFAULT '';
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func TestAddStatus_TimeoutReached(t *testing.T) {
+	migration := NewAddStatusMigration()
+	assert.Equal(t, migration.Name, "add-user-status")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		ALTER TABLE IF EXISTS users
+		ADD COLUMN IF NOT EXISTS status varchar(16) NOT NULL DEFAULT 'active'
+			CHECK (status IN ('pending', 'active', 'suspended', 'deactivated')),
+		ADD COLUMN IF NOT EXISTS status_reason text NOT NULL DEFAULT '';
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("boom"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = migration.Up(ctx, db, nil)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func TestAddStatus_Success(t *testing.T) {
+	migration := NewAddStatusMigration()
+	assert.Equal(t, migration.Name, "add-user-status")
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+
+	query := `
+		ALTER TABLE IF EXISTS users
+		ADD COLUMN IF NOT EXISTS status varchar(16) NOT NULL DEFAULT 'active'
+			CHECK (status IN ('pending', 'active', 'suspended', 'deactivated')),
+		ADD COLUMN IF NOT EXISTS status_reason text NOT NULL DEFAULT '';
+	`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = migration.Up(context.TODO(), db, nil)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/plagioriginal/user-microservice/database/migrations"
+	"github.com/plagioriginal/user-microservice/helpers"
+	_usersRepo "github.com/plagioriginal/user-microservice/users/repository/postgres"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Flags the default user added before the password change existed to change its password,
+// when it still has the one from the environment.
+func FlagDefaultUserPasswordChange(ctx context.Context, db *sql.DB, logger *log.Logger) error {
+	defaultUserUsername := helpers.StringFromContext(ctx, DefaultUserNameKey)
+	defaultUserPassword := helpers.StringFromContext(ctx, DefaultUserPasswordKey)
+
+	if len(defaultUserUsername) == 0 || len(defaultUserPassword) == 0 {
+		return errors.New("cant flag the default user without credentials")
+	}
+
+	user, err := _usersRepo.New(db).GetByUsername(ctx, defaultUserUsername)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if user == nil || user.MustChangePassword {
+		logger.Println("nothing to flag, skipping flag-default-user-password-change migration")
+		return nil
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(defaultUserPassword)) != nil {
+		logger.Println("default user password already changed, skipping flag-default-user-password-change migration")
+		return nil
+	}
+
+	query := `
+		UPDATE users
+		SET must_change_password = true, updated_at = NOW(), version = version + 1
+		WHERE id = $1;
+	`
+
+	_, err = db.ExecContext(ctx, query, user.ID)
+	return err
+}
+
+// Creates a new migration
+func NewFlagDefaultUserPasswordChangeMigration() migrations.Migration {
+	return migrations.Migration{
+		Name: "flag-default-user-password-change",
+		Up:   FlagDefaultUserPasswordChange,
+	}
+}
@@ -0,0 +1,113 @@
+package migrations
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const getDefaultUserQuery = `
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
+		FROM users
+		WHERE normalized_username = $1 AND organization_id = $2 AND deleted_at IS NULL
+		LIMIT 1
+	`
+
+const flagDefaultUserQuery = `
+		UPDATE users
+		SET must_change_password = true, updated_at = NOW(), version = version + 1
+		WHERE id = $1;
+	`
+
+func defaultUserRows(t *testing.T, id uuid.UUID, password string, mustChangePassword bool) *sqlmock.Rows {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	assert.Nil(t, err)
+
+	now := time.Now()
+	return sqlmock.NewRows(
+		[]string{"id", "first_name", "last_name", "username", "email", "email_verified", "password", "role_id", "refresh_token_id", "status", "status_reason", "attributes", "last_login_at", "must_change_password", "password_changed_at", "backend", "version", "created_at", "updated_at"},
+	).AddRow(id, "", "", "admin", "", false, string(hash), uuid.New(), uuid.Nil, "active", "", []byte(`{}`), nil, mustChangePassword, now, "", 1, now, now)
+}
+
+func defaultUserContext() context.Context {
+	ctx := context.WithValue(context.TODO(), DefaultUserNameKey, "admin")
+	return context.WithValue(ctx, DefaultUserPasswordKey, "env-password")
+}
+
+func TestFlagDefaultUserPasswordChange_NoDefaultUserInContext(t *testing.T) {
+	migration := NewFlagDefaultUserPasswordChangeMigration()
+	assert.Equal(t, "flag-default-user-password-change", migration.Name)
+
+	err := migration.Up(context.TODO(), nil, nil)
+	assert.Error(t, err)
+}
+
+func TestFlagDefaultUserPasswordChange_StillEnvironmentPassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(getDefaultUserQuery)).
+		ExpectQuery().
+		WithArgs("admin", domain.DefaultOrganizationID).
+		WillReturnRows(defaultUserRows(t, id, "env-password", false))
+	mock.ExpectExec(regexp.QuoteMeta(flagDefaultUserQuery)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = FlagDefaultUserPasswordChange(defaultUserContext(), db, log.New(ioutil.Discard, "tests: ", log.Flags()))
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestFlagDefaultUserPasswordChange_Skipped(t *testing.T) {
+	tests := map[string]struct {
+		password           string
+		mustChangePassword bool
+	}{
+		"password already changed": {"chosen-password", false},
+		"already flagged":          {"env-password", true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.Nil(t, err)
+			defer db.Close()
+
+			mock.ExpectPrepare(regexp.QuoteMeta(getDefaultUserQuery)).
+				ExpectQuery().
+				WithArgs("admin", domain.DefaultOrganizationID).
+				WillReturnRows(defaultUserRows(t, uuid.New(), test.password, test.mustChangePassword))
+
+			err = FlagDefaultUserPasswordChange(defaultUserContext(), db, log.New(ioutil.Discard, "tests: ", log.Flags()))
+			assert.Nil(t, err)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFlagDefaultUserPasswordChange_NoDefaultUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(getDefaultUserQuery)).
+		ExpectQuery().
+		WithArgs("admin", domain.DefaultOrganizationID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	err = FlagDefaultUserPasswordChange(defaultUserContext(), db, log.New(ioutil.Discard, "tests: ", log.Flags()))
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
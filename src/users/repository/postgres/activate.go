@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Activates a pending user at the given version, setting its password and profile.
+// Its email is marked as verified, as it's where the user was reached. The invitation is deleted
+// in the same transaction, so it's only used up when the user is activated.
+func (r PostgresRepository) Activate(ctx context.Context, id uuid.UUID, version int, password string, profile domain.UserProfile, invitationID uuid.UUID) error {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	invitationQuery := `
+		DELETE FROM one_time_tokens
+		WHERE id = $1 AND user_id = $2 AND purpose = $3
+	`
+
+	result, err := tx.ExecContext(ctx, invitationQuery, invitationID, id, domain.TokenPurposeInvitation)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrInvalidToken
+	}
+
+	query := `
+		UPDATE users
+		SET password = $1, first_name = $2, last_name = $3, status = $4, email_verified = true, password_changed_at = $5, updated_at = $5, version = version + 1
+		WHERE id = $6 AND status = $7 AND organization_id = $8 AND version = $9 AND deleted_at IS NULL
+	`
+
+	result, err = tx.ExecContext(
+		ctx,
+		query,
+		password,
+		profile.FirstName,
+		profile.LastName,
+		domain.UserStatusActive,
+		time.Now(),
+		id,
+		domain.UserStatusPending,
+		domain.OrganizationFromContext(ctx),
+		version,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err = result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return r.missedUpdateError(ctx, tx, id)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const deleteInvitationQuery = `
+		DELETE FROM one_time_tokens
+		WHERE id = $1 AND user_id = $2 AND purpose = $3
+	`
+
+const activateQuery = `
+		UPDATE users
+		SET password = $1, first_name = $2, last_name = $3, status = $4, email_verified = true, password_changed_at = $5, updated_at = $5, version = version + 1
+		WHERE id = $6 AND status = $7 AND organization_id = $8 AND version = $9 AND deleted_at IS NULL
+	`
+
+var activateProfile = domain.UserProfile{FirstName: "Alice", LastName: "Smith"}
+
+func expectInvitationDeleted(mock sqlmock.Sqlmock, invitationID uuid.UUID, id uuid.UUID, affected int64) {
+	mock.ExpectExec(regexp.QuoteMeta(deleteInvitationQuery)).
+		WithArgs(invitationID, id, domain.TokenPurposeInvitation).
+		WillReturnResult(sqlmock.NewResult(0, affected))
+}
+
+func Test_Activate_ErrorBeginningTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin().WillReturnError(errors.New("boom"))
+
+	repo := PostgresRepository{db}
+	err = repo.Activate(context.TODO(), uuid.New(), 1, "hash", activateProfile, uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func Test_Activate_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id, invitationID := uuid.New(), uuid.New()
+	mock.ExpectBegin()
+	expectInvitationDeleted(mock, invitationID, id, 1)
+	mock.ExpectExec(regexp.QuoteMeta(activateQuery)).
+		WithArgs("hash", "Alice", "Smith", domain.UserStatusActive, anyTime{}, id, domain.UserStatusPending, domain.DefaultOrganizationID, 1).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	repo := PostgresRepository{db}
+	err = repo.Activate(ctx, id, 1, "hash", activateProfile, invitationID)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func Test_Activate_InvitationAlreadyUsed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id, invitationID := uuid.New(), uuid.New()
+	mock.ExpectBegin()
+	expectInvitationDeleted(mock, invitationID, id, 0)
+	mock.ExpectRollback()
+
+	repo := PostgresRepository{db}
+	err = repo.Activate(context.TODO(), id, 1, "hash", activateProfile, invitationID)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Activate_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id, invitationID := uuid.New(), uuid.New()
+	mock.ExpectBegin()
+	expectInvitationDeleted(mock, invitationID, id, 1)
+	mock.ExpectExec(regexp.QuoteMeta(activateQuery)).
+		WithArgs("hash", "Alice", "Smith", domain.UserStatusActive, anyTime{}, id, domain.UserStatusPending, domain.DefaultOrganizationID, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	expectMissedUpdate(mock, id, false)
+	mock.ExpectRollback()
+
+	repo := PostgresRepository{db}
+	err = repo.Activate(context.TODO(), id, 1, "hash", activateProfile, invitationID)
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Activate_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	// The invitation is kept when the user isn't activated.
+	id, invitationID := uuid.New(), uuid.New()
+	mock.ExpectBegin()
+	expectInvitationDeleted(mock, invitationID, id, 1)
+	mock.ExpectExec(regexp.QuoteMeta(activateQuery)).
+		WithArgs("hash", "Alice", "Smith", domain.UserStatusActive, anyTime{}, id, domain.UserStatusPending, domain.DefaultOrganizationID, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	expectMissedUpdate(mock, id, true)
+	mock.ExpectRollback()
+
+	repo := PostgresRepository{db}
+	err = repo.Activate(context.TODO(), id, 1, "hash", activateProfile, invitationID)
+	assert.Equal(t, domain.ErrVersionConflict, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Activate_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id, invitationID := uuid.New(), uuid.New()
+	mock.ExpectBegin()
+	expectInvitationDeleted(mock, invitationID, id, 1)
+	mock.ExpectExec(regexp.QuoteMeta(activateQuery)).
+		WithArgs("hash", "Alice", "Smith", domain.UserStatusActive, anyTime{}, id, domain.UserStatusPending, domain.DefaultOrganizationID, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := PostgresRepository{db}
+	err = repo.Activate(context.TODO(), id, 1, "hash", activateProfile, invitationID)
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Sets a password chosen by a user, marking when it was changed.
+// The user no longer has to change it.
+func (r PostgresRepository) ChangePassword(ctx context.Context, id uuid.UUID, password string) error {
+	query := `
+		UPDATE users
+		SET password = $1, must_change_password = false, password_changed_at = $2, updated_at = $2, version = version + 1
+		WHERE id = $3 AND organization_id = $4 AND deleted_at IS NULL
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	result, err := stmt.ExecContext(ctx, password, time.Now(), id, domain.OrganizationFromContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Counts the users whose password hashes are in each of the schemes,
+// the scheme being the first "$" separated field of the hash.
+func (r PostgresRepository) CountPasswordSchemes(ctx context.Context, schemes []string) (map[string]int, error) {
+	result := make(map[string]int, len(schemes))
+
+	query := `
+		SELECT split_part(password, '$', 1) AS scheme, count(*)
+		FROM users
+		WHERE split_part(password, '$', 1) = ANY($1::text[]) AND organization_id = $2 AND deleted_at IS NULL
+		GROUP BY scheme
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return result, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, pq.Array(schemes), domain.OrganizationFromContext(ctx))
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var scheme string
+		var count int
+		if err = rows.Scan(&scheme, &count); err != nil {
+			return make(map[string]int), err
+		}
+		result[scheme] = count
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const countPasswordSchemesQuery = `
+		SELECT split_part(password, '$', 1) AS scheme, count(*)
+		FROM users
+		WHERE split_part(password, '$', 1) = ANY($1::text[]) AND organization_id = $2 AND deleted_at IS NULL
+		GROUP BY scheme
+	`
+
+func Test_CountPasswordSchemes_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(countPasswordSchemesQuery)).WillReturnError(errors.New("boom"))
+
+	repo := PostgresRepository{db}
+	counts, err := repo.CountPasswordSchemes(context.TODO(), []string{"sha1"})
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, counts)
+}
+
+func Test_CountPasswordSchemes_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(countPasswordSchemesQuery)).
+		ExpectQuery().
+		WithArgs(pq.Array([]string{"sha1"}), domain.DefaultOrganizationID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	repo := PostgresRepository{db}
+	counts, err := repo.CountPasswordSchemes(ctx, []string{"sha1"})
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, counts)
+}
+
+func Test_CountPasswordSchemes_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"scheme", "count"}).
+		AddRow("sha1", 3).
+		AddRow("pbkdf2_sha256", 12)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(countPasswordSchemesQuery)).
+		ExpectQuery().
+		WithArgs(pq.Array([]string{"pbkdf2_sha256", "sha1"}), domain.DefaultOrganizationID).
+		WillReturnRows(rows)
+
+	repo := PostgresRepository{db}
+	counts, err := repo.CountPasswordSchemes(context.TODO(), []string{"pbkdf2_sha256", "sha1"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"sha1": 3, "pbkdf2_sha256": 12}, counts)
+}
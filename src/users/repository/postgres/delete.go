@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Soft deletes a user at the given version, deleting its refresh token to end its session.
+// The user keeps its username until it is purged.
+func (r PostgresRepository) Delete(ctx context.Context, id uuid.UUID, version int, deletedAt time.Time) error {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE users
+		SET deleted_at = $2, updated_at = $2, version = version + 1
+		WHERE id = $1 AND organization_id = $3 AND version = $4 AND deleted_at IS NULL
+		RETURNING refresh_token_id
+	`
+
+	var refreshTokenID uuid.NullUUID
+	err = tx.QueryRowContext(ctx, query, id, deletedAt, domain.OrganizationFromContext(ctx), version).Scan(&refreshTokenID)
+	if err == sql.ErrNoRows {
+		return r.missedUpdateError(ctx, tx, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if refreshTokenID.Valid {
+		_, err = tx.ExecContext(ctx, `DELETE FROM refresh_tokens WHERE id = $1`, refreshTokenID.UUID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const deleteQuery = `
+		UPDATE users
+		SET deleted_at = $2, updated_at = $2, version = version + 1
+		WHERE id = $1 AND organization_id = $3 AND version = $4 AND deleted_at IS NULL
+		RETURNING refresh_token_id
+	`
+
+func Test_Delete_ErrorBeginningTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin().WillReturnError(errors.New("boom"))
+
+	err = PostgresRepository{db}.Delete(context.TODO(), uuid.New(), 3, time.Now())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func Test_Delete_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userId := uuid.New()
+	deletedAt := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(deleteQuery)).
+		WithArgs(userId, deletedAt, domain.DefaultOrganizationID, 3).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = PostgresRepository{db}.Delete(ctx, userId, 3, deletedAt)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func Test_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userId := uuid.New()
+	deletedAt := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(deleteQuery)).
+		WithArgs(userId, deletedAt, domain.DefaultOrganizationID, 3).
+		WillReturnError(sql.ErrNoRows)
+	expectMissedUpdate(mock, userId, false)
+	mock.ExpectRollback()
+
+	err = PostgresRepository{db}.Delete(context.TODO(), userId, 3, deletedAt)
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Delete_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userId := uuid.New()
+	deletedAt := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(deleteQuery)).
+		WithArgs(userId, deletedAt, domain.DefaultOrganizationID, 3).
+		WillReturnError(sql.ErrNoRows)
+	expectMissedUpdate(mock, userId, true)
+	mock.ExpectRollback()
+
+	err = PostgresRepository{db}.Delete(context.TODO(), userId, 3, deletedAt)
+	assert.Equal(t, domain.ErrVersionConflict, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Delete_ErrorDeletingRefreshTokenRollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userId := uuid.New()
+	refreshTokenId := uuid.New()
+	deletedAt := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(deleteQuery)).
+		WithArgs(userId, deletedAt, domain.DefaultOrganizationID, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"refresh_token_id"}).AddRow(refreshTokenId))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM refresh_tokens WHERE id = $1`)).
+		WithArgs(refreshTokenId).
+		WillReturnError(errors.New("boom"))
+	mock.ExpectRollback()
+
+	err = PostgresRepository{db}.Delete(context.TODO(), userId, 3, deletedAt)
+	assert.Equal(t, "boom", err.Error())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Delete_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userId := uuid.New()
+	refreshTokenId := uuid.New()
+	deletedAt := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(deleteQuery)).
+		WithArgs(userId, deletedAt, domain.DefaultOrganizationID, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"refresh_token_id"}).AddRow(refreshTokenId))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM refresh_tokens WHERE id = $1`)).
+		WithArgs(refreshTokenId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = PostgresRepository{db}.Delete(context.TODO(), userId, 3, deletedAt)
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
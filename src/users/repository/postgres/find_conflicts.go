@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/helpers"
+)
+
+// Returns if a username and an email are taken by users of the organization of the context.
+// Deleted users still hold theirs, like the unique indexes enforce.
+func (r PostgresRepository) FindConflicts(ctx context.Context, username string, email string) (bool, bool, error) {
+	query := `
+		SELECT
+			EXISTS (SELECT 1 FROM users WHERE organization_id = $1 AND normalized_username = $2),
+			EXISTS (SELECT 1 FROM users WHERE organization_id = $1 AND email <> '' AND lower(email) = lower($3))
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return false, false, err
+	}
+
+	var usernameTaken, emailTaken bool
+	err = stmt.QueryRowContext(
+		ctx,
+		domain.OrganizationFromContext(ctx),
+		helpers.NormalizeUsername(username),
+		strings.TrimSpace(email),
+	).Scan(&usernameTaken, &emailTaken)
+	return usernameTaken, emailTaken, err
+}
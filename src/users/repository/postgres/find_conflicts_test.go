@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const findConflictsQuery = `
+		SELECT
+			EXISTS (SELECT 1 FROM users WHERE organization_id = $1 AND normalized_username = $2),
+			EXISTS (SELECT 1 FROM users WHERE organization_id = $1 AND email <> '' AND lower(email) = lower($3))
+	`
+
+func Test_FindConflicts_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(findConflictsQuery)).WillReturnError(errors.New("boom"))
+
+	repo := PostgresRepository{db}
+	usernameTaken, emailTaken, err := repo.FindConflicts(context.TODO(), "alice", "alice@example.com")
+	assert.Equal(t, "boom", err.Error())
+	assert.False(t, usernameTaken)
+	assert.False(t, emailTaken)
+}
+
+func Test_FindConflicts_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	organizationID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(findConflictsQuery)).
+		ExpectQuery().
+		WithArgs(organizationID, "alice", "Alice@Example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"username", "email"}).AddRow(false, true))
+
+	repo := PostgresRepository{db}
+	ctx := domain.WithOrganization(context.TODO(), organizationID)
+	usernameTaken, emailTaken, err := repo.FindConflicts(ctx, " Alice ", " Alice@Example.com ")
+	assert.Nil(t, err)
+	assert.False(t, usernameTaken)
+	assert.True(t, emailTaken)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Gets a user by their email, regardless of its casing.
+func (r PostgresRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	query := `
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
+		FROM users 
+		WHERE lower(email) = lower($1) AND email <> '' AND organization_id = $2 AND deleted_at IS NULL
+		LIMIT 1
+	`
+
+	statement, err := r.Db.PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	row := statement.QueryRowContext(ctx, email, domain.OrganizationFromContext(ctx))
+	return r.scanUserRow(ctx, row)
+}
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetByEmail_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
+		FROM users 
+		WHERE lower(email) = lower($1) AND email <> '' AND organization_id = $2 AND deleted_at IS NULL
+		LIMIT 1
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	repo := PostgresRepository{db}
+	user, err := repo.GetByEmail(context.TODO(), "alice@example.com")
+	assert.Nil(t, user)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func Test_GetByEmail_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
+		FROM users 
+		WHERE lower(email) = lower($1) AND email <> '' AND organization_id = $2 AND deleted_at IS NULL
+		LIMIT 1
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs("alice@example.com", domain.DefaultOrganizationID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	repo := PostgresRepository{db}
+	user, err := repo.GetByEmail(ctx, "alice@example.com")
+	assert.Nil(t, user)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func Test_GetByEmail_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userId := uuid.New()
+	roleId := uuid.New()
+	createdAt := time.Now()
+
+	expectedResult := sqlmock.NewRows(
+		[]string{"id", "first_name", "last_name", "username", "email", "email_verified", "password", "role_id", "refresh_token_id", "status", "status_reason", "attributes", "last_login_at", "must_change_password", "password_changed_at", "backend", "version", "created_at", "updated_at"},
+	).AddRow(userId, "", "", "alice", "Alice@example.com", true, "wrong password wtv", roleId, uuid.Nil, "active", "", []byte(`{"timezone":"Europe/Lisbon"}`), nil, false, createdAt, "", 1, createdAt, createdAt)
+
+	query := `
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
+		FROM users 
+		WHERE lower(email) = lower($1) AND email <> '' AND organization_id = $2 AND deleted_at IS NULL
+		LIMIT 1
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs("alice@example.com", domain.DefaultOrganizationID).
+		WillReturnRows(expectedResult)
+
+	repo := PostgresRepository{db}
+	user, err := repo.GetByEmail(context.TODO(), "alice@example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, userId, user.ID)
+	assert.Equal(t, "Alice@example.com", user.Email)
+	assert.True(t, user.EmailVerified)
+}
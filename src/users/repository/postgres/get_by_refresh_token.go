@@ -10,9 +10,9 @@ import (
 // Gets a user by the refresh token id
 func (r PostgresRepository) GetByRefreshToken(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	query := `
-		SELECT id, first_name, last_name, username, password, role_id, refresh_token_id, created_at, updated_at
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
 		FROM users 
-		WHERE refresh_token_id = $1 AND deleted_at IS NULL
+		WHERE refresh_token_id = $1 AND organization_id = $2 AND deleted_at IS NULL
 		LIMIT 1
 	`
 
@@ -22,6 +22,6 @@ func (r PostgresRepository) GetByRefreshToken(ctx context.Context, id uuid.UUID)
 		return nil, err
 	}
 
-	row := statement.QueryRowContext(ctx, id)
-	return r.scanUserRow(row)
+	row := statement.QueryRowContext(ctx, id, domain.OrganizationFromContext(ctx))
+	return r.scanUserRow(ctx, row)
 }
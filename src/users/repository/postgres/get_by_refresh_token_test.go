@@ -9,6 +9,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,9 +19,9 @@ func Test_GetByRefreshToken_ErrorPreparingContext(t *testing.T) {
 	defer db.Close()
 
 	query := `
-		SELECT id, first_name, last_name, username, password, role_id, refresh_token_id, created_at, updated_at
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
 		FROM users 
-		WHERE refresh_token_id = $1 AND deleted_at IS NULL
+		WHERE refresh_token_id = $1 AND organization_id = $2 AND deleted_at IS NULL
 		LIMIT 1
 	`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
@@ -39,14 +40,14 @@ func Test_GetByRefreshToken_TimeoutReached(t *testing.T) {
 
 	userId := uuid.New()
 	query := `
-		SELECT id, first_name, last_name, username, password, role_id, refresh_token_id, created_at, updated_at
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
 		FROM users 
-		WHERE refresh_token_id = $1 AND deleted_at IS NULL
+		WHERE refresh_token_id = $1 AND organization_id = $2 AND deleted_at IS NULL
 		LIMIT 1
 	`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs(userId).
+		WithArgs(userId, domain.DefaultOrganizationID).
 		WillDelayFor(time.Duration(200 * time.Millisecond)).
 		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
 
@@ -70,18 +71,18 @@ func Test_GetByRefreshToken_Success(t *testing.T) {
 	defer db.Close()
 
 	expectedResult := sqlmock.NewRows(
-		[]string{"id", "first_name", "last_name", "username", "password", "role_id", "refresh_token_id", "created_at", "updated_at"},
-	).AddRow(userId, "cenas", "", "admin", "wrong password wtv", roleId, uuid.Nil, createdAt, createdAt)
+		[]string{"id", "first_name", "last_name", "username", "email", "email_verified", "password", "role_id", "refresh_token_id", "status", "status_reason", "attributes", "last_login_at", "must_change_password", "password_changed_at", "backend", "version", "created_at", "updated_at"},
+	).AddRow(userId, "cenas", "", "admin", "", false, "wrong password wtv", roleId, uuid.Nil, "active", "", []byte(`{"timezone":"Europe/Lisbon"}`), nil, false, createdAt, "", 1, createdAt, createdAt)
 
 	query := `
-		SELECT id, first_name, last_name, username, password, role_id, refresh_token_id, created_at, updated_at
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
 		FROM users 
-		WHERE refresh_token_id = $1 AND deleted_at IS NULL
+		WHERE refresh_token_id = $1 AND organization_id = $2 AND deleted_at IS NULL
 		LIMIT 1
 	`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs(userId).
+		WithArgs(userId, domain.DefaultOrganizationID).
 		WillReturnRows(expectedResult)
 
 	ctx := context.TODO()
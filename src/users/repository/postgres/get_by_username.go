@@ -4,14 +4,15 @@ import (
 	"context"
 
 	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/helpers"
 )
 
-// Gets a user by their respective username.
+// Gets a user by their respective username, regardless of its casing.
 func (r PostgresRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
 	query := `
-		SELECT id, first_name, last_name, username, password, role_id, refresh_token_id, created_at, updated_at
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
 		FROM users 
-		WHERE username = $1 AND deleted_at IS NULL
+		WHERE normalized_username = $1 AND organization_id = $2 AND deleted_at IS NULL
 		LIMIT 1
 	`
 
@@ -21,6 +22,6 @@ func (r PostgresRepository) GetByUsername(ctx context.Context, username string)
 		return nil, err
 	}
 
-	row := statement.QueryRowContext(ctx, username)
-	return r.scanUserRow(row)
+	row := statement.QueryRowContext(ctx, helpers.NormalizeUsername(username), domain.OrganizationFromContext(ctx))
+	return r.scanUserRow(ctx, row)
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,9 +19,9 @@ func Test_GetByUsername_ErrorPreparingContext(t *testing.T) {
 	defer db.Close()
 
 	query := `
-		SELECT id, first_name, last_name, username, password, role_id, refresh_token_id, created_at, updated_at
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
 		FROM users 
-		WHERE username = $1 AND deleted_at IS NULL
+		WHERE normalized_username = $1 AND organization_id = $2 AND deleted_at IS NULL
 		LIMIT 1
 	`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
@@ -38,14 +39,14 @@ func Test_GetByUsername_TimeoutReached(t *testing.T) {
 	defer db.Close()
 
 	query := `
-		SELECT id, first_name, last_name, username, password, role_id, refresh_token_id, created_at, updated_at
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
 		FROM users 
-		WHERE username = $1 AND deleted_at IS NULL
+		WHERE normalized_username = $1 AND organization_id = $2 AND deleted_at IS NULL
 		LIMIT 1
 	`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs("admin").
+		WithArgs("admin", domain.DefaultOrganizationID).
 		WillDelayFor(time.Duration(200 * time.Millisecond)).
 		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
 
@@ -69,18 +70,18 @@ func Test_GetByUsername_Success(t *testing.T) {
 	defer db.Close()
 
 	expectedResult := sqlmock.NewRows(
-		[]string{"id", "first_name", "last_name", "username", "password", "role_id", "refresh_token_id", "created_at", "updated_at"},
-	).AddRow(userId, "cenas", "", "admin", "wrong password wtv", roleId, uuid.Nil, createdAt, createdAt)
+		[]string{"id", "first_name", "last_name", "username", "email", "email_verified", "password", "role_id", "refresh_token_id", "status", "status_reason", "attributes", "last_login_at", "must_change_password", "password_changed_at", "backend", "version", "created_at", "updated_at"},
+	).AddRow(userId, "cenas", "", "admin", "", false, "wrong password wtv", roleId, uuid.Nil, "active", "", []byte(`{"timezone":"Europe/Lisbon"}`), nil, false, createdAt, "", 1, createdAt, createdAt)
 
 	query := `
-		SELECT id, first_name, last_name, username, password, role_id, refresh_token_id, created_at, updated_at
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
 		FROM users 
-		WHERE username = $1 AND deleted_at IS NULL
+		WHERE normalized_username = $1 AND organization_id = $2 AND deleted_at IS NULL
 		LIMIT 1
 	`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs("admin").
+		WithArgs("admin", domain.DefaultOrganizationID).
 		WillReturnRows(expectedResult)
 
 	ctx := context.TODO()
@@ -98,3 +99,55 @@ func Test_GetByUsername_Success(t *testing.T) {
 	assert.Equal(t, createdUser.UpdatedAt, createdAt)
 	assert.Nil(t, err)
 }
+
+func Test_GetByUsername_IgnoresCasing(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
+		FROM users 
+		WHERE normalized_username = $1 AND organization_id = $2 AND deleted_at IS NULL
+		LIMIT 1
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs("admin", domain.DefaultOrganizationID).
+		WillReturnError(errors.New("boom"))
+
+	repo := PostgresRepository{db}
+	user, err := repo.GetByUsername(context.TODO(), " ADMIN ")
+	assert.Nil(t, user)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_GetByUsername_ScopedToOrganization(t *testing.T) {
+	userId := uuid.New()
+	organizationID := uuid.New()
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	expectedResult := sqlmock.NewRows(
+		[]string{"id", "first_name", "last_name", "username", "email", "email_verified", "password", "role_id", "refresh_token_id", "status", "status_reason", "attributes", "last_login_at", "must_change_password", "password_changed_at", "backend", "version", "created_at", "updated_at"},
+	).AddRow(userId, "", "", "admin", "", false, "hash", uuid.New(), uuid.Nil, "active", "", []byte(`{}`), nil, false, time.Now(), "", 1, time.Now(), time.Now())
+
+	query := `
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
+		FROM users 
+		WHERE normalized_username = $1 AND organization_id = $2 AND deleted_at IS NULL
+		LIMIT 1
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs("admin", organizationID).
+		WillReturnRows(expectedResult)
+
+	ctx := domain.WithOrganization(context.TODO(), organizationID)
+	user, err := PostgresRepository{db}.GetByUsername(ctx, "admin")
+	assert.Nil(t, err)
+	assert.Equal(t, userId, user.ID)
+	assert.Equal(t, organizationID, user.OrganizationID)
+}
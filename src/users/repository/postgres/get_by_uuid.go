@@ -10,9 +10,9 @@ import (
 // Gets a user by uuid
 func (r PostgresRepository) GetByUUID(ctx context.Context, uuid uuid.UUID) (*domain.User, error) {
 	query := `
-		SELECT id, first_name, last_name, username, password, role_id, refresh_token_id, created_at, updated_at
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
 		FROM users 
-		WHERE id = $1  AND deleted_at IS NULL
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL
 		LIMIT 1
 	`
 
@@ -22,6 +22,6 @@ func (r PostgresRepository) GetByUUID(ctx context.Context, uuid uuid.UUID) (*dom
 		return nil, err
 	}
 
-	row := statement.QueryRowContext(ctx, uuid)
-	return r.scanUserRow(row)
+	row := statement.QueryRowContext(ctx, uuid, domain.OrganizationFromContext(ctx))
+	return r.scanUserRow(ctx, row)
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,9 +19,9 @@ func Test_GetByUuid_ErrorPreparingContext(t *testing.T) {
 	defer db.Close()
 
 	query := `
-		SELECT id, first_name, last_name, username, password, role_id, refresh_token_id, created_at, updated_at
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
 		FROM users 
-		WHERE id = $1  AND deleted_at IS NULL
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL
 		LIMIT 1
 	`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
@@ -39,14 +40,14 @@ func Test_GetByUuid_TimeoutReached(t *testing.T) {
 
 	userId := uuid.New()
 	query := `
-		SELECT id, first_name, last_name, username, password, role_id, refresh_token_id, created_at, updated_at
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
 		FROM users 
-		WHERE id = $1  AND deleted_at IS NULL
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL
 		LIMIT 1
 	`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs(userId).
+		WithArgs(userId, domain.DefaultOrganizationID).
 		WillDelayFor(time.Duration(200 * time.Millisecond)).
 		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
 
@@ -65,23 +66,24 @@ func Test_GetByUuid_Success(t *testing.T) {
 	userId := uuid.New()
 	roleId := uuid.New()
 	createdAt := time.Now()
+	lastLoginAt := createdAt.Add(-time.Hour)
 	db, mock, err := sqlmock.New()
 	assert.Nil(t, err)
 	defer db.Close()
 
 	expectedResult := sqlmock.NewRows(
-		[]string{"id", "first_name", "last_name", "username", "password", "role_id", "refresh_token_id", "created_at", "updated_at"},
-	).AddRow(userId, "cenas", "", "admin", "wrong password wtv", roleId, uuid.Nil, createdAt, createdAt)
+		[]string{"id", "first_name", "last_name", "username", "email", "email_verified", "password", "role_id", "refresh_token_id", "status", "status_reason", "attributes", "last_login_at", "must_change_password", "password_changed_at", "backend", "version", "created_at", "updated_at"},
+	).AddRow(userId, "cenas", "", "admin", "", false, "wrong password wtv", roleId, uuid.Nil, "active", "", []byte(`{"timezone":"Europe/Lisbon"}`), lastLoginAt, false, createdAt, "", 1, createdAt, createdAt)
 
 	query := `
-		SELECT id, first_name, last_name, username, password, role_id, refresh_token_id, created_at, updated_at
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
 		FROM users 
-		WHERE id = $1  AND deleted_at IS NULL
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL
 		LIMIT 1
 	`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
-		WithArgs(userId).
+		WithArgs(userId, domain.DefaultOrganizationID).
 		WillReturnRows(expectedResult)
 
 	ctx := context.TODO()
@@ -95,7 +97,9 @@ func Test_GetByUuid_Success(t *testing.T) {
 	assert.Equal(t, createdUser.Username, "admin")
 	assert.Equal(t, createdUser.Password, "wrong password wtv")
 	assert.Equal(t, createdUser.RoleId, roleId)
+	assert.Equal(t, createdUser.LastLoginAt, lastLoginAt)
 	assert.Equal(t, createdUser.CreatedAt, createdAt)
 	assert.Equal(t, createdUser.UpdatedAt, createdAt)
+	assert.Equal(t, domain.Attributes{"timezone": "Europe/Lisbon"}, createdUser.Attributes)
 	assert.Nil(t, err)
 }
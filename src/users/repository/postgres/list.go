@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Lists the users in a paginated manner, oldest first.
+// Only the users containing all the attributes of the filter are listed.
+func (r PostgresRepository) List(ctx context.Context, filter domain.UserFilter) ([]domain.User, error) {
+	result := make([]domain.User, 0)
+
+	attributes := filter.Attributes
+	if attributes == nil {
+		attributes = domain.Attributes{}
+	}
+	encoded, err := json.Marshal(attributes)
+	if err != nil {
+		return result, err
+	}
+
+	query := `
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
+		FROM users
+		WHERE attributes @> $1 AND organization_id = $4 AND deleted_at IS NULL
+		ORDER BY created_at, id
+		LIMIT $2 OFFSET $3
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return result, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, encoded, filter.Limit, filter.Offset, domain.OrganizationFromContext(ctx))
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user, err := r.scanUserRow(ctx, rows)
+		if err != nil {
+			return make([]domain.User, 0), err
+		}
+		result = append(result, *user)
+	}
+	return result, rows.Err()
+}
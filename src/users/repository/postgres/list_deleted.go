@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Lists the users deleted after a time in a paginated manner, by the time they were deleted.
+func (r PostgresRepository) ListDeleted(ctx context.Context, deletedAfter time.Time, limit int, offset int) ([]domain.User, error) {
+	result := make([]domain.User, 0)
+
+	query := `
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at, deleted_at
+		FROM users
+		WHERE deleted_at > $1 AND organization_id = $2
+		ORDER BY deleted_at, id
+		LIMIT $3 OFFSET $4
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return result, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, deletedAfter, domain.OrganizationFromContext(ctx), limit, offset)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var deletedAt time.Time
+		user, err := r.scanUserRow(ctx, deletedUserRow{rows, &deletedAt})
+		if err != nil {
+			return make([]domain.User, 0), err
+		}
+		user.DeletedAt = deletedAt
+		result = append(result, *user)
+	}
+	return result, rows.Err()
+}
+
+// Row of a user followed by its deleted_at value.
+type deletedUserRow struct {
+	row       rowScanner
+	deletedAt *time.Time
+}
+
+func (d deletedUserRow) Scan(dest ...interface{}) error {
+	return d.row.Scan(append(dest, d.deletedAt)...)
+}
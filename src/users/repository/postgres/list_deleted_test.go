@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const listDeletedQuery = `
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at, deleted_at
+		FROM users
+		WHERE deleted_at > $1 AND organization_id = $2
+		ORDER BY deleted_at, id
+		LIMIT $3 OFFSET $4
+	`
+
+func Test_ListDeleted_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(listDeletedQuery)).WillReturnError(errors.New("boom"))
+
+	repo := PostgresRepository{db}
+	users, err := repo.ListDeleted(context.TODO(), time.Now(), 10, 0)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, users)
+}
+
+func Test_ListDeleted_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id, roleId := uuid.New(), uuid.New()
+	createdAt := time.Now().Add(-time.Hour)
+	deletedAt := time.Now()
+	deletedAfter := deletedAt.Add(-24 * time.Hour)
+	rows := sqlmock.NewRows(
+		[]string{"id", "first_name", "last_name", "username", "email", "email_verified", "password", "role_id", "refresh_token_id", "status", "status_reason", "attributes", "last_login_at", "must_change_password", "password_changed_at", "backend", "version", "created_at", "updated_at", "deleted_at"},
+	).
+		AddRow(id, "", "", "alice", "", false, "hash", roleId, uuid.Nil, "active", "", []byte(`{}`), nil, false, createdAt, "", 2, createdAt, deletedAt, deletedAt)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(listDeletedQuery)).
+		ExpectQuery().
+		WithArgs(deletedAfter, domain.DefaultOrganizationID, 2, 4).
+		WillReturnRows(rows)
+
+	repo := PostgresRepository{db}
+	users, err := repo.ListDeleted(context.TODO(), deletedAfter, 2, 4)
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, id, users[0].ID)
+	assert.Equal(t, "alice", users[0].Username)
+	assert.Equal(t, deletedAt, users[0].DeletedAt)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
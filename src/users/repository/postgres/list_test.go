@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const listQuery = `
+		SELECT id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at
+		FROM users
+		WHERE attributes @> $1 AND organization_id = $4 AND deleted_at IS NULL
+		ORDER BY created_at, id
+		LIMIT $2 OFFSET $3
+	`
+
+func Test_List_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(listQuery)).WillReturnError(errors.New("boom"))
+
+	repo := PostgresRepository{db}
+	users, err := repo.List(context.TODO(), domain.UserFilter{Limit: 10})
+	assert.Equal(t, err.Error(), "boom")
+	assert.Empty(t, users)
+}
+
+func Test_List_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(listQuery)).
+		ExpectQuery().
+		WithArgs([]byte(`{}`), 10, 0, domain.DefaultOrganizationID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	repo := PostgresRepository{db}
+	users, err := repo.List(ctx, domain.UserFilter{Limit: 10})
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+	assert.Empty(t, users)
+}
+
+func Test_List_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	firstId, secondId, roleId := uuid.New(), uuid.New(), uuid.New()
+	createdAt := time.Now()
+	rows := sqlmock.NewRows(
+		[]string{"id", "first_name", "last_name", "username", "email", "email_verified", "password", "role_id", "refresh_token_id", "status", "status_reason", "attributes", "last_login_at", "must_change_password", "password_changed_at", "backend", "version", "created_at", "updated_at"},
+	).
+		AddRow(firstId, "", "", "alice", "", false, "hash", roleId, uuid.Nil, "active", "", []byte(`{"department":"sales","locale":"pt-PT"}`), nil, false, createdAt, "", 1, createdAt, createdAt).
+		AddRow(secondId, "", "", "bob", "", false, "hash", roleId, uuid.Nil, "suspended", "spam", []byte(`{"department":"sales"}`), nil, false, createdAt, "", 1, createdAt, createdAt)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(listQuery)).
+		ExpectQuery().
+		WithArgs([]byte(`{"department":"sales"}`), 2, 4, domain.DefaultOrganizationID).
+		WillReturnRows(rows)
+
+	repo := PostgresRepository{db}
+	users, err := repo.List(context.TODO(), domain.UserFilter{
+		Attributes: domain.Attributes{"department": "sales"},
+		Limit:      2,
+		Offset:     4,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, firstId, users[0].ID)
+	assert.Equal(t, domain.Attributes{"department": "sales", "locale": "pt-PT"}, users[0].Attributes)
+	assert.Equal(t, secondId, users[1].ID)
+	assert.Equal(t, domain.UserStatusSuspended, users[1].Status)
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Marks the email of a user as verified.
+// Only applies if the user still has that email.
+func (r PostgresRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) error {
+	query := `
+		UPDATE users
+		SET email_verified = true, updated_at = $3, version = version + 1
+		WHERE id = $1 AND lower(email) = lower($2) AND organization_id = $4 AND deleted_at IS NULL
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	result, err := stmt.ExecContext(ctx, id, email, time.Now(), domain.OrganizationFromContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MarkEmailVerified_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		UPDATE users
+		SET email_verified = true, updated_at = $3, version = version + 1
+		WHERE id = $1 AND lower(email) = lower($2) AND organization_id = $4 AND deleted_at IS NULL
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	repo := PostgresRepository{db}
+	err = repo.MarkEmailVerified(context.TODO(), uuid.New(), "alice@example.com")
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func Test_MarkEmailVerified_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	query := `
+		UPDATE users
+		SET email_verified = true, updated_at = $3, version = version + 1
+		WHERE id = $1 AND lower(email) = lower($2) AND organization_id = $4 AND deleted_at IS NULL
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs(id, "alice@example.com", anyTime{}, domain.DefaultOrganizationID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	repo := PostgresRepository{db}
+	err = repo.MarkEmailVerified(ctx, id, "alice@example.com")
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func Test_MarkEmailVerified_EmailChanged(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	query := `
+		UPDATE users
+		SET email_verified = true, updated_at = $3, version = version + 1
+		WHERE id = $1 AND lower(email) = lower($2) AND organization_id = $4 AND deleted_at IS NULL
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs(id, "alice@example.com", anyTime{}, domain.DefaultOrganizationID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := PostgresRepository{db}
+	err = repo.MarkEmailVerified(context.TODO(), id, "alice@example.com")
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func Test_MarkEmailVerified_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	query := `
+		UPDATE users
+		SET email_verified = true, updated_at = $3, version = version + 1
+		WHERE id = $1 AND lower(email) = lower($2) AND organization_id = $4 AND deleted_at IS NULL
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs(id, "alice@example.com", anyTime{}, domain.DefaultOrganizationID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := PostgresRepository{db}
+	err = repo.MarkEmailVerified(context.TODO(), id, "alice@example.com")
+	assert.Nil(t, err)
+}
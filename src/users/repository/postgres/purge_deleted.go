@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Permanently removes up to limit users deleted before the given time, along with
+// their refresh tokens. Everything else of the users is removed by cascade.
+// Rows locked by a purge running elsewhere are skipped, so several can run at once.
+func (r PostgresRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) (int64, error) {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		DELETE FROM users
+		WHERE id IN (
+			SELECT id FROM users
+			WHERE deleted_at IS NOT NULL AND deleted_at < $1
+			ORDER BY deleted_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING refresh_token_id
+	`
+	rows, err := tx.QueryContext(ctx, query, deletedBefore, limit)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var purged int64
+	refreshTokenIDs := []string{}
+	for rows.Next() {
+		var refreshTokenID uuid.NullUUID
+		if err = rows.Scan(&refreshTokenID); err != nil {
+			return 0, err
+		}
+		purged++
+		if refreshTokenID.Valid {
+			refreshTokenIDs = append(refreshTokenIDs, refreshTokenID.UUID.String())
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(refreshTokenIDs) > 0 {
+		_, err = tx.ExecContext(ctx, `DELETE FROM refresh_tokens WHERE id = ANY($1::uuid[])`, pq.Array(refreshTokenIDs))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
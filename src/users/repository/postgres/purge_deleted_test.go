@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+const purgeDeletedQuery = `
+		DELETE FROM users
+		WHERE id IN (
+			SELECT id FROM users
+			WHERE deleted_at IS NOT NULL AND deleted_at < $1
+			ORDER BY deleted_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING refresh_token_id
+	`
+
+const purgeRefreshTokensQuery = `DELETE FROM refresh_tokens WHERE id = ANY($1::uuid[])`
+
+func Test_PurgeDeleted_ErrorBeginningTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin().WillReturnError(errors.New("boom"))
+
+	purged, err := PostgresRepository{db}.PurgeDeleted(context.TODO(), time.Now(), 100)
+	assert.Equal(t, int64(0), purged)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func Test_PurgeDeleted_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	deletedBefore := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(purgeDeletedQuery)).
+		WithArgs(deletedBefore, 100).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	purged, err := PostgresRepository{db}.PurgeDeleted(ctx, deletedBefore, 100)
+	assert.Equal(t, int64(0), purged)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func Test_PurgeDeleted_NothingToPurge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	deletedBefore := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(purgeDeletedQuery)).
+		WithArgs(deletedBefore, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"refresh_token_id"}))
+	mock.ExpectCommit()
+
+	purged, err := PostgresRepository{db}.PurgeDeleted(context.TODO(), deletedBefore, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), purged)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_PurgeDeleted_ErrorDeletingRefreshTokensRollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	deletedBefore := time.Now()
+	refreshTokenId := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(purgeDeletedQuery)).
+		WithArgs(deletedBefore, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"refresh_token_id"}).AddRow(refreshTokenId))
+	mock.ExpectExec(regexp.QuoteMeta(purgeRefreshTokensQuery)).
+		WithArgs(pq.Array([]string{refreshTokenId.String()})).
+		WillReturnError(errors.New("boom"))
+	mock.ExpectRollback()
+
+	purged, err := PostgresRepository{db}.PurgeDeleted(context.TODO(), deletedBefore, 100)
+	assert.Equal(t, int64(0), purged)
+	assert.Equal(t, "boom", err.Error())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_PurgeDeleted_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	deletedBefore := time.Now()
+	refreshTokenId := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(purgeDeletedQuery)).
+		WithArgs(deletedBefore, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"refresh_token_id"}).AddRow(refreshTokenId).AddRow(nil))
+	mock.ExpectExec(regexp.QuoteMeta(purgeRefreshTokensQuery)).
+		WithArgs(pq.Array([]string{refreshTokenId.String()})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	purged, err := PostgresRepository{db}.PurgeDeleted(context.TODO(), deletedBefore, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), purged)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -3,7 +3,9 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
+	"github.com/google/uuid"
 	"github.com/plagioriginal/user-microservice/domain"
 )
 
@@ -17,18 +19,36 @@ func New(db *sql.DB) domain.UserRepository {
 	return PostgresRepository{db}
 }
 
-// Scans a user row without the deleted_at value
-func (r PostgresRepository) scanUserRow(row *sql.Row) (*domain.User, error) {
+// Row of a single or multi row query
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans a user row without the deleted_at value.
+// Users are only read from the organization of the context, which is the one they belong to.
+func (r PostgresRepository) scanUserRow(ctx context.Context, row rowScanner) (*domain.User, error) {
 	result := domain.User{}
+	var attributes []byte
+	var lastLoginAt sql.NullTime
 
 	err := row.Scan(
 		&result.ID,
 		&result.FirstName,
 		&result.LastName,
 		&result.Username,
+		&result.Email,
+		&result.EmailVerified,
 		&result.Password,
 		&result.RoleId,
 		&result.RefreshTokenId,
+		&result.Status,
+		&result.StatusReason,
+		&attributes,
+		&lastLoginAt,
+		&result.MustChangePassword,
+		&result.PasswordChangedAt,
+		&result.Backend,
+		&result.Version,
 		&result.CreatedAt,
 		&result.UpdatedAt,
 	)
@@ -37,10 +57,32 @@ func (r PostgresRepository) scanUserRow(row *sql.Row) (*domain.User, error) {
 		return nil, err
 	}
 
+	if err = json.Unmarshal(attributes, &result.Attributes); err != nil {
+		return nil, err
+	}
+
+	// Users that never logged in have no date.
+	result.LastLoginAt = lastLoginAt.Time
+	result.OrganizationID = domain.OrganizationFromContext(ctx)
 	return &result, nil
 }
 
-// Lists the users in the DB in a paginated matter.
-func (r PostgresRepository) List(ctx context.Context, page int, perPage int) ([]domain.User, error) {
-	panic("to be implemented")
+// Database or transaction to query a single row from.
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Tells why an update of a user at a version didn't change it: domain.ErrNotFound when
+// there is no such user, or domain.ErrVersionConflict when it is at another version.
+func (r PostgresRepository) missedUpdateError(ctx context.Context, db rowQuerier, id uuid.UUID) error {
+	query := `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL)`
+
+	var exists bool
+	if err := db.QueryRowContext(ctx, query, id, domain.OrganizationFromContext(ctx)).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrNotFound
+	}
+	return domain.ErrVersionConflict
 }
@@ -0,0 +1,18 @@
+package postgres
+
+import (
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+const missedUpdateQuery = `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL)`
+
+// Expects the check of why an update at a version didn't change the user.
+func expectMissedUpdate(mock sqlmock.Sqlmock, id uuid.UUID, exists bool) {
+	mock.ExpectQuery(regexp.QuoteMeta(missedUpdateQuery)).
+		WithArgs(id, domain.DefaultOrganizationID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(exists))
+}
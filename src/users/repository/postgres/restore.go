@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Restores a user deleted after the given time.
+// Fails with domain.ErrNotFound when there isn't such user.
+func (r PostgresRepository) Restore(ctx context.Context, id uuid.UUID, deletedAfter time.Time) error {
+	query := `
+		UPDATE users
+		SET deleted_at = NULL, updated_at = $3, version = version + 1
+		WHERE id = $1 AND organization_id = $4 AND deleted_at IS NOT NULL AND deleted_at > $2
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	result, err := stmt.ExecContext(ctx, id, deletedAfter, time.Now(), domain.OrganizationFromContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const restoreQuery = `
+		UPDATE users
+		SET deleted_at = NULL, updated_at = $3, version = version + 1
+		WHERE id = $1 AND organization_id = $4 AND deleted_at IS NOT NULL AND deleted_at > $2
+	`
+
+func Test_Restore_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(restoreQuery)).WillReturnError(errors.New("boom"))
+
+	err = PostgresRepository{db}.Restore(context.TODO(), uuid.New(), time.Now())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func Test_Restore_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	deletedAfter := time.Now().Add(-time.Hour)
+	mock.ExpectPrepare(regexp.QuoteMeta(restoreQuery)).
+		ExpectExec().
+		WithArgs(id, deletedAfter, anyTime{}, domain.DefaultOrganizationID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = PostgresRepository{db}.Restore(ctx, id, deletedAfter)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func Test_Restore_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	deletedAfter := time.Now().Add(-time.Hour)
+	mock.ExpectPrepare(regexp.QuoteMeta(restoreQuery)).
+		ExpectExec().
+		WithArgs(id, deletedAfter, anyTime{}, domain.DefaultOrganizationID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = PostgresRepository{db}.Restore(context.TODO(), id, deletedAfter)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func Test_Restore_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	deletedAfter := time.Now().Add(-time.Hour)
+	mock.ExpectPrepare(regexp.QuoteMeta(restoreQuery)).
+		ExpectExec().
+		WithArgs(id, deletedAfter, anyTime{}, domain.DefaultOrganizationID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = PostgresRepository{db}.Restore(context.TODO(), id, deletedAfter)
+	assert.Nil(t, err)
+}
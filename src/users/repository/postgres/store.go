@@ -2,21 +2,32 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/helpers"
 )
 
-// Stores a new user into the DB
+// Postgres error code for unique constraint violations
+const uniqueViolationCode = "23505"
+
+// Stores a new user into the DB.
+// The username is kept as typed, and its normalized form is used for uniqueness.
+// Emails are unique regardless of their casing.
 func (r PostgresRepository) Store(ctx context.Context, user domain.User) (*domain.User, error) {
 	if user.ID == uuid.Nil {
 		user.ID = uuid.New()
 	}
+	if len(user.Status) == 0 {
+		user.Status = domain.UserStatusActive
+	}
 
-	query := `INSERT INTO users(id, first_name, last_name, username, password, role_id, created_at, updated_at) 
-			VALUES($1, $2, $3, $4, $5, $6, $7, $8)
-			RETURNING id, first_name, last_name, username, password, role_id, refresh_token_id, created_at, updated_at`
+	query := `INSERT INTO users(id, first_name, last_name, username, normalized_username, email, password, role_id, status, created_at, updated_at, organization_id, must_change_password, backend) 
+			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			RETURNING id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at`
 
 	statement, err := r.Db.PrepareContext(ctx, query)
 
@@ -30,11 +41,22 @@ func (r PostgresRepository) Store(ctx context.Context, user domain.User) (*domai
 			user.FirstName,
 			user.LastName,
 			user.Username,
+			helpers.NormalizeUsername(user.Username),
+			user.Email,
 			user.Password,
 			user.RoleId,
+			user.Status,
 			time.Now(),
 			time.Now(),
+			domain.OrganizationFromContext(ctx),
+			user.MustChangePassword,
+			user.Backend,
 		)
 
-	return r.scanUserRow(row)
+	result, err := r.scanUserRow(ctx, row)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+		return nil, domain.ErrAlreadyExists
+	}
+	return result, err
 }
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/helpers"
+)
+
+// Columns inserted for every user by StoreMany
+const storeManyColumns = 16
+
+// Stores many users at once, batchSize users per statement, all inside a single transaction.
+// Users conflicting with existing ones (ID, username or email) are skipped instead of failing everything.
+// The timestamps of the users are kept when set, so restored users keep theirs,
+// and the ones with a deletion date are stored deleted.
+// Returns the IDs of the users that were stored.
+func (r PostgresRepository) StoreMany(ctx context.Context, users []domain.User, batchSize int) ([]uuid.UUID, error) {
+	result := make([]uuid.UUID, 0, len(users))
+	if len(users) == 0 {
+		return result, nil
+	}
+	if batchSize <= 0 {
+		return nil, domain.ErrBadParamInput
+	}
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for start := 0; start < len(users); start += batchSize {
+		end := start + batchSize
+		if end > len(users) {
+			end = len(users)
+		}
+
+		query, args, err := storeManyQuery(users[start:end], domain.OrganizationFromContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var id uuid.UUID
+			if err = rows.Scan(&id); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			result = append(result, id)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Builds the multi-row insert of a batch of users, into an organization.
+func storeManyQuery(users []domain.User, organizationID uuid.UUID) (string, []interface{}, error) {
+	now := time.Now()
+	values := make([]string, 0, len(users))
+	args := make([]interface{}, 0, len(users)*storeManyColumns)
+
+	for i, user := range users {
+		if user.ID == uuid.Nil {
+			user.ID = uuid.New()
+		}
+		if len(user.Status) == 0 {
+			user.Status = domain.UserStatusActive
+		}
+		if user.Attributes == nil {
+			user.Attributes = domain.Attributes{}
+		}
+		attributes, err := json.Marshal(user.Attributes)
+		if err != nil {
+			return "", nil, err
+		}
+		createdAt, updatedAt := user.CreatedAt, user.UpdatedAt
+		if createdAt.IsZero() {
+			createdAt = now
+		}
+		if updatedAt.IsZero() {
+			updatedAt = createdAt
+		}
+		var deletedAt interface{}
+		if !user.DeletedAt.IsZero() {
+			deletedAt = user.DeletedAt
+		}
+
+		placeholders := make([]string, storeManyColumns)
+		for j := range placeholders {
+			placeholders[j] = fmt.Sprintf("$%d", i*storeManyColumns+j+1)
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+
+		args = append(args,
+			user.ID,
+			user.FirstName,
+			user.LastName,
+			user.Username,
+			helpers.NormalizeUsername(user.Username),
+			user.Email,
+			user.EmailVerified,
+			user.Password,
+			user.RoleId,
+			user.Status,
+			user.StatusReason,
+			attributes,
+			createdAt,
+			updatedAt,
+			organizationID,
+			deletedAt,
+		)
+	}
+
+	query := `INSERT INTO users(id, first_name, last_name, username, normalized_username, email, email_verified, password, role_id, status, status_reason, attributes, created_at, updated_at, organization_id, deleted_at)
+			VALUES ` + strings.Join(values, ", ") + `
+			ON CONFLICT DO NOTHING
+			RETURNING id`
+	return query, args, nil
+}
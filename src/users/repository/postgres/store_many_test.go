@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	storeManyOneRowQuery = `INSERT INTO users(id, first_name, last_name, username, normalized_username, email, email_verified, password, role_id, status, status_reason, attributes, created_at, updated_at, organization_id, deleted_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+			ON CONFLICT DO NOTHING
+			RETURNING id`
+	storeManyTwoRowsQuery = `INSERT INTO users(id, first_name, last_name, username, normalized_username, email, email_verified, password, role_id, status, status_reason, attributes, created_at, updated_at, organization_id, deleted_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16), ($17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32)
+			ON CONFLICT DO NOTHING
+			RETURNING id`
+)
+
+func storeManyArgs(user domain.User) []driver.Value {
+	return []driver.Value{user.ID, "", "", user.Username, user.Username, user.Email, false, user.Password, user.RoleId, "active", "", []byte(`{}`), anyTime{}, anyTime{}, domain.DefaultOrganizationID, nil}
+}
+
+func Test_StoreMany_NothingToStore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	ids, err := PostgresRepository{db}.StoreMany(context.TODO(), nil, 10)
+	assert.Nil(t, err)
+	assert.Empty(t, ids)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_StoreMany_InvalidBatchSize(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	ids, err := PostgresRepository{db}.StoreMany(context.TODO(), []domain.User{{ID: uuid.New()}}, 0)
+	assert.Nil(t, ids)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func Test_StoreMany_ErrorBeginningTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin().WillReturnError(errors.New("boom"))
+
+	ids, err := PostgresRepository{db}.StoreMany(context.TODO(), []domain.User{{ID: uuid.New()}}, 10)
+	assert.Nil(t, ids)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func Test_StoreMany_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	user := domain.User{ID: uuid.New(), Username: "alice", Password: "hash", RoleId: uuid.New()}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(storeManyOneRowQuery)).
+		WithArgs(storeManyArgs(user)...).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	ids, err := PostgresRepository{db}.StoreMany(ctx, []domain.User{user}, 10)
+	assert.Nil(t, ids)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func Test_StoreMany_ErrorInLaterBatchRollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	roleId := uuid.New()
+	first := domain.User{ID: uuid.New(), Username: "alice", Password: "hash", RoleId: roleId}
+	second := domain.User{ID: uuid.New(), Username: "bob", Password: "hash", RoleId: roleId}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(storeManyOneRowQuery)).
+		WithArgs(storeManyArgs(first)...).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(first.ID))
+	mock.ExpectQuery(regexp.QuoteMeta(storeManyOneRowQuery)).
+		WithArgs(storeManyArgs(second)...).
+		WillReturnError(errors.New("boom"))
+	mock.ExpectRollback()
+
+	ids, err := PostgresRepository{db}.StoreMany(context.TODO(), []domain.User{first, second}, 1)
+	assert.Nil(t, ids)
+	assert.Equal(t, err.Error(), "boom")
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_StoreMany_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	roleId := uuid.New()
+	alice := domain.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com", Password: "hash", RoleId: roleId}
+	bob := domain.User{ID: uuid.New(), Username: "bob", Password: "hash", RoleId: roleId}
+	carol := domain.User{ID: uuid.New(), Username: "carol", Password: "hash", RoleId: roleId}
+
+	mock.ExpectBegin()
+	// Bob conflicts with an existing user, so it isn't returned.
+	mock.ExpectQuery(regexp.QuoteMeta(storeManyTwoRowsQuery)).
+		WithArgs(append(storeManyArgs(alice), storeManyArgs(bob)...)...).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(alice.ID))
+	mock.ExpectQuery(regexp.QuoteMeta(storeManyOneRowQuery)).
+		WithArgs(storeManyArgs(carol)...).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(carol.ID))
+	mock.ExpectCommit()
+
+	ids, err := PostgresRepository{db}.StoreMany(context.TODO(), []domain.User{alice, bob, carol}, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []uuid.UUID{alice.ID, carol.ID}, ids)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_StoreMany_KeepsRestoredFields(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	createdAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	updatedAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	deletedAt := time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)
+	user := domain.User{
+		ID:            uuid.New(),
+		FirstName:     "Alice",
+		LastName:      "Smith",
+		Username:      "alice",
+		Email:         "alice@example.com",
+		EmailVerified: true,
+		Password:      "hash",
+		RoleId:        uuid.New(),
+		Status:        domain.UserStatusSuspended,
+		StatusReason:  "fraud",
+		Attributes:    domain.Attributes{"plan": "pro"},
+		CreatedAt:     createdAt,
+		UpdatedAt:     updatedAt,
+		DeletedAt:     deletedAt,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(storeManyOneRowQuery)).
+		WithArgs(user.ID, "Alice", "Smith", "alice", "alice", "alice@example.com", true, "hash", user.RoleId, "suspended", "fraud", []byte(`{"plan":"pro"}`), createdAt, updatedAt, domain.DefaultOrganizationID, deletedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(user.ID))
+	mock.ExpectCommit()
+
+	ids, err := PostgresRepository{db}.StoreMany(context.TODO(), []domain.User{user}, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []uuid.UUID{user.ID}, ids)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
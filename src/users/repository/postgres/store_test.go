@@ -10,6 +10,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/plagioriginal/user-microservice/domain"
 	"github.com/stretchr/testify/assert"
 )
@@ -36,9 +37,9 @@ func Test_Store_ErrorPreparingContext(t *testing.T) {
 	assert.Nil(t, err)
 	defer db.Close()
 
-	query := `INSERT INTO users(id, first_name, last_name, username, password, role_id, created_at, updated_at) 
-			VALUES($1, $2, $3, $4, $5, $6, $7, $8)
-			RETURNING id, first_name, last_name, username, password, role_id, refresh_token_id, created_at, updated_at`
+	query := `INSERT INTO users(id, first_name, last_name, username, normalized_username, email, password, role_id, status, created_at, updated_at, organization_id, must_change_password, backend) 
+			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			RETURNING id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
 
 	repo := PostgresRepository{db}
@@ -62,9 +63,9 @@ func Test_Store_TimeoutReached(t *testing.T) {
 	assert.Nil(t, err)
 	defer db.Close()
 
-	query := `INSERT INTO users(id, first_name, last_name, username, password, role_id, created_at, updated_at) 
-			VALUES($1, $2, $3, $4, $5, $6, $7, $8)
-			RETURNING id, first_name, last_name, username, password, role_id, refresh_token_id, created_at, updated_at`
+	query := `INSERT INTO users(id, first_name, last_name, username, normalized_username, email, password, role_id, status, created_at, updated_at, organization_id, must_change_password, backend) 
+			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			RETURNING id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
 		WithArgs(
@@ -72,10 +73,16 @@ func Test_Store_TimeoutReached(t *testing.T) {
 			"cenas",
 			"",
 			"",
+			"",
+			"",
 			"wrong password wtv",
 			roleId,
+			"active",
 			anyTime{},
 			anyTime{},
+			domain.DefaultOrganizationID,
+			false,
+			"",
 		).WillDelayFor(time.Duration(6 * time.Second)).
 		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
 
@@ -106,12 +113,12 @@ func Test_Store_Success(t *testing.T) {
 	defer db.Close()
 
 	expectedResult := sqlmock.NewRows(
-		[]string{"id", "first_name", "last_name", "username", "password", "role_id", "refresh_token_id", "created_at", "updated_at"},
-	).AddRow(userId, "cenas", "", "", "wrong password wtv", roleId, uuid.Nil, createdAt, createdAt)
+		[]string{"id", "first_name", "last_name", "username", "email", "email_verified", "password", "role_id", "refresh_token_id", "status", "status_reason", "attributes", "last_login_at", "must_change_password", "password_changed_at", "backend", "version", "created_at", "updated_at"},
+	).AddRow(userId, "cenas", "", "", "", false, "wrong password wtv", roleId, uuid.Nil, "active", "", []byte(`{"timezone":"Europe/Lisbon"}`), nil, false, createdAt, "", 1, createdAt, createdAt)
 
-	query := `INSERT INTO users(id, first_name, last_name, username, password, role_id, created_at, updated_at) 
-			VALUES($1, $2, $3, $4, $5, $6, $7, $8)
-			RETURNING id, first_name, last_name, username, password, role_id, refresh_token_id, created_at, updated_at`
+	query := `INSERT INTO users(id, first_name, last_name, username, normalized_username, email, password, role_id, status, created_at, updated_at, organization_id, must_change_password, backend) 
+			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			RETURNING id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at`
 	mock.ExpectPrepare(regexp.QuoteMeta(query)).
 		ExpectQuery().
 		WithArgs(
@@ -119,10 +126,16 @@ func Test_Store_Success(t *testing.T) {
 			"cenas",
 			"",
 			"",
+			"",
+			"",
 			"wrong password wtv",
 			roleId,
+			"active",
 			anyTime{},
 			anyTime{},
+			domain.DefaultOrganizationID,
+			false,
+			"",
 		).
 		WillReturnRows(expectedResult)
 
@@ -140,3 +153,64 @@ func Test_Store_Success(t *testing.T) {
 	assert.Equal(t, createdUser.UpdatedAt, createdAt)
 	assert.Nil(t, err)
 }
+
+func Test_Store_NormalizesUsername(t *testing.T) {
+	userId := uuid.New()
+	roleId := uuid.New()
+	createdAt := time.Now()
+	user := domain.User{
+		ID:       userId,
+		Username: "Alice",
+		Password: "wrong password wtv",
+		RoleId:   roleId,
+	}
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	expectedResult := sqlmock.NewRows(
+		[]string{"id", "first_name", "last_name", "username", "email", "email_verified", "password", "role_id", "refresh_token_id", "status", "status_reason", "attributes", "last_login_at", "must_change_password", "password_changed_at", "backend", "version", "created_at", "updated_at"},
+	).AddRow(userId, "", "", "Alice", "", false, "wrong password wtv", roleId, uuid.Nil, "active", "", []byte(`{"timezone":"Europe/Lisbon"}`), nil, false, createdAt, "", 1, createdAt, createdAt)
+
+	query := `INSERT INTO users(id, first_name, last_name, username, normalized_username, email, password, role_id, status, created_at, updated_at, organization_id, must_change_password, backend) 
+			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			RETURNING id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs(userId, "", "", "Alice", "alice", "", "wrong password wtv", roleId, "active", anyTime{}, anyTime{}, domain.DefaultOrganizationID, false, "").
+		WillReturnRows(expectedResult)
+
+	repo := PostgresRepository{db}
+	createdUser, err := repo.Store(context.TODO(), user)
+	assert.Equal(t, createdUser.Username, "Alice")
+	assert.Nil(t, err)
+}
+
+func Test_Store_UsernameAlreadyTaken(t *testing.T) {
+	userId := uuid.New()
+	roleId := uuid.New()
+	user := domain.User{
+		ID:       userId,
+		Username: "ALICE",
+		Password: "wrong password wtv",
+		RoleId:   roleId,
+	}
+
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `INSERT INTO users(id, first_name, last_name, username, normalized_username, email, password, role_id, status, created_at, updated_at, organization_id, must_change_password, backend) 
+			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			RETURNING id, first_name, last_name, username, email, email_verified, password, role_id, refresh_token_id, status, status_reason, attributes, last_login_at, must_change_password, password_changed_at, backend, version, created_at, updated_at`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectQuery().
+		WithArgs(userId, "", "", "ALICE", "alice", "", "wrong password wtv", roleId, "active", anyTime{}, anyTime{}, domain.DefaultOrganizationID, false, "").
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	repo := PostgresRepository{db}
+	createdUser, err := repo.Store(context.TODO(), user)
+	assert.Nil(t, createdUser)
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+}
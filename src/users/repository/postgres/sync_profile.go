@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Overwrites the email, names and role of a user with the ones of its directory, as long as it is
+// still at the given version. The email is verified, unless there's none.
+// Fails when another user of the organization has the same email.
+func (r PostgresRepository) SyncProfile(
+	ctx context.Context,
+	id uuid.UUID,
+	version int,
+	email string,
+	profile domain.UserProfile,
+	roleID uuid.UUID,
+) error {
+	query := `
+		UPDATE users
+		SET email = $1, email_verified = $1 <> '', first_name = $2, last_name = $3, role_id = $4, updated_at = $5, version = version + 1
+		WHERE id = $6 AND organization_id = $7 AND version = $8 AND deleted_at IS NULL
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	result, err := stmt.ExecContext(
+		ctx,
+		email,
+		profile.FirstName,
+		profile.LastName,
+		roleID,
+		time.Now(),
+		id,
+		domain.OrganizationFromContext(ctx),
+		version,
+	)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+		return domain.ErrAlreadyExists
+	}
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return r.missedUpdateError(ctx, r.Db, id)
+	}
+	return nil
+}
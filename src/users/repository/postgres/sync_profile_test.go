@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var syncedProfile = domain.UserProfile{FirstName: "Bob", LastName: "Smith"}
+
+const syncProfileQuery = `
+		UPDATE users
+		SET email = $1, email_verified = $1 <> '', first_name = $2, last_name = $3, role_id = $4, updated_at = $5, version = version + 1
+		WHERE id = $6 AND organization_id = $7 AND version = $8 AND deleted_at IS NULL
+	`
+
+func Test_SyncProfile_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(syncProfileQuery)).WillReturnError(errors.New("boom"))
+
+	repo := PostgresRepository{db}
+	err = repo.SyncProfile(context.TODO(), uuid.New(), 3, "bob@example.com", syncedProfile, uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func Test_SyncProfile_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	roleID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(syncProfileQuery)).
+		ExpectExec().
+		WithArgs("bob@example.com", "Bob", "Smith", roleID, anyTime{}, id, domain.DefaultOrganizationID, 3).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	repo := PostgresRepository{db}
+	err = repo.SyncProfile(ctx, id, 3, "bob@example.com", syncedProfile, roleID)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func Test_SyncProfile_AlreadyExists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	roleID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(syncProfileQuery)).
+		ExpectExec().
+		WithArgs("bob@example.com", "Bob", "Smith", roleID, anyTime{}, id, domain.DefaultOrganizationID, 3).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	repo := PostgresRepository{db}
+	err = repo.SyncProfile(context.TODO(), id, 3, "bob@example.com", syncedProfile, roleID)
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+}
+
+func Test_SyncProfile_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	roleID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(syncProfileQuery)).
+		ExpectExec().
+		WithArgs("bob@example.com", "Bob", "Smith", roleID, anyTime{}, id, domain.DefaultOrganizationID, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	expectMissedUpdate(mock, id, false)
+
+	repo := PostgresRepository{db}
+	err = repo.SyncProfile(context.TODO(), id, 3, "bob@example.com", syncedProfile, roleID)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func Test_SyncProfile_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	roleID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(syncProfileQuery)).
+		ExpectExec().
+		WithArgs("bob@example.com", "Bob", "Smith", roleID, anyTime{}, id, domain.DefaultOrganizationID, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	expectMissedUpdate(mock, id, true)
+
+	repo := PostgresRepository{db}
+	err = repo.SyncProfile(context.TODO(), id, 3, "bob@example.com", syncedProfile, roleID)
+	assert.Equal(t, domain.ErrVersionConflict, err)
+}
+
+func Test_SyncProfile_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	roleID := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(syncProfileQuery)).
+		ExpectExec().
+		WithArgs("bob@example.com", "Bob", "Smith", roleID, anyTime{}, id, domain.DefaultOrganizationID, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := PostgresRepository{db}
+	err = repo.SyncProfile(context.TODO(), id, 3, "bob@example.com", syncedProfile, roleID)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Replaces the custom attributes of a user, as long as it is still at the given version.
+func (r PostgresRepository) UpdateAttributes(ctx context.Context, id uuid.UUID, version int, attributes domain.Attributes) error {
+	if attributes == nil {
+		attributes = domain.Attributes{}
+	}
+	encoded, err := json.Marshal(attributes)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE users
+		SET attributes = $1, updated_at = $2, version = version + 1
+		WHERE id = $3 AND organization_id = $4 AND version = $5 AND deleted_at IS NULL
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	result, err := stmt.ExecContext(ctx, encoded, time.Now(), id, domain.OrganizationFromContext(ctx), version)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return r.missedUpdateError(ctx, r.Db, id)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const updateAttributesQuery = `
+		UPDATE users
+		SET attributes = $1, updated_at = $2, version = version + 1
+		WHERE id = $3 AND organization_id = $4 AND version = $5 AND deleted_at IS NULL
+	`
+
+func Test_UpdateAttributes_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(updateAttributesQuery)).WillReturnError(errors.New("boom"))
+
+	repo := PostgresRepository{db}
+	err = repo.UpdateAttributes(context.TODO(), uuid.New(), 3, domain.Attributes{"locale": "pt-PT"})
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func Test_UpdateAttributes_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(updateAttributesQuery)).
+		ExpectExec().
+		WithArgs([]byte(`{"locale":"pt-PT"}`), anyTime{}, id, domain.DefaultOrganizationID, 3).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	repo := PostgresRepository{db}
+	err = repo.UpdateAttributes(ctx, id, 3, domain.Attributes{"locale": "pt-PT"})
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func Test_UpdateAttributes_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(updateAttributesQuery)).
+		ExpectExec().
+		WithArgs([]byte(`{}`), anyTime{}, id, domain.DefaultOrganizationID, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	expectMissedUpdate(mock, id, false)
+
+	repo := PostgresRepository{db}
+	err = repo.UpdateAttributes(context.TODO(), id, 3, nil)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func Test_UpdateAttributes_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(updateAttributesQuery)).
+		ExpectExec().
+		WithArgs([]byte(`{}`), anyTime{}, id, domain.DefaultOrganizationID, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	expectMissedUpdate(mock, id, true)
+
+	repo := PostgresRepository{db}
+	err = repo.UpdateAttributes(context.TODO(), id, 3, nil)
+	assert.Equal(t, domain.ErrVersionConflict, err)
+}
+
+func Test_UpdateAttributes_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectPrepare(regexp.QuoteMeta(updateAttributesQuery)).
+		ExpectExec().
+		WithArgs([]byte(`{"locale":"pt-PT"}`), anyTime{}, id, domain.DefaultOrganizationID, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := PostgresRepository{db}
+	err = repo.UpdateAttributes(context.TODO(), id, 3, domain.Attributes{"locale": "pt-PT"})
+	assert.Nil(t, err)
+}
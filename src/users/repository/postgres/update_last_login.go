@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Sets when a user last logged in.
+// It isn't a change of the user, so the update date is kept.
+func (r PostgresRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `
+		UPDATE users
+		SET last_login_at = $1
+		WHERE id = $2 AND organization_id = $3 AND deleted_at IS NULL
+	`
+
+	stmt, err := r.Db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	result, err := stmt.ExecContext(ctx, at, id, domain.OrganizationFromContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
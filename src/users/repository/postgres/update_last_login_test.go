@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const updateLastLoginQuery = `
+		UPDATE users
+		SET last_login_at = $1
+		WHERE id = $2 AND organization_id = $3 AND deleted_at IS NULL
+	`
+
+func Test_UpdateLastLogin_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(updateLastLoginQuery)).WillReturnError(errors.New("boom"))
+
+	repo := PostgresRepository{db}
+	err = repo.UpdateLastLogin(context.TODO(), uuid.New(), time.Now())
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func Test_UpdateLastLogin_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	at := time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(updateLastLoginQuery)).
+		ExpectExec().
+		WithArgs(at, id, domain.DefaultOrganizationID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	repo := PostgresRepository{db}
+	err = repo.UpdateLastLogin(ctx, id, at)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func Test_UpdateLastLogin_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	at := time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(updateLastLoginQuery)).
+		ExpectExec().
+		WithArgs(at, id, domain.DefaultOrganizationID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := PostgresRepository{db}
+	err = repo.UpdateLastLogin(context.TODO(), id, at)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func Test_UpdateLastLogin_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	at := time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(updateLastLoginQuery)).
+		ExpectExec().
+		WithArgs(at, id, domain.DefaultOrganizationID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := PostgresRepository{db}
+	err = repo.UpdateLastLogin(context.TODO(), id, at)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_UpdatePassword_ErrorPreparingContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	query := `
+		UPDATE users
+		SET password = $1, updated_at = $2, version = version + 1
+		WHERE id = $3 AND organization_id = $4 AND deleted_at IS NULL
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillReturnError(errors.New("boom"))
+
+	repo := PostgresRepository{db}
+	err = repo.UpdatePassword(context.TODO(), uuid.New(), "hash")
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func Test_UpdatePassword_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	query := `
+		UPDATE users
+		SET password = $1, updated_at = $2, version = version + 1
+		WHERE id = $3 AND organization_id = $4 AND deleted_at IS NULL
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs("hash", anyTime{}, id, domain.DefaultOrganizationID).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	repo := PostgresRepository{db}
+	err = repo.UpdatePassword(ctx, id, "hash")
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func Test_UpdatePassword_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	query := `
+		UPDATE users
+		SET password = $1, updated_at = $2, version = version + 1
+		WHERE id = $3 AND organization_id = $4 AND deleted_at IS NULL
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs("hash", anyTime{}, id, domain.DefaultOrganizationID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := PostgresRepository{db}
+	err = repo.UpdatePassword(context.TODO(), id, "hash")
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func Test_UpdatePassword_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	query := `
+		UPDATE users
+		SET password = $1, updated_at = $2, version = version + 1
+		WHERE id = $3 AND organization_id = $4 AND deleted_at IS NULL
+	`
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs("hash", anyTime{}, id, domain.DefaultOrganizationID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := PostgresRepository{db}
+	err = repo.UpdatePassword(context.TODO(), id, "hash")
+	assert.Nil(t, err)
+}
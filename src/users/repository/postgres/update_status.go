@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Moves a user from a status to another one, failing with domain.ErrVersionConflict
+// when the user isn't at the given version or in the expected status anymore.
+// Leaving the active status also deletes the refresh token of the user, ending its session.
+func (r PostgresRepository) UpdateStatus(ctx context.Context, id uuid.UUID, version int, from domain.UserStatus, to domain.UserStatus, reason string) error {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE users
+		SET status = $3, status_reason = $4, updated_at = $5, version = version + 1
+		WHERE id = $1 AND status = $2 AND organization_id = $6 AND version = $7 AND deleted_at IS NULL
+		RETURNING refresh_token_id
+	`
+
+	var refreshTokenID uuid.NullUUID
+	err = tx.QueryRowContext(ctx, query, id, from, to, reason, time.Now(), domain.OrganizationFromContext(ctx), version).Scan(&refreshTokenID)
+	if err == sql.ErrNoRows {
+		return r.missedUpdateError(ctx, tx, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if to != domain.UserStatusActive && refreshTokenID.Valid {
+		_, err = tx.ExecContext(ctx, `DELETE FROM refresh_tokens WHERE id = $1`, refreshTokenID.UUID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const updateStatusQuery = `
+		UPDATE users
+		SET status = $3, status_reason = $4, updated_at = $5, version = version + 1
+		WHERE id = $1 AND status = $2 AND organization_id = $6 AND version = $7 AND deleted_at IS NULL
+		RETURNING refresh_token_id
+	`
+
+func Test_UpdateStatus_ErrorBeginningTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin().WillReturnError(errors.New("boom"))
+
+	err = PostgresRepository{db}.UpdateStatus(context.TODO(), uuid.New(), 3, domain.UserStatusActive, domain.UserStatusSuspended, "spam")
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func Test_UpdateStatus_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userId := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(updateStatusQuery)).
+		WithArgs(userId, domain.UserStatusActive, domain.UserStatusSuspended, "spam", anyTime{}, domain.DefaultOrganizationID, 3).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = PostgresRepository{db}.UpdateStatus(ctx, userId, 3, domain.UserStatusActive, domain.UserStatusSuspended, "spam")
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func Test_UpdateStatus_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userId := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(updateStatusQuery)).
+		WithArgs(userId, domain.UserStatusActive, domain.UserStatusSuspended, "spam", anyTime{}, domain.DefaultOrganizationID, 3).
+		WillReturnError(sql.ErrNoRows)
+	expectMissedUpdate(mock, userId, false)
+	mock.ExpectRollback()
+
+	err = PostgresRepository{db}.UpdateStatus(context.TODO(), userId, 3, domain.UserStatusActive, domain.UserStatusSuspended, "spam")
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_UpdateStatus_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userId := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(updateStatusQuery)).
+		WithArgs(userId, domain.UserStatusActive, domain.UserStatusSuspended, "spam", anyTime{}, domain.DefaultOrganizationID, 3).
+		WillReturnError(sql.ErrNoRows)
+	expectMissedUpdate(mock, userId, true)
+	mock.ExpectRollback()
+
+	err = PostgresRepository{db}.UpdateStatus(context.TODO(), userId, 3, domain.UserStatusActive, domain.UserStatusSuspended, "spam")
+	assert.Equal(t, domain.ErrVersionConflict, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_UpdateStatus_ErrorDeletingRefreshTokenRollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userId := uuid.New()
+	refreshTokenId := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(updateStatusQuery)).
+		WithArgs(userId, domain.UserStatusActive, domain.UserStatusSuspended, "spam", anyTime{}, domain.DefaultOrganizationID, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"refresh_token_id"}).AddRow(refreshTokenId))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM refresh_tokens WHERE id = $1`)).
+		WithArgs(refreshTokenId).
+		WillReturnError(errors.New("boom"))
+	mock.ExpectRollback()
+
+	err = PostgresRepository{db}.UpdateStatus(context.TODO(), userId, 3, domain.UserStatusActive, domain.UserStatusSuspended, "spam")
+	assert.Equal(t, "boom", err.Error())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_UpdateStatus_SuspendEndsSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userId := uuid.New()
+	refreshTokenId := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(updateStatusQuery)).
+		WithArgs(userId, domain.UserStatusActive, domain.UserStatusSuspended, "spam", anyTime{}, domain.DefaultOrganizationID, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"refresh_token_id"}).AddRow(refreshTokenId))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM refresh_tokens WHERE id = $1`)).
+		WithArgs(refreshTokenId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = PostgresRepository{db}.UpdateStatus(context.TODO(), userId, 3, domain.UserStatusActive, domain.UserStatusSuspended, "spam")
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_UpdateStatus_Reactivate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	userId := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(updateStatusQuery)).
+		WithArgs(userId, domain.UserStatusSuspended, domain.UserStatusActive, "", anyTime{}, domain.DefaultOrganizationID, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"refresh_token_id"}).AddRow(nil))
+	mock.ExpectCommit()
+
+	err = PostgresRepository{db}.UpdateStatus(context.TODO(), userId, 3, domain.UserStatusSuspended, domain.UserStatusActive, "")
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/helpers"
+)
+
+// Renames a user to the new username of a change, keeping it as typed, as long as the user is
+// still at the given version. The change is stored in the same transaction, so the old username
+// is reserved for the user whenever the rename goes through.
+// Fails when another user of the organization has the same normalized username.
+func (r PostgresRepository) UpdateUsername(ctx context.Context, id uuid.UUID, version int, change domain.UsernameChange) error {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE users
+		SET username = $1, normalized_username = $2, updated_at = $3, version = version + 1
+		WHERE id = $4 AND organization_id = $5 AND version = $6 AND deleted_at IS NULL
+	`
+
+	result, err := tx.ExecContext(
+		ctx,
+		query,
+		change.NewUsername,
+		helpers.NormalizeUsername(change.NewUsername),
+		time.Now(),
+		id,
+		domain.OrganizationFromContext(ctx),
+		version,
+	)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+		return domain.ErrAlreadyExists
+	}
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return r.missedUpdateError(ctx, tx, id)
+	}
+
+	if change.ID == uuid.Nil {
+		change.ID = uuid.New()
+	}
+	historyQuery := `
+		INSERT INTO username_history (id, organization_id, user_id, old_username, normalized_old_username, new_username, changed_at, reserved_until)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err = tx.ExecContext(
+		ctx,
+		historyQuery,
+		change.ID,
+		domain.OrganizationFromContext(ctx),
+		id,
+		change.OldUsername,
+		helpers.NormalizeUsername(change.OldUsername),
+		change.NewUsername,
+		change.ChangedAt,
+		change.ReservedUntil,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const updateUsernameQuery = `
+		UPDATE users
+		SET username = $1, normalized_username = $2, updated_at = $3, version = version + 1
+		WHERE id = $4 AND organization_id = $5 AND version = $6 AND deleted_at IS NULL
+	`
+
+const storeUsernameChangeQuery = `
+		INSERT INTO username_history (id, organization_id, user_id, old_username, normalized_old_username, new_username, changed_at, reserved_until)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+func usernameChange(id uuid.UUID) domain.UsernameChange {
+	now := time.Now()
+	return domain.UsernameChange{
+		ID:            uuid.New(),
+		UserID:        id,
+		OldUsername:   "Alice",
+		NewUsername:   "Bob",
+		ChangedAt:     now,
+		ReservedUntil: now.Add(time.Hour),
+	}
+}
+
+func Test_UpdateUsername_ErrorBeginningTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin().WillReturnError(errors.New("boom"))
+
+	id := uuid.New()
+	err = PostgresRepository{db}.UpdateUsername(context.TODO(), id, 3, usernameChange(id))
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "boom")
+}
+
+func Test_UpdateUsername_TimeoutReached(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(updateUsernameQuery)).
+		WithArgs("Bob", "bob", anyTime{}, id, domain.DefaultOrganizationID, 3).
+		WillDelayFor(time.Duration(200 * time.Millisecond)).
+		WillReturnError(errors.New("result doesnt matter because we are testing timeout"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Duration(100*time.Millisecond))
+	defer cancel()
+
+	err = PostgresRepository{db}.UpdateUsername(ctx, id, 3, usernameChange(id))
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "canceling query due to user request")
+}
+
+func Test_UpdateUsername_AlreadyExists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(updateUsernameQuery)).
+		WithArgs("Bob", "bob", anyTime{}, id, domain.DefaultOrganizationID, 3).
+		WillReturnError(&pq.Error{Code: "23505"})
+	mock.ExpectRollback()
+
+	err = PostgresRepository{db}.UpdateUsername(context.TODO(), id, 3, usernameChange(id))
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_UpdateUsername_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(updateUsernameQuery)).
+		WithArgs("Bob", "bob", anyTime{}, id, domain.DefaultOrganizationID, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	expectMissedUpdate(mock, id, false)
+	mock.ExpectRollback()
+
+	err = PostgresRepository{db}.UpdateUsername(context.TODO(), id, 3, usernameChange(id))
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_UpdateUsername_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(updateUsernameQuery)).
+		WithArgs("Bob", "bob", anyTime{}, id, domain.DefaultOrganizationID, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	expectMissedUpdate(mock, id, true)
+	mock.ExpectRollback()
+
+	err = PostgresRepository{db}.UpdateUsername(context.TODO(), id, 3, usernameChange(id))
+	assert.Equal(t, domain.ErrVersionConflict, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_UpdateUsername_RolledBackIfErrorStoringChange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	change := usernameChange(id)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(updateUsernameQuery)).
+		WithArgs("Bob", "bob", anyTime{}, id, domain.DefaultOrganizationID, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(storeUsernameChangeQuery)).
+		WithArgs(change.ID, domain.DefaultOrganizationID, id, "Alice", "alice", "Bob", change.ChangedAt, change.ReservedUntil).
+		WillReturnError(errors.New("boom"))
+	mock.ExpectRollback()
+
+	err = PostgresRepository{db}.UpdateUsername(context.TODO(), id, 3, change)
+	assert.Equal(t, "boom", err.Error())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_UpdateUsername_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	id := uuid.New()
+	change := usernameChange(id)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(updateUsernameQuery)).
+		WithArgs("Bob", "bob", anyTime{}, id, domain.DefaultOrganizationID, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(storeUsernameChangeQuery)).
+		WithArgs(change.ID, domain.DefaultOrganizationID, id, "Alice", "alice", "Bob", change.ChangedAt, change.ReservedUntil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = PostgresRepository{db}.UpdateUsername(context.TODO(), id, 3, change)
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
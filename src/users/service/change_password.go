@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Sets a new password for a user, which no longer has to change it.
+// Setting the current password again isn't allowed, so an expired password can't be kept.
+// Users without a local password can't set one.
+func (s DefaultUserService) ChangePassword(ctx context.Context, id uuid.UUID, password string) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	if id == uuid.Nil || len(password) == 0 {
+		return nil, domain.ErrBadParamInput
+	}
+
+	user, err := s.UserRepo.GetByUUID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	// A password would let directory users in without the directory, and the others in a new way.
+	if !user.HasLocalPassword() {
+		return nil, domain.ErrNoLocalPassword
+	}
+	if comparePassword(user.Password, password) == nil {
+		return nil, domain.ErrNotAllowed
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.BcryptHashingCost)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.UserRepo.ChangePassword(ctx, id, string(hash)); err != nil {
+		return nil, err
+	}
+
+	return s.GetUserByUUID(ctx, id)
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func Test_ChangePassword_InvalidInput(t *testing.T) {
+	service := newService(nil, nil, nil)
+
+	user, err := service.ChangePassword(context.TODO(), uuid.Nil, "new-password")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+
+	user, err = service.ChangePassword(context.TODO(), uuid.New(), "")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrBadParamInput, err)
+}
+
+func Test_ChangePassword_UserNotFound(t *testing.T) {
+	id := uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, id).Once().Return(nil, sql.ErrNoRows)
+
+	service := newService(userRepo, nil, nil)
+	user, err := service.ChangePassword(context.TODO(), id, "new-password")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrNotFound, err)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_ChangePassword_SamePassword(t *testing.T) {
+	id := uuid.New()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("old-password"), TestingBcryptCost)
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, id).Once().Return(&domain.User{ID: id, Password: string(hash)}, nil)
+
+	service := newService(userRepo, nil, nil)
+	user, err := service.ChangePassword(context.TODO(), id, "old-password")
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrNotAllowed, err)
+	userRepo.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_ChangePassword_NoLocalPassword(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("old-password"), TestingBcryptCost)
+	cases := map[string]domain.User{
+		"directory user":        {Password: string(hash), Backend: "ldap"},
+		"user without password": {},
+	}
+
+	for name, existing := range cases {
+		t.Run(name, func(t *testing.T) {
+			existing.ID = uuid.New()
+			userRepo := new(mocks.UserRepository)
+			userRepo.On("GetByUUID", mock.Anything, existing.ID).Once().Return(&existing, nil)
+
+			service := newService(userRepo, nil, nil)
+			user, err := service.ChangePassword(context.TODO(), existing.ID, "new-password")
+			assert.Nil(t, user)
+			assert.Equal(t, domain.ErrNoLocalPassword, err)
+			userRepo.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func Test_ChangePassword_RepoError(t *testing.T) {
+	id := uuid.New()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("old-password"), TestingBcryptCost)
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, id).Once().Return(&domain.User{ID: id, Password: string(hash)}, nil)
+	userRepo.On("ChangePassword", mock.Anything, id, mock.Anything).Once().Return(errors.New("boom"))
+
+	service := newService(userRepo, nil, nil)
+	user, err := service.ChangePassword(context.TODO(), id, "new-password")
+	assert.Nil(t, user)
+	assert.Equal(t, "boom", err.Error())
+	userRepo.AssertExpectations(t)
+}
+
+func Test_ChangePassword_Success(t *testing.T) {
+	id := uuid.New()
+	roleID := uuid.New()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("old-password"), TestingBcryptCost)
+	userRepo := new(mocks.UserRepository)
+	roleRepo := new(mocks.RoleRepository)
+	userRepo.On("GetByUUID", mock.Anything, id).Once().
+		Return(&domain.User{ID: id, RoleId: roleID, Password: string(hash), MustChangePassword: true}, nil)
+	userRepo.On("ChangePassword", mock.Anything, id, mock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte("new-password")) == nil
+	})).Once().Return(nil)
+	userRepo.On("GetByUUID", mock.Anything, id).Once().
+		Return(&domain.User{ID: id, RoleId: roleID, Version: 2}, nil)
+	roleRepo.On("GetByUUID", mock.Anything, roleID).Once().Return(domain.Role{ID: roleID, RoleSlug: "admin"}, nil)
+
+	service := newService(userRepo, roleRepo, nil)
+	user, err := service.ChangePassword(context.TODO(), id, "new-password")
+	assert.Nil(t, err)
+	assert.False(t, user.MustChangePassword)
+	assert.Equal(t, 2, user.Version)
+	assert.Equal(t, "admin", user.Role.RoleSlug)
+	userRepo.AssertExpectations(t)
+	roleRepo.AssertExpectations(t)
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Moves a user at a version to another status, following the allowed transitions.
+// Leaving the active status ends the sessions of the user.
+func (s DefaultUserService) ChangeStatus(ctx context.Context, id uuid.UUID, version int, status domain.UserStatus, reason string) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	if id == uuid.Nil || version <= 0 || !status.IsValid() {
+		return nil, domain.ErrBadParamInput
+	}
+
+	user, err := s.UserRepo.GetByUUID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Version != version {
+		return nil, domain.ErrVersionConflict
+	}
+	if !user.Status.CanTransitionTo(status) {
+		return nil, domain.ErrNotAllowed
+	}
+
+	if err = s.UserRepo.UpdateStatus(ctx, id, version, user.Status, status, reason); err != nil {
+		return nil, err
+	}
+
+	return s.GetUserByUUID(ctx, id)
+}
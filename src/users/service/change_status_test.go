@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_ChangeStatus_FailIfInvalidStatus(t *testing.T) {
+	service := newService(nil, nil, nil)
+	user, err := service.ChangeStatus(context.TODO(), uuid.New(), 1, domain.UserStatus("banned"), "")
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+}
+
+func Test_ChangeStatus_FailIfInvalidVersion(t *testing.T) {
+	service := newService(nil, nil, nil)
+	user, err := service.ChangeStatus(context.TODO(), uuid.New(), 0, domain.UserStatusSuspended, "")
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+}
+
+func Test_ChangeStatus_FailIfUserNotFound(t *testing.T) {
+	userUuid := uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userUuid).Once().
+		Return(nil, sql.ErrNoRows)
+
+	service := newService(userRepo, nil, nil)
+	user, err := service.ChangeStatus(context.TODO(), userUuid, 1, domain.UserStatusSuspended, "spam")
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_ChangeStatus_FailIfVersionConflict(t *testing.T) {
+	userUuid := uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userUuid).Once().
+		Return(&domain.User{ID: userUuid, Version: 2, Status: domain.UserStatusActive}, nil)
+
+	service := newService(userRepo, nil, nil)
+	user, err := service.ChangeStatus(context.TODO(), userUuid, 1, domain.UserStatusSuspended, "spam")
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, domain.ErrVersionConflict)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_ChangeStatus_FailIfTransitionNotAllowed(t *testing.T) {
+	userUuid := uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userUuid).Once().
+		Return(&domain.User{ID: userUuid, Version: 1, Status: domain.UserStatusPending}, nil)
+
+	service := newService(userRepo, nil, nil)
+	user, err := service.ChangeStatus(context.TODO(), userUuid, 1, domain.UserStatusSuspended, "spam")
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, domain.ErrNotAllowed)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_ChangeStatus_FailIfUpdateError(t *testing.T) {
+	userUuid := uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userUuid).Once().
+		Return(&domain.User{ID: userUuid, Version: 1, Status: domain.UserStatusActive}, nil)
+	userRepo.On("UpdateStatus", mock.Anything, userUuid, 1, domain.UserStatusActive, domain.UserStatusSuspended, "spam").
+		Once().Return(errors.New("boom"))
+
+	service := newService(userRepo, nil, nil)
+	user, err := service.ChangeStatus(context.TODO(), userUuid, 1, domain.UserStatusSuspended, "spam")
+	assert.Nil(t, user)
+	assert.Contains(t, err.Error(), "boom")
+	userRepo.AssertExpectations(t)
+}
+
+func Test_ChangeStatus_Success(t *testing.T) {
+	userUuid := uuid.New()
+	roleUuid := uuid.New()
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userUuid).Once().
+		Return(&domain.User{ID: userUuid, RoleId: roleUuid, Version: 1, Status: domain.UserStatusActive}, nil)
+	userRepo.On("UpdateStatus", mock.Anything, userUuid, 1, domain.UserStatusActive, domain.UserStatusSuspended, "spam").
+		Once().Return(nil)
+	userRepo.On("GetByUUID", mock.Anything, userUuid).Once().
+		Return(&domain.User{ID: userUuid, RoleId: roleUuid, Version: 2, Status: domain.UserStatusSuspended, StatusReason: "spam"}, nil)
+
+	role := domain.Role{ID: roleUuid}
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetByUUID", mock.Anything, roleUuid).Once().Return(role, nil)
+
+	service := newService(userRepo, roleRepo, nil)
+	user, err := service.ChangeStatus(context.TODO(), userUuid, 1, domain.UserStatusSuspended, "spam")
+	assert.Nil(t, err)
+	assert.Equal(t, domain.UserStatusSuspended, user.Status)
+	assert.Equal(t, "spam", user.StatusReason)
+	assert.Equal(t, &role, user.Role)
+	assert.Equal(t, 2, user.Version)
+	userRepo.AssertExpectations(t)
+	roleRepo.AssertExpectations(t)
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/helpers"
+)
+
+// Renames a user at a version, keeping its old username reserved for it during the reservation period.
+// Access tokens with the old username stop being valid, while refreshing gives one with the new username.
+func (s DefaultUserService) ChangeUsername(ctx context.Context, id uuid.UUID, version int, username string) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	username = strings.TrimSpace(username)
+	if id == uuid.Nil || version <= 0 || len(helpers.NormalizeUsername(username)) == 0 {
+		return nil, domain.ErrBadParamInput
+	}
+
+	user, err := s.UserRepo.GetByUUID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Version != version {
+		return nil, domain.ErrVersionConflict
+	}
+	if user.Username == username {
+		return s.GetUserByUUID(ctx, id)
+	}
+
+	if err := s.checkUsernameNotReserved(ctx, username, id); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = s.UserRepo.UpdateUsername(ctx, id, version, domain.UsernameChange{
+		UserID:        id,
+		OldUsername:   user.Username,
+		NewUsername:   username,
+		ChangedAt:     now,
+		ReservedUntil: now.Add(s.UsernameSettings.ReservationPeriod),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetUserByUUID(ctx, id)
+}
+
+// Checks that a username isn't reserved for another user.
+// Usernames taken by other users are rejected when storing them.
+func (s DefaultUserService) checkUsernameNotReserved(ctx context.Context, username string, userID uuid.UUID) error {
+	reserved, err := s.UsernameHistoryRepo.IsReserved(ctx, username, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	if reserved {
+		return domain.ErrAlreadyExists
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_ChangeUsername_FailIfInvalidUsername(t *testing.T) {
+	service := newService(nil, nil, nil)
+
+	for _, username := range []string{"", "   "} {
+		user, err := service.ChangeUsername(context.TODO(), uuid.New(), 1, username)
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	}
+}
+
+func Test_ChangeUsername_FailIfInvalidVersion(t *testing.T) {
+	service := newService(nil, nil, nil)
+	user, err := service.ChangeUsername(context.TODO(), uuid.New(), 0, "bob")
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+}
+
+func Test_ChangeUsername_FailIfUserNotFound(t *testing.T) {
+	userUuid := uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userUuid).Once().
+		Return(nil, sql.ErrNoRows)
+
+	service := newService(userRepo, nil, nil)
+	user, err := service.ChangeUsername(context.TODO(), userUuid, 1, "bob")
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_ChangeUsername_FailIfVersionConflict(t *testing.T) {
+	userUuid := uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userUuid).Once().
+		Return(&domain.User{ID: userUuid, Version: 2, Username: "alice"}, nil)
+
+	service := newService(userRepo, nil, nil)
+	user, err := service.ChangeUsername(context.TODO(), userUuid, 1, "bob")
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, domain.ErrVersionConflict)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_ChangeUsername_FailIfReserved(t *testing.T) {
+	userUuid := uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userUuid).Once().
+		Return(&domain.User{ID: userUuid, Version: 1, Username: "alice"}, nil)
+
+	usernameHistoryRepo := new(mocks.UsernameHistoryRepository)
+	usernameHistoryRepo.On("IsReserved", mock.Anything, "bob", userUuid, mock.Anything).
+		Once().Return(true, nil)
+
+	service := newService(userRepo, nil, usernameHistoryRepo)
+	user, err := service.ChangeUsername(context.TODO(), userUuid, 1, "bob")
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+	userRepo.AssertExpectations(t)
+	usernameHistoryRepo.AssertExpectations(t)
+}
+
+func Test_ChangeUsername_FailIfTaken(t *testing.T) {
+	userUuid := uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userUuid).Once().
+		Return(&domain.User{ID: userUuid, Version: 1, Username: "alice"}, nil)
+	userRepo.On("UpdateUsername", mock.Anything, userUuid, 1, mock.Anything).Once().
+		Return(domain.ErrAlreadyExists)
+
+	service := newService(userRepo, nil, unreservedUsernames())
+	user, err := service.ChangeUsername(context.TODO(), userUuid, 1, "bob")
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_ChangeUsername_SameUsername(t *testing.T) {
+	userUuid, roleUuid := uuid.New(), uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userUuid).Twice().
+		Return(&domain.User{ID: userUuid, Version: 1, Username: "alice", RoleId: roleUuid}, nil)
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetByUUID", mock.Anything, roleUuid).Once().Return(domain.Role{ID: roleUuid}, nil)
+
+	service := newService(userRepo, roleRepo, nil)
+	user, err := service.ChangeUsername(context.TODO(), userUuid, 1, " alice ")
+	assert.Nil(t, err)
+	assert.Equal(t, "alice", user.Username)
+	userRepo.AssertExpectations(t)
+	roleRepo.AssertExpectations(t)
+}
+
+func Test_ChangeUsername_Success(t *testing.T) {
+	userUuid, roleUuid := uuid.New(), uuid.New()
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUUID", mock.Anything, userUuid).Once().
+		Return(&domain.User{ID: userUuid, Version: 1, Username: "alice", RoleId: roleUuid}, nil)
+	// The old username is reserved along with the rename.
+	userRepo.On("UpdateUsername", mock.Anything, userUuid, 1, mock.MatchedBy(func(change domain.UsernameChange) bool {
+		return change.UserID == userUuid &&
+			change.OldUsername == "alice" &&
+			change.NewUsername == "Bob" &&
+			change.ReservedUntil.Sub(change.ChangedAt) == 30*24*time.Hour
+	})).Once().Return(nil)
+	userRepo.On("GetByUUID", mock.Anything, userUuid).Once().
+		Return(&domain.User{ID: userUuid, Version: 2, Username: "Bob", RoleId: roleUuid}, nil)
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetByUUID", mock.Anything, roleUuid).Once().Return(domain.Role{ID: roleUuid}, nil)
+
+	usernameHistoryRepo := unreservedUsernames()
+
+	service := newService(userRepo, roleRepo, usernameHistoryRepo)
+	user, err := service.ChangeUsername(context.TODO(), userUuid, 1, " Bob ")
+	assert.Nil(t, err)
+	assert.Equal(t, "Bob", user.Username)
+	assert.Equal(t, roleUuid, user.Role.ID)
+	assert.Equal(t, 2, user.Version)
+	userRepo.AssertExpectations(t)
+	roleRepo.AssertExpectations(t)
+	usernameHistoryRepo.AssertExpectations(t)
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/helpers"
+)
+
+// Counts the users whose passwords still have a legacy hash, by scheme.
+func (s DefaultUserService) GetLegacyPasswordReport(ctx context.Context) (domain.LegacyPasswordReport, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	counts, err := s.UserRepo.CountPasswordSchemes(ctx, helpers.LegacyPasswordSchemes)
+	if err != nil {
+		return domain.LegacyPasswordReport{}, err
+	}
+
+	report := domain.LegacyPasswordReport{Schemes: make(map[string]int, len(helpers.LegacyPasswordSchemes))}
+	for _, scheme := range helpers.LegacyPasswordSchemes {
+		report.Schemes[scheme] = counts[scheme]
+		report.Total += counts[scheme]
+	}
+	return report, nil
+}
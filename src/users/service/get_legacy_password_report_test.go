@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/plagioriginal/user-microservice/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_GetLegacyPasswordReport_FailIfRepositoryError(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("CountPasswordSchemes", mock.Anything, helpers.LegacyPasswordSchemes).Once().
+		Return(nil, errors.New("boom"))
+
+	service := newService(userRepo, nil, nil)
+	_, err := service.GetLegacyPasswordReport(context.TODO())
+	assert.EqualError(t, err, "boom")
+	userRepo.AssertExpectations(t)
+}
+
+func Test_GetLegacyPasswordReport_Success(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("CountPasswordSchemes", mock.Anything, helpers.LegacyPasswordSchemes).Once().
+		Return(map[string]int{
+			helpers.LegacyPasswordSchemePBKDF2SHA256: 12,
+			helpers.LegacyPasswordSchemeSaltedSHA1:   3,
+		}, nil)
+
+	service := newService(userRepo, nil, nil)
+	report, err := service.GetLegacyPasswordReport(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, domain.LegacyPasswordReport{
+		Total: 15,
+		Schemes: map[string]int{
+			helpers.LegacyPasswordSchemePBKDF2SHA256: 12,
+			helpers.LegacyPasswordSchemeScrypt:       0,
+			helpers.LegacyPasswordSchemeArgon2:       0,
+			helpers.LegacyPasswordSchemeSaltedSHA1:   3,
+		},
+	}, report)
+	userRepo.AssertExpectations(t)
+}
@@ -2,13 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
 
-	"github.com/google/uuid"
 	"github.com/plagioriginal/user-microservice/domain"
-	"golang.org/x/crypto/bcrypt"
 )
 
-// Gets a user by the username and password. With role attached
+// Gets an active user by the username and password. With role attached
+// The authenticators are tried in order, until one of them knows the user.
 func (s DefaultUserService) GetUserByLogin(ctx context.Context, request domain.GetUserRequest) (*domain.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
 	defer cancel()
@@ -17,24 +17,18 @@ func (s DefaultUserService) GetUserByLogin(ctx context.Context, request domain.G
 		return nil, domain.ErrBadParamInput
 	}
 
-	user, err := s.UserRepo.GetByUsername(ctx, request.Username)
-	if err != nil {
-		return nil, err
+	for _, authenticator := range s.Authenticators {
+		user, err := authenticator.Authenticate(ctx, request.Username, request.Password)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !user.IsActive() {
+			return nil, domain.ErrUserNotActive
+		}
+		return user, nil
 	}
-	if user.ID == uuid.Nil {
-		return nil, domain.ErrNotFound
-	}
-
-	userRole, err := s.RoleRepo.GetByUUID(ctx, user.RoleId)
-	if err != nil {
-		return nil, err
-	}
-
-	user.Role = &userRole
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password))
-
-	if err != nil {
-		return nil, err
-	}
-	return user, nil
+	return nil, domain.ErrNotFound
 }
@@ -2,12 +2,15 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/plagioriginal/user-microservice/domain"
 	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/plagioriginal/user-microservice/helpers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"golang.org/x/crypto/bcrypt"
@@ -30,7 +33,7 @@ func Test_GetUserByLogin_FailIfInvalidInput(t *testing.T) {
 	}
 
 	for _, req := range requests {
-		service := newService(nil, nil)
+		service := newService(nil, nil, nil)
 		user, err := service.GetUserByLogin(context.TODO(), req)
 		assert.Nil(t, user)
 		assert.Error(t, err)
@@ -43,7 +46,7 @@ func Test_GetUserByLogin_FailIfGetByUsernameError(t *testing.T) {
 	userRepo.On("GetByUsername", mock.Anything, "username").Once().
 		Return(nil, errors.New("boom"))
 
-	service := newService(userRepo, nil)
+	service := newService(userRepo, nil, nil)
 	user, err := service.GetUserByLogin(context.TODO(), domain.GetUserRequest{Username: "username", Password: "casd"})
 	assert.Nil(t, user)
 	assert.Error(t, err)
@@ -57,15 +60,16 @@ func Test_GetUserByLogin_FailIfRoleFetchingError(t *testing.T) {
 	userRepo := new(mocks.UserRepository)
 	userRepo.On("GetByUsername", mock.Anything, "username").Once().
 		Return(&domain.User{
-			ID:     uuid.New(),
-			RoleId: roleUuid,
+			ID:       uuid.New(),
+			RoleId:   roleUuid,
+			Password: "hash",
 		}, nil)
 
 	roleRepo := new(mocks.RoleRepository)
 	roleRepo.On("GetByUUID", mock.Anything, roleUuid).
 		Once().Return(domain.Role{}, errors.New("boom"))
 
-	service := newService(userRepo, roleRepo)
+	service := newService(userRepo, roleRepo, nil)
 	user, err := service.GetUserByLogin(context.TODO(), domain.GetUserRequest{Username: "username", Password: "casd"})
 	assert.Nil(t, user)
 	assert.Error(t, err)
@@ -90,7 +94,7 @@ func Test_GetUserByLogin_FailIfPasswordsDontMatchError(t *testing.T) {
 	roleRepo.On("GetByUUID", mock.Anything, roleUuid).
 		Once().Return(role, nil)
 
-	service := newService(userRepo, roleRepo)
+	service := newService(userRepo, roleRepo, nil)
 	user, err := service.GetUserByLogin(context.TODO(), domain.GetUserRequest{Username: "username", Password: "casd"})
 	assert.Nil(t, user)
 	assert.Error(t, err)
@@ -112,6 +116,7 @@ func Test_GetUserByLogin_Success(t *testing.T) {
 			ID:       userUuid,
 			RoleId:   roleUuid,
 			Password: password,
+			Status:   domain.UserStatusActive,
 		}, nil)
 
 	role := domain.Role{ID: roleUuid}
@@ -119,15 +124,234 @@ func Test_GetUserByLogin_Success(t *testing.T) {
 	roleRepo.On("GetByUUID", mock.Anything, roleUuid).
 		Once().Return(role, nil)
 
-	service := newService(userRepo, roleRepo)
+	service := newService(userRepo, roleRepo, nil)
 	user, err := service.GetUserByLogin(context.TODO(), domain.GetUserRequest{Username: "username", Password: "password"})
 	assert.Equal(t, user, &domain.User{
 		ID:       userUuid,
 		RoleId:   roleUuid,
 		Password: password,
+		Status:   domain.UserStatusActive,
 		Role:     &role,
 	})
 	assert.Nil(t, err)
 	roleRepo.AssertExpectations(t)
 	userRepo.AssertExpectations(t)
 }
+
+func Test_GetUserByLogin_FailIfUserNotActive(t *testing.T) {
+	roleUuid := uuid.New()
+
+	passwordBytes, err := bcrypt.GenerateFromPassword([]byte("password"), 2)
+	assert.Nil(t, err)
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "username").Once().
+		Return(&domain.User{
+			ID:       uuid.New(),
+			RoleId:   roleUuid,
+			Password: string(passwordBytes),
+			Status:   domain.UserStatusSuspended,
+		}, nil)
+
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetByUUID", mock.Anything, roleUuid).
+		Once().Return(domain.Role{ID: roleUuid}, nil)
+
+	service := newService(userRepo, roleRepo, nil)
+	user, err := service.GetUserByLogin(context.TODO(), domain.GetUserRequest{Username: "username", Password: "password"})
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, domain.ErrUserNotActive)
+	roleRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+const legacyPasswordHash = "pbkdf2_sha256$1000$saltsalt$qQDPSZa3Ormyy9oK1Pu0ZLLwP2Svzmx3yu8OvDdq/J0="
+
+func Test_GetUserByLogin_FailIfLegacyPasswordDoesntMatch(t *testing.T) {
+	roleUuid := uuid.New()
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "username").Once().
+		Return(&domain.User{
+			ID:       uuid.New(),
+			RoleId:   roleUuid,
+			Password: legacyPasswordHash,
+			Status:   domain.UserStatusActive,
+		}, nil)
+
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetByUUID", mock.Anything, roleUuid).
+		Once().Return(domain.Role{ID: roleUuid}, nil)
+
+	service := newService(userRepo, roleRepo, nil)
+	user, err := service.GetUserByLogin(context.TODO(), domain.GetUserRequest{Username: "username", Password: "wrong horse"})
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, helpers.ErrMismatchedLegacyPassword)
+	roleRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_GetUserByLogin_FailIfLegacyPasswordUpgradeError(t *testing.T) {
+	roleUuid := uuid.New()
+	userUuid := uuid.New()
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "username").Once().
+		Return(&domain.User{
+			ID:       userUuid,
+			RoleId:   roleUuid,
+			Password: legacyPasswordHash,
+			Status:   domain.UserStatusActive,
+		}, nil)
+	userRepo.On("UpdatePassword", mock.Anything, userUuid, mock.AnythingOfType("string")).Once().
+		Return(errors.New("boom"))
+
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetByUUID", mock.Anything, roleUuid).
+		Once().Return(domain.Role{ID: roleUuid}, nil)
+
+	service := newService(userRepo, roleRepo, nil)
+	user, err := service.GetUserByLogin(context.TODO(), domain.GetUserRequest{Username: "username", Password: "correct horse"})
+	assert.Nil(t, user)
+	assert.EqualError(t, err, "boom")
+	roleRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_GetUserByLogin_UpgradesLegacyPassword(t *testing.T) {
+	roleUuid := uuid.New()
+	userUuid := uuid.New()
+
+	var upgraded string
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "username").Once().
+		Return(&domain.User{
+			ID:       userUuid,
+			RoleId:   roleUuid,
+			Password: legacyPasswordHash,
+			Status:   domain.UserStatusActive,
+		}, nil)
+	userRepo.On("UpdatePassword", mock.Anything, userUuid, mock.AnythingOfType("string")).Once().
+		Run(func(args mock.Arguments) { upgraded = args.String(2) }).
+		Return(nil)
+
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetByUUID", mock.Anything, roleUuid).
+		Once().Return(domain.Role{ID: roleUuid}, nil)
+
+	service := newService(userRepo, roleRepo, nil)
+	user, err := service.GetUserByLogin(context.TODO(), domain.GetUserRequest{Username: "username", Password: "correct horse"})
+	assert.Nil(t, err)
+	assert.Equal(t, userUuid, user.ID)
+	assert.Equal(t, upgraded, user.Password)
+	assert.Nil(t, bcrypt.CompareHashAndPassword([]byte(upgraded), []byte("correct horse")))
+	roleRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_GetUserByLogin_DoesntUpgradeLegacyPasswordIfUserNotActive(t *testing.T) {
+	roleUuid := uuid.New()
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "username").Once().
+		Return(&domain.User{
+			ID:       uuid.New(),
+			RoleId:   roleUuid,
+			Password: legacyPasswordHash,
+			Status:   domain.UserStatusSuspended,
+		}, nil)
+
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetByUUID", mock.Anything, roleUuid).
+		Once().Return(domain.Role{ID: roleUuid}, nil)
+
+	service := newService(userRepo, roleRepo, nil)
+	user, err := service.GetUserByLogin(context.TODO(), domain.GetUserRequest{Username: "username", Password: "correct horse"})
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, domain.ErrUserNotActive)
+	roleRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_GetUserByLogin_NotFoundIfUserHasNoPassword(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "username").Once().
+		Return(&domain.User{ID: uuid.New(), RoleId: uuid.New(), Status: domain.UserStatusActive}, nil)
+
+	service := newService(userRepo, nil, nil)
+	user, err := service.GetUserByLogin(context.TODO(), domain.GetUserRequest{Username: "username", Password: "casd"})
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrNotFound, err)
+	userRepo.AssertExpectations(t)
+}
+
+// Service whose logins are checked by the authenticators.
+func newAuthenticatedService(authenticators ...domain.Authenticator) domain.UserService {
+	return New(nil, nil, nil, time.Duration(2*time.Second), 2, domain.UsernameSettings{}, authenticators)
+}
+
+func Test_GetUserByLogin_TriesNextAuthenticatorIfUserNotFound(t *testing.T) {
+	expected := &domain.User{ID: uuid.New(), Status: domain.UserStatusActive}
+
+	local := new(mocks.Authenticator)
+	local.On("Authenticate", mock.Anything, "username", "casd").Once().Return(nil, domain.ErrNotFound)
+	directory := new(mocks.Authenticator)
+	directory.On("Authenticate", mock.Anything, "username", "casd").Once().Return(expected, nil)
+
+	service := newAuthenticatedService(local, directory)
+	user, err := service.GetUserByLogin(context.TODO(), domain.GetUserRequest{Username: "username", Password: "casd"})
+	assert.Nil(t, err)
+	assert.Equal(t, expected, user)
+	local.AssertExpectations(t)
+	directory.AssertExpectations(t)
+}
+
+func Test_GetUserByLogin_StopsAtFirstAuthenticatorError(t *testing.T) {
+	local := new(mocks.Authenticator)
+	local.On("Authenticate", mock.Anything, "username", "casd").Once().Return(nil, errors.New("wrong password"))
+	directory := new(mocks.Authenticator)
+
+	service := newAuthenticatedService(local, directory)
+	user, err := service.GetUserByLogin(context.TODO(), domain.GetUserRequest{Username: "username", Password: "casd"})
+	assert.Nil(t, user)
+	assert.EqualError(t, err, "wrong password")
+	local.AssertExpectations(t)
+	directory.AssertNotCalled(t, "Authenticate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_GetUserByLogin_FailIfNoAuthenticatorKnowsUser(t *testing.T) {
+	local := new(mocks.Authenticator)
+	local.On("Authenticate", mock.Anything, "username", "casd").Once().Return(nil, domain.ErrNotFound)
+	directory := new(mocks.Authenticator)
+	directory.On("Authenticate", mock.Anything, "username", "casd").Once().Return(nil, domain.ErrNotFound)
+
+	service := newAuthenticatedService(local, directory)
+	user, err := service.GetUserByLogin(context.TODO(), domain.GetUserRequest{Username: "username", Password: "casd"})
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrNotFound, err)
+	local.AssertExpectations(t)
+	directory.AssertExpectations(t)
+}
+
+func Test_GetUserByLogin_FailIfAuthenticatedUserNotActive(t *testing.T) {
+	directory := new(mocks.Authenticator)
+	directory.On("Authenticate", mock.Anything, "username", "casd").Once().
+		Return(&domain.User{ID: uuid.New(), Status: domain.UserStatusPending}, nil)
+
+	service := newAuthenticatedService(directory)
+	user, err := service.GetUserByLogin(context.TODO(), domain.GetUserRequest{Username: "username", Password: "casd"})
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, domain.ErrUserNotActive)
+	directory.AssertExpectations(t)
+}
+
+func Test_GetUserByLogin_NotFoundIfUnknownUsername(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByUsername", mock.Anything, "username").Once().Return(nil, sql.ErrNoRows)
+
+	service := newService(userRepo, nil, nil)
+	user, err := service.GetUserByLogin(context.TODO(), domain.GetUserRequest{Username: "username", Password: "casd"})
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrNotFound, err)
+	userRepo.AssertExpectations(t)
+}
@@ -18,7 +18,7 @@ func Test_GetUserUUID_FailIfGetByUsernameError(t *testing.T) {
 	userRepo.On("GetByUUID", mock.Anything, uuid).Once().
 		Return(nil, errors.New("boom"))
 
-	service := newService(userRepo, nil)
+	service := newService(userRepo, nil, nil)
 	user, err := service.GetUserByUUID(context.TODO(), uuid)
 	assert.Nil(t, user)
 	assert.Error(t, err)
@@ -41,7 +41,7 @@ func Test_GetUserUUID_FailIfRoleFetchingError(t *testing.T) {
 	roleRepo.On("GetByUUID", mock.Anything, roleUuid).
 		Once().Return(domain.Role{}, errors.New("boom"))
 
-	service := newService(userRepo, roleRepo)
+	service := newService(userRepo, roleRepo, nil)
 	user, err := service.GetUserByUUID(context.TODO(), userUuid)
 	assert.Nil(t, user)
 	assert.Error(t, err)
@@ -66,7 +66,7 @@ func Test_GetUserUUID_Success(t *testing.T) {
 	roleRepo.On("GetByUUID", mock.Anything, roleUuid).
 		Once().Return(role, nil)
 
-	service := newService(userRepo, roleRepo)
+	service := newService(userRepo, roleRepo, nil)
 	user, err := service.GetUserByUUID(context.TODO(), userUuid)
 	assert.Equal(t, user, &domain.User{
 		ID:     userUuid,
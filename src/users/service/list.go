@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+)
+
+// Pagination of the listings of users.
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// Lists the users matching the filter, with their roles.
+func (s DefaultUserService) List(ctx context.Context, filter domain.UserFilter) ([]domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
+	defer cancel()
+
+	if filter.Limit < 0 || filter.Limit > MaxListLimit || filter.Offset < 0 {
+		return nil, domain.ErrBadParamInput
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultListLimit
+	}
+
+	users, err := s.UserRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := s.RoleRepo.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rolesByID := make(map[uuid.UUID]domain.Role, len(roles))
+	for _, role := range roles {
+		rolesByID[role.ID] = role
+	}
+
+	for i := range users {
+		if role, ok := rolesByID[users[i].RoleId]; ok {
+			users[i].Role = &role
+		}
+	}
+	return users, nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_List_FailIfInvalidPagination(t *testing.T) {
+	service := newService(nil, nil, nil)
+
+	for _, filter := range []domain.UserFilter{
+		{Limit: -1},
+		{Limit: MaxListLimit + 1},
+		{Offset: -1},
+	} {
+		users, err := service.List(context.TODO(), filter)
+		assert.Nil(t, users)
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	}
+}
+
+func Test_List_FailIfRepoError(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("List", mock.Anything, domain.UserFilter{Limit: DefaultListLimit}).Once().
+		Return(nil, errors.New("boom"))
+
+	service := newService(userRepo, nil, nil)
+	users, err := service.List(context.TODO(), domain.UserFilter{})
+	assert.Nil(t, users)
+	assert.Equal(t, "boom", err.Error())
+	userRepo.AssertExpectations(t)
+}
+
+func Test_List_FailIfRoleFetchingError(t *testing.T) {
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("List", mock.Anything, domain.UserFilter{Limit: 5}).Once().
+		Return([]domain.User{{ID: uuid.New()}}, nil)
+
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("Fetch", mock.Anything).Once().Return(nil, errors.New("boom"))
+
+	service := newService(userRepo, roleRepo, nil)
+	users, err := service.List(context.TODO(), domain.UserFilter{Limit: 5})
+	assert.Nil(t, users)
+	assert.Equal(t, "boom", err.Error())
+	userRepo.AssertExpectations(t)
+	roleRepo.AssertExpectations(t)
+}
+
+func Test_List_Success(t *testing.T) {
+	role := domain.Role{ID: uuid.New(), RoleSlug: "user"}
+	filter := domain.UserFilter{
+		Attributes: domain.Attributes{"department": "sales"},
+		Limit:      10,
+		Offset:     20,
+	}
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("List", mock.Anything, filter).Once().
+		Return([]domain.User{{ID: uuid.New(), RoleId: role.ID}}, nil)
+
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("Fetch", mock.Anything).Once().Return([]domain.Role{role}, nil)
+
+	service := newService(userRepo, roleRepo, nil)
+	users, err := service.List(context.TODO(), filter)
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, &role, users[0].Role)
+	userRepo.AssertExpectations(t)
+	roleRepo.AssertExpectations(t)
+}
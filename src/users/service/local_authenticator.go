@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/helpers"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Name of the authenticator in the configured backends.
+const LocalBackend = "local"
+
+// Checks the passwords against the hashes stored with the users.
+type LocalAuthenticator struct {
+	UserRepo          domain.UserRepository
+	RoleRepo          domain.RoleRepository
+	BcryptHashingCost int
+}
+
+// Constructor
+func NewLocalAuthenticator(userRepo domain.UserRepository, roleRepo domain.RoleRepository, bcryptHashingCost int) domain.Authenticator {
+	return LocalAuthenticator{userRepo, roleRepo, bcryptHashingCost}
+}
+
+// Gets a user by the username and password. With role attached
+// Users without a password, like the ones of a directory, are left to the other authenticators.
+// Passwords imported with a legacy hash are rehashed with bcrypt on the first successful login.
+func (a LocalAuthenticator) Authenticate(ctx context.Context, username string, password string) (*domain.User, error) {
+	user, err := a.UserRepo.GetByUsername(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == uuid.Nil || len(user.Password) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	userRole, err := a.RoleRepo.GetByUUID(ctx, user.RoleId)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Role = &userRole
+	if err = comparePassword(user.Password, password); err != nil {
+		return nil, err
+	}
+	// Users that can't log in keep their hash until they can.
+	if user.IsActive() && len(helpers.LegacyPasswordScheme(user.Password)) > 0 {
+		if err = a.upgradePassword(ctx, user, password); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// Checks a password against its hash, which can be a legacy one.
+func comparePassword(hash string, password string) error {
+	if len(helpers.LegacyPasswordScheme(hash)) > 0 {
+		return helpers.CompareLegacyPasswordHash(hash, password)
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+}
+
+// Replaces the legacy hash of a user with a bcrypt one.
+func (a LocalAuthenticator) upgradePassword(ctx context.Context, user *domain.User, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), a.BcryptHashingCost)
+	if err != nil {
+		return err
+	}
+	if err = a.UserRepo.UpdatePassword(ctx, user.ID, string(hash)); err != nil {
+		return err
+	}
+	user.Password = string(hash)
+	return nil
+}
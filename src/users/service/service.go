@@ -12,33 +12,50 @@ const (
 )
 
 type DefaultUserService struct {
-	UserRepo          domain.UserRepository
-	RoleRepo          domain.RoleRepository
-	ContextTimeout    time.Duration
-	BcryptHashingCost int
+	UserRepo            domain.UserRepository
+	RoleRepo            domain.RoleRepository
+	UsernameHistoryRepo domain.UsernameHistoryRepository
+	ContextTimeout      time.Duration
+	BcryptHashingCost   int
+	UsernameSettings    domain.UsernameSettings
+	// Tried in order on the logins.
+	Authenticators []domain.Authenticator
 }
 
 // Constructor
 func New(
 	userRepo domain.UserRepository,
 	roleRepo domain.RoleRepository,
+	usernameHistoryRepo domain.UsernameHistoryRepository,
 	contextTimeout time.Duration,
 	bcryptHashingCost int,
+	usernameSettings domain.UsernameSettings,
+	authenticators []domain.Authenticator,
 ) domain.UserService {
+	// Without other authenticators, the logins are checked against the local passwords.
+	if len(authenticators) == 0 {
+		authenticators = []domain.Authenticator{NewLocalAuthenticator(userRepo, roleRepo, bcryptHashingCost)}
+	}
 	return DefaultUserService{
 		userRepo,
 		roleRepo,
+		usernameHistoryRepo,
 		contextTimeout,
 		bcryptHashingCost,
+		usernameSettings,
+		authenticators,
 	}
 }
 
 // Used for tests
-func newService(userRepo domain.UserRepository, roleRepo domain.RoleRepository) domain.UserService {
+func newService(userRepo domain.UserRepository, roleRepo domain.RoleRepository, usernameHistoryRepo domain.UsernameHistoryRepository) domain.UserService {
 	return New(
 		userRepo,
 		roleRepo,
+		usernameHistoryRepo,
 		time.Duration(2*time.Second),
 		2,
+		domain.UsernameSettings{ReservationPeriod: 30 * 24 * time.Hour},
+		nil,
 	)
 }
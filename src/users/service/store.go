@@ -2,31 +2,57 @@ package service
 
 import (
 	"context"
+	"strings"
 
+	"github.com/google/uuid"
 	"github.com/plagioriginal/user-microservice/domain"
+	"github.com/plagioriginal/user-microservice/helpers"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// Stores q new user based on username and password
+// Stores q new user based on username, password and optional email.
+// The user is active unless another status is requested.
 func (s DefaultUserService) Store(ctx context.Context, request domain.StoreUserRequest) (*domain.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, s.ContextTimeout)
 	defer cancel()
 
-	role, err := s.RoleRepo.GetBySlug(ctx, request.RoleSlug)
-	if err != nil {
+	username := strings.TrimSpace(request.Username)
+	if len(helpers.NormalizeUsername(username)) == 0 {
+		return nil, domain.ErrBadParamInput
+	}
+
+	// The email is optional
+	email := strings.TrimSpace(request.Email)
+	if len(email) > 0 && !helpers.IsValidEmail(email) {
+		return nil, domain.ErrBadParamInput
+	}
+
+	if err := s.checkUsernameNotReserved(ctx, username, uuid.Nil); err != nil {
 		return nil, err
 	}
 
-	passwordBytes, err := bcrypt.GenerateFromPassword([]byte(request.Password), s.BcryptHashingCost)
+	role, err := s.RoleRepo.GetBySlug(ctx, request.RoleSlug)
 	if err != nil {
 		return nil, err
 	}
-	password := string(passwordBytes[:])
+
+	// Without a password, the stored hash is empty and never matches.
+	password := ""
+	if len(request.Password) > 0 {
+		passwordBytes, err := bcrypt.GenerateFromPassword([]byte(request.Password), s.BcryptHashingCost)
+		if err != nil {
+			return nil, err
+		}
+		password = string(passwordBytes[:])
+	}
 
 	user, err := s.UserRepo.Store(ctx, domain.User{
-		Username: request.Username,
-		Password: password,
-		RoleId:   role.ID,
+		Username:           username,
+		Email:              email,
+		Password:           password,
+		RoleId:             role.ID,
+		Status:             request.Status,
+		MustChangePassword: request.MustChangePassword,
 	})
 	if err != nil {
 		return nil, err
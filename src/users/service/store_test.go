@@ -13,15 +13,71 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Username history where no username is reserved.
+func unreservedUsernames() *mocks.UsernameHistoryRepository {
+	usernameHistoryRepo := new(mocks.UsernameHistoryRepository)
+	usernameHistoryRepo.On("IsReserved", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe().Return(false, nil)
+	return usernameHistoryRepo
+}
+
+func Test_Store_FailIfInvalidUsername(t *testing.T) {
+	service := newService(nil, nil, nil)
+
+	for _, username := range []string{"", "   "} {
+		user, err := service.Store(context.TODO(), domain.StoreUserRequest{
+			Username: username,
+			Password: "password",
+			RoleSlug: "admin",
+		})
+
+		assert.Nil(t, user)
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	}
+}
+
+func Test_Store_FailIfInvalidEmail(t *testing.T) {
+	service := newService(nil, nil, nil)
+
+	for _, email := range []string{"alice", "alice@", "Alice <alice@example.com>"} {
+		user, err := service.Store(context.TODO(), domain.StoreUserRequest{
+			Username: "alice",
+			Email:    email,
+			Password: "password",
+			RoleSlug: "admin",
+		})
+
+		assert.Nil(t, user)
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	}
+}
+
+func Test_Store_FailIfUsernameReserved(t *testing.T) {
+	usernameHistoryRepo := new(mocks.UsernameHistoryRepository)
+	usernameHistoryRepo.On("IsReserved", mock.Anything, "alice", uuid.Nil, mock.Anything).
+		Once().Return(true, nil)
+
+	service := newService(nil, nil, usernameHistoryRepo)
+
+	user, err := service.Store(context.TODO(), domain.StoreUserRequest{
+		Username: " alice ",
+		Password: "password",
+		RoleSlug: "admin",
+	})
+
+	assert.Nil(t, user)
+	assert.Equal(t, domain.ErrAlreadyExists, err)
+	usernameHistoryRepo.AssertExpectations(t)
+}
+
 func Test_Store_FailIfInvalidRole(t *testing.T) {
 	roleRepo := new(mocks.RoleRepository)
 	roleRepo.On("GetBySlug", mock.Anything, "inexistant").
 		Once().Return(domain.Role{}, errors.New("boom"))
 
-	service := newService(nil, roleRepo)
+	service := newService(nil, roleRepo, unreservedUsernames())
 
 	user, err := service.Store(context.TODO(), domain.StoreUserRequest{
-		Username: "",
+		Username: "username",
 		Password: "",
 		RoleSlug: "inexistant",
 	})
@@ -43,7 +99,7 @@ func Test_Store_FailIfErrorStoringUser(t *testing.T) {
 		return user.Username == "username" && user.RoleId.String() == uuid.String() && bcrypt.CompareHashAndPassword([]byte(user.Password), []byte("password")) == nil
 	})).Once().Return(nil, errors.New("boom"))
 
-	service := newService(userRepo, roleRepo)
+	service := newService(userRepo, roleRepo, unreservedUsernames())
 
 	user, err := service.Store(context.TODO(), domain.StoreUserRequest{
 		Username: "username",
@@ -74,10 +130,10 @@ func Test_Store_Success(t *testing.T) {
 		RoleId:   uuid,
 	}, nil)
 
-	service := newService(userRepo, roleRepo)
+	service := newService(userRepo, roleRepo, unreservedUsernames())
 
 	user, err := service.Store(context.TODO(), domain.StoreUserRequest{
-		Username: "username",
+		Username: " username ",
 		Password: "password",
 		RoleSlug: "admin",
 	})
@@ -93,3 +149,97 @@ func Test_Store_Success(t *testing.T) {
 	roleRepo.AssertExpectations(t)
 	userRepo.AssertExpectations(t)
 }
+
+func Test_Store_SuccessWithEmail(t *testing.T) {
+	roleID := uuid.New()
+	role := domain.Role{ID: roleID}
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "admin").
+		Once().Return(role, nil)
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("Store", mock.Anything, mock.MatchedBy(func(user domain.User) bool {
+		return user.Username == "alice" && user.Email == "alice@example.com" && !user.EmailVerified
+	})).Once().Return(&domain.User{
+		Username: "alice",
+		Email:    "alice@example.com",
+		RoleId:   roleID,
+	}, nil)
+
+	service := newService(userRepo, roleRepo, unreservedUsernames())
+
+	user, err := service.Store(context.TODO(), domain.StoreUserRequest{
+		Username: "alice",
+		Email:    " alice@example.com ",
+		Password: "password",
+		RoleSlug: "admin",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "alice@example.com", user.Email)
+	roleRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_Store_SuccessPendingWithoutPassword(t *testing.T) {
+	roleID := uuid.New()
+	role := domain.Role{ID: roleID}
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "user").
+		Once().Return(role, nil)
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("Store", mock.Anything, mock.MatchedBy(func(user domain.User) bool {
+		return user.Username == "alice" && len(user.Password) == 0 && user.Status == domain.UserStatusPending
+	})).Once().Return(&domain.User{
+		Username: "alice",
+		Email:    "alice@example.com",
+		RoleId:   roleID,
+		Status:   domain.UserStatusPending,
+	}, nil)
+
+	service := newService(userRepo, roleRepo, unreservedUsernames())
+
+	user, err := service.Store(context.TODO(), domain.StoreUserRequest{
+		Username: "alice",
+		Email:    "alice@example.com",
+		RoleSlug: "user",
+		Status:   domain.UserStatusPending,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, domain.UserStatusPending, user.Status)
+	roleRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_Store_SuccessMustChangePassword(t *testing.T) {
+	roleID := uuid.New()
+	roleRepo := new(mocks.RoleRepository)
+	roleRepo.On("GetBySlug", mock.Anything, "admin").
+		Once().Return(domain.Role{ID: roleID}, nil)
+
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("Store", mock.Anything, mock.MatchedBy(func(user domain.User) bool {
+		return user.Username == "admin" && user.MustChangePassword
+	})).Once().Return(&domain.User{
+		Username:           "admin",
+		RoleId:             roleID,
+		Status:             domain.UserStatusActive,
+		MustChangePassword: true,
+	}, nil)
+
+	service := newService(userRepo, roleRepo, unreservedUsernames())
+
+	user, err := service.Store(context.TODO(), domain.StoreUserRequest{
+		Username:           "admin",
+		Password:           "from-the-environment",
+		RoleSlug:           "admin",
+		MustChangePassword: true,
+	})
+
+	assert.Nil(t, err)
+	assert.True(t, user.MustChangePassword)
+	roleRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
@@ -2,6 +2,7 @@ package tokens
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/golang-jwt/jwt"
@@ -13,52 +14,100 @@ import (
 type ClaimsWithRole struct {
 	UserRoleSlug  string `json:"roleSlug"`
 	UserRoleLabel string `json:"roleLabel"`
-	Username      string `json:"username"`
+	// Effective roles of the user: its own role first, then the ones inherited from its groups.
+	Roles         []string `json:"roles,omitempty"`
+	Username      string   `json:"username"`
+	EmailVerified bool     `json:"email_verified"`
+	// Organization of the user, the requests made with the token are scoped to it.
+	OrganizationID string `json:"org,omitempty"`
+	// Custom attributes of the user flagged to be copied into the tokens.
+	Attributes domain.Attributes `json:"attributes,omitempty"`
 	jwt.StandardClaims
 }
 
+// Audience of the tokens that can only be used to complete a multi-factor login.
+const mfaChallengeAudience = "mfa-challenge"
+
+// Time given to complete a multi-factor login.
+const mfaChallengeDuration = 5 * time.Minute
+
+// Audience of the tokens that can only be used to change the password of their user.
+const passwordChangeAudience = "password-change"
+
+// Time given to change a password that has to be changed to log in.
+const passwordChangeDuration = 10 * time.Minute
+
 // Object used to manage token/auth operations
 type TokenManager struct {
 	JWTSecret           string
 	RefreshTokenService domain.RefreshTokenService
 	RoleRepo            domain.RoleRepository
+	AttributeRepo       domain.AttributeRepository
+	GroupRepo           domain.GroupRepository
+	UsernameHistoryRepo domain.UsernameHistoryRepository
+	UserRepo            domain.UserRepository
 }
 
 // Instantiates a new Token Manager
-func NewTokenManager(jwtSecret string, refreshTokenService domain.RefreshTokenService, rolesRepo domain.RoleRepository) TokenManager {
+func NewTokenManager(
+	jwtSecret string,
+	refreshTokenService domain.RefreshTokenService,
+	rolesRepo domain.RoleRepository,
+	attributeRepo domain.AttributeRepository,
+	groupRepo domain.GroupRepository,
+	usernameHistoryRepo domain.UsernameHistoryRepository,
+	userRepo domain.UserRepository,
+) TokenManager {
 	return TokenManager{
 		JWTSecret:           jwtSecret,
 		RefreshTokenService: refreshTokenService,
 		RoleRepo:            rolesRepo,
+		AttributeRepo:       attributeRepo,
+		GroupRepo:           groupRepo,
+		UsernameHistoryRepo: usernameHistoryRepo,
+		UserRepo:            userRepo,
 	}
 }
 
 // Deletes a refresh token.
-func (t TokenManager) DeleteRefreshToken(ctx context.Context, refreshToken string) bool {
+// Returns the user it was issued to, nil when the user can't be found.
+func (t TokenManager) DeleteRefreshToken(ctx context.Context, refreshToken string) (*domain.User, bool) {
 	uuid, err := uuid.Parse(refreshToken)
 
 	if err != nil {
-		return false
+		return nil, false
 	}
 
 	tokenToDelete, err := t.RefreshTokenService.GetTokenFromRepo(ctx, uuid)
 
 	if err != nil {
-		return false
+		return nil, false
 	}
 
-	return t.RefreshTokenService.DeleteToken(ctx, tokenToDelete) == nil
+	// The user is looked up in the organization the token was issued in.
+	ctx = domain.WithOrganization(ctx, tokenToDelete.OrganizationID)
+	user, err := t.RefreshTokenService.GetUserByToken(ctx, tokenToDelete)
+	if err != nil {
+		user = nil
+	}
+
+	return user, t.RefreshTokenService.DeleteToken(ctx, tokenToDelete) == nil
 }
 
 // Refreshes all the tokens, based on an old refresh token.
 // If old token is invalid (out of date) then it will delete it from the DB and return error.
 // If it is valid, it will generate new Access token and Refresh token to be used on next request.
+// Tokens of users that are not active, or that have to change their password, are deleted as well,
+// returning the user along with the error.
 func (t TokenManager) RefreshAllTokens(ctx context.Context, askedRefreshToken uuid.UUID) (domain.TokenResponse, error) {
 	oldRefreshToken, err := t.RefreshTokenService.GetTokenFromRepo(ctx, askedRefreshToken)
 	if err != nil {
 		return domain.TokenResponse{}, err
 	}
 
+	// The user is looked up in the organization the token was issued in.
+	ctx = domain.WithOrganization(ctx, oldRefreshToken.OrganizationID)
+
 	isValid := t.RefreshTokenService.IsTokenValid(oldRefreshToken)
 	if !isValid {
 		t.RefreshTokenService.DeleteToken(ctx, oldRefreshToken)
@@ -71,18 +120,41 @@ func (t TokenManager) RefreshAllTokens(ctx context.Context, askedRefreshToken uu
 		return domain.TokenResponse{}, domain.ErrInvalidToken
 	}
 
+	// Suspended or deactivated users lose their session for good.
+	if !user.IsActive() {
+		t.RefreshTokenService.DeleteToken(ctx, oldRefreshToken)
+		return domain.TokenResponse{User: *user}, domain.ErrUserNotActive
+	}
+
 	userRole, err := t.RoleRepo.GetByUUID(ctx, user.RoleId)
 	if err != nil {
 		return domain.TokenResponse{}, err
 	}
 	user.Role = &userRole
 
+	// Passwords expiring or reset by an administrator end the sessions on the next refresh.
+	if user.PasswordChangeRequired(time.Now()) {
+		t.RefreshTokenService.DeleteToken(ctx, oldRefreshToken)
+		return domain.TokenResponse{User: *user}, domain.ErrPasswordChangeRequired
+	}
+
 	return t.GenerateTokens(ctx, user)
 }
 
 // Gets all the tokens as token response.
+// The roles inherited from the groups are read every time, so membership changes apply on the next refresh.
 func (t TokenManager) GenerateTokens(ctx context.Context, user *domain.User) (domain.TokenResponse, error) {
-	jwtToken, err := t.GenerateJWT(user)
+	attributes, err := t.tokenAttributes(ctx, user)
+	if err != nil {
+		return domain.TokenResponse{}, err
+	}
+
+	roles, err := t.effectiveRoles(ctx, user)
+	if err != nil {
+		return domain.TokenResponse{}, err
+	}
+
+	jwtToken, err := t.GenerateJWT(user, roles, attributes)
 	if err != nil {
 		return domain.TokenResponse{}, err
 	}
@@ -108,8 +180,54 @@ func (t TokenManager) GenerateRefreshToken(ctx context.Context, user *domain.Use
 	return refreshToken.Token, nil
 }
 
-// Generates a new JWT token for a given user.
-func (t TokenManager) GenerateJWT(user *domain.User) (string, error) {
+// Gets the attributes of a user that are copied into the access tokens.
+func (t TokenManager) tokenAttributes(ctx context.Context, user *domain.User) (domain.Attributes, error) {
+	if user == nil || len(user.Attributes) == 0 {
+		return nil, nil
+	}
+
+	definitions, err := t.AttributeRepo.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := domain.Attributes{}
+	for _, definition := range definitions {
+		if value, ok := user.Attributes[definition.Name]; ok && definition.InToken {
+			result[definition.Name] = value
+		}
+	}
+	return result, nil
+}
+
+// Gets the slugs of the roles of a user: its own role, followed by the ones
+// it inherits from its groups, sorted and without repetitions.
+func (t TokenManager) effectiveRoles(ctx context.Context, user *domain.User) ([]string, error) {
+	if user == nil || user.Role == nil {
+		return nil, nil
+	}
+
+	inherited, err := t.GroupRepo.GetRolesByMember(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []string{user.Role.RoleSlug}
+	others := make([]string, 0, len(inherited))
+	seen := map[string]bool{user.Role.RoleSlug: true}
+	for _, role := range inherited {
+		if !seen[role.RoleSlug] {
+			seen[role.RoleSlug] = true
+			others = append(others, role.RoleSlug)
+		}
+	}
+	sort.Strings(others)
+	return append(result, others...), nil
+}
+
+// Generates a new JWT token for a given user, with its effective roles and the given custom attributes.
+// Only the own role of the user is in the token when no effective roles are given.
+func (t TokenManager) GenerateJWT(user *domain.User, roles []string, attributes domain.Attributes) (string, error) {
 	if user == nil ||
 		len(user.Role.RoleLabel) == 0 ||
 		len(user.Role.RoleSlug) == 0 ||
@@ -120,12 +238,26 @@ func (t TokenManager) GenerateJWT(user *domain.User) (string, error) {
 		return "", domain.ErrBadParamInput
 	}
 
+	if len(roles) == 0 {
+		roles = []string{user.Role.RoleSlug}
+	}
+
+	organizationID := user.OrganizationID
+	if organizationID == uuid.Nil {
+		organizationID = domain.DefaultOrganizationID
+	}
+
 	claims := ClaimsWithRole{
 		user.Role.RoleSlug,
 		user.Role.RoleLabel,
+		roles,
 		user.Username,
+		user.EmailVerified,
+		organizationID.String(),
+		attributes,
 		jwt.StandardClaims{
 			Issuer:    user.ID.String(),
+			IssuedAt:  time.Now().Unix(),
 			ExpiresAt: time.Now().Add(time.Minute * 15).Unix(),
 		},
 	}
@@ -141,26 +273,166 @@ func (t TokenManager) GenerateJWT(user *domain.User) (string, error) {
 
 // Gets the user ID from token
 func (t TokenManager) GetUserIDFromToken(token *jwt.Token) (uuid.UUID, error) {
-	if claims, ok := token.Claims.(*ClaimsWithRole); ok && t.IsJWTokenValid(token) {
+	if claims, ok := token.Claims.(*ClaimsWithRole); ok && t.isJWTokenSigned(token) {
 		return uuid.Parse(claims.StandardClaims.Issuer)
 	}
 	return uuid.Nil, domain.ErrInvalidToken
 }
 
-// Gets the user role from a jwt token
-func (t TokenManager) GetUserRoleFromToken(token *jwt.Token) (string, error) {
-	if claims, ok := token.Claims.(*ClaimsWithRole); ok && t.IsJWTokenValid(token) {
-		return claims.UserRoleSlug, nil
+// Gets the effective roles of the user from a jwt token.
+// Tokens issued before the roles were added only have the own role of the user.
+func (t TokenManager) GetUserRolesFromToken(token *jwt.Token) ([]string, error) {
+	if claims, ok := token.Claims.(*ClaimsWithRole); ok && t.isJWTokenSigned(token) {
+		if len(claims.Roles) == 0 {
+			return []string{claims.UserRoleSlug}, nil
+		}
+		return claims.Roles, nil
+	}
+
+	return nil, domain.ErrInvalidToken
+}
+
+// Gets the organization of the user from a jwt token.
+// Tokens issued before there were organizations belong to the default one.
+func (t TokenManager) GetOrganizationIDFromToken(token *jwt.Token) (uuid.UUID, error) {
+	if claims, ok := token.Claims.(*ClaimsWithRole); ok && t.isJWTokenSigned(token) {
+		if len(claims.OrganizationID) == 0 {
+			return domain.DefaultOrganizationID, nil
+		}
+		return uuid.Parse(claims.OrganizationID)
+	}
+
+	return uuid.Nil, domain.ErrInvalidToken
+}
+
+// Checks if a token is valid.
+// Tokens issued before their user changed from the username in them aren't valid anymore,
+// and neither are the ones of users that can't log in anymore.
+func (t TokenManager) IsJWTokenValid(ctx context.Context, token *jwt.Token) bool {
+	if !t.isJWTokenSigned(token) {
+		return false
 	}
 
-	return "", domain.ErrInvalidToken
+	claims, ok := token.Claims.(*ClaimsWithRole)
+	if !ok {
+		return false
+	}
+	userID, err := uuid.Parse(claims.StandardClaims.Issuer)
+	if err != nil {
+		return false
+	}
+
+	// Tokens issued before the issue date was added are checked against every change.
+	renamed, err := t.UsernameHistoryRepo.WasRenamedSince(ctx, userID, claims.Username, time.Unix(claims.IssuedAt, 0))
+	if err != nil || renamed {
+		return false
+	}
+
+	// Tokens stop working as soon as their user is suspended, deactivated or deleted.
+	organizationID, err := t.GetOrganizationIDFromToken(token)
+	if err != nil {
+		return false
+	}
+	user, err := t.UserRepo.GetByUUID(domain.WithOrganization(ctx, organizationID), userID)
+	return err == nil && user.IsActive()
 }
 
-// Checks if a token is valid
-func (t TokenManager) IsJWTokenValid(token *jwt.Token) bool {
+// Checks the signature and expiration of a token.
+// MFA challenge and password change tokens are never valid access tokens.
+func (t TokenManager) isJWTokenSigned(token *jwt.Token) bool {
+	if claims, ok := token.Claims.(*ClaimsWithRole); ok &&
+		(claims.Audience == mfaChallengeAudience || claims.Audience == passwordChangeAudience) {
+		return false
+	}
 	return token.Valid
 }
 
+// Generates a short-lived token proving the password of a user was checked,
+// to be exchanged for the real tokens once the second factor is verified.
+func (t TokenManager) GenerateMFAChallenge(user *domain.User) (string, error) {
+	return t.generateRestrictedToken(user, mfaChallengeAudience, mfaChallengeDuration)
+}
+
+// Gets the user ID and its organization from a MFA challenge token.
+func (t TokenManager) GetUserIDFromMFAChallenge(tokenString string) (uuid.UUID, uuid.UUID, error) {
+	return t.getUserIDFromRestrictedToken(tokenString, mfaChallengeAudience)
+}
+
+// Generates a short-lived token proving the password of a user was checked,
+// which only allows the user to change its password.
+func (t TokenManager) GeneratePasswordChangeToken(user *domain.User) (string, error) {
+	return t.generateRestrictedToken(user, passwordChangeAudience, passwordChangeDuration)
+}
+
+// Gets the user ID and its organization from a password change token.
+func (t TokenManager) GetUserIDFromPasswordChangeToken(tokenString string) (uuid.UUID, uuid.UUID, error) {
+	return t.getUserIDFromRestrictedToken(tokenString, passwordChangeAudience)
+}
+
+// Claims of the tokens only accepted for an audience.
+type restrictedClaims struct {
+	// Organization of the user, where it is looked up when the token is used.
+	OrganizationID string `json:"org,omitempty"`
+	jwt.StandardClaims
+}
+
+// Generates a token for a user that is only accepted for the given audience.
+func (t TokenManager) generateRestrictedToken(user *domain.User, audience string, duration time.Duration) (string, error) {
+	if user == nil || user.ID == uuid.Nil {
+		return "", domain.ErrBadParamInput
+	}
+
+	organizationID := user.OrganizationID
+	if organizationID == uuid.Nil {
+		organizationID = domain.DefaultOrganizationID
+	}
+
+	claims := restrictedClaims{
+		organizationID.String(),
+		jwt.StandardClaims{
+			Subject:   user.ID.String(),
+			Audience:  audience,
+			ExpiresAt: time.Now().Add(duration).Unix(),
+		},
+	}
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return jwtToken.SignedString([]byte(t.JWTSecret))
+}
+
+// Gets the user ID and its organization from a token generated for the given audience.
+// Tokens issued before they had the organization belong to the default one.
+func (t TokenManager) getUserIDFromRestrictedToken(tokenString string, audience string) (uuid.UUID, uuid.UUID, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &restrictedClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, domain.ErrInvalidToken
+		}
+		return []byte(t.JWTSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return uuid.Nil, uuid.Nil, domain.ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*restrictedClaims)
+	if !ok || !claims.VerifyAudience(audience, true) {
+		return uuid.Nil, uuid.Nil, domain.ErrInvalidToken
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, domain.ErrInvalidToken
+	}
+
+	if len(claims.OrganizationID) == 0 {
+		return userID, domain.DefaultOrganizationID, nil
+	}
+	organizationID, err := uuid.Parse(claims.OrganizationID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, domain.ErrInvalidToken
+	}
+	return userID, organizationID, nil
+}
+
 // Parses a JWT Token string to an object.
 func (t TokenManager) ParseJWT(tokenString string) (*jwt.Token, error) {
 	key := []byte(t.JWTSecret)
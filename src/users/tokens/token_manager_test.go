@@ -2,10 +2,14 @@ package tokens
 
 import (
 	"context"
+	"database/sql"
+	"encoding/base64"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
 	"github.com/plagioriginal/user-microservice/domain"
 	"github.com/plagioriginal/user-microservice/domain/mocks"
@@ -19,6 +23,10 @@ type TokenManagerTestSuite struct {
 	jwtSecret           string
 	refreshTokenService *mocks.RefreshTokenService
 	roleRepo            *mocks.RoleRepository
+	attributeRepo       *mocks.AttributeRepository
+	groupRepo           *mocks.GroupRepository
+	usernameHistoryRepo *mocks.UsernameHistoryRepository
+	userRepo            *mocks.UserRepository
 	validMockUser       *domain.User
 	invalidMockUsers    []*domain.User
 }
@@ -32,6 +40,7 @@ func (ts *TokenManagerTestSuite) SetupSuite() {
 		ID:       userId,
 		Username: "testuser",
 		RoleId:   roleId,
+		Status:   domain.UserStatusActive,
 		Role: &domain.Role{
 			ID:        roleId,
 			RoleSlug:  domain.DEFAULT_ROLE_ADMIN.RoleSlug,
@@ -97,6 +106,10 @@ func (ts *TokenManagerTestSuite) SetupSuite() {
 func (ts *TokenManagerTestSuite) SetupTest() {
 	ts.refreshTokenService = new(mocks.RefreshTokenService)
 	ts.roleRepo = new(mocks.RoleRepository)
+	ts.attributeRepo = new(mocks.AttributeRepository)
+	ts.groupRepo = new(mocks.GroupRepository)
+	ts.usernameHistoryRepo = new(mocks.UsernameHistoryRepository)
+	ts.userRepo = new(mocks.UserRepository)
 }
 
 // Tests the refresh all tokens function.
@@ -109,7 +122,7 @@ func (ts *TokenManagerTestSuite) TestRefreshAllTokens() {
 			Return(domain.RefreshToken{}, errors.New("unexpected")).
 			Once()
 
-		tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo)
+		tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
 
 		tokens, err := tm.RefreshAllTokens(context.TODO(), oldRefreshToken)
 
@@ -140,7 +153,7 @@ func (ts *TokenManagerTestSuite) TestRefreshAllTokens() {
 			Return(nil).
 			Once()
 
-		tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo)
+		tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
 
 		tokens, err := tm.RefreshAllTokens(context.TODO(), oldRefreshToken)
 
@@ -176,7 +189,7 @@ func (ts *TokenManagerTestSuite) TestRefreshAllTokens() {
 			Return(nil).
 			Once()
 
-		tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo)
+		tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
 
 		tokens, err := tm.RefreshAllTokens(context.TODO(), oldRefreshToken)
 
@@ -185,6 +198,43 @@ func (ts *TokenManagerTestSuite) TestRefreshAllTokens() {
 		ts.refreshTokenService.AssertExpectations(ts.T())
 	})
 
+	ts.Run("user of the refresh token is suspended", func() {
+		suspendedUser := &domain.User{ID: uuid.New(), Status: domain.UserStatusSuspended}
+		oldDomainToken := domain.RefreshToken{
+			Id:         uuid.New(),
+			Token:      oldRefreshToken,
+			ValidUntil: time.Now().Add(time.Hour * 2),
+		}
+
+		ts.refreshTokenService.
+			On("GetTokenFromRepo", mock.Anything, oldRefreshToken).
+			Return(oldDomainToken, nil).
+			Once()
+
+		ts.refreshTokenService.
+			On("IsTokenValid", oldDomainToken).
+			Return(true).
+			Once()
+
+		ts.refreshTokenService.
+			On("GetUserByToken", mock.Anything, oldDomainToken).
+			Return(suspendedUser, nil).
+			Once()
+
+		ts.refreshTokenService.
+			On("DeleteToken", mock.Anything, oldDomainToken).
+			Return(nil).
+			Once()
+
+		tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
+
+		tokens, err := tm.RefreshAllTokens(context.TODO(), oldRefreshToken)
+
+		ts.Equal(tokens, domain.TokenResponse{User: *suspendedUser})
+		ts.ErrorIs(err, domain.ErrUserNotActive)
+		ts.refreshTokenService.AssertExpectations(ts.T())
+	})
+
 	ts.Run("unexpected error fetching the user role", func() {
 		oldDomainToken := domain.RefreshToken{
 			Id:         uuid.New(),
@@ -212,7 +262,7 @@ func (ts *TokenManagerTestSuite) TestRefreshAllTokens() {
 			Return(domain.Role{}, errors.New("unexpexted")).
 			Once()
 
-		tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo)
+		tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
 
 		tokens, err := tm.RefreshAllTokens(context.TODO(), oldRefreshToken)
 
@@ -261,7 +311,13 @@ func (ts *TokenManagerTestSuite) TestRefreshAllTokens() {
 			}, nil).
 			Once()
 
-		tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo)
+		// Memberships are read again, so the ones changed since the login apply.
+		ts.groupRepo.
+			On("GetRolesByMember", mock.Anything, ts.validMockUser.ID).
+			Return([]domain.Role{{ID: uuid.New(), RoleSlug: "editor", RoleLabel: "Editor"}}, nil).
+			Once()
+
+		tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
 
 		tokens, err := tm.RefreshAllTokens(context.TODO(), oldRefreshToken)
 
@@ -269,10 +325,153 @@ func (ts *TokenManagerTestSuite) TestRefreshAllTokens() {
 		ts.NoError(err)
 		ts.refreshTokenService.AssertExpectations(ts.T())
 		ts.roleRepo.AssertExpectations(ts.T())
+		ts.groupRepo.AssertExpectations(ts.T())
+
+		token, err := tm.ParseJWT(tokens.AccessToken)
+		ts.NoError(err)
+		ts.Equal([]string{domain.DEFAULT_ROLE_ADMIN.RoleSlug, "editor"}, token.Claims.(*ClaimsWithRole).Roles)
+	})
+
+	ts.Run("users that have to change their password lose the session", func() {
+		oldDomainToken := domain.RefreshToken{
+			Id:         uuid.New(),
+			Token:      oldRefreshToken,
+			ValidUntil: time.Now().Add(time.Hour * 2),
+		}
+		user := &domain.User{
+			ID:                uuid.New(),
+			Username:          "testuser",
+			RoleId:            ts.validMockUser.RoleId,
+			Password:          "hash",
+			Status:            domain.UserStatusActive,
+			PasswordChangedAt: time.Now().AddDate(0, 0, -31),
+		}
+
+		ts.refreshTokenService.
+			On("GetTokenFromRepo", mock.Anything, oldRefreshToken).
+			Return(oldDomainToken, nil).
+			Once()
+
+		ts.refreshTokenService.
+			On("IsTokenValid", oldDomainToken).
+			Return(true).
+			Once()
+
+		ts.refreshTokenService.
+			On("GetUserByToken", mock.Anything, oldDomainToken).
+			Return(user, nil).
+			Once()
+
+		ts.roleRepo.
+			On("GetByUUID", mock.Anything, user.RoleId).
+			Return(domain.Role{ID: user.RoleId, RoleSlug: "user", PasswordMaxAgeDays: 30}, nil).
+			Once()
+
+		ts.refreshTokenService.
+			On("DeleteToken", mock.Anything, oldDomainToken).
+			Return(nil).
+			Once()
+
+		tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
+
+		tokens, err := tm.RefreshAllTokens(context.TODO(), oldRefreshToken)
+
+		ts.Equal(domain.ErrPasswordChangeRequired, err)
+		ts.Equal(user.ID, tokens.User.ID)
+		ts.Empty(tokens.AccessToken)
+		ts.refreshTokenService.AssertExpectations(ts.T())
+		ts.refreshTokenService.AssertNotCalled(ts.T(), "GenerateRefreshToken", mock.Anything, user)
+	})
+
+	ts.Run("the user is looked up in the organization of the refresh token", func() {
+		organizationID := uuid.New()
+		oldDomainToken := domain.RefreshToken{
+			Id:             uuid.New(),
+			Token:          oldRefreshToken,
+			ValidUntil:     time.Now().Add(time.Hour * 2),
+			OrganizationID: organizationID,
+		}
+		inOrganization := mock.MatchedBy(func(ctx context.Context) bool {
+			return domain.OrganizationFromContext(ctx) == organizationID
+		})
+
+		ts.refreshTokenService.
+			On("GetTokenFromRepo", mock.Anything, oldRefreshToken).
+			Return(oldDomainToken, nil).
+			Once()
+
+		ts.refreshTokenService.
+			On("IsTokenValid", oldDomainToken).
+			Return(true).
+			Once()
+
+		ts.refreshTokenService.
+			On("GetUserByToken", inOrganization, oldDomainToken).
+			Return(nil, errors.New("not found")).
+			Once()
+
+		ts.refreshTokenService.
+			On("DeleteToken", inOrganization, oldDomainToken).
+			Return(nil).
+			Once()
+
+		tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
+
+		_, err := tm.RefreshAllTokens(context.TODO(), oldRefreshToken)
+
+		ts.Equal(domain.ErrInvalidToken, err)
+		ts.refreshTokenService.AssertExpectations(ts.T())
 	})
 }
 
 // Tests the generate refresh token function.
+func (ts *TokenManagerTestSuite) TestDeleteRefreshToken() {
+	tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
+
+	ts.Run("invalid refresh token", func() {
+		user, isDeleted := tm.DeleteRefreshToken(context.TODO(), "cenas")
+		ts.Nil(user)
+		ts.False(isDeleted)
+	})
+
+	ts.Run("unknown refresh token", func() {
+		refreshToken := uuid.New()
+		ts.refreshTokenService.
+			On("GetTokenFromRepo", mock.Anything, refreshToken).
+			Return(domain.RefreshToken{}, errors.New("not found")).
+			Once()
+
+		user, isDeleted := tm.DeleteRefreshToken(context.TODO(), refreshToken.String())
+		ts.Nil(user)
+		ts.False(isDeleted)
+		ts.refreshTokenService.AssertExpectations(ts.T())
+	})
+
+	ts.Run("deletes the token of a user", func() {
+		organizationID := uuid.New()
+		refreshToken := domain.RefreshToken{Id: uuid.New(), Token: uuid.New(), OrganizationID: organizationID}
+		ts.refreshTokenService.
+			On("GetTokenFromRepo", mock.Anything, refreshToken.Token).
+			Return(refreshToken, nil).
+			Once()
+		ts.refreshTokenService.
+			On("GetUserByToken", mock.MatchedBy(func(ctx context.Context) bool {
+				return domain.OrganizationFromContext(ctx) == organizationID
+			}), refreshToken).
+			Return(ts.validMockUser, nil).
+			Once()
+		ts.refreshTokenService.
+			On("DeleteToken", mock.Anything, refreshToken).
+			Return(nil).
+			Once()
+
+		user, isDeleted := tm.DeleteRefreshToken(context.TODO(), refreshToken.Token.String())
+		ts.Equal(ts.validMockUser, user)
+		ts.True(isDeleted)
+		ts.refreshTokenService.AssertExpectations(ts.T())
+	})
+}
+
 func (ts *TokenManagerTestSuite) TestGenerateRefreshToken() {
 	ts.Run("invalid user to generate token", func() {
 		ts.refreshTokenService.
@@ -280,7 +479,7 @@ func (ts *TokenManagerTestSuite) TestGenerateRefreshToken() {
 			Return(domain.RefreshToken{}, errors.New("any error")).
 			Once()
 
-		tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo)
+		tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
 
 		token, err := tm.GenerateRefreshToken(context.TODO(), ts.validMockUser)
 
@@ -299,7 +498,7 @@ func (ts *TokenManagerTestSuite) TestGenerateRefreshToken() {
 			}, nil).
 			Once()
 
-		tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo)
+		tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
 
 		token, err := tm.GenerateRefreshToken(context.TODO(), ts.validMockUser)
 
@@ -311,8 +510,8 @@ func (ts *TokenManagerTestSuite) TestGenerateRefreshToken() {
 
 // Tests the ID and role getters for a JWT.
 func (ts *TokenManagerTestSuite) TestGettersFromJWT() {
-	tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo)
-	tokenString, _ := tm.GenerateJWT(ts.validMockUser)
+	tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
+	tokenString, _ := tm.GenerateJWT(ts.validMockUser, nil, nil)
 	token, _ := tm.ParseJWT(tokenString)
 
 	ts.Run("User ID getter works", func() {
@@ -321,10 +520,10 @@ func (ts *TokenManagerTestSuite) TestGettersFromJWT() {
 		ts.Equal(id, ts.validMockUser.ID)
 	})
 
-	ts.Run("user role getter works", func() {
-		role, err := tm.GetUserRoleFromToken(token)
+	ts.Run("user roles getter works", func() {
+		roles, err := tm.GetUserRolesFromToken(token)
 		ts.NoError(err)
-		ts.Equal(role, ts.validMockUser.Role.RoleSlug)
+		ts.Equal([]string{ts.validMockUser.Role.RoleSlug}, roles)
 	})
 
 	token.Valid = false
@@ -335,19 +534,337 @@ func (ts *TokenManagerTestSuite) TestGettersFromJWT() {
 		ts.NotEqual(id, ts.validMockUser.ID)
 	})
 
-	ts.Run("user role getter works", func() {
-		role, err := tm.GetUserRoleFromToken(token)
+	ts.Run("user roles getter works but token is invalid", func() {
+		roles, err := tm.GetUserRolesFromToken(token)
+		ts.Error(err)
+		ts.Nil(roles)
+	})
+}
+
+// Tests the email verification claim of the JWT.
+func (ts *TokenManagerTestSuite) TestEmailVerifiedClaim() {
+	tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
+
+	ts.Run("unverified user has the claim set to false", func() {
+		tokenString, err := tm.GenerateJWT(ts.validMockUser, nil, nil)
+		ts.NoError(err)
+
+		token, err := tm.ParseJWT(tokenString)
+		ts.NoError(err)
+		ts.False(token.Claims.(*ClaimsWithRole).EmailVerified)
+
+		// The claim is always present, so clients can tell it apart from old tokens.
+		parts := strings.Split(tokenString, ".")
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		ts.NoError(err)
+		ts.Contains(string(payload), `"email_verified":false`)
+	})
+
+	ts.Run("verified user has the claim set to true", func() {
+		verifiedUser := *ts.validMockUser
+		verifiedUser.Email = "testuser@example.com"
+		verifiedUser.EmailVerified = true
+
+		tokenString, err := tm.GenerateJWT(&verifiedUser, nil, nil)
+		ts.NoError(err)
+
+		token, err := tm.ParseJWT(tokenString)
+		ts.NoError(err)
+		ts.True(token.Claims.(*ClaimsWithRole).EmailVerified)
+	})
+}
+
+// Tests the custom attributes copied into the JWT.
+func (ts *TokenManagerTestSuite) TestAttributeClaims() {
+	tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
+
+	ts.Run("users without attributes don't need the definitions", func() {
+		attributes, err := tm.tokenAttributes(context.TODO(), ts.validMockUser)
+		ts.NoError(err)
+		ts.Nil(attributes)
+		ts.attributeRepo.AssertNotCalled(ts.T(), "Fetch", mock.Anything)
+	})
+
+	ts.Run("error fetching the definitions", func() {
+		user := *ts.validMockUser
+		user.Attributes = domain.Attributes{"department": "sales"}
+		ts.attributeRepo.On("Fetch", mock.Anything).Once().Return(nil, errors.New("boom"))
+
+		tokens, err := tm.GenerateTokens(context.TODO(), &user)
+		ts.Equal(domain.TokenResponse{}, tokens)
+		ts.Error(err)
+		ts.attributeRepo.AssertExpectations(ts.T())
+	})
+
+	ts.Run("only the attributes flagged for the tokens are copied", func() {
+		user := *ts.validMockUser
+		user.Attributes = domain.Attributes{"department": "sales", "timezone": "UTC"}
+		ts.attributeRepo.On("Fetch", mock.Anything).Once().Return([]domain.AttributeDefinition{
+			{Name: "department", Type: domain.AttributeTypeString, InToken: true},
+			{Name: "timezone", Type: domain.AttributeTypeString},
+			{Name: "seniority", Type: domain.AttributeTypeNumber, InToken: true},
+		}, nil)
+		ts.groupRepo.On("GetRolesByMember", mock.Anything, user.ID).Once().Return([]domain.Role{}, nil)
+		ts.refreshTokenService.
+			On("GenerateRefreshToken", mock.Anything, &user).
+			Return(domain.RefreshToken{Token: uuid.New()}, nil).
+			Once()
+
+		tokens, err := tm.GenerateTokens(context.TODO(), &user)
+		ts.NoError(err)
+
+		token, err := tm.ParseJWT(tokens.AccessToken)
+		ts.NoError(err)
+		ts.Equal(domain.Attributes{"department": "sales"}, token.Claims.(*ClaimsWithRole).Attributes)
+		ts.attributeRepo.AssertExpectations(ts.T())
+	})
+}
+
+// Tests the effective roles of the user in the JWT.
+func (ts *TokenManagerTestSuite) TestRoleClaims() {
+	tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
+
+	ts.Run("error fetching the roles of the groups", func() {
+		ts.groupRepo.On("GetRolesByMember", mock.Anything, ts.validMockUser.ID).Once().Return(nil, errors.New("boom"))
+
+		tokens, err := tm.GenerateTokens(context.TODO(), ts.validMockUser)
+		ts.Equal(domain.TokenResponse{}, tokens)
 		ts.Error(err)
-		ts.NotEqual(role, ts.validMockUser.Role.RoleSlug)
+		ts.groupRepo.AssertExpectations(ts.T())
+	})
+
+	ts.Run("own role first, then the inherited ones without repetitions", func() {
+		ts.groupRepo.On("GetRolesByMember", mock.Anything, ts.validMockUser.ID).Once().Return([]domain.Role{
+			{ID: uuid.New(), RoleSlug: "support"},
+			{ID: uuid.New(), RoleSlug: domain.DEFAULT_ROLE_ADMIN.RoleSlug},
+			{ID: uuid.New(), RoleSlug: "editor"},
+		}, nil)
+		ts.refreshTokenService.
+			On("GenerateRefreshToken", mock.Anything, ts.validMockUser).
+			Return(domain.RefreshToken{Token: uuid.New()}, nil).
+			Once()
+
+		tokens, err := tm.GenerateTokens(context.TODO(), ts.validMockUser)
+		ts.NoError(err)
+
+		token, err := tm.ParseJWT(tokens.AccessToken)
+		ts.NoError(err)
+		claims := token.Claims.(*ClaimsWithRole)
+		ts.Equal(domain.DEFAULT_ROLE_ADMIN.RoleSlug, claims.UserRoleSlug)
+		ts.Equal([]string{domain.DEFAULT_ROLE_ADMIN.RoleSlug, "editor", "support"}, claims.Roles)
+
+		roles, err := tm.GetUserRolesFromToken(token)
+		ts.NoError(err)
+		ts.Equal(claims.Roles, roles)
+		ts.groupRepo.AssertExpectations(ts.T())
+	})
+
+	ts.Run("tokens without the roles claim have the own role", func() {
+		claims := ClaimsWithRole{UserRoleSlug: "user", Username: "testuser"}
+		claims.ExpiresAt = time.Now().Add(time.Minute).Unix()
+		tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(ts.jwtSecret))
+		ts.NoError(err)
+
+		token, err := tm.ParseJWT(tokenString)
+		ts.NoError(err)
+		roles, err := tm.GetUserRolesFromToken(token)
+		ts.NoError(err)
+		ts.Equal([]string{"user"}, roles)
+	})
+}
+
+// Tests the organization claim of the JWT.
+func (ts *TokenManagerTestSuite) TestOrganizationClaim() {
+	tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
+
+	ts.Run("tokens carry the organization of the user", func() {
+		user := *ts.validMockUser
+		user.OrganizationID = uuid.New()
+
+		tokenString, err := tm.GenerateJWT(&user, nil, nil)
+		ts.NoError(err)
+		token, err := tm.ParseJWT(tokenString)
+		ts.NoError(err)
+
+		organizationID, err := tm.GetOrganizationIDFromToken(token)
+		ts.NoError(err)
+		ts.Equal(user.OrganizationID, organizationID)
+	})
+
+	ts.Run("tokens without the organization claim belong to the default one", func() {
+		claims := ClaimsWithRole{UserRoleSlug: "user", Username: "testuser"}
+		claims.ExpiresAt = time.Now().Add(time.Minute).Unix()
+		tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(ts.jwtSecret))
+		ts.NoError(err)
+
+		token, err := tm.ParseJWT(tokenString)
+		ts.NoError(err)
+		organizationID, err := tm.GetOrganizationIDFromToken(token)
+		ts.NoError(err)
+		ts.Equal(domain.DefaultOrganizationID, organizationID)
+	})
+
+	ts.Run("invalid tokens have no organization", func() {
+		tokenString, _ := tm.GenerateJWT(ts.validMockUser, nil, nil)
+		token, _ := tm.ParseJWT(tokenString)
+		token.Valid = false
+
+		organizationID, err := tm.GetOrganizationIDFromToken(token)
+		ts.Equal(domain.ErrInvalidToken, err)
+		ts.Equal(uuid.Nil, organizationID)
+	})
+}
+
+// Tests the tokens of the multi-factor logins
+func (ts *TokenManagerTestSuite) TestMFAChallenge() {
+	tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
+
+	ts.Run("invalid user", func() {
+		token, err := tm.GenerateMFAChallenge(nil)
+		ts.Equal(domain.ErrBadParamInput, err)
+		ts.Empty(token)
+
+		token, err = tm.GenerateMFAChallenge(&domain.User{})
+		ts.Equal(domain.ErrBadParamInput, err)
+		ts.Empty(token)
+	})
+
+	ts.Run("gets the user back from the challenge", func() {
+		challenge, err := tm.GenerateMFAChallenge(ts.validMockUser)
+		ts.NoError(err)
+
+		userID, organizationID, err := tm.GetUserIDFromMFAChallenge(challenge)
+		ts.NoError(err)
+		ts.Equal(ts.validMockUser.ID, userID)
+		ts.Equal(domain.DefaultOrganizationID, organizationID)
+	})
+
+	ts.Run("keeps the organization of the user", func() {
+		user := *ts.validMockUser
+		user.OrganizationID = uuid.New()
+		challenge, err := tm.GenerateMFAChallenge(&user)
+		ts.NoError(err)
+
+		userID, organizationID, err := tm.GetUserIDFromMFAChallenge(challenge)
+		ts.NoError(err)
+		ts.Equal(user.ID, userID)
+		ts.Equal(user.OrganizationID, organizationID)
+	})
+
+	ts.Run("challenge is not an access token", func() {
+		challenge, err := tm.GenerateMFAChallenge(ts.validMockUser)
+		ts.NoError(err)
+
+		token, err := tm.ParseJWT(challenge)
+		ts.NoError(err)
+		ts.False(tm.IsJWTokenValid(context.TODO(), token))
+
+		_, err = tm.GetUserIDFromToken(token)
+		ts.Equal(domain.ErrInvalidToken, err)
+	})
+
+	ts.Run("access token is not a challenge", func() {
+		accessToken, err := tm.GenerateJWT(ts.validMockUser, nil, nil)
+		ts.NoError(err)
+
+		_, _, err = tm.GetUserIDFromMFAChallenge(accessToken)
+		ts.Equal(domain.ErrInvalidToken, err)
+	})
+
+	ts.Run("challenge signed with another secret", func() {
+		other := NewTokenManager("another-secret", ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
+		challenge, err := other.GenerateMFAChallenge(ts.validMockUser)
+		ts.NoError(err)
+
+		_, _, err = tm.GetUserIDFromMFAChallenge(challenge)
+		ts.Equal(domain.ErrInvalidToken, err)
+	})
+}
+
+func (ts *TokenManagerTestSuite) TestPasswordChangeToken() {
+	tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
+
+	ts.Run("invalid user", func() {
+		token, err := tm.GeneratePasswordChangeToken(nil)
+		ts.Equal(domain.ErrBadParamInput, err)
+		ts.Empty(token)
+	})
+
+	ts.Run("gets the user back from the token", func() {
+		token, err := tm.GeneratePasswordChangeToken(ts.validMockUser)
+		ts.NoError(err)
+
+		userID, organizationID, err := tm.GetUserIDFromPasswordChangeToken(token)
+		ts.NoError(err)
+		ts.Equal(ts.validMockUser.ID, userID)
+		ts.Equal(domain.DefaultOrganizationID, organizationID)
+	})
+
+	ts.Run("keeps the organization of the user", func() {
+		user := *ts.validMockUser
+		user.OrganizationID = uuid.New()
+		token, err := tm.GeneratePasswordChangeToken(&user)
+		ts.NoError(err)
+
+		_, organizationID, err := tm.GetUserIDFromPasswordChangeToken(token)
+		ts.NoError(err)
+		ts.Equal(user.OrganizationID, organizationID)
+	})
+
+	ts.Run("tokens issued without the organization belong to the default one", func() {
+		claims := jwt.StandardClaims{
+			Subject:   ts.validMockUser.ID.String(),
+			Audience:  passwordChangeAudience,
+			ExpiresAt: time.Now().Add(time.Minute).Unix(),
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(ts.jwtSecret))
+		ts.NoError(err)
+
+		userID, organizationID, err := tm.GetUserIDFromPasswordChangeToken(token)
+		ts.NoError(err)
+		ts.Equal(ts.validMockUser.ID, userID)
+		ts.Equal(domain.DefaultOrganizationID, organizationID)
+	})
+
+	ts.Run("token is not an access token", func() {
+		tokenString, err := tm.GeneratePasswordChangeToken(ts.validMockUser)
+		ts.NoError(err)
+
+		token, err := tm.ParseJWT(tokenString)
+		ts.NoError(err)
+		ts.False(tm.IsJWTokenValid(context.TODO(), token))
+
+		_, err = tm.GetUserIDFromToken(token)
+		ts.Equal(domain.ErrInvalidToken, err)
+	})
+
+	ts.Run("token is not a mfa challenge", func() {
+		token, err := tm.GeneratePasswordChangeToken(ts.validMockUser)
+		ts.NoError(err)
+
+		_, _, err = tm.GetUserIDFromMFAChallenge(token)
+		ts.Equal(domain.ErrInvalidToken, err)
+	})
+
+	ts.Run("mfa challenge and access token are not password change tokens", func() {
+		challenge, err := tm.GenerateMFAChallenge(ts.validMockUser)
+		ts.NoError(err)
+		_, _, err = tm.GetUserIDFromPasswordChangeToken(challenge)
+		ts.Equal(domain.ErrInvalidToken, err)
+
+		accessToken, err := tm.GenerateJWT(ts.validMockUser, nil, nil)
+		ts.NoError(err)
+		_, _, err = tm.GetUserIDFromPasswordChangeToken(accessToken)
+		ts.Equal(domain.ErrInvalidToken, err)
 	})
 }
 
 // Tests the parsing of the JWT tokens
 func (ts *TokenManagerTestSuite) TestParseJWT() {
-	tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo)
+	tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
 
 	ts.Run("valid structure jwt", func() {
-		tokenString, _ := tm.GenerateJWT(ts.validMockUser)
+		tokenString, _ := tm.GenerateJWT(ts.validMockUser, nil, nil)
 		token, err := tm.ParseJWT(tokenString)
 
 		ts.NoError(err)
@@ -366,12 +883,125 @@ func (ts *TokenManagerTestSuite) TestParseJWT() {
 	})
 }
 
+// Tests the validation of the access tokens
+func (ts *TokenManagerTestSuite) TestIsJWTokenValid() {
+	tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
+
+	ts.Run("token with the current username", func() {
+		tokenString, _ := tm.GenerateJWT(ts.validMockUser, nil, nil)
+		token, _ := tm.ParseJWT(tokenString)
+		claims := token.Claims.(*ClaimsWithRole)
+		ts.NotZero(claims.IssuedAt)
+
+		ts.usernameHistoryRepo.
+			On("WasRenamedSince", mock.Anything, ts.validMockUser.ID, "testuser", time.Unix(claims.IssuedAt, 0)).
+			Return(false, nil).
+			Once()
+		ts.userRepo.
+			On("GetByUUID", mock.Anything, ts.validMockUser.ID).
+			Return(ts.validMockUser, nil).
+			Once()
+
+		ts.True(tm.IsJWTokenValid(context.TODO(), token))
+	})
+
+	ts.Run("token with a username the user changed from", func() {
+		tokenString, _ := tm.GenerateJWT(ts.validMockUser, nil, nil)
+		token, _ := tm.ParseJWT(tokenString)
+
+		ts.usernameHistoryRepo.
+			On("WasRenamedSince", mock.Anything, ts.validMockUser.ID, "testuser", mock.Anything).
+			Return(true, nil).
+			Once()
+
+		ts.False(tm.IsJWTokenValid(context.TODO(), token))
+	})
+
+	ts.Run("error checking the username changes", func() {
+		tokenString, _ := tm.GenerateJWT(ts.validMockUser, nil, nil)
+		token, _ := tm.ParseJWT(tokenString)
+
+		ts.usernameHistoryRepo.
+			On("WasRenamedSince", mock.Anything, ts.validMockUser.ID, "testuser", mock.Anything).
+			Return(false, errors.New("boom")).
+			Once()
+
+		ts.False(tm.IsJWTokenValid(context.TODO(), token))
+	})
+
+	ts.Run("token of a suspended user", func() {
+		tokenString, _ := tm.GenerateJWT(ts.validMockUser, nil, nil)
+		token, _ := tm.ParseJWT(tokenString)
+
+		suspended := *ts.validMockUser
+		suspended.Status = domain.UserStatusSuspended
+		ts.usernameHistoryRepo.
+			On("WasRenamedSince", mock.Anything, ts.validMockUser.ID, "testuser", mock.Anything).
+			Return(false, nil).
+			Once()
+		ts.userRepo.
+			On("GetByUUID", mock.Anything, ts.validMockUser.ID).
+			Return(&suspended, nil).
+			Once()
+
+		ts.False(tm.IsJWTokenValid(context.TODO(), token))
+	})
+
+	ts.Run("token of a deleted user", func() {
+		tokenString, _ := tm.GenerateJWT(ts.validMockUser, nil, nil)
+		token, _ := tm.ParseJWT(tokenString)
+
+		ts.usernameHistoryRepo.
+			On("WasRenamedSince", mock.Anything, ts.validMockUser.ID, "testuser", mock.Anything).
+			Return(false, nil).
+			Once()
+		ts.userRepo.
+			On("GetByUUID", mock.Anything, ts.validMockUser.ID).
+			Return(nil, sql.ErrNoRows).
+			Once()
+
+		ts.False(tm.IsJWTokenValid(context.TODO(), token))
+	})
+
+	ts.Run("user looked up in the organization of the token", func() {
+		organizationID := uuid.New()
+		orgUser := *ts.validMockUser
+		orgUser.OrganizationID = organizationID
+		tokenString, _ := tm.GenerateJWT(&orgUser, nil, nil)
+		token, _ := tm.ParseJWT(tokenString)
+
+		ts.usernameHistoryRepo.
+			On("WasRenamedSince", mock.Anything, ts.validMockUser.ID, "testuser", mock.Anything).
+			Return(false, nil).
+			Once()
+		ts.userRepo.
+			On("GetByUUID", mock.MatchedBy(func(ctx context.Context) bool {
+				return domain.OrganizationFromContext(ctx) == organizationID
+			}), ts.validMockUser.ID).
+			Return(ts.validMockUser, nil).
+			Once()
+
+		ts.True(tm.IsJWTokenValid(context.TODO(), token))
+	})
+
+	ts.Run("expired token", func() {
+		tokenString, _ := tm.GenerateJWT(ts.validMockUser, nil, nil)
+		token, _ := tm.ParseJWT(tokenString)
+		token.Valid = false
+
+		ts.False(tm.IsJWTokenValid(context.TODO(), token))
+	})
+
+	ts.usernameHistoryRepo.AssertExpectations(ts.T())
+	ts.userRepo.AssertExpectations(ts.T())
+}
+
 // Tests the generation of the jwts
 func (ts *TokenManagerTestSuite) TestGenerateJWT() {
-	tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo)
+	tm := NewTokenManager(ts.jwtSecret, ts.refreshTokenService, ts.roleRepo, ts.attributeRepo, ts.groupRepo, ts.usernameHistoryRepo, ts.userRepo)
 
 	ts.Run("user is valid and generates proper token", func() {
-		token, err := tm.GenerateJWT(ts.validMockUser)
+		token, err := tm.GenerateJWT(ts.validMockUser, nil, nil)
 
 		ts.NoError(err)
 		ts.NotEmpty(token)
@@ -380,7 +1010,7 @@ func (ts *TokenManagerTestSuite) TestGenerateJWT() {
 
 	ts.Run("user is invalid and doesn't generate token", func() {
 		for _, invalidUser := range ts.invalidMockUsers {
-			token, err := tm.GenerateJWT(invalidUser)
+			token, err := tm.GenerateJWT(invalidUser, nil, nil)
 
 			ts.Empty(token)
 			ts.Equal("", token)